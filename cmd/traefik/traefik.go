@@ -15,13 +15,22 @@ import (
 	"github.com/containous/traefik/v2/cmd"
 	"github.com/containous/traefik/v2/cmd/healthcheck"
 	cmdVersion "github.com/containous/traefik/v2/cmd/version"
+	"github.com/containous/traefik/v2/pkg/api"
 	"github.com/containous/traefik/v2/pkg/cli"
 	"github.com/containous/traefik/v2/pkg/collector"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/connections"
+	"github.com/containous/traefik/v2/pkg/freeze"
+	"github.com/containous/traefik/v2/pkg/generations"
+	traefikhealthcheck "github.com/containous/traefik/v2/pkg/healthcheck"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
+	"github.com/containous/traefik/v2/pkg/middlewares/overload"
+	"github.com/containous/traefik/v2/pkg/middlewares/shutdown"
+	"github.com/containous/traefik/v2/pkg/panics"
 	"github.com/containous/traefik/v2/pkg/provider/acme"
 	"github.com/containous/traefik/v2/pkg/provider/aggregator"
 	"github.com/containous/traefik/v2/pkg/provider/traefik"
@@ -189,8 +198,68 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	metricsRegistry := registerMetricClients(staticConfiguration.Metrics)
 	accessLog := setupAccessLog(staticConfiguration.AccessLog)
 	chainBuilder := middleware.NewChainBuilder(*staticConfiguration, metricsRegistry, accessLog)
-	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry)
-	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder)
+	connectionTable := connections.NewTable()
+
+	var history *generations.History
+	if staticConfiguration.API != nil && staticConfiguration.API.Generations > 0 {
+		history = generations.NewHistory(staticConfiguration.API.Generations)
+	}
+
+	var configFreeze *freeze.Freeze
+	if staticConfiguration.API != nil && staticConfiguration.API.Freeze {
+		configFreeze = freeze.New()
+	}
+
+	panicReports := panics.NewReports(panics.DefaultCapacity, metricsRegistry)
+	circuitBreakers := circuitbreaker.NewRegistry()
+
+	apiOptions := api.BuilderOptions{
+		ConnectionTable: connectionTable,
+		History:         history,
+		Freeze:          configFreeze,
+		PanicReports:    panicReports,
+		CircuitBreakers: circuitBreakers,
+	}
+
+	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, apiOptions)
+	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, server.RouterFactoryOptions{
+		ConnectionTable: connectionTable,
+		MetricsRegistry: metricsRegistry,
+		PanicReports:    panicReports,
+		CircuitBreakers: circuitBreakers,
+	})
+
+	var shutdownTimeout time.Duration
+	if staticConfiguration.Shutdown != nil {
+		shutdownTimeout = time.Duration(staticConfiguration.Shutdown.Timeout)
+	}
+	shutdownCoordinator := shutdown.NewCoordinator(shutdownTimeout)
+	chainBuilder.SetShutdownCoordinator(shutdownCoordinator)
+	managerFactory.SetShutdownCoordinator(shutdownCoordinator)
+
+	if staticConfiguration.Overload != nil {
+		overloadGuard := overload.NewGuard(staticConfiguration.Overload, metricsRegistry)
+		chainBuilder.SetOverloadGuard(overloadGuard)
+		serverEntryPointsTCP.SetOverloadGuard(overloadGuard)
+		routinesPool.GoCtx(overloadGuard.Run)
+	}
+
+	if staticConfiguration.HealthCheckWebhook != nil {
+		webhook := staticConfiguration.HealthCheckWebhook
+		notifier := traefikhealthcheck.NewWebhookNotifier(webhook.URL, webhook.Headers, time.Duration(webhook.Timeout))
+		traefikhealthcheck.SetNotifier(notifier)
+		routinesPool.GoCtx(notifier.Run)
+	}
+
+	var internalListener *server.InternalListener
+	if staticConfiguration.InternalListener != nil {
+		internalListener, err = server.NewInternalListener(*staticConfiguration, apiOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		routerFactory.SetInternalListener(internalListener)
+	}
 
 	var defaultEntryPoints []string
 	for name, cfg := range staticConfiguration.EntryPoints {
@@ -213,6 +282,13 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		time.Duration(staticConfiguration.Providers.ProvidersThrottleDuration),
 		defaultEntryPoints,
 	)
+	watcher.SetDryRunProviders(staticConfiguration.Providers.DryRunProviders())
+	if history != nil {
+		watcher.SetHistory(history)
+	}
+	if configFreeze != nil {
+		watcher.SetFreeze(configFreeze)
+	}
 
 	watcher.AddListener(func(conf dynamic.Configuration) {
 		ctx := context.Background()
@@ -255,7 +331,13 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		}
 	})
 
-	return server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, chainBuilder, accessLog), nil
+	svr := server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, chainBuilder, accessLog)
+	svr.SetShutdownCoordinator(shutdownCoordinator)
+	if internalListener != nil {
+		svr.SetInternalListener(internalListener)
+	}
+
+	return svr, nil
 }
 
 func switchRouter(routerFactory *server.RouterFactory, acmeProviders []*acme.Provider, serverEntryPointsTCP server.TCPEntryPoints, serverEntryPointsUDP server.UDPEntryPoints) func(conf dynamic.Configuration) {
@@ -8,7 +8,12 @@ import (
 
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/connections"
+	"github.com/containous/traefik/v2/pkg/freeze"
+	"github.com/containous/traefik/v2/pkg/generations"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
+	"github.com/containous/traefik/v2/pkg/panics"
 	"github.com/containous/traefik/v2/pkg/version"
 	assetfs "github.com/elazarl/go-bindata-assetfs"
 	"github.com/gorilla/mux"
@@ -53,12 +58,43 @@ type Handler struct {
 
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
+
+	// connectionTable holds the active TCP connections and UDP sessions, if they are tracked.
+	connectionTable *connections.Table
+
+	// history holds the last dynamic configuration generations, if they are kept.
+	history *generations.History
+
+	// freeze holds the freeze state of the dynamic configuration, if it can be frozen.
+	freeze *freeze.Freeze
+
+	// panicReports holds the reports of the last recovered panics.
+	panicReports *panics.Reports
+
+	// circuitBreakers holds the circuit breakers of the current configuration.
+	circuitBreakers *circuitbreaker.Registry
+}
+
+// BuilderOptions holds the states reported by the API besides the runtime configuration.
+// The states left nil are not reported.
+type BuilderOptions struct {
+	ConnectionTable *connections.Table
+	History         *generations.History
+	Freeze          *freeze.Freeze
+	PanicReports    *panics.Reports
+	CircuitBreakers *circuitbreaker.Registry
 }
 
 // NewBuilder returns a http.Handler builder based on runtime.Configuration.
-func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+func NewBuilder(staticConfig static.Configuration, opts BuilderOptions) func(*runtime.Configuration) http.Handler {
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		handler := New(staticConfig, configuration)
+		handler.connectionTable = opts.ConnectionTable
+		handler.history = opts.History
+		handler.freeze = opts.Freeze
+		handler.panicReports = opts.PanicReports
+		handler.circuitBreakers = opts.CircuitBreakers
+		return handler.createRouter()
 	}
 }
 
@@ -97,6 +133,7 @@ func (h Handler) createRouter() *mux.Router {
 
 	router.Methods(http.MethodGet).Path("/api/http/routers").HandlerFunc(h.getRouters)
 	router.Methods(http.MethodGet).Path("/api/http/routers/{routerID}").HandlerFunc(h.getRouter)
+	router.Methods(http.MethodGet).Path("/api/http/explain").HandlerFunc(h.explainRouters)
 	router.Methods(http.MethodGet).Path("/api/http/services").HandlerFunc(h.getServices)
 	router.Methods(http.MethodGet).Path("/api/http/services/{serviceID}").HandlerFunc(h.getService)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares").HandlerFunc(h.getMiddlewares)
@@ -112,6 +149,32 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/udp/services").HandlerFunc(h.getUDPServices)
 	router.Methods(http.MethodGet).Path("/api/udp/services/{serviceID}").HandlerFunc(h.getUDPService)
 
+	router.Methods(http.MethodGet).Path("/api/{protocol:http|tcp|udp}/errors").HandlerFunc(h.getConfigErrors)
+
+	if h.connectionTable != nil {
+		router.Methods(http.MethodGet).Path("/api/{protocol:tcp|udp}/connections").HandlerFunc(h.getConnections)
+		router.Methods(http.MethodDelete).Path("/api/{protocol:tcp|udp}/connections/{connectionID}").HandlerFunc(h.deleteConnection)
+	}
+
+	if h.history != nil {
+		router.Methods(http.MethodGet).Path("/api/generations").HandlerFunc(h.getGenerations)
+		router.Methods(http.MethodPost).Path("/api/generations/{generationID}/rollback").HandlerFunc(h.rollbackGeneration)
+	}
+
+	if h.freeze != nil {
+		router.Methods(http.MethodGet).Path("/api/freeze").HandlerFunc(h.getFreeze)
+		router.Methods(http.MethodPut).Path("/api/freeze").HandlerFunc(h.putFreeze)
+		router.Methods(http.MethodDelete).Path("/api/freeze").HandlerFunc(h.deleteFreeze)
+	}
+
+	if h.panicReports != nil {
+		router.Methods(http.MethodGet).Path("/api/http/panics").HandlerFunc(h.getPanics)
+	}
+
+	if h.circuitBreakers != nil {
+		router.Methods(http.MethodGet).Path("/api/http/circuitbreakers").HandlerFunc(h.getCircuitBreakers)
+	}
+
 	version.Handler{}.Append(router)
 
 	if h.dashboard {
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+func (h Handler) getCircuitBreakers(rw http.ResponseWriter, request *http.Request) {
+	results := h.circuitBreakers.States()
+
+	query := request.URL.Query()
+	service, middleware, state := query.Get("service"), query.Get("middleware"), query.Get("state")
+
+	filtered := results[:0]
+	for _, result := range results {
+		if (service == "" || result.Service == service) &&
+			(middleware == "" || result.Middleware == middleware) &&
+			(state == "" || result.State == state) {
+			filtered = append(filtered, result)
+		}
+	}
+	results = filtered
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
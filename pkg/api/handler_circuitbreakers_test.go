@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_CircuitBreakers(t *testing.T) {
+	config := dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.5"}
+
+	var breakers []*circuitbreaker.Breaker
+	for _, names := range [][2]string{{"cb@file", "foo@file"}, {"cb@file", "bar@file"}, {"other@file", "foo@file"}} {
+		breaker, err := circuitbreaker.NewBreaker(config, names[0], names[1], nil)
+		require.NoError(t, err)
+
+		breakers = append(breakers, breaker)
+	}
+
+	registry := circuitbreaker.NewRegistry()
+	registry.Set(breakers)
+
+	testCases := []struct {
+		desc             string
+		path             string
+		expectedBreakers []string
+	}{
+		{
+			desc:             "all circuit breakers",
+			path:             "/api/http/circuitbreakers",
+			expectedBreakers: []string{"cb@file/bar@file", "cb@file/foo@file", "other@file/foo@file"},
+		},
+		{
+			desc:             "circuit breakers of a service",
+			path:             "/api/http/circuitbreakers?service=foo@file",
+			expectedBreakers: []string{"cb@file/foo@file", "other@file/foo@file"},
+		},
+		{
+			desc:             "circuit breakers of a middleware",
+			path:             "/api/http/circuitbreakers?middleware=other@file",
+			expectedBreakers: []string{"other@file/foo@file"},
+		},
+		{
+			desc:             "circuit breakers in a state",
+			path:             "/api/http/circuitbreakers?state=tripped",
+			expectedBreakers: []string{},
+		},
+		{
+			desc:             "paginated circuit breakers",
+			path:             "/api/http/circuitbreakers?page=2&per_page=2",
+			expectedBreakers: []string{"other@file/foo@file"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &runtime.Configuration{})
+			handler.circuitBreakers = registry
+			server := httptest.NewServer(handler.createRouter())
+			defer server.Close()
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			var states []circuitbreaker.State
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&states))
+
+			names := make([]string, 0, len(states))
+			for _, state := range states {
+				assert.Equal(t, circuitbreaker.StateStandby, state.State)
+				names = append(names, state.Middleware+"/"+state.Service)
+			}
+
+			assert.Equal(t, test.expectedBreakers, names)
+		})
+	}
+}
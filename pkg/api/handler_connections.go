@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/containous/traefik/v2/pkg/connections"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/gorilla/mux"
+)
+
+func (h Handler) getConnections(rw http.ResponseWriter, request *http.Request) {
+	protocol := mux.Vars(request)["protocol"]
+	routerName := request.URL.Query().Get("router")
+
+	criterion := newSearchCriterion(request.URL.Query())
+
+	results := make([]connections.Connection, 0)
+	for _, conn := range h.connectionTable.List(protocol) {
+		if routerName != "" && conn.Router != routerName {
+			continue
+		}
+
+		if criterion == nil || criterion.searchIn(conn.Router, conn.Source, conn.Backend) {
+			results = append(results, conn)
+		}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) deleteConnection(rw http.ResponseWriter, request *http.Request) {
+	protocol := mux.Vars(request)["protocol"]
+	connectionID := mux.Vars(request)["connectionID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := h.connectionTable.Close(protocol, connectionID)
+	if errors.Is(err, connections.ErrNotFound) {
+		writeError(rw, fmt.Sprintf("connection not found: %s", connectionID), http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
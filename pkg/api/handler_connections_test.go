@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/connections"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConn struct {
+	stats  connections.Stats
+	closed bool
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) Stats() connections.Stats {
+	return c.stats
+}
+
+func TestHandler_Connections(t *testing.T) {
+	table := connections.NewTable()
+
+	db := &fakeConn{stats: connections.Stats{Backend: "10.0.0.2:5432", BytesIn: 42, BytesOut: 1024}}
+	table.Add("tcp", "db@file", "192.168.1.10:51234", db)
+
+	stuck := &fakeConn{stats: connections.Stats{Backend: "10.0.0.3:6379"}}
+	stuckID := table.Add("tcp", "redis@file", "192.168.1.11:40000", stuck)
+
+	dns := &fakeConn{stats: connections.Stats{Backend: "10.0.0.4:53", BytesIn: 64, BytesOut: 128}}
+	table.Add("udp", "dns@file", "192.168.1.12:53000", dns)
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &runtime.Configuration{})
+	handler.connectionTable = table
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	testCases := []struct {
+		desc            string
+		path            string
+		expectedSources []string
+	}{
+		{
+			desc:            "all TCP connections",
+			path:            "/api/tcp/connections",
+			expectedSources: []string{"192.168.1.10:51234", "192.168.1.11:40000"},
+		},
+		{
+			desc:            "TCP connections of a router",
+			path:            "/api/tcp/connections?router=redis@file",
+			expectedSources: []string{"192.168.1.11:40000"},
+		},
+		{
+			desc:            "TCP connections to a backend",
+			path:            "/api/tcp/connections?search=5432",
+			expectedSources: []string{"192.168.1.10:51234"},
+		},
+		{
+			desc:            "all UDP sessions",
+			path:            "/api/udp/connections",
+			expectedSources: []string{"192.168.1.12:53000"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			resp, err := http.Get(server.URL + test.path)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			var results []connections.Connection
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&results))
+
+			var sources []string
+			for _, result := range results {
+				sources = append(sources, result.Source)
+			}
+			assert.Equal(t, test.expectedSources, sources)
+		})
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, server.URL+"/api/tcp/connections/"+stuckID, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.True(t, stuck.closed)
+	assert.False(t, db.closed)
+
+	// The connection does not belong to the UDP sessions.
+	req, err = http.NewRequest(http.MethodDelete, server.URL+"/api/udp/connections/"+stuckID, nil)
+	require.NoError(t, err)
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/gorilla/mux"
+)
+
+// Kinds of the elements reported by the errors endpoints.
+const (
+	kindRouter     = "router"
+	kindService    = "service"
+	kindMiddleware = "middleware"
+)
+
+// kindOrder is the order in which the kinds of elements are listed, the routers being the entry point of the configuration.
+var kindOrder = map[string]int{kindRouter: 0, kindService: 1, kindMiddleware: 2}
+
+// configErrorRepresentation describes an element of the dynamic configuration rejected, or partially applied,
+// because of configuration errors.
+type configErrorRepresentation struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Kind     string `json:"kind"`
+	Status   string `json:"status"`
+	// Configuration is the configuration of the element, as delivered by the provider.
+	Configuration interface{} `json:"configuration,omitempty"`
+	Errors        []string    `json:"errors"`
+}
+
+func newConfigErrorRepresentation(kind, name, status string, conf interface{}, errs []string) configErrorRepresentation {
+	return configErrorRepresentation{
+		Name:          name,
+		Provider:      getProviderName(name),
+		Kind:          kind,
+		Status:        status,
+		Configuration: conf,
+		Errors:        errs,
+	}
+}
+
+func (h Handler) getConfigErrors(rw http.ResponseWriter, request *http.Request) {
+	var results []configErrorRepresentation
+
+	switch mux.Vars(request)["protocol"] {
+	case "http":
+		results = h.getHTTPConfigErrors()
+	case "tcp":
+		results = h.getTCPConfigErrors()
+	case "udp":
+		results = h.getUDPConfigErrors()
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Kind != results[j].Kind {
+			return kindOrder[results[i].Kind] < kindOrder[results[j].Kind]
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getHTTPConfigErrors() []configErrorRepresentation {
+	results := make([]configErrorRepresentation, 0)
+
+	for name, rt := range h.runtimeConfiguration.Routers {
+		if len(rt.Err) > 0 {
+			results = append(results, newConfigErrorRepresentation(kindRouter, name, rt.Status, rt.Router, rt.Err))
+		}
+	}
+
+	for name, si := range h.runtimeConfiguration.Services {
+		if len(si.Err) > 0 {
+			results = append(results, newConfigErrorRepresentation(kindService, name, si.Status, si.Service, si.Err))
+		}
+	}
+
+	for name, mi := range h.runtimeConfiguration.Middlewares {
+		if len(mi.Err) > 0 {
+			results = append(results, newConfigErrorRepresentation(kindMiddleware, name, mi.Status, mi.Middleware, mi.Err))
+		}
+	}
+
+	return results
+}
+
+func (h Handler) getTCPConfigErrors() []configErrorRepresentation {
+	results := make([]configErrorRepresentation, 0)
+
+	for name, rt := range h.runtimeConfiguration.TCPRouters {
+		if len(rt.Err) > 0 {
+			results = append(results, newConfigErrorRepresentation(kindRouter, name, rt.Status, rt.TCPRouter, rt.Err))
+		}
+	}
+
+	for name, si := range h.runtimeConfiguration.TCPServices {
+		if len(si.Err) > 0 {
+			results = append(results, newConfigErrorRepresentation(kindService, name, si.Status, si.TCPService, si.Err))
+		}
+	}
+
+	return results
+}
+
+func (h Handler) getUDPConfigErrors() []configErrorRepresentation {
+	results := make([]configErrorRepresentation, 0)
+
+	for name, rt := range h.runtimeConfiguration.UDPRouters {
+		if len(rt.Err) > 0 {
+			results = append(results, newConfigErrorRepresentation(kindRouter, name, rt.Status, rt.UDPRouter, rt.Err))
+		}
+	}
+
+	for name, si := range h.runtimeConfiguration.UDPServices {
+		if len(si.Err) > 0 {
+			results = append(results, newConfigErrorRepresentation(kindService, name, si.Status, si.UDPService, si.Err))
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,226 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ConfigErrors(t *testing.T) {
+	type expected struct {
+		statusCode int
+		nextPage   string
+		jsonFile   string
+	}
+
+	testCases := []struct {
+		desc     string
+		path     string
+		conf     runtime.Configuration
+		expected expected
+	}{
+		{
+			desc: "no error",
+			path: "/api/http/errors",
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"bar@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@myprovider",
+							Rule:        "Host(`foo.bar`)",
+						},
+						Status: runtime.StatusEnabled,
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/errors-empty.json",
+			},
+		},
+		{
+			desc: "http errors",
+			path: "/api/http/errors",
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"bar@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@myprovider",
+							Rule:        "Host(`foo.bar`",
+						},
+						Err:    []string{"error while parsing rule Host(`foo.bar`: 1:14: missing ',' before newline in argument list"},
+						Status: runtime.StatusDisabled,
+					},
+					"baz@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "missing-service@myprovider",
+							Rule:        "Host(`foo.baz`)",
+						},
+						Err:    []string{"the service \"missing-service@myprovider\" does not exist"},
+						Status: runtime.StatusDisabled,
+					},
+					"test@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@myprovider",
+							Rule:        "Host(`foo.test`)",
+						},
+						Status: runtime.StatusEnabled,
+					},
+				},
+				Services: map[string]*runtime.ServiceInfo{
+					"foo-service@myprovider": {
+						Service: &dynamic.Service{
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								PassHostHeader: Bool(true),
+							},
+						},
+						Err:    []string{"no servers in the pool"},
+						Status: runtime.StatusDisabled,
+					},
+				},
+				Middlewares: map[string]*runtime.MiddlewareInfo{
+					"addPrefixTest@myprovider": {
+						Middleware: &dynamic.Middleware{
+							AddPrefix: &dynamic.AddPrefix{},
+						},
+						Err:    []string{"prefix cannot be empty"},
+						Status: runtime.StatusDisabled,
+					},
+				},
+				TCPRouters: map[string]*runtime.TCPRouterInfo{
+					"tcpbar@myprovider": {
+						TCPRouter: &dynamic.TCPRouter{
+							EntryPoints: []string{"tcp"},
+							Service:     "tcp-service@myprovider",
+							Rule:        "HostSNI(`foo.bar`)",
+						},
+						Err:    []string{"the service \"tcp-service@myprovider\" does not exist"},
+						Status: runtime.StatusDisabled,
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/errors-http.json",
+			},
+		},
+		{
+			desc: "tcp errors",
+			path: "/api/tcp/errors",
+			conf: runtime.Configuration{
+				TCPRouters: map[string]*runtime.TCPRouterInfo{
+					"tcpbar@myprovider": {
+						TCPRouter: &dynamic.TCPRouter{
+							EntryPoints: []string{"tcp"},
+							Service:     "tcp-service@myprovider",
+							Rule:        "HostSNI(`foo.bar`)",
+						},
+						Err:    []string{"the service \"tcp-service@myprovider\" does not exist"},
+						Status: runtime.StatusDisabled,
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/errors-tcp.json",
+			},
+		},
+		{
+			desc: "errors, page 2",
+			path: "/api/http/errors?page=2&per_page=1",
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"bar@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@myprovider",
+							Rule:        "Host(`foo.bar`",
+						},
+						Err:    []string{"error while parsing rule Host(`foo.bar`: 1:14: missing ',' before newline in argument list"},
+						Status: runtime.StatusDisabled,
+					},
+					"baz@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "missing-service@myprovider",
+							Rule:        "Host(`foo.baz`)",
+						},
+						Err:    []string{"the service \"missing-service@myprovider\" does not exist"},
+						Status: runtime.StatusDisabled,
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/errors-page2.json",
+			},
+		},
+		{
+			desc: "unknown protocol",
+			path: "/api/foo/errors",
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &test.conf)
+			server := httptest.NewServer(handler.createRouter())
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+
+			require.Equal(t, test.expected.statusCode, resp.StatusCode)
+
+			assert.Equal(t, test.expected.nextPage, resp.Header.Get(nextPageHeader))
+
+			if test.expected.jsonFile == "" {
+				return
+			}
+
+			assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+			contents, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			if *updateExpected {
+				var results interface{}
+				err := json.Unmarshal(contents, &results)
+				require.NoError(t, err)
+
+				newJSON, err := json.MarshalIndent(results, "", "\t")
+				require.NoError(t, err)
+
+				err = ioutil.WriteFile(test.expected.jsonFile, newJSON, 0644)
+				require.NoError(t, err)
+			}
+
+			data, err := ioutil.ReadFile(test.expected.jsonFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(contents))
+		})
+	}
+}
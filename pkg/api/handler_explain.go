@@ -0,0 +1,158 @@
+package api
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/rules"
+)
+
+type explainCandidate struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider,omitempty"`
+	Rule     string `json:"rule"`
+	Priority int    `json:"priority"`
+	Matched  bool   `json:"matched"`
+	Error    string `json:"error,omitempty"`
+}
+
+type explainRepresentation struct {
+	EntryPoint string             `json:"entryPoint"`
+	TLS        bool               `json:"tls"`
+	Router     string             `json:"router,omitempty"`
+	Candidates []explainCandidate `json:"candidates"`
+}
+
+// explainRouters tells which HTTP router of an entry point would handle a sample request,
+// and lists the candidate routers in the order they are evaluated.
+func (h Handler) explainRouters(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	query := request.URL.Query()
+
+	entryPoint := query.Get("entryPoint")
+	if entryPoint == "" {
+		writeError(rw, "missing entryPoint parameter", http.StatusBadRequest)
+		return
+	}
+
+	sample, err := newSampleRequest(query.Get("method"), query.Get("host"), query.Get("path"), query.Get("sni"), query["header"])
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := explainRepresentation{
+		EntryPoint: entryPoint,
+		TLS:        sample.TLS != nil,
+		Candidates: []explainCandidate{},
+	}
+
+	for name, rt := range h.runtimeConfiguration.Routers {
+		if rt.Status == runtime.StatusDisabled || (rt.TLS != nil) != result.TLS || !usesEntryPoint(rt, entryPoint) {
+			continue
+		}
+
+		candidate := explainCandidate{
+			Name:     name,
+			Provider: getProviderName(name),
+			Rule:     rt.Rule,
+			Priority: rt.Priority,
+		}
+
+		// Same default priority as the one given by the routing.
+		if candidate.Priority == 0 {
+			candidate.Priority = len(rt.Rule)
+		}
+
+		candidate.Matched, err = rules.Match(rt.Rule, sample)
+		if err != nil {
+			candidate.Error = err.Error()
+		}
+
+		result.Candidates = append(result.Candidates, candidate)
+	}
+
+	sort.Slice(result.Candidates, func(i, j int) bool {
+		if result.Candidates[i].Priority == result.Candidates[j].Priority {
+			return result.Candidates[i].Name < result.Candidates[j].Name
+		}
+		return result.Candidates[i].Priority > result.Candidates[j].Priority
+	})
+
+	for _, candidate := range result.Candidates {
+		if candidate.Matched {
+			result.Router = candidate.Name
+			break
+		}
+	}
+
+	err = json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newSampleRequest creates the request to match against the router rules.
+// When an SNI is given, the request is considered to be received over TLS, and its host defaults to the SNI.
+func newSampleRequest(method, host, path, sni string, headers []string) (*http.Request, error) {
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	if host == "" {
+		host = sni
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid path %q: must start with /", path)
+	}
+
+	req, err := http.NewRequest(method, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	req.Host = host
+
+	if sni != "" {
+		req.TLS = &tls.ConnectionState{ServerName: sni}
+	}
+
+	for _, header := range headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid header %q: must be in the form Name:value", header)
+		}
+
+		req.Header.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+
+	return req, nil
+}
+
+func usesEntryPoint(rt *runtime.RouterInfo, entryPoint string) bool {
+	entryPoints := rt.Using
+	if len(entryPoints) == 0 {
+		entryPoints = rt.EntryPoints
+	}
+
+	for _, ep := range entryPoints {
+		if ep == entryPoint {
+			return true
+		}
+	}
+
+	return false
+}
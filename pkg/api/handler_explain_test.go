@@ -0,0 +1,161 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Explain(t *testing.T) {
+	conf := &runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"host@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.bar`)",
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"api@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.bar`) && PathPrefix(`/api`)",
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"priority@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"web"},
+					Rule:        "PathPrefix(`/`)",
+					Priority:    1,
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"post@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"web"},
+					Rule:        "Method(`POST`) && Headers(`X-Foo`, `bar`)",
+					Priority:    100,
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"secure@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.bar`)",
+					TLS:         &dynamic.RouterTLSConfig{},
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"other@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"websecure"},
+					Rule:        "Host(`foo.bar`)",
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"disabled@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.bar`)",
+					Priority:    1000,
+				},
+				Status: runtime.StatusDisabled,
+			},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, conf)
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	testCases := []struct {
+		desc               string
+		query              url.Values
+		expectedStatusCode int
+		expectedRouter     string
+		expectedCandidates []string
+		expectedMatched    []string
+	}{
+		{
+			desc:               "missing entry point",
+			query:              url.Values{"host": {"foo.bar"}},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "invalid header",
+			query:              url.Values{"entryPoint": {"web"}, "header": {"X-Foo"}},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "most specific router",
+			query:              url.Values{"entryPoint": {"web"}, "host": {"foo.bar"}, "path": {"/api/users"}},
+			expectedStatusCode: http.StatusOK,
+			expectedRouter:     "api@myprovider",
+			expectedCandidates: []string{"post@myprovider", "api@myprovider", "host@myprovider", "priority@myprovider"},
+			expectedMatched:    []string{"api@myprovider", "host@myprovider", "priority@myprovider"},
+		},
+		{
+			desc:               "explicit priority",
+			query:              url.Values{"entryPoint": {"web"}, "method": {"POST"}, "host": {"foo.bar"}, "header": {"X-Foo: bar"}},
+			expectedStatusCode: http.StatusOK,
+			expectedRouter:     "post@myprovider",
+			expectedCandidates: []string{"post@myprovider", "api@myprovider", "host@myprovider", "priority@myprovider"},
+			expectedMatched:    []string{"post@myprovider", "host@myprovider", "priority@myprovider"},
+		},
+		{
+			desc:               "no matching router",
+			query:              url.Values{"entryPoint": {"websecure"}, "host": {"bar.foo"}},
+			expectedStatusCode: http.StatusOK,
+			expectedCandidates: []string{"other@myprovider"},
+		},
+		{
+			desc:               "TLS routers with the SNI",
+			query:              url.Values{"entryPoint": {"web"}, "sni": {"foo.bar"}},
+			expectedStatusCode: http.StatusOK,
+			expectedRouter:     "secure@myprovider",
+			expectedCandidates: []string{"secure@myprovider"},
+			expectedMatched:    []string{"secure@myprovider"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			resp, err := http.DefaultClient.Get(server.URL + "/api/http/explain?" + test.query.Encode())
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			var result explainRepresentation
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+			assert.Equal(t, test.expectedRouter, result.Router)
+
+			var candidates, matched []string
+			for _, candidate := range result.Candidates {
+				candidates = append(candidates, candidate.Name)
+				if candidate.Matched {
+					matched = append(matched, candidate.Name)
+				}
+			}
+			assert.Equal(t, test.expectedCandidates, candidates)
+			assert.Equal(t, test.expectedMatched, matched)
+		})
+	}
+}
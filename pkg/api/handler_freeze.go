@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/freeze"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/types"
+)
+
+// freezeRequest is the body of a freeze request.
+type freezeRequest struct {
+	Reason string `json:"reason,omitempty"`
+	// Duration is the duration after what the freeze ends automatically, none by default.
+	Duration types.Duration `json:"duration,omitempty"`
+}
+
+func (h Handler) getFreeze(rw http.ResponseWriter, request *http.Request) {
+	h.writeFreezeStatus(rw, request)
+}
+
+func (h Handler) putFreeze(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	var freezeReq freezeRequest
+	if request.ContentLength != 0 {
+		if err := json.NewDecoder(request.Body).Decode(&freezeReq); err != nil {
+			writeError(rw, fmt.Sprintf("invalid freeze request: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if freezeReq.Duration < 0 {
+		writeError(rw, fmt.Sprintf("invalid freeze duration: %s", freezeReq.Duration), http.StatusBadRequest)
+		return
+	}
+
+	h.freeze.Freeze(freezeReq.Reason, time.Duration(freezeReq.Duration))
+
+	h.writeFreezeStatus(rw, request)
+}
+
+func (h Handler) deleteFreeze(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := h.freeze.Unfreeze()
+	if errors.Is(err, freeze.ErrNotFrozen) {
+		writeError(rw, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (h Handler) writeFreezeStatus(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(h.freeze.Status())
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
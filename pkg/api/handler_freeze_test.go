@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/freeze"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Freeze(t *testing.T) {
+	configFreeze := freeze.New()
+
+	var applied []string
+	configFreeze.SetUnfreezeFunc(func(providers []string) {
+		applied = providers
+	})
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &runtime.Configuration{})
+	handler.freeze = configFreeze
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	do := func(method, body string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+"/api/freeze", strings.NewReader(body))
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+
+		return resp
+	}
+
+	getStatus := func() freeze.Status {
+		resp := do(http.MethodGet, "")
+		defer func() { _ = resp.Body.Close() }()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		var status freeze.Status
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+
+		return status
+	}
+
+	assert.Equal(t, freeze.Status{}, getStatus())
+
+	resp := do(http.MethodPut, `{"duration": "-1h"}`)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = do(http.MethodPut, `{"reason": "peak", "duration": "1h"}`)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	status := getStatus()
+	assert.True(t, status.Frozen)
+	assert.Equal(t, "peak", status.Reason)
+	require.NotNil(t, status.Since)
+	require.NotNil(t, status.Until)
+	assert.True(t, status.Until.After(*status.Since))
+
+	assert.True(t, configFreeze.Queue("file"))
+	assert.Equal(t, []string{"file"}, getStatus().PendingProviders)
+
+	resp = do(http.MethodDelete, "")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	assert.Equal(t, []string{"file"}, applied)
+	assert.Equal(t, freeze.Status{}, getStatus())
+
+	resp = do(http.MethodDelete, "")
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/containous/traefik/v2/pkg/generations"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/gorilla/mux"
+)
+
+func (h Handler) getGenerations(rw http.ResponseWriter, request *http.Request) {
+	results := h.history.List()
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) rollbackGeneration(rw http.ResponseWriter, request *http.Request) {
+	generationID := mux.Vars(request)["generationID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.ParseUint(generationID, 10, 64)
+	if err != nil {
+		writeError(rw, fmt.Sprintf("invalid generation ID: %s", generationID), http.StatusBadRequest)
+		return
+	}
+
+	err = h.history.Rollback(id)
+	if errors.Is(err, generations.ErrNotFound) {
+		writeError(rw, fmt.Sprintf("generation not found: %s", generationID), http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
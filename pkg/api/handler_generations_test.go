@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/generations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Generations(t *testing.T) {
+	history := generations.NewHistory(2)
+
+	var rolledBack dynamic.Configurations
+	history.SetRollbackFunc(func(id uint64, configurations dynamic.Configurations) error {
+		rolledBack = configurations
+		history.SetCurrent(id)
+		return nil
+	})
+
+	history.Add("file", dynamic.Configurations{"file": &dynamic.Configuration{}})
+	history.Add("docker", dynamic.Configurations{"file": &dynamic.Configuration{}, "docker": &dynamic.Configuration{}})
+	history.Add("file", dynamic.Configurations{"file": &dynamic.Configuration{}, "docker": &dynamic.Configuration{HTTP: &dynamic.HTTPConfiguration{}}})
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &runtime.Configuration{})
+	handler.history = history
+	server := httptest.NewServer(handler.createRouter())
+	defer server.Close()
+
+	getGenerations := func() []generations.Generation {
+		resp, err := http.Get(server.URL + "/api/generations")
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		var results []generations.Generation
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&results))
+
+		return results
+	}
+
+	// The oldest generation is dropped.
+	results := getGenerations()
+	require.Len(t, results, 2)
+	assert.Equal(t, uint64(3), results[0].ID)
+	assert.True(t, results[0].Current)
+	assert.Equal(t, uint64(2), results[1].ID)
+	assert.Equal(t, "docker", results[1].Provider)
+	assert.False(t, results[1].Current)
+
+	testCases := []struct {
+		desc           string
+		generationID   string
+		expectedStatus int
+	}{
+		{
+			desc:           "dropped generation",
+			generationID:   "1",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:           "invalid generation ID",
+			generationID:   "foo",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "previous generation",
+			generationID:   "2",
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, test := range testCases {
+		resp, err := http.Post(server.URL+"/api/generations/"+test.generationID+"/rollback", "", nil)
+		require.NoError(t, err, test.desc)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, test.expectedStatus, resp.StatusCode, test.desc)
+	}
+
+	assert.Equal(t, dynamic.Configurations{"file": &dynamic.Configuration{}, "docker": &dynamic.Configuration{}}, rolledBack)
+
+	results = getGenerations()
+	require.Len(t, results, 2)
+	assert.False(t, results[0].Current)
+	assert.True(t, results[1].Current)
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+func (h Handler) getPanics(rw http.ResponseWriter, request *http.Request) {
+	results := h.panicReports.List()
+
+	query := request.URL.Query()
+	if middleware := query.Get("middleware"); middleware != "" {
+		filtered := results[:0]
+		for _, report := range results {
+			if report.Middleware == middleware {
+				filtered = append(filtered, report)
+			}
+		}
+		results = filtered
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/panics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Panics(t *testing.T) {
+	panicReports := panics.NewReports(panics.DefaultCapacity, metrics.NewVoidRegistry())
+	panicReports.Add(panics.Report{EntryPoint: "web", Router: "foo@file", Middleware: "auth@file", Error: "first"})
+	panicReports.Add(panics.Report{EntryPoint: "web", Router: "bar@file", Error: "second"})
+
+	testCases := []struct {
+		desc           string
+		path           string
+		expectedErrors []string
+	}{
+		{
+			desc:           "all reports",
+			path:           "/api/http/panics",
+			expectedErrors: []string{"second", "first"},
+		},
+		{
+			desc:           "reports of a middleware",
+			path:           "/api/http/panics?middleware=auth@file",
+			expectedErrors: []string{"first"},
+		},
+		{
+			desc:           "reports of a middleware without panic",
+			path:           "/api/http/panics?middleware=other@file",
+			expectedErrors: []string{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &runtime.Configuration{})
+			handler.panicReports = panicReports
+			server := httptest.NewServer(handler.createRouter())
+			defer server.Close()
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			var reports []panics.Report
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&reports))
+
+			errs := make([]string, 0, len(reports))
+			for _, report := range reports {
+				errs = append(errs, report.Error)
+			}
+
+			assert.Equal(t, test.expectedErrors, errs)
+		})
+	}
+}
@@ -25,6 +25,119 @@ type Configuration struct {
 	TCP  *TCPConfiguration  `json:"tcp,omitempty" toml:"tcp,omitempty" yaml:"tcp,omitempty"`
 	UDP  *UDPConfiguration  `json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
 	TLS  *TLSConfiguration  `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
+	// Origins are recorded by the providers, and cannot be configured.
+	Origins *Origins `json:"-" toml:"-" yaml:"-" label:"-"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Origin describes the provider resource a router, a service or a middleware was defined in.
+type Origin struct {
+	// File is the path of the file the element was defined in, and Line the line of its definition, when known.
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+	// ContainerID is the ID of the container whose labels define the element.
+	ContainerID string `json:"containerID,omitempty"`
+	// Kind, Namespace, Name, UID and ResourceVersion identify the Kubernetes object the element was defined in.
+	Kind            string `json:"kind,omitempty"`
+	Namespace       string `json:"namespace,omitempty"`
+	Name            string `json:"name,omitempty"`
+	UID             string `json:"uid,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Origins holds the origins of the routers, services and middlewares of a configuration, by name.
+type Origins struct {
+	Routers     map[string][]Origin `json:"routers,omitempty"`
+	Services    map[string][]Origin `json:"services,omitempty"`
+	Middlewares map[string][]Origin `json:"middlewares,omitempty"`
+	TCPRouters  map[string][]Origin `json:"tcpRouters,omitempty"`
+	TCPServices map[string][]Origin `json:"tcpServices,omitempty"`
+	UDPRouters  map[string][]Origin `json:"udpRouters,omitempty"`
+	UDPServices map[string][]Origin `json:"udpServices,omitempty"`
+}
+
+// AddHTTP records the origin of the routers, services and middlewares of conf which do not have one yet.
+func (o *Origins) AddHTTP(conf *HTTPConfiguration, origin Origin) {
+	if conf == nil {
+		return
+	}
+
+	for name := range conf.Routers {
+		addOrigin(&o.Routers, name, origin)
+	}
+	for name := range conf.Services {
+		addOrigin(&o.Services, name, origin)
+	}
+	for name := range conf.Middlewares {
+		addOrigin(&o.Middlewares, name, origin)
+	}
+}
+
+// AddTCP records the origin of the TCP routers and services of conf which do not have one yet.
+func (o *Origins) AddTCP(conf *TCPConfiguration, origin Origin) {
+	if conf == nil {
+		return
+	}
+
+	for name := range conf.Routers {
+		addOrigin(&o.TCPRouters, name, origin)
+	}
+	for name := range conf.Services {
+		addOrigin(&o.TCPServices, name, origin)
+	}
+}
+
+// AddUDP records the origin of the UDP routers and services of conf which do not have one yet.
+func (o *Origins) AddUDP(conf *UDPConfiguration, origin Origin) {
+	if conf == nil {
+		return
+	}
+
+	for name := range conf.Routers {
+		addOrigin(&o.UDPRouters, name, origin)
+	}
+	for name := range conf.Services {
+		addOrigin(&o.UDPServices, name, origin)
+	}
+}
+
+// Merge appends the origins of other to the ones of o, renaming the elements with rename, if any.
+func (o *Origins) Merge(other *Origins, rename func(name string) string) {
+	if other == nil {
+		return
+	}
+
+	dst := o.all()
+	for i, src := range other.all() {
+		for name, origins := range *src {
+			if *dst[i] == nil {
+				*dst[i] = make(map[string][]Origin)
+			}
+
+			key := name
+			if rename != nil {
+				key = rename(name)
+			}
+			(*dst[i])[key] = append((*dst[i])[key], origins...)
+		}
+	}
+}
+
+func (o *Origins) all() []*map[string][]Origin {
+	return []*map[string][]Origin{&o.Routers, &o.Services, &o.Middlewares, &o.TCPRouters, &o.TCPServices, &o.UDPRouters, &o.UDPServices}
+}
+
+func addOrigin(origins *map[string][]Origin, name string, origin Origin) {
+	if *origins == nil {
+		*origins = make(map[string][]Origin)
+	}
+
+	if _, ok := (*origins)[name]; !ok {
+		(*origins)[name] = []Origin{origin}
+	}
 }
 
 // +k8s:deepcopy-gen=true
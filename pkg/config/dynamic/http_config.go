@@ -1,6 +1,7 @@
 package dynamic
 
 import (
+	"net/http"
 	"reflect"
 
 	"github.com/containous/traefik/v2/pkg/types"
@@ -31,18 +32,25 @@ type Service struct {
 	LoadBalancer *ServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty"`
 	Weighted     *WeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-"`
 	Mirroring    *Mirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-"`
+	Static       *StaticFiles         `json:"static,omitempty" toml:"static,omitempty" yaml:"static,omitempty" label:"-"`
+	Redirect     *RedirectService     `json:"redirect,omitempty" toml:"redirect,omitempty" yaml:"redirect,omitempty" label:"-"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // Router holds the router configuration.
 type Router struct {
-	EntryPoints []string         `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty"`
-	Middlewares []string         `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
-	Service     string           `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
-	Rule        string           `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
-	Priority    int              `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty"`
-	TLS         *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty"`
+	EntryPoints         []string            `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty"`
+	Middlewares         []string            `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
+	Service             string              `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
+	Rule                string              `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
+	Priority            int                 `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty"`
+	TLS                 *RouterTLSConfig    `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty"`
+	DebugHeaders        bool                `json:"debugHeaders,omitempty" toml:"debugHeaders,omitempty" yaml:"debugHeaders,omitempty"`
+	Quota               *RouterQuota        `json:"quota,omitempty" toml:"quota,omitempty" yaml:"quota,omitempty"`
+	BodyTimeouts        *RouterBodyTimeouts `json:"bodyTimeouts,omitempty" toml:"bodyTimeouts,omitempty" yaml:"bodyTimeouts,omitempty"`
+	KeepResponseHeaders []string            `json:"keepResponseHeaders,omitempty" toml:"keepResponseHeaders,omitempty" yaml:"keepResponseHeaders,omitempty"`
+	ShutdownOrder       *int                `json:"shutdownOrder,omitempty" toml:"shutdownOrder,omitempty" yaml:"shutdownOrder,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -52,15 +60,42 @@ type RouterTLSConfig struct {
 	Options      string         `json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty"`
 	CertResolver string         `json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty"`
 	Domains      []types.Domain `json:"domains,omitempty" toml:"domains,omitempty" yaml:"domains,omitempty"`
+	Certificate  string         `json:"certificate,omitempty" toml:"certificate,omitempty" yaml:"certificate,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouterBodyTimeouts holds the timeouts of the reading of the request bodies of a router.
+// They replace the readTimeout of the entry point for the bodies of the requests handled by the router.
+type RouterBodyTimeouts struct {
+	// ReadTimeout is the maximum duration for reading the entire body, from the start of the request.
+	ReadTimeout types.Duration `json:"readTimeout,omitempty" toml:"readTimeout,omitempty" yaml:"readTimeout,omitempty"`
+	// IdleTimeout is the maximum duration to wait for the next chunk of the body.
+	IdleTimeout types.Duration `json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouterQuota holds the monthly byte quota of a router.
+type RouterQuota struct {
+	MonthlyBytes int64  `json:"monthlyBytes,omitempty" toml:"monthlyBytes,omitempty" yaml:"monthlyBytes,omitempty"`
+	StatusCode   int    `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty"`
+	Message      string `json:"message,omitempty" toml:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// SetDefaults sets the default values on a RouterQuota.
+func (r *RouterQuota) SetDefaults() {
+	r.StatusCode = http.StatusTooManyRequests
 }
 
 // +k8s:deepcopy-gen=true
 
 // Mirroring holds the Mirroring configuration.
 type Mirroring struct {
-	Service     string          `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
-	MaxBodySize *int64          `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty"`
-	Mirrors     []MirrorService `json:"mirrors,omitempty" toml:"mirrors,omitempty" yaml:"mirrors,omitempty"`
+	Service     string              `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
+	MaxBodySize *int64              `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty"`
+	Streaming   *MirroringStreaming `json:"streaming,omitempty" toml:"streaming,omitempty" yaml:"streaming,omitempty" label:"allowEmpty"`
+	Mirrors     []MirrorService     `json:"mirrors,omitempty" toml:"mirrors,omitempty" yaml:"mirrors,omitempty"`
 }
 
 // SetDefaults Default values for a WRRService.
@@ -71,6 +106,15 @@ func (m *Mirroring) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// MirroringStreaming holds the configuration of the streaming of the request bodies to the mirrors,
+// which replaces their buffering, so that the requests are mirrored whatever the size of their body.
+type MirroringStreaming struct {
+	// MaxLag is how many bytes of the body a mirror can lag behind the main request, before its request is aborted.
+	MaxLag int64 `json:"maxLag,omitempty" toml:"maxLag,omitempty" yaml:"maxLag,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // MirrorService holds the MirrorService configuration.
 type MirrorService struct {
 	Name    string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty"`
@@ -79,6 +123,37 @@ type MirrorService struct {
 
 // +k8s:deepcopy-gen=true
 
+// RedirectService holds the configuration of a service answering all the requests with a redirection.
+type RedirectService struct {
+	// URL is a template of the redirection URL, which can use the Scheme, Host, Path and Query placeholders.
+	URL        string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	StatusCode int    `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty"`
+}
+
+// SetDefaults Default values for a RedirectService.
+func (r *RedirectService) SetDefaults() {
+	r.StatusCode = http.StatusFound
+}
+
+// +k8s:deepcopy-gen=true
+
+// StaticFiles holds the configuration of a service serving the files of a local directory.
+type StaticFiles struct {
+	Root       string   `json:"root,omitempty" toml:"root,omitempty" yaml:"root,omitempty"`
+	IndexFiles []string `json:"indexFiles,omitempty" toml:"indexFiles,omitempty" yaml:"indexFiles,omitempty"`
+	// CacheControl is the value of the Cache-Control header set on the responses.
+	CacheControl string `json:"cacheControl,omitempty" toml:"cacheControl,omitempty" yaml:"cacheControl,omitempty"`
+	// SPAFallback is the path, relative to the root, of the file served when the requested file does not exist.
+	SPAFallback string `json:"spaFallback,omitempty" toml:"spaFallback,omitempty" yaml:"spaFallback,omitempty"`
+}
+
+// SetDefaults Default values for a StaticFiles.
+func (s *StaticFiles) SetDefaults() {
+	s.IndexFiles = []string{"index.html"}
+}
+
+// +k8s:deepcopy-gen=true
+
 // WeightedRoundRobin is a weighted round robin load-balancer of services.
 type WeightedRoundRobin struct {
 	Services []WRRService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty"`
@@ -114,17 +189,43 @@ type Cookie struct {
 	Secure   bool   `json:"secure,omitempty" toml:"secure,omitempty" yaml:"secure,omitempty"`
 	HTTPOnly bool   `json:"httpOnly,omitempty" toml:"httpOnly,omitempty" yaml:"httpOnly,omitempty"`
 	SameSite string `json:"sameSite,omitempty" toml:"sameSite,omitempty" yaml:"sameSite,omitempty"`
+	// Protection signs, and optionally encrypts, the value of the cookie.
+	Protection *CookieProtection `json:"protection,omitempty" toml:"protection,omitempty" yaml:"protection,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CookieProtection holds the options protecting the value of a sticky cookie, which names a server or a service,
+// against tampering and disclosure.
+type CookieProtection struct {
+	// Keys are the secret keys of the cookie values, of at least 32 characters.
+	// The first one protects the new values, and all of them are accepted to read the existing ones, so that the keys can be rotated.
+	Keys []string `json:"keys,omitempty" toml:"keys,omitempty" yaml:"keys,omitempty"`
+	// Encrypt makes the values encrypted with AES-GCM, instead of only signed with HMAC-SHA256.
+	Encrypt bool `json:"encrypt,omitempty" toml:"encrypt,omitempty" yaml:"encrypt,omitempty"`
+	// OpaqueIDs replaces the server URLs and the service names with opaque identifiers derived from the keys.
+	OpaqueIDs bool `json:"opaqueIDs,omitempty" toml:"opaqueIDs,omitempty" yaml:"opaqueIDs,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // ServersLoadBalancer holds the ServersLoadBalancer configuration.
 type ServersLoadBalancer struct {
-	Sticky             *Sticky             `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" label:"allowEmpty"`
-	Servers            []Server            `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
-	HealthCheck        *HealthCheck        `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
-	PassHostHeader     *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader"`
-	ResponseForwarding *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty"`
+	Sticky              *Sticky             `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" label:"allowEmpty"`
+	Servers             []Server            `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
+	HealthCheck         *HealthCheck        `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
+	PassHostHeader      *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader"`
+	HostHeader          *HostHeader         `json:"hostHeader,omitempty" toml:"hostHeader,omitempty" yaml:"hostHeader,omitempty"`
+	ResponseForwarding  *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty"`
+	HeaderPropagation   *HeaderPropagation  `json:"headerPropagation,omitempty" toml:"headerPropagation,omitempty" yaml:"headerPropagation,omitempty"`
+	DNSExpansion        *DNSExpansion       `json:"dnsExpansion,omitempty" toml:"dnsExpansion,omitempty" yaml:"dnsExpansion,omitempty" label:"allowEmpty"`
+	ServersTLS          *ServersTLS         `json:"serversTLS,omitempty" toml:"serversTLS,omitempty" yaml:"serversTLS,omitempty"`
+	ConsistentHash      *ConsistentHash     `json:"consistentHash,omitempty" toml:"consistentHash,omitempty" yaml:"consistentHash,omitempty" label:"allowEmpty"`
+	OutlierDetection    *OutlierDetection   `json:"outlierDetection,omitempty" toml:"outlierDetection,omitempty" yaml:"outlierDetection,omitempty" label:"allowEmpty"`
+	P2C                 *P2C                `json:"p2c,omitempty" toml:"p2c,omitempty" yaml:"p2c,omitempty" label:"allowEmpty"`
+	SlowStart           *SlowStart          `json:"slowStart,omitempty" toml:"slowStart,omitempty" yaml:"slowStart,omitempty"`
+	ServerOverride      *ServerOverride     `json:"serverOverride,omitempty" toml:"serverOverride,omitempty" yaml:"serverOverride,omitempty"`
+	ShutdownGracePeriod types.Duration      `json:"shutdownGracePeriod,omitempty" toml:"shutdownGracePeriod,omitempty" yaml:"shutdownGracePeriod,omitempty"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -152,18 +253,115 @@ func (l *ServersLoadBalancer) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// HostHeader holds the policy applied to the Host header of the requests forwarded to the servers.
+// With HTTP/2 servers, it applies to the :authority pseudo-header, which replaces the Host header.
+type HostHeader struct {
+	// Mode is either preserve (the inbound Host), server (the host of the selected server), or template.
+	Mode string `json:"mode,omitempty" toml:"mode,omitempty" yaml:"mode,omitempty"`
+	// Value is the template of the Host header, when Mode is template.
+	Value string `json:"value,omitempty" toml:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // ResponseForwarding holds configuration for the forward of the response.
 type ResponseForwarding struct {
 	FlushInterval string `json:"flushInterval,omitempty" toml:"flushInterval,omitempty" yaml:"flushInterval,omitempty"`
+	// ErrorCauseHeader is the name of the response header set with the cause of the 502 and 504 status codes generated by Traefik.
+	ErrorCauseHeader string `json:"errorCauseHeader,omitempty" toml:"errorCauseHeader,omitempty" yaml:"errorCauseHeader,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ServersTLS holds the TLS options overriding the ones of the serversTransport to connect to the servers.
+type ServersTLS struct {
+	// ServerName is the server name sent with SNI and verified against the certificates of the servers,
+	// where the {host} placeholder is replaced by the host of the request.
+	ServerName string `json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty"`
+	// PinnedPublicKeys are the base64 encoded SHA-256 hashes of the expected public keys (SPKI) of the certificates of the servers.
+	PinnedPublicKeys []string `json:"pinnedPublicKeys,omitempty" toml:"pinnedPublicKeys,omitempty" yaml:"pinnedPublicKeys,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// DNSExpansion expands the servers into one server per IP address their hostname resolves to.
+type DNSExpansion struct {
+	// RefreshInterval is how often the hostnames are resolved again.
+	RefreshInterval string `json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// P2C balances each request between two servers picked at random (power of two choices),
+// forwarding it to the one with the fewest in-flight requests.
+type P2C struct {
+	// EWMA weights the in-flight requests of the servers with the moving average of their response times.
+	EWMA bool `json:"ewma,omitempty" toml:"ewma,omitempty" yaml:"ewma,omitempty"`
+	// DecayTime is how fast the past response times fade from the moving average, defaulting to 10s.
+	DecayTime string `json:"decayTime,omitempty" toml:"decayTime,omitempty" yaml:"decayTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SlowStart ramps up linearly the weight of the servers returning to the load-balancer,
+// after they were removed by the health check or the outlier detection.
+type SlowStart struct {
+	// Duration is how long the weight of a returning server takes to reach its full value.
+	Duration string `json:"duration,omitempty" toml:"duration,omitempty" yaml:"duration,omitempty"`
+	// InitialPercent is the percentage of its full weight a returning server starts with.
+	InitialPercent int `json:"initialPercent,omitempty" toml:"initialPercent,omitempty,omitzero" yaml:"initialPercent,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ConsistentHash balances the requests on a hash ring of the servers, keyed on an attribute of the requests,
+// so that the requests with the same key are forwarded to the same server, as long as it is available.
+type ConsistentHash struct {
+	// Header, Cookie and Query are the names of the request header, cookie, or query parameter used as the key.
+	// At most one of them can be set, and the client IP is used when none is set, or the request does not have it.
+	Header string `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty"`
+	Cookie string `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty"`
+	Query  string `json:"query,omitempty" toml:"query,omitempty" yaml:"query,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ServerOverride holds the configuration of the signed header pinning a request to a server of the service,
+// bypassing the load-balancer.
+type ServerOverride struct {
+	// HeaderName is the name of the header holding the URL of the server, its expiration time, and their signature.
+	HeaderName string `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty"`
+	// Secret is the key of the HMAC-SHA256 signatures of the header values.
+	Secret string `json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty"`
+}
+
+// SetDefaults Default values for a ServerOverride.
+func (s *ServerOverride) SetDefaults() {
+	s.HeaderName = "X-Traefik-Server"
+}
+
+// +k8s:deepcopy-gen=true
+
+// HeaderPropagation holds the policy applied to the request headers before they are forwarded to the servers.
+type HeaderPropagation struct {
+	// ForwardedHeaders is the list of the inbound headers allowed to reach the servers.
+	// When empty, all the inbound headers are forwarded.
+	ForwardedHeaders []string `json:"forwardedHeaders,omitempty" toml:"forwardedHeaders,omitempty" yaml:"forwardedHeaders,omitempty"`
+	// StrippedHeaders is the list of the inbound headers removed before reaching the servers.
+	StrippedHeaders []string `json:"strippedHeaders,omitempty" toml:"strippedHeaders,omitempty" yaml:"strippedHeaders,omitempty"`
+	// AddedHeaders are the headers set on the forwarded request, their values are Go templates
+	// evaluated against the inbound request (e.g. "{{ .Request.Host }}").
+	AddedHeaders map[string]string `json:"addedHeaders,omitempty" toml:"addedHeaders,omitempty" yaml:"addedHeaders,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // Server holds the server configuration.
 type Server struct {
-	URL    string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty" label:"-"`
-	Scheme string `toml:"-" json:"-" yaml:"-"`
-	Port   string `toml:"-" json:"-" yaml:"-"`
+	URL         string             `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty" label:"-"`
+	Scheme      string             `toml:"-" json:"-" yaml:"-"`
+	Port        string             `toml:"-" json:"-" yaml:"-"`
+	HealthCheck *ServerHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"-"`
 }
 
 // SetDefaults Default values for a Server.
@@ -173,6 +371,28 @@ func (s *Server) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// ServerHealthCheck overrides, for a server, the endpoint of the health check of its service.
+type ServerHealthCheck struct {
+	Path string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty"`
+	Port int    `json:"port,omitempty" toml:"port,omitempty,omitzero" yaml:"port,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// OutlierDetection ejects from the load-balancer the servers answering the forwarded requests with consecutive errors.
+type OutlierDetection struct {
+	// ConsecutiveErrors is the number of consecutive 5XX responses, including the connection errors, ejecting a server.
+	ConsecutiveErrors int `json:"consecutiveErrors,omitempty" toml:"consecutiveErrors,omitempty,omitzero" yaml:"consecutiveErrors,omitempty"`
+	// BaseEjectionTime is the duration of the first ejection of a server, doubled on each following ejection.
+	BaseEjectionTime string `json:"baseEjectionTime,omitempty" toml:"baseEjectionTime,omitempty" yaml:"baseEjectionTime,omitempty"`
+	// MaxEjectionTime caps the duration of the ejections.
+	MaxEjectionTime string `json:"maxEjectionTime,omitempty" toml:"maxEjectionTime,omitempty" yaml:"maxEjectionTime,omitempty"`
+	// MaxEjectionPercent is the maximum percentage of the servers which can be ejected at the same time.
+	MaxEjectionPercent int `json:"maxEjectionPercent,omitempty" toml:"maxEjectionPercent,omitempty,omitzero" yaml:"maxEjectionPercent,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // HealthCheck holds the HealthCheck configuration.
 type HealthCheck struct {
 	Scheme string `json:"scheme,omitempty" toml:"scheme,omitempty" yaml:"scheme,omitempty"`
@@ -16,28 +16,43 @@ import (
 
 // Middleware holds the Middleware configuration.
 type Middleware struct {
-	AddPrefix         *AddPrefix         `json:"addPrefix,omitempty" toml:"addPrefix,omitempty" yaml:"addPrefix,omitempty"`
-	StripPrefix       *StripPrefix       `json:"stripPrefix,omitempty" toml:"stripPrefix,omitempty" yaml:"stripPrefix,omitempty"`
-	StripPrefixRegex  *StripPrefixRegex  `json:"stripPrefixRegex,omitempty" toml:"stripPrefixRegex,omitempty" yaml:"stripPrefixRegex,omitempty"`
-	ReplacePath       *ReplacePath       `json:"replacePath,omitempty" toml:"replacePath,omitempty" yaml:"replacePath,omitempty"`
-	ReplacePathRegex  *ReplacePathRegex  `json:"replacePathRegex,omitempty" toml:"replacePathRegex,omitempty" yaml:"replacePathRegex,omitempty"`
-	Chain             *Chain             `json:"chain,omitempty" toml:"chain,omitempty" yaml:"chain,omitempty"`
-	IPWhiteList       *IPWhiteList       `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty"`
-	Headers           *Headers           `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
-	Errors            *ErrorPage         `json:"errors,omitempty" toml:"errors,omitempty" yaml:"errors,omitempty"`
-	RateLimit         *RateLimit         `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
-	RedirectRegex     *RedirectRegex     `json:"redirectRegex,omitempty" toml:"redirectRegex,omitempty" yaml:"redirectRegex,omitempty"`
-	RedirectScheme    *RedirectScheme    `json:"redirectScheme,omitempty" toml:"redirectScheme,omitempty" yaml:"redirectScheme,omitempty"`
-	BasicAuth         *BasicAuth         `json:"basicAuth,omitempty" toml:"basicAuth,omitempty" yaml:"basicAuth,omitempty"`
-	DigestAuth        *DigestAuth        `json:"digestAuth,omitempty" toml:"digestAuth,omitempty" yaml:"digestAuth,omitempty"`
-	ForwardAuth       *ForwardAuth       `json:"forwardAuth,omitempty" toml:"forwardAuth,omitempty" yaml:"forwardAuth,omitempty"`
-	InFlightReq       *InFlightReq       `json:"inFlightReq,omitempty" toml:"inFlightReq,omitempty" yaml:"inFlightReq,omitempty"`
-	Buffering         *Buffering         `json:"buffering,omitempty" toml:"buffering,omitempty" yaml:"buffering,omitempty"`
-	CircuitBreaker    *CircuitBreaker    `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty"`
-	Compress          *Compress          `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty"`
-	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty"`
-	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty"`
-	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty"`
+	AddPrefix           *AddPrefix           `json:"addPrefix,omitempty" toml:"addPrefix,omitempty" yaml:"addPrefix,omitempty"`
+	StripPrefix         *StripPrefix         `json:"stripPrefix,omitempty" toml:"stripPrefix,omitempty" yaml:"stripPrefix,omitempty"`
+	StripPrefixRegex    *StripPrefixRegex    `json:"stripPrefixRegex,omitempty" toml:"stripPrefixRegex,omitempty" yaml:"stripPrefixRegex,omitempty"`
+	ReplacePath         *ReplacePath         `json:"replacePath,omitempty" toml:"replacePath,omitempty" yaml:"replacePath,omitempty"`
+	ReplacePathRegex    *ReplacePathRegex    `json:"replacePathRegex,omitempty" toml:"replacePathRegex,omitempty" yaml:"replacePathRegex,omitempty"`
+	Chain               *Chain               `json:"chain,omitempty" toml:"chain,omitempty" yaml:"chain,omitempty"`
+	IPWhiteList         *IPWhiteList         `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty"`
+	Headers             *Headers             `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	Errors              *ErrorPage           `json:"errors,omitempty" toml:"errors,omitempty" yaml:"errors,omitempty"`
+	RateLimit           *RateLimit           `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+	RedirectRegex       *RedirectRegex       `json:"redirectRegex,omitempty" toml:"redirectRegex,omitempty" yaml:"redirectRegex,omitempty"`
+	RedirectScheme      *RedirectScheme      `json:"redirectScheme,omitempty" toml:"redirectScheme,omitempty" yaml:"redirectScheme,omitempty"`
+	BasicAuth           *BasicAuth           `json:"basicAuth,omitempty" toml:"basicAuth,omitempty" yaml:"basicAuth,omitempty"`
+	DigestAuth          *DigestAuth          `json:"digestAuth,omitempty" toml:"digestAuth,omitempty" yaml:"digestAuth,omitempty"`
+	ForwardAuth         *ForwardAuth         `json:"forwardAuth,omitempty" toml:"forwardAuth,omitempty" yaml:"forwardAuth,omitempty"`
+	InFlightReq         *InFlightReq         `json:"inFlightReq,omitempty" toml:"inFlightReq,omitempty" yaml:"inFlightReq,omitempty"`
+	Buffering           *Buffering           `json:"buffering,omitempty" toml:"buffering,omitempty" yaml:"buffering,omitempty"`
+	CircuitBreaker      *CircuitBreaker      `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty"`
+	Compress            *Compress            `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty"`
+	PassTLSClientCert   *PassTLSClientCert   `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty"`
+	Retry               *Retry               `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty"`
+	ContentType         *ContentType         `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty"`
+	ETag                *ETag                `json:"etag,omitempty" toml:"etag,omitempty" yaml:"etag,omitempty" label:"allowEmpty"`
+	Aggregate           *Aggregate           `json:"aggregate,omitempty" toml:"aggregate,omitempty" yaml:"aggregate,omitempty"`
+	Introspection       *Introspection       `json:"introspection,omitempty" toml:"introspection,omitempty" yaml:"introspection,omitempty"`
+	ClientCertPolicy    *ClientCertPolicy    `json:"clientCertPolicy,omitempty" toml:"clientCertPolicy,omitempty" yaml:"clientCertPolicy,omitempty"`
+	AdaptiveConcurrency *AdaptiveConcurrency `json:"adaptiveConcurrency,omitempty" toml:"adaptiveConcurrency,omitempty" yaml:"adaptiveConcurrency,omitempty" label:"allowEmpty"`
+	CORS                *CORS                `json:"cors,omitempty" toml:"cors,omitempty" yaml:"cors,omitempty"`
+	ResourceHints       *ResourceHints       `json:"resourceHints,omitempty" toml:"resourceHints,omitempty" yaml:"resourceHints,omitempty"`
+	OIDC                *OIDC                `json:"oidc,omitempty" toml:"oidc,omitempty" yaml:"oidc,omitempty"`
+	JWT                 *JWT                 `json:"jwt,omitempty" toml:"jwt,omitempty" yaml:"jwt,omitempty"`
+	Cache               *Cache               `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty"`
+	RewriteBody         *RewriteBody         `json:"rewriteBody,omitempty" toml:"rewriteBody,omitempty" yaml:"rewriteBody,omitempty"`
+
+	// Optional makes the middleware skipped (fail-open), instead of failing the requests (fail-closed),
+	// when it cannot handle them because its external dependency (e.g. an authentication server) is unavailable.
+	Optional bool `json:"optional,omitempty" toml:"optional,omitempty" yaml:"optional,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -57,6 +72,34 @@ type ContentType struct {
 
 // +k8s:deepcopy-gen=true
 
+// AdaptiveConcurrency holds the adaptive concurrency middleware configuration.
+// This middleware limits the number of in-flight requests, and adjusts the limit to the latency of the service,
+// which increases as the service gets saturated (gradient algorithm).
+type AdaptiveConcurrency struct {
+	// InitialLimit is the number of in-flight requests allowed until the latency of the service is measured.
+	// It defaults to 20.
+	InitialLimit int64 `json:"initialLimit,omitempty" toml:"initialLimit,omitempty" yaml:"initialLimit,omitempty"`
+	// MinLimit is the lowest number of in-flight requests the limit can decrease to. It defaults to 1.
+	MinLimit int64 `json:"minLimit,omitempty" toml:"minLimit,omitempty" yaml:"minLimit,omitempty"`
+	// MaxLimit is the highest number of in-flight requests the limit can increase to. It defaults to 1000.
+	MaxLimit int64 `json:"maxLimit,omitempty" toml:"maxLimit,omitempty" yaml:"maxLimit,omitempty"`
+	// Tolerance is the increase of the latency, in percent, tolerated before the limit decreases. It defaults to 50.
+	Tolerance int64 `json:"tolerance,omitempty" toml:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+	// Window is the minimum duration over which the latency is sampled before the limit is adjusted. It defaults to a second.
+	Window types.Duration `json:"window,omitempty" toml:"window,omitempty" yaml:"window,omitempty"`
+}
+
+// SetDefaults sets the default values on an AdaptiveConcurrency.
+func (a *AdaptiveConcurrency) SetDefaults() {
+	a.InitialLimit = 20
+	a.MinLimit = 1
+	a.MaxLimit = 1000
+	a.Tolerance = 50
+	a.Window = types.Duration(time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
 // AddPrefix holds the AddPrefix configuration.
 type AddPrefix struct {
 	Prefix string `json:"prefix,omitempty" toml:"prefix,omitempty" yaml:"prefix,omitempty"`
@@ -64,6 +107,28 @@ type AddPrefix struct {
 
 // +k8s:deepcopy-gen=true
 
+// Aggregate holds the aggregate middleware configuration.
+// This middleware fans the request out to several endpoints, and merges their JSON responses into the response of the service.
+type Aggregate struct {
+	Endpoints []AggregateEndpoint `json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	// ForwardHeaders are the headers of the request copied to the requests sent to the endpoints.
+	ForwardHeaders []string `json:"forwardHeaders,omitempty" toml:"forwardHeaders,omitempty" yaml:"forwardHeaders,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// AggregateEndpoint holds the configuration of an endpoint of the aggregate middleware.
+type AggregateEndpoint struct {
+	URL string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	// Key is the field of the merged response holding the response of the endpoint.
+	// When empty, the fields of the response of the endpoint are merged at the top level.
+	Key string `json:"key,omitempty" toml:"key,omitempty" yaml:"key,omitempty"`
+	// Optional defines whether the failures of the endpoint are ignored.
+	Optional bool `json:"optional,omitempty" toml:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Auth holds the authentication configuration (BASIC, DIGEST, users).
 type Auth struct {
 	Basic   *BasicAuth   `json:"basic,omitempty" toml:"basic,omitempty" yaml:"basic,omitempty" export:"true"`
@@ -105,6 +170,37 @@ type Chain struct {
 // CircuitBreaker holds the circuit breaker configuration.
 type CircuitBreaker struct {
 	Expression string `json:"expression,omitempty" toml:"expression,omitempty" yaml:"expression,omitempty"`
+
+	// CheckPeriod is the interval between two evaluations of the expression, while the circuit breaker is closed.
+	// It defaults to 100ms.
+	CheckPeriod types.Duration `json:"checkPeriod,omitempty" toml:"checkPeriod,omitempty" yaml:"checkPeriod,omitempty"`
+
+	// FallbackDuration is how long the circuit breaker stays open, before letting probe requests through.
+	// It defaults to 10s.
+	FallbackDuration types.Duration `json:"fallbackDuration,omitempty" toml:"fallbackDuration,omitempty" yaml:"fallbackDuration,omitempty"`
+
+	// ProbeRequests is the number of probe requests let through in the half-open state,
+	// which must all succeed for the circuit breaker to close. It defaults to 1.
+	ProbeRequests int `json:"probeRequests,omitempty" toml:"probeRequests,omitempty" yaml:"probeRequests,omitempty"`
+
+	// PerServer opens the circuit breaker of each server of the service independently,
+	// the requests for an isolated server being sent to the other servers.
+	PerServer bool `json:"perServer,omitempty" toml:"perServer,omitempty" yaml:"perServer,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ClientCertPolicy holds the client certificate policy middleware configuration.
+// This middleware forbids the requests whose client certificate does not comply with the policy.
+type ClientCertPolicy struct {
+	// AllowedIssuers are the common names or distinguished names of the allowed issuers.
+	AllowedIssuers []string `json:"allowedIssuers,omitempty" toml:"allowedIssuers,omitempty" yaml:"allowedIssuers,omitempty"`
+	// AllowedSANs are regular expressions, one of which must match a subject alternative name of the certificate.
+	AllowedSANs []string `json:"allowedSANs,omitempty" toml:"allowedSANs,omitempty" yaml:"allowedSANs,omitempty"`
+	// MaxAge is the maximum duration elapsed since the beginning of the validity of the client certificate.
+	MaxAge types.Duration `json:"maxAge,omitempty" toml:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+	// KeyUsages are the key usages and extended key usages the certificate must allow.
+	KeyUsages []string `json:"keyUsages,omitempty" toml:"keyUsages,omitempty" yaml:"keyUsages,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -112,6 +208,46 @@ type CircuitBreaker struct {
 // Compress holds the compress configuration.
 type Compress struct {
 	ExcludedContentTypes []string `json:"excludedContentTypes,omitempty" toml:"excludedContentTypes,omitempty" yaml:"excludedContentTypes,omitempty" export:"true"`
+	// IncludedContentTypes are the media types of the compressed responses, all by default.
+	// A type or subtype can end with a "*" wildcard.
+	IncludedContentTypes []string `json:"includedContentTypes,omitempty" toml:"includedContentTypes,omitempty" yaml:"includedContentTypes,omitempty" export:"true"`
+	// MinResponseBodyBytes is the minimum size of a compressed response body, 1400 bytes by default.
+	MinResponseBodyBytes int `json:"minResponseBodyBytes,omitempty" toml:"minResponseBodyBytes,omitempty" yaml:"minResponseBodyBytes,omitempty" export:"true"`
+	// MaxResponseBodyBytes is the maximum Content-Length of a compressed response, unlimited by default.
+	MaxResponseBodyBytes int64 `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
+	// Encodings are the enabled encodings (gzip, zstd), by order of preference, gzip only by default.
+	Encodings []string `json:"encodings,omitempty" toml:"encodings,omitempty" yaml:"encodings,omitempty" export:"true"`
+	// GzipLevel is the gzip compression level, from 1 (best speed) to 9 (best compression).
+	GzipLevel int `json:"gzipLevel,omitempty" toml:"gzipLevel,omitempty" yaml:"gzipLevel,omitempty" export:"true"`
+	// ZstdLevel is the Zstandard compression level, from 1 (best speed) to 22 (best compression).
+	ZstdLevel int `json:"zstdLevel,omitempty" toml:"zstdLevel,omitempty" yaml:"zstdLevel,omitempty" export:"true"`
+	// Dictionaries are the paths to the pre-shared Zstandard dictionaries used for the dcz content-encoding.
+	Dictionaries []string `json:"dictionaries,omitempty" toml:"dictionaries,omitempty" yaml:"dictionaries,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CORS holds the CORS middleware configuration.
+// This middleware answers the CORS preflight requests and sets the CORS headers of the responses,
+// according to the first policy matching the origin of the request.
+type CORS struct {
+	Policies []CORSPolicy `json:"policies,omitempty" toml:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CORSPolicy holds the CORS headers sent to a set of origins.
+type CORSPolicy struct {
+	// Origins are the origins the policy applies to: an exact origin, a pattern with wildcards such as https://*.example.com, or *.
+	Origins          []string `json:"origins,omitempty" toml:"origins,omitempty" yaml:"origins,omitempty"`
+	AllowMethods     []string `json:"allowMethods,omitempty" toml:"allowMethods,omitempty" yaml:"allowMethods,omitempty"`
+	AllowHeaders     []string `json:"allowHeaders,omitempty" toml:"allowHeaders,omitempty" yaml:"allowHeaders,omitempty"`
+	ExposeHeaders    []string `json:"exposeHeaders,omitempty" toml:"exposeHeaders,omitempty" yaml:"exposeHeaders,omitempty"`
+	AllowCredentials bool     `json:"allowCredentials,omitempty" toml:"allowCredentials,omitempty" yaml:"allowCredentials,omitempty"`
+	// MaxAge is the number of seconds the result of a preflight request can be cached.
+	MaxAge int64 `json:"maxAge,omitempty" toml:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+	// AllowPrivateNetwork answers the preflight requests of the public websites reaching a private network (Private Network Access).
+	AllowPrivateNetwork bool `json:"allowPrivateNetwork,omitempty" toml:"allowPrivateNetwork,omitempty" yaml:"allowPrivateNetwork,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -136,6 +272,77 @@ type ErrorPage struct {
 
 // +k8s:deepcopy-gen=true
 
+// ETag holds the ETag middleware configuration.
+// This middleware computes the ETag of the responses, and answers to the conditional requests on behalf of the services.
+type ETag struct {
+	// Weak defines whether the computed ETags are weak validators.
+	Weak bool `json:"weak,omitempty" toml:"weak,omitempty" yaml:"weak,omitempty" export:"true"`
+	// MaxBodySize is the maximum size in bytes of a response body for which an ETag is computed.
+	MaxBodySize int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Cache holds the HTTP response caching middleware configuration.
+// The cacheable responses of the services are stored, and served to the next requests while they are fresh.
+type Cache struct {
+	// TTL overrides the freshness lifetime given by the services to the cacheable responses.
+	TTL types.Duration `json:"ttl,omitempty" toml:"ttl,omitempty" yaml:"ttl,omitempty" export:"true"`
+	// DefaultTTL is the freshness lifetime of the cacheable responses without explicit expiration time.
+	// Such responses are not stored when it is not set.
+	DefaultTTL types.Duration `json:"defaultTTL,omitempty" toml:"defaultTTL,omitempty" yaml:"defaultTTL,omitempty" export:"true"`
+	// MaxEntrySize is the maximum size in bytes of a stored response body.
+	MaxEntrySize int64        `json:"maxEntrySize,omitempty" toml:"maxEntrySize,omitempty" yaml:"maxEntrySize,omitempty" export:"true"`
+	Memory       *CacheMemory `json:"memory,omitempty" toml:"memory,omitempty" yaml:"memory,omitempty" label:"allowEmpty" export:"true"`
+	Redis        *CacheRedis  `json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CacheMemory holds the configuration of the in-memory storage of a cache,
+// evicting the least recently used responses.
+type CacheMemory struct {
+	// MaxSize is the maximum size in bytes of the stored responses.
+	MaxSize int64 `json:"maxSize,omitempty" toml:"maxSize,omitempty" yaml:"maxSize,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CacheRedis holds the configuration of the Redis storage of a cache, which can be shared by several Traefik instances.
+type CacheRedis struct {
+	Address  string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Password string `json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	DB       int    `json:"db,omitempty" toml:"db,omitempty" yaml:"db,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RewriteBody holds the body rewrite middleware configuration.
+type RewriteBody struct {
+	Rewrites []BodyRewrite `json:"rewrites,omitempty" toml:"rewrites,omitempty" yaml:"rewrites,omitempty"`
+	// Request also rewrites the request bodies.
+	Request bool `json:"request,omitempty" toml:"request,omitempty" yaml:"request,omitempty" export:"true"`
+	// ContentTypes are the media types of the rewritten bodies, "text/*" by default.
+	// A type or subtype can end with a "*" wildcard.
+	ContentTypes []string `json:"contentTypes,omitempty" toml:"contentTypes,omitempty" yaml:"contentTypes,omitempty" export:"true"`
+	// MaxBodySize is the maximum size in bytes of a rewritten body. The larger bodies are forwarded as is.
+	MaxBodySize int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// BodyRewrite holds a substitution of the body rewrite middleware.
+// Either Regex or Value must be set.
+type BodyRewrite struct {
+	// Regex is a regular expression whose matches are replaced, the replacement can use its capture groups (e.g. $1).
+	Regex string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty"`
+	// Value is a literal string whose occurrences are replaced.
+	Value       string `json:"value,omitempty" toml:"value,omitempty" yaml:"value,omitempty"`
+	Replacement string `json:"replacement,omitempty" toml:"replacement,omitempty" yaml:"replacement,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // ForwardAuth holds the http forward authentication configuration.
 type ForwardAuth struct {
 	Address             string     `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
@@ -235,6 +442,26 @@ func (h *Headers) HasSecureHeadersDefined() bool {
 
 // +k8s:deepcopy-gen=true
 
+// Introspection holds the OAuth2 token introspection (RFC 7662) configuration.
+type Introspection struct {
+	// Address is the URL of the introspection endpoint.
+	Address      string     `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	TLS          *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
+	ClientID     string     `json:"clientId,omitempty" toml:"clientId,omitempty" yaml:"clientId,omitempty"`
+	ClientSecret string     `json:"clientSecret,omitempty" toml:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+	// CacheDuration is the maximum duration for which the result of an introspection is cached.
+	CacheDuration types.Duration `json:"cacheDuration,omitempty" toml:"cacheDuration,omitempty" yaml:"cacheDuration,omitempty"`
+	// HeaderFields maps the names of the headers forwarded to the service to the fields of the introspection response.
+	HeaderFields map[string]string `json:"headerFields,omitempty" toml:"headerFields,omitempty" yaml:"headerFields,omitempty"`
+}
+
+// SetDefaults Default values for an Introspection.
+func (i *Introspection) SetDefaults() {
+	i.CacheDuration = types.Duration(time.Minute)
+}
+
+// +k8s:deepcopy-gen=true
+
 // IPStrategy holds the ip strategy configuration.
 type IPStrategy struct {
 	Depth       int      `json:"depth,omitempty" toml:"depth,omitempty" yaml:"depth,omitempty" export:"true"`
@@ -288,10 +515,63 @@ type InFlightReq struct {
 
 // +k8s:deepcopy-gen=true
 
+// JWT holds the JSON Web Token authentication configuration.
+type JWT struct {
+	// JWKSURL is the URL of the JSON Web Key Set holding the public keys which sign the tokens.
+	JWKSURL string     `json:"jwksUrl,omitempty" toml:"jwksUrl,omitempty" yaml:"jwksUrl,omitempty"`
+	TLS     *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
+	// KeysRefreshInterval is the duration after which the key set is fetched again, to follow the rotations of the keys.
+	KeysRefreshInterval types.Duration `json:"keysRefreshInterval,omitempty" toml:"keysRefreshInterval,omitempty" yaml:"keysRefreshInterval,omitempty"`
+	Issuer              string         `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// Audiences are the accepted audiences, one of which must be an audience of the tokens.
+	Audiences []string `json:"audiences,omitempty" toml:"audiences,omitempty" yaml:"audiences,omitempty"`
+	// RequiredClaims maps the claims to the values they must have, or contain for the array claims.
+	RequiredClaims map[string]string `json:"requiredClaims,omitempty" toml:"requiredClaims,omitempty" yaml:"requiredClaims,omitempty"`
+	// HeaderClaims maps the names of the headers forwarded to the service to the claims of the tokens.
+	HeaderClaims map[string]string `json:"headerClaims,omitempty" toml:"headerClaims,omitempty" yaml:"headerClaims,omitempty"`
+}
+
+// SetDefaults Default values for a JWT.
+func (j *JWT) SetDefaults() {
+	j.KeysRefreshInterval = types.Duration(time.Hour)
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDC holds the OpenID Connect authentication configuration.
+type OIDC struct {
+	// Issuer is the URL of the OpenID Connect provider, from which its endpoints are discovered.
+	Issuer       string     `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty"`
+	TLS          *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
+	ClientID     string     `json:"clientId,omitempty" toml:"clientId,omitempty" yaml:"clientId,omitempty"`
+	ClientSecret string     `json:"clientSecret,omitempty" toml:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+	Scopes       []string   `json:"scopes,omitempty" toml:"scopes,omitempty" yaml:"scopes,omitempty"`
+	// RedirectPath is the path, handled by the middleware, to which the provider redirects the users once authenticated.
+	RedirectPath string `json:"redirectPath,omitempty" toml:"redirectPath,omitempty" yaml:"redirectPath,omitempty"`
+	// SessionSecret is the secret from which the key encrypting the session cookie is derived.
+	SessionSecret   string         `json:"sessionSecret,omitempty" toml:"sessionSecret,omitempty" yaml:"sessionSecret,omitempty"`
+	SessionCookie   string         `json:"sessionCookie,omitempty" toml:"sessionCookie,omitempty" yaml:"sessionCookie,omitempty"`
+	SessionDuration types.Duration `json:"sessionDuration,omitempty" toml:"sessionDuration,omitempty" yaml:"sessionDuration,omitempty"`
+	// HeaderClaims maps the names of the headers forwarded to the service to the claims of the ID token.
+	HeaderClaims       map[string]string `json:"headerClaims,omitempty" toml:"headerClaims,omitempty" yaml:"headerClaims,omitempty"`
+	ForwardAccessToken bool              `json:"forwardAccessToken,omitempty" toml:"forwardAccessToken,omitempty" yaml:"forwardAccessToken,omitempty"`
+}
+
+// SetDefaults Default values for an OIDC.
+func (o *OIDC) SetDefaults() {
+	o.Scopes = []string{"openid", "profile", "email"}
+	o.RedirectPath = "/oauth2/callback"
+	o.SessionCookie = "_traefik_oidc"
+	o.SessionDuration = types.Duration(24 * time.Hour)
+}
+
+// +k8s:deepcopy-gen=true
+
 // PassTLSClientCert holds the TLS client cert headers configuration.
 type PassTLSClientCert struct {
 	PEM  bool                      `json:"pem,omitempty" toml:"pem,omitempty" yaml:"pem,omitempty"`
 	Info *TLSClientCertificateInfo `json:"info,omitempty" toml:"info,omitempty" yaml:"info,omitempty"`
+	JWT  *TLSClientCertificateJWT  `json:"jwt,omitempty" toml:"jwt,omitempty" yaml:"jwt,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -300,9 +580,11 @@ type PassTLSClientCert struct {
 // If none are set, the default is to use the request's remote address field.
 // All fields are mutually exclusive.
 type SourceCriterion struct {
-	IPStrategy        *IPStrategy `json:"ipStrategy" toml:"ipStrategy, omitempty"`
-	RequestHeaderName string      `json:"requestHeaderName,omitempty" toml:"requestHeaderName,omitempty" yaml:"requestHeaderName,omitempty"`
-	RequestHost       bool        `json:"requestHost,omitempty" toml:"requestHost,omitempty" yaml:"requestHost,omitempty"`
+	IPStrategy          *IPStrategy `json:"ipStrategy" toml:"ipStrategy, omitempty"`
+	RequestHeaderName   string      `json:"requestHeaderName,omitempty" toml:"requestHeaderName,omitempty" yaml:"requestHeaderName,omitempty"`
+	RequestHost         bool        `json:"requestHost,omitempty" toml:"requestHost,omitempty" yaml:"requestHost,omitempty"`
+	RequestJWTClaim     string      `json:"requestJWTClaim,omitempty" toml:"requestJWTClaim,omitempty" yaml:"requestJWTClaim,omitempty"`
+	RequestClientCertCN bool        `json:"requestClientCertCN,omitempty" toml:"requestClientCertCN,omitempty" yaml:"requestClientCertCN,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -324,6 +606,10 @@ type RateLimit struct {
 	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty"`
 
 	SourceCriterion *SourceCriterion `json:"sourceCriterion,omitempty" toml:"sourceCriterion,omitempty" yaml:"sourceCriterion,omitempty"`
+
+	// Cost defines the number of tokens charged for each request.
+	// By default, each request costs one token.
+	Cost *RateLimitCost `json:"cost,omitempty" toml:"cost,omitempty" yaml:"cost,omitempty"`
 }
 
 // SetDefaults sets the default values on a RateLimit.
@@ -334,6 +620,29 @@ func (r *RateLimit) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// RateLimitCost holds the configuration of the cost charged for each request by the rate limiter.
+type RateLimitCost struct {
+	// Rules are evaluated in order, and the first matching rule defines the cost of the request.
+	Rules []RateLimitCostRule `json:"rules,omitempty" toml:"rules,omitempty" yaml:"rules,omitempty"`
+	// ResponseHeader is the name of the response header holding an additional cost,
+	// reported by the service and charged once the response is sent.
+	ResponseHeader string `json:"responseHeader,omitempty" toml:"responseHeader,omitempty" yaml:"responseHeader,omitempty"`
+	// RemainingHeader is the name of the response header holding the remaining budget of the source,
+	// reported by the service, to which the bucket of the source is lowered.
+	RemainingHeader string `json:"remainingHeader,omitempty" toml:"remainingHeader,omitempty" yaml:"remainingHeader,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RateLimitCostRule holds the cost of the requests matching the methods and the path pattern.
+type RateLimitCostRule struct {
+	Methods   []string `json:"methods,omitempty" toml:"methods,omitempty" yaml:"methods,omitempty"`
+	PathRegex string   `json:"pathRegex,omitempty" toml:"pathRegex,omitempty" yaml:"pathRegex,omitempty"`
+	Cost      int64    `json:"cost,omitempty" toml:"cost,omitempty" yaml:"cost,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // RedirectRegex holds the redirection configuration.
 type RedirectRegex struct {
 	Regex       string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty"`
@@ -367,9 +676,72 @@ type ReplacePathRegex struct {
 
 // +k8s:deepcopy-gen=true
 
+// ResourceHints holds the resource hints middleware configuration.
+// This middleware adds Link headers to the responses, so that the browsers preload resources and preconnect to origins early.
+type ResourceHints struct {
+	Preload []PreloadHint `json:"preload,omitempty" toml:"preload,omitempty" yaml:"preload,omitempty"`
+	// Preconnect are the origins the browsers open a connection to, such as https://cdn.example.com.
+	Preconnect []string `json:"preconnect,omitempty" toml:"preconnect,omitempty" yaml:"preconnect,omitempty"`
+	// LearnFromHTML collects the stylesheets and scripts of the head of the HTML responses,
+	// and adds them as preload hints to the subsequent responses for the same path.
+	LearnFromHTML bool `json:"learnFromHTML,omitempty" toml:"learnFromHTML,omitempty" yaml:"learnFromHTML,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// PreloadHint holds a resource the browsers preload.
+type PreloadHint struct {
+	URL string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	// As is the type of the resource, such as script, style, font or image.
+	As string `json:"as,omitempty" toml:"as,omitempty" yaml:"as,omitempty"`
+	// CrossOrigin fetches the resource in CORS mode, as required for the fonts.
+	CrossOrigin bool `json:"crossOrigin,omitempty" toml:"crossOrigin,omitempty" yaml:"crossOrigin,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Retry holds the retry configuration.
 type Retry struct {
 	Attempts int `json:"attempts,omitempty" toml:"attempts,omitempty" yaml:"attempts,omitempty" export:"true"`
+
+	// InitialInterval is the delay before the first retry, doubled for each following retry, with a random jitter.
+	// It defaults to 0, which means the retries are not delayed.
+	InitialInterval types.Duration `json:"initialInterval,omitempty" toml:"initialInterval,omitempty" yaml:"initialInterval,omitempty" export:"true"`
+
+	// MaxInterval is the maximum delay before a retry, including the delay asked by the Retry-After header of a response.
+	// It defaults to 10s.
+	MaxInterval types.Duration `json:"maxInterval,omitempty" toml:"maxInterval,omitempty" yaml:"maxInterval,omitempty" export:"true"`
+
+	// Budget limits the share of the requests to the service which are retries.
+	Budget *RetryBudget `json:"budget,omitempty" toml:"budget,omitempty" yaml:"budget,omitempty" export:"true"`
+
+	// RetryOn defines the failures, on top of the unreachable servers, which are retried.
+	RetryOn *RetryConditions `json:"retryOn,omitempty" toml:"retryOn,omitempty" yaml:"retryOn,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RetryBudget holds the retry budget configuration.
+type RetryBudget struct {
+	// Percent is the maximum percentage of retries among the requests to the service, over the last 10 seconds.
+	Percent int `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+
+	// MinRetriesPerSecond is the number of retries per second allowed whatever the percentage, so that the services with a low traffic can be retried.
+	MinRetriesPerSecond int `json:"minRetriesPerSecond,omitempty" toml:"minRetriesPerSecond,omitempty" yaml:"minRetriesPerSecond,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RetryConditions holds the conditions under which a request is retried.
+type RetryConditions struct {
+	// StatusCodes are the status codes of the server responses to retry, e.g. "502-504".
+	StatusCodes []string `json:"statusCodes,omitempty" toml:"statusCodes,omitempty" yaml:"statusCodes,omitempty" export:"true"`
+
+	// ConnectionReset retries the requests whose connection was reset or closed by the server before it answered.
+	ConnectionReset bool `json:"connectionReset,omitempty" toml:"connectionReset,omitempty" yaml:"connectionReset,omitempty" export:"true"`
+
+	// IdempotentMethodsOnly restricts the retries to the requests with an idempotent method.
+	IdempotentMethodsOnly bool `json:"idempotentMethodsOnly,omitempty" toml:"idempotentMethodsOnly,omitempty" yaml:"idempotentMethodsOnly,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -406,6 +778,27 @@ type TLSClientCertificateInfo struct {
 
 // +k8s:deepcopy-gen=true
 
+// TLSClientCertificateJWT holds the configuration of the signed JWT conveying the identity of a verified client certificate.
+type TLSClientCertificateJWT struct {
+	// SigningKeyFile is the path to the PEM encoded RSA or ECDSA private key signing the JWT.
+	SigningKeyFile string `json:"signingKeyFile,omitempty" toml:"signingKeyFile,omitempty" yaml:"signingKeyFile,omitempty"`
+	// HeaderName is the name of the header holding the JWT.
+	HeaderName string         `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty"`
+	Issuer     string         `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty"`
+	Audience   string         `json:"audience,omitempty" toml:"audience,omitempty" yaml:"audience,omitempty"`
+	Expiration types.Duration `json:"expiration,omitempty" toml:"expiration,omitempty" yaml:"expiration,omitempty"`
+	// Claims maps the names of additional claims to fields of the client certificate.
+	Claims map[string]string `json:"claims,omitempty" toml:"claims,omitempty" yaml:"claims,omitempty"`
+}
+
+// SetDefaults Default values for a TLSClientCertificateJWT.
+func (t *TLSClientCertificateJWT) SetDefaults() {
+	t.HeaderName = "X-Forwarded-Tls-Client-Cert-Jwt"
+	t.Expiration = types.Duration(time.Minute)
+}
+
+// +k8s:deepcopy-gen=true
+
 // TLSCLientCertificateDNInfo holds the client TLS certificate distinguished name info configuration
 // cf https://tools.ietf.org/html/rfc3739
 type TLSCLientCertificateDNInfo struct {
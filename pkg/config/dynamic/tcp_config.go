@@ -2,6 +2,7 @@ package dynamic
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/types"
 )
@@ -20,6 +21,8 @@ type TCPConfiguration struct {
 type TCPService struct {
 	LoadBalancer *TCPServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty"`
 	Weighted     *TCPWeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-"`
+	Mirroring    *TCPMirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-"`
+	Failover     *TCPFailover            `json:"failover,omitempty" toml:"failover,omitempty" yaml:"failover,omitempty" label:"-"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -45,12 +48,43 @@ func (w *TCPWRRService) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// TCPFailover is a TCP service forwarding the connections to a main service,
+// and to a fallback service while the main one is down.
+type TCPFailover struct {
+	Service  string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
+	Fallback string `json:"fallback,omitempty" toml:"fallback,omitempty" yaml:"fallback,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPMirroring is a TCP service forwarding the connections to a main service,
+// and duplicating the bytes sent by the clients to mirror services.
+type TCPMirroring struct {
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
+	// BufferSize is the maximum number of bytes, waiting to be sent to a mirror, above which the mirrored connection is closed.
+	BufferSize int                `json:"bufferSize,omitempty" toml:"bufferSize,omitempty" yaml:"bufferSize,omitempty"`
+	Mirrors    []TCPMirrorService `json:"mirrors,omitempty" toml:"mirrors,omitempty" yaml:"mirrors,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPMirrorService is a reference to a tcp service receiving a percentage of the mirrored connections.
+type TCPMirrorService struct {
+	Name    string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty"`
+	Percent int    `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPRouter holds the router configuration.
 type TCPRouter struct {
 	EntryPoints []string            `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty"`
 	Service     string              `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty"`
 	Rule        string              `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
+	Priority    int                 `json:"priority,omitempty" toml:"priority,omitempty" yaml:"priority,omitempty"`
 	TLS         *RouterTCPTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty"`
+	// ProxyProtocol holds the custom TLVs added to the PROXY protocol headers sent to the servers of the service.
+	ProxyProtocol *RouterTCPProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"-"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -61,6 +95,15 @@ type RouterTCPTLSConfig struct {
 	Options      string         `json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty"`
 	CertResolver string         `json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty"`
 	Domains      []types.Domain `json:"domains,omitempty" toml:"domains,omitempty" yaml:"domains,omitempty"`
+	Certificate  string         `json:"certificate,omitempty" toml:"certificate,omitempty" yaml:"certificate,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouterTCPProxyProtocol holds the PROXY protocol options of a router.
+type RouterTCPProxyProtocol struct {
+	// TLVs are custom TLVs added to the version 2 headers, after the TLS ones.
+	TLVs []TCPProxyProtocolTLV `json:"tlvs,omitempty" toml:"tlvs,omitempty" yaml:"tlvs,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -72,8 +115,21 @@ type TCPServersLoadBalancer struct {
 	// connection, to close the reading capability as well, hence fully terminating the
 	// connection. It is a duration in milliseconds, defaulting to 100. A negative value
 	// means an infinite deadline (i.e. the reading capability is never closed).
-	TerminationDelay *int        `json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty"`
-	Servers          []TCPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
+	TerminationDelay *int `json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty"`
+	// Strategy is the balancing strategy of the connections: wrr (weighted round robin, the default),
+	// leastconn (the server with the fewest active connections relative to its weight),
+	// or sourceip (the server owning the hash of the client IP on a consistent-hash ring).
+	Strategy string `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty"`
+	// SourceIP holds the options of the sourceip strategy.
+	SourceIP *TCPSourceIP `json:"sourceIP,omitempty" toml:"sourceIP,omitempty" yaml:"sourceIP,omitempty" label:"allowEmpty"`
+	// MaxConnections is the maximum number of concurrent connections of the service, 0 meaning no limit.
+	// Once it is reached, the new connections are closed.
+	MaxConnections int             `json:"maxConnections,omitempty" toml:"maxConnections,omitempty" yaml:"maxConnections,omitempty"`
+	Servers        []TCPServer     `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
+	HealthCheck    *TCPHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
+	SlowStart      *TCPSlowStart   `json:"slowStart,omitempty" toml:"slowStart,omitempty" yaml:"slowStart,omitempty"`
+	// ProxyProtocol sends a PROXY protocol header to the servers, before the data of the connections.
+	ProxyProtocol *TCPProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty"`
 }
 
 // SetDefaults Default values for a TCPServersLoadBalancer.
@@ -101,8 +157,73 @@ func (l *TCPServersLoadBalancer) Mergeable(loadBalancer *TCPServersLoadBalancer)
 
 // +k8s:deepcopy-gen=true
 
+// TCPSourceIP holds the options of the sourceip balancing strategy.
+type TCPSourceIP struct {
+	// IPv4Prefix and IPv6Prefix are the prefix lengths the client IPs are masked with before they are hashed,
+	// so that the clients of a subnet share their server (e.g. 24 and 64). 0 means the whole address.
+	IPv4Prefix int `json:"ipv4Prefix,omitempty" toml:"ipv4Prefix,omitempty" yaml:"ipv4Prefix,omitempty"`
+	IPv6Prefix int `json:"ipv6Prefix,omitempty" toml:"ipv6Prefix,omitempty" yaml:"ipv6Prefix,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPProxyProtocol holds the PROXY protocol header sent to the servers of a TCP service.
+type TCPProxyProtocol struct {
+	// Version is the version of the PROXY protocol, 1 or 2, defaulting to 2.
+	Version int `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPProxyProtocolTLV is a custom TLV of the PROXY protocol version 2 headers, set on a router.
+type TCPProxyProtocolTLV struct {
+	// Type is the type of the TLV, in the range reserved to the custom TLVs, from 0xE0 to 0xEF.
+	Type int `json:"type,omitempty" toml:"type,omitempty" yaml:"type,omitempty"`
+	// Value is the value of the TLV.
+	Value string `json:"value,omitempty" toml:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPSlowStart ramps up linearly the weight of the servers coming back up after a failed health check.
+type TCPSlowStart struct {
+	// Duration is how long the weight of a server coming back up takes to reach its full value.
+	Duration types.Duration `json:"duration,omitempty" toml:"duration,omitempty" yaml:"duration,omitempty"`
+	// InitialPercent is the percentage of its full weight a server coming back up starts with.
+	InitialPercent int `json:"initialPercent,omitempty" toml:"initialPercent,omitempty" yaml:"initialPercent,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPHealthCheck holds the active health check configuration of the servers of a TCP service.
+// A server is healthy when a connection to it can be opened within the timeout
+// and, if Send or Expect are set, the exchange of the payloads succeeds.
+type TCPHealthCheck struct {
+	Interval types.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty"`
+	Timeout  types.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Send is the payload written to the server once connected.
+	Send string `json:"send,omitempty" toml:"send,omitempty" yaml:"send,omitempty"`
+	// Expect is the payload the server must answer with, as a prefix of its response.
+	Expect string `json:"expect,omitempty" toml:"expect,omitempty" yaml:"expect,omitempty"`
+	// TLS enables a TLS handshake with the server, before the payloads are exchanged.
+	TLS bool `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// SetDefaults Default values for a TCPHealthCheck.
+func (h *TCPHealthCheck) SetDefaults() {
+	h.Interval = types.Duration(30 * time.Second)
+	h.Timeout = types.Duration(5 * time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPServer holds a TCP Server configuration.
 type TCPServer struct {
 	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty" label:"-"`
 	Port    string `toml:"-" json:"-" yaml:"-"`
+	// Weight is the weight of the server in the balancing, defaulting to 1.
+	Weight int `json:"weight,omitempty" toml:"weight,omitempty" yaml:"weight,omitempty"`
+	// MaxConnections is the maximum number of concurrent connections forwarded to the server, 0 meaning no limit.
+	// Once it is reached, the server is skipped by the balancing.
+	MaxConnections int `json:"maxConnections,omitempty" toml:"maxConnections,omitempty" yaml:"maxConnections,omitempty"`
 }
@@ -2,6 +2,9 @@ package dynamic
 
 import (
 	"reflect"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/types"
 )
 
 // +k8s:deepcopy-gen=true
@@ -10,6 +13,8 @@ import (
 type UDPConfiguration struct {
 	Routers  map[string]*UDPRouter  `json:"routers,omitempty" toml:"routers,omitempty" yaml:"routers,omitempty"`
 	Services map[string]*UDPService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty"`
+	// ServersTransports are the named options of the sockets opened to the servers, which the load-balancers can refer to.
+	ServersTransports map[string]*UDPServersTransport `json:"serversTransports,omitempty" toml:"serversTransports,omitempty" yaml:"serversTransports,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -17,7 +22,7 @@ type UDPConfiguration struct {
 // UDPService defines the configuration for a UDP service. All fields are mutually exclusive.
 type UDPService struct {
 	LoadBalancer *UDPServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty"`
-	Weighted     *UDPWeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-"`
+	Weighted     *UDPWeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -53,7 +58,15 @@ type UDPRouter struct {
 
 // UDPServersLoadBalancer defines the configuration for a load-balancer of UDP servers.
 type UDPServersLoadBalancer struct {
-	Servers []UDPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
+	// Strategy is the balancing strategy of the sessions: wrr (weighted round robin, the default),
+	// or sourceip (the server owning the hash of the client address on a consistent-hash ring).
+	Strategy string `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty"`
+	// SourceIP holds the options of the sourceip strategy.
+	SourceIP    *UDPSourceIP    `json:"sourceIP,omitempty" toml:"sourceIP,omitempty" yaml:"sourceIP,omitempty" label:"allowEmpty"`
+	Servers     []UDPServer     `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server"`
+	HealthCheck *UDPHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
+	// ServersTransport is the name of the serversTransport used to reach the servers.
+	ServersTransport string `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty"`
 }
 
 // Mergeable reports whether the given load-balancer can be merged with the receiver.
@@ -75,8 +88,58 @@ func (l *UDPServersLoadBalancer) Mergeable(loadBalancer *UDPServersLoadBalancer)
 
 // +k8s:deepcopy-gen=true
 
+// UDPServersTransport holds the options of the sockets opened to the servers of the UDP services.
+type UDPServersTransport struct {
+	// DialTimeout is the maximum duration to set up a socket to a server, which is the resolution of its address.
+	DialTimeout types.Duration `json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty"`
+	// SourceAddress is the local IP the datagrams are sent from.
+	SourceAddress string `json:"sourceAddress,omitempty" toml:"sourceAddress,omitempty" yaml:"sourceAddress,omitempty"`
+	// ReadBufferSize and WriteBufferSize are the sizes, in bytes, of the receive and send buffers of the sockets.
+	// 0 means the system default.
+	ReadBufferSize  int `json:"readBufferSize,omitempty" toml:"readBufferSize,omitempty" yaml:"readBufferSize,omitempty"`
+	WriteBufferSize int `json:"writeBufferSize,omitempty" toml:"writeBufferSize,omitempty" yaml:"writeBufferSize,omitempty"`
+	// DSCP is the Differentiated Services Code Point (0 to 63) the datagrams sent to the servers are marked with.
+	DSCP int `json:"dscp,omitempty" toml:"dscp,omitempty" yaml:"dscp,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// UDPSourceIP holds the options of the sourceip balancing strategy.
+type UDPSourceIP struct {
+	// IPv4Prefix and IPv6Prefix are the prefix lengths the client IPs are masked with before they are hashed,
+	// so that the clients of a subnet share their server (e.g. 24 and 64). 0 means the whole address.
+	IPv4Prefix int `json:"ipv4Prefix,omitempty" toml:"ipv4Prefix,omitempty" yaml:"ipv4Prefix,omitempty"`
+	IPv6Prefix int `json:"ipv6Prefix,omitempty" toml:"ipv6Prefix,omitempty" yaml:"ipv6Prefix,omitempty"`
+	// Port makes the client port part of the hash, so that each client socket, rather than each client IP, sticks to its server.
+	Port bool `json:"port,omitempty" toml:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// UDPHealthCheck holds the active health check configuration of the servers of a UDP service.
+// Without Expect, a server is healthy as long as its port is not reported unreachable,
+// otherwise it must answer the Send payload with the Expect one within the timeout.
+type UDPHealthCheck struct {
+	Interval types.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty"`
+	Timeout  types.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Send is the payload of the datagram sent to the server.
+	Send string `json:"send,omitempty" toml:"send,omitempty" yaml:"send,omitempty"`
+	// Expect is the payload the server must answer with, as a prefix of its response.
+	Expect string `json:"expect,omitempty" toml:"expect,omitempty" yaml:"expect,omitempty"`
+}
+
+// SetDefaults Default values for a UDPHealthCheck.
+func (h *UDPHealthCheck) SetDefaults() {
+	h.Interval = types.Duration(30 * time.Second)
+	h.Timeout = types.Duration(5 * time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
 // UDPServer defines a UDP server configuration.
 type UDPServer struct {
 	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty" label:"-"`
 	Port    string `toml:"-" json:"-" yaml:"-"`
+	// Weight is the weight of the server in the balancing, defaulting to 1.
+	Weight int `json:"weight,omitempty" toml:"weight,omitempty" yaml:"weight,omitempty"`
 }
@@ -1095,26 +1095,37 @@ func TestEncodeConfiguration(t *testing.T) {
 
 	expected := map[string]string{
 		"traefik.HTTP.Middlewares.Middleware0.AddPrefix.Prefix":                                    "foobar",
+		"traefik.HTTP.Middlewares.Middleware0.Optional":                                            "false",
 		"traefik.HTTP.Middlewares.Middleware1.BasicAuth.HeaderField":                               "foobar",
 		"traefik.HTTP.Middlewares.Middleware1.BasicAuth.Realm":                                     "foobar",
 		"traefik.HTTP.Middlewares.Middleware1.BasicAuth.RemoveHeader":                              "true",
 		"traefik.HTTP.Middlewares.Middleware1.BasicAuth.Users":                                     "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware1.BasicAuth.UsersFile":                                 "foobar",
+		"traefik.HTTP.Middlewares.Middleware1.Optional":                                            "false",
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.MaxRequestBodyBytes":                       "42",
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.MaxResponseBodyBytes":                      "42",
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.MemRequestBodyBytes":                       "42",
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.MemResponseBodyBytes":                      "42",
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.RetryExpression":                           "foobar",
+		"traefik.HTTP.Middlewares.Middleware2.Optional":                                            "false",
 		"traefik.HTTP.Middlewares.Middleware3.Chain.Middlewares":                                   "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware3.Optional":                                            "false",
+		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.CheckPeriod":                          "0",
 		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.Expression":                           "foobar",
+		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.FallbackDuration":                     "0",
+		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.PerServer":                            "false",
+		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.ProbeRequests":                        "0",
+		"traefik.HTTP.Middlewares.Middleware4.Optional":                                            "false",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.HeaderField":                              "foobar",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.Realm":                                    "foobar",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.RemoveHeader":                             "true",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.Users":                                    "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.UsersFile":                                "foobar",
+		"traefik.HTTP.Middlewares.Middleware5.Optional":                                            "false",
 		"traefik.HTTP.Middlewares.Middleware6.Errors.Query":                                        "foobar",
 		"traefik.HTTP.Middlewares.Middleware6.Errors.Service":                                      "foobar",
 		"traefik.HTTP.Middlewares.Middleware6.Errors.Status":                                       "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware6.Optional":                                            "false",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Address":                                 "foobar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.AuthResponseHeaders":                     "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.CA":                                  "foobar",
@@ -1123,6 +1134,7 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.InsecureSkipVerify":                  "true",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.Key":                                 "foobar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TrustForwardHeader":                      "true",
+		"traefik.HTTP.Middlewares.Middleware7.Optional":                                            "false",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.AccessControlAllowCredentials":               "true",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.AccessControlAllowHeaders":                   "X-foobar, X-fiibar",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.AccessControlAllowMethods":                   "GET, PUT",
@@ -1157,14 +1169,18 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware8.Headers.STSIncludeSubdomains":                        "true",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.STSPreload":                                  "true",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.STSSeconds":                                  "42",
+		"traefik.HTTP.Middlewares.Middleware8.Optional":                                            "false",
 		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.IPStrategy.Depth":                        "42",
 		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.IPStrategy.ExcludedIPs":                  "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.SourceRange":                             "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware9.Optional":                                            "false",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.Amount":                                 "42",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.IPStrategy.Depth":       "42",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.IPStrategy.ExcludedIPs": "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.RequestHeaderName":      "foobar",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.RequestHost":            "true",
+		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.RequestClientCertCN":    "false",
+		"traefik.HTTP.Middlewares.Middleware10.Optional":                                           "false",
 		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.Info.NotAfter":                    "true",
 		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.Info.NotBefore":                   "true",
 		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.Info.Sans":                        "true",
@@ -1184,39 +1200,57 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.Info.Issuer.SerialNumber":         "true",
 		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.Info.Issuer.DomainComponent":      "true",
 		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.PEM":                              "true",
+		"traefik.HTTP.Middlewares.Middleware11.Optional":                                           "false",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Average":                                  "42",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Period":                                   "1000000000",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.Burst":                                    "42",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.RequestHeaderName":        "foobar",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.RequestHost":              "true",
+		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.RequestClientCertCN":      "false",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.IPStrategy.Depth":         "42",
 		"traefik.HTTP.Middlewares.Middleware12.RateLimit.SourceCriterion.IPStrategy.ExcludedIPs":   "foobar, foobar",
+		"traefik.HTTP.Middlewares.Middleware12.Optional":                                           "false",
 		"traefik.HTTP.Middlewares.Middleware13.RedirectRegex.Regex":                                "foobar",
 		"traefik.HTTP.Middlewares.Middleware13.RedirectRegex.Replacement":                          "foobar",
 		"traefik.HTTP.Middlewares.Middleware13.RedirectRegex.Permanent":                            "true",
+		"traefik.HTTP.Middlewares.Middleware13.Optional":                                           "false",
 		"traefik.HTTP.Middlewares.Middleware13b.RedirectScheme.Scheme":                             "https",
 		"traefik.HTTP.Middlewares.Middleware13b.RedirectScheme.Port":                               "80",
 		"traefik.HTTP.Middlewares.Middleware13b.RedirectScheme.Permanent":                          "true",
+		"traefik.HTTP.Middlewares.Middleware13b.Optional":                                          "false",
 		"traefik.HTTP.Middlewares.Middleware14.ReplacePath.Path":                                   "foobar",
+		"traefik.HTTP.Middlewares.Middleware14.Optional":                                           "false",
 		"traefik.HTTP.Middlewares.Middleware15.ReplacePathRegex.Regex":                             "foobar",
 		"traefik.HTTP.Middlewares.Middleware15.ReplacePathRegex.Replacement":                       "foobar",
+		"traefik.HTTP.Middlewares.Middleware15.Optional":                                           "false",
 		"traefik.HTTP.Middlewares.Middleware16.Retry.Attempts":                                     "42",
+		"traefik.HTTP.Middlewares.Middleware16.Retry.InitialInterval":                              "0",
+		"traefik.HTTP.Middlewares.Middleware16.Retry.MaxInterval":                                  "0",
+		"traefik.HTTP.Middlewares.Middleware16.Optional":                                           "false",
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.Prefixes":                               "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.ForceSlash":                             "true",
+		"traefik.HTTP.Middlewares.Middleware17.Optional":                                           "false",
 		"traefik.HTTP.Middlewares.Middleware18.StripPrefixRegex.Regex":                             "foobar, fiibar",
-		"traefik.HTTP.Middlewares.Middleware19.Compress":                                           "true",
+		"traefik.HTTP.Middlewares.Middleware18.Optional":                                           "false",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.GzipLevel":                                 "0",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.MaxResponseBodyBytes":                      "0",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.MinResponseBodyBytes":                      "0",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.ZstdLevel":                                 "0",
+		"traefik.HTTP.Middlewares.Middleware19.Optional":                                           "false",
 
-		"traefik.HTTP.Routers.Router0.EntryPoints": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router0.Middlewares": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router0.Priority":    "42",
-		"traefik.HTTP.Routers.Router0.Rule":        "foobar",
-		"traefik.HTTP.Routers.Router0.Service":     "foobar",
-		"traefik.HTTP.Routers.Router0.TLS":         "true",
-		"traefik.HTTP.Routers.Router1.EntryPoints": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router1.Middlewares": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router1.Priority":    "42",
-		"traefik.HTTP.Routers.Router1.Rule":        "foobar",
-		"traefik.HTTP.Routers.Router1.Service":     "foobar",
+		"traefik.HTTP.Routers.Router0.DebugHeaders": "false",
+		"traefik.HTTP.Routers.Router0.EntryPoints":  "foobar, fiibar",
+		"traefik.HTTP.Routers.Router0.Middlewares":  "foobar, fiibar",
+		"traefik.HTTP.Routers.Router0.Priority":     "42",
+		"traefik.HTTP.Routers.Router0.Rule":         "foobar",
+		"traefik.HTTP.Routers.Router0.Service":      "foobar",
+		"traefik.HTTP.Routers.Router0.TLS":          "true",
+		"traefik.HTTP.Routers.Router1.DebugHeaders": "false",
+		"traefik.HTTP.Routers.Router1.EntryPoints":  "foobar, fiibar",
+		"traefik.HTTP.Routers.Router1.Middlewares":  "foobar, fiibar",
+		"traefik.HTTP.Routers.Router1.Priority":     "42",
+		"traefik.HTTP.Routers.Router1.Rule":         "foobar",
+		"traefik.HTTP.Routers.Router1.Service":      "foobar",
 
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name1":        "foobar",
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Hostname":             "foobar",
@@ -1246,27 +1280,37 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                    "foobar",
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name0":        "foobar",
 
-		"traefik.TCP.Routers.Router0.Rule":                            "foobar",
-		"traefik.TCP.Routers.Router0.EntryPoints":                     "foobar, fiibar",
-		"traefik.TCP.Routers.Router0.Service":                         "foobar",
-		"traefik.TCP.Routers.Router0.TLS.Passthrough":                 "false",
-		"traefik.TCP.Routers.Router0.TLS.Options":                     "foo",
-		"traefik.TCP.Routers.Router1.Rule":                            "foobar",
-		"traefik.TCP.Routers.Router1.EntryPoints":                     "foobar, fiibar",
-		"traefik.TCP.Routers.Router1.Service":                         "foobar",
-		"traefik.TCP.Routers.Router1.TLS.Passthrough":                 "false",
-		"traefik.TCP.Routers.Router1.TLS.Options":                     "foo",
-		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":      "42",
-		"traefik.TCP.Services.Service0.LoadBalancer.TerminationDelay": "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":      "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.TerminationDelay": "42",
+		"traefik.TCP.Routers.Router0.Rule":                                 "foobar",
+		"traefik.TCP.Routers.Router0.EntryPoints":                          "foobar, fiibar",
+		"traefik.TCP.Routers.Router0.Service":                              "foobar",
+		"traefik.TCP.Routers.Router0.Priority":                             "0",
+		"traefik.TCP.Routers.Router0.TLS.Passthrough":                      "false",
+		"traefik.TCP.Routers.Router0.TLS.Options":                          "foo",
+		"traefik.TCP.Routers.Router1.Rule":                                 "foobar",
+		"traefik.TCP.Routers.Router1.EntryPoints":                          "foobar, fiibar",
+		"traefik.TCP.Routers.Router1.Service":                              "foobar",
+		"traefik.TCP.Routers.Router1.Priority":                             "0",
+		"traefik.TCP.Routers.Router1.TLS.Passthrough":                      "false",
+		"traefik.TCP.Routers.Router1.TLS.Options":                          "foo",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":           "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.Weight":         "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.MaxConnections": "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.MaxConnections":        "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.TerminationDelay":      "42",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":           "42",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.Weight":         "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.MaxConnections": "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.MaxConnections":        "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.TerminationDelay":      "42",
 
-		"traefik.UDP.Routers.Router0.EntryPoints":                "foobar, fiibar",
-		"traefik.UDP.Routers.Router0.Service":                    "foobar",
-		"traefik.UDP.Routers.Router1.EntryPoints":                "foobar, fiibar",
-		"traefik.UDP.Routers.Router1.Service":                    "foobar",
-		"traefik.UDP.Services.Service0.LoadBalancer.server.Port": "42",
-		"traefik.UDP.Services.Service1.LoadBalancer.server.Port": "42",
+		"traefik.UDP.Routers.Router0.EntryPoints":                  "foobar, fiibar",
+		"traefik.UDP.Routers.Router0.Service":                      "foobar",
+		"traefik.UDP.Routers.Router1.EntryPoints":                  "foobar, fiibar",
+		"traefik.UDP.Routers.Router1.Service":                      "foobar",
+		"traefik.UDP.Services.Service0.LoadBalancer.server.Port":   "42",
+		"traefik.UDP.Services.Service0.LoadBalancer.server.Weight": "0",
+		"traefik.UDP.Services.Service1.LoadBalancer.server.Port":   "42",
+		"traefik.UDP.Services.Service1.LoadBalancer.server.Weight": "0",
 	}
 
 	for key, val := range expected {
@@ -34,12 +34,17 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 
 	runtimeConfig := &Configuration{}
 
+	origins := conf.Origins
+	if origins == nil {
+		origins = &dynamic.Origins{}
+	}
+
 	if conf.HTTP != nil {
 		routers := conf.HTTP.Routers
 		if len(routers) > 0 {
 			runtimeConfig.Routers = make(map[string]*RouterInfo, len(routers))
 			for k, v := range routers {
-				runtimeConfig.Routers[k] = &RouterInfo{Router: v, Status: StatusEnabled}
+				runtimeConfig.Routers[k] = &RouterInfo{Router: v, Status: StatusEnabled, Origins: origins.Routers[k]}
 			}
 		}
 
@@ -47,7 +52,7 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 		if len(services) > 0 {
 			runtimeConfig.Services = make(map[string]*ServiceInfo, len(services))
 			for k, v := range services {
-				runtimeConfig.Services[k] = &ServiceInfo{Service: v, Status: StatusEnabled}
+				runtimeConfig.Services[k] = &ServiceInfo{Service: v, Status: StatusEnabled, Origins: origins.Services[k]}
 			}
 		}
 
@@ -55,7 +60,7 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 		if len(middlewares) > 0 {
 			runtimeConfig.Middlewares = make(map[string]*MiddlewareInfo, len(middlewares))
 			for k, v := range middlewares {
-				runtimeConfig.Middlewares[k] = &MiddlewareInfo{Middleware: v, Status: StatusEnabled}
+				runtimeConfig.Middlewares[k] = &MiddlewareInfo{Middleware: v, Status: StatusEnabled, Origins: origins.Middlewares[k]}
 			}
 		}
 	}
@@ -64,14 +69,14 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 		if len(conf.TCP.Routers) > 0 {
 			runtimeConfig.TCPRouters = make(map[string]*TCPRouterInfo, len(conf.TCP.Routers))
 			for k, v := range conf.TCP.Routers {
-				runtimeConfig.TCPRouters[k] = &TCPRouterInfo{TCPRouter: v, Status: StatusEnabled}
+				runtimeConfig.TCPRouters[k] = &TCPRouterInfo{TCPRouter: v, Status: StatusEnabled, Origins: origins.TCPRouters[k]}
 			}
 		}
 
 		if len(conf.TCP.Services) > 0 {
 			runtimeConfig.TCPServices = make(map[string]*TCPServiceInfo, len(conf.TCP.Services))
 			for k, v := range conf.TCP.Services {
-				runtimeConfig.TCPServices[k] = &TCPServiceInfo{TCPService: v, Status: StatusEnabled}
+				runtimeConfig.TCPServices[k] = &TCPServiceInfo{TCPService: v, Status: StatusEnabled, Origins: origins.TCPServices[k]}
 			}
 		}
 	}
@@ -80,14 +85,14 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 		if len(conf.UDP.Routers) > 0 {
 			runtimeConfig.UDPRouters = make(map[string]*UDPRouterInfo, len(conf.UDP.Routers))
 			for k, v := range conf.UDP.Routers {
-				runtimeConfig.UDPRouters[k] = &UDPRouterInfo{UDPRouter: v, Status: StatusEnabled}
+				runtimeConfig.UDPRouters[k] = &UDPRouterInfo{UDPRouter: v, Status: StatusEnabled, Origins: origins.UDPRouters[k]}
 			}
 		}
 
 		if len(conf.UDP.Services) > 0 {
 			runtimeConfig.UDPServices = make(map[string]*UDPServiceInfo, len(conf.UDP.Services))
 			for k, v := range conf.UDP.Services {
-				runtimeConfig.UDPServices[k] = &UDPServiceInfo{UDPService: v, Status: StatusEnabled}
+				runtimeConfig.UDPServices[k] = &UDPServiceInfo{UDPService: v, Status: StatusEnabled, Origins: origins.UDPServices[k]}
 			}
 		}
 	}
@@ -76,7 +76,10 @@ type RouterInfo struct {
 	// If not in "enabled" state, the reason for it should be in the list of Err.
 	// It is the caller's responsibility to set the initial status.
 	Status string   `json:"status,omitempty"`
-	Using  []string `json:"using,omitempty"` // Effective entry points used by that router.
+	Using  []string `json:"using,omitempty"`  // Effective entry points used by that router.
+	Shadow bool     `json:"shadow,omitempty"` // Set when the router comes from a provider in dry-run mode, and receives no traffic.
+	// Origins are the provider resources the router was defined in.
+	Origins []dynamic.Origin `json:"origins,omitempty"`
 }
 
 // AddError adds err to r.Err, if it does not already exist.
@@ -107,6 +110,9 @@ type MiddlewareInfo struct {
 	Err    []string `json:"error,omitempty"`
 	Status string   `json:"status,omitempty"`
 	UsedBy []string `json:"usedBy,omitempty"` // list of routers and services using that middleware.
+	Shadow bool     `json:"shadow,omitempty"` // Set when the middleware comes from a provider in dry-run mode, and handles no traffic.
+	// Origins are the provider resources the middleware was defined in.
+	Origins []dynamic.Origin `json:"origins,omitempty"`
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -140,6 +146,9 @@ type ServiceInfo struct {
 	// It is the caller's responsibility to set the initial status.
 	Status string   `json:"status,omitempty"`
 	UsedBy []string `json:"usedBy,omitempty"` // list of routers using that service
+	Shadow bool     `json:"shadow,omitempty"` // Set when the service comes from a provider in dry-run mode, and handles no traffic.
+	// Origins are the provider resources the service was defined in.
+	Origins []dynamic.Origin `json:"origins,omitempty"`
 
 	serverStatusMu sync.RWMutex
 	serverStatus   map[string]string // keyed by server URL
@@ -178,6 +187,14 @@ func (s *ServiceInfo) UpdateServerStatus(server string, status string) {
 	s.serverStatus[server] = status
 }
 
+// RemoveServerStatus removes the status of a server which is no longer part of the service.
+func (s *ServiceInfo) RemoveServerStatus(server string) {
+	s.serverStatusMu.Lock()
+	defer s.serverStatusMu.Unlock()
+
+	delete(s.serverStatus, server)
+}
+
 // GetAllStatus returns all the statuses of all the servers in ServiceInfo.
 // It is the responsibility of the caller to check that s is not nil.
 func (s *ServiceInfo) GetAllStatus() map[string]string {
@@ -688,3 +688,36 @@ func TestPopulateUsedBy(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfig_origins(t *testing.T) {
+	origin := dynamic.Origin{File: "/etc/traefik/dynamic.toml", Line: 3}
+
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@file": {Service: "foo-service"},
+				"bar@file": {Service: "foo-service"},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Services: map[string]*dynamic.TCPService{
+				"foo@file": {},
+			},
+		},
+		Origins: &dynamic.Origins{
+			Routers:     map[string][]dynamic.Origin{"foo@file": {origin}},
+			TCPServices: map[string][]dynamic.Origin{"foo@file": {origin}},
+		},
+	}
+
+	runtimeConf := runtime.NewConfig(conf)
+
+	assert.Equal(t, []dynamic.Origin{origin}, runtimeConf.Routers["foo@file"].Origins)
+	assert.Nil(t, runtimeConf.Routers["bar@file"].Origins)
+	assert.Equal(t, []dynamic.Origin{origin}, runtimeConf.TCPServices["foo@file"].Origins)
+
+	conf.Origins = nil
+	runtimeConf = runtime.NewConfig(conf)
+
+	assert.Nil(t, runtimeConf.Routers["foo@file"].Origins)
+}
@@ -3,6 +3,7 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
@@ -57,7 +58,10 @@ type UDPRouterInfo struct {
 	// If not in "enabled" state, the reason for it should be in the list of Err.
 	// It is the caller's responsibility to set the initial status.
 	Status string   `json:"status,omitempty"`
-	Using  []string `json:"using,omitempty"` // Effective entry points used by that router.
+	Using  []string `json:"using,omitempty"`  // Effective entry points used by that router.
+	Shadow bool     `json:"shadow,omitempty"` // Set when the router comes from a provider in dry-run mode, and receives no traffic.
+	// Origins are the provider resources the router was defined in.
+	Origins []dynamic.Origin `json:"origins,omitempty"`
 }
 
 // AddError adds err to r.Err, if it does not already exist.
@@ -90,6 +94,12 @@ type UDPServiceInfo struct {
 	// It is the caller's responsibility to set the initial status.
 	Status string   `json:"status,omitempty"`
 	UsedBy []string `json:"usedBy,omitempty"` // list of routers using that service
+	Shadow bool     `json:"shadow,omitempty"` // Set when the service comes from a provider in dry-run mode, and handles no traffic.
+	// Origins are the provider resources the service was defined in.
+	Origins []dynamic.Origin `json:"origins,omitempty"`
+
+	serverStatusMu sync.RWMutex
+	serverStatus   map[string]string // keyed by server address
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -112,3 +122,32 @@ func (s *UDPServiceInfo) AddError(err error, critical bool) {
 		s.Status = StatusWarning
 	}
 }
+
+// UpdateServerStatus sets the status of the server in the UDPServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *UDPServiceInfo) UpdateServerStatus(server string, status string) {
+	s.serverStatusMu.Lock()
+	defer s.serverStatusMu.Unlock()
+
+	if s.serverStatus == nil {
+		s.serverStatus = make(map[string]string)
+	}
+	s.serverStatus[server] = status
+}
+
+// GetAllStatus returns all the statuses of all the servers in UDPServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *UDPServiceInfo) GetAllStatus() map[string]string {
+	s.serverStatusMu.RLock()
+	defer s.serverStatusMu.RUnlock()
+
+	if len(s.serverStatus) == 0 {
+		return nil
+	}
+
+	allStatus := make(map[string]string, len(s.serverStatus))
+	for k, v := range s.serverStatus {
+		allStatus[k] = v
+	}
+	return allStatus
+}
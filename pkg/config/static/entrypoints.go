@@ -15,6 +15,8 @@ type EntryPoint struct {
 	ProxyProtocol    *ProxyProtocol        `description:"Proxy-Protocol configuration." json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty"`
 	ForwardedHeaders *ForwardedHeaders     `description:"Trust client forwarding headers." json:"forwardedHeaders,omitempty" toml:"forwardedHeaders,omitempty" yaml:"forwardedHeaders,omitempty"`
 	HTTP             HTTPConfig            `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty"`
+	TCP              TCPConfig             `description:"TCP configuration." json:"tcp,omitempty" toml:"tcp,omitempty" yaml:"tcp,omitempty" export:"true"`
+	TLSFilter        *TLSFilter            `description:"Rules rejecting the TLS connections on their ClientHello, before the handshake." json:"tlsFilter,omitempty" toml:"tlsFilter,omitempty" yaml:"tlsFilter,omitempty" export:"true"`
 }
 
 // GetAddress strips any potential protocol part of the address field of the
@@ -49,14 +51,35 @@ func (ep *EntryPoint) SetDefaults() {
 
 // HTTPConfig is the HTTP configuration of an entry point.
 type HTTPConfig struct {
-	Redirections *Redirections `description:"Set of redirection" json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty"`
-	Middlewares  []string      `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
-	TLS          *TLSConfig    `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty"`
+	Redirections   *Redirections   `description:"Set of redirection" json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty"`
+	Middlewares    []string        `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
+	TLS            *TLSConfig      `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty"`
+	MaxRequestRate *MaxRequestRate `description:"Maximum rate of the requests accepted by the entry point, the excess requests being rejected before the routing." json:"maxRequestRate,omitempty" toml:"maxRequestRate,omitempty" yaml:"maxRequestRate,omitempty" export:"true"`
+}
+
+// MaxRequestRate is the maximum rate of the requests accepted by an entry point.
+type MaxRequestRate struct {
+	Average int64 `description:"Maximum average number of requests per second." json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	Burst   int64 `description:"Maximum number of requests accepted at once above the average rate. Defaults to the average." json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+}
+
+// TCPConfig is the TCP configuration of an entry point.
+type TCPConfig struct {
+	CatchAllPriority int `description:"Priority of the HostSNI(*) TCP routers without explicit priority, against the wildcard HostSNI and HostSNIRegexp routers. By default, they are only used when no other router matches." json:"catchAllPriority,omitempty" toml:"catchAllPriority,omitempty" yaml:"catchAllPriority,omitempty" export:"true"`
 }
 
 // Redirections is a set of redirection for an entry point.
 type Redirections struct {
 	EntryPoint *RedirectEntryPoint `description:"Set of redirection for an entry point." json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty"`
+	Rules      []RedirectRule      `description:"Redirections of the requests by host, taking precedence over the entry point redirection." json:"rules,omitempty" toml:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// RedirectRule is the definition of the redirection of the requests whose host matches one of the patterns.
+// The first matching rule applies.
+type RedirectRule struct {
+	Hosts     []string `description:"Host patterns of the redirected requests, with the HostRegexp syntax." json:"hosts,omitempty" toml:"hosts,omitempty" yaml:"hosts,omitempty"`
+	To        string   `description:"Target URL template of the redirection, where {scheme}, {host}, {port} and {path} are replaced by the ones of the request. The requests are not redirected if empty." json:"to,omitempty" toml:"to,omitempty" yaml:"to,omitempty"`
+	Permanent bool     `description:"Applies a permanent redirection." json:"permanent,omitempty" toml:"permanent,omitempty" yaml:"permanent,omitempty"`
 }
 
 // RedirectEntryPoint is the definition of an entry point redirection.
@@ -93,6 +116,15 @@ type ProxyProtocol struct {
 	TrustedIPs []string `description:"Trust only selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
 }
 
+// TLSFilter holds the rules rejecting the TLS connections on their ClientHello.
+type TLSFilter struct {
+	RequireServerName     bool     `description:"Reject the connections without SNI." json:"requireServerName,omitempty" toml:"requireServerName,omitempty" yaml:"requireServerName,omitempty" export:"true"`
+	AllowedServerNames    []string `description:"Regular expressions matching the allowed SNI." json:"allowedServerNames,omitempty" toml:"allowedServerNames,omitempty" yaml:"allowedServerNames,omitempty"`
+	DeniedServerNames     []string `description:"Regular expressions matching the rejected SNI." json:"deniedServerNames,omitempty" toml:"deniedServerNames,omitempty" yaml:"deniedServerNames,omitempty"`
+	AllowedALPNProtocols  []string `description:"Reject the connections which do not offer any of these ALPN protocols." json:"allowedALPNProtocols,omitempty" toml:"allowedALPNProtocols,omitempty" yaml:"allowedALPNProtocols,omitempty"`
+	DeniedJA3Fingerprints []string `description:"JA3 fingerprints of the rejected clients." json:"deniedJA3Fingerprints,omitempty" toml:"deniedJA3Fingerprints,omitempty" yaml:"deniedJA3Fingerprints,omitempty"`
+}
+
 // EntryPoints holds the HTTP entry point list.
 type EntryPoints map[string]*EntryPoint
 
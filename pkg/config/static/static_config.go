@@ -1,11 +1,14 @@
 package static
 
 import (
+	"errors"
 	"fmt"
 	stdlog "log"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/containous/traefik/v2/pkg/health"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/ping"
 	acmeprovider "github.com/containous/traefik/v2/pkg/provider/acme"
@@ -57,9 +60,18 @@ type Configuration struct {
 	EntryPoints      EntryPoints       `description:"Entry points definition." json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
 	Providers        *Providers        `description:"Providers configuration." json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty" export:"true"`
 
-	API     *API           `description:"Enable api/dashboard." json:"api,omitempty" toml:"api,omitempty" yaml:"api,omitempty" label:"allowEmpty" export:"true"`
-	Metrics *types.Metrics `description:"Enable a metrics exporter." json:"metrics,omitempty" toml:"metrics,omitempty" yaml:"metrics,omitempty" export:"true"`
-	Ping    *ping.Handler  `description:"Enable ping." json:"ping,omitempty" toml:"ping,omitempty" yaml:"ping,omitempty" label:"allowEmpty" export:"true"`
+	API     *API            `description:"Enable api/dashboard." json:"api,omitempty" toml:"api,omitempty" yaml:"api,omitempty" label:"allowEmpty" export:"true"`
+	Metrics *types.Metrics  `description:"Enable a metrics exporter." json:"metrics,omitempty" toml:"metrics,omitempty" yaml:"metrics,omitempty" export:"true"`
+	Ping    *ping.Handler   `description:"Enable ping." json:"ping,omitempty" toml:"ping,omitempty" yaml:"ping,omitempty" label:"allowEmpty" export:"true"`
+	Health  *health.Handler `description:"Enable the aggregated health endpoint of backend services." json:"health,omitempty" toml:"health,omitempty" yaml:"health,omitempty" label:"allowEmpty" export:"true"`
+
+	DebugHeaders     *DebugHeaders     `description:"Debug headers configuration." json:"debugHeaders,omitempty" toml:"debugHeaders,omitempty" yaml:"debugHeaders,omitempty" export:"true"`
+	InternalListener *InternalListener `description:"Dedicated listener for the API and the metrics." json:"internalListener,omitempty" toml:"internalListener,omitempty" yaml:"internalListener,omitempty" export:"true"`
+	Overload         *Overload         `description:"Load shedding when the memory usage exceeds a soft limit." json:"overload,omitempty" toml:"overload,omitempty" yaml:"overload,omitempty" export:"true"`
+	ResponseHeaders  *ResponseHeaders  `description:"Response headers removed before reaching the clients." json:"responseHeaders,omitempty" toml:"responseHeaders,omitempty" yaml:"responseHeaders,omitempty" export:"true"`
+	Shutdown         *Shutdown         `description:"Shutdown configuration." json:"shutdown,omitempty" toml:"shutdown,omitempty" yaml:"shutdown,omitempty" export:"true"`
+
+	HealthCheckWebhook *HealthCheckWebhook `description:"Webhook notified when the health check marks a server up or down." json:"healthCheckWebhook,omitempty" toml:"healthCheckWebhook,omitempty" yaml:"healthCheckWebhook,omitempty" export:"true"`
 
 	Log       *types.TraefikLog `description:"Traefik log settings." json:"log,omitempty" toml:"log,omitempty" yaml:"log,omitempty" label:"allowEmpty" export:"true"`
 	AccessLog *types.AccessLog  `description:"Access log settings." json:"accessLog,omitempty" toml:"accessLog,omitempty" yaml:"accessLog,omitempty" label:"allowEmpty" export:"true"`
@@ -85,8 +97,39 @@ type Global struct {
 type ServersTransport struct {
 	InsecureSkipVerify  bool                `description:"Disable SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
 	RootCAs             []tls.FileOrContent `description:"Add cert file for self-signed certificate." json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
+	ServerName          string              `description:"Server name sent with SNI and verified against the certificates of the servers, instead of their hostname. The {host} placeholder is replaced by the host of the request." json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty" export:"true"`
+	PinnedPublicKeys    []string            `description:"Base64 encoded SHA-256 hashes of the expected public keys (SPKI) of the certificates of the servers." json:"pinnedPublicKeys,omitempty" toml:"pinnedPublicKeys,omitempty" yaml:"pinnedPublicKeys,omitempty"`
+	ClientCertificate   *ClientCertificate  `description:"Certificate presented to the servers requesting a client certificate (mTLS)." json:"clientCertificate,omitempty" toml:"clientCertificate,omitempty" yaml:"clientCertificate,omitempty"`
 	MaxIdleConnsPerHost int                 `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
 	ForwardingTimeouts  *ForwardingTimeouts `description:"Timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
+	DNSResolution       *DNSResolution      `description:"Periodic re-resolution of the servers hostnames, honoring the TTL of the DNS answers." json:"dnsResolution,omitempty" toml:"dnsResolution,omitempty" yaml:"dnsResolution,omitempty" label:"allowEmpty" export:"true"`
+	ProtocolDetection   *ProtocolDetection  `description:"Detection of the servers speaking HTTP/2 over cleartext (h2c), to which the requests are then forwarded with HTTP/2." json:"protocolDetection,omitempty" toml:"protocolDetection,omitempty" yaml:"protocolDetection,omitempty" label:"allowEmpty" export:"true"`
+}
+
+// DNSResolution configures how long the IP addresses of the servers hostnames are cached before being resolved again.
+type DNSResolution struct {
+	MinTTL       types.Duration `description:"Minimum duration a DNS answer is cached, whatever its TTL." json:"minTTL,omitempty" toml:"minTTL,omitempty" yaml:"minTTL,omitempty" export:"true"`
+	MaxTTL       types.Duration `description:"Maximum duration a DNS answer is cached, whatever its TTL." json:"maxTTL,omitempty" toml:"maxTTL,omitempty" yaml:"maxTTL,omitempty" export:"true"`
+	ResolvConfig string         `description:"resolv.conf used for DNS resolving." json:"resolvConfig,omitempty" toml:"resolvConfig,omitempty" yaml:"resolvConfig,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (d *DNSResolution) SetDefaults() {
+	d.MinTTL = types.Duration(5 * time.Second)
+	d.MaxTTL = types.Duration(5 * time.Minute)
+	d.ResolvConfig = "/etc/resolv.conf"
+}
+
+// ProtocolDetection configures the probing of the protocol spoken by the servers reached over cleartext HTTP.
+type ProtocolDetection struct {
+	ProbeTimeout  types.Duration `description:"Maximum duration of the probe of the protocol of a server." json:"probeTimeout,omitempty" toml:"probeTimeout,omitempty" yaml:"probeTimeout,omitempty" export:"true"`
+	CacheDuration types.Duration `description:"Duration the detected protocol of a server is cached before probing it again." json:"cacheDuration,omitempty" toml:"cacheDuration,omitempty" yaml:"cacheDuration,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (p *ProtocolDetection) SetDefaults() {
+	p.ProbeTimeout = types.Duration(time.Second)
+	p.CacheDuration = types.Duration(time.Minute)
 }
 
 // API holds the API configuration.
@@ -94,6 +137,9 @@ type API struct {
 	Insecure  bool `description:"Activate API directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
 	Dashboard bool `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
 	Debug     bool `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
+	// Generations is the number of the last dynamic configurations kept in memory, 0 meaning none.
+	Generations int  `description:"Number of the last dynamic configuration generations kept in memory, which can be rolled back to." json:"generations,omitempty" toml:"generations,omitempty" yaml:"generations,omitempty" export:"true"`
+	Freeze      bool `description:"Enable the endpoints freezing the application of the dynamic configuration changes." json:"freeze,omitempty" toml:"freeze,omitempty" yaml:"freeze,omitempty" export:"true"`
 	// TODO: Re-enable statistics
 	// Statistics      *types.Statistics `description:"Enable more detailed statistics." json:"statistics,omitempty" toml:"statistics,omitempty" yaml:"statistics,omitempty" export:"true" label:"allowEmpty"`
 	DashboardAssets *assetfs.AssetFS `json:"-" toml:"-" yaml:"-" label:"-"`
@@ -104,6 +150,62 @@ func (a *API) SetDefaults() {
 	a.Dashboard = true
 }
 
+// Overload holds the configuration of the load shedding, which starts when the memory usage of Traefik exceeds a soft limit.
+type Overload struct {
+	MemoryLimit   int64          `description:"Memory usage, in bytes, above which the load is shed." json:"memoryLimit,omitempty" toml:"memoryLimit,omitempty" yaml:"memoryLimit,omitempty" export:"true"`
+	CheckInterval types.Duration `description:"Interval at which the memory usage is checked." json:"checkInterval,omitempty" toml:"checkInterval,omitempty" yaml:"checkInterval,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (o *Overload) SetDefaults() {
+	o.CheckInterval = types.Duration(time.Second)
+}
+
+// Shutdown holds the configuration of the shutdown of Traefik, going through the stages of the entry points.
+type Shutdown struct {
+	Timeout types.Duration `description:"Maximum duration of the whole shutdown, all the stages included. Defaults to the longest lifeCycle of an entry point." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// DebugHeaders holds the configuration of the debug headers, appended to the responses of the routers which enable them.
+type DebugHeaders struct {
+	Secret string `description:"Secret used to sign the debug tokens." json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty"`
+}
+
+// ResponseHeaders holds the response headers removed on all the routers, after all their middlewares,
+// so that the servers cannot leak them to the clients.
+type ResponseHeaders struct {
+	Remove []string `description:"Names of the response headers to remove." json:"remove,omitempty" toml:"remove,omitempty" yaml:"remove,omitempty" export:"true"`
+}
+
+// HealthCheckWebhook holds the configuration of the webhook receiving the transitions of the servers decided by the health check.
+type HealthCheckWebhook struct {
+	URL     string            `description:"URL receiving the transitions, in POST requests." json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	Headers map[string]string `description:"Headers added to the requests." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	Timeout types.Duration    `description:"Timeout of the requests." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (h *HealthCheckWebhook) SetDefaults() {
+	h.Timeout = types.Duration(5 * time.Second)
+}
+
+// InternalListener holds the configuration of the listener dedicated to the API and the metrics.
+// It is independent of the entry points, so no router can expose what it serves.
+type InternalListener struct {
+	Address     string               `description:"Internal listener address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	TLS         *InternalListenerTLS `description:"Serve the internal listener over TLS." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	SourceRange []string             `description:"Allowed source IPs (CIDR ranges or IPs). If empty, all the source IPs are allowed." json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+	API         bool                 `description:"Serve the API and the dashboard on the internal listener." json:"api,omitempty" toml:"api,omitempty" yaml:"api,omitempty" export:"true"`
+	Prometheus  bool                 `description:"Serve the Prometheus metrics on the internal listener." json:"prometheus,omitempty" toml:"prometheus,omitempty" yaml:"prometheus,omitempty" export:"true"`
+}
+
+// InternalListenerTLS holds the TLS configuration of the internal listener.
+type InternalListenerTLS struct {
+	CertFile tls.FileOrContent   `description:"Certificate file (or content) of the internal listener." json:"certFile,omitempty" toml:"certFile,omitempty" yaml:"certFile,omitempty"`
+	KeyFile  tls.FileOrContent   `description:"Key file (or content) of the internal listener." json:"keyFile,omitempty" toml:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+	CAFiles  []tls.FileOrContent `description:"CA files (or contents) used to verify the client certificates. When set, a valid client certificate is required." json:"caFiles,omitempty" toml:"caFiles,omitempty" yaml:"caFiles,omitempty"`
+}
+
 // RespondingTimeouts contains timeout configurations for incoming requests to the Traefik instance.
 type RespondingTimeouts struct {
 	ReadTimeout  types.Duration `description:"ReadTimeout is the maximum duration for reading the entire request, including the body. If zero, no timeout is set." json:"readTimeout,omitempty" toml:"readTimeout,omitempty" yaml:"readTimeout,omitempty" export:"true"`
@@ -116,6 +218,12 @@ func (a *RespondingTimeouts) SetDefaults() {
 	a.IdleTimeout = types.Duration(DefaultIdleTimeout)
 }
 
+// ClientCertificate holds the certificate presented by Traefik to the servers, both when forwarding the requests and when health checking them.
+type ClientCertificate struct {
+	CertFile tls.FileOrContent `description:"Certificate file (or content) presented to the servers." json:"certFile,omitempty" toml:"certFile,omitempty" yaml:"certFile,omitempty"`
+	KeyFile  tls.FileOrContent `description:"Key file (or content) of the certificate presented to the servers." json:"keyFile,omitempty" toml:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+}
+
 // ForwardingTimeouts contains timeout configurations for forwarding requests to the backend servers.
 type ForwardingTimeouts struct {
 	DialTimeout           types.Duration `description:"The amount of time to wait until a connection to a backend server can be established. If zero, no timeout exists." json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
@@ -133,6 +241,7 @@ func (f *ForwardingTimeouts) SetDefaults() {
 type LifeCycle struct {
 	RequestAcceptGraceTimeout types.Duration `description:"Duration to keep accepting requests before Traefik initiates the graceful shutdown procedure." json:"requestAcceptGraceTimeout,omitempty" toml:"requestAcceptGraceTimeout,omitempty" yaml:"requestAcceptGraceTimeout,omitempty" export:"true"`
 	GraceTimeOut              types.Duration `description:"Duration to give active requests a chance to finish before Traefik stops." json:"graceTimeOut,omitempty" toml:"graceTimeOut,omitempty" yaml:"graceTimeOut,omitempty" export:"true"`
+	ShutdownOrder             int            `description:"Order in which the entry point is shut down, the entry points with the lowest order being shut down first." json:"shutdownOrder,omitempty" toml:"shutdownOrder,omitempty" yaml:"shutdownOrder,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -177,6 +286,60 @@ type Providers struct {
 	Redis     *redis.Provider  `description:"Enable Redis backend with default settings." json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" export:"true" label:"allowEmpty"`
 }
 
+// DryRunProviders returns the names of the providers running in dry-run mode,
+// whose configuration is validated and shown in the API, but never routes traffic.
+func (p *Providers) DryRunProviders() []string {
+	if p == nil {
+		return nil
+	}
+
+	var names []string
+	addIf := func(dryRun bool, name string) {
+		if dryRun {
+			names = append(names, name)
+		}
+	}
+
+	if p.Docker != nil {
+		addIf(p.Docker.DryRun, "docker")
+	}
+	if p.File != nil {
+		addIf(p.File.DryRun, "file")
+	}
+	if p.Marathon != nil {
+		addIf(p.Marathon.DryRun, "marathon")
+	}
+	if p.KubernetesIngress != nil {
+		addIf(p.KubernetesIngress.DryRun, "kubernetes")
+	}
+	if p.KubernetesCRD != nil {
+		addIf(p.KubernetesCRD.DryRun, "kubernetescrd")
+	}
+	if p.Rest != nil {
+		addIf(p.Rest.DryRun, "rest")
+	}
+	if p.Rancher != nil {
+		addIf(p.Rancher.DryRun, "rancher")
+	}
+	if p.ConsulCatalog != nil {
+		addIf(p.ConsulCatalog.DryRun, "consulcatalog")
+	}
+	if p.Consul != nil {
+		addIf(p.Consul.DryRun, "consul")
+	}
+	if p.Etcd != nil {
+		addIf(p.Etcd.DryRun, "etcd")
+	}
+	if p.ZooKeeper != nil {
+		addIf(p.ZooKeeper.DryRun, "zookeeper")
+	}
+	if p.Redis != nil {
+		addIf(p.Redis.DryRun, "redis")
+	}
+
+	return names
+}
+
 // SetEffectiveConfiguration adds missing configuration parameters derived from existing ones.
 // It also takes care of maintaining backwards compatibility.
 func (c *Configuration) SetEffectiveConfiguration() {
@@ -190,6 +353,7 @@ func (c *Configuration) SetEffectiveConfiguration() {
 	// Creates the internal traefik entry point if needed
 	if (c.API != nil && c.API.Insecure) ||
 		(c.Ping != nil && !c.Ping.ManualRouting && c.Ping.EntryPoint == DefaultInternalEntryPointName) ||
+		(c.Health != nil && !c.Health.ManualRouting && c.Health.EntryPoint == DefaultInternalEntryPointName) ||
 		(c.Metrics != nil && c.Metrics.Prometheus != nil && !c.Metrics.Prometheus.ManualRouting && c.Metrics.Prometheus.EntryPoint == DefaultInternalEntryPointName) ||
 		(c.Providers != nil && c.Providers.Rest != nil && c.Providers.Rest.Insecure) {
 		if _, ok := c.EntryPoints[DefaultInternalEntryPointName]; !ok {
@@ -242,6 +406,63 @@ func (c *Configuration) ValidateConfiguration() error {
 		acmeEmail = resolver.ACME.Email
 	}
 
+	if c.DebugHeaders != nil && c.DebugHeaders.Secret == "" {
+		return errors.New("the debug headers require a secret")
+	}
+
+	if c.ResponseHeaders != nil && len(c.ResponseHeaders.Remove) == 0 {
+		return errors.New("the response headers removal requires at least one header")
+	}
+
+	if c.HealthCheckWebhook != nil {
+		u, err := url.Parse(c.HealthCheckWebhook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("the health check webhook requires an HTTP URL: %q", c.HealthCheckWebhook.URL)
+		}
+	}
+
+	if c.Overload != nil && (c.Overload.MemoryLimit <= 0 || c.Overload.CheckInterval <= 0) {
+		return errors.New("the overload protection requires a positive memory limit and check interval")
+	}
+
+	for name, ep := range c.EntryPoints {
+		if ep.HTTP.MaxRequestRate != nil && (ep.HTTP.MaxRequestRate.Average <= 0 || ep.HTTP.MaxRequestRate.Burst < 0) {
+			return fmt.Errorf("the maximum request rate of the entry point %s requires a positive average and a non-negative burst", name)
+		}
+	}
+
+	if c.InternalListener != nil {
+		if c.InternalListener.Address == "" {
+			return errors.New("the internal listener requires an address")
+		}
+
+		if c.InternalListener.API && c.API == nil {
+			return errors.New("the internal listener cannot serve the API: the API is not enabled")
+		}
+
+		if c.InternalListener.Prometheus && (c.Metrics == nil || c.Metrics.Prometheus == nil) {
+			return errors.New("the internal listener cannot serve the Prometheus metrics: the Prometheus metrics are not enabled")
+		}
+
+		if c.InternalListener.TLS != nil && (c.InternalListener.TLS.CertFile == "" || c.InternalListener.TLS.KeyFile == "") {
+			return errors.New("the TLS configuration of the internal listener requires a certificate and a key")
+		}
+	}
+
+	if c.ServersTransport != nil && c.ServersTransport.ClientCertificate != nil {
+		if c.ServersTransport.ClientCertificate.CertFile == "" || c.ServersTransport.ClientCertificate.KeyFile == "" {
+			return errors.New("the client certificate of the servers transport requires a certificate and a key")
+		}
+	}
+
+	if c.ServersTransport != nil && c.ServersTransport.DNSResolution != nil {
+		resolution := c.ServersTransport.DNSResolution
+		if resolution.MinTTL <= 0 || resolution.MaxTTL < resolution.MinTTL {
+			return fmt.Errorf("invalid DNS resolution TTL bounds: the minimum (%s) must be positive and lower than the maximum (%s)",
+				time.Duration(resolution.MinTTL), time.Duration(resolution.MaxTTL))
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,139 @@
+// Package connections keeps track of the active TCP connections and UDP sessions handled by the routers.
+package connections
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when a connection is not in the table.
+var ErrNotFound = errors.New("connection not found")
+
+// Stats holds the live statistics of a tracked connection.
+type Stats struct {
+	Backend  string
+	BytesIn  int64
+	BytesOut int64
+}
+
+// Conn is a connection which can be tracked by the table.
+type Conn interface {
+	io.Closer
+	Stats() Stats
+}
+
+// Connection is the representation of an active connection.
+type Connection struct {
+	ID       string    `json:"id"`
+	Protocol string    `json:"protocol"`
+	Router   string    `json:"router"`
+	Source   string    `json:"source"`
+	Backend  string    `json:"backend,omitempty"`
+	Start    time.Time `json:"start"`
+	Age      string    `json:"age"`
+	BytesIn  int64     `json:"bytesIn"`
+	BytesOut int64     `json:"bytesOut"`
+}
+
+type entry struct {
+	id       uint64
+	protocol string
+	router   string
+	source   string
+	start    time.Time
+	conn     Conn
+}
+
+// Table is the table of the active connections.
+type Table struct {
+	mu      sync.RWMutex
+	entries map[string]*entry
+	lastID  uint64
+}
+
+// NewTable creates a new Table.
+func NewTable() *Table {
+	return &Table{entries: make(map[string]*entry)}
+}
+
+// Add registers a connection of the given router, and returns its ID.
+func (t *Table) Add(protocol, router, source string, conn Conn) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastID++
+	id := strconv.FormatUint(t.lastID, 10)
+
+	t.entries[id] = &entry{
+		id:       t.lastID,
+		protocol: protocol,
+		router:   router,
+		source:   source,
+		start:    time.Now(),
+		conn:     conn,
+	}
+
+	return id
+}
+
+// Remove unregisters a connection.
+func (t *Table) Remove(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.entries, id)
+}
+
+// List returns the active connections of the given protocol, sorted by ID.
+func (t *Table) List(protocol string) []Connection {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	now := time.Now()
+
+	var sorted []*entry
+	for _, e := range t.entries {
+		if e.protocol == protocol {
+			sorted = append(sorted, e)
+		}
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].id < sorted[j].id
+	})
+
+	connections := make([]Connection, 0, len(sorted))
+	for _, e := range sorted {
+		stats := e.conn.Stats()
+		connections = append(connections, Connection{
+			ID:       strconv.FormatUint(e.id, 10),
+			Protocol: e.protocol,
+			Router:   e.router,
+			Source:   e.source,
+			Backend:  stats.Backend,
+			Start:    e.start,
+			Age:      now.Sub(e.start).Truncate(time.Second).String(),
+			BytesIn:  stats.BytesIn,
+			BytesOut: stats.BytesOut,
+		})
+	}
+
+	return connections
+}
+
+// Close terminates the connection with the given ID.
+func (t *Table) Close(protocol, id string) error {
+	t.mu.RLock()
+	e, ok := t.entries[id]
+	t.mu.RUnlock()
+
+	if !ok || e.protocol != protocol {
+		return ErrNotFound
+	}
+
+	return e.conn.Close()
+}
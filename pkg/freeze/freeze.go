@@ -0,0 +1,208 @@
+// Package freeze suspends the application of the dynamic configuration changes during sensitive windows,
+// and applies the accumulated changes at once when the freeze ends.
+package freeze
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+// ErrNotFrozen is returned when unfreezing a dynamic configuration which is not frozen.
+var ErrNotFrozen = errors.New("the dynamic configuration is not frozen")
+
+// Status is the representation of the freeze of the dynamic configuration.
+type Status struct {
+	Frozen bool       `json:"frozen"`
+	Reason string     `json:"reason,omitempty"`
+	Since  *time.Time `json:"since,omitempty"`
+	// Until is the date the freeze ends automatically, if any.
+	Until *time.Time `json:"until,omitempty"`
+	// PendingProviders are the providers whose configuration changes are queued until the freeze ends.
+	PendingProviders []string `json:"pendingProviders,omitempty"`
+}
+
+// UnfreezeFunc applies the configuration changes of the given providers, which were queued during the freeze.
+type UnfreezeFunc func(providers []string)
+
+// Freeze holds the freeze state of the dynamic configuration.
+type Freeze struct {
+	mu       sync.Mutex
+	frozen   bool
+	reason   string
+	since    time.Time
+	until    time.Time
+	timer    *time.Timer
+	pending  map[string]struct{}
+	unfreeze UnfreezeFunc
+}
+
+// New creates a new Freeze, initially not frozen.
+func New() *Freeze {
+	return &Freeze{}
+}
+
+// SetUnfreezeFunc sets the function applying the queued configuration changes.
+func (f *Freeze) SetUnfreezeFunc(unfreeze UnfreezeFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.unfreeze = unfreeze
+}
+
+// Freeze suspends the application of the configuration changes.
+// When duration is positive, the freeze ends automatically after it.
+// Freezing an already frozen configuration updates the reason and the end of the freeze.
+func (f *Freeze) Freeze(reason string, duration time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if !f.frozen {
+		f.frozen = true
+		f.since = now
+		f.pending = make(map[string]struct{})
+	}
+
+	f.reason = reason
+
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
+	}
+
+	f.until = time.Time{}
+	if duration > 0 {
+		until := now.Add(duration)
+		f.until = until
+		f.timer = time.AfterFunc(duration, func() { f.expire(until) })
+	}
+
+	log.WithoutContext().Infof("Freezing the dynamic configuration: %s", reason)
+}
+
+// Unfreeze resumes the application of the configuration changes, and applies the ones queued during the freeze.
+func (f *Freeze) Unfreeze() error {
+	f.mu.Lock()
+
+	if !f.frozen {
+		f.mu.Unlock()
+		return ErrNotFrozen
+	}
+
+	providers, unfreeze := f.reset(), f.unfreeze
+
+	f.mu.Unlock()
+
+	log.WithoutContext().Info("Unfreezing the dynamic configuration")
+	apply(providers, unfreeze)
+
+	return nil
+}
+
+// Queue reports whether the configuration is frozen, in which case the configuration change of the provider is queued.
+func (f *Freeze) Queue(provider string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.frozen {
+		return false
+	}
+
+	f.pending[provider] = struct{}{}
+
+	return true
+}
+
+// ClearPending forgets the configuration changes queued so far, while staying frozen,
+// when they are superseded by the configurations applied in the meantime, e.g. by a rollback.
+func (f *Freeze) ClearPending() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.frozen {
+		f.pending = make(map[string]struct{})
+	}
+}
+
+// Status returns the current freeze status.
+func (f *Freeze) Status() Status {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.frozen {
+		return Status{}
+	}
+
+	since := f.since
+	status := Status{
+		Frozen:           true,
+		Reason:           f.reason,
+		Since:            &since,
+		PendingProviders: f.pendingProviders(),
+	}
+
+	if !f.until.IsZero() {
+		until := f.until
+		status.Until = &until
+	}
+
+	return status
+}
+
+// expire ends the freeze whose end was set to the given date, unless it has been changed since.
+func (f *Freeze) expire(until time.Time) {
+	f.mu.Lock()
+
+	if !f.frozen || !f.until.Equal(until) {
+		f.mu.Unlock()
+		return
+	}
+
+	providers, unfreeze := f.reset(), f.unfreeze
+
+	f.mu.Unlock()
+
+	log.WithoutContext().Info("The freeze of the dynamic configuration has expired")
+	apply(providers, unfreeze)
+}
+
+// reset ends the freeze, and returns the pending providers. The lock must be held.
+func (f *Freeze) reset() []string {
+	providers := f.pendingProviders()
+
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
+	}
+
+	f.frozen = false
+	f.reason = ""
+	f.since = time.Time{}
+	f.until = time.Time{}
+	f.pending = nil
+
+	return providers
+}
+
+func apply(providers []string, unfreeze UnfreezeFunc) {
+	if unfreeze == nil || len(providers) == 0 {
+		return
+	}
+
+	log.WithoutContext().Infof("Applying the configuration changes queued during the freeze, from the providers %v", providers)
+	unfreeze(providers)
+}
+
+func (f *Freeze) pendingProviders() []string {
+	var providers []string
+	for name := range f.pending {
+		providers = append(providers, name)
+	}
+	sort.Strings(providers)
+
+	return providers
+}
@@ -0,0 +1,71 @@
+package freeze
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreeze_expiration(t *testing.T) {
+	f := New()
+
+	applied := make(chan []string, 1)
+	f.SetUnfreezeFunc(func(providers []string) {
+		applied <- providers
+	})
+
+	f.Freeze("peak", 50*time.Millisecond)
+	assert.True(t, f.Queue("docker"))
+	assert.True(t, f.Queue("file"))
+
+	select {
+	case providers := <-applied:
+		assert.Equal(t, []string{"docker", "file"}, providers)
+	case <-time.After(time.Second):
+		t.Fatal("the freeze should have expired")
+	}
+
+	assert.False(t, f.Status().Frozen)
+	assert.False(t, f.Queue("file"))
+}
+
+func TestFreeze_extended(t *testing.T) {
+	f := New()
+
+	f.Freeze("peak", 50*time.Millisecond)
+	// Freezing again without duration disables the automatic end of the freeze.
+	f.Freeze("longer peak", 0)
+
+	time.Sleep(100 * time.Millisecond)
+
+	status := f.Status()
+	assert.True(t, status.Frozen)
+	assert.Equal(t, "longer peak", status.Reason)
+	assert.Nil(t, status.Until)
+
+	assert.NoError(t, f.Unfreeze())
+	assert.Equal(t, ErrNotFrozen, f.Unfreeze())
+}
+
+func TestFreeze_clearPending(t *testing.T) {
+	f := New()
+
+	var applied []string
+	f.SetUnfreezeFunc(func(providers []string) {
+		applied = providers
+	})
+
+	f.Freeze("peak", 0)
+	assert.True(t, f.Queue("docker"))
+
+	f.ClearPending()
+
+	status := f.Status()
+	assert.True(t, status.Frozen)
+	assert.Empty(t, status.PendingProviders)
+	assert.True(t, f.Queue("file"))
+
+	assert.NoError(t, f.Unfreeze())
+	assert.Equal(t, []string{"file"}, applied)
+}
@@ -0,0 +1,131 @@
+// Package generations keeps the last dynamic configurations applied, so that one of them can be applied again.
+package generations
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+)
+
+// ErrNotFound is returned when a generation is not in the history.
+var ErrNotFound = errors.New("generation not found")
+
+// Generation is the representation of an applied dynamic configuration.
+type Generation struct {
+	ID uint64 `json:"id"`
+	// Provider is the provider whose configuration change created the generation.
+	Provider string    `json:"provider"`
+	Date     time.Time `json:"date"`
+	Current  bool      `json:"current"`
+}
+
+type entry struct {
+	id             uint64
+	provider       string
+	date           time.Time
+	configurations dynamic.Configurations
+}
+
+// RollbackFunc applies again the configurations of the given generation,
+// and marks it as the current one with SetCurrent.
+type RollbackFunc func(id uint64, configurations dynamic.Configurations) error
+
+// History holds the last generations of the dynamic configuration.
+type History struct {
+	mu       sync.RWMutex
+	max      int
+	entries  []*entry
+	lastID   uint64
+	current  uint64
+	rollback RollbackFunc
+}
+
+// NewHistory creates a new History keeping the given number of generations.
+func NewHistory(max int) *History {
+	return &History{max: max}
+}
+
+// SetRollbackFunc sets the function applying again the configurations of a generation.
+func (h *History) SetRollbackFunc(rollback RollbackFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.rollback = rollback
+}
+
+// Add records the configurations of all the providers as a new generation, which becomes the current one,
+// and drops the oldest generations beyond the maximum.
+// The configurations must not be modified afterwards.
+func (h *History) Add(provider string, configurations dynamic.Configurations) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	h.current = h.lastID
+
+	h.entries = append(h.entries, &entry{
+		id:             h.lastID,
+		provider:       provider,
+		date:           time.Now(),
+		configurations: configurations,
+	})
+
+	if len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+
+	return h.lastID
+}
+
+// List returns the generations, the newest first.
+func (h *History) List() []Generation {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	generations := make([]Generation, 0, len(h.entries))
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		e := h.entries[i]
+		generations = append(generations, Generation{
+			ID:       e.id,
+			Provider: e.provider,
+			Date:     e.date,
+			Current:  e.id == h.current,
+		})
+	}
+
+	return generations
+}
+
+// Rollback applies again the configurations of the given generation.
+// The next configuration change of a provider is applied on top of them.
+func (h *History) Rollback(id uint64) error {
+	h.mu.RLock()
+	rollback := h.rollback
+	var configurations dynamic.Configurations
+	for _, e := range h.entries {
+		if e.id == id {
+			configurations = e.configurations
+		}
+	}
+	h.mu.RUnlock()
+
+	if configurations == nil {
+		return ErrNotFound
+	}
+
+	if rollback == nil {
+		return errors.New("rollback is not available")
+	}
+
+	return rollback(id, configurations.DeepCopy())
+}
+
+// SetCurrent marks the given generation as the current one.
+func (h *History) SetCurrent(id uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.current = id
+}
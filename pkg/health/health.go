@@ -0,0 +1,117 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+const (
+	statusHealthy   = "healthy"
+	statusUnhealthy = "unhealthy"
+	serverUp        = "UP"
+)
+
+// Handler holds the configuration of the aggregated health endpoint of backend services.
+type Handler struct {
+	EntryPoint         string   `description:"EntryPoint" export:"true" json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty"`
+	ManualRouting      bool     `description:"Manual routing" json:"manualRouting,omitempty" toml:"manualRouting,omitempty" yaml:"manualRouting,omitempty"`
+	Services           []string `description:"Services to aggregate, by qualified name." json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	MinUpServers       int      `description:"Minimum number of UP servers for a service to be healthy." json:"minUpServers,omitempty" toml:"minUpServers,omitempty" yaml:"minUpServers,omitempty" export:"true"`
+	MinHealthyServices int      `description:"Minimum number of healthy services for the endpoint to succeed (0 means all of them)." json:"minHealthyServices,omitempty" toml:"minHealthyServices,omitempty" yaml:"minHealthyServices,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (h *Handler) SetDefaults() {
+	h.EntryPoint = "traefik"
+	h.MinUpServers = 1
+}
+
+// CreateHandler creates the handler reporting the aggregated health of the services of the given runtime configuration.
+func (h *Handler) CreateHandler(configuration *runtime.Configuration) http.Handler {
+	return &aggregator{config: *h, configuration: configuration}
+}
+
+type serviceRepresentation struct {
+	Status    string `json:"status"`
+	UpServers int    `json:"upServers"`
+	Servers   int    `json:"servers"`
+	Error     string `json:"error,omitempty"`
+}
+
+type healthRepresentation struct {
+	Status   string                           `json:"status"`
+	Services map[string]serviceRepresentation `json:"services"`
+}
+
+type aggregator struct {
+	config        Handler
+	configuration *runtime.Configuration
+}
+
+func (a *aggregator) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	result := healthRepresentation{
+		Status:   statusHealthy,
+		Services: make(map[string]serviceRepresentation, len(a.config.Services)),
+	}
+
+	var healthy int
+	for _, name := range a.config.Services {
+		service := a.serviceHealth(name)
+		if service.Status == statusHealthy {
+			healthy++
+		}
+		result.Services[name] = service
+	}
+
+	minHealthy := a.config.MinHealthyServices
+	if minHealthy <= 0 || minHealthy > len(a.config.Services) {
+		minHealthy = len(a.config.Services)
+	}
+
+	statusCode := http.StatusOK
+	if healthy < minHealthy {
+		result.Status = statusUnhealthy
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(rw).Encode(result); err != nil {
+		log.FromContext(req.Context()).Error(err)
+	}
+}
+
+func (a *aggregator) serviceHealth(name string) serviceRepresentation {
+	info, ok := a.configuration.Services[name]
+	if !ok || info == nil {
+		return serviceRepresentation{Status: statusUnhealthy, Error: "service not found"}
+	}
+
+	if info.Status == runtime.StatusDisabled {
+		return serviceRepresentation{Status: statusUnhealthy, Error: "service disabled"}
+	}
+
+	allStatus := info.GetAllStatus()
+
+	result := serviceRepresentation{Status: statusUnhealthy, Servers: len(allStatus)}
+	for _, status := range allStatus {
+		if status == serverUp {
+			result.UpServers++
+		}
+	}
+
+	minUp := a.config.MinUpServers
+	if minUp <= 0 {
+		minUp = 1
+	}
+
+	if result.UpServers >= minUp {
+		result.Status = statusHealthy
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServiceInfo(status string, servers map[string]string) *runtime.ServiceInfo {
+	info := &runtime.ServiceInfo{Status: status}
+	for server, serverStatus := range servers {
+		info.UpdateServerStatus(server, serverStatus)
+	}
+	return info
+}
+
+func TestHandler_CreateHandler(t *testing.T) {
+	configuration := &runtime.Configuration{
+		Services: map[string]*runtime.ServiceInfo{
+			"up@file": newServiceInfo(runtime.StatusEnabled, map[string]string{
+				"http://127.0.0.1": "UP",
+				"http://127.0.0.2": "UP",
+			}),
+			"degraded@file": newServiceInfo(runtime.StatusEnabled, map[string]string{
+				"http://127.0.0.3": "UP",
+				"http://127.0.0.4": "DOWN",
+			}),
+			"down@file": newServiceInfo(runtime.StatusEnabled, map[string]string{
+				"http://127.0.0.5": "DOWN",
+			}),
+			"disabled@file": newServiceInfo(runtime.StatusDisabled, map[string]string{
+				"http://127.0.0.6": "UP",
+			}),
+		},
+	}
+
+	testCases := []struct {
+		desc               string
+		handler            Handler
+		expectedStatusCode int
+		expectedServices   map[string]string
+	}{
+		{
+			desc:               "all services healthy",
+			handler:            Handler{Services: []string{"up@file", "degraded@file"}, MinUpServers: 1},
+			expectedStatusCode: http.StatusOK,
+			expectedServices:   map[string]string{"up@file": statusHealthy, "degraded@file": statusHealthy},
+		},
+		{
+			desc:               "not enough UP servers",
+			handler:            Handler{Services: []string{"up@file", "degraded@file"}, MinUpServers: 2},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedServices:   map[string]string{"up@file": statusHealthy, "degraded@file": statusUnhealthy},
+		},
+		{
+			desc:               "enough healthy services",
+			handler:            Handler{Services: []string{"up@file", "down@file"}, MinUpServers: 1, MinHealthyServices: 1},
+			expectedStatusCode: http.StatusOK,
+			expectedServices:   map[string]string{"up@file": statusHealthy, "down@file": statusUnhealthy},
+		},
+		{
+			desc:               "disabled and unknown services",
+			handler:            Handler{Services: []string{"disabled@file", "unknown@file"}, MinUpServers: 1, MinHealthyServices: 1},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedServices:   map[string]string{"disabled@file": statusUnhealthy, "unknown@file": statusUnhealthy},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rw := httptest.NewRecorder()
+			test.handler.CreateHandler(configuration).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			assert.Equal(t, test.expectedStatusCode, rw.Code)
+			assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+
+			var result healthRepresentation
+			require.NoError(t, json.NewDecoder(rw.Body).Decode(&result))
+
+			services := make(map[string]string)
+			for name, service := range result.Services {
+				services[name] = service.Status
+			}
+			assert.Equal(t, test.expectedServices, services)
+		})
+	}
+}
@@ -57,6 +57,14 @@ type Options struct {
 	Interval        time.Duration
 	Timeout         time.Duration
 	LB              Balancer
+	// Servers overrides the endpoint of the health check of the servers, keyed by server URL.
+	Servers map[string]ServerOptions
+}
+
+// ServerOptions are the health check options overridden for a server.
+type ServerOptions struct {
+	Path string
+	Port int
 }
 
 func (opt Options) String() string {
@@ -76,7 +84,17 @@ type BackendConfig struct {
 }
 
 func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
-	u, err := serverURL.Parse(b.Path)
+	path, port := b.Path, b.Port
+	if server, ok := b.Servers[serverURL.String()]; ok {
+		if server.Path != "" {
+			path = server.Path
+		}
+		if server.Port != 0 {
+			port = server.Port
+		}
+	}
+
+	u, err := serverURL.Parse(path)
 	if err != nil {
 		return nil, err
 	}
@@ -85,8 +103,8 @@ func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
 		u.Scheme = b.Scheme
 	}
 
-	if b.Port != 0 {
-		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(b.Port))
+	if port != 0 {
+		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(port))
 	}
 
 	return http.NewRequest(http.MethodGet, u.String(), http.NoBody)
@@ -158,6 +176,8 @@ func (hc *HealthCheck) checkBackend(ctx context.Context, backend *BackendConfig)
 				backend.name, disabledURL.url.String(), disabledURL.weight)
 			if err = backend.LB.UpsertServer(disabledURL.url, roundrobin.Weight(disabledURL.weight)); err != nil {
 				logger.Error(err)
+			} else {
+				notify(backend.name, disabledURL.url.String(), serverUp, nil)
 			}
 		} else {
 			logger.Warnf("Health check still failing. Backend: %q URL: %q Reason: %s", backend.name, disabledURL.url.String(), err)
@@ -178,8 +198,10 @@ func (hc *HealthCheck) checkBackend(ctx context.Context, backend *BackendConfig)
 				}
 			}
 			logger.Warnf("Health check failed, removing from server list. Backend: %q URL: %q Weight: %d Reason: %s", backend.name, enableURL.String(), weight, err)
-			if err := backend.LB.RemoveServer(enableURL); err != nil {
-				logger.Error(err)
+			if errRemove := backend.LB.RemoveServer(enableURL); errRemove != nil {
+				logger.Error(errRemove)
+			} else {
+				notify(backend.name, enableURL.String(), serverDown, err)
 			}
 			backend.disabledURLs = append(backend.disabledURLs, backendURL{enableURL, weight})
 		}
@@ -262,6 +262,49 @@ func TestNewRequest(t *testing.T) {
 				value: "",
 			},
 		},
+		{
+			desc:      "server path and port override",
+			serverURL: "http://backend1:80",
+			options: Options{
+				Path: "/health",
+				Port: 8080,
+				Servers: map[string]ServerOptions{
+					"http://backend1:80": {Path: "/status", Port: 9090},
+				},
+			},
+			expected: expected{
+				err:   false,
+				value: "http://backend1:9090/status",
+			},
+		},
+		{
+			desc:      "server port override",
+			serverURL: "http://backend1:80",
+			options: Options{
+				Path: "/health",
+				Servers: map[string]ServerOptions{
+					"http://backend1:80": {Port: 9090},
+				},
+			},
+			expected: expected{
+				err:   false,
+				value: "http://backend1:9090/health",
+			},
+		},
+		{
+			desc:      "override of another server",
+			serverURL: "http://backend1:80",
+			options: Options{
+				Path: "/health",
+				Servers: map[string]ServerOptions{
+					"http://backend2:80": {Path: "/status", Port: 9090},
+				},
+			},
+			expected: expected{
+				err:   false,
+				value: "http://backend1:80/health",
+			},
+		},
 	}
 
 	for _, test := range testCases {
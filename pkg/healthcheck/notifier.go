@@ -0,0 +1,140 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+// webhookQueueSize is the number of transitions waiting to be sent, beyond which they are dropped,
+// so that a slow webhook never delays the health checks.
+const webhookQueueSize = 100
+
+// Transition is a change of the status of a server, decided by the active health check.
+type Transition struct {
+	Service string    `json:"service"`
+	Server  string    `json:"server"`
+	Status  string    `json:"status"`
+	Reason  string    `json:"reason,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Notifier is notified of the transitions of the servers.
+// Notify is called from the health check goroutines, so it must not block.
+type Notifier interface {
+	Notify(transition Transition)
+}
+
+var (
+	notifierMu sync.RWMutex
+	notifier   Notifier
+)
+
+// SetNotifier sets the notifier of the transitions of the servers of all the services.
+func SetNotifier(n Notifier) {
+	notifierMu.Lock()
+	defer notifierMu.Unlock()
+
+	notifier = n
+}
+
+func notify(service, server, status string, reason error) {
+	notifierMu.RLock()
+	n := notifier
+	notifierMu.RUnlock()
+
+	if n == nil {
+		return
+	}
+
+	transition := Transition{
+		Service: service,
+		Server:  server,
+		Status:  status,
+		Time:    time.Now().UTC(),
+	}
+	if reason != nil {
+		transition.Reason = reason.Error()
+	}
+
+	n.Notify(transition)
+}
+
+// WebhookNotifier sends the transitions of the servers, as JSON, in POST requests to a URL.
+type WebhookNotifier struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	queue   chan Transition
+}
+
+// NewWebhookNotifier creates a notifier sending the transitions to the given URL, once it runs.
+func NewWebhookNotifier(url string, headers map[string]string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Timeout: timeout},
+		queue:   make(chan Transition, webhookQueueSize),
+	}
+}
+
+// Notify queues the transition, or drops it if too many transitions are already waiting.
+func (w *WebhookNotifier) Notify(transition Transition) {
+	select {
+	case w.queue <- transition:
+	default:
+		log.WithoutContext().Warnf("Health check webhook: dropping the transition of server %s of service %s to %s, too many transitions are waiting",
+			transition.Server, transition.Service, transition.Status)
+	}
+}
+
+// Run sends the queued transitions, in order, until the context is done.
+func (w *WebhookNotifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case transition := <-w.queue:
+			if err := w.send(ctx, transition); err != nil {
+				log.FromContext(ctx).Errorf("Health check webhook: unable to send the transition of server %s of service %s to %s: %v",
+					transition.Server, transition.Service, transition.Status, err)
+			}
+		}
+	}
+}
+
+func (w *WebhookNotifier) send(ctx context.Context, transition Transition) error {
+	body, err := json.Marshal(transition)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("received status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type transitionsRecorder []Transition
+
+func (r *transitionsRecorder) Notify(transition Transition) {
+	*r = append(*r, transition)
+}
+
+func TestNotify_tcp(t *testing.T) {
+	recorder := &transitionsRecorder{}
+	SetNotifier(recorder)
+	defer SetNotifier(nil)
+
+	listener := startTCPServer(t)
+	address := listener.Addr().String()
+
+	lb := &testTCPBalancer{status: map[string]bool{address: true}}
+	backend := NewTCPBackendConfig(TCPOptions{Timeout: time.Second}, "backend", lb, []string{address})
+
+	checkTCPBackend(context.Background(), backend)
+	assert.Empty(t, *recorder)
+
+	require.NoError(t, listener.Close())
+
+	checkTCPBackend(context.Background(), backend)
+	checkTCPBackend(context.Background(), backend)
+
+	listener, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	checkTCPBackend(context.Background(), backend)
+
+	require.Len(t, *recorder, 2)
+
+	down := (*recorder)[0]
+	assert.Equal(t, "backend", down.Service)
+	assert.Equal(t, address, down.Server)
+	assert.Equal(t, serverDown, down.Status)
+	assert.Contains(t, down.Reason, "connection failed")
+
+	up := (*recorder)[1]
+	assert.Equal(t, serverUp, up.Status)
+	assert.Empty(t, up.Reason)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	received := make(chan Transition, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+
+		var transition Transition
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&transition))
+		received <- transition
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, map[string]string{"Authorization": "Bearer token"}, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notifier.Run(ctx)
+
+	expected := Transition{
+		Service: "foo@file",
+		Server:  "http://10.0.0.1:80",
+		Status:  serverDown,
+		Reason:  "received error status code: 500",
+		Time:    time.Date(2020, time.May, 1, 12, 0, 0, 0, time.UTC),
+	}
+	notifier.Notify(expected)
+
+	select {
+	case transition := <-received:
+		assert.Equal(t, expected, transition)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the transition has not been sent")
+	}
+}
+
+func TestWebhookNotifier_queueFull(t *testing.T) {
+	notifier := NewWebhookNotifier("http://127.0.0.1", nil, time.Second)
+
+	// Without running the notifier, the transitions beyond the size of the queue are dropped, without blocking.
+	for i := 0; i < webhookQueueSize+10; i++ {
+		notifier.Notify(Transition{})
+	}
+
+	assert.Len(t, notifier.queue, webhookQueueSize)
+}
@@ -0,0 +1,234 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/safe"
+)
+
+var tcpSingleton *TCPHealthCheck
+var tcpOnce sync.Once
+
+// TCPBalancer is a TCP load-balancer whose servers, named after their addresses, can be marked up or down.
+type TCPBalancer interface {
+	SetStatus(name string, up bool) error
+}
+
+// TCPOptions are the health check options of a TCP service.
+type TCPOptions struct {
+	Interval time.Duration
+	Timeout  time.Duration
+	Send     string
+	Expect   string
+	TLS      bool
+}
+
+func (opt TCPOptions) String() string {
+	return fmt.Sprintf("[Interval: %s Timeout: %s Send: %q Expect: %q TLS: %t]", opt.Interval, opt.Timeout, opt.Send, opt.Expect, opt.TLS)
+}
+
+// TCPBackendConfig holds the health check configuration of a TCP service.
+type TCPBackendConfig struct {
+	TCPOptions
+	LB      TCPBalancer
+	name    string
+	servers []string
+	// down holds the addresses of the servers currently marked down.
+	down map[string]bool
+}
+
+// NewTCPBackendConfig creates the health check configuration of the servers of a TCP service.
+func NewTCPBackendConfig(options TCPOptions, backendName string, lb TCPBalancer, servers []string) *TCPBackendConfig {
+	return &TCPBackendConfig{
+		TCPOptions: options,
+		LB:         lb,
+		name:       backendName,
+		servers:    servers,
+		down:       make(map[string]bool),
+	}
+}
+
+// TCPHealthCheck runs the active health checks of the TCP services.
+type TCPHealthCheck struct {
+	Backends map[string]*TCPBackendConfig
+	cancel   context.CancelFunc
+}
+
+// GetTCPHealthCheck returns the TCP health check which is guaranteed to be a singleton.
+func GetTCPHealthCheck() *TCPHealthCheck {
+	tcpOnce.Do(func() {
+		tcpSingleton = &TCPHealthCheck{
+			Backends: make(map[string]*TCPBackendConfig),
+		}
+	})
+	return tcpSingleton
+}
+
+// SetBackendsConfiguration stops the running health checks, and starts the ones of the given backends.
+func (hc *TCPHealthCheck) SetBackendsConfiguration(parentCtx context.Context, backends map[string]*TCPBackendConfig) {
+	hc.Backends = backends
+	if hc.cancel != nil {
+		hc.cancel()
+	}
+	ctx, cancel := context.WithCancel(parentCtx)
+	hc.cancel = cancel
+
+	for _, backend := range backends {
+		currentBackend := backend
+		safe.Go(func() {
+			hc.execute(ctx, currentBackend)
+		})
+	}
+}
+
+func (hc *TCPHealthCheck) execute(ctx context.Context, backend *TCPBackendConfig) {
+	logger := log.FromContext(ctx)
+	logger.Debugf("Initial health check for TCP backend: %q", backend.name)
+
+	checkTCPBackend(ctx, backend)
+	ticker := time.NewTicker(backend.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Debugf("Stopping current health check goroutines of TCP backend: %s", backend.name)
+			return
+		case <-ticker.C:
+			logger.Debugf("Refreshing health check for TCP backend: %s", backend.name)
+			checkTCPBackend(ctx, backend)
+		}
+	}
+}
+
+func checkTCPBackend(ctx context.Context, backend *TCPBackendConfig) {
+	logger := log.FromContext(ctx)
+
+	for _, address := range backend.servers {
+		err := checkTCPHealth(address, backend.TCPOptions)
+
+		switch {
+		case err != nil && !backend.down[address]:
+			logger.Warnf("Health check failed, removing from server list. Backend: %q Address: %q Reason: %s", backend.name, address, err)
+			if errStatus := backend.LB.SetStatus(address, false); errStatus != nil {
+				logger.Error(errStatus)
+				continue
+			}
+			backend.down[address] = true
+			notify(backend.name, address, serverDown, err)
+
+		case err != nil:
+			logger.Warnf("Health check still failing. Backend: %q Address: %q Reason: %s", backend.name, address, err)
+
+		case backend.down[address]:
+			logger.Warnf("Health check up: Returning to server list. Backend: %q Address: %q", backend.name, address)
+			if errStatus := backend.LB.SetStatus(address, true); errStatus != nil {
+				logger.Error(errStatus)
+				continue
+			}
+			delete(backend.down, address)
+			notify(backend.name, address, serverUp, nil)
+		}
+	}
+}
+
+// checkTCPHealth returns a nil error in case it was successful and otherwise
+// a non-nil error with a meaningful description why the health check failed.
+func checkTCPHealth(address string, opts TCPOptions) error {
+	conn, err := net.DialTimeout("tcp", address, opts.Timeout)
+	if err != nil {
+		return fmt.Errorf("connection failed: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if opts.Timeout > 0 {
+		if err = conn.SetDeadline(time.Now().Add(opts.Timeout)); err != nil {
+			return err
+		}
+	}
+
+	if opts.TLS {
+		host, _, errSplit := net.SplitHostPort(address)
+		if errSplit != nil {
+			return errSplit
+		}
+
+		// Like the TCP proxy, which forwards the connections as they are, the check does not verify the certificates:
+		// it only checks that the server completes the handshake.
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+		if err = tlsConn.Handshake(); err != nil {
+			return fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	if opts.Send != "" {
+		if _, err = io.WriteString(conn, opts.Send); err != nil {
+			return fmt.Errorf("sending the payload failed: %w", err)
+		}
+	}
+
+	if opts.Expect != "" {
+		buf := make([]byte, len(opts.Expect))
+		if _, err = io.ReadFull(conn, buf); err != nil {
+			return fmt.Errorf("reading the response failed: %w", err)
+		}
+
+		if !bytes.Equal(buf, []byte(opts.Expect)) {
+			return errors.New("unexpected response")
+		}
+	}
+
+	return nil
+}
+
+// NewTCPLBStatusUpdater returns a new TCPLbStatusUpdater.
+func NewTCPLBStatusUpdater(lb TCPBalancer, info *runtime.TCPServiceInfo) *TCPLbStatusUpdater {
+	return &TCPLbStatusUpdater{
+		TCPBalancer: lb,
+		serviceInfo: info,
+	}
+}
+
+// TCPLbStatusUpdater wraps a TCPBalancer and a TCPServiceInfo,
+// so it can keep track of the status of a server in the TCPServiceInfo.
+type TCPLbStatusUpdater struct {
+	TCPBalancer
+	serviceInfo *runtime.TCPServiceInfo // can be nil
+}
+
+// SetStatus marks the given server as up or down in the TCPBalancer,
+// and updates the status of the server to "UP" or "DOWN".
+func (lb *TCPLbStatusUpdater) SetStatus(name string, up bool) error {
+	err := lb.TCPBalancer.SetStatus(name, up)
+	if err == nil && lb.serviceInfo != nil {
+		status := serverDown
+		if up {
+			status = serverUp
+		}
+		lb.serviceInfo.UpdateServerStatus(name, status)
+	}
+	return err
+}
+
+// TCPBalancers is a list of TCPBalancer(s) that implements the TCPBalancer interface.
+type TCPBalancers []TCPBalancer
+
+// SetStatus marks the given server as up or down in all the TCPBalancer(s).
+func (b TCPBalancers) SetStatus(name string, up bool) error {
+	for _, lb := range b {
+		if err := lb.SetStatus(name, up); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTCPServer starts a server answering "PONG" to the "PING" payloads.
+func startTCPServer(t *testing.T) net.Listener {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer func() { _ = conn.Close() }()
+
+				buf := make([]byte, 4)
+				if _, err := io.ReadFull(conn, buf); err != nil {
+					return
+				}
+				if string(buf) == "PING" {
+					_, _ = conn.Write([]byte("PONG"))
+				}
+			}()
+		}
+	}()
+
+	return listener
+}
+
+func TestCheckTCPHealth(t *testing.T) {
+	listener := startTCPServer(t)
+	defer func() { _ = listener.Close() }()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closed.Addr().String()
+	require.NoError(t, closed.Close())
+
+	testCases := []struct {
+		desc        string
+		address     string
+		opts        TCPOptions
+		expectError bool
+	}{
+		{
+			desc:    "connection only",
+			address: listener.Addr().String(),
+			opts:    TCPOptions{Timeout: time.Second},
+		},
+		{
+			desc:    "expected response",
+			address: listener.Addr().String(),
+			opts:    TCPOptions{Timeout: time.Second, Send: "PING", Expect: "PONG"},
+		},
+		{
+			desc:        "unexpected response",
+			address:     listener.Addr().String(),
+			opts:        TCPOptions{Timeout: time.Second, Send: "PING", Expect: "PANG"},
+			expectError: true,
+		},
+		{
+			desc:        "no response",
+			address:     listener.Addr().String(),
+			opts:        TCPOptions{Timeout: 100 * time.Millisecond, Send: "PIN", Expect: "PONG"},
+			expectError: true,
+		},
+		{
+			desc:        "connection refused",
+			address:     closedAddress,
+			opts:        TCPOptions{Timeout: time.Second},
+			expectError: true,
+		},
+		{
+			desc:        "no TLS",
+			address:     listener.Addr().String(),
+			opts:        TCPOptions{Timeout: 100 * time.Millisecond, TLS: true},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			// The subtests are not run in parallel, as they share the listener closed at the end of the test.
+			err := checkTCPHealth(test.address, test.opts)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type testTCPBalancer struct {
+	status map[string]bool
+}
+
+func (b *testTCPBalancer) SetStatus(name string, up bool) error {
+	if _, ok := b.status[name]; !ok {
+		return errors.New("server not found")
+	}
+	b.status[name] = up
+	return nil
+}
+
+func TestCheckTCPBackend(t *testing.T) {
+	listener := startTCPServer(t)
+	defer func() { _ = listener.Close() }()
+
+	address := listener.Addr().String()
+
+	lb := &testTCPBalancer{status: map[string]bool{address: true}}
+	backend := NewTCPBackendConfig(TCPOptions{Timeout: time.Second}, "backend", lb, []string{address})
+
+	checkTCPBackend(context.Background(), backend)
+	assert.True(t, lb.status[address])
+
+	require.NoError(t, listener.Close())
+
+	checkTCPBackend(context.Background(), backend)
+	assert.False(t, lb.status[address])
+
+	// The server comes back on the same address.
+	listener, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	checkTCPBackend(context.Background(), backend)
+	assert.True(t, lb.status[address])
+}
+
+func TestTCPLBStatusUpdater(t *testing.T) {
+	lb := &testTCPBalancer{status: map[string]bool{"127.0.0.1:80": true}}
+	svInfo := &runtime.TCPServiceInfo{}
+	lbsu := NewTCPLBStatusUpdater(lb, svInfo)
+
+	require.NoError(t, lbsu.SetStatus("127.0.0.1:80", false))
+	assert.Equal(t, map[string]string{"127.0.0.1:80": serverDown}, svInfo.GetAllStatus())
+
+	require.NoError(t, lbsu.SetStatus("127.0.0.1:80", true))
+	assert.Equal(t, map[string]string{"127.0.0.1:80": serverUp}, svInfo.GetAllStatus())
+
+	assert.Error(t, lbsu.SetStatus("127.0.0.1:81", true))
+	assert.Equal(t, map[string]string{"127.0.0.1:80": serverUp}, svInfo.GetAllStatus())
+}
@@ -0,0 +1,223 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/safe"
+)
+
+var udpSingleton *UDPHealthCheck
+var udpOnce sync.Once
+
+// UDPBalancer is a UDP load-balancer whose servers, named after their addresses, can be marked up or down.
+type UDPBalancer interface {
+	SetStatus(name string, up bool) error
+}
+
+// UDPOptions are the health check options of a UDP service.
+type UDPOptions struct {
+	Interval time.Duration
+	Timeout  time.Duration
+	Send     string
+	Expect   string
+}
+
+func (opt UDPOptions) String() string {
+	return fmt.Sprintf("[Interval: %s Timeout: %s Send: %q Expect: %q]", opt.Interval, opt.Timeout, opt.Send, opt.Expect)
+}
+
+// UDPBackendConfig holds the health check configuration of a UDP service.
+type UDPBackendConfig struct {
+	UDPOptions
+	LB      UDPBalancer
+	name    string
+	servers []string
+	// down holds the addresses of the servers currently marked down.
+	down map[string]bool
+}
+
+// NewUDPBackendConfig creates the health check configuration of the servers of a UDP service.
+func NewUDPBackendConfig(options UDPOptions, backendName string, lb UDPBalancer, servers []string) *UDPBackendConfig {
+	return &UDPBackendConfig{
+		UDPOptions: options,
+		LB:         lb,
+		name:       backendName,
+		servers:    servers,
+		down:       make(map[string]bool),
+	}
+}
+
+// UDPHealthCheck runs the active health checks of the UDP services.
+type UDPHealthCheck struct {
+	Backends map[string]*UDPBackendConfig
+	cancel   context.CancelFunc
+}
+
+// GetUDPHealthCheck returns the UDP health check which is guaranteed to be a singleton.
+func GetUDPHealthCheck() *UDPHealthCheck {
+	udpOnce.Do(func() {
+		udpSingleton = &UDPHealthCheck{
+			Backends: make(map[string]*UDPBackendConfig),
+		}
+	})
+	return udpSingleton
+}
+
+// SetBackendsConfiguration stops the running health checks, and starts the ones of the given backends.
+func (hc *UDPHealthCheck) SetBackendsConfiguration(parentCtx context.Context, backends map[string]*UDPBackendConfig) {
+	hc.Backends = backends
+	if hc.cancel != nil {
+		hc.cancel()
+	}
+	ctx, cancel := context.WithCancel(parentCtx)
+	hc.cancel = cancel
+
+	for _, backend := range backends {
+		currentBackend := backend
+		safe.Go(func() {
+			hc.execute(ctx, currentBackend)
+		})
+	}
+}
+
+func (hc *UDPHealthCheck) execute(ctx context.Context, backend *UDPBackendConfig) {
+	logger := log.FromContext(ctx)
+	logger.Debugf("Initial health check for UDP backend: %q", backend.name)
+
+	checkUDPBackend(ctx, backend)
+	ticker := time.NewTicker(backend.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Debugf("Stopping current health check goroutines of UDP backend: %s", backend.name)
+			return
+		case <-ticker.C:
+			logger.Debugf("Refreshing health check for UDP backend: %s", backend.name)
+			checkUDPBackend(ctx, backend)
+		}
+	}
+}
+
+func checkUDPBackend(ctx context.Context, backend *UDPBackendConfig) {
+	logger := log.FromContext(ctx)
+
+	for _, address := range backend.servers {
+		err := checkUDPHealth(address, backend.UDPOptions)
+
+		switch {
+		case err != nil && !backend.down[address]:
+			logger.Warnf("Health check failed, removing from server list. Backend: %q Address: %q Reason: %s", backend.name, address, err)
+			if errStatus := backend.LB.SetStatus(address, false); errStatus != nil {
+				logger.Error(errStatus)
+				continue
+			}
+			backend.down[address] = true
+			notify(backend.name, address, serverDown, err)
+
+		case err != nil:
+			logger.Warnf("Health check still failing. Backend: %q Address: %q Reason: %s", backend.name, address, err)
+
+		case backend.down[address]:
+			logger.Warnf("Health check up: Returning to server list. Backend: %q Address: %q", backend.name, address)
+			if errStatus := backend.LB.SetStatus(address, true); errStatus != nil {
+				logger.Error(errStatus)
+				continue
+			}
+			delete(backend.down, address)
+			notify(backend.name, address, serverUp, nil)
+		}
+	}
+}
+
+// checkUDPHealth returns a nil error in case it was successful and otherwise
+// a non-nil error with a meaningful description why the health check failed.
+// As UDP is connectionless, without an expected response the server is only deemed unhealthy
+// when its port is reported unreachable before the timeout.
+func checkUDPHealth(address string, opts UDPOptions) error {
+	conn, err := net.DialTimeout("udp", address, opts.Timeout)
+	if err != nil {
+		return fmt.Errorf("connection failed: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err = conn.SetDeadline(time.Now().Add(opts.Timeout)); err != nil {
+		return err
+	}
+
+	if _, err = conn.Write([]byte(opts.Send)); err != nil {
+		return fmt.Errorf("sending the payload failed: %w", err)
+	}
+
+	size := len(opts.Expect)
+	if size == 0 {
+		size = 1
+	}
+	buf := make([]byte, size)
+
+	n, err := conn.Read(buf)
+	if err != nil {
+		var netErr net.Error
+		if opts.Expect == "" && errors.As(err, &netErr) && netErr.Timeout() {
+			// No answer, but no unreachable port either.
+			return nil
+		}
+		return fmt.Errorf("reading the response failed: %w", err)
+	}
+
+	if opts.Expect != "" && !bytes.Equal(buf[:n], []byte(opts.Expect)) {
+		return errors.New("unexpected response")
+	}
+
+	return nil
+}
+
+// NewUDPLBStatusUpdater returns a new UDPLbStatusUpdater.
+func NewUDPLBStatusUpdater(lb UDPBalancer, info *runtime.UDPServiceInfo) *UDPLbStatusUpdater {
+	return &UDPLbStatusUpdater{
+		UDPBalancer: lb,
+		serviceInfo: info,
+	}
+}
+
+// UDPLbStatusUpdater wraps a UDPBalancer and a UDPServiceInfo,
+// so it can keep track of the status of a server in the UDPServiceInfo.
+type UDPLbStatusUpdater struct {
+	UDPBalancer
+	serviceInfo *runtime.UDPServiceInfo // can be nil
+}
+
+// SetStatus marks the given server as up or down in the UDPBalancer,
+// and updates the status of the server to "UP" or "DOWN".
+func (lb *UDPLbStatusUpdater) SetStatus(name string, up bool) error {
+	err := lb.UDPBalancer.SetStatus(name, up)
+	if err == nil && lb.serviceInfo != nil {
+		status := serverDown
+		if up {
+			status = serverUp
+		}
+		lb.serviceInfo.UpdateServerStatus(name, status)
+	}
+	return err
+}
+
+// UDPBalancers is a list of UDPBalancer(s) that implements the UDPBalancer interface.
+type UDPBalancers []UDPBalancer
+
+// SetStatus marks the given server as up or down in all the UDPBalancer(s).
+func (b UDPBalancers) SetStatus(name string, up bool) error {
+	for _, lb := range b {
+		if err := lb.SetStatus(name, up); err != nil {
+			return err
+		}
+	}
+	return nil
+}
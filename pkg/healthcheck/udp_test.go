@@ -0,0 +1,141 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startUDPServer starts a server answering "PONG" to the "PING" datagrams, and ignoring the other ones.
+func startUDPServer(t *testing.T) net.PacketConn {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			if string(buf[:n]) == "PING" {
+				_, _ = conn.WriteTo([]byte("PONG"), addr)
+			}
+		}
+	}()
+
+	return conn
+}
+
+func TestCheckUDPHealth(t *testing.T) {
+	server := startUDPServer(t)
+	defer func() { _ = server.Close() }()
+
+	closed, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closed.LocalAddr().String()
+	require.NoError(t, closed.Close())
+
+	testCases := []struct {
+		desc        string
+		address     string
+		opts        UDPOptions
+		expectError bool
+	}{
+		{
+			desc:    "reachability only",
+			address: server.LocalAddr().String(),
+			opts:    UDPOptions{Timeout: 100 * time.Millisecond},
+		},
+		{
+			desc:    "expected response",
+			address: server.LocalAddr().String(),
+			opts:    UDPOptions{Timeout: time.Second, Send: "PING", Expect: "PONG"},
+		},
+		{
+			desc:        "unexpected response",
+			address:     server.LocalAddr().String(),
+			opts:        UDPOptions{Timeout: time.Second, Send: "PING", Expect: "PANG"},
+			expectError: true,
+		},
+		{
+			desc:        "no response",
+			address:     server.LocalAddr().String(),
+			opts:        UDPOptions{Timeout: 100 * time.Millisecond, Send: "PIN", Expect: "PONG"},
+			expectError: true,
+		},
+		{
+			desc:        "port unreachable",
+			address:     closedAddress,
+			opts:        UDPOptions{Timeout: time.Second},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			// The subtests are not run in parallel, as they share the server closed at the end of the test.
+			err := checkUDPHealth(test.address, test.opts)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type testUDPBalancer struct {
+	status map[string]bool
+}
+
+func (b *testUDPBalancer) SetStatus(name string, up bool) error {
+	if _, ok := b.status[name]; !ok {
+		return errors.New("server not found")
+	}
+	b.status[name] = up
+	return nil
+}
+
+func TestCheckUDPBackend(t *testing.T) {
+	server := startUDPServer(t)
+	defer func() { _ = server.Close() }()
+
+	address := server.LocalAddr().String()
+
+	lb := &testUDPBalancer{status: map[string]bool{address: true}}
+	backend := NewUDPBackendConfig(UDPOptions{Timeout: time.Second, Send: "PING", Expect: "PONG"}, "backend", lb, []string{address})
+
+	checkUDPBackend(context.Background(), backend)
+	assert.True(t, lb.status[address])
+
+	require.NoError(t, server.Close())
+
+	checkUDPBackend(context.Background(), backend)
+	assert.False(t, lb.status[address])
+}
+
+func TestUDPLBStatusUpdater(t *testing.T) {
+	lb := &testUDPBalancer{status: map[string]bool{"127.0.0.1:53": true}}
+	svInfo := &runtime.UDPServiceInfo{}
+	lbsu := NewUDPLBStatusUpdater(lb, svInfo)
+
+	require.NoError(t, lbsu.SetStatus("127.0.0.1:53", false))
+	assert.Equal(t, map[string]string{"127.0.0.1:53": serverDown}, svInfo.GetAllStatus())
+
+	require.NoError(t, lbsu.SetStatus("127.0.0.1:53", true))
+	assert.Equal(t, map[string]string{"127.0.0.1:53": serverUp}, svInfo.GetAllStatus())
+
+	assert.Error(t, lbsu.SetStatus("127.0.0.1:54", true))
+	assert.Equal(t, map[string]string{"127.0.0.1:53": serverUp}, svInfo.GetAllStatus())
+}
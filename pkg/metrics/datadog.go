@@ -20,18 +20,49 @@ var datadogTicker *time.Ticker
 
 // Metric names consistent with https://github.com/DataDog/integrations-extras/pull/64
 const (
-	ddMetricsServiceReqsName      = "service.request.total"
-	ddMetricsServiceLatencyName   = "service.request.duration"
-	ddRetriesTotalName            = "service.retries.total"
-	ddConfigReloadsName           = "config.reload.total"
-	ddConfigReloadsFailureTagName = "failure"
-	ddLastConfigReloadSuccessName = "config.reload.lastSuccessTimestamp"
-	ddLastConfigReloadFailureName = "config.reload.lastFailureTimestamp"
-	ddEntryPointReqsName          = "entrypoint.request.total"
-	ddEntryPointReqDurationName   = "entrypoint.request.duration"
-	ddEntryPointOpenConnsName     = "entrypoint.connections.open"
-	ddOpenConnsName               = "service.connections.open"
-	ddServerUpName                = "service.server.up"
+	ddMetricsServiceReqsName              = "service.request.total"
+	ddMetricsServiceLatencyName           = "service.request.duration"
+	ddRetriesTotalName                    = "service.retries.total"
+	ddRetriesSucceededTotalName           = "service.retries.succeeded.total"
+	ddCircuitBreakerTransitionsName       = "service.circuitbreaker.transitions.total"
+	ddCircuitBreakerStateName             = "service.circuitbreaker.state"
+	ddShedReqsName                        = "service.request.shed.total"
+	ddConfigReloadsName                   = "config.reload.total"
+	ddConfigReloadsFailureTagName         = "failure"
+	ddLastConfigReloadSuccessName         = "config.reload.lastSuccessTimestamp"
+	ddLastConfigReloadFailureName         = "config.reload.lastFailureTimestamp"
+	ddOverloadedName                      = "overload.active"
+	ddOverloadMemoryName                  = "overload.memory"
+	ddOverloadShedReqsName                = "overload.request.shed.total"
+	ddRecoveredPanicsName                 = "panic.recovered.total"
+	ddEntryPointRateLimitedReqsName       = "entrypoint.request.ratelimited.total"
+	ddEntryPointReqsName                  = "entrypoint.request.total"
+	ddEntryPointReqDurationName           = "entrypoint.request.duration"
+	ddEntryPointOpenConnsName             = "entrypoint.connections.open"
+	ddEntryPointReqsBytesName             = "entrypoint.request.bytes.total"
+	ddEntryPointRespsBytesName            = "entrypoint.response.bytes.total"
+	ddEntryPointReqHeadersBytesName       = "entrypoint.request.headers.bytes"
+	ddEntryPointReqBodyBytesName          = "entrypoint.request.body.bytes"
+	ddEntryPointRespHeadersBytesName      = "entrypoint.response.headers.bytes"
+	ddEntryPointRespBodyBytesName         = "entrypoint.response.body.bytes"
+	ddEntryPointTLSHandshakeDurationName  = "entrypoint.tls.handshake.duration"
+	ddEntryPointTLSHandshakesName         = "entrypoint.tls.handshake.total"
+	ddEntryPointTLSHelloRetryRequestsName = "entrypoint.tls.helloretryrequest.total"
+	ddRouterReqsBytesName                 = "router.request.bytes.total"
+	ddRouterRespsBytesName                = "router.response.bytes.total"
+	ddOpenConnsName                       = "service.connections.open"
+	ddServerUpName                        = "service.server.up"
+	ddStaleConnsName                      = "service.connections.stale"
+	ddDrainingConnsName                   = "service.connections.draining"
+	ddProxyErrorsTotalName                = "service.proxy.errors.total"
+	ddServerOverrideReqsName              = "service.server.override.requests.total"
+	ddMiddlewareSkippedReqsName           = "service.middleware.skipped.requests.total"
+	ddTCPRouterOpenConnsName              = "tcp.router.connections.open"
+	ddTCPRouterReadBytesName              = "tcp.router.read.bytes.total"
+	ddTCPRouterWrittenBytesName           = "tcp.router.written.bytes.total"
+	ddTCPRouterConnDurationName           = "tcp.router.connection.duration"
+	ddTCPServiceOpenConnsName             = "tcp.service.connections.open"
+	ddTCPServiceServerOpenConnsName       = "tcp.service.server.connections.open"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -41,10 +72,15 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:         datadogClient.NewCounter(ddConfigReloadsName, 1.0),
-		configReloadsFailureCounter:  datadogClient.NewCounter(ddConfigReloadsName, 1.0).With(ddConfigReloadsFailureTagName, "true"),
-		lastConfigReloadSuccessGauge: datadogClient.NewGauge(ddLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge: datadogClient.NewGauge(ddLastConfigReloadFailureName),
+		configReloadsCounter:             datadogClient.NewCounter(ddConfigReloadsName, 1.0),
+		configReloadsFailureCounter:      datadogClient.NewCounter(ddConfigReloadsName, 1.0).With(ddConfigReloadsFailureTagName, "true"),
+		lastConfigReloadSuccessGauge:     datadogClient.NewGauge(ddLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:     datadogClient.NewGauge(ddLastConfigReloadFailureName),
+		overloadedGauge:                  datadogClient.NewGauge(ddOverloadedName),
+		overloadMemoryGauge:              datadogClient.NewGauge(ddOverloadMemoryName),
+		overloadShedReqsCounter:          datadogClient.NewCounter(ddOverloadShedReqsName, 1.0),
+		recoveredPanicsCounter:           datadogClient.NewCounter(ddRecoveredPanicsName, 1.0),
+		entryPointRateLimitedReqsCounter: datadogClient.NewCounter(ddEntryPointRateLimitedReqsName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -52,6 +88,25 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.entryPointReqsCounter = datadogClient.NewCounter(ddEntryPointReqsName, 1.0)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddEntryPointReqDurationName, 1.0), time.Second)
 		registry.entryPointOpenConnsGauge = datadogClient.NewGauge(ddEntryPointOpenConnsName)
+		registry.entryPointReqsBytesCounter = datadogClient.NewCounter(ddEntryPointReqsBytesName, 1.0)
+		registry.entryPointRespsBytesCounter = datadogClient.NewCounter(ddEntryPointRespsBytesName, 1.0)
+		registry.entryPointReqHeadersBytesHistogram = datadogClient.NewHistogram(ddEntryPointReqHeadersBytesName, 1.0)
+		registry.entryPointReqBodyBytesHistogram = datadogClient.NewHistogram(ddEntryPointReqBodyBytesName, 1.0)
+		registry.entryPointRespHeadersBytesHistogram = datadogClient.NewHistogram(ddEntryPointRespHeadersBytesName, 1.0)
+		registry.entryPointRespBodyBytesHistogram = datadogClient.NewHistogram(ddEntryPointRespBodyBytesName, 1.0)
+		registry.entryPointTLSHandshakeDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddEntryPointTLSHandshakeDurationName, 1.0), time.Second)
+		registry.entryPointTLSHandshakesCounter = datadogClient.NewCounter(ddEntryPointTLSHandshakesName, 1.0)
+		registry.entryPointTLSHelloRetryRequestsCounter = datadogClient.NewCounter(ddEntryPointTLSHelloRetryRequestsName, 1.0)
+	}
+
+	if config.AddRoutersLabels {
+		registry.routerEnabled = config.AddRoutersLabels
+		registry.routerReqsBytesCounter = datadogClient.NewCounter(ddRouterReqsBytesName, 1.0)
+		registry.routerRespsBytesCounter = datadogClient.NewCounter(ddRouterRespsBytesName, 1.0)
+		registry.tcpRouterOpenConnsGauge = datadogClient.NewGauge(ddTCPRouterOpenConnsName)
+		registry.tcpRouterReadBytesCounter = datadogClient.NewCounter(ddTCPRouterReadBytesName, 1.0)
+		registry.tcpRouterWrittenBytesCounter = datadogClient.NewCounter(ddTCPRouterWrittenBytesName, 1.0)
+		registry.tcpRouterConnDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddTCPRouterConnDurationName, 1.0), time.Second)
 	}
 
 	if config.AddServicesLabels {
@@ -59,8 +114,19 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceReqsCounter = datadogClient.NewCounter(ddMetricsServiceReqsName, 1.0)
 		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddMetricsServiceLatencyName, 1.0), time.Second)
 		registry.serviceRetriesCounter = datadogClient.NewCounter(ddRetriesTotalName, 1.0)
+		registry.serviceRetriesSucceededCounter = datadogClient.NewCounter(ddRetriesSucceededTotalName, 1.0)
+		registry.serviceCircuitBreakerTransitionsCounter = datadogClient.NewCounter(ddCircuitBreakerTransitionsName, 1.0)
+		registry.serviceCircuitBreakerStateGauge = datadogClient.NewGauge(ddCircuitBreakerStateName)
+		registry.serviceShedReqsCounter = datadogClient.NewCounter(ddShedReqsName, 1.0)
 		registry.serviceOpenConnsGauge = datadogClient.NewGauge(ddOpenConnsName)
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServerUpName)
+		registry.serviceStaleConnsGauge = datadogClient.NewGauge(ddStaleConnsName)
+		registry.serviceDrainingConnsGauge = datadogClient.NewGauge(ddDrainingConnsName)
+		registry.serviceProxyErrorsCounter = datadogClient.NewCounter(ddProxyErrorsTotalName, 1.0)
+		registry.serviceServerOverrideReqsCounter = datadogClient.NewCounter(ddServerOverrideReqsName, 1.0)
+		registry.serviceMiddlewareSkippedReqsCounter = datadogClient.NewCounter(ddMiddlewareSkippedReqsName, 1.0)
+		registry.tcpServiceOpenConnsGauge = datadogClient.NewGauge(ddTCPServiceOpenConnsName)
+		registry.tcpServiceServerOpenConnsGauge = datadogClient.NewGauge(ddTCPServiceServerOpenConnsName)
 	}
 
 	return registry
@@ -26,18 +26,49 @@ type influxDBWriter struct {
 var influxDBTicker *time.Ticker
 
 const (
-	influxDBMetricsServiceReqsName      = "traefik.service.requests.total"
-	influxDBMetricsServiceLatencyName   = "traefik.service.request.duration"
-	influxDBRetriesTotalName            = "traefik.service.retries.total"
-	influxDBConfigReloadsName           = "traefik.config.reload.total"
-	influxDBConfigReloadsFailureName    = influxDBConfigReloadsName + ".failure"
-	influxDBLastConfigReloadSuccessName = "traefik.config.reload.lastSuccessTimestamp"
-	influxDBLastConfigReloadFailureName = "traefik.config.reload.lastFailureTimestamp"
-	influxDBEntryPointReqsName          = "traefik.entrypoint.requests.total"
-	influxDBEntryPointReqDurationName   = "traefik.entrypoint.request.duration"
-	influxDBEntryPointOpenConnsName     = "traefik.entrypoint.connections.open"
-	influxDBOpenConnsName               = "traefik.service.connections.open"
-	influxDBServerUpName                = "traefik.service.server.up"
+	influxDBMetricsServiceReqsName              = "traefik.service.requests.total"
+	influxDBMetricsServiceLatencyName           = "traefik.service.request.duration"
+	influxDBRetriesTotalName                    = "traefik.service.retries.total"
+	influxDBRetriesSucceededTotalName           = "traefik.service.retries.succeeded.total"
+	influxDBCircuitBreakerTransitionsName       = "traefik.service.circuitbreaker.transitions.total"
+	influxDBCircuitBreakerStateName             = "traefik.service.circuitbreaker.state"
+	influxDBShedReqsName                        = "traefik.service.requests.shed.total"
+	influxDBConfigReloadsName                   = "traefik.config.reload.total"
+	influxDBConfigReloadsFailureName            = influxDBConfigReloadsName + ".failure"
+	influxDBLastConfigReloadSuccessName         = "traefik.config.reload.lastSuccessTimestamp"
+	influxDBLastConfigReloadFailureName         = "traefik.config.reload.lastFailureTimestamp"
+	influxDBOverloadedName                      = "traefik.overload.active"
+	influxDBOverloadMemoryName                  = "traefik.overload.memory"
+	influxDBOverloadShedReqsName                = "traefik.overload.requests.shed.total"
+	influxDBRecoveredPanicsName                 = "traefik.panic.recovered.total"
+	influxDBEntryPointRateLimitedReqsName       = "traefik.entrypoint.requests.ratelimited.total"
+	influxDBEntryPointReqsName                  = "traefik.entrypoint.requests.total"
+	influxDBEntryPointReqDurationName           = "traefik.entrypoint.request.duration"
+	influxDBEntryPointOpenConnsName             = "traefik.entrypoint.connections.open"
+	influxDBEntryPointReqsBytesName             = "traefik.entrypoint.requests.bytes.total"
+	influxDBEntryPointRespsBytesName            = "traefik.entrypoint.responses.bytes.total"
+	influxDBEntryPointReqHeadersBytesName       = "traefik.entrypoint.request.headers.bytes"
+	influxDBEntryPointReqBodyBytesName          = "traefik.entrypoint.request.body.bytes"
+	influxDBEntryPointRespHeadersBytesName      = "traefik.entrypoint.response.headers.bytes"
+	influxDBEntryPointRespBodyBytesName         = "traefik.entrypoint.response.body.bytes"
+	influxDBEntryPointTLSHandshakeDurationName  = "traefik.entrypoint.tls.handshake.duration"
+	influxDBEntryPointTLSHandshakesName         = "traefik.entrypoint.tls.handshakes.total"
+	influxDBEntryPointTLSHelloRetryRequestsName = "traefik.entrypoint.tls.helloretryrequests.total"
+	influxDBRouterReqsBytesName                 = "traefik.router.requests.bytes.total"
+	influxDBRouterRespsBytesName                = "traefik.router.responses.bytes.total"
+	influxDBOpenConnsName                       = "traefik.service.connections.open"
+	influxDBServerUpName                        = "traefik.service.server.up"
+	influxDBStaleConnsName                      = "traefik.service.connections.stale"
+	influxDBDrainingConnsName                   = "traefik.service.connections.draining"
+	influxDBProxyErrorsTotalName                = "traefik.service.proxy.errors.total"
+	influxDBServerOverrideReqsName              = "traefik.service.server.override.requests.total"
+	influxDBMiddlewareSkippedReqsName           = "traefik.service.middleware.skipped.requests.total"
+	influxDBTCPRouterOpenConnsName              = "traefik.tcp.router.connections.open"
+	influxDBTCPRouterReadBytesName              = "traefik.tcp.router.read.bytes.total"
+	influxDBTCPRouterWrittenBytesName           = "traefik.tcp.router.written.bytes.total"
+	influxDBTCPRouterConnDurationName           = "traefik.tcp.router.connection.duration"
+	influxDBTCPServiceOpenConnsName             = "traefik.tcp.service.connections.open"
+	influxDBTCPServiceServerOpenConnsName       = "traefik.tcp.service.server.connections.open"
 )
 
 const (
@@ -55,10 +86,15 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:         influxDBClient.NewCounter(influxDBConfigReloadsName),
-		configReloadsFailureCounter:  influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
-		lastConfigReloadSuccessGauge: influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge: influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
+		configReloadsCounter:             influxDBClient.NewCounter(influxDBConfigReloadsName),
+		configReloadsFailureCounter:      influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
+		lastConfigReloadSuccessGauge:     influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:     influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
+		overloadedGauge:                  influxDBClient.NewGauge(influxDBOverloadedName),
+		overloadMemoryGauge:              influxDBClient.NewGauge(influxDBOverloadMemoryName),
+		overloadShedReqsCounter:          influxDBClient.NewCounter(influxDBOverloadShedReqsName),
+		recoveredPanicsCounter:           influxDBClient.NewCounter(influxDBRecoveredPanicsName),
+		entryPointRateLimitedReqsCounter: influxDBClient.NewCounter(influxDBEntryPointRateLimitedReqsName),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -66,6 +102,25 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.entryPointReqsCounter = influxDBClient.NewCounter(influxDBEntryPointReqsName)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBEntryPointReqDurationName), time.Second)
 		registry.entryPointOpenConnsGauge = influxDBClient.NewGauge(influxDBEntryPointOpenConnsName)
+		registry.entryPointReqsBytesCounter = influxDBClient.NewCounter(influxDBEntryPointReqsBytesName)
+		registry.entryPointRespsBytesCounter = influxDBClient.NewCounter(influxDBEntryPointRespsBytesName)
+		registry.entryPointReqHeadersBytesHistogram = influxDBClient.NewHistogram(influxDBEntryPointReqHeadersBytesName)
+		registry.entryPointReqBodyBytesHistogram = influxDBClient.NewHistogram(influxDBEntryPointReqBodyBytesName)
+		registry.entryPointRespHeadersBytesHistogram = influxDBClient.NewHistogram(influxDBEntryPointRespHeadersBytesName)
+		registry.entryPointRespBodyBytesHistogram = influxDBClient.NewHistogram(influxDBEntryPointRespBodyBytesName)
+		registry.entryPointTLSHandshakeDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBEntryPointTLSHandshakeDurationName), time.Second)
+		registry.entryPointTLSHandshakesCounter = influxDBClient.NewCounter(influxDBEntryPointTLSHandshakesName)
+		registry.entryPointTLSHelloRetryRequestsCounter = influxDBClient.NewCounter(influxDBEntryPointTLSHelloRetryRequestsName)
+	}
+
+	if config.AddRoutersLabels {
+		registry.routerEnabled = config.AddRoutersLabels
+		registry.routerReqsBytesCounter = influxDBClient.NewCounter(influxDBRouterReqsBytesName)
+		registry.routerRespsBytesCounter = influxDBClient.NewCounter(influxDBRouterRespsBytesName)
+		registry.tcpRouterOpenConnsGauge = influxDBClient.NewGauge(influxDBTCPRouterOpenConnsName)
+		registry.tcpRouterReadBytesCounter = influxDBClient.NewCounter(influxDBTCPRouterReadBytesName)
+		registry.tcpRouterWrittenBytesCounter = influxDBClient.NewCounter(influxDBTCPRouterWrittenBytesName)
+		registry.tcpRouterConnDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBTCPRouterConnDurationName), time.Second)
 	}
 
 	if config.AddServicesLabels {
@@ -73,8 +128,19 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.serviceReqsCounter = influxDBClient.NewCounter(influxDBMetricsServiceReqsName)
 		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBMetricsServiceLatencyName), time.Second)
 		registry.serviceRetriesCounter = influxDBClient.NewCounter(influxDBRetriesTotalName)
+		registry.serviceRetriesSucceededCounter = influxDBClient.NewCounter(influxDBRetriesSucceededTotalName)
+		registry.serviceCircuitBreakerTransitionsCounter = influxDBClient.NewCounter(influxDBCircuitBreakerTransitionsName)
+		registry.serviceCircuitBreakerStateGauge = influxDBClient.NewGauge(influxDBCircuitBreakerStateName)
+		registry.serviceShedReqsCounter = influxDBClient.NewCounter(influxDBShedReqsName)
 		registry.serviceOpenConnsGauge = influxDBClient.NewGauge(influxDBOpenConnsName)
 		registry.serviceServerUpGauge = influxDBClient.NewGauge(influxDBServerUpName)
+		registry.serviceStaleConnsGauge = influxDBClient.NewGauge(influxDBStaleConnsName)
+		registry.serviceDrainingConnsGauge = influxDBClient.NewGauge(influxDBDrainingConnsName)
+		registry.serviceProxyErrorsCounter = influxDBClient.NewCounter(influxDBProxyErrorsTotalName)
+		registry.serviceServerOverrideReqsCounter = influxDBClient.NewCounter(influxDBServerOverrideReqsName)
+		registry.serviceMiddlewareSkippedReqsCounter = influxDBClient.NewCounter(influxDBMiddlewareSkippedReqsName)
+		registry.tcpServiceOpenConnsGauge = influxDBClient.NewGauge(influxDBTCPServiceOpenConnsName)
+		registry.tcpServiceServerOpenConnsGauge = influxDBClient.NewGauge(influxDBTCPServiceServerOpenConnsName)
 	}
 
 	return registry
@@ -14,6 +14,8 @@ type Registry interface {
 	IsEpEnabled() bool
 	// IsSvcEnabled shows whether metrics instrumentation is enabled on services.
 	IsSvcEnabled() bool
+	// IsRouterEnabled shows whether metrics instrumentation is enabled on routers.
+	IsRouterEnabled() bool
 
 	// server metrics
 	ConfigReloadsCounter() metrics.Counter
@@ -21,11 +23,41 @@ type Registry interface {
 	LastConfigReloadSuccessGauge() metrics.Gauge
 	LastConfigReloadFailureGauge() metrics.Gauge
 
+	// overload metrics
+	OverloadedGauge() metrics.Gauge
+	OverloadMemoryGauge() metrics.Gauge
+	OverloadShedReqsCounter() metrics.Counter
+
+	// panic metrics
+	RecoveredPanicsCounter() metrics.Counter
+
+	// entry point rate metrics
+	EntryPointRateLimitedReqsCounter() metrics.Counter
+
 	// entry point metrics
 	EntryPointReqsCounter() metrics.Counter
 	EntryPointReqsTLSCounter() metrics.Counter
 	EntryPointReqDurationHistogram() ScalableHistogram
 	EntryPointOpenConnsGauge() metrics.Gauge
+	EntryPointReqsBytesCounter() metrics.Counter
+	EntryPointRespsBytesCounter() metrics.Counter
+	EntryPointReqHeadersBytesHistogram() metrics.Histogram
+	EntryPointReqBodyBytesHistogram() metrics.Histogram
+	EntryPointRespHeadersBytesHistogram() metrics.Histogram
+	EntryPointRespBodyBytesHistogram() metrics.Histogram
+	EntryPointTLSHandshakeDurationHistogram() ScalableHistogram
+	EntryPointTLSHandshakesCounter() metrics.Counter
+	EntryPointTLSHelloRetryRequestsCounter() metrics.Counter
+
+	// router metrics
+	RouterReqsBytesCounter() metrics.Counter
+	RouterRespsBytesCounter() metrics.Counter
+
+	// TCP router metrics
+	TCPRouterOpenConnsGauge() metrics.Gauge
+	TCPRouterReadBytesCounter() metrics.Counter
+	TCPRouterWrittenBytesCounter() metrics.Counter
+	TCPRouterConnDurationHistogram() ScalableHistogram
 
 	// service metrics
 	ServiceReqsCounter() metrics.Counter
@@ -33,7 +65,20 @@ type Registry interface {
 	ServiceReqDurationHistogram() ScalableHistogram
 	ServiceOpenConnsGauge() metrics.Gauge
 	ServiceRetriesCounter() metrics.Counter
+	ServiceRetriesSucceededCounter() metrics.Counter
+	ServiceCircuitBreakerTransitionsCounter() metrics.Counter
+	ServiceCircuitBreakerStateGauge() metrics.Gauge
+	ServiceShedReqsCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
+	ServiceStaleConnsGauge() metrics.Gauge
+	ServiceDrainingConnsGauge() metrics.Gauge
+	ServiceProxyErrorsCounter() metrics.Counter
+	ServiceServerOverrideReqsCounter() metrics.Counter
+	ServiceMiddlewareSkippedReqsCounter() metrics.Counter
+
+	// TCP service metrics
+	TCPServiceOpenConnsGauge() metrics.Gauge
+	TCPServiceServerOpenConnsGauge() metrics.Gauge
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -50,16 +95,47 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var configReloadsFailureCounter []metrics.Counter
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var lastConfigReloadFailureGauge []metrics.Gauge
+	var overloadedGauge []metrics.Gauge
+	var overloadMemoryGauge []metrics.Gauge
+	var overloadShedReqsCounter []metrics.Counter
+	var recoveredPanicsCounter []metrics.Counter
+	var entryPointRateLimitedReqsCounter []metrics.Counter
 	var entryPointReqsCounter []metrics.Counter
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
 	var entryPointOpenConnsGauge []metrics.Gauge
+	var entryPointReqsBytesCounter []metrics.Counter
+	var entryPointRespsBytesCounter []metrics.Counter
+	var entryPointReqHeadersBytesHistogram []metrics.Histogram
+	var entryPointReqBodyBytesHistogram []metrics.Histogram
+	var entryPointRespHeadersBytesHistogram []metrics.Histogram
+	var entryPointRespBodyBytesHistogram []metrics.Histogram
+	var entryPointTLSHandshakeDurationHistogram []ScalableHistogram
+	var entryPointTLSHandshakesCounter []metrics.Counter
+	var entryPointTLSHelloRetryRequestsCounter []metrics.Counter
+	var routerReqsBytesCounter []metrics.Counter
+	var routerRespsBytesCounter []metrics.Counter
+	var tcpRouterOpenConnsGauge []metrics.Gauge
+	var tcpRouterReadBytesCounter []metrics.Counter
+	var tcpRouterWrittenBytesCounter []metrics.Counter
+	var tcpRouterConnDurationHistogram []ScalableHistogram
 	var serviceReqsCounter []metrics.Counter
 	var serviceReqsTLSCounter []metrics.Counter
 	var serviceReqDurationHistogram []ScalableHistogram
 	var serviceOpenConnsGauge []metrics.Gauge
 	var serviceRetriesCounter []metrics.Counter
+	var serviceRetriesSucceededCounter []metrics.Counter
+	var serviceCircuitBreakerTransitionsCounter []metrics.Counter
+	var serviceCircuitBreakerStateGauge []metrics.Gauge
+	var serviceShedReqsCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
+	var serviceStaleConnsGauge []metrics.Gauge
+	var serviceDrainingConnsGauge []metrics.Gauge
+	var serviceProxyErrorsCounter []metrics.Counter
+	var serviceServerOverrideReqsCounter []metrics.Counter
+	var serviceMiddlewareSkippedReqsCounter []metrics.Counter
+	var tcpServiceOpenConnsGauge []metrics.Gauge
+	var tcpServiceServerOpenConnsGauge []metrics.Gauge
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -74,6 +150,21 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.LastConfigReloadFailureGauge() != nil {
 			lastConfigReloadFailureGauge = append(lastConfigReloadFailureGauge, r.LastConfigReloadFailureGauge())
 		}
+		if r.OverloadedGauge() != nil {
+			overloadedGauge = append(overloadedGauge, r.OverloadedGauge())
+		}
+		if r.OverloadMemoryGauge() != nil {
+			overloadMemoryGauge = append(overloadMemoryGauge, r.OverloadMemoryGauge())
+		}
+		if r.OverloadShedReqsCounter() != nil {
+			overloadShedReqsCounter = append(overloadShedReqsCounter, r.OverloadShedReqsCounter())
+		}
+		if r.RecoveredPanicsCounter() != nil {
+			recoveredPanicsCounter = append(recoveredPanicsCounter, r.RecoveredPanicsCounter())
+		}
+		if r.EntryPointRateLimitedReqsCounter() != nil {
+			entryPointRateLimitedReqsCounter = append(entryPointRateLimitedReqsCounter, r.EntryPointRateLimitedReqsCounter())
+		}
 		if r.EntryPointReqsCounter() != nil {
 			entryPointReqsCounter = append(entryPointReqsCounter, r.EntryPointReqsCounter())
 		}
@@ -86,6 +177,51 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.EntryPointOpenConnsGauge() != nil {
 			entryPointOpenConnsGauge = append(entryPointOpenConnsGauge, r.EntryPointOpenConnsGauge())
 		}
+		if r.EntryPointReqsBytesCounter() != nil {
+			entryPointReqsBytesCounter = append(entryPointReqsBytesCounter, r.EntryPointReqsBytesCounter())
+		}
+		if r.EntryPointRespsBytesCounter() != nil {
+			entryPointRespsBytesCounter = append(entryPointRespsBytesCounter, r.EntryPointRespsBytesCounter())
+		}
+		if r.EntryPointReqHeadersBytesHistogram() != nil {
+			entryPointReqHeadersBytesHistogram = append(entryPointReqHeadersBytesHistogram, r.EntryPointReqHeadersBytesHistogram())
+		}
+		if r.EntryPointReqBodyBytesHistogram() != nil {
+			entryPointReqBodyBytesHistogram = append(entryPointReqBodyBytesHistogram, r.EntryPointReqBodyBytesHistogram())
+		}
+		if r.EntryPointRespHeadersBytesHistogram() != nil {
+			entryPointRespHeadersBytesHistogram = append(entryPointRespHeadersBytesHistogram, r.EntryPointRespHeadersBytesHistogram())
+		}
+		if r.EntryPointRespBodyBytesHistogram() != nil {
+			entryPointRespBodyBytesHistogram = append(entryPointRespBodyBytesHistogram, r.EntryPointRespBodyBytesHistogram())
+		}
+		if r.EntryPointTLSHandshakeDurationHistogram() != nil {
+			entryPointTLSHandshakeDurationHistogram = append(entryPointTLSHandshakeDurationHistogram, r.EntryPointTLSHandshakeDurationHistogram())
+		}
+		if r.EntryPointTLSHandshakesCounter() != nil {
+			entryPointTLSHandshakesCounter = append(entryPointTLSHandshakesCounter, r.EntryPointTLSHandshakesCounter())
+		}
+		if r.EntryPointTLSHelloRetryRequestsCounter() != nil {
+			entryPointTLSHelloRetryRequestsCounter = append(entryPointTLSHelloRetryRequestsCounter, r.EntryPointTLSHelloRetryRequestsCounter())
+		}
+		if r.RouterReqsBytesCounter() != nil {
+			routerReqsBytesCounter = append(routerReqsBytesCounter, r.RouterReqsBytesCounter())
+		}
+		if r.RouterRespsBytesCounter() != nil {
+			routerRespsBytesCounter = append(routerRespsBytesCounter, r.RouterRespsBytesCounter())
+		}
+		if r.TCPRouterOpenConnsGauge() != nil {
+			tcpRouterOpenConnsGauge = append(tcpRouterOpenConnsGauge, r.TCPRouterOpenConnsGauge())
+		}
+		if r.TCPRouterReadBytesCounter() != nil {
+			tcpRouterReadBytesCounter = append(tcpRouterReadBytesCounter, r.TCPRouterReadBytesCounter())
+		}
+		if r.TCPRouterWrittenBytesCounter() != nil {
+			tcpRouterWrittenBytesCounter = append(tcpRouterWrittenBytesCounter, r.TCPRouterWrittenBytesCounter())
+		}
+		if r.TCPRouterConnDurationHistogram() != nil {
+			tcpRouterConnDurationHistogram = append(tcpRouterConnDurationHistogram, r.TCPRouterConnDurationHistogram())
+		}
 		if r.ServiceReqsCounter() != nil {
 			serviceReqsCounter = append(serviceReqsCounter, r.ServiceReqsCounter())
 		}
@@ -101,48 +237,145 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceRetriesCounter() != nil {
 			serviceRetriesCounter = append(serviceRetriesCounter, r.ServiceRetriesCounter())
 		}
+		if r.ServiceRetriesSucceededCounter() != nil {
+			serviceRetriesSucceededCounter = append(serviceRetriesSucceededCounter, r.ServiceRetriesSucceededCounter())
+		}
+		if r.ServiceCircuitBreakerTransitionsCounter() != nil {
+			serviceCircuitBreakerTransitionsCounter = append(serviceCircuitBreakerTransitionsCounter, r.ServiceCircuitBreakerTransitionsCounter())
+		}
+		if r.ServiceCircuitBreakerStateGauge() != nil {
+			serviceCircuitBreakerStateGauge = append(serviceCircuitBreakerStateGauge, r.ServiceCircuitBreakerStateGauge())
+		}
+		if r.ServiceShedReqsCounter() != nil {
+			serviceShedReqsCounter = append(serviceShedReqsCounter, r.ServiceShedReqsCounter())
+		}
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
+		if r.ServiceStaleConnsGauge() != nil {
+			serviceStaleConnsGauge = append(serviceStaleConnsGauge, r.ServiceStaleConnsGauge())
+		}
+		if r.ServiceDrainingConnsGauge() != nil {
+			serviceDrainingConnsGauge = append(serviceDrainingConnsGauge, r.ServiceDrainingConnsGauge())
+		}
+		if r.ServiceProxyErrorsCounter() != nil {
+			serviceProxyErrorsCounter = append(serviceProxyErrorsCounter, r.ServiceProxyErrorsCounter())
+		}
+		if r.ServiceServerOverrideReqsCounter() != nil {
+			serviceServerOverrideReqsCounter = append(serviceServerOverrideReqsCounter, r.ServiceServerOverrideReqsCounter())
+		}
+		if r.ServiceMiddlewareSkippedReqsCounter() != nil {
+			serviceMiddlewareSkippedReqsCounter = append(serviceMiddlewareSkippedReqsCounter, r.ServiceMiddlewareSkippedReqsCounter())
+		}
+		if r.TCPServiceOpenConnsGauge() != nil {
+			tcpServiceOpenConnsGauge = append(tcpServiceOpenConnsGauge, r.TCPServiceOpenConnsGauge())
+		}
+		if r.TCPServiceServerOpenConnsGauge() != nil {
+			tcpServiceServerOpenConnsGauge = append(tcpServiceServerOpenConnsGauge, r.TCPServiceServerOpenConnsGauge())
+		}
 	}
 
 	return &standardRegistry{
-		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0,
-		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
-		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
-		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
-		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
-		lastConfigReloadFailureGauge:   multi.NewGauge(lastConfigReloadFailureGauge...),
-		entryPointReqsCounter:          multi.NewCounter(entryPointReqsCounter...),
-		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
-		entryPointReqDurationHistogram: NewMultiHistogram(entryPointReqDurationHistogram...),
-		entryPointOpenConnsGauge:       multi.NewGauge(entryPointOpenConnsGauge...),
-		serviceReqsCounter:             multi.NewCounter(serviceReqsCounter...),
-		serviceReqsTLSCounter:          multi.NewCounter(serviceReqsTLSCounter...),
-		serviceReqDurationHistogram:    NewMultiHistogram(serviceReqDurationHistogram...),
-		serviceOpenConnsGauge:          multi.NewGauge(serviceOpenConnsGauge...),
-		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
-		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
+		epEnabled:                               len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0 || len(entryPointReqsBytesCounter) > 0 || len(entryPointRespsBytesCounter) > 0 || len(entryPointReqHeadersBytesHistogram) > 0 || len(entryPointReqBodyBytesHistogram) > 0 || len(entryPointRespHeadersBytesHistogram) > 0 || len(entryPointRespBodyBytesHistogram) > 0 || len(entryPointTLSHandshakeDurationHistogram) > 0 || len(entryPointTLSHandshakesCounter) > 0 || len(entryPointTLSHelloRetryRequestsCounter) > 0,
+		routerEnabled:                           len(routerReqsBytesCounter) > 0 || len(routerRespsBytesCounter) > 0 || len(tcpRouterOpenConnsGauge) > 0 || len(tcpRouterReadBytesCounter) > 0 || len(tcpRouterWrittenBytesCounter) > 0 || len(tcpRouterConnDurationHistogram) > 0,
+		svcEnabled:                              len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceRetriesSucceededCounter) > 0 || len(serviceCircuitBreakerTransitionsCounter) > 0 || len(serviceCircuitBreakerStateGauge) > 0 || len(serviceShedReqsCounter) > 0 || len(serviceServerUpGauge) > 0 || len(serviceStaleConnsGauge) > 0 || len(serviceDrainingConnsGauge) > 0 || len(serviceProxyErrorsCounter) > 0 || len(serviceServerOverrideReqsCounter) > 0 || len(serviceMiddlewareSkippedReqsCounter) > 0 || len(tcpServiceOpenConnsGauge) > 0 || len(tcpServiceServerOpenConnsGauge) > 0,
+		configReloadsCounter:                    multi.NewCounter(configReloadsCounter...),
+		configReloadsFailureCounter:             multi.NewCounter(configReloadsFailureCounter...),
+		lastConfigReloadSuccessGauge:            multi.NewGauge(lastConfigReloadSuccessGauge...),
+		lastConfigReloadFailureGauge:            multi.NewGauge(lastConfigReloadFailureGauge...),
+		overloadedGauge:                         multi.NewGauge(overloadedGauge...),
+		overloadMemoryGauge:                     multi.NewGauge(overloadMemoryGauge...),
+		overloadShedReqsCounter:                 multi.NewCounter(overloadShedReqsCounter...),
+		recoveredPanicsCounter:                  multi.NewCounter(recoveredPanicsCounter...),
+		entryPointRateLimitedReqsCounter:        multi.NewCounter(entryPointRateLimitedReqsCounter...),
+		entryPointReqsCounter:                   multi.NewCounter(entryPointReqsCounter...),
+		entryPointReqsTLSCounter:                multi.NewCounter(entryPointReqsTLSCounter...),
+		entryPointReqDurationHistogram:          NewMultiHistogram(entryPointReqDurationHistogram...),
+		entryPointOpenConnsGauge:                multi.NewGauge(entryPointOpenConnsGauge...),
+		entryPointReqsBytesCounter:              multi.NewCounter(entryPointReqsBytesCounter...),
+		entryPointRespsBytesCounter:             multi.NewCounter(entryPointRespsBytesCounter...),
+		entryPointReqHeadersBytesHistogram:      multi.NewHistogram(entryPointReqHeadersBytesHistogram...),
+		entryPointReqBodyBytesHistogram:         multi.NewHistogram(entryPointReqBodyBytesHistogram...),
+		entryPointRespHeadersBytesHistogram:     multi.NewHistogram(entryPointRespHeadersBytesHistogram...),
+		entryPointRespBodyBytesHistogram:        multi.NewHistogram(entryPointRespBodyBytesHistogram...),
+		entryPointTLSHandshakeDurationHistogram: NewMultiHistogram(entryPointTLSHandshakeDurationHistogram...),
+		entryPointTLSHandshakesCounter:          multi.NewCounter(entryPointTLSHandshakesCounter...),
+		entryPointTLSHelloRetryRequestsCounter:  multi.NewCounter(entryPointTLSHelloRetryRequestsCounter...),
+		routerReqsBytesCounter:                  multi.NewCounter(routerReqsBytesCounter...),
+		routerRespsBytesCounter:                 multi.NewCounter(routerRespsBytesCounter...),
+		tcpRouterOpenConnsGauge:                 multi.NewGauge(tcpRouterOpenConnsGauge...),
+		tcpRouterReadBytesCounter:               multi.NewCounter(tcpRouterReadBytesCounter...),
+		tcpRouterWrittenBytesCounter:            multi.NewCounter(tcpRouterWrittenBytesCounter...),
+		tcpRouterConnDurationHistogram:          NewMultiHistogram(tcpRouterConnDurationHistogram...),
+		serviceReqsCounter:                      multi.NewCounter(serviceReqsCounter...),
+		serviceReqsTLSCounter:                   multi.NewCounter(serviceReqsTLSCounter...),
+		serviceReqDurationHistogram:             NewMultiHistogram(serviceReqDurationHistogram...),
+		serviceOpenConnsGauge:                   multi.NewGauge(serviceOpenConnsGauge...),
+		serviceRetriesCounter:                   multi.NewCounter(serviceRetriesCounter...),
+		serviceRetriesSucceededCounter:          multi.NewCounter(serviceRetriesSucceededCounter...),
+		serviceCircuitBreakerTransitionsCounter: multi.NewCounter(serviceCircuitBreakerTransitionsCounter...),
+		serviceCircuitBreakerStateGauge:         multi.NewGauge(serviceCircuitBreakerStateGauge...),
+		serviceShedReqsCounter:                  multi.NewCounter(serviceShedReqsCounter...),
+		serviceServerUpGauge:                    multi.NewGauge(serviceServerUpGauge...),
+		serviceStaleConnsGauge:                  multi.NewGauge(serviceStaleConnsGauge...),
+		serviceDrainingConnsGauge:               multi.NewGauge(serviceDrainingConnsGauge...),
+		serviceProxyErrorsCounter:               multi.NewCounter(serviceProxyErrorsCounter...),
+		serviceServerOverrideReqsCounter:        multi.NewCounter(serviceServerOverrideReqsCounter...),
+		serviceMiddlewareSkippedReqsCounter:     multi.NewCounter(serviceMiddlewareSkippedReqsCounter...),
+		tcpServiceOpenConnsGauge:                multi.NewGauge(tcpServiceOpenConnsGauge...),
+		tcpServiceServerOpenConnsGauge:          multi.NewGauge(tcpServiceServerOpenConnsGauge...),
 	}
 }
 
 type standardRegistry struct {
-	epEnabled                      bool
-	svcEnabled                     bool
-	configReloadsCounter           metrics.Counter
-	configReloadsFailureCounter    metrics.Counter
-	lastConfigReloadSuccessGauge   metrics.Gauge
-	lastConfigReloadFailureGauge   metrics.Gauge
-	entryPointReqsCounter          metrics.Counter
-	entryPointReqsTLSCounter       metrics.Counter
-	entryPointReqDurationHistogram ScalableHistogram
-	entryPointOpenConnsGauge       metrics.Gauge
-	serviceReqsCounter             metrics.Counter
-	serviceReqsTLSCounter          metrics.Counter
-	serviceReqDurationHistogram    ScalableHistogram
-	serviceOpenConnsGauge          metrics.Gauge
-	serviceRetriesCounter          metrics.Counter
-	serviceServerUpGauge           metrics.Gauge
+	epEnabled                               bool
+	svcEnabled                              bool
+	routerEnabled                           bool
+	configReloadsCounter                    metrics.Counter
+	configReloadsFailureCounter             metrics.Counter
+	lastConfigReloadSuccessGauge            metrics.Gauge
+	lastConfigReloadFailureGauge            metrics.Gauge
+	overloadedGauge                         metrics.Gauge
+	overloadMemoryGauge                     metrics.Gauge
+	overloadShedReqsCounter                 metrics.Counter
+	recoveredPanicsCounter                  metrics.Counter
+	entryPointRateLimitedReqsCounter        metrics.Counter
+	entryPointReqsCounter                   metrics.Counter
+	entryPointReqsTLSCounter                metrics.Counter
+	entryPointReqDurationHistogram          ScalableHistogram
+	entryPointOpenConnsGauge                metrics.Gauge
+	entryPointReqsBytesCounter              metrics.Counter
+	entryPointRespsBytesCounter             metrics.Counter
+	entryPointReqHeadersBytesHistogram      metrics.Histogram
+	entryPointReqBodyBytesHistogram         metrics.Histogram
+	entryPointRespHeadersBytesHistogram     metrics.Histogram
+	entryPointRespBodyBytesHistogram        metrics.Histogram
+	entryPointTLSHandshakeDurationHistogram ScalableHistogram
+	entryPointTLSHandshakesCounter          metrics.Counter
+	entryPointTLSHelloRetryRequestsCounter  metrics.Counter
+	routerReqsBytesCounter                  metrics.Counter
+	routerRespsBytesCounter                 metrics.Counter
+	tcpRouterOpenConnsGauge                 metrics.Gauge
+	tcpRouterReadBytesCounter               metrics.Counter
+	tcpRouterWrittenBytesCounter            metrics.Counter
+	tcpRouterConnDurationHistogram          ScalableHistogram
+	serviceReqsCounter                      metrics.Counter
+	serviceReqsTLSCounter                   metrics.Counter
+	serviceReqDurationHistogram             ScalableHistogram
+	serviceOpenConnsGauge                   metrics.Gauge
+	serviceRetriesCounter                   metrics.Counter
+	serviceRetriesSucceededCounter          metrics.Counter
+	serviceCircuitBreakerTransitionsCounter metrics.Counter
+	serviceCircuitBreakerStateGauge         metrics.Gauge
+	serviceShedReqsCounter                  metrics.Counter
+	serviceServerUpGauge                    metrics.Gauge
+	serviceStaleConnsGauge                  metrics.Gauge
+	serviceDrainingConnsGauge               metrics.Gauge
+	serviceProxyErrorsCounter               metrics.Counter
+	serviceServerOverrideReqsCounter        metrics.Counter
+	serviceMiddlewareSkippedReqsCounter     metrics.Counter
+	tcpServiceOpenConnsGauge                metrics.Gauge
+	tcpServiceServerOpenConnsGauge          metrics.Gauge
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -153,6 +386,10 @@ func (r *standardRegistry) IsSvcEnabled() bool {
 	return r.svcEnabled
 }
 
+func (r *standardRegistry) IsRouterEnabled() bool {
+	return r.routerEnabled
+}
+
 func (r *standardRegistry) ConfigReloadsCounter() metrics.Counter {
 	return r.configReloadsCounter
 }
@@ -169,6 +406,26 @@ func (r *standardRegistry) LastConfigReloadFailureGauge() metrics.Gauge {
 	return r.lastConfigReloadFailureGauge
 }
 
+func (r *standardRegistry) OverloadedGauge() metrics.Gauge {
+	return r.overloadedGauge
+}
+
+func (r *standardRegistry) OverloadMemoryGauge() metrics.Gauge {
+	return r.overloadMemoryGauge
+}
+
+func (r *standardRegistry) OverloadShedReqsCounter() metrics.Counter {
+	return r.overloadShedReqsCounter
+}
+
+func (r *standardRegistry) RecoveredPanicsCounter() metrics.Counter {
+	return r.recoveredPanicsCounter
+}
+
+func (r *standardRegistry) EntryPointRateLimitedReqsCounter() metrics.Counter {
+	return r.entryPointRateLimitedReqsCounter
+}
+
 func (r *standardRegistry) EntryPointReqsCounter() metrics.Counter {
 	return r.entryPointReqsCounter
 }
@@ -185,6 +442,66 @@ func (r *standardRegistry) EntryPointOpenConnsGauge() metrics.Gauge {
 	return r.entryPointOpenConnsGauge
 }
 
+func (r *standardRegistry) EntryPointReqsBytesCounter() metrics.Counter {
+	return r.entryPointReqsBytesCounter
+}
+
+func (r *standardRegistry) EntryPointRespsBytesCounter() metrics.Counter {
+	return r.entryPointRespsBytesCounter
+}
+
+func (r *standardRegistry) EntryPointReqHeadersBytesHistogram() metrics.Histogram {
+	return r.entryPointReqHeadersBytesHistogram
+}
+
+func (r *standardRegistry) EntryPointReqBodyBytesHistogram() metrics.Histogram {
+	return r.entryPointReqBodyBytesHistogram
+}
+
+func (r *standardRegistry) EntryPointRespHeadersBytesHistogram() metrics.Histogram {
+	return r.entryPointRespHeadersBytesHistogram
+}
+
+func (r *standardRegistry) EntryPointRespBodyBytesHistogram() metrics.Histogram {
+	return r.entryPointRespBodyBytesHistogram
+}
+
+func (r *standardRegistry) EntryPointTLSHandshakeDurationHistogram() ScalableHistogram {
+	return r.entryPointTLSHandshakeDurationHistogram
+}
+
+func (r *standardRegistry) EntryPointTLSHandshakesCounter() metrics.Counter {
+	return r.entryPointTLSHandshakesCounter
+}
+
+func (r *standardRegistry) EntryPointTLSHelloRetryRequestsCounter() metrics.Counter {
+	return r.entryPointTLSHelloRetryRequestsCounter
+}
+
+func (r *standardRegistry) RouterReqsBytesCounter() metrics.Counter {
+	return r.routerReqsBytesCounter
+}
+
+func (r *standardRegistry) RouterRespsBytesCounter() metrics.Counter {
+	return r.routerRespsBytesCounter
+}
+
+func (r *standardRegistry) TCPRouterOpenConnsGauge() metrics.Gauge {
+	return r.tcpRouterOpenConnsGauge
+}
+
+func (r *standardRegistry) TCPRouterReadBytesCounter() metrics.Counter {
+	return r.tcpRouterReadBytesCounter
+}
+
+func (r *standardRegistry) TCPRouterWrittenBytesCounter() metrics.Counter {
+	return r.tcpRouterWrittenBytesCounter
+}
+
+func (r *standardRegistry) TCPRouterConnDurationHistogram() ScalableHistogram {
+	return r.tcpRouterConnDurationHistogram
+}
+
 func (r *standardRegistry) ServiceReqsCounter() metrics.Counter {
 	return r.serviceReqsCounter
 }
@@ -205,10 +522,54 @@ func (r *standardRegistry) ServiceRetriesCounter() metrics.Counter {
 	return r.serviceRetriesCounter
 }
 
+func (r *standardRegistry) ServiceRetriesSucceededCounter() metrics.Counter {
+	return r.serviceRetriesSucceededCounter
+}
+
+func (r *standardRegistry) ServiceCircuitBreakerTransitionsCounter() metrics.Counter {
+	return r.serviceCircuitBreakerTransitionsCounter
+}
+
+func (r *standardRegistry) ServiceCircuitBreakerStateGauge() metrics.Gauge {
+	return r.serviceCircuitBreakerStateGauge
+}
+
+func (r *standardRegistry) ServiceShedReqsCounter() metrics.Counter {
+	return r.serviceShedReqsCounter
+}
+
 func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
 
+func (r *standardRegistry) ServiceStaleConnsGauge() metrics.Gauge {
+	return r.serviceStaleConnsGauge
+}
+
+func (r *standardRegistry) ServiceDrainingConnsGauge() metrics.Gauge {
+	return r.serviceDrainingConnsGauge
+}
+
+func (r *standardRegistry) ServiceProxyErrorsCounter() metrics.Counter {
+	return r.serviceProxyErrorsCounter
+}
+
+func (r *standardRegistry) ServiceServerOverrideReqsCounter() metrics.Counter {
+	return r.serviceServerOverrideReqsCounter
+}
+
+func (r *standardRegistry) ServiceMiddlewareSkippedReqsCounter() metrics.Counter {
+	return r.serviceMiddlewareSkippedReqsCounter
+}
+
+func (r *standardRegistry) TCPServiceOpenConnsGauge() metrics.Gauge {
+	return r.tcpServiceOpenConnsGauge
+}
+
+func (r *standardRegistry) TCPServiceServerOpenConnsGauge() metrics.Gauge {
+	return r.tcpServiceServerOpenConnsGauge
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
@@ -28,23 +28,68 @@ const (
 	configLastReloadSuccessName    = metricConfigPrefix + "last_reload_success"
 	configLastReloadFailureName    = metricConfigPrefix + "last_reload_failure"
 
+	// overload
+	metricOverloadPrefix      = MetricNamePrefix + "overload_"
+	overloadedName            = metricOverloadPrefix + "active"
+	overloadMemoryName        = metricOverloadPrefix + "memory_bytes"
+	overloadShedReqsTotalName = metricOverloadPrefix + "shed_requests_total"
+
+	// panics
+	recoveredPanicsTotalName = MetricNamePrefix + "recovered_panics_total"
+
 	// entry point
-	metricEntryPointPrefix     = MetricNamePrefix + "entrypoint_"
-	entryPointReqsTotalName    = metricEntryPointPrefix + "requests_total"
-	entryPointReqsTLSTotalName = metricEntryPointPrefix + "requests_tls_total"
-	entryPointReqDurationName  = metricEntryPointPrefix + "request_duration_seconds"
-	entryPointOpenConnsName    = metricEntryPointPrefix + "open_connections"
+	metricEntryPointPrefix                   = MetricNamePrefix + "entrypoint_"
+	entryPointReqsTotalName                  = metricEntryPointPrefix + "requests_total"
+	entryPointReqsTLSTotalName               = metricEntryPointPrefix + "requests_tls_total"
+	entryPointReqDurationName                = metricEntryPointPrefix + "request_duration_seconds"
+	entryPointOpenConnsName                  = metricEntryPointPrefix + "open_connections"
+	entryPointReqsBytesName                  = metricEntryPointPrefix + "requests_bytes_total"
+	entryPointRespsBytesName                 = metricEntryPointPrefix + "responses_bytes_total"
+	entryPointReqHeadersBytesName            = metricEntryPointPrefix + "request_headers_bytes"
+	entryPointReqBodyBytesName               = metricEntryPointPrefix + "request_body_bytes"
+	entryPointRespHeadersBytesName           = metricEntryPointPrefix + "response_headers_bytes"
+	entryPointRespBodyBytesName              = metricEntryPointPrefix + "response_body_bytes"
+	entryPointRateLimitedReqsName            = metricEntryPointPrefix + "rate_limited_requests_total"
+	entryPointTLSHandshakeDurationName       = metricEntryPointPrefix + "tls_handshake_duration_seconds"
+	entryPointTLSHandshakesTotalName         = metricEntryPointPrefix + "tls_handshakes_total"
+	entryPointTLSHelloRetryRequestsTotalName = metricEntryPointPrefix + "tls_hello_retry_requests_total"
+
+	// router level
+	metricRouterPrefix   = MetricNamePrefix + "router_"
+	routerReqsBytesName  = metricRouterPrefix + "requests_bytes_total"
+	routerRespsBytesName = metricRouterPrefix + "responses_bytes_total"
+
+	// TCP router level
+	metricTCPRouterPrefix     = MetricNamePrefix + "tcp_router_"
+	tcpRouterOpenConnsName    = metricTCPRouterPrefix + "open_connections"
+	tcpRouterReadBytesName    = metricTCPRouterPrefix + "read_bytes_total"
+	tcpRouterWrittenBytesName = metricTCPRouterPrefix + "written_bytes_total"
+	tcpRouterConnDurationName = metricTCPRouterPrefix + "connection_duration_seconds"
+
+	// TCP service level
+	metricTCPServicePrefix        = MetricNamePrefix + "tcp_service_"
+	tcpServiceOpenConnsName       = metricTCPServicePrefix + "open_connections"
+	tcpServiceServerOpenConnsName = metricTCPServicePrefix + "server_open_connections"
 
 	// service level.
 
 	// MetricServicePrefix prefix of all service metric names
-	MetricServicePrefix     = MetricNamePrefix + "service_"
-	serviceReqsTotalName    = MetricServicePrefix + "requests_total"
-	serviceReqsTLSTotalName = MetricServicePrefix + "requests_tls_total"
-	serviceReqDurationName  = MetricServicePrefix + "request_duration_seconds"
-	serviceOpenConnsName    = MetricServicePrefix + "open_connections"
-	serviceRetriesTotalName = MetricServicePrefix + "retries_total"
-	serviceServerUpName     = MetricServicePrefix + "server_up"
+	MetricServicePrefix                       = MetricNamePrefix + "service_"
+	serviceReqsTotalName                      = MetricServicePrefix + "requests_total"
+	serviceReqsTLSTotalName                   = MetricServicePrefix + "requests_tls_total"
+	serviceReqDurationName                    = MetricServicePrefix + "request_duration_seconds"
+	serviceOpenConnsName                      = MetricServicePrefix + "open_connections"
+	serviceRetriesTotalName                   = MetricServicePrefix + "retries_total"
+	serviceRetriesSucceededTotalName          = MetricServicePrefix + "retries_succeeded_total"
+	serviceCircuitBreakerTransitionsTotalName = MetricServicePrefix + "circuit_breaker_transitions_total"
+	serviceCircuitBreakerStateName            = MetricServicePrefix + "circuit_breaker_state"
+	serviceShedReqsTotalName                  = MetricServicePrefix + "shed_requests_total"
+	serviceServerUpName                       = MetricServicePrefix + "server_up"
+	serviceStaleConnsName                     = MetricServicePrefix + "stale_connections"
+	serviceDrainingConnsName                  = MetricServicePrefix + "draining_connections"
+	serviceProxyErrorsTotalName               = MetricServicePrefix + "proxy_errors_total"
+	serviceServerOverrideReqsTotalName        = MetricServicePrefix + "server_override_requests_total"
+	serviceMiddlewareSkippedReqsTotalName     = MetricServicePrefix + "middleware_skipped_requests_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -97,6 +142,12 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		buckets = config.Buckets
 	}
 
+	// From 256B to 1MB, which is the default maximum size of the request headers.
+	sizeBuckets := stdprometheus.ExponentialBuckets(256, 4, 7)
+
+	// From 1ms to 1s, as the TLS handshakes are expected to be much faster than the requests.
+	handshakeBuckets := []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
 	safe.Go(func() {
 		promState.ListenValueUpdates()
 	})
@@ -118,20 +169,53 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Help: "Last config reload failure",
 	}, []string{})
 
+	overloaded := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: overloadedName,
+		Help: "Traefik is shedding the load, its memory usage being above the limit, described by gauge value of 0 or 1.",
+	}, []string{})
+	overloadMemory := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: overloadMemoryName,
+		Help: "Memory usage of Traefik, compared to the overload memory limit.",
+	}, []string{})
+	overloadShedReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: overloadShedReqsTotalName,
+		Help: "How many HTTP requests were rejected on an entrypoint while Traefik was overloaded.",
+	}, []string{"entrypoint"})
+
+	recoveredPanics := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: recoveredPanicsTotalName,
+		Help: "How many panics were recovered while serving HTTP requests, partitioned by entrypoint and by middleware.",
+	}, []string{"entrypoint", "middleware"})
+	entryPointRateLimitedReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: entryPointRateLimitedReqsName,
+		Help: "How many HTTP requests were rejected on an entrypoint, for exceeding its maximum request rate.",
+	}, []string{"entrypoint"})
+
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
 		configReloadsFailures.cv.Describe,
 		lastConfigReloadSuccess.gv.Describe,
 		lastConfigReloadFailure.gv.Describe,
+		overloaded.gv.Describe,
+		overloadMemory.gv.Describe,
+		overloadShedReqs.cv.Describe,
+		recoveredPanics.cv.Describe,
+		entryPointRateLimitedReqs.cv.Describe,
 	}
 
 	reg := &standardRegistry{
-		epEnabled:                    config.AddEntryPointsLabels,
-		svcEnabled:                   config.AddServicesLabels,
-		configReloadsCounter:         configReloads,
-		configReloadsFailureCounter:  configReloadsFailures,
-		lastConfigReloadSuccessGauge: lastConfigReloadSuccess,
-		lastConfigReloadFailureGauge: lastConfigReloadFailure,
+		epEnabled:                        config.AddEntryPointsLabels,
+		svcEnabled:                       config.AddServicesLabels,
+		routerEnabled:                    config.AddRoutersLabels,
+		configReloadsCounter:             configReloads,
+		configReloadsFailureCounter:      configReloadsFailures,
+		lastConfigReloadSuccessGauge:     lastConfigReloadSuccess,
+		lastConfigReloadFailureGauge:     lastConfigReloadFailure,
+		overloadedGauge:                  overloaded,
+		overloadMemoryGauge:              overloadMemory,
+		overloadShedReqsCounter:          overloadShedReqs,
+		recoveredPanicsCounter:           recoveredPanics,
+		entryPointRateLimitedReqsCounter: entryPointRateLimitedReqs,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -152,17 +236,119 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: entryPointOpenConnsName,
 			Help: "How many open connections exist on an entrypoint, partitioned by method and protocol.",
 		}, []string{"method", "protocol", "entrypoint"})
+		entryPointReqsBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: entryPointReqsBytesName,
+			Help: "How many bytes of request bodies were received on an entrypoint.",
+		}, []string{"entrypoint"})
+		entryPointRespsBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: entryPointRespsBytesName,
+			Help: "How many bytes of response bodies were sent on an entrypoint.",
+		}, []string{"entrypoint"})
+		entryPointReqHeadersBytes := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    entryPointReqHeadersBytesName,
+			Help:    "How many bytes of request headers were received on an entrypoint, including the request line.",
+			Buckets: sizeBuckets,
+		}, []string{"entrypoint"})
+		entryPointReqBodyBytes := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    entryPointReqBodyBytesName,
+			Help:    "How many bytes of request body were received on an entrypoint.",
+			Buckets: sizeBuckets,
+		}, []string{"entrypoint"})
+		entryPointRespHeadersBytes := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    entryPointRespHeadersBytesName,
+			Help:    "How many bytes of response headers were sent on an entrypoint, including the status line.",
+			Buckets: sizeBuckets,
+		}, []string{"entrypoint"})
+		entryPointRespBodyBytes := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    entryPointRespBodyBytesName,
+			Help:    "How many bytes of response body were sent on an entrypoint.",
+			Buckets: sizeBuckets,
+		}, []string{"entrypoint"})
+		entryPointTLSHandshakeDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    entryPointTLSHandshakeDurationName,
+			Help:    "How long it took to complete the TLS handshakes on an entrypoint, partitioned by TLS version.",
+			Buckets: handshakeBuckets,
+		}, []string{"tls_version", "entrypoint"})
+		entryPointTLSHandshakes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: entryPointTLSHandshakesTotalName,
+			Help: "How many TLS handshakes were completed on an entrypoint, partitioned by TLS version and session resumption.",
+		}, []string{"tls_version", "resumption", "entrypoint"})
+		entryPointTLSHelloRetryRequests := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: entryPointTLSHelloRetryRequestsTotalName,
+			Help: "How many HelloRetryRequests were sent during the TLS handshakes on an entrypoint.",
+		}, []string{"entrypoint"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			entryPointReqs.cv.Describe,
 			entryPointReqsTLS.cv.Describe,
 			entryPointReqDurations.hv.Describe,
 			entryPointOpenConns.gv.Describe,
+			entryPointReqsBytes.cv.Describe,
+			entryPointRespsBytes.cv.Describe,
+			entryPointReqHeadersBytes.hv.Describe,
+			entryPointReqBodyBytes.hv.Describe,
+			entryPointRespHeadersBytes.hv.Describe,
+			entryPointRespBodyBytes.hv.Describe,
+			entryPointTLSHandshakeDurations.hv.Describe,
+			entryPointTLSHandshakes.cv.Describe,
+			entryPointTLSHelloRetryRequests.cv.Describe,
 		}...)
 		reg.entryPointReqsCounter = entryPointReqs
 		reg.entryPointReqsTLSCounter = entryPointReqsTLS
 		reg.entryPointReqDurationHistogram, _ = NewHistogramWithScale(entryPointReqDurations, time.Second)
 		reg.entryPointOpenConnsGauge = entryPointOpenConns
+		reg.entryPointReqsBytesCounter = entryPointReqsBytes
+		reg.entryPointRespsBytesCounter = entryPointRespsBytes
+		reg.entryPointReqHeadersBytesHistogram = entryPointReqHeadersBytes
+		reg.entryPointReqBodyBytesHistogram = entryPointReqBodyBytes
+		reg.entryPointRespHeadersBytesHistogram = entryPointRespHeadersBytes
+		reg.entryPointRespBodyBytesHistogram = entryPointRespBodyBytes
+		reg.entryPointTLSHandshakeDurationHistogram, _ = NewHistogramWithScale(entryPointTLSHandshakeDurations, time.Second)
+		reg.entryPointTLSHandshakesCounter = entryPointTLSHandshakes
+		reg.entryPointTLSHelloRetryRequestsCounter = entryPointTLSHelloRetryRequests
+	}
+	if config.AddRoutersLabels {
+		routerReqsBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: routerReqsBytesName,
+			Help: "How many bytes of request bodies were received on a router.",
+		}, []string{"router"})
+		routerRespsBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: routerRespsBytesName,
+			Help: "How many bytes of response bodies were sent on a router.",
+		}, []string{"router"})
+
+		tcpRouterOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: tcpRouterOpenConnsName,
+			Help: "How many TCP connections are open on a TCP router.",
+		}, []string{"router"})
+		tcpRouterReadBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpRouterReadBytesName,
+			Help: "How many bytes were read from the clients on a TCP router.",
+		}, []string{"router"})
+		tcpRouterWrittenBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpRouterWrittenBytesName,
+			Help: "How many bytes were written to the clients on a TCP router.",
+		}, []string{"router"})
+		tcpRouterConnDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    tcpRouterConnDurationName,
+			Help:    "How long the TCP connections lasted on a TCP router.",
+			Buckets: buckets,
+		}, []string{"router"})
+
+		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
+			routerReqsBytes.cv.Describe,
+			routerRespsBytes.cv.Describe,
+			tcpRouterOpenConns.gv.Describe,
+			tcpRouterReadBytes.cv.Describe,
+			tcpRouterWrittenBytes.cv.Describe,
+			tcpRouterConnDurations.hv.Describe,
+		}...)
+		reg.routerReqsBytesCounter = routerReqsBytes
+		reg.routerRespsBytesCounter = routerRespsBytes
+		reg.tcpRouterOpenConnsGauge = tcpRouterOpenConns
+		reg.tcpRouterReadBytesCounter = tcpRouterReadBytes
+		reg.tcpRouterWrittenBytesCounter = tcpRouterWrittenBytes
+		reg.tcpRouterConnDurationHistogram, _ = NewHistogramWithScale(tcpRouterConnDurations, time.Second)
 	}
 	if config.AddServicesLabels {
 		serviceReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
@@ -186,10 +372,54 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: serviceRetriesTotalName,
 			Help: "How many request retries happened on a service.",
 		}, []string{"service"})
+		serviceRetriesSucceeded := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceRetriesSucceededTotalName,
+			Help: "How many retried requests eventually succeeded on a service.",
+		}, []string{"service"})
+		serviceCircuitBreakerTransitions := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceCircuitBreakerTransitionsTotalName,
+			Help: "How many times the circuit breakers in front of a service changed their state, partitioned by server URL, if per server, and new state.",
+		}, []string{"service", "server", "state"})
+		serviceCircuitBreakerState := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceCircuitBreakerStateName,
+			Help: "The state of the circuit breakers in front of a service, partitioned by server URL, if per server: 0 for standby, 1 for recovering, and 2 for tripped.",
+		}, []string{"service", "server"})
+		serviceShedReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceShedReqsTotalName,
+			Help: "How many requests to a service were rejected by the inFlightReq and rateLimit middlewares, partitioned by middleware type.",
+		}, []string{"service", "type"})
 		serviceServerUp := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 			Name: serviceServerUpName,
 			Help: "service server is up, described by gauge value of 0 or 1.",
 		}, []string{"service", "url"})
+		serviceStaleConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceStaleConnsName,
+			Help: "How many connections to the servers are still open to an IP address their hostname no longer resolves to, partitioned by hostname.",
+		}, []string{"host"})
+		serviceDrainingConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceDrainingConnsName,
+			Help: "How many connections of the servers transports replaced by a configuration reload are still open.",
+		}, []string{})
+		serviceProxyErrors := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceProxyErrorsTotalName,
+			Help: "How many requests to a service failed with a 502 or 504 generated by Traefik, partitioned by cause.",
+		}, []string{"service", "cause"})
+		serviceServerOverrideReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceServerOverrideReqsTotalName,
+			Help: "How many requests to a service were pinned to a server by the server override header, partitioned by server URL.",
+		}, []string{"service", "url"})
+		serviceMiddlewareSkippedReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceMiddlewareSkippedReqsTotalName,
+			Help: "How many requests to a service skipped an optional middleware whose dependency was unavailable, partitioned by middleware.",
+		}, []string{"service", "middleware"})
+		tcpServiceOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: tcpServiceOpenConnsName,
+			Help: "How many TCP connections are open to the servers of a TCP service.",
+		}, []string{"service"})
+		tcpServiceServerOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: tcpServiceServerOpenConnsName,
+			Help: "How many TCP connections are open to a server of a TCP service, partitioned by server address.",
+		}, []string{"service", "address"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			serviceReqs.cv.Describe,
@@ -197,7 +427,18 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceReqDurations.hv.Describe,
 			serviceOpenConns.gv.Describe,
 			serviceRetries.cv.Describe,
+			serviceRetriesSucceeded.cv.Describe,
+			serviceCircuitBreakerTransitions.cv.Describe,
+			serviceCircuitBreakerState.gv.Describe,
+			serviceShedReqs.cv.Describe,
 			serviceServerUp.gv.Describe,
+			serviceStaleConns.gv.Describe,
+			serviceDrainingConns.gv.Describe,
+			serviceProxyErrors.cv.Describe,
+			serviceServerOverrideReqs.cv.Describe,
+			serviceMiddlewareSkippedReqs.cv.Describe,
+			tcpServiceOpenConns.gv.Describe,
+			tcpServiceServerOpenConns.gv.Describe,
 		}...)
 
 		reg.serviceReqsCounter = serviceReqs
@@ -205,7 +446,18 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceReqDurationHistogram, _ = NewHistogramWithScale(serviceReqDurations, time.Second)
 		reg.serviceOpenConnsGauge = serviceOpenConns
 		reg.serviceRetriesCounter = serviceRetries
+		reg.serviceRetriesSucceededCounter = serviceRetriesSucceeded
+		reg.serviceCircuitBreakerTransitionsCounter = serviceCircuitBreakerTransitions
+		reg.serviceCircuitBreakerStateGauge = serviceCircuitBreakerState
+		reg.serviceShedReqsCounter = serviceShedReqs
 		reg.serviceServerUpGauge = serviceServerUp
+		reg.serviceStaleConnsGauge = serviceStaleConns
+		reg.serviceDrainingConnsGauge = serviceDrainingConns
+		reg.serviceProxyErrorsCounter = serviceProxyErrors
+		reg.serviceServerOverrideReqsCounter = serviceServerOverrideReqs
+		reg.serviceMiddlewareSkippedReqsCounter = serviceMiddlewareSkippedReqs
+		reg.tcpServiceOpenConnsGauge = tcpServiceOpenConns
+		reg.tcpServiceServerOpenConnsGauge = tcpServiceServerOpenConns
 	}
 
 	return reg
@@ -246,6 +498,21 @@ func OnConfigurationUpdate(conf dynamic.Configuration, entryPoints []string) {
 		}
 	}
 
+	if conf.TCP != nil {
+		for name := range conf.TCP.Routers {
+			dynamicConfig.routers[name] = true
+		}
+
+		for serviceName, service := range conf.TCP.Services {
+			dynamicConfig.services[serviceName] = make(map[string]bool)
+			if service.LoadBalancer != nil {
+				for _, server := range service.LoadBalancer.Servers {
+					dynamicConfig.services[serviceName][server.Address] = true
+				}
+			}
+		}
+	}
+
 	promState.SetDynamicConfig(dynamicConfig)
 }
 
@@ -321,6 +588,10 @@ func (ps *prometheusState) isOutdated(collector *collector) bool {
 		return true
 	}
 
+	if routerName, ok := labels["router"]; ok && !ps.dynamicConfig.hasRouter(routerName) {
+		return true
+	}
+
 	if serviceName, ok := labels["service"]; ok {
 		if !ps.dynamicConfig.hasService(serviceName) {
 			return true
@@ -328,6 +599,9 @@ func (ps *prometheusState) isOutdated(collector *collector) bool {
 		if url, ok := labels["url"]; ok && !ps.dynamicConfig.hasServerURL(serviceName, url) {
 			return true
 		}
+		if address, ok := labels["address"]; ok && !ps.dynamicConfig.hasServerURL(serviceName, address) {
+			return true
+		}
 	}
 
 	return false
@@ -356,6 +630,11 @@ func (d *dynamicConfig) hasEntryPoint(entrypointName string) bool {
 	return ok
 }
 
+func (d *dynamicConfig) hasRouter(routerName string) bool {
+	_, ok := d.routers[routerName]
+	return ok
+}
+
 func (d *dynamicConfig) hasService(serviceName string) bool {
 	_, ok := d.services[serviceName]
 	return ok
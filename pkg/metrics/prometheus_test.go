@@ -104,10 +104,10 @@ func TestPrometheus(t *testing.T) {
 	// Reset state of global promState.
 	defer promState.reset()
 
-	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true})
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true, AddRoutersLabels: true})
 	defer promRegistry.Unregister(promState)
 
-	if !prometheusRegistry.IsEpEnabled() || !prometheusRegistry.IsSvcEnabled() {
+	if !prometheusRegistry.IsEpEnabled() || !prometheusRegistry.IsSvcEnabled() || !prometheusRegistry.IsRouterEnabled() {
 		t.Errorf("PrometheusRegistry should return true for IsEnabled()")
 	}
 
@@ -128,6 +128,76 @@ func TestPrometheus(t *testing.T) {
 		EntryPointOpenConnsGauge().
 		With("method", http.MethodGet, "protocol", "http", "entrypoint", "http").
 		Set(1)
+	prometheusRegistry.
+		EntryPointReqsBytesCounter().
+		With("entrypoint", "http").
+		Add(10)
+	prometheusRegistry.
+		EntryPointRespsBytesCounter().
+		With("entrypoint", "http").
+		Add(20)
+	prometheusRegistry.
+		EntryPointReqHeadersBytesHistogram().
+		With("entrypoint", "http").
+		Observe(512)
+	prometheusRegistry.
+		EntryPointReqBodyBytesHistogram().
+		With("entrypoint", "http").
+		Observe(512)
+	prometheusRegistry.
+		EntryPointRespHeadersBytesHistogram().
+		With("entrypoint", "http").
+		Observe(512)
+	prometheusRegistry.
+		EntryPointRespBodyBytesHistogram().
+		With("entrypoint", "http").
+		Observe(512)
+	prometheusRegistry.
+		EntryPointTLSHandshakeDurationHistogram().
+		With("tls_version", "1.3", "entrypoint", "https").
+		Observe(0.01)
+	prometheusRegistry.
+		EntryPointTLSHandshakesCounter().
+		With("tls_version", "1.3", "resumption", "psk", "entrypoint", "https").
+		Add(1)
+	prometheusRegistry.
+		EntryPointTLSHelloRetryRequestsCounter().
+		With("entrypoint", "https").
+		Add(1)
+
+	prometheusRegistry.
+		RouterReqsBytesCounter().
+		With("router", "demo").
+		Add(10)
+	prometheusRegistry.
+		RouterRespsBytesCounter().
+		With("router", "demo").
+		Add(20)
+
+	prometheusRegistry.
+		TCPRouterOpenConnsGauge().
+		With("router", "tcp-demo").
+		Set(1)
+	prometheusRegistry.
+		TCPRouterReadBytesCounter().
+		With("router", "tcp-demo").
+		Add(10)
+	prometheusRegistry.
+		TCPRouterWrittenBytesCounter().
+		With("router", "tcp-demo").
+		Add(20)
+	prometheusRegistry.
+		TCPRouterConnDurationHistogram().
+		With("router", "tcp-demo").
+		Observe(2)
+	prometheusRegistry.
+		TCPServiceOpenConnsGauge().
+		With("service", "tcp-service1").
+		Set(1)
+	prometheusRegistry.
+		TCPServiceServerOpenConnsGauge().
+		With("service", "tcp-service1", "address", "127.0.0.10:80").
+		Set(1)
 
 	prometheusRegistry.
 		ServiceReqsCounter().
@@ -145,10 +215,45 @@ func TestPrometheus(t *testing.T) {
 		ServiceRetriesCounter().
 		With("service", "service1").
 		Add(1)
+	prometheusRegistry.
+		ServiceRetriesSucceededCounter().
+		With("service", "service1").
+		Add(1)
+	prometheusRegistry.
+		ServiceCircuitBreakerTransitionsCounter().
+		With("service", "service1", "server", "http://127.0.0.10:80", "state", "tripped").
+		Add(1)
+	prometheusRegistry.
+		ServiceCircuitBreakerStateGauge().
+		With("service", "service1", "server", "http://127.0.0.10:80").
+		Set(2)
+	prometheusRegistry.
+		ServiceShedReqsCounter().
+		With("service", "service1", "type", "ratelimit").
+		Add(1)
 	prometheusRegistry.
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
+	prometheusRegistry.
+		ServiceStaleConnsGauge().
+		With("host", "backend.local").
+		Set(2)
+	prometheusRegistry.
+		ServiceDrainingConnsGauge().
+		Set(3)
+	prometheusRegistry.
+		ServiceProxyErrorsCounter().
+		With("service", "service1", "cause", "dial_timeout").
+		Add(1)
+	prometheusRegistry.
+		ServiceServerOverrideReqsCounter().
+		With("service", "service1", "url", "http://127.0.0.10:80").
+		Add(1)
+	prometheusRegistry.
+		ServiceMiddlewareSkippedReqsCounter().
+		With("service", "service1", "middleware", "auth@file").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -204,6 +309,129 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, entryPointOpenConnsName, 1),
 		},
+		{
+			name: entryPointReqsBytesName,
+			labels: map[string]string{
+				"entrypoint": "http",
+			},
+			assert: buildCounterAssert(t, entryPointReqsBytesName, 10),
+		},
+		{
+			name: entryPointRespsBytesName,
+			labels: map[string]string{
+				"entrypoint": "http",
+			},
+			assert: buildCounterAssert(t, entryPointRespsBytesName, 20),
+		},
+		{
+			name: entryPointReqHeadersBytesName,
+			labels: map[string]string{
+				"entrypoint": "http",
+			},
+			assert: buildHistogramAssert(t, entryPointReqHeadersBytesName, 1),
+		},
+		{
+			name: entryPointReqBodyBytesName,
+			labels: map[string]string{
+				"entrypoint": "http",
+			},
+			assert: buildHistogramAssert(t, entryPointReqBodyBytesName, 1),
+		},
+		{
+			name: entryPointRespHeadersBytesName,
+			labels: map[string]string{
+				"entrypoint": "http",
+			},
+			assert: buildHistogramAssert(t, entryPointRespHeadersBytesName, 1),
+		},
+		{
+			name: entryPointRespBodyBytesName,
+			labels: map[string]string{
+				"entrypoint": "http",
+			},
+			assert: buildHistogramAssert(t, entryPointRespBodyBytesName, 1),
+		},
+		{
+			name: entryPointTLSHandshakeDurationName,
+			labels: map[string]string{
+				"tls_version": "1.3",
+				"entrypoint":  "https",
+			},
+			assert: buildHistogramAssert(t, entryPointTLSHandshakeDurationName, 1),
+		},
+		{
+			name: entryPointTLSHandshakesTotalName,
+			labels: map[string]string{
+				"tls_version": "1.3",
+				"resumption":  "psk",
+				"entrypoint":  "https",
+			},
+			assert: buildCounterAssert(t, entryPointTLSHandshakesTotalName, 1),
+		},
+		{
+			name: entryPointTLSHelloRetryRequestsTotalName,
+			labels: map[string]string{
+				"entrypoint": "https",
+			},
+			assert: buildCounterAssert(t, entryPointTLSHelloRetryRequestsTotalName, 1),
+		},
+		{
+			name: routerReqsBytesName,
+			labels: map[string]string{
+				"router": "demo",
+			},
+			assert: buildCounterAssert(t, routerReqsBytesName, 10),
+		},
+		{
+			name: routerRespsBytesName,
+			labels: map[string]string{
+				"router": "demo",
+			},
+			assert: buildCounterAssert(t, routerRespsBytesName, 20),
+		},
+		{
+			name: tcpRouterOpenConnsName,
+			labels: map[string]string{
+				"router": "tcp-demo",
+			},
+			assert: buildGaugeAssert(t, tcpRouterOpenConnsName, 1),
+		},
+		{
+			name: tcpRouterReadBytesName,
+			labels: map[string]string{
+				"router": "tcp-demo",
+			},
+			assert: buildCounterAssert(t, tcpRouterReadBytesName, 10),
+		},
+		{
+			name: tcpRouterWrittenBytesName,
+			labels: map[string]string{
+				"router": "tcp-demo",
+			},
+			assert: buildCounterAssert(t, tcpRouterWrittenBytesName, 20),
+		},
+		{
+			name: tcpRouterConnDurationName,
+			labels: map[string]string{
+				"router": "tcp-demo",
+			},
+			assert: buildHistogramAssert(t, tcpRouterConnDurationName, 1),
+		},
+		{
+			name: tcpServiceOpenConnsName,
+			labels: map[string]string{
+				"service": "tcp-service1",
+			},
+			assert: buildGaugeAssert(t, tcpServiceOpenConnsName, 1),
+		},
+		{
+			name: tcpServiceServerOpenConnsName,
+			labels: map[string]string{
+				"service": "tcp-service1",
+				"address": "127.0.0.10:80",
+			},
+			assert: buildGaugeAssert(t, tcpServiceServerOpenConnsName, 1),
+		},
 		{
 			name: serviceReqsTotalName,
 			labels: map[string]string{
@@ -240,6 +468,38 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGreaterThanCounterAssert(t, serviceRetriesTotalName, 1),
 		},
+		{
+			name: serviceRetriesSucceededTotalName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildGreaterThanCounterAssert(t, serviceRetriesSucceededTotalName, 1),
+		},
+		{
+			name: serviceCircuitBreakerTransitionsTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"server":  "http://127.0.0.10:80",
+				"state":   "tripped",
+			},
+			assert: buildGreaterThanCounterAssert(t, serviceCircuitBreakerTransitionsTotalName, 1),
+		},
+		{
+			name: serviceCircuitBreakerStateName,
+			labels: map[string]string{
+				"service": "service1",
+				"server":  "http://127.0.0.10:80",
+			},
+			assert: buildGaugeAssert(t, serviceCircuitBreakerStateName, 2),
+		},
+		{
+			name: serviceShedReqsTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"type":    "ratelimit",
+			},
+			assert: buildGreaterThanCounterAssert(t, serviceShedReqsTotalName, 1),
+		},
 		{
 			name: serviceServerUpName,
 			labels: map[string]string{
@@ -248,6 +508,41 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, serviceServerUpName, 1),
 		},
+		{
+			name: serviceStaleConnsName,
+			labels: map[string]string{
+				"host": "backend.local",
+			},
+			assert: buildGaugeAssert(t, serviceStaleConnsName, 2),
+		},
+		{
+			name:   serviceDrainingConnsName,
+			assert: buildGaugeAssert(t, serviceDrainingConnsName, 3),
+		},
+		{
+			name: serviceProxyErrorsTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"cause":   "dial_timeout",
+			},
+			assert: buildCounterAssert(t, serviceProxyErrorsTotalName, 1),
+		},
+		{
+			name: serviceServerOverrideReqsTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"url":     "http://127.0.0.10:80",
+			},
+			assert: buildCounterAssert(t, serviceServerOverrideReqsTotalName, 1),
+		},
+		{
+			name: serviceMiddlewareSkippedReqsTotalName,
+			labels: map[string]string{
+				"service":    "service1",
+				"middleware": "auth@file",
+			},
+			assert: buildCounterAssert(t, serviceMiddlewareSkippedReqsTotalName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -278,7 +573,7 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 	// Reset state of global promState.
 	defer promState.reset()
 
-	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true})
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true, AddRoutersLabels: true})
 	defer promRegistry.Unregister(promState)
 
 	conf := dynamic.Configuration{
@@ -315,11 +610,15 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://localhost:9999").
 		Set(1)
+	prometheusRegistry.
+		RouterReqsBytesCounter().
+		With("router", "foo2@providerName").
+		Add(1)
 
 	delayForTrackingCompletion()
 
-	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName, serviceReqsTotalName, serviceServerUpName)
-	assertMetricsAbsent(t, mustScrape(), entryPointReqsTotalName, serviceReqsTotalName, serviceServerUpName)
+	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName, serviceReqsTotalName, serviceServerUpName, routerReqsBytesName)
+	assertMetricsAbsent(t, mustScrape(), entryPointReqsTotalName, serviceReqsTotalName, serviceServerUpName, routerReqsBytesName)
 
 	// To verify that metrics belonging to active configurations are not removed
 	// here the counter examples.
@@ -327,11 +626,15 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 		EntryPointReqsCounter().
 		With("entrypoint", "entrypoint1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
 		Add(1)
+	prometheusRegistry.
+		RouterReqsBytesCounter().
+		With("router", "foo@providerName").
+		Add(1)
 
 	delayForTrackingCompletion()
 
-	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName)
-	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName)
+	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName, routerReqsBytesName)
+	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName, routerReqsBytesName)
 }
 
 func TestPrometheusRemovedMetricsReset(t *testing.T) {
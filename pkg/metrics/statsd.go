@@ -15,18 +15,49 @@ var statsdClient *statsd.Statsd
 var statsdTicker *time.Ticker
 
 const (
-	statsdMetricsServiceReqsName      = "service.request.total"
-	statsdMetricsServiceLatencyName   = "service.request.duration"
-	statsdRetriesTotalName            = "service.retries.total"
-	statsdConfigReloadsName           = "config.reload.total"
-	statsdConfigReloadsFailureName    = statsdConfigReloadsName + ".failure"
-	statsdLastConfigReloadSuccessName = "config.reload.lastSuccessTimestamp"
-	statsdLastConfigReloadFailureName = "config.reload.lastFailureTimestamp"
-	statsdEntryPointReqsName          = "entrypoint.request.total"
-	statsdEntryPointReqDurationName   = "entrypoint.request.duration"
-	statsdEntryPointOpenConnsName     = "entrypoint.connections.open"
-	statsdOpenConnsName               = "service.connections.open"
-	statsdServerUpName                = "service.server.up"
+	statsdMetricsServiceReqsName              = "service.request.total"
+	statsdMetricsServiceLatencyName           = "service.request.duration"
+	statsdRetriesTotalName                    = "service.retries.total"
+	statsdRetriesSucceededTotalName           = "service.retries.succeeded.total"
+	statsdCircuitBreakerTransitionsName       = "service.circuitbreaker.transitions.total"
+	statsdCircuitBreakerStateName             = "service.circuitbreaker.state"
+	statsdShedReqsName                        = "service.request.shed.total"
+	statsdConfigReloadsName                   = "config.reload.total"
+	statsdConfigReloadsFailureName            = statsdConfigReloadsName + ".failure"
+	statsdLastConfigReloadSuccessName         = "config.reload.lastSuccessTimestamp"
+	statsdLastConfigReloadFailureName         = "config.reload.lastFailureTimestamp"
+	statsdOverloadedName                      = "overload.active"
+	statsdOverloadMemoryName                  = "overload.memory"
+	statsdOverloadShedReqsName                = "overload.request.shed.total"
+	statsdRecoveredPanicsName                 = "panic.recovered.total"
+	statsdEntryPointRateLimitedReqsName       = "entrypoint.request.ratelimited.total"
+	statsdEntryPointReqsName                  = "entrypoint.request.total"
+	statsdEntryPointReqDurationName           = "entrypoint.request.duration"
+	statsdEntryPointOpenConnsName             = "entrypoint.connections.open"
+	statsdEntryPointReqsBytesName             = "entrypoint.request.bytes.total"
+	statsdEntryPointRespsBytesName            = "entrypoint.response.bytes.total"
+	statsdEntryPointReqHeadersBytesName       = "entrypoint.request.headers.bytes"
+	statsdEntryPointReqBodyBytesName          = "entrypoint.request.body.bytes"
+	statsdEntryPointRespHeadersBytesName      = "entrypoint.response.headers.bytes"
+	statsdEntryPointRespBodyBytesName         = "entrypoint.response.body.bytes"
+	statsdEntryPointTLSHandshakeDurationName  = "entrypoint.tls.handshake.duration"
+	statsdEntryPointTLSHandshakesName         = "entrypoint.tls.handshake.total"
+	statsdEntryPointTLSHelloRetryRequestsName = "entrypoint.tls.helloretryrequest.total"
+	statsdRouterReqsBytesName                 = "router.request.bytes.total"
+	statsdRouterRespsBytesName                = "router.response.bytes.total"
+	statsdOpenConnsName                       = "service.connections.open"
+	statsdServerUpName                        = "service.server.up"
+	statsdStaleConnsName                      = "service.connections.stale"
+	statsdDrainingConnsName                   = "service.connections.draining"
+	statsdProxyErrorsTotalName                = "service.proxy.errors.total"
+	statsdServerOverrideReqsName              = "service.server.override.requests.total"
+	statsdMiddlewareSkippedReqsName           = "service.middleware.skipped.requests.total"
+	statsdTCPRouterOpenConnsName              = "tcp.router.connections.open"
+	statsdTCPRouterReadBytesName              = "tcp.router.read.bytes.total"
+	statsdTCPRouterWrittenBytesName           = "tcp.router.written.bytes.total"
+	statsdTCPRouterConnDurationName           = "tcp.router.connection.duration"
+	statsdTCPServiceOpenConnsName             = "tcp.service.connections.open"
+	statsdTCPServiceServerOpenConnsName       = "tcp.service.server.connections.open"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -46,10 +77,15 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:         statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
-		configReloadsFailureCounter:  statsdClient.NewCounter(statsdConfigReloadsFailureName, 1.0),
-		lastConfigReloadSuccessGauge: statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge: statsdClient.NewGauge(statsdLastConfigReloadFailureName),
+		configReloadsCounter:             statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
+		configReloadsFailureCounter:      statsdClient.NewCounter(statsdConfigReloadsFailureName, 1.0),
+		lastConfigReloadSuccessGauge:     statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:     statsdClient.NewGauge(statsdLastConfigReloadFailureName),
+		overloadedGauge:                  statsdClient.NewGauge(statsdOverloadedName),
+		overloadMemoryGauge:              statsdClient.NewGauge(statsdOverloadMemoryName),
+		overloadShedReqsCounter:          statsdClient.NewCounter(statsdOverloadShedReqsName, 1.0),
+		recoveredPanicsCounter:           statsdClient.NewCounter(statsdRecoveredPanicsName, 1.0),
+		entryPointRateLimitedReqsCounter: statsdClient.NewCounter(statsdEntryPointRateLimitedReqsName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -57,6 +93,25 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.entryPointReqsCounter = statsdClient.NewCounter(statsdEntryPointReqsName, 1.0)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdEntryPointReqDurationName, 1.0), time.Millisecond)
 		registry.entryPointOpenConnsGauge = statsdClient.NewGauge(statsdEntryPointOpenConnsName)
+		registry.entryPointReqsBytesCounter = statsdClient.NewCounter(statsdEntryPointReqsBytesName, 1.0)
+		registry.entryPointRespsBytesCounter = statsdClient.NewCounter(statsdEntryPointRespsBytesName, 1.0)
+		registry.entryPointReqHeadersBytesHistogram = statsdClient.NewTiming(statsdEntryPointReqHeadersBytesName, 1.0)
+		registry.entryPointReqBodyBytesHistogram = statsdClient.NewTiming(statsdEntryPointReqBodyBytesName, 1.0)
+		registry.entryPointRespHeadersBytesHistogram = statsdClient.NewTiming(statsdEntryPointRespHeadersBytesName, 1.0)
+		registry.entryPointRespBodyBytesHistogram = statsdClient.NewTiming(statsdEntryPointRespBodyBytesName, 1.0)
+		registry.entryPointTLSHandshakeDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdEntryPointTLSHandshakeDurationName, 1.0), time.Millisecond)
+		registry.entryPointTLSHandshakesCounter = statsdClient.NewCounter(statsdEntryPointTLSHandshakesName, 1.0)
+		registry.entryPointTLSHelloRetryRequestsCounter = statsdClient.NewCounter(statsdEntryPointTLSHelloRetryRequestsName, 1.0)
+	}
+
+	if config.AddRoutersLabels {
+		registry.routerEnabled = config.AddRoutersLabels
+		registry.routerReqsBytesCounter = statsdClient.NewCounter(statsdRouterReqsBytesName, 1.0)
+		registry.routerRespsBytesCounter = statsdClient.NewCounter(statsdRouterRespsBytesName, 1.0)
+		registry.tcpRouterOpenConnsGauge = statsdClient.NewGauge(statsdTCPRouterOpenConnsName)
+		registry.tcpRouterReadBytesCounter = statsdClient.NewCounter(statsdTCPRouterReadBytesName, 1.0)
+		registry.tcpRouterWrittenBytesCounter = statsdClient.NewCounter(statsdTCPRouterWrittenBytesName, 1.0)
+		registry.tcpRouterConnDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdTCPRouterConnDurationName, 1.0), time.Millisecond)
 	}
 
 	if config.AddServicesLabels {
@@ -64,8 +119,19 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceReqsCounter = statsdClient.NewCounter(statsdMetricsServiceReqsName, 1.0)
 		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdMetricsServiceLatencyName, 1.0), time.Millisecond)
 		registry.serviceRetriesCounter = statsdClient.NewCounter(statsdRetriesTotalName, 1.0)
+		registry.serviceRetriesSucceededCounter = statsdClient.NewCounter(statsdRetriesSucceededTotalName, 1.0)
+		registry.serviceCircuitBreakerTransitionsCounter = statsdClient.NewCounter(statsdCircuitBreakerTransitionsName, 1.0)
+		registry.serviceCircuitBreakerStateGauge = statsdClient.NewGauge(statsdCircuitBreakerStateName)
+		registry.serviceShedReqsCounter = statsdClient.NewCounter(statsdShedReqsName, 1.0)
 		registry.serviceOpenConnsGauge = statsdClient.NewGauge(statsdOpenConnsName)
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServerUpName)
+		registry.serviceStaleConnsGauge = statsdClient.NewGauge(statsdStaleConnsName)
+		registry.serviceDrainingConnsGauge = statsdClient.NewGauge(statsdDrainingConnsName)
+		registry.serviceProxyErrorsCounter = statsdClient.NewCounter(statsdProxyErrorsTotalName, 1.0)
+		registry.serviceServerOverrideReqsCounter = statsdClient.NewCounter(statsdServerOverrideReqsName, 1.0)
+		registry.serviceMiddlewareSkippedReqsCounter = statsdClient.NewCounter(statsdMiddlewareSkippedReqsName, 1.0)
+		registry.tcpServiceOpenConnsGauge = statsdClient.NewGauge(statsdTCPServiceOpenConnsName)
+		registry.tcpServiceServerOpenConnsGauge = statsdClient.NewGauge(statsdTCPServiceServerOpenConnsName)
 	}
 
 	return registry
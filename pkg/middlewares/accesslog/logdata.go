@@ -28,6 +28,10 @@ const (
 	ClientHost = "ClientHost"
 	// ClientPort is the map key used for the remote TCP port from which the client request was received.
 	ClientPort = "ClientPort"
+	// ProxyProtocolSrcAddr is the map key used for the source address received in the PROXY protocol header of the connection, if any.
+	ProxyProtocolSrcAddr = "ProxyProtocolSrcAddr"
+	// ProxyProtocolDstAddr is the map key used for the destination address received in the PROXY protocol header of the connection, if any.
+	ProxyProtocolDstAddr = "ProxyProtocolDstAddr"
 	// ClientUsername is the map key used for the username provided in the URL, if present.
 	ClientUsername = "ClientUsername"
 	// RequestAddr is the map key used for the HTTP Host header (usually IP:port). This is treated as not a header by the Go API.
@@ -57,6 +61,9 @@ const (
 	// OriginStatus is the map key used for the HTTP status code returned by the origin server.
 	// If the request was handled by this Traefik instance (e.g. with a redirect), then this value will be absent.
 	OriginStatus = "OriginStatus"
+	// OriginErrorCause is the map key used for the cause of the 502 or 504 status code returned when the origin server could not be reached,
+	// e.g. dns, dial_timeout, tls, connection_reset or header_timeout.
+	OriginErrorCause = "OriginErrorCause"
 	// DownstreamStatus is the map key used for the HTTP status code returned to the client.
 	DownstreamStatus = "DownstreamStatus"
 	// DownstreamContentSize is the map key used for the number of bytes in the response entity returned to the client.
@@ -70,6 +77,10 @@ const (
 	Overhead = "Overhead"
 	// RetryAttempts is the map key used for the amount of attempts the request was retried.
 	RetryAttempts = "RetryAttempts"
+	// MiddlewaresDuration is the map key used for the time spent in each middleware, by middleware name.
+	MiddlewaresDuration = "MiddlewaresDuration"
+	// ServerOverride is the map key used to flag the requests pinned to a server by the server override header.
+	ServerOverride = "ServerOverride"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -111,6 +122,11 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[MiddlewaresDuration] = struct{}{}
+	allCoreKeys[OriginErrorCause] = struct{}{}
+	allCoreKeys[ProxyProtocolSrcAddr] = struct{}{}
+	allCoreKeys[ProxyProtocolDstAddr] = struct{}{}
+	allCoreKeys[ServerOverride] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
@@ -15,6 +15,8 @@ import (
 
 	"github.com/containous/alice"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/proxyprotocol"
 	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/sirupsen/logrus"
 )
@@ -58,6 +60,7 @@ type Handler struct {
 	httpCodeRanges types.HTTPCodeRanges
 	logHandlerChan chan handlerParams
 	wg             sync.WaitGroup
+	redactionRules []redactionRule
 }
 
 // WrapHandler Wraps access log handler into an Alice Constructor.
@@ -71,6 +74,19 @@ func WrapHandler(handler *Handler) alice.Constructor {
 
 // NewHandler creates a new Handler.
 func NewHandler(config *types.AccessLog) (*Handler, error) {
+	var redactionRules []redactionRule
+	if config.Fields != nil && len(config.Fields.Redactions) > 0 {
+		hashKey, err := newHashKey()
+		if err != nil {
+			return nil, err
+		}
+
+		redactionRules, err = newRedactionRules(config.Fields.Redactions, hashKey)
+		if err != nil {
+			return nil, fmt.Errorf("error creating access log redactions: %w", err)
+		}
+	}
+
 	var file io.WriteCloser = noopCloser{os.Stdout}
 	if len(config.FilePath) > 0 {
 		f, err := openAccessLogFile(config.FilePath)
@@ -105,6 +121,7 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		logger:         logger,
 		file:           file,
 		logHandlerChan: logHandlerChan,
+		redactionRules: redactionRules,
 	}
 
 	if config.Filters != nil {
@@ -205,6 +222,11 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		core[ClientHost] = forwardedFor
 	}
 
+	if info, ok := proxyprotocol.GetInfo(req); ok {
+		core[ProxyProtocolSrcAddr] = info.Source.String()
+		core[ProxyProtocolDstAddr] = info.Destination.String()
+	}
+
 	crw := newCaptureResponseWriter(rw)
 
 	next.ServeHTTP(crw, reqWithDataTable)
@@ -213,9 +235,15 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		core[ClientUsername] = usernameIfPresent(reqWithDataTable.URL)
 	}
 
+	status := crw.Status()
+	if status == 0 && middlewares.ClientAborted(req) {
+		// The client went away before a response was sent.
+		status = middlewares.StatusClientClosedRequest
+	}
+
 	logDataTable.DownstreamResponse = downstreamResponse{
 		headers: crw.Header().Clone(),
-		status:  crw.Status(),
+		status:  status,
 		size:    crw.Size(),
 	}
 	if crr != nil {
@@ -321,6 +349,8 @@ func (h *Handler) logTheRoundTrip(logDataTable *LogData) {
 		h.redactHeaders(logDataTable.OriginResponse, fields, "origin_")
 		h.redactHeaders(logDataTable.DownstreamResponse.headers, fields, "downstream_")
 
+		redactFields(h.redactionRules, fields)
+
 		h.mu.Lock()
 		defer h.mu.Unlock()
 		h.logger.WithFields(fields).Println()
@@ -1,6 +1,7 @@
 package accesslog
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -165,6 +166,16 @@ func assertNotEmpty() func(t *testing.T, actual interface{}) {
 	}
 }
 
+func assertHashed(value string) func(t *testing.T, actual interface{}) {
+	return func(t *testing.T, actual interface{}) {
+		t.Helper()
+
+		// The hash key is generated for each handler.
+		assert.Regexp(t, "^"+hashPrefix+"[0-9a-f]{64}$", actual)
+		assert.NotEqual(t, hashValue(nil, value), actual)
+	}
+}
+
 func assertFloat64(exp float64) func(t *testing.T, actual interface{}) {
 	return func(t *testing.T, actual interface{}) {
 		t.Helper()
@@ -350,6 +361,40 @@ func TestLoggerJSON(t *testing.T) {
 				RequestRefererHeader: assertString(testReferer),
 			},
 		},
+		{
+			desc: "redactions applied to the kept fields and headers",
+			config: &types.AccessLog{
+				FilePath: "",
+				Format:   JSONFormat,
+				Fields: &types.AccessLogFields{
+					DefaultMode: "drop",
+					Names: map[string]string{
+						RequestHost: "keep",
+						RouterName:  "keep",
+					},
+					Headers: &types.FieldHeaders{
+						DefaultMode: "drop",
+						Names: map[string]string{
+							"Referer":    "keep",
+							"User-Agent": "keep",
+						},
+					},
+					Redactions: []types.FieldRedaction{
+						{FieldName: RequestHost, Mode: "hash"},
+						{FieldName: "request_Referer", Value: "Referer", Mode: "mask"},
+						{FieldName: "request_User-Agent", Mode: "drop"},
+					},
+				},
+			},
+			expected: map[string]func(t *testing.T, value interface{}){
+				RequestHost:          assertHashed(testHostname),
+				RouterName:           assertString(testRouterName),
+				"level":              assertString("info"),
+				"msg":                assertString(""),
+				"time":               assertNotEmpty(),
+				RequestRefererHeader: assertString("testREDACTED"),
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -385,6 +430,33 @@ func TestLoggerJSON(t *testing.T) {
 	}
 }
 
+func TestLogger_clientAborted(t *testing.T) {
+	tmpDir := createTempDir(t, JSONFormat)
+	defer os.RemoveAll(tmpDir)
+
+	logFilePath := filepath.Join(tmpDir, logFileNameSuffix)
+
+	logger, err := NewHandler(&types.AccessLog{FilePath: logFilePath, Format: JSONFormat})
+	require.NoError(t, err)
+	defer logger.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil).WithContext(ctx)
+
+	logger.ServeHTTP(httptest.NewRecorder(), req, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The client goes away while the request is processed.
+		cancel()
+	}))
+
+	logData, err := ioutil.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	jsonData := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(logData, &jsonData))
+
+	assert.Equal(t, float64(499), jsonData[DownstreamStatus])
+}
+
 func TestNewLogHandlerOutputStdout(t *testing.T) {
 	testCases := []struct {
 		desc        string
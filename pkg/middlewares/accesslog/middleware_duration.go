@@ -0,0 +1,82 @@
+package accesslog
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/containous/alice"
+)
+
+// durationKey is the context key of the timing of a middleware, unique per middleware instance.
+type durationKey struct {
+	name string
+}
+
+// timing holds the time spent by a request in the handlers following a middleware.
+type timing struct {
+	next time.Duration
+}
+
+// WrapMiddlewareDuration records in the access logs the time spent in the middleware built by the constructor,
+// which is the time elapsed between entering and leaving the middleware, minus the time spent in the next handler.
+func WrapMiddlewareDuration(name string, constructor alice.Constructor) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		key := &durationKey{name: name}
+
+		handler, err := constructor(&nextTimer{next: next, key: key})
+		if err != nil || handler == nil {
+			return handler, err
+		}
+
+		return &middlewareTimer{next: handler, key: key}, nil
+	}
+}
+
+type middlewareTimer struct {
+	next http.Handler
+	key  *durationKey
+}
+
+func (m *middlewareTimer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logData := GetLogData(req)
+	if logData == nil {
+		m.next.ServeHTTP(rw, req)
+		return
+	}
+
+	t := &timing{}
+	start := time.Now()
+
+	m.next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), m.key, t)))
+
+	elapsed := time.Since(start) - t.next
+
+	durations, ok := logData.Core[MiddlewaresDuration].(map[string]time.Duration)
+	if !ok {
+		durations = make(map[string]time.Duration)
+		logData.Core[MiddlewaresDuration] = durations
+	}
+
+	// The same middleware can be used more than once, e.g. by an entry point and a router.
+	durations[m.key.name] += elapsed
+}
+
+// nextTimer measures the time spent in the handler following a middleware.
+type nextTimer struct {
+	next http.Handler
+	key  *durationKey
+}
+
+func (n *nextTimer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	t, ok := req.Context().Value(n.key).(*timing)
+	if !ok {
+		n.next.ServeHTTP(rw, req)
+		return
+	}
+
+	start := time.Now()
+	n.next.ServeHTTP(rw, req)
+	// The next handler can be called several times, e.g. by the retry middleware.
+	t.next += time.Since(start)
+}
@@ -0,0 +1,64 @@
+package accesslog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sleeping(d time.Duration, calls int) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			time.Sleep(d)
+			for i := 0; i < calls; i++ {
+				next.ServeHTTP(rw, req)
+			}
+		}), nil
+	}
+}
+
+func TestWrapMiddlewareDuration(t *testing.T) {
+	upstream := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	handler, err := alice.New(
+		WrapMiddlewareDuration("slow@file", sleeping(20*time.Millisecond, 1)),
+		WrapMiddlewareDuration("retry@file", sleeping(0, 2)),
+	).Then(upstream)
+	require.NoError(t, err)
+
+	logData := &LogData{Core: CoreLogData{}}
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req = req.WithContext(context.WithValue(req.Context(), DataTableKey, logData))
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	durations, ok := logData.Core[MiddlewaresDuration].(map[string]time.Duration)
+	require.True(t, ok)
+	require.Len(t, durations, 2)
+
+	// The time spent in the next handlers, including the upstream one, is not accounted.
+	assert.True(t, durations["slow@file"] >= 20*time.Millisecond, durations["slow@file"])
+	assert.True(t, durations["slow@file"] < 100*time.Millisecond, durations["slow@file"])
+	assert.True(t, durations["retry@file"] < 100*time.Millisecond, durations["retry@file"])
+}
+
+func TestWrapMiddlewareDuration_withoutLogData(t *testing.T) {
+	handler, err := alice.New(WrapMiddlewareDuration("noop@file", sleeping(0, 1))).
+		Then(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusTeapot)
+		}))
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusTeapot, rw.Code)
+}
@@ -0,0 +1,173 @@
+package accesslog
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	redactedValue = "REDACTED"
+	hashPrefix    = "hmac-sha256:"
+	// hashKeyLength is the length of the secret key of the hashes.
+	hashKeyLength = 32
+)
+
+type redactionRule struct {
+	fieldName *regexp.Regexp
+	value     *regexp.Regexp
+	mode      string
+	// hashKey is the secret key of the hashes, so that the hashed values cannot be recovered by hashing candidate values.
+	hashKey []byte
+}
+
+// newHashKey generates a random secret key for the hashes,
+// which keeps the values correlatable across the logs of a Traefik instance only.
+func newHashKey() ([]byte, error) {
+	key := make([]byte, hashKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("error generating the hash key: %w", err)
+	}
+	return key, nil
+}
+
+func newRedactionRules(redactions []types.FieldRedaction, hashKey []byte) ([]redactionRule, error) {
+	var rules []redactionRule
+
+	for i, redaction := range redactions {
+		switch redaction.Mode {
+		case types.AccessLogDrop, types.AccessLogHash, types.AccessLogMask:
+		default:
+			return nil, fmt.Errorf("redaction %d: unsupported mode %q", i, redaction.Mode)
+		}
+
+		if redaction.FieldName == "" {
+			return nil, fmt.Errorf("redaction %d: empty field name", i)
+		}
+
+		fieldName, err := regexp.Compile("^(?:" + redaction.FieldName + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("redaction %d: invalid field name regular expression: %w", i, err)
+		}
+
+		rule := redactionRule{fieldName: fieldName, mode: redaction.Mode, hashKey: hashKey}
+
+		if redaction.Value != "" {
+			rule.value, err = regexp.Compile(redaction.Value)
+			if err != nil {
+				return nil, fmt.Errorf("redaction %d: invalid value regular expression: %w", i, err)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// redactFields applies the redaction rules, in order, to the fields.
+func redactFields(rules []redactionRule, fields logrus.Fields) {
+	if len(rules) == 0 {
+		return
+	}
+
+	for name, value := range fields {
+		for _, rule := range rules {
+			if !rule.fieldName.MatchString(name) {
+				continue
+			}
+
+			redacted, keep := rule.apply(value)
+			if !keep {
+				delete(fields, name)
+				break
+			}
+
+			value = redacted
+			fields[name] = value
+		}
+	}
+}
+
+// apply returns the redacted value, and whether the field has to be kept.
+func (r redactionRule) apply(value interface{}) (interface{}, bool) {
+	if r.value == nil {
+		switch r.mode {
+		case types.AccessLogDrop:
+			return nil, false
+		case types.AccessLogHash:
+			return hashValue(r.hashKey, fmt.Sprint(value)), true
+		default:
+			return redactedValue, true
+		}
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		str = fmt.Sprint(value)
+	}
+
+	matches := r.value.FindAllStringSubmatchIndex(str, -1)
+	if len(matches) == 0 {
+		return value, true
+	}
+
+	if r.mode == types.AccessLogDrop {
+		return nil, false
+	}
+
+	return r.replace(str, matches), true
+}
+
+// replace redacts the matched parts of a value.
+// When the regular expression has capturing groups, only the captured parts are redacted,
+// e.g. "token=([^&]+)" redacts the token but keeps its name.
+func (r redactionRule) replace(str string, matches [][]int) string {
+	var b strings.Builder
+
+	last := 0
+	for _, match := range matches {
+		spans := [][]int{match[:2]}
+		if len(match) > 2 {
+			spans = nil
+			for i := 2; i+1 < len(match); i += 2 {
+				// Skips the groups which did not participate in the match.
+				if match[i] >= 0 {
+					spans = append(spans, match[i:i+2])
+				}
+			}
+		}
+
+		for _, span := range spans {
+			if span[0] < last {
+				// Skips the nested groups, their parent being already redacted.
+				continue
+			}
+
+			b.WriteString(str[last:span[0]])
+			if r.mode == types.AccessLogHash {
+				b.WriteString(hashValue(r.hashKey, str[span[0]:span[1]]))
+			} else {
+				b.WriteString(redactedValue)
+			}
+			last = span[1]
+		}
+	}
+
+	b.WriteString(str[last:])
+
+	return b.String()
+}
+
+func hashValue(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(value))
+	return hashPrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,181 @@
+package accesslog
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testHashKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestRedactFields(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		redactions []types.FieldRedaction
+		fields     logrus.Fields
+		expected   logrus.Fields
+	}{
+		{
+			desc: "drop the fields matching the name",
+			redactions: []types.FieldRedaction{
+				{FieldName: "request_(Authorization|Cookie)", Mode: "drop"},
+			},
+			fields: logrus.Fields{
+				"request_Authorization": "Basic Zm9vOmJhcg==",
+				"request_Cookie":        "session=secret",
+				"request_X-Foo":         "bar",
+			},
+			expected: logrus.Fields{
+				"request_X-Foo": "bar",
+			},
+		},
+		{
+			desc: "field name must match as a whole",
+			redactions: []types.FieldRedaction{
+				{FieldName: "Cookie", Mode: "drop"},
+			},
+			fields: logrus.Fields{
+				"request_Cookie": "session=secret",
+			},
+			expected: logrus.Fields{
+				"request_Cookie": "session=secret",
+			},
+		},
+		{
+			desc: "hash a whole value",
+			redactions: []types.FieldRedaction{
+				{FieldName: "ClientUsername", Mode: "hash"},
+			},
+			fields: logrus.Fields{
+				"ClientUsername": "john",
+			},
+			expected: logrus.Fields{
+				"ClientUsername": hashValue(testHashKey, "john"),
+			},
+		},
+		{
+			desc: "mask the captured parts of the value",
+			redactions: []types.FieldRedaction{
+				{FieldName: "RequestPath", Value: "(?:token|key)=([^&]*)", Mode: "mask"},
+			},
+			fields: logrus.Fields{
+				"RequestPath": "/foo?token=secret&page=1&key=other",
+			},
+			expected: logrus.Fields{
+				"RequestPath": "/foo?token=REDACTED&page=1&key=REDACTED",
+			},
+		},
+		{
+			desc: "hash the matched parts of the value",
+			redactions: []types.FieldRedaction{
+				{FieldName: "request_Cookie", Value: "secret", Mode: "hash"},
+			},
+			fields: logrus.Fields{
+				"request_Cookie": "session=secret",
+			},
+			expected: logrus.Fields{
+				"request_Cookie": "session=" + hashValue(testHashKey, "secret"),
+			},
+		},
+		{
+			desc: "drop the fields matching the value",
+			redactions: []types.FieldRedaction{
+				{FieldName: "Request.*", Value: "^/admin", Mode: "drop"},
+			},
+			fields: logrus.Fields{
+				"RequestPath":   "/admin/users",
+				"RequestHost":   "example.com",
+				"RequestMethod": "GET",
+			},
+			expected: logrus.Fields{
+				"RequestHost":   "example.com",
+				"RequestMethod": "GET",
+			},
+		},
+		{
+			desc: "non string value",
+			redactions: []types.FieldRedaction{
+				{FieldName: "DownstreamStatus", Value: "^5", Mode: "mask"},
+			},
+			fields: logrus.Fields{
+				"DownstreamStatus": 503,
+			},
+			expected: logrus.Fields{
+				"DownstreamStatus": "REDACTED03",
+			},
+		},
+		{
+			desc: "rules applied in order",
+			redactions: []types.FieldRedaction{
+				{FieldName: "RequestPath", Value: "token=([^&]*)", Mode: "mask"},
+				{FieldName: "RequestPath", Value: "REDACTED", Mode: "drop"},
+			},
+			fields: logrus.Fields{
+				"RequestPath": "/foo?token=secret",
+			},
+			expected: logrus.Fields{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rules, err := newRedactionRules(test.redactions, testHashKey)
+			require.NoError(t, err)
+
+			redactFields(rules, test.fields)
+
+			assert.Equal(t, test.expected, test.fields)
+		})
+	}
+}
+
+func TestNewRedactionRules_errors(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		redaction   types.FieldRedaction
+		expectedErr string
+	}{
+		{
+			desc:        "unsupported mode",
+			redaction:   types.FieldRedaction{FieldName: "RequestPath", Mode: "redact"},
+			expectedErr: `redaction 0: unsupported mode "redact"`,
+		},
+		{
+			desc:        "empty field name",
+			redaction:   types.FieldRedaction{Mode: "drop"},
+			expectedErr: "redaction 0: empty field name",
+		},
+		{
+			desc:        "invalid value",
+			redaction:   types.FieldRedaction{FieldName: "RequestPath", Value: "(", Mode: "mask"},
+			expectedErr: "redaction 0: invalid value regular expression: error parsing regexp: missing closing ): `(`",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newRedactionRules([]types.FieldRedaction{test.redaction}, testHashKey)
+			assert.EqualError(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestHashValue(t *testing.T) {
+	hash := hashValue(testHashKey, "john")
+	assert.Regexp(t, "^hmac-sha256:[0-9a-f]{64}$", hash)
+	assert.Equal(t, hash, hashValue(testHashKey, "john"))
+
+	// Another Traefik instance has another key, so its hashes cannot be correlated with, nor precomputed for, this one.
+	otherKey, err := newHashKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, hashValue(otherKey, "john"))
+}
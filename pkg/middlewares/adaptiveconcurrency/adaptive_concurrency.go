@@ -0,0 +1,211 @@
+package adaptiveconcurrency
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "AdaptiveConcurrency"
+)
+
+const (
+	defaultInitialLimit = 20
+	defaultMaxLimit     = 1000
+	defaultTolerance    = 50
+	defaultWindow       = time.Second
+)
+
+const (
+	// smoothing is the weight of a new limit against the previous one.
+	smoothing = 0.2
+	// longRTTSmoothing is the weight of the latency of a window in the long-term latency.
+	longRTTSmoothing = 0.05
+	minGradient      = 0.5
+	maxGradient      = 1.0
+)
+
+// adaptiveConcurrency is a middleware that limits the number of in-flight requests,
+// and adjusts the limit to the latency of the service.
+type adaptiveConcurrency struct {
+	next    http.Handler
+	name    string
+	limiter *limiter
+}
+
+// New creates an adaptive concurrency middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.AdaptiveConcurrency, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	return &adaptiveConcurrency{
+		next:    next,
+		name:    name,
+		limiter: newLimiter(config),
+	}, nil
+}
+
+func (a *adaptiveConcurrency) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return a.name, tracing.SpanKindNoneEnum
+}
+
+func (a *adaptiveConcurrency) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !a.limiter.acquire() {
+		logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), a.name, typeName))
+		logger.Debugf("Limiting request, the concurrency limit (%d) is reached", a.limiter.currentLimit())
+
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	start := time.Now()
+	defer func() {
+		a.limiter.release(time.Since(start))
+	}()
+
+	a.next.ServeHTTP(rw, req)
+}
+
+// limiter implements the gradient algorithm:
+// the limit is multiplied by the ratio between the long-term latency and the latency of the last window,
+// so that it decreases when the latency increases above the tolerance,
+// and increases by the square root of the limit otherwise, to probe for additional capacity.
+type limiter struct {
+	minLimit  float64
+	maxLimit  float64
+	tolerance float64
+	window    time.Duration
+
+	mu       sync.Mutex
+	limit    float64
+	inFlight int64
+	longRTT  float64
+
+	windowStart   time.Time
+	windowSum     time.Duration
+	windowCount   int64
+	windowMaxUsed int64
+}
+
+func newLimiter(config dynamic.AdaptiveConcurrency) *limiter {
+	if config.InitialLimit <= 0 {
+		config.InitialLimit = defaultInitialLimit
+	}
+
+	if config.MaxLimit <= 0 {
+		config.MaxLimit = defaultMaxLimit
+	}
+
+	if config.Tolerance <= 0 {
+		config.Tolerance = defaultTolerance
+	}
+
+	if config.Window <= 0 {
+		config.Window = types.Duration(defaultWindow)
+	}
+
+	l := &limiter{
+		minLimit:  math.Max(1, float64(config.MinLimit)),
+		maxLimit:  float64(config.MaxLimit),
+		tolerance: 1 + float64(config.Tolerance)/100,
+		window:    time.Duration(config.Window),
+	}
+
+	if l.maxLimit < l.minLimit {
+		l.maxLimit = l.minLimit
+	}
+
+	l.limit = math.Max(l.minLimit, math.Min(l.maxLimit, float64(config.InitialLimit)))
+
+	return l
+}
+
+func (l *limiter) acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if float64(l.inFlight) >= math.Floor(l.limit) {
+		return false
+	}
+
+	l.inFlight++
+	if l.inFlight > l.windowMaxUsed {
+		l.windowMaxUsed = l.inFlight
+	}
+
+	return true
+}
+
+func (l *limiter) release(rtt time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+
+	now := time.Now()
+	if l.windowStart.IsZero() {
+		l.windowStart = now
+	}
+
+	l.windowSum += rtt
+	l.windowCount++
+
+	if now.Sub(l.windowStart) < l.window {
+		return
+	}
+
+	l.update(float64(l.windowSum) / float64(l.windowCount))
+
+	l.windowStart = now
+	l.windowSum = 0
+	l.windowCount = 0
+	l.windowMaxUsed = l.inFlight
+}
+
+// update adjusts the limit to the average latency of the last window.
+// It must be called with the lock held.
+func (l *limiter) update(shortRTT float64) {
+	if shortRTT <= 0 {
+		return
+	}
+
+	if l.longRTT == 0 {
+		l.longRTT = shortRTT
+	} else {
+		l.longRTT = l.longRTT*(1-longRTTSmoothing) + shortRTT*longRTTSmoothing
+	}
+
+	// The long-term latency recovers faster after a latency spike.
+	if l.longRTT/shortRTT > 2 {
+		l.longRTT *= 0.95
+	}
+
+	gradient := math.Max(minGradient, math.Min(maxGradient, l.tolerance*l.longRTT/shortRTT))
+	newLimit := l.limit*gradient + math.Sqrt(l.limit)
+	newLimit = l.limit*(1-smoothing) + newLimit*smoothing
+
+	// The limit is not increased while the service is not using it,
+	// as the latency then tells nothing about the capacity of the service,
+	// but it is still decreased when the latency grows.
+	if newLimit > l.limit && float64(l.windowMaxUsed) < l.limit/2 {
+		return
+	}
+
+	l.limit = math.Max(l.minLimit, math.Min(l.maxLimit, newLimit))
+}
+
+func (l *limiter) currentLimit() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return int64(l.limit)
+}
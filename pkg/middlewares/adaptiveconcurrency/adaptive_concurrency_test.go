@@ -0,0 +1,142 @@
+package adaptiveconcurrency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_acquire(t *testing.T) {
+	l := newLimiter(dynamic.AdaptiveConcurrency{InitialLimit: 2, MaxLimit: 10})
+
+	assert.True(t, l.acquire())
+	assert.True(t, l.acquire())
+	assert.False(t, l.acquire())
+
+	l.release(time.Millisecond)
+	assert.True(t, l.acquire())
+}
+
+func TestLimiter_update(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.AdaptiveConcurrency
+		rtts          []time.Duration
+		used          int64
+		expectedLimit func(t *testing.T, limit int64)
+	}{
+		{
+			desc:   "stable latency increases the limit",
+			config: dynamic.AdaptiveConcurrency{InitialLimit: 20, MaxLimit: 1000, Tolerance: 50},
+			rtts:   []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond},
+			used:   20,
+			expectedLimit: func(t *testing.T, limit int64) {
+				assert.Greater(t, limit, int64(20))
+			},
+		},
+		{
+			desc:   "increasing latency decreases the limit",
+			config: dynamic.AdaptiveConcurrency{InitialLimit: 100, MaxLimit: 1000, Tolerance: 50},
+			rtts:   []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond},
+			used:   100,
+			expectedLimit: func(t *testing.T, limit int64) {
+				assert.Less(t, limit, int64(100))
+			},
+		},
+		{
+			desc:   "unused limit is not increased",
+			config: dynamic.AdaptiveConcurrency{InitialLimit: 20, MaxLimit: 1000, Tolerance: 50},
+			rtts:   []time.Duration{10 * time.Millisecond, 10 * time.Millisecond},
+			used:   1,
+			expectedLimit: func(t *testing.T, limit int64) {
+				assert.Equal(t, int64(20), limit)
+			},
+		},
+		{
+			desc:   "increasing latency decreases an unused limit",
+			config: dynamic.AdaptiveConcurrency{InitialLimit: 100, MaxLimit: 1000, Tolerance: 50},
+			rtts:   []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond},
+			used:   1,
+			expectedLimit: func(t *testing.T, limit int64) {
+				assert.Less(t, limit, int64(100))
+			},
+		},
+		{
+			desc:   "limit is capped to the max limit",
+			config: dynamic.AdaptiveConcurrency{InitialLimit: 20, MaxLimit: 21, Tolerance: 50},
+			rtts:   []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond},
+			used:   21,
+			expectedLimit: func(t *testing.T, limit int64) {
+				assert.Equal(t, int64(21), limit)
+			},
+		},
+		{
+			desc:   "limit is floored to the min limit",
+			config: dynamic.AdaptiveConcurrency{InitialLimit: 10, MinLimit: 9, MaxLimit: 1000, Tolerance: 50},
+			rtts:   []time.Duration{time.Millisecond, time.Second, time.Second, time.Second, time.Second, time.Second, time.Second},
+			used:   10,
+			expectedLimit: func(t *testing.T, limit int64) {
+				assert.Equal(t, int64(9), limit)
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			l := newLimiter(test.config)
+			for _, rtt := range test.rtts {
+				l.windowMaxUsed = test.used
+				l.update(float64(rtt))
+			}
+
+			test.expectedLimit(t, l.currentLimit())
+		})
+	}
+}
+
+func TestAdaptiveConcurrency(t *testing.T) {
+	release := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-release
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.AdaptiveConcurrency{
+		InitialLimit: 1,
+		MaxLimit:     1,
+		Window:       types.Duration(time.Second),
+	}, "test")
+	require.NoError(t, err)
+
+	done := make(chan int)
+	go func() {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		done <- rw.Code
+	}()
+
+	// Waits for the first request to be in flight.
+	limiter := handler.(*adaptiveConcurrency).limiter
+	require.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return limiter.inFlight == 1
+	}, time.Second, time.Millisecond)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+}
@@ -0,0 +1,230 @@
+package aggregate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "Aggregate"
+)
+
+// aggregate is a middleware that merges the JSON responses of several endpoints into the response of the service.
+type aggregate struct {
+	next           http.Handler
+	name           string
+	endpoints      []dynamic.AggregateEndpoint
+	forwardHeaders []string
+	client         http.Client
+}
+
+// New creates an aggregate middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Aggregate, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Endpoints) == 0 {
+		return nil, errors.New("at least one endpoint is required")
+	}
+
+	for _, endpoint := range config.Endpoints {
+		if endpoint.URL == "" {
+			return nil, errors.New("the URL of an endpoint cannot be empty")
+		}
+	}
+
+	return &aggregate{
+		next:           next,
+		name:           name,
+		endpoints:      config.Endpoints,
+		forwardHeaders: config.ForwardHeaders,
+		client: http.Client{
+			CheckRedirect: func(r *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+func (a *aggregate) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return a.name, ext.SpanKindRPCClientEnum
+}
+
+type endpointResult struct {
+	value interface{}
+	err   error
+}
+
+func (a *aggregate) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), a.name, typeName))
+
+	// The endpoints are called while the service handles the request.
+	results := make([]endpointResult, len(a.endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range a.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint dynamic.AggregateEndpoint) {
+			defer wg.Done()
+			results[i].value, results[i].err = a.fetch(req, endpoint.URL)
+		}(i, endpoint)
+	}
+
+	recorder := newResponseRecorder()
+	a.next.ServeHTTP(recorder, req)
+	wg.Wait()
+
+	merged := make(map[string]interface{})
+	if recorder.code < http.StatusOK || recorder.code >= http.StatusMultipleChoices || json.Unmarshal(recorder.body.Bytes(), &merged) != nil {
+		// Only the successful JSON object responses of the service are aggregated.
+		recorder.writeTo(rw)
+		return
+	}
+
+	for i, endpoint := range a.endpoints {
+		err := results[i].err
+		if err == nil {
+			err = mergeValue(merged, endpoint.Key, results[i].value)
+		}
+		if err == nil {
+			continue
+		}
+
+		logMessage := fmt.Sprintf("Error aggregating %s. Cause: %s", endpoint.URL, err)
+		logger.Debug(logMessage)
+
+		if endpoint.Optional {
+			continue
+		}
+
+		tracing.SetErrorWithEvent(req, logMessage)
+		rw.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	body, err := json.Marshal(merged)
+	if err != nil {
+		logger.Errorf("Error while encoding the aggregated response: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	for k, v := range recorder.Header() {
+		rw.Header()[k] = v
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	rw.WriteHeader(recorder.code)
+
+	if _, err = rw.Write(body); err != nil {
+		logger.Debugf("Error while writing response: %v", err)
+	}
+}
+
+// fetch calls the endpoint, and decodes its JSON response.
+func (a *aggregate) fetch(req *http.Request, url string) (interface{}, error) {
+	endpointReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range a.forwardHeaders {
+		if values, ok := req.Header[http.CanonicalHeaderKey(name)]; ok {
+			endpointReq.Header[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+
+	resp, err := a.client.Do(endpointReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(body, &value); err != nil {
+		return nil, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	return value, nil
+}
+
+// mergeValue sets the value under the key,
+// or merges the fields of the value at the top level when the key is empty.
+func mergeValue(merged map[string]interface{}, key string, value interface{}) error {
+	if key != "" {
+		merged[key] = value
+		return nil
+	}
+
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return errors.New("a response merged at the top level must be a JSON object")
+	}
+
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return nil
+}
+
+// responseRecorder buffers the response of the service.
+type responseRecorder struct {
+	header      http.Header
+	code        int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header), code: http.StatusOK}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.wroteHeader {
+		return
+	}
+
+	r.wroteHeader = true
+	r.code = code
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(p)
+}
+
+// writeTo forwards the buffered response as is.
+func (r *responseRecorder) writeTo(rw http.ResponseWriter) {
+	for k, v := range r.header {
+		rw.Header()[k] = v
+	}
+	rw.WriteHeader(r.code)
+	_, _ = rw.Write(r.body.Bytes())
+}
@@ -0,0 +1,149 @@
+package aggregate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(`{"name":"alice","token":"` + req.Header.Get("Authorization") + `"}`))
+	})
+	mux.HandleFunc("/orders", func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(`[1,2]`))
+	})
+	mux.HandleFunc("/invalid", func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(`not json`))
+	})
+	mux.HandleFunc("/error", func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.Aggregate
+		backendBody    string
+		backendStatus  int
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			desc: "merges at the top level and under keys",
+			config: dynamic.Aggregate{
+				Endpoints: []dynamic.AggregateEndpoint{
+					{URL: server.URL + "/user"},
+					{URL: server.URL + "/orders", Key: "orders"},
+				},
+			},
+			backendBody:    `{"id":42}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":42,"name":"alice","orders":[1,2],"token":""}`,
+		},
+		{
+			desc: "forwards the headers",
+			config: dynamic.Aggregate{
+				Endpoints:      []dynamic.AggregateEndpoint{{URL: server.URL + "/user", Key: "user"}},
+				ForwardHeaders: []string{"authorization"},
+			},
+			backendBody:    `{}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"user":{"name":"alice","token":"Bearer foo"}}`,
+		},
+		{
+			desc: "fails on endpoint error",
+			config: dynamic.Aggregate{
+				Endpoints: []dynamic.AggregateEndpoint{{URL: server.URL + "/error", Key: "error"}},
+			},
+			backendBody:    `{"id":42}`,
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			desc: "fails on an array merged at the top level",
+			config: dynamic.Aggregate{
+				Endpoints: []dynamic.AggregateEndpoint{{URL: server.URL + "/orders"}},
+			},
+			backendBody:    `{"id":42}`,
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			desc: "ignores optional endpoint errors",
+			config: dynamic.Aggregate{
+				Endpoints: []dynamic.AggregateEndpoint{
+					{URL: server.URL + "/error", Key: "error", Optional: true},
+					{URL: server.URL + "/invalid", Key: "invalid", Optional: true},
+				},
+			},
+			backendBody:    `{"id":42}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":42}`,
+		},
+		{
+			desc: "does not aggregate a non JSON response",
+			config: dynamic.Aggregate{
+				Endpoints: []dynamic.AggregateEndpoint{{URL: server.URL + "/user"}},
+			},
+			backendBody:    `hello`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `hello`,
+		},
+		{
+			desc: "does not aggregate an error response",
+			config: dynamic.Aggregate{
+				Endpoints: []dynamic.AggregateEndpoint{{URL: server.URL + "/user"}},
+			},
+			backendBody:    `{"error":"not found"}`,
+			backendStatus:  http.StatusNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"not found"}`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("X-Backend", "foo")
+				if test.backendStatus != 0 {
+					rw.WriteHeader(test.backendStatus)
+				}
+				_, _ = rw.Write([]byte(test.backendBody))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "aggregate")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set("Authorization", "Bearer foo")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedStatus == http.StatusBadGateway {
+				return
+			}
+
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, "foo", recorder.Header().Get("X-Backend"))
+		})
+	}
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.Aggregate{}, "aggregate")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), http.NotFoundHandler(), dynamic.Aggregate{Endpoints: []dynamic.AggregateEndpoint{{Key: "foo"}}}, "aggregate")
+	assert.Error(t, err)
+}
@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/mailgun/ttlmap"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	introspectionTypeName = "IntrospectionAuth"
+
+	// maxCachedTokens is the maximum number of introspection results kept in cache.
+	maxCachedTokens = 10000
+)
+
+type introspection struct {
+	address       string
+	clientID      string
+	clientSecret  string
+	headerFields  map[string]string
+	cacheDuration time.Duration
+	cache         *ttlmap.TtlMap
+	next          http.Handler
+	name          string
+	client        http.Client
+}
+
+// introspectionResponse is the response of the introspection endpoint.
+type introspectionResponse struct {
+	active bool
+	exp    int64
+	fields map[string]interface{}
+}
+
+// NewIntrospection creates an OAuth2 token introspection middleware.
+func NewIntrospection(ctx context.Context, next http.Handler, config dynamic.Introspection, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, introspectionTypeName)).Debug("Creating middleware")
+
+	if config.Address == "" {
+		return nil, errors.New("the address of the introspection endpoint is required")
+	}
+
+	cache, err := ttlmap.NewConcurrent(maxCachedTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &introspection{
+		address:       config.Address,
+		clientID:      config.ClientID,
+		clientSecret:  config.ClientSecret,
+		headerFields:  config.HeaderFields,
+		cacheDuration: time.Duration(config.CacheDuration),
+		cache:         cache,
+		next:          next,
+		name:          name,
+		client: http.Client{
+			CheckRedirect: func(r *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+			Timeout: 30 * time.Second,
+		},
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = tlsConfig
+		i.client.Transport = tr
+	}
+
+	return i, nil
+}
+
+func (i *introspection) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return i.name, ext.SpanKindRPCClientEnum
+}
+
+func (i *introspection) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), i.name, introspectionTypeName))
+
+	token := bearerToken(req)
+	if token == "" {
+		logger.Debug("Missing bearer token")
+		tracing.SetErrorWithEvent(req, "Missing bearer token")
+
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="`+defaultRealm+`"`)
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	result, err := i.introspect(req, token)
+	if err != nil {
+		logMessage := fmt.Sprintf("Error calling %s. Cause: %s", i.address, err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !result.active {
+		logger.Debug("Inactive token")
+		tracing.SetErrorWithEvent(req, "Inactive token")
+
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="`+defaultRealm+`", error="invalid_token"`)
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	for headerName, field := range i.headerFields {
+		req.Header.Del(headerName)
+
+		if value, ok := result.fields[field]; ok && value != nil {
+			req.Header.Set(headerName, headerValue(value))
+		}
+	}
+
+	i.next.ServeHTTP(rw, req)
+}
+
+// introspect returns the introspection result of the token, from the cache if possible.
+func (i *introspection) introspect(req *http.Request, token string) (*introspectionResponse, error) {
+	hash := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(hash[:])
+
+	if cached, ok := i.cache.Get(key); ok {
+		return cached.(*introspectionResponse), nil
+	}
+
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", "access_token")
+
+	introspectionReq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, i.address, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	tracing.LogRequest(tracing.GetSpan(req), introspectionReq)
+
+	introspectionReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	introspectionReq.Header.Set("Accept", "application/json")
+	if i.clientID != "" {
+		introspectionReq.SetBasicAuth(url.QueryEscape(i.clientID), url.QueryEscape(i.clientSecret))
+	}
+
+	resp, err := i.client.Do(introspectionReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	result := &introspectionResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(&result.fields); err != nil {
+		return nil, fmt.Errorf("invalid introspection response: %w", err)
+	}
+
+	result.active, _ = result.fields["active"].(bool)
+	if exp, ok := result.fields["exp"].(float64); ok {
+		result.exp = int64(exp)
+	}
+
+	if ttl := i.cacheTTL(result); ttl > 0 {
+		if err = i.cache.Set(key, result, ttl); err != nil {
+			log.FromContext(req.Context()).Errorf("Error while caching the introspection result: %v", err)
+		}
+	}
+
+	return result, nil
+}
+
+// cacheTTL returns the number of seconds the introspection result can be cached,
+// which never exceeds the expiration of the token.
+func (i *introspection) cacheTTL(result *introspectionResponse) int {
+	ttl := i.cacheDuration
+	if result.active && result.exp > 0 {
+		if untilExp := time.Until(time.Unix(result.exp, 0)); untilExp < ttl {
+			ttl = untilExp
+		}
+	}
+
+	return int(ttl / time.Second)
+}
+
+// bearerToken returns the bearer token of the Authorization header.
+func bearerToken(req *http.Request) string {
+	parts := strings.SplitN(req.Header.Get(authorizationHeader), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(parts[1])
+}
+
+// headerValue formats a field of the introspection response as a header value.
+func headerValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, headerValue(item))
+		}
+		return strings.Join(values, ",")
+	default:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	}
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	ptypes "github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospection(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		user, password, _ := req.BasicAuth()
+		if user != "client" || password != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var response map[string]interface{}
+		switch req.FormValue("token") {
+		case "valid":
+			response = map[string]interface{}{
+				"active":    true,
+				"sub":       "alice",
+				"scope":     "read write",
+				"groups":    []string{"admin", "dev"},
+				"exp":       time.Now().Add(time.Hour).Unix(),
+				"client_id": "app",
+			}
+		case "error":
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		default:
+			response = map[string]interface{}{"active": false}
+		}
+
+		_ = json.NewEncoder(rw).Encode(response)
+	}))
+	defer server.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-User", req.Header.Get("X-User"))
+		rw.Header().Set("X-Groups", req.Header.Get("X-Groups"))
+		rw.Header().Set("X-Exp", req.Header.Get("X-Exp"))
+		rw.Header().Set("X-Missing", req.Header.Get("X-Missing"))
+	})
+
+	handler, err := NewIntrospection(context.Background(), next, dynamic.Introspection{
+		Address:       server.URL,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		CacheDuration: ptypes.Duration(time.Minute),
+		HeaderFields: map[string]string{
+			"X-User":    "sub",
+			"X-Groups":  "groups",
+			"X-Exp":     "exp",
+			"X-Missing": "missing",
+		},
+	}, "introspection")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc           string
+		authorization  string
+		expectedStatus int
+		expectedUser   string
+		expectedGroups string
+	}{
+		{
+			desc:           "missing token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "basic credentials",
+			authorization:  "Basic dGVzdDp0ZXN0",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "inactive token",
+			authorization:  "Bearer expired",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "introspection error",
+			authorization:  "Bearer error",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:           "active token",
+			authorization:  "Bearer valid",
+			expectedStatus: http.StatusOK,
+			expectedUser:   "alice",
+			expectedGroups: "admin,dev",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set("X-Missing", "spoofed")
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedStatus == http.StatusUnauthorized {
+				assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, test.expectedUser, recorder.Header().Get("X-User"))
+			assert.Equal(t, test.expectedGroups, recorder.Header().Get("X-Groups"))
+			assert.NotEmpty(t, recorder.Header().Get("X-Exp"))
+			assert.NotContains(t, recorder.Header().Get("X-Exp"), "e+")
+			assert.Empty(t, recorder.Header().Get("X-Missing"))
+		})
+	}
+
+	// The active token introspection result is cached.
+	before := atomic.LoadInt32(&calls)
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Set("Authorization", "Bearer valid")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, before, atomic.LoadInt32(&calls))
+}
+
+func TestIntrospection_missingAddress(t *testing.T) {
+	_, err := NewIntrospection(context.Background(), http.NotFoundHandler(), dynamic.Introspection{}, "introspection")
+	assert.Error(t, err)
+}
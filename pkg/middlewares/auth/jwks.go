@@ -0,0 +1,384 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha512" // registers the SHA-384 and SHA-512 hash functions.
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+const (
+	// minKeysRefreshInterval is the minimum interval between two fetches of a key set,
+	// so that tokens signed with unknown keys cannot be used to flood its server.
+	minKeysRefreshInterval = time.Minute
+
+	// defaultKeysMaxAge is the duration after which a key set is fetched again, to follow the rotations of the keys.
+	defaultKeysMaxAge = time.Hour
+)
+
+// errKeysUnavailable is returned when a key set cannot be fetched, and no previous key can be used instead.
+var errKeysUnavailable = errors.New("unable to fetch the keys")
+
+// jsonWebKey is a public key of a JSON Web Key Set (RFC 7517).
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet fetches, and keeps, the public keys of a JSON Web Key Set.
+// The keys are fetched again once they are older than maxAge, or when a token is signed with an unknown key.
+type keySet struct {
+	url    string
+	client *http.Client
+	maxAge time.Duration
+
+	mu     sync.Mutex
+	keys   map[string]crypto.PublicKey
+	update time.Time
+	// failure is the time of the last failed fetch, and err its error.
+	failure time.Time
+	err     error
+	// fetching is closed once the fetch in progress, if any, is done.
+	fetching chan struct{}
+}
+
+func newKeySet(url string, client *http.Client, maxAge time.Duration) *keySet {
+	return &keySet{url: url, client: client, maxAge: maxAge}
+}
+
+// verify checks the signature of the JWT, and returns its claims.
+func (k *keySet) verify(ctx context.Context, raw string) (map[string]interface{}, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature: %w", err)
+	}
+
+	key, err := k.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+
+	return claims, nil
+}
+
+// key returns the public key with the given ID.
+// The key set is fetched without holding the lock, and only once for the concurrent requests needing it.
+func (k *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	for {
+		k.mu.Lock()
+
+		age := time.Since(k.update)
+
+		key := lookupKey(k.keys, kid)
+		if key != nil && (k.maxAge <= 0 || age < k.maxAge || age < minKeysRefreshInterval) {
+			k.mu.Unlock()
+			return key, nil
+		}
+
+		if key == nil && k.keys != nil && age < minKeysRefreshInterval {
+			k.mu.Unlock()
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+
+		// A failed fetch is not retried before the minimum interval, so that an unavailable server is not flooded.
+		// The known keys are still used meanwhile.
+		if time.Since(k.failure) < minKeysRefreshInterval {
+			err := k.err
+			k.mu.Unlock()
+
+			if key != nil {
+				return key, nil
+			}
+			return nil, err
+		}
+
+		if k.fetching == nil {
+			k.fetching = make(chan struct{})
+			k.mu.Unlock()
+
+			return k.refresh(ctx, kid, key)
+		}
+
+		fetching := k.fetching
+		k.mu.Unlock()
+
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// refresh fetches the key set, and returns the public key with the given ID,
+// or the previous key, if any, when the key set cannot be fetched.
+func (k *keySet) refresh(ctx context.Context, kid string, previous crypto.PublicKey) (crypto.PublicKey, error) {
+	keys, err := k.fetch(ctx)
+
+	k.mu.Lock()
+	close(k.fetching)
+	k.fetching = nil
+
+	switch {
+	case err == nil:
+		k.keys = keys
+		k.update = time.Now()
+		k.failure = time.Time{}
+		k.err = nil
+
+	case ctx.Err() == nil:
+		// The fetches aborted by the cancellation of their request are not failures of the server.
+		k.failure = time.Now()
+		k.err = err
+	}
+	k.mu.Unlock()
+
+	if err != nil {
+		if previous != nil {
+			log.FromContext(ctx).Warnf("Unable to fetch the keys from %s, using the previous ones: %v", k.url, err)
+			return previous, nil
+		}
+		return nil, err
+	}
+
+	if key := lookupKey(keys, kid); key != nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (k *keySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, k.client, k.url, &set); err != nil {
+		return nil, fmt.Errorf("%w: %v", errKeysUnavailable, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Unsupported keys are skipped, the tokens are never signed with them.
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, address string, value interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(value)
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("invalid elliptic curve point")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+// verifySignature checks the JWS signature of the input, refusing the algorithms which do not match the key.
+func verifySignature(alg string, key crypto.PublicKey, input, signature []byte) error {
+	if len(alg) != 5 {
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+
+	var hash crypto.Hash
+	switch alg[len(alg)-3:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+
+	h := hash.New()
+	_, _ = h.Write(input)
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return rsa.VerifyPKCS1v15(k, hash, digest, signature)
+		case "PS":
+			return rsa.VerifyPSS(k, hash, digest, signature, nil)
+		}
+
+	case *ecdsa.PublicKey:
+		if alg[:2] != "ES" {
+			break
+		}
+
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid ECDSA signature size")
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported signing algorithm %q", alg)
+}
+
+func lookupKey(keys map[string]crypto.PublicKey, kid string) crypto.PublicKey {
+	if key, ok := keys[kid]; ok {
+		return key
+	}
+
+	// Tokens without key ID can only be verified when there is no ambiguity.
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+
+	return nil
+}
+
+func hasAudience(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, item := range v {
+			if item == clientID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, value)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(raw), nil
+}
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const jwtTypeName = "JWTAuth"
+
+type jwtAuth struct {
+	keys           *keySet
+	issuer         string
+	audiences      []string
+	requiredClaims map[string]string
+	headerClaims   map[string]string
+	next           http.Handler
+	name           string
+}
+
+// NewJWT creates a JSON Web Token authentication middleware.
+func NewJWT(ctx context.Context, next http.Handler, config dynamic.JWT, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, jwtTypeName)).Debug("Creating middleware")
+
+	if config.JWKSURL == "" {
+		return nil, errors.New("the URL of the key set is required")
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout: 30 * time.Second,
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = tlsConfig
+		client.Transport = tr
+	}
+
+	maxAge := time.Duration(config.KeysRefreshInterval)
+	if maxAge <= 0 {
+		maxAge = defaultKeysMaxAge
+	}
+
+	return &jwtAuth{
+		keys:           newKeySet(config.JWKSURL, client, maxAge),
+		issuer:         config.Issuer,
+		audiences:      config.Audiences,
+		requiredClaims: config.RequiredClaims,
+		headerClaims:   config.HeaderClaims,
+		next:           next,
+		name:           name,
+	}, nil
+}
+
+func (j *jwtAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return j.name, ext.SpanKindRPCClientEnum
+}
+
+func (j *jwtAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), j.name, jwtTypeName))
+
+	token := bearerToken(req)
+	if token == "" {
+		logger.Debug("Missing bearer token")
+		tracing.SetErrorWithEvent(req, "Missing bearer token")
+
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="`+defaultRealm+`"`)
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := j.validate(req.Context(), token)
+	if errors.Is(err, errKeysUnavailable) {
+		logMessage := fmt.Sprintf("Unable to validate the token: %v", err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		logMessage := fmt.Sprintf("Invalid token: %v", err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="`+defaultRealm+`", error="invalid_token"`)
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	for claim, expected := range j.requiredClaims {
+		if !claimMatches(claims[claim], expected) {
+			logMessage := fmt.Sprintf("The claim %s does not match the required value", claim)
+			logger.Debug(logMessage)
+			tracing.SetErrorWithEvent(req, logMessage)
+
+			rw.Header().Set("WWW-Authenticate", `Bearer realm="`+defaultRealm+`", error="insufficient_scope"`)
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	for headerName, claim := range j.headerClaims {
+		req.Header.Del(headerName)
+
+		if value, ok := claims[claim]; ok && value != nil {
+			req.Header.Set(headerName, headerValue(value))
+		}
+	}
+
+	j.next.ServeHTTP(rw, middlewares.WithJWTClaims(req, claims))
+}
+
+// validate checks the signature, the issuer, the audience and the validity period of the token,
+// and returns its claims.
+func (j *jwtAuth) validate(ctx context.Context, token string) (map[string]interface{}, error) {
+	claims, err := j.keys.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if j.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != j.issuer {
+			return nil, fmt.Errorf("unexpected issuer %q", iss)
+		}
+	}
+
+	if len(j.audiences) > 0 && !hasAnyAudience(claims["aud"], j.audiences) {
+		return nil, errors.New("the token is not issued for an accepted audience")
+	}
+
+	now := time.Now().Unix()
+
+	if exp, ok := claims["exp"].(float64); !ok || now >= int64(exp) {
+		return nil, errors.New("the token is expired")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now < int64(nbf) {
+		return nil, errors.New("the token is not valid yet")
+	}
+
+	return claims, nil
+}
+
+func hasAnyAudience(aud interface{}, audiences []string) bool {
+	for _, audience := range audiences {
+		if hasAudience(aud, audience) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// claimMatches returns whether the claim has the expected value, or contains it for an array claim.
+func claimMatches(value interface{}, expected string) bool {
+	if value == nil {
+		return false
+	}
+
+	if values, ok := value.([]interface{}); ok {
+		for _, item := range values {
+			if item != nil && headerValue(item) == expected {
+				return true
+			}
+		}
+		return false
+	}
+
+	return headerValue(value) == expected
+}
@@ -0,0 +1,574 @@
+package auth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/vulcand/oxy/forward"
+)
+
+const (
+	oidcTypeName = "OIDCAuth"
+
+	// oidcStateDuration is the time given to the users to authenticate with the provider.
+	oidcStateDuration = 10 * time.Minute
+
+	// maxCookieSize is the size of the cookies beyond which most browsers drop them.
+	maxCookieSize = 4096
+)
+
+type oidc struct {
+	provider           *oidcProvider
+	clientID           string
+	clientSecret       string
+	scopes             []string
+	redirectPath       string
+	sessionCookie      string
+	stateCookie        string
+	sessionDuration    time.Duration
+	aead               cipher.AEAD
+	headerClaims       map[string]string
+	forwardAccessToken bool
+	next               http.Handler
+	name               string
+	client             http.Client
+}
+
+// oidcSession is the content of the session cookie.
+type oidcSession struct {
+	Claims       map[string]interface{} `json:"claims"`
+	AccessToken  string                 `json:"accessToken,omitempty"`
+	RefreshToken string                 `json:"refreshToken,omitempty"`
+	// Expiry is the expiration of the tokens, after which they are refreshed.
+	Expiry int64 `json:"expiry"`
+	// End is the end of the session, after which the users authenticate again.
+	End int64 `json:"end"`
+}
+
+// oidcState is the content of the state cookie, set during the authentication with the provider.
+type oidcState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	URI      string `json:"uri"`
+}
+
+// tokenResponse is the response of the token endpoint.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// NewOIDC creates an OpenID Connect authentication middleware.
+func NewOIDC(ctx context.Context, next http.Handler, config dynamic.OIDC, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, oidcTypeName)).Debug("Creating middleware")
+
+	if config.Issuer == "" || config.ClientID == "" {
+		return nil, errors.New("the issuer and the client ID are required")
+	}
+
+	// The key encrypting the cookies is derived from the secret, which must not be guessable.
+	if len(config.SessionSecret) < 32 {
+		return nil, errors.New("the session secret must be at least 32 characters long")
+	}
+
+	block, err := aes.NewCipher(sha256Sum(config.SessionSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &oidc{
+		clientID:           config.ClientID,
+		clientSecret:       config.ClientSecret,
+		scopes:             config.Scopes,
+		redirectPath:       config.RedirectPath,
+		sessionCookie:      config.SessionCookie,
+		sessionDuration:    time.Duration(config.SessionDuration),
+		aead:               aead,
+		headerClaims:       config.HeaderClaims,
+		forwardAccessToken: config.ForwardAccessToken,
+		next:               next,
+		name:               name,
+		client: http.Client{
+			CheckRedirect: func(r *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+			Timeout: 30 * time.Second,
+		},
+	}
+
+	if o.redirectPath == "" {
+		o.redirectPath = "/oauth2/callback"
+	}
+
+	if !strings.HasPrefix(o.redirectPath, "/") {
+		return nil, fmt.Errorf("the redirect path %q must start with a /", o.redirectPath)
+	}
+
+	if o.sessionCookie == "" {
+		o.sessionCookie = "_traefik_oidc"
+	}
+	o.stateCookie = o.sessionCookie + "_state"
+
+	if o.sessionDuration <= 0 {
+		o.sessionDuration = 24 * time.Hour
+	}
+
+	if !containsScope(o.scopes, "openid") {
+		o.scopes = append([]string{"openid"}, o.scopes...)
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = tlsConfig
+		o.client.Transport = tr
+	}
+
+	o.provider = &oidcProvider{issuer: config.Issuer, client: &o.client}
+
+	return o, nil
+}
+
+func (o *oidc) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return o.name, ext.SpanKindRPCClientEnum
+}
+
+func (o *oidc) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	if req.URL.Path == o.redirectPath {
+		o.callback(rw, req)
+		return
+	}
+
+	session := &oidcSession{}
+	if err := o.readCookie(req, o.sessionCookie, session); err != nil {
+		logger.Debugf("No valid session: %v", err)
+		o.authenticate(rw, req)
+		return
+	}
+
+	now := time.Now().Unix()
+	if now >= session.End {
+		logger.Debug("Session ended")
+		o.authenticate(rw, req)
+		return
+	}
+
+	if now >= session.Expiry {
+		if err := o.refresh(req, session); err != nil {
+			logger.Debugf("Unable to refresh the session: %v", err)
+			o.authenticate(rw, req)
+			return
+		}
+
+		if err := o.setCookie(rw, req, o.sessionCookie, session, time.Unix(session.End, 0)); err != nil {
+			logMessage := fmt.Sprintf("Error while storing the session: %v", err)
+			logger.Error(logMessage)
+			tracing.SetErrorWithEvent(req, logMessage)
+
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	for headerName, claim := range o.headerClaims {
+		req.Header.Del(headerName)
+
+		if value, ok := session.Claims[claim]; ok && value != nil {
+			req.Header.Set(headerName, headerValue(value))
+		}
+	}
+
+	if o.forwardAccessToken && session.AccessToken != "" {
+		req.Header.Set(authorizationHeader, "Bearer "+session.AccessToken)
+	}
+
+	o.removeCookies(req)
+
+	o.next.ServeHTTP(rw, req)
+}
+
+// authenticate redirects the users to the provider, to start the authorization code flow.
+func (o *oidc) authenticate(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	// The users are redirected back to the original URL once authenticated, with a GET request:
+	// the other requests could not be replayed.
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		tracing.SetErrorWithEvent(req, "Unauthenticated request")
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	metadata, _, err := o.provider.discover(req.Context())
+	if err != nil {
+		logMessage := fmt.Sprintf("Error calling %s. Cause: %s", o.provider.issuer, err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	state := &oidcState{
+		State:    randomString(),
+		Nonce:    randomString(),
+		Verifier: randomString(),
+		URI:      req.URL.RequestURI(),
+	}
+
+	if err = o.setCookie(rw, req, o.stateCookie, state, time.Now().Add(oidcStateDuration)); err != nil {
+		logMessage := fmt.Sprintf("Error while storing the authentication state: %v", err)
+		logger.Error(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(state.Verifier))
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", o.clientID)
+	query.Set("redirect_uri", o.redirectURI(req))
+	query.Set("scope", strings.Join(o.scopes, " "))
+	query.Set("state", state.State)
+	query.Set("nonce", state.Nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	http.Redirect(rw, req, metadata.AuthorizationEndpoint+separator+query.Encode(), http.StatusFound)
+}
+
+// callback completes the authorization code flow, once the users are redirected back by the provider.
+func (o *oidc) callback(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	query := req.URL.Query()
+	if errorCode := query.Get("error"); errorCode != "" {
+		logMessage := fmt.Sprintf("Authentication refused by the provider: %s %s", errorCode, query.Get("error_description"))
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	state := &oidcState{}
+	if err := o.readCookie(req, o.stateCookie, state); err != nil {
+		logger.Debugf("No valid authentication state: %v", err)
+		tracing.SetErrorWithEvent(req, "No valid authentication state")
+
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(state.State)) != 1 {
+		logger.Debug("Unexpected authentication state")
+		tracing.SetErrorWithEvent(req, "Unexpected authentication state")
+
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", query.Get("code"))
+	form.Set("redirect_uri", o.redirectURI(req))
+	form.Set("code_verifier", state.Verifier)
+
+	tokens, err := o.requestTokens(req, form)
+	if err != nil {
+		logMessage := fmt.Sprintf("Error while exchanging the authorization code: %v", err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if tokens.IDToken == "" {
+		logger.Debug("No ID token returned by the provider")
+		tracing.SetErrorWithEvent(req, "No ID token returned by the provider")
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := o.provider.verifyIDToken(req.Context(), tokens.IDToken, o.clientID, state.Nonce)
+	if err != nil {
+		logMessage := fmt.Sprintf("Invalid ID token: %v", err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	end := time.Now().Add(o.sessionDuration)
+	session := &oidcSession{End: end.Unix()}
+	o.update(session, tokens, claims)
+
+	if err = o.setCookie(rw, req, o.sessionCookie, session, end); err != nil {
+		logMessage := fmt.Sprintf("Error while storing the session: %v", err)
+		logger.Error(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	o.deleteCookie(rw, req, o.stateCookie)
+
+	http.Redirect(rw, req, state.URI, http.StatusFound)
+}
+
+// refresh renews the tokens of the session with its refresh token.
+func (o *oidc) refresh(req *http.Request, session *oidcSession) error {
+	if session.RefreshToken == "" {
+		return errors.New("no refresh token")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", session.RefreshToken)
+
+	tokens, err := o.requestTokens(req, form)
+	if err != nil {
+		return err
+	}
+
+	var claims map[string]interface{}
+	if tokens.IDToken != "" {
+		claims, err = o.provider.verifyIDToken(req.Context(), tokens.IDToken, o.clientID, "")
+		if err != nil {
+			return err
+		}
+
+		if claims["sub"] != session.Claims["sub"] {
+			return errors.New("the refreshed ID token is issued for another subject")
+		}
+	}
+
+	o.update(session, tokens, claims)
+
+	return nil
+}
+
+// update stores the tokens, and the claims if any, in the session.
+func (o *oidc) update(session *oidcSession, tokens *tokenResponse, claims map[string]interface{}) {
+	// The tokens are refreshed at the end of the session if their expiration is unknown.
+	expiry := session.End
+	if claims != nil {
+		session.Claims = claims
+		if exp, ok := claims["exp"].(float64); ok && int64(exp) < expiry {
+			expiry = int64(exp)
+		}
+	}
+
+	if tokens.ExpiresIn > 0 {
+		if exp := time.Now().Unix() + tokens.ExpiresIn; exp < expiry {
+			expiry = exp
+		}
+	}
+	session.Expiry = expiry
+
+	// The access token is only kept when forwarded, as it makes the cookie larger.
+	if o.forwardAccessToken {
+		session.AccessToken = tokens.AccessToken
+	}
+
+	if tokens.RefreshToken != "" {
+		session.RefreshToken = tokens.RefreshToken
+	}
+}
+
+func (o *oidc) requestTokens(req *http.Request, form url.Values) (*tokenResponse, error) {
+	metadata, _, err := o.provider.discover(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	tokenReq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	tracing.LogRequest(tracing.GetSpan(req), tokenReq)
+
+	tokenReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	tokenReq.Header.Set("Accept", "application/json")
+	tokenReq.SetBasicAuth(url.QueryEscape(o.clientID), url.QueryEscape(o.clientSecret))
+
+	resp, err := o.client.Do(tokenReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	tokens := &tokenResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(tokens); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// redirectURI returns the URL to which the provider redirects the users, on the host of the request.
+func (o *oidc) redirectURI(req *http.Request) string {
+	scheme := "http"
+	if isSecure(req) {
+		scheme = "https"
+	}
+
+	return scheme + "://" + req.Host + o.redirectPath
+}
+
+// setCookie encrypts the value in a cookie, with the cookie name as additional data,
+// so that the value of a cookie cannot be used in another one.
+func (o *oidc) setCookie(rw http.ResponseWriter, req *http.Request, name string, value interface{}, expires time.Time) error {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, o.aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(o.aead.Seal(nonce, nonce, plaintext, []byte(name)))
+	if len(encoded) > maxCookieSize {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName)).
+			Warnf("The cookie %s is %d bytes long, which exceeds the size supported by most browsers", name, len(encoded))
+	}
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     name,
+		Value:    encoded,
+		Path:     "/",
+		Expires:  expires,
+		Secure:   isSecure(req),
+		HttpOnly: true,
+		// The state cookie must be sent when the provider redirects the users back.
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+func (o *oidc) readCookie(req *http.Request, name string, value interface{}) error {
+	cookie, err := req.Cookie(name)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return err
+	}
+
+	if len(sealed) < o.aead.NonceSize() {
+		return errors.New("malformed cookie")
+	}
+
+	plaintext, err := o.aead.Open(nil, sealed[:o.aead.NonceSize()], sealed[o.aead.NonceSize():], []byte(name))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(plaintext, value)
+}
+
+func (o *oidc) deleteCookie(rw http.ResponseWriter, req *http.Request, name string) {
+	http.SetCookie(rw, &http.Cookie{
+		Name:     name,
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   isSecure(req),
+		HttpOnly: true,
+	})
+}
+
+// removeCookies removes the cookies of the middleware from the request forwarded to the service.
+func (o *oidc) removeCookies(req *http.Request) {
+	cookies := req.Cookies()
+	req.Header.Del("Cookie")
+
+	for _, cookie := range cookies {
+		if cookie.Name != o.sessionCookie && cookie.Name != o.stateCookie {
+			req.AddCookie(cookie)
+		}
+	}
+}
+
+func isSecure(req *http.Request) bool {
+	if proto := req.Header.Get(forward.XForwardedProto); proto != "" {
+		return proto == "https"
+	}
+
+	return req.TLS != nil
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func randomString() string {
+	raw := make([]byte, 32)
+	_, _ = rand.Read(raw)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func sha256Sum(value string) []byte {
+	sum := sha256.Sum256([]byte(value))
+	return sum[:]
+}
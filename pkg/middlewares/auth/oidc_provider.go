@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// oidcMetadata holds the discovered endpoints of an OpenID Connect provider.
+type oidcMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcProvider discovers, and keeps, the endpoints and the signing keys of an OpenID Connect provider.
+// They are discovered on first use, so that an unavailable provider does not prevent the middleware from being created.
+type oidcProvider struct {
+	issuer string
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *oidcMetadata
+	keys     *keySet
+}
+
+// discover returns the endpoints of the provider, and the set of its signing keys.
+func (p *oidcProvider) discover(ctx context.Context) (*oidcMetadata, *keySet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, p.keys, nil
+	}
+
+	metadata := &oidcMetadata{}
+	if err := getJSON(ctx, p.client, strings.TrimSuffix(p.issuer, "/")+"/.well-known/openid-configuration", metadata); err != nil {
+		return nil, nil, fmt.Errorf("unable to discover the provider configuration: %w", err)
+	}
+
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(p.issuer, "/") {
+		return nil, nil, fmt.Errorf("the discovered issuer %q does not match the configured issuer %q", metadata.Issuer, p.issuer)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, nil, errors.New("the provider configuration lacks the authorization, token or keys endpoints")
+	}
+
+	p.metadata = metadata
+	p.keys = newKeySet(metadata.JWKSURI, p.client, defaultKeysMaxAge)
+
+	return p.metadata, p.keys, nil
+}
+
+// verifyIDToken checks the signature, the issuer, the audience, the expiration and the nonce of the ID token,
+// and returns its claims.
+func (p *oidcProvider) verifyIDToken(ctx context.Context, raw, clientID, nonce string) (map[string]interface{}, error) {
+	metadata, keys, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := keys.verify(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	if iss, _ := claims["iss"].(string); iss != metadata.Issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", iss)
+	}
+
+	if !hasAudience(claims["aud"], clientID) {
+		return nil, errors.New("the ID token is not issued for this client")
+	}
+
+	if exp, ok := claims["exp"].(float64); !ok || time.Now().Unix() >= int64(exp) {
+		return nil, errors.New("the ID token is expired")
+	}
+
+	if nonce != "" {
+		if n, _ := claims["nonce"].(string); n != nonce {
+			return nil, errors.New("unexpected nonce")
+		}
+	}
+
+	return claims, nil
+}
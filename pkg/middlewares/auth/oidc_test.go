@@ -0,0 +1,526 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	ptypes "github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSessionSecret = "0123456789abcdef0123456789abcdef"
+
+// testProvider is an OpenID Connect provider issuing ID tokens for the authorization codes it is given.
+type testProvider struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	nonce  string
+	claims map[string]interface{}
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &testProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(oidcMetadata{
+			Issuer:                p.URL,
+			AuthorizationEndpoint: p.URL + "/authorize",
+			TokenEndpoint:         p.URL + "/token",
+			JWKSURI:               p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+			"keys": []jsonWebKey{{
+				Kty: "RSA",
+				Kid: "key",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(rw http.ResponseWriter, req *http.Request) {
+		user, password, _ := req.BasicAuth()
+		if user != "client" || password != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch req.FormValue("grant_type") {
+		case "authorization_code":
+			if req.FormValue("code") != "code" || req.FormValue("code_verifier") == "" {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		case "refresh_token":
+			if req.FormValue("refresh_token") != "refresh" {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+
+		p.mu.Lock()
+		claims := map[string]interface{}{"nonce": p.nonce}
+		for k, v := range p.claims {
+			claims[k] = v
+		}
+		p.mu.Unlock()
+
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+			"access_token":  "access",
+			"refresh_token": "refresh",
+			"id_token":      signTestToken(t, p.key, "RS256", "key", p.idTokenClaims(claims)),
+			"expires_in":    3600,
+		})
+	})
+
+	p.Server = httptest.NewServer(mux)
+
+	return p
+}
+
+func (p *testProvider) idTokenClaims(claims map[string]interface{}) map[string]interface{} {
+	token := map[string]interface{}{
+		"iss": p.URL,
+		"aud": []string{"client", "other"},
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range claims {
+		token[k] = v
+	}
+
+	return token
+}
+
+func (p *testProvider) setClaims(nonce string, claims map[string]interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nonce = nonce
+	p.claims = claims
+}
+
+func signTestToken(t *testing.T, key crypto.Signer, alg, kid string, claims map[string]interface{}) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		require.NoError(t, err)
+		signature = make([]byte, 64)
+		rBytes, sBytes := r.Bytes(), s.Bytes()
+		copy(signature[32-len(rBytes):32], rBytes)
+		copy(signature[64-len(sBytes):], sBytes)
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestNewOIDC(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.OIDC
+		expectedError bool
+	}{
+		{
+			desc: "valid configuration",
+			config: dynamic.OIDC{
+				Issuer:        "https://accounts.example.com",
+				ClientID:      "client",
+				SessionSecret: testSessionSecret,
+			},
+		},
+		{
+			desc: "missing issuer",
+			config: dynamic.OIDC{
+				ClientID:      "client",
+				SessionSecret: testSessionSecret,
+			},
+			expectedError: true,
+		},
+		{
+			desc: "missing client ID",
+			config: dynamic.OIDC{
+				Issuer:        "https://accounts.example.com",
+				SessionSecret: testSessionSecret,
+			},
+			expectedError: true,
+		},
+		{
+			desc: "short session secret",
+			config: dynamic.OIDC{
+				Issuer:        "https://accounts.example.com",
+				ClientID:      "client",
+				SessionSecret: "secret",
+			},
+			expectedError: true,
+		},
+		{
+			desc: "relative redirect path",
+			config: dynamic.OIDC{
+				Issuer:        "https://accounts.example.com",
+				ClientID:      "client",
+				SessionSecret: testSessionSecret,
+				RedirectPath:  "callback",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewOIDC(context.Background(), http.NotFoundHandler(), test.config, "oidc")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOIDC(t *testing.T) {
+	provider := newTestProvider(t)
+	defer provider.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-User", req.Header.Get("X-User"))
+		rw.Header().Set("X-Groups", req.Header.Get("X-Groups"))
+		rw.Header().Set("X-Missing", req.Header.Get("X-Missing"))
+		rw.Header().Set("X-Authorization", req.Header.Get("Authorization"))
+		rw.Header().Set("X-Cookie", req.Header.Get("Cookie"))
+	})
+
+	handler, err := NewOIDC(context.Background(), next, dynamic.OIDC{
+		Issuer:          provider.URL,
+		ClientID:        "client",
+		ClientSecret:    "secret",
+		Scopes:          []string{"email"},
+		RedirectPath:    "/oauth2/callback",
+		SessionSecret:   testSessionSecret,
+		SessionCookie:   "session",
+		SessionDuration: ptypes.Duration(time.Hour),
+		HeaderClaims: map[string]string{
+			"X-User":    "email",
+			"X-Groups":  "groups",
+			"X-Missing": "missing",
+		},
+		ForwardAccessToken: true,
+	}, "oidc")
+	require.NoError(t, err)
+
+	// An unauthenticated request is redirected to the provider.
+	req := httptest.NewRequest(http.MethodGet, "http://app.localhost/foo?bar=baz", nil)
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusFound, rw.Code)
+
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, provider.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+
+	query := location.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "client", query.Get("client_id"))
+	assert.Equal(t, "http://app.localhost/oauth2/callback", query.Get("redirect_uri"))
+	assert.Equal(t, "openid email", query.Get("scope"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.NotEmpty(t, query.Get("code_challenge"))
+
+	stateCookie := findCookie(rw.Result().Cookies(), "session_state")
+	require.NotNil(t, stateCookie)
+
+	// A request which cannot be replayed after the authentication is refused.
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "http://app.localhost/foo", nil))
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+	provider.setClaims(query.Get("nonce"), map[string]interface{}{
+		"email":  "alice@example.com",
+		"groups": []string{"admin", "dev"},
+	})
+
+	// The callback with another state is refused.
+	req = httptest.NewRequest(http.MethodGet, "http://app.localhost/oauth2/callback?code=code&state=other", nil)
+	req.AddCookie(stateCookie)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+
+	// The callback establishes the session, and redirects to the original URL.
+	req = httptest.NewRequest(http.MethodGet, "http://app.localhost/oauth2/callback?code=code&state="+url.QueryEscape(query.Get("state")), nil)
+	req.AddCookie(stateCookie)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusFound, rw.Code)
+	assert.Equal(t, "/foo?bar=baz", rw.Header().Get("Location"))
+
+	sessionCookie := findCookie(rw.Result().Cookies(), "session")
+	require.NotNil(t, sessionCookie)
+	assert.True(t, sessionCookie.HttpOnly)
+
+	// The claims are forwarded with the authenticated requests, and the cookies of the middleware are not.
+	req = httptest.NewRequest(http.MethodGet, "http://app.localhost/foo", nil)
+	req.Header.Set("X-Missing", "forged")
+	req.AddCookie(sessionCookie)
+	req.AddCookie(&http.Cookie{Name: "app", Value: "value"})
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "alice@example.com", rw.Header().Get("X-User"))
+	assert.Equal(t, "admin,dev", rw.Header().Get("X-Groups"))
+	assert.Empty(t, rw.Header().Get("X-Missing"))
+	assert.Equal(t, "Bearer access", rw.Header().Get("X-Authorization"))
+	assert.Equal(t, "app=value", rw.Header().Get("X-Cookie"))
+
+	// A tampered session is not accepted.
+	req = httptest.NewRequest(http.MethodGet, "http://app.localhost/foo", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: sessionCookie.Value[:len(sessionCookie.Value)-2] + "AA"})
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusFound, rw.Code)
+}
+
+func TestOIDC_refresh(t *testing.T) {
+	provider := newTestProvider(t)
+	defer provider.Close()
+
+	provider.setClaims("", map[string]interface{}{"email": "alice@example.org"})
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-User", req.Header.Get("X-User"))
+	})
+
+	handler, err := NewOIDC(context.Background(), next, dynamic.OIDC{
+		Issuer:        provider.URL,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		SessionSecret: testSessionSecret,
+		HeaderClaims:  map[string]string{"X-User": "email"},
+	}, "oidc")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc            string
+		session         oidcSession
+		expectedStatus  int
+		expectedUser    string
+		expectedRefresh bool
+	}{
+		{
+			desc: "valid session",
+			session: oidcSession{
+				Claims: map[string]interface{}{"sub": "alice", "email": "alice@example.com"},
+				Expiry: time.Now().Add(time.Minute).Unix(),
+				End:    time.Now().Add(time.Hour).Unix(),
+			},
+			expectedStatus: http.StatusOK,
+			expectedUser:   "alice@example.com",
+		},
+		{
+			desc: "expired tokens",
+			session: oidcSession{
+				Claims:       map[string]interface{}{"sub": "alice", "email": "alice@example.com"},
+				RefreshToken: "refresh",
+				Expiry:       time.Now().Add(-time.Minute).Unix(),
+				End:          time.Now().Add(time.Hour).Unix(),
+			},
+			expectedStatus:  http.StatusOK,
+			expectedUser:    "alice@example.org",
+			expectedRefresh: true,
+		},
+		{
+			desc: "refresh of another subject",
+			session: oidcSession{
+				Claims:       map[string]interface{}{"sub": "bob"},
+				RefreshToken: "refresh",
+				Expiry:       time.Now().Add(-time.Minute).Unix(),
+				End:          time.Now().Add(time.Hour).Unix(),
+			},
+			expectedStatus: http.StatusFound,
+		},
+		{
+			desc: "expired tokens without refresh token",
+			session: oidcSession{
+				Claims: map[string]interface{}{"sub": "alice"},
+				Expiry: time.Now().Add(-time.Minute).Unix(),
+				End:    time.Now().Add(time.Hour).Unix(),
+			},
+			expectedStatus: http.StatusFound,
+		},
+		{
+			desc: "ended session",
+			session: oidcSession{
+				Claims:       map[string]interface{}{"sub": "alice"},
+				RefreshToken: "refresh",
+				Expiry:       time.Now().Add(time.Minute).Unix(),
+				End:          time.Now().Add(-time.Minute).Unix(),
+			},
+			expectedStatus: http.StatusFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://app.localhost/", nil)
+
+			recorder := httptest.NewRecorder()
+			require.NoError(t, handler.(*oidc).setCookie(recorder, req, "_traefik_oidc", test.session, time.Now().Add(time.Hour)))
+			req.AddCookie(recorder.Result().Cookies()[0])
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+			assert.Equal(t, test.expectedUser, rw.Header().Get("X-User"))
+			assert.Equal(t, test.expectedRefresh, findCookie(rw.Result().Cookies(), "_traefik_oidc") != nil)
+		})
+	}
+}
+
+func TestOIDCProvider_verifyIDToken(t *testing.T) {
+	provider := newTestProvider(t)
+	defer provider.Close()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		token         string
+		expectedError bool
+	}{
+		{
+			desc:  "valid token",
+			token: signTestToken(t, provider.key, "RS256", "key", provider.idTokenClaims(map[string]interface{}{"nonce": "nonce"})),
+		},
+		{
+			desc:          "invalid signature",
+			token:         signTestToken(t, otherKey, "RS256", "key", provider.idTokenClaims(map[string]interface{}{"nonce": "nonce"})),
+			expectedError: true,
+		},
+		{
+			desc:          "unknown key",
+			token:         signTestToken(t, provider.key, "RS256", "other", provider.idTokenClaims(map[string]interface{}{"nonce": "nonce"})),
+			expectedError: true,
+		},
+		{
+			desc:          "algorithm not matching the key",
+			token:         signTestToken(t, provider.key, "ES256", "key", provider.idTokenClaims(map[string]interface{}{"nonce": "nonce"})),
+			expectedError: true,
+		},
+		{
+			desc:          "other issuer",
+			token:         signTestToken(t, provider.key, "RS256", "key", provider.idTokenClaims(map[string]interface{}{"nonce": "nonce", "iss": "https://other.example.com"})),
+			expectedError: true,
+		},
+		{
+			desc:          "other audience",
+			token:         signTestToken(t, provider.key, "RS256", "key", provider.idTokenClaims(map[string]interface{}{"nonce": "nonce", "aud": "other"})),
+			expectedError: true,
+		},
+		{
+			desc:          "expired",
+			token:         signTestToken(t, provider.key, "RS256", "key", provider.idTokenClaims(map[string]interface{}{"nonce": "nonce", "exp": time.Now().Add(-time.Minute).Unix()})),
+			expectedError: true,
+		},
+		{
+			desc:          "other nonce",
+			token:         signTestToken(t, provider.key, "RS256", "key", provider.idTokenClaims(map[string]interface{}{"nonce": "other"})),
+			expectedError: true,
+		},
+		{
+			desc:          "malformed",
+			token:         "foo.bar",
+			expectedError: true,
+		},
+	}
+
+	p := &oidcProvider{issuer: provider.URL, client: http.DefaultClient}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			claims, err := p.verifyIDToken(context.Background(), test.token, "client", "nonce")
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "alice", claims["sub"])
+		})
+	}
+}
+
+func TestVerifySignature_ecdsa(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	token := signTestToken(t, key, "ES256", "key", map[string]interface{}{"sub": "alice"})
+	parts := strings.Split(token, ".")
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+
+	assert.NoError(t, verifySignature("ES256", &key.PublicKey, []byte(parts[0]+"."+parts[1]), signature))
+	assert.Error(t, verifySignature("ES256", &key.PublicKey, []byte(parts[0]+".foo"), signature))
+	assert.Error(t, verifySignature("RS256", &key.PublicKey, []byte(parts[0]+"."+parts[1]), signature))
+}
+
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+
+	return nil
+}
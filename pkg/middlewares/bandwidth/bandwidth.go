@@ -0,0 +1,239 @@
+package bandwidth
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+const (
+	typeName       = "Bandwidth"
+	nameEntrypoint = "bandwidth-entrypoint"
+	nameRouter     = "bandwidth-router"
+)
+
+// bandwidth is a middleware that accounts for the bytes of the request and response bodies,
+// and rejects the requests of a router which exceeded its monthly quota.
+// On an entry point, it also observes the size distribution of the headers and bodies.
+type bandwidth struct {
+	next              http.Handler
+	name              string
+	reqsBytesCounter  gokitmetrics.Counter
+	respsBytesCounter gokitmetrics.Counter
+	sizes             *sizeHistograms
+	tracker           *QuotaTracker
+	quota             *dynamic.RouterQuota
+}
+
+type sizeHistograms struct {
+	reqHeaders  gokitmetrics.Histogram
+	reqBody     gokitmetrics.Histogram
+	respHeaders gokitmetrics.Histogram
+	respBody    gokitmetrics.Histogram
+}
+
+// NewEntryPointMiddleware creates a new bandwidth middleware for an entry point.
+func NewEntryPointMiddleware(ctx context.Context, next http.Handler, registry metrics.Registry, entryPointName string) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, nameEntrypoint, typeName)).Debug("Creating middleware")
+
+	return &bandwidth{
+		next:              next,
+		name:              entryPointName,
+		reqsBytesCounter:  registry.EntryPointReqsBytesCounter().With("entrypoint", entryPointName),
+		respsBytesCounter: registry.EntryPointRespsBytesCounter().With("entrypoint", entryPointName),
+		sizes: &sizeHistograms{
+			reqHeaders:  registry.EntryPointReqHeadersBytesHistogram().With("entrypoint", entryPointName),
+			reqBody:     registry.EntryPointReqBodyBytesHistogram().With("entrypoint", entryPointName),
+			respHeaders: registry.EntryPointRespHeadersBytesHistogram().With("entrypoint", entryPointName),
+			respBody:    registry.EntryPointRespBodyBytesHistogram().With("entrypoint", entryPointName),
+		},
+	}
+}
+
+// NewRouterMiddleware creates a new bandwidth middleware for a router, enforcing its quota if any.
+func NewRouterMiddleware(ctx context.Context, next http.Handler, registry metrics.Registry, tracker *QuotaTracker, routerName string, quota *dynamic.RouterQuota) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, nameRouter, typeName)).Debug("Creating middleware")
+
+	b := &bandwidth{
+		next:              next,
+		name:              routerName,
+		reqsBytesCounter:  registry.RouterReqsBytesCounter().With("router", routerName),
+		respsBytesCounter: registry.RouterRespsBytesCounter().With("router", routerName),
+	}
+
+	if quota != nil && quota.MonthlyBytes > 0 {
+		b.tracker = tracker
+		b.quota = quota
+	}
+
+	return b
+}
+
+// WrapEntryPointHandler wraps the bandwidth middleware of an entry point in an alice.Constructor.
+func WrapEntryPointHandler(ctx context.Context, registry metrics.Registry, entryPointName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewEntryPointMiddleware(ctx, next, registry, entryPointName), nil
+	}
+}
+
+// WrapRouterHandler wraps the bandwidth middleware of a router in an alice.Constructor.
+func WrapRouterHandler(ctx context.Context, registry metrics.Registry, tracker *QuotaTracker, routerName string, quota *dynamic.RouterQuota) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewRouterMiddleware(ctx, next, registry, tracker, routerName, quota), nil
+	}
+}
+
+func (b *bandwidth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if b.quota != nil && b.tracker.Usage(b.name) >= b.quota.MonthlyBytes {
+		logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), nameRouter, typeName))
+		logger.Debugf("Rejecting request, the monthly quota of the router %s (%d bytes) is exceeded", b.name, b.quota.MonthlyBytes)
+
+		b.writeOverQuota(rw)
+		return
+	}
+
+	body := &countingReader{ReadCloser: req.Body}
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = body
+	}
+
+	recorder := newResponseRecorder(rw)
+
+	b.next.ServeHTTP(recorder, req)
+
+	reqBytes := atomic.LoadInt64(&body.size)
+	respBytes := recorder.getSize()
+
+	b.reqsBytesCounter.Add(float64(reqBytes))
+	b.respsBytesCounter.Add(float64(respBytes))
+
+	if b.sizes != nil {
+		b.sizes.reqHeaders.Observe(float64(requestHeadersSize(req)))
+		b.sizes.reqBody.Observe(float64(reqBytes))
+		if headersSize := recorder.getHeadersSize(); headersSize > 0 {
+			b.sizes.respHeaders.Observe(float64(headersSize))
+		}
+		b.sizes.respBody.Observe(float64(respBytes))
+	}
+
+	if b.quota != nil {
+		b.tracker.Add(b.name, reqBytes+respBytes)
+	}
+}
+
+func (b *bandwidth) writeOverQuota(rw http.ResponseWriter) {
+	statusCode := b.quota.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusTooManyRequests
+	}
+
+	message := b.quota.Message
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+
+	http.Error(rw, message, statusCode)
+}
+
+// countingReader counts the bytes read from a request body.
+// The size is accessed atomically, as the body can be read by another goroutine than the handler one.
+type countingReader struct {
+	io.ReadCloser
+	size int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	atomic.AddInt64(&c.size, int64(n))
+	return n, err
+}
+
+type recorder interface {
+	http.ResponseWriter
+	http.Flusher
+	getSize() int64
+	getHeadersSize() int64
+}
+
+func newResponseRecorder(rw http.ResponseWriter) recorder {
+	rec := &responseRecorder{ResponseWriter: rw}
+	if _, ok := rw.(http.CloseNotifier); !ok {
+		return rec
+	}
+	return &responseRecorderWithCloseNotify{rec}
+}
+
+// responseRecorder counts the bytes written to the response body, and the ones of the response headers.
+type responseRecorder struct {
+	http.ResponseWriter
+	size        int64
+	headersSize int64
+	wroteHeader bool
+}
+
+type responseRecorderWithCloseNotify struct {
+	*responseRecorder
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone away.
+func (r *responseRecorderWithCloseNotify) CloseNotify() <-chan bool {
+	return r.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+func (r *responseRecorder) getSize() int64 {
+	return r.size
+}
+
+// getHeadersSize returns the size of the response headers, or 0 if the connection was hijacked.
+func (r *responseRecorder) getHeadersSize() int64 {
+	if !r.wroteHeader {
+		// The response headers are sent when the handler returns.
+		return responseHeadersSize(http.StatusOK, r.Header())
+	}
+	return r.headersSize
+}
+
+// WriteHeader measures the response headers when they are sent.
+func (r *responseRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.headersSize = responseHeadersSize(code, r.Header())
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Write counts the bytes written to the response body.
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.headersSize = responseHeadersSize(http.StatusOK, r.Header())
+		r.wroteHeader = true
+	}
+
+	n, err := r.ResponseWriter.Write(b)
+	r.size += int64(n)
+	return n, err
+}
+
+// Hijack hijacks the connection.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,187 @@
+package bandwidth
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bytesRegistry struct {
+	metrics.Registry
+	reqsBytes  *testhelpers.CollectingCounter
+	respsBytes *testhelpers.CollectingCounter
+}
+
+func newBytesRegistry() *bytesRegistry {
+	return &bytesRegistry{
+		Registry:   metrics.NewVoidRegistry(),
+		reqsBytes:  &testhelpers.CollectingCounter{},
+		respsBytes: &testhelpers.CollectingCounter{},
+	}
+}
+
+func (r *bytesRegistry) RouterReqsBytesCounter() gokitmetrics.Counter {
+	return r.reqsBytes
+}
+
+func (r *bytesRegistry) RouterRespsBytesCounter() gokitmetrics.Counter {
+	return r.respsBytes
+}
+
+func TestBandwidth_accounting(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		_, _ = rw.Write([]byte("0123456789"))
+	})
+
+	registry := newBytesRegistry()
+	handler := NewRouterMiddleware(context.Background(), next, registry, NewQuotaTracker(), "demo", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost", strings.NewReader("hello"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, float64(5), registry.reqsBytes.CounterValue)
+	assert.Equal(t, []string{"router", "demo"}, registry.reqsBytes.LastLabelValues)
+	assert.Equal(t, float64(10), registry.respsBytes.CounterValue)
+}
+
+type sizesRegistry struct {
+	metrics.Registry
+	reqHeaders  *testhelpers.CollectingHistogram
+	reqBody     *testhelpers.CollectingHistogram
+	respHeaders *testhelpers.CollectingHistogram
+	respBody    *testhelpers.CollectingHistogram
+}
+
+func (r *sizesRegistry) EntryPointReqHeadersBytesHistogram() gokitmetrics.Histogram {
+	return r.reqHeaders
+}
+
+func (r *sizesRegistry) EntryPointReqBodyBytesHistogram() gokitmetrics.Histogram {
+	return r.reqBody
+}
+
+func (r *sizesRegistry) EntryPointRespHeadersBytesHistogram() gokitmetrics.Histogram {
+	return r.respHeaders
+}
+
+func (r *sizesRegistry) EntryPointRespBodyBytesHistogram() gokitmetrics.Histogram {
+	return r.respBody
+}
+
+func TestBandwidth_sizes(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		rw.Header().Set("Content-Type", "text/plain")
+		_, _ = rw.Write([]byte("0123456789"))
+	})
+
+	registry := &sizesRegistry{
+		Registry:    metrics.NewVoidRegistry(),
+		reqHeaders:  &testhelpers.CollectingHistogram{},
+		reqBody:     &testhelpers.CollectingHistogram{},
+		respHeaders: &testhelpers.CollectingHistogram{},
+		respBody:    &testhelpers.CollectingHistogram{},
+	}
+	handler := NewEntryPointMiddleware(context.Background(), next, registry, "web")
+
+	req := httptest.NewRequest(http.MethodPost, "/foo", strings.NewReader("hello"))
+	req.Header.Set("X-Foo", "bar")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// "POST /foo HTTP/1.1\r\n" + "Host: example.com\r\n" + "X-Foo: bar\r\n" + "\r\n"
+	assert.Equal(t, []float64{53}, registry.reqHeaders.Observations)
+	assert.Equal(t, []string{"entrypoint", "web"}, registry.reqHeaders.LastLabelValues)
+	assert.Equal(t, []float64{5}, registry.reqBody.Observations)
+	// "HTTP/1.1 200 OK\r\n" + "Content-Type: text/plain\r\n" + "\r\n"
+	assert.Equal(t, []float64{45}, registry.respHeaders.Observations)
+	assert.Equal(t, []float64{10}, registry.respBody.Observations)
+}
+
+func TestBandwidth_quota(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte("0123456789"))
+	})
+
+	testCases := []struct {
+		desc            string
+		quota           *dynamic.RouterQuota
+		expectedCodes   []int
+		expectedMessage string
+	}{
+		{
+			desc:          "no quota",
+			expectedCodes: []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			desc:            "default over-quota response",
+			quota:           &dynamic.RouterQuota{MonthlyBytes: 15},
+			expectedCodes:   []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+			expectedMessage: "Too Many Requests\n",
+		},
+		{
+			desc:            "custom over-quota response",
+			quota:           &dynamic.RouterQuota{MonthlyBytes: 10, StatusCode: http.StatusPaymentRequired, Message: "Quota exceeded"},
+			expectedCodes:   []int{http.StatusOK, http.StatusPaymentRequired, http.StatusPaymentRequired},
+			expectedMessage: "Quota exceeded\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewRouterMiddleware(context.Background(), next, metrics.NewVoidRegistry(), NewQuotaTracker(), "demo", test.quota)
+
+			var rw *httptest.ResponseRecorder
+			for _, expectedCode := range test.expectedCodes {
+				rw = httptest.NewRecorder()
+				handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+				assert.Equal(t, expectedCode, rw.Code)
+			}
+
+			if test.expectedMessage != "" {
+				assert.Equal(t, test.expectedMessage, rw.Body.String())
+			}
+		})
+	}
+}
+
+func TestQuotaTracker(t *testing.T) {
+	now := time.Date(2020, time.January, 31, 23, 0, 0, 0, time.UTC)
+
+	tracker := NewQuotaTracker()
+	tracker.now = func() time.Time { return now }
+
+	tracker.Add("foo", 10)
+	tracker.Add("foo", 5)
+	tracker.Add("bar", 1)
+
+	assert.Equal(t, int64(15), tracker.Usage("foo"))
+	assert.Equal(t, int64(1), tracker.Usage("bar"))
+
+	// The usage is reset when a new month begins.
+	now = now.Add(2 * time.Hour)
+
+	assert.Equal(t, int64(0), tracker.Usage("foo"))
+
+	tracker.Add("foo", 3)
+	assert.Equal(t, int64(3), tracker.Usage("foo"))
+}
@@ -0,0 +1,46 @@
+package bandwidth
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// requestHeadersSize returns the size of the request line and headers, as they are sent over HTTP/1.x,
+// which is what the maxHeaderBytes option of an entry point limits.
+func requestHeadersSize(req *http.Request) int64 {
+	uri := req.RequestURI
+	if uri == "" {
+		uri = req.URL.RequestURI()
+	}
+
+	// Request line, e.g. "GET /foo HTTP/1.1\r\n".
+	size := len(req.Method) + len(uri) + len(req.Proto) + 4
+
+	// The Host header is removed from the headers by the server.
+	if req.Host != "" {
+		size += len("Host: \r\n") + len(req.Host)
+	}
+
+	// The headers are followed by an empty line.
+	return int64(size + headerSize(req.Header) + 2)
+}
+
+// responseHeadersSize returns the size of the status line and headers of a response, as they are sent over HTTP/1.1.
+// The headers added by the server itself, such as Date or Content-Length, are not accounted.
+func responseHeadersSize(code int, header http.Header) int64 {
+	// Status line, e.g. "HTTP/1.1 200 OK\r\n".
+	size := len("HTTP/1.1") + len(strconv.Itoa(code)) + len(http.StatusText(code)) + 4
+
+	return int64(size + headerSize(header) + 2)
+}
+
+func headerSize(header http.Header) int {
+	var size int
+	for name, values := range header {
+		for _, value := range values {
+			// "Name: value\r\n"
+			size += len(name) + len(value) + 4
+		}
+	}
+	return size
+}
@@ -0,0 +1,58 @@
+package bandwidth
+
+import (
+	"sync"
+	"time"
+)
+
+// QuotaTracker keeps the byte usage of the routers for the current month.
+// It outlives the configuration reloads, so that the usage is not reset when the routers are rebuilt.
+type QuotaTracker struct {
+	mu     sync.Mutex
+	usages map[string]*usage
+	now    func() time.Time
+}
+
+type usage struct {
+	month int
+	bytes int64
+}
+
+// NewQuotaTracker creates a QuotaTracker.
+func NewQuotaTracker() *QuotaTracker {
+	return &QuotaTracker{
+		usages: make(map[string]*usage),
+		now:    time.Now,
+	}
+}
+
+// Add adds bytes to the usage of a router.
+func (t *QuotaTracker) Add(routerName string, bytes int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.current(routerName).bytes += bytes
+}
+
+// Usage returns the bytes used by a router during the current month.
+func (t *QuotaTracker) Usage(routerName string) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.current(routerName).bytes
+}
+
+// current returns the usage of a router for the current month, resetting it when a new month has begun.
+// It must be called with the lock held.
+func (t *QuotaTracker) current(routerName string) *usage {
+	now := t.now().UTC()
+	month := now.Year()*12 + int(now.Month())
+
+	u, ok := t.usages[routerName]
+	if !ok || u.month != month {
+		u = &usage{month: month}
+		t.usages[routerName] = u
+	}
+
+	return u
+}
@@ -0,0 +1,196 @@
+package bodytimeout
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+)
+
+const typeName = "BodyTimeout"
+
+// ErrTimeout is the error returned when reading a request body exceeds one of the timeouts of the router.
+var ErrTimeout = errors.New("request body read timeout")
+
+type connKey struct{}
+
+// ContextWithConn returns a copy of ctx carrying the client connection the requests are read from.
+// It is meant to be used as the ConnContext of the entry point servers.
+func ContextWithConn(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connKey{}, conn)
+}
+
+// bodyTimeout is a middleware enforcing the timeouts of the reading of the request bodies of a router.
+type bodyTimeout struct {
+	next        http.Handler
+	routerName  string
+	readTimeout time.Duration
+	idleTimeout time.Duration
+}
+
+// New creates a body timeout middleware.
+func New(ctx context.Context, next http.Handler, routerName string, config dynamic.RouterBodyTimeouts) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, routerName, typeName)).Debug("Creating middleware")
+
+	return &bodyTimeout{
+		next:        next,
+		routerName:  routerName,
+		readTimeout: time.Duration(config.ReadTimeout),
+		idleTimeout: time.Duration(config.IdleTimeout),
+	}
+}
+
+// WrapRouterHandler wraps the body timeout middleware of a router in an alice.Constructor.
+func WrapRouterHandler(ctx context.Context, routerName string, config dynamic.RouterBodyTimeouts) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return New(ctx, next, routerName, config), nil
+	}
+}
+
+func (b *bodyTimeout) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Body == nil || req.Body == http.NoBody || (b.readTimeout <= 0 && b.idleTimeout <= 0) {
+		b.next.ServeHTTP(rw, req)
+		return
+	}
+
+	body := &timeoutReader{
+		ReadCloser:  req.Body,
+		start:       time.Now(),
+		readTimeout: b.readTimeout,
+		idleTimeout: b.idleTimeout,
+	}
+
+	// With HTTP/2, the connection is shared by the streams, so the body is closed instead.
+	if conn, ok := req.Context().Value(connKey{}).(net.Conn); ok && req.ProtoMajor == 1 {
+		body.conn = conn
+	}
+
+	req.Body = body
+
+	b.next.ServeHTTP(rw, req)
+
+	body.stop()
+
+	if body.hasTimedOut() {
+		logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), b.routerName, typeName))
+		logger.Debugf("Timeout while reading the body of the request from %s", req.RemoteAddr)
+	}
+}
+
+// timeoutReader is a request body whose reads are bounded by the read and idle timeouts.
+// With HTTP/1, the timeouts are applied as read deadlines on the client connection,
+// otherwise the body is closed when they expire, which unblocks the pending read.
+type timeoutReader struct {
+	io.ReadCloser
+	conn        net.Conn
+	start       time.Time
+	readTimeout time.Duration
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	timer    *time.Timer
+	timedOut bool
+	eof      bool
+}
+
+func (r *timeoutReader) Read(p []byte) (int, error) {
+	if r.eof {
+		return r.ReadCloser.Read(p)
+	}
+
+	deadline := r.deadline()
+	if !deadline.After(time.Now()) {
+		r.setTimedOut()
+		return 0, ErrTimeout
+	}
+
+	if r.conn != nil {
+		if err := r.conn.SetReadDeadline(deadline); err != nil {
+			log.WithoutContext().Debugf("Unable to set the read deadline of the connection: %v", err)
+		}
+	} else {
+		r.startTimer(time.Until(deadline))
+	}
+
+	n, err := r.ReadCloser.Read(p)
+
+	r.stop()
+
+	if err == io.EOF {
+		// Past the end of the body, the connection belongs to the server again,
+		// which is watching it for the next request or the client going away.
+		r.eof = true
+		return n, err
+	}
+
+	if err != nil && (r.hasTimedOut() || isTimeout(err)) {
+		r.setTimedOut()
+		return n, ErrTimeout
+	}
+
+	return n, err
+}
+
+// deadline returns the deadline of the next read.
+func (r *timeoutReader) deadline() time.Time {
+	var deadline time.Time
+	if r.readTimeout > 0 {
+		deadline = r.start.Add(r.readTimeout)
+	}
+
+	if r.idleTimeout > 0 {
+		idle := time.Now().Add(r.idleTimeout)
+		if deadline.IsZero() || idle.Before(deadline) {
+			deadline = idle
+		}
+	}
+
+	return deadline
+}
+
+func (r *timeoutReader) startTimer(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.timer = time.AfterFunc(d, func() {
+		r.setTimedOut()
+		_ = r.ReadCloser.Close()
+	})
+}
+
+// stop stops the pending timer, if any.
+func (r *timeoutReader) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+}
+
+func (r *timeoutReader) setTimedOut() {
+	r.mu.Lock()
+	r.timedOut = true
+	r.mu.Unlock()
+}
+
+func (r *timeoutReader) hasTimedOut() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.timedOut
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
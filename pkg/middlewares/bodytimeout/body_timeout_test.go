@@ -0,0 +1,132 @@
+package bodytimeout
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readBody answers with a 408 when reading the body times out, like the proxy does.
+var readBody = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	_, err := ioutil.ReadAll(req.Body)
+	if errors.Is(err, ErrTimeout) {
+		rw.WriteHeader(http.StatusRequestTimeout)
+		return
+	}
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+})
+
+func TestBodyTimeout_http1(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   dynamic.RouterBodyTimeouts
+		chunks   []string
+		pause    time.Duration
+		expected int
+	}{
+		{
+			desc:     "body sent in time",
+			config:   dynamic.RouterBodyTimeouts{ReadTimeout: types.Duration(time.Second), IdleTimeout: types.Duration(500 * time.Millisecond)},
+			chunks:   []string{"hello", "world"},
+			pause:    10 * time.Millisecond,
+			expected: http.StatusOK,
+		},
+		{
+			desc:     "idle timeout",
+			config:   dynamic.RouterBodyTimeouts{IdleTimeout: types.Duration(100 * time.Millisecond)},
+			chunks:   []string{"hello", "world"},
+			pause:    300 * time.Millisecond,
+			expected: http.StatusRequestTimeout,
+		},
+		{
+			desc:     "read timeout",
+			config:   dynamic.RouterBodyTimeouts{ReadTimeout: types.Duration(150 * time.Millisecond), IdleTimeout: types.Duration(time.Second)},
+			chunks:   []string{"he", "ll", "ow", "or", "ld"},
+			pause:    60 * time.Millisecond,
+			expected: http.StatusRequestTimeout,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewUnstartedServer(New(context.Background(), readBody, "foo", test.config))
+			server.Config.ConnContext = ContextWithConn
+			server.Start()
+			defer server.Close()
+
+			conn, err := net.Dial("tcp", server.Listener.Addr().String())
+			require.NoError(t, err)
+			defer func() { _ = conn.Close() }()
+
+			body := strings.Join(test.chunks, "")
+			_, err = fmt.Fprintf(conn, "POST / HTTP/1.1\r\nHost: foo\r\nContent-Length: %d\r\n\r\n", len(body))
+			require.NoError(t, err)
+
+			for _, chunk := range test.chunks {
+				time.Sleep(test.pause)
+
+				// The server may have answered and closed the connection already.
+				if _, err = conn.Write([]byte(chunk)); err != nil {
+					break
+				}
+			}
+
+			resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, resp.StatusCode)
+		})
+	}
+}
+
+func TestBodyTimeout_withoutConn(t *testing.T) {
+	handler := New(context.Background(), readBody, "foo", dynamic.RouterBodyTimeouts{IdleTimeout: types.Duration(100 * time.Millisecond)})
+
+	pr, pw := io.Pipe()
+	defer func() { _ = pw.Close() }()
+
+	req := httptest.NewRequest(http.MethodPost, "http://foo/", pr)
+	req.ProtoMajor = 2
+
+	go func() {
+		_, _ = pw.Write([]byte("hello"))
+	}()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusRequestTimeout, recorder.Code)
+}
+
+func TestBodyTimeout_noBody(t *testing.T) {
+	handler := New(context.Background(), readBody, "foo", dynamic.RouterBodyTimeouts{IdleTimeout: types.Duration(time.Nanosecond)})
+
+	req := httptest.NewRequest(http.MethodGet, "http://foo/", nil)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
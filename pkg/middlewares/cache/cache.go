@@ -0,0 +1,493 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "Cache"
+)
+
+const (
+	defaultMaxEntrySize  int64 = 1024 * 1024
+	defaultMemoryMaxSize int64 = 64 * 1024 * 1024
+)
+
+// statusHeader is the header reporting how the cache handled the request (RFC 9211).
+const statusHeader = "Cache-Status"
+
+// notModifiedIgnoredHeaders are the headers of a 304 response which do not update the stored response.
+var notModifiedIgnoredHeaders = map[string]bool{
+	"Content-Length":    true,
+	"Content-Encoding":  true,
+	"Transfer-Encoding": true,
+	"Content-Range":     true,
+}
+
+// entry is a stored response.
+type entry struct {
+	Code   int         `json:"code"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+	// Date is when the response was generated, i.e. when it was received minus its initial age.
+	Date     time.Time     `json:"date"`
+	Lifetime time.Duration `json:"lifetime"`
+}
+
+func (e *entry) age(now time.Time) time.Duration {
+	if age := now.Sub(e.Date); age > 0 {
+		return age
+	}
+	return 0
+}
+
+func (e *entry) hasValidators() bool {
+	return e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != ""
+}
+
+// cache is a middleware storing the cacheable responses of the services,
+// and serving them to the next requests while they are fresh.
+type cache struct {
+	next         http.Handler
+	name         string
+	store        store
+	ttl          time.Duration
+	defaultTTL   time.Duration
+	maxEntrySize int64
+}
+
+// New creates a cache middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Cache, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.TTL < 0 || config.DefaultTTL < 0 {
+		return nil, errors.New("the TTLs must not be negative")
+	}
+
+	maxEntrySize := config.MaxEntrySize
+	if maxEntrySize <= 0 {
+		maxEntrySize = defaultMaxEntrySize
+	}
+
+	var st store
+	switch {
+	case config.Memory != nil && config.Redis != nil:
+		return nil, errors.New("a single storage can be configured")
+	case config.Redis != nil:
+		if config.Redis.Address == "" {
+			return nil, errors.New("the address of the Redis storage is required")
+		}
+		st = newRedisStore(config.Redis.Address, config.Redis.Password, config.Redis.DB, name)
+	default:
+		maxSize := defaultMemoryMaxSize
+		if config.Memory != nil && config.Memory.MaxSize > 0 {
+			maxSize = config.Memory.MaxSize
+		}
+		st = newMemoryStore(maxSize)
+	}
+
+	return &cache{
+		next:         next,
+		name:         name,
+		store:        st,
+		ttl:          time.Duration(config.TTL),
+		defaultTTL:   time.Duration(config.DefaultTTL),
+		maxEntrySize: maxEntrySize,
+	}, nil
+}
+
+func (c *cache) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *cache) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		c.serveUnsafe(rw, req)
+		return
+	}
+
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName))
+
+	reqCC := parseCacheControl(req.Header.Values("Cache-Control"))
+	if reqCC.has("no-store") {
+		rw.Header().Set(statusHeader, "traefik; fwd=bypass")
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	key := primaryKey(req)
+
+	stored, err := c.lookup(req, key)
+	if err != nil {
+		logger.Debugf("Error while looking up the cache: %v", err)
+	}
+
+	now := time.Now()
+
+	fwd := "uri-miss"
+	if stored != nil {
+		age := stored.age(now)
+		maxAge, hasMaxAge := reqCC.duration("max-age")
+
+		switch {
+		case reqCC.has("no-cache") || hasMaxAge && age > maxAge:
+			fwd = "request"
+		case age < stored.Lifetime:
+			c.serve(rw, req, stored, now, "traefik; hit")
+			return
+		default:
+			fwd = "stale"
+		}
+	}
+
+	outReq := req
+	revalidating := stored != nil && stored.hasValidators() && !isConditional(req)
+	if revalidating {
+		outReq = req.Clone(req.Context())
+		if tag := stored.Header.Get("ETag"); tag != "" {
+			outReq.Header.Set("If-None-Match", tag)
+		}
+		if lastModified := stored.Header.Get("Last-Modified"); lastModified != "" {
+			outReq.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	status := "traefik; fwd=" + fwd
+	recorder := newResponseRecorder(rw, c.maxEntrySize, status)
+	c.next.ServeHTTP(recorder, outReq)
+
+	if recorder.streaming {
+		// The response has already been forwarded to the client.
+		return
+	}
+
+	received := time.Now()
+
+	if revalidating && recorder.code == http.StatusNotModified {
+		c.refresh(req, key, stored, recorder.header, received)
+		c.serve(rw, req, stored, received, status+"; fwd-status=304")
+		return
+	}
+
+	if req.Method == http.MethodGet {
+		if d := lifetime(req, recorder.code, recorder.header, c.ttl, c.defaultTTL); d > 0 {
+			e := &entry{
+				Code:     recorder.code,
+				Header:   recorder.header.Clone(),
+				Body:     recorder.body.Bytes(),
+				Date:     received.Add(-initialAge(recorder.header)),
+				Lifetime: d,
+			}
+
+			if err := c.save(req, key, e); err != nil {
+				logger.Debugf("Error while storing the response: %v", err)
+			} else {
+				status += "; stored"
+			}
+		}
+	}
+
+	recorder.header.Set(statusHeader, status)
+	recorder.flushHeader()
+
+	if _, err := rw.Write(recorder.body.Bytes()); err != nil {
+		logger.Debugf("Error while writing response: %v", err)
+	}
+}
+
+// serveUnsafe forwards the requests with an unsafe method, and invalidates the stored response of their URI when they succeed (RFC 9111, section 4.4).
+func (c *cache) serveUnsafe(rw http.ResponseWriter, req *http.Request) {
+	recorder := &codeRecorder{ResponseWriter: rw, code: http.StatusOK}
+	c.next.ServeHTTP(recorder, req)
+
+	if req.Method == http.MethodOptions || req.Method == http.MethodTrace || recorder.code >= http.StatusBadRequest {
+		return
+	}
+
+	key := primaryKey(req)
+	for _, k := range []string{varyKey(key), entryKey(key)} {
+		if err := c.store.Delete(req.Context(), k); err != nil {
+			log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName)).Debugf("Error while invalidating the stored response: %v", err)
+			return
+		}
+	}
+}
+
+// lookup returns the stored response matching the request, taking the Vary header of the stored response into account.
+func (c *cache) lookup(req *http.Request, key string) (*entry, error) {
+	ctx := req.Context()
+
+	k := entryKey(key)
+
+	vary, ok, err := c.store.Get(ctx, varyKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		k = variantKey(key, strings.Split(string(vary), ","), req.Header)
+	}
+
+	raw, ok, err := c.store.Get(ctx, k)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	e := &entry{}
+	if err = json.Unmarshal(raw, e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// save stores the response, along with the names of the headers it varies on, if any.
+func (c *cache) save(req *http.Request, key string, e *entry) error {
+	ctx := req.Context()
+
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// A stale response with validators is kept for as long again, so that it can be revalidated.
+	ttl := e.Lifetime - e.age(time.Now())
+	if e.hasValidators() {
+		ttl += e.Lifetime
+	}
+
+	var names []string
+	for _, value := range e.Header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		if err = c.store.Delete(ctx, varyKey(key)); err != nil {
+			return err
+		}
+		return c.store.Set(ctx, entryKey(key), raw, ttl)
+	}
+
+	if err = c.store.Set(ctx, varyKey(key), []byte(strings.Join(names, ",")), ttl); err != nil {
+		return err
+	}
+	return c.store.Set(ctx, variantKey(key, names, req.Header), raw, ttl)
+}
+
+// refresh updates the stored response with the headers of the 304 response validating it (RFC 9111, section 4.3.4).
+func (c *cache) refresh(req *http.Request, key string, e *entry, header http.Header, received time.Time) {
+	for name, values := range header {
+		if !notModifiedIgnoredHeaders[name] {
+			e.Header[name] = values
+		}
+	}
+
+	e.Date = received.Add(-initialAge(header))
+
+	d := lifetime(req, e.Code, e.Header, c.ttl, c.defaultTTL)
+	if d <= 0 {
+		return
+	}
+	e.Lifetime = d
+
+	if err := c.save(req, key, e); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName)).Debugf("Error while storing the response: %v", err)
+	}
+}
+
+// serve writes the stored response.
+func (c *cache) serve(rw http.ResponseWriter, req *http.Request, e *entry, now time.Time, status string) {
+	header := rw.Header()
+	for name, values := range e.Header {
+		header[name] = append([]string(nil), values...)
+	}
+	header.Set("Age", strconv.FormatInt(int64(e.age(now)/time.Second), 10))
+	header.Set(statusHeader, status)
+
+	rw.WriteHeader(e.Code)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := rw.Write(e.Body); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName)).Debugf("Error while writing response: %v", err)
+	}
+}
+
+// primaryKey identifies the stored responses of the target URI of the request, the HEAD requests sharing the ones of the GET requests.
+func primaryKey(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + strings.ToLower(req.Host) + req.URL.RequestURI()
+}
+
+func entryKey(key string) string {
+	return "entry:" + key
+}
+
+func varyKey(key string) string {
+	return "vary:" + key
+}
+
+// variantKey identifies the stored response of the values of the request headers the response varies on.
+func variantKey(key string, names []string, header http.Header) string {
+	hash := sha256.New()
+	for _, name := range names {
+		_, _ = hash.Write([]byte(strings.ToLower(name) + ":"))
+		for _, value := range header.Values(name) {
+			_, _ = hash.Write([]byte(strings.Join(strings.Fields(value), " ") + ","))
+		}
+		_, _ = hash.Write([]byte{'\n'})
+	}
+
+	return entryKey(key) + "#" + hex.EncodeToString(hash.Sum(nil))
+}
+
+func isConditional(req *http.Request) bool {
+	return req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+}
+
+// responseRecorder buffers the response up to maxBodySize,
+// after what it switches to streaming the response to the client.
+type responseRecorder struct {
+	rw          http.ResponseWriter
+	maxBodySize int64
+	// status is the cache status reported to the client, when the response is streamed.
+	status string
+
+	header      http.Header
+	code        int
+	wroteHeader bool
+	body        bytes.Buffer
+	streaming   bool
+}
+
+func newResponseRecorder(rw http.ResponseWriter, maxBodySize int64, status string) *responseRecorder {
+	return &responseRecorder{
+		rw:          rw,
+		maxBodySize: maxBodySize,
+		status:      status,
+		header:      make(http.Header),
+		code:        http.StatusOK,
+	}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.wroteHeader {
+		return
+	}
+
+	r.wroteHeader = true
+	r.code = code
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.streaming {
+		return r.rw.Write(p)
+	}
+
+	r.wroteHeader = true
+
+	if int64(r.body.Len()+len(p)) > r.maxBodySize {
+		if err := r.stream(); err != nil {
+			return 0, err
+		}
+		return r.rw.Write(p)
+	}
+
+	return r.body.Write(p)
+}
+
+// flushHeader copies the recorded headers to the client response, and writes its status code.
+func (r *responseRecorder) flushHeader() {
+	header := r.rw.Header()
+	for name, values := range r.header {
+		header[name] = values
+	}
+
+	r.rw.WriteHeader(r.code)
+}
+
+// stream forwards the buffered response to the client, and disables the buffering.
+func (r *responseRecorder) stream() error {
+	if r.streaming {
+		return nil
+	}
+
+	r.streaming = true
+	r.header.Set(statusHeader, r.status)
+	r.flushHeader()
+
+	_, err := r.rw.Write(r.body.Bytes())
+	r.body.Reset()
+
+	return err
+}
+
+// Flush sends any buffered data to the client.
+// A flushed response cannot be stored anymore.
+func (r *responseRecorder) Flush() {
+	if err := r.stream(); err != nil {
+		return
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := r.rw.(http.Hijacker); ok {
+		r.streaming = true
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.rw)
+}
+
+// codeRecorder records the status code of the response.
+type codeRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *codeRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Flush sends any buffered data to the client.
+func (r *codeRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
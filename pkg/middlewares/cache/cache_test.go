@@ -0,0 +1,314 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.Cache
+		expectedErr bool
+	}{
+		{
+			desc:   "default storage",
+			config: dynamic.Cache{},
+		},
+		{
+			desc:   "memory storage",
+			config: dynamic.Cache{Memory: &dynamic.CacheMemory{MaxSize: 1024}},
+		},
+		{
+			desc:   "redis storage",
+			config: dynamic.Cache{Redis: &dynamic.CacheRedis{Address: "127.0.0.1:6379"}},
+		},
+		{
+			desc:        "redis storage without address",
+			config:      dynamic.Cache{Redis: &dynamic.CacheRedis{}},
+			expectedErr: true,
+		},
+		{
+			desc: "both storages",
+			config: dynamic.Cache{
+				Memory: &dynamic.CacheMemory{},
+				Redis:  &dynamic.CacheRedis{Address: "127.0.0.1:6379"},
+			},
+			expectedErr: true,
+		},
+		{
+			desc:        "negative TTL",
+			config:      dynamic.Cache{TTL: -1},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, "foo")
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCache(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.Cache
+		cacheControl   string
+		requests       []*http.Request
+		expectedCalls  int32
+		expectedStatus []string
+	}{
+		{
+			desc:          "fresh response",
+			cacheControl:  "max-age=60",
+			requests:      []*http.Request{get("/foo", nil), get("/foo", nil)},
+			expectedCalls: 1,
+			expectedStatus: []string{
+				"traefik; fwd=uri-miss; stored",
+				"traefik; hit",
+			},
+		},
+		{
+			desc:          "no-store response",
+			cacheControl:  "no-store",
+			requests:      []*http.Request{get("/foo", nil), get("/foo", nil)},
+			expectedCalls: 2,
+			expectedStatus: []string{
+				"traefik; fwd=uri-miss",
+				"traefik; fwd=uri-miss",
+			},
+		},
+		{
+			desc:          "no explicit expiration time",
+			requests:      []*http.Request{get("/foo", nil), get("/foo", nil)},
+			expectedCalls: 2,
+			expectedStatus: []string{
+				"traefik; fwd=uri-miss",
+				"traefik; fwd=uri-miss",
+			},
+		},
+		{
+			desc:          "default TTL",
+			config:        dynamic.Cache{DefaultTTL: types.Duration(time.Minute)},
+			requests:      []*http.Request{get("/foo", nil), get("/foo", nil)},
+			expectedCalls: 1,
+			expectedStatus: []string{
+				"traefik; fwd=uri-miss; stored",
+				"traefik; hit",
+			},
+		},
+		{
+			desc:          "TTL override",
+			config:        dynamic.Cache{TTL: types.Duration(2 * time.Minute)},
+			cacheControl:  "max-age=60",
+			requests:      []*http.Request{get("/foo", nil), get("/foo", nil)},
+			expectedCalls: 1,
+			expectedStatus: []string{
+				"traefik; fwd=uri-miss; stored",
+				"traefik; hit",
+			},
+		},
+		{
+			desc:          "other URI",
+			cacheControl:  "max-age=60",
+			requests:      []*http.Request{get("/foo", nil), get("/bar", nil)},
+			expectedCalls: 2,
+			expectedStatus: []string{
+				"traefik; fwd=uri-miss; stored",
+				"traefik; fwd=uri-miss; stored",
+			},
+		},
+		{
+			desc:          "request no-cache",
+			cacheControl:  "max-age=60",
+			requests:      []*http.Request{get("/foo", nil), get("/foo", http.Header{"Cache-Control": {"no-cache"}})},
+			expectedCalls: 2,
+			expectedStatus: []string{
+				"traefik; fwd=uri-miss; stored",
+				"traefik; fwd=request; stored",
+			},
+		},
+		{
+			desc:          "request no-store",
+			cacheControl:  "max-age=60",
+			requests:      []*http.Request{get("/foo", http.Header{"Cache-Control": {"no-store"}}), get("/foo", nil)},
+			expectedCalls: 2,
+			expectedStatus: []string{
+				"traefik; fwd=bypass",
+				"traefik; fwd=uri-miss; stored",
+			},
+		},
+		{
+			desc:          "HEAD request served from a GET response",
+			cacheControl:  "max-age=60",
+			requests:      []*http.Request{get("/foo", nil), httptest.NewRequest(http.MethodHead, "http://example.com/foo", nil)},
+			expectedCalls: 1,
+			expectedStatus: []string{
+				"traefik; fwd=uri-miss; stored",
+				"traefik; hit",
+			},
+		},
+		{
+			desc:         "invalidation by an unsafe request",
+			cacheControl: "max-age=60",
+			requests: []*http.Request{
+				get("/foo", nil),
+				httptest.NewRequest(http.MethodPost, "http://example.com/foo", nil),
+				get("/foo", nil),
+			},
+			expectedCalls: 3,
+			expectedStatus: []string{
+				"traefik; fwd=uri-miss; stored",
+				"",
+				"traefik; fwd=uri-miss; stored",
+			},
+		},
+		{
+			desc:         "vary",
+			cacheControl: "max-age=60",
+			requests: []*http.Request{
+				get("/vary", http.Header{"Accept-Language": {"fr"}}),
+				get("/vary", http.Header{"Accept-Language": {"en"}}),
+				get("/vary", http.Header{"Accept-Language": {"fr"}}),
+			},
+			expectedCalls: 2,
+			expectedStatus: []string{
+				"traefik; fwd=uri-miss; stored",
+				"traefik; fwd=uri-miss; stored",
+				"traefik; hit",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&calls, 1)
+
+				if test.cacheControl != "" {
+					rw.Header().Set("Cache-Control", test.cacheControl)
+				}
+				if req.URL.Path == "/vary" {
+					rw.Header().Set("Vary", "Accept-Language")
+				}
+
+				_, _ = rw.Write([]byte("hello " + req.Header.Get("Accept-Language")))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "foo")
+			require.NoError(t, err)
+
+			for i, req := range test.requests {
+				rw := httptest.NewRecorder()
+				handler.ServeHTTP(rw, req)
+
+				assert.Equal(t, http.StatusOK, rw.Code)
+				assert.Equal(t, test.expectedStatus[i], rw.Header().Get(statusHeader), "request %d", i)
+
+				if req.Method == http.MethodHead {
+					assert.Empty(t, rw.Body.String())
+				} else {
+					assert.Equal(t, "hello "+req.Header.Get("Accept-Language"), rw.Body.String())
+				}
+			}
+
+			assert.Equal(t, test.expectedCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestCache_revalidation(t *testing.T) {
+	var calls, notModified int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=0, must-revalidate")
+		rw.Header().Set("ETag", `"v1"`)
+
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		_, _ = rw.Write([]byte("hello"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "foo")
+	require.NoError(t, err)
+
+	// A zero max-age response cannot be stored, even with validators.
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, get("/foo", nil))
+	assert.Equal(t, "traefik; fwd=uri-miss", rw.Header().Get(statusHeader))
+
+	st := handler.(*cache).store
+	e := `{"code":200,"header":{"Etag":["\"v1\""]},"body":"aGVsbG8=","date":"2020-01-01T00:00:00Z","lifetime":1000000000}`
+	require.NoError(t, st.Set(context.Background(), entryKey("http://example.com/foo"), []byte(e), time.Minute))
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, get("/foo", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "hello", rw.Body.String())
+	assert.Equal(t, "traefik; fwd=stale; fwd-status=304", rw.Header().Get(statusHeader))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&notModified))
+}
+
+func TestCache_largeResponse(t *testing.T) {
+	body := strings.Repeat("a", 100)
+
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte(body[:50]))
+		_, _ = rw.Write([]byte(body[50:]))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{MaxEntrySize: 60}, "foo")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, get("/foo", nil))
+
+		assert.Equal(t, body, rw.Body.String())
+		assert.Equal(t, "traefik; fwd=uri-miss", rw.Header().Get(statusHeader))
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func get(path string, header http.Header) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	return req
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// heuristicStatuses are the status codes whose responses can be stored without explicit expiration time (RFC 9111, section 4.2.2).
+var heuristicStatuses = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusPermanentRedirect:    true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// explicitStatuses are the other status codes whose responses can be stored, with an explicit expiration time.
+var explicitStatuses = map[int]bool{
+	http.StatusFound:             true,
+	http.StatusTemporaryRedirect: true,
+}
+
+// cacheControl holds the directives of Cache-Control headers, keyed by lowercase name.
+type cacheControl map[string]string
+
+func parseCacheControl(values []string) cacheControl {
+	cc := make(cacheControl)
+	for _, value := range values {
+		for _, directive := range strings.Split(value, ",") {
+			directive = strings.TrimSpace(directive)
+			if directive == "" {
+				continue
+			}
+
+			name, arg := directive, ""
+			if i := strings.Index(directive, "="); i >= 0 {
+				name, arg = directive[:i], strings.Trim(strings.TrimSpace(directive[i+1:]), `"`)
+			}
+
+			cc[strings.ToLower(strings.TrimSpace(name))] = arg
+		}
+	}
+
+	return cc
+}
+
+func (cc cacheControl) has(name string) bool {
+	_, ok := cc[name]
+	return ok
+}
+
+// duration returns the delta-seconds argument of the directive.
+func (cc cacheControl) duration(name string) (time.Duration, bool) {
+	arg, ok := cc[name]
+	if !ok {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || seconds < 0 {
+		// An invalid delta-seconds is treated as stale (RFC 9111, section 1.2.2).
+		return 0, true
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+// lifetime returns the freshness lifetime of a response, or zero when it cannot be stored by a shared cache.
+// ttl, when set, overrides the explicit expiration time of the response,
+// and defaultTTL is given to the responses without explicit expiration time.
+func lifetime(req *http.Request, code int, header http.Header, ttl, defaultTTL time.Duration) time.Duration {
+	if !heuristicStatuses[code] && !explicitStatuses[code] {
+		return 0
+	}
+
+	cc := parseCacheControl(header.Values("Cache-Control"))
+	if cc.has("no-store") || cc.has("private") || cc.has("no-cache") {
+		return 0
+	}
+
+	// The responses to authenticated requests are only stored when the service explicitly allows it (RFC 9111, section 3.5).
+	if req.Header.Get("Authorization") != "" && !cc.has("public") && !cc.has("s-maxage") && !cc.has("must-revalidate") {
+		return 0
+	}
+
+	// The cookies are meant for a single client.
+	if header.Get("Set-Cookie") != "" {
+		return 0
+	}
+
+	for _, vary := range header.Values("Vary") {
+		if strings.Contains(vary, "*") {
+			return 0
+		}
+	}
+
+	explicit, ok := explicitLifetime(cc, header)
+
+	switch {
+	case ok && explicit <= 0:
+		return 0
+	case ttl > 0:
+		return ttl
+	case ok:
+		return explicit
+	case heuristicStatuses[code]:
+		return defaultTTL
+	default:
+		return 0
+	}
+}
+
+// explicitLifetime returns the freshness lifetime given by the s-maxage, max-age, or Expires headers of the response.
+func explicitLifetime(cc cacheControl, header http.Header) (time.Duration, bool) {
+	if d, ok := cc.duration("s-maxage"); ok {
+		return d, true
+	}
+
+	if d, ok := cc.duration("max-age"); ok {
+		return d, true
+	}
+
+	expires := header.Get("Expires")
+	if expires == "" {
+		return 0, false
+	}
+
+	expiresAt, err := http.ParseTime(expires)
+	if err != nil {
+		// An invalid date, e.g. "0", represents a time in the past.
+		return 0, true
+	}
+
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		date = time.Now()
+	}
+
+	return expiresAt.Sub(date), true
+}
+
+// initialAge returns the age of a response when received, given by its Age header.
+func initialAge(header http.Header) time.Duration {
+	seconds, err := strconv.ParseInt(header.Get("Age"), 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
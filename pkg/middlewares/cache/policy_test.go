@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifetime(t *testing.T) {
+	now := time.Now().UTC()
+
+	testCases := []struct {
+		desc       string
+		reqHeader  http.Header
+		code       int
+		header     http.Header
+		ttl        time.Duration
+		defaultTTL time.Duration
+		expected   time.Duration
+	}{
+		{
+			desc:     "max-age",
+			code:     http.StatusOK,
+			header:   http.Header{"Cache-Control": {"public, max-age=60"}},
+			expected: time.Minute,
+		},
+		{
+			desc:     "s-maxage over max-age",
+			code:     http.StatusOK,
+			header:   http.Header{"Cache-Control": {"max-age=60, s-maxage=120"}},
+			expected: 2 * time.Minute,
+		},
+		{
+			desc: "expires",
+			code: http.StatusOK,
+			header: http.Header{
+				"Date":    {now.Format(http.TimeFormat)},
+				"Expires": {now.Add(time.Hour).Format(http.TimeFormat)},
+			},
+			expected: time.Hour,
+		},
+		{
+			desc:   "invalid expires",
+			code:   http.StatusOK,
+			header: http.Header{"Expires": {"0"}},
+		},
+		{
+			desc:       "no explicit expiration time",
+			code:       http.StatusOK,
+			header:     http.Header{},
+			defaultTTL: time.Minute,
+			expected:   time.Minute,
+		},
+		{
+			desc:       "no explicit expiration time on a non heuristically cacheable status",
+			code:       http.StatusFound,
+			header:     http.Header{},
+			defaultTTL: time.Minute,
+		},
+		{
+			desc:     "explicit expiration time on a non heuristically cacheable status",
+			code:     http.StatusFound,
+			header:   http.Header{"Cache-Control": {"max-age=60"}},
+			expected: time.Minute,
+		},
+		{
+			desc:   "non cacheable status",
+			code:   http.StatusInternalServerError,
+			header: http.Header{"Cache-Control": {"max-age=60"}},
+		},
+		{
+			desc:     "TTL override",
+			code:     http.StatusOK,
+			header:   http.Header{"Cache-Control": {"max-age=60"}},
+			ttl:      time.Hour,
+			expected: time.Hour,
+		},
+		{
+			desc:   "TTL override of a zero max-age",
+			code:   http.StatusOK,
+			header: http.Header{"Cache-Control": {"max-age=0"}},
+			ttl:    time.Hour,
+		},
+		{
+			desc:   "no-store",
+			code:   http.StatusOK,
+			header: http.Header{"Cache-Control": {"no-store, max-age=60"}},
+			ttl:    time.Hour,
+		},
+		{
+			desc:   "private",
+			code:   http.StatusOK,
+			header: http.Header{"Cache-Control": {"private, max-age=60"}},
+		},
+		{
+			desc:   "no-cache",
+			code:   http.StatusOK,
+			header: http.Header{"Cache-Control": {`no-cache="Set-Cookie", max-age=60`}},
+		},
+		{
+			desc:      "authorization",
+			reqHeader: http.Header{"Authorization": {"Basic Zm9vOmJhcg=="}},
+			code:      http.StatusOK,
+			header:    http.Header{"Cache-Control": {"max-age=60"}},
+		},
+		{
+			desc:      "authorization with public",
+			reqHeader: http.Header{"Authorization": {"Basic Zm9vOmJhcg=="}},
+			code:      http.StatusOK,
+			header:    http.Header{"Cache-Control": {"public, max-age=60"}},
+			expected:  time.Minute,
+		},
+		{
+			desc: "set-cookie",
+			code: http.StatusOK,
+			header: http.Header{
+				"Cache-Control": {"max-age=60"},
+				"Set-Cookie":    {"foo=bar"},
+			},
+		},
+		{
+			desc: "vary all",
+			code: http.StatusOK,
+			header: http.Header{
+				"Cache-Control": {"max-age=60"},
+				"Vary":          {"*"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			for name, values := range test.reqHeader {
+				req.Header[name] = values
+			}
+
+			assert.Equal(t, test.expected, lifetime(req, test.code, test.header, test.ttl, test.defaultTTL))
+		})
+	}
+}
+
+func TestParseCacheControl(t *testing.T) {
+	cc := parseCacheControl([]string{`Max-Age=60, no-cache="Set-Cookie"`, "public,,  s-maxage = 10", "max-stale=foo"})
+
+	assert.Equal(t, cacheControl{
+		"max-age":   "60",
+		"no-cache":  "Set-Cookie",
+		"public":    "",
+		"s-maxage":  "10",
+		"max-stale": "foo",
+	}, cc)
+
+	d, ok := cc.duration("s-maxage")
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, d)
+
+	d, ok = cc.duration("max-stale")
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	_, ok = cc.duration("min-fresh")
+	assert.False(t, ok)
+}
@@ -0,0 +1,212 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	redisTimeout  = time.Second
+	redisMaxIdle  = 8
+	redisKeysBase = "traefik:cache:"
+)
+
+// redisStore stores the responses in Redis, speaking the RESP protocol.
+// Only the few commands needed by the cache are implemented.
+type redisStore struct {
+	address  string
+	password string
+	db       int
+	// prefix isolates the keys of a cache from the ones of the other caches sharing the same Redis.
+	prefix string
+
+	idle chan *redisConn
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newRedisStore(address, password string, db int, name string) *redisStore {
+	return &redisStore{
+		address:  address,
+		password: password,
+		db:       db,
+		prefix:   redisKeysBase + name + ":",
+		idle:     make(chan *redisConn, redisMaxIdle),
+	}
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := s.do(ctx, "GET", s.prefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if reply == nil {
+		return nil, false, nil
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected reply %v to GET", reply)
+	}
+
+	return value, true, nil
+}
+
+func (s *redisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ms := ttl.Milliseconds()
+	if ms <= 0 {
+		return nil
+	}
+
+	_, err := s.do(ctx, "SET", s.prefix+key, string(value), "PX", strconv.FormatInt(ms, 10))
+	return err
+}
+
+func (s *redisStore) Delete(ctx context.Context, key string) error {
+	_, err := s.do(ctx, "DEL", s.prefix+key)
+	return err
+}
+
+// do sends the command on an idle connection, or a new one, and returns the reply.
+func (s *redisStore) do(ctx context.Context, args ...string) (interface{}, error) {
+	var rc *redisConn
+	select {
+	case rc = <-s.idle:
+	default:
+		var err error
+		rc, err = s.dial(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := rc.exchange(ctx, args...)
+
+	// Unlike the network errors, an error reply leaves the connection usable.
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		_ = rc.conn.Close()
+		return nil, fmt.Errorf("redis %s: %w", s.address, err)
+	}
+
+	select {
+	case s.idle <- rc:
+	default:
+		_ = rc.conn.Close()
+	}
+
+	return reply, err
+}
+
+func (s *redisStore) dial(ctx context.Context) (*redisConn, error) {
+	dialer := net.Dialer{Timeout: redisTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return nil, fmt.Errorf("redis %s: %w", s.address, err)
+	}
+
+	rc := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+
+	if s.password != "" {
+		if _, err = rc.exchange(ctx, "AUTH", s.password); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis %s: authentication failed: %w", s.address, err)
+		}
+	}
+
+	if s.db != 0 {
+		if _, err = rc.exchange(ctx, "SELECT", strconv.Itoa(s.db)); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis %s: selecting the database failed: %w", s.address, err)
+		}
+	}
+
+	return rc, nil
+}
+
+// redisError is an error reply of Redis.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+func (rc *redisConn) exchange(ctx context.Context, args ...string) (interface{}, error) {
+	deadline := time.Now().Add(redisTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err := rc.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	cmd := make([]byte, 0, 64)
+	cmd = append(cmd, '*')
+	cmd = strconv.AppendInt(cmd, int64(len(args)), 10)
+	cmd = append(cmd, '\r', '\n')
+	for _, arg := range args {
+		cmd = append(cmd, '$')
+		cmd = strconv.AppendInt(cmd, int64(len(arg)), 10)
+		cmd = append(cmd, '\r', '\n')
+		cmd = append(cmd, arg...)
+		cmd = append(cmd, '\r', '\n')
+	}
+
+	if _, err := rc.conn.Write(cmd); err != nil {
+		return nil, err
+	}
+
+	return rc.readReply()
+}
+
+// readReply reads a simple string, error, integer or bulk string reply.
+// A nil bulk string is returned as nil.
+func (rc *redisConn) readReply() (interface{}, error) {
+	line, err := rc.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	line = line[:len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("malformed reply %q", line)
+		}
+
+		if size < 0 {
+			return nil, nil
+		}
+
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(rc.reader, buf); err != nil {
+			return nil, err
+		}
+
+		return buf[:size], nil
+	default:
+		return nil, fmt.Errorf("unsupported reply %q", line)
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// store holds the serialized responses of a cache.
+type store interface {
+	// Get returns the value of the key, and false when the key is missing or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value of the key, for the given duration.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+type memoryItem struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// memoryStore is an in-memory store bounded in size, evicting the least recently used items.
+type memoryStore struct {
+	maxSize int64
+
+	mu    sync.Mutex
+	size  int64
+	items map[string]*list.Element
+	lru   *list.List
+}
+
+func newMemoryStore(maxSize int64) *memoryStore {
+	return &memoryStore{
+		maxSize: maxSize,
+		items:   make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elt, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	item := elt.Value.(*memoryItem)
+	if !time.Now().Before(item.expiresAt) {
+		s.remove(elt)
+		return nil, false, nil
+	}
+
+	s.lru.MoveToFront(elt)
+
+	return item.value, true, nil
+}
+
+func (s *memoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elt, ok := s.items[key]; ok {
+		s.remove(elt)
+	}
+
+	size := itemSize(key, value)
+	if size > s.maxSize {
+		return nil
+	}
+
+	s.items[key] = s.lru.PushFront(&memoryItem{key: key, value: value, expiresAt: time.Now().Add(ttl)})
+	s.size += size
+
+	for s.size > s.maxSize {
+		s.remove(s.lru.Back())
+	}
+
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elt, ok := s.items[key]; ok {
+		s.remove(elt)
+	}
+
+	return nil
+}
+
+// remove removes the item of the element, s.mu being held.
+func (s *memoryStore) remove(elt *list.Element) {
+	item := s.lru.Remove(elt).(*memoryItem)
+	delete(s.items, item.key)
+	s.size -= itemSize(item.key, item.value)
+}
+
+func itemSize(key string, value []byte) int64 {
+	return int64(len(key) + len(value))
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	s := newMemoryStore(10)
+
+	require.NoError(t, s.Set(ctx, "a", []byte("foo"), time.Minute))
+	require.NoError(t, s.Set(ctx, "b", []byte("bar"), time.Minute))
+
+	value, ok, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("foo"), value)
+
+	// "b" is the least recently used item, and is evicted.
+	require.NoError(t, s.Set(ctx, "c", []byte("baz"), time.Minute))
+
+	_, ok, err = s.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = s.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Too large items are not stored.
+	require.NoError(t, s.Set(ctx, "d", []byte("0123456789"), time.Minute))
+
+	_, ok, err = s.Get(ctx, "d")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, s.Delete(ctx, "a"))
+
+	_, ok, err = s.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.Equal(t, int64(4), s.size)
+}
+
+func TestMemoryStore_expiration(t *testing.T) {
+	ctx := context.Background()
+
+	s := newMemoryStore(100)
+
+	require.NoError(t, s.Set(ctx, "a", []byte("foo"), -time.Second))
+
+	_, ok, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.Equal(t, int64(0), s.size)
+}
@@ -2,53 +2,201 @@ package circuitbreaker
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
 	"github.com/containous/traefik/v2/pkg/tracing"
 	"github.com/opentracing/opentracing-go/ext"
-	"github.com/vulcand/oxy/cbreaker"
 )
 
 const (
 	typeName = "CircuitBreaker"
 )
 
-type circuitBreaker struct {
-	circuitBreaker *cbreaker.CircuitBreaker
-	name           string
+const (
+	defaultCheckPeriod      = 100 * time.Millisecond
+	defaultFallbackDuration = 10 * time.Second
+	defaultProbeRequests    = 1
+)
+
+// Listener is used to inform about the state transitions of a circuit breaker.
+type Listener interface {
+	// StateChanged will be called when the circuit breaker enters a state,
+	// with the URL of the server for a per-server circuit breaker, and an empty one otherwise.
+	StateChanged(server, state string)
+}
+
+// State is the state of the circuit breaker of a service, or of one of its servers.
+type State struct {
+	Middleware string    `json:"middleware"`
+	Service    string    `json:"service"`
+	Server     string    `json:"server,omitempty"`
+	State      string    `json:"state"`
+	Since      time.Time `json:"since"`
 }
 
-// New creates a new circuit breaker middleware.
-func New(ctx context.Context, next http.Handler, confCircuitBreaker dynamic.CircuitBreaker, name string) (http.Handler, error) {
-	expression := confCircuitBreaker.Expression
+// Breaker holds the states of a circuit breaker,
+// shared by the routers using the circuit breaker middleware in front of the same service.
+type Breaker struct {
+	name        string
+	serviceName string
+	expression  string
+	perServer   bool
+	settings    *settings
+	listener    Listener
 
-	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
-	logger.Debug("Creating middleware")
-	logger.Debug("Setting up with expression: %s", expression)
+	// service is the state of the service, when the circuit breaker is not per server.
+	service *stateMachine
+
+	mu      sync.Mutex
+	servers map[string]*stateMachine
+}
+
+// NewBreaker creates the states of the circuit breaker middleware with the given name, in front of the given service.
+// The listener, if not nil, is informed about the state transitions.
+func NewBreaker(config dynamic.CircuitBreaker, name, serviceName string, listener Listener) (*Breaker, error) {
+	cond, err := parseExpression(config.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", config.Expression, err)
+	}
+
+	if config.CheckPeriod < 0 || config.FallbackDuration < 0 || config.ProbeRequests < 0 {
+		return nil, fmt.Errorf("incorrect settings (check period: %s, fallback duration: %s, probe requests: %d)", config.CheckPeriod, config.FallbackDuration, config.ProbeRequests)
+	}
+
+	s := &settings{
+		condition:        cond,
+		checkPeriod:      defaultCheckPeriod,
+		fallbackDuration: defaultFallbackDuration,
+		probeRequests:    defaultProbeRequests,
+	}
+	if config.CheckPeriod > 0 {
+		s.checkPeriod = time.Duration(config.CheckPeriod)
+	}
+	if config.FallbackDuration > 0 {
+		s.fallbackDuration = time.Duration(config.FallbackDuration)
+	}
+	if config.ProbeRequests > 0 {
+		s.probeRequests = config.ProbeRequests
+	}
+
+	b := &Breaker{
+		name:        name,
+		serviceName: serviceName,
+		expression:  config.Expression,
+		perServer:   config.PerServer,
+		settings:    s,
+		listener:    listener,
+		servers:     make(map[string]*stateMachine),
+	}
+
+	if !b.perServer {
+		b.service, err = newStateMachine(s, b.onChange(""))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// States returns the states of the service or of its servers, sorted by server.
+// The servers which did not receive any request yet are not reported.
+func (b *Breaker) States() []State {
+	if !b.perServer {
+		state, since := b.service.current()
+		return []State{{Middleware: b.name, Service: b.serviceName, State: state, Since: since}}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make([]State, 0, len(b.servers))
+	for server, machine := range b.servers {
+		state, since := machine.current()
+		states = append(states, State{Middleware: b.name, Service: b.serviceName, Server: server, State: state, Since: since})
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Server < states[j].Server
+	})
+
+	return states
+}
+
+// server returns the state of a server of the service, created on the first request to the server.
+// No state is returned for a server which is not one of the current servers of the service,
+// and the states of the servers no longer part of the service are dropped when a state is created.
+func (b *Breaker) server(server string, servers func() []*url.URL) (*stateMachine, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if machine, ok := b.servers[server]; ok {
+		return machine, nil
+	}
 
-	oxyCircuitBreaker, err := cbreaker.New(next, expression, createCircuitBreakerOptions(expression))
+	current := make(map[string]struct{})
+	for _, u := range servers() {
+		current[u.String()] = struct{}{}
+	}
+
+	if _, ok := current[server]; !ok {
+		return nil, nil
+	}
+
+	for name := range b.servers {
+		if _, ok := current[name]; !ok {
+			delete(b.servers, name)
+		}
+	}
+
+	machine, err := newStateMachine(b.settings, b.onChange(server))
 	if err != nil {
 		return nil, err
 	}
-	return &circuitBreaker{
-		circuitBreaker: oxyCircuitBreaker,
-		name:           name,
-	}, nil
+	b.servers[server] = machine
+
+	return machine, nil
 }
 
-// NewCircuitBreakerOptions returns a new CircuitBreakerOption.
-func createCircuitBreakerOptions(expression string) cbreaker.CircuitBreakerOption {
-	return cbreaker.Fallback(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		tracing.SetErrorWithEvent(req, "blocked by circuit-breaker (%q)", expression)
-		rw.WriteHeader(http.StatusServiceUnavailable)
+func (b *Breaker) onChange(server string) func(state string) {
+	return func(state string) {
+		logger := log.WithoutContext().WithField(log.MiddlewareName, b.name).WithField(log.ServiceName, b.serviceName)
+		if server != "" {
+			logger = logger.WithField(log.ServerName, server)
+		}
+		logger.Debugf("Circuit breaker entering the %s state", state)
 
-		if _, err := rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable))); err != nil {
-			log.FromContext(req.Context()).Error(err)
+		if b.listener != nil {
+			b.listener.StateChanged(server, state)
 		}
-	}))
+	}
+}
+
+type circuitBreaker struct {
+	next    http.Handler
+	breaker *Breaker
+	name    string
+}
+
+// New creates a new circuit breaker middleware, with the given states.
+func New(ctx context.Context, next http.Handler, breaker *Breaker, name string) (http.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+	logger.Debugf("Setting up with expression: %s", breaker.expression)
+
+	return &circuitBreaker{
+		next:    next,
+		breaker: breaker,
+		name:    name,
+	}, nil
 }
 
 func (c *circuitBreaker) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -56,5 +204,54 @@ func (c *circuitBreaker) GetTracingInformation() (string, ext.SpanKindEnum) {
 }
 
 func (c *circuitBreaker) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	c.circuitBreaker.ServeHTTP(rw, req)
+	if c.breaker.perServer {
+		// The circuit breakers of the servers are applied once the load-balancer of the service selected a server.
+		c.next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), breakerKey{}, c.breaker)))
+		return
+	}
+
+	if !serve(c.breaker.service, rw, req, c.next) {
+		c.breaker.fallback(rw, req)
+	}
+}
+
+// fallback rejects a request, as the circuit breaker is open.
+func (b *Breaker) fallback(rw http.ResponseWriter, req *http.Request) {
+	tracing.SetErrorWithEvent(req, "blocked by circuit-breaker (%q)", b.expression)
+	rw.WriteHeader(http.StatusServiceUnavailable)
+
+	if _, err := rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable))); err != nil {
+		log.FromContext(req.Context()).Error(err)
+	}
+}
+
+// serve forwards the request if the state allows it, and records its response.
+// It returns false if the request was not forwarded.
+func serve(machine *stateMachine, rw http.ResponseWriter, req *http.Request, next http.Handler) bool {
+	allowed, probe := machine.allow()
+	if !allowed {
+		return false
+	}
+
+	recorder := &statusRecorder{ResponseWriter: rw}
+	start := time.Now()
+
+	completed := false
+	defer func() {
+		code := recorder.status
+		if code == 0 {
+			// No status code was written: the handler either returned, which means a 200, or panicked.
+			code = http.StatusOK
+			if !completed {
+				code = http.StatusInternalServerError
+			}
+		}
+
+		machine.record(probe, code, time.Since(start))
+	}()
+
+	next.ServeHTTP(recorder, req)
+	completed = true
+
+	return true
 }
@@ -0,0 +1,336 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/memmetrics"
+)
+
+type collectingListener struct {
+	transitions []string
+}
+
+func (l *collectingListener) StateChanged(server, state string) {
+	if server != "" {
+		state = server + " " + state
+	}
+	l.transitions = append(l.transitions, state)
+}
+
+// expire makes the fallback duration of a tripped state elapse.
+func expire(m *stateMachine) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.since = m.since.Add(-m.settings.fallbackDuration)
+}
+
+func TestNewBreaker(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.CircuitBreaker
+		expectedError bool
+	}{
+		{
+			desc:   "latency expression",
+			config: dynamic.CircuitBreaker{Expression: "LatencyAtQuantileMS(50.0) > 100"},
+		},
+		{
+			desc:   "combined expression",
+			config: dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.5 || ResponseCodeRatio(500, 600, 0, 600) >= 0.25 && LatencyAtQuantileMS(99.0) != 0"},
+		},
+		{
+			desc:          "unknown function",
+			config:        dynamic.CircuitBreaker{Expression: "ErrorRatio() > 0.5"},
+			expectedError: true,
+		},
+		{
+			desc:          "int compared to a float",
+			config:        dynamic.CircuitBreaker{Expression: "LatencyAtQuantileMS(50.0) > 0.5"},
+			expectedError: true,
+		},
+		{
+			desc:          "not a condition",
+			config:        dynamic.CircuitBreaker{Expression: "NetworkErrorRatio()"},
+			expectedError: true,
+		},
+		{
+			desc:          "negative fallback duration",
+			config:        dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.5", FallbackDuration: types.Duration(-time.Second)},
+			expectedError: true,
+		},
+		{
+			desc:          "negative probe requests",
+			config:        dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.5", ProbeRequests: -1},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewBreaker(test.config, "cb", "foo", nil)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	config := dynamic.CircuitBreaker{
+		Expression:    "ResponseCodeRatio(500, 600, 0, 600) > 0.5",
+		CheckPeriod:   types.Duration(time.Nanosecond),
+		ProbeRequests: 2,
+	}
+
+	listener := &collectingListener{}
+	breaker, err := NewBreaker(config, "cb", "foo", listener)
+	require.NoError(t, err)
+
+	status := http.StatusInternalServerError
+	var calls int
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.WriteHeader(status)
+	})
+
+	handler, err := New(context.Background(), next, breaker, "cb")
+	require.NoError(t, err)
+
+	serve := func() int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return recorder.Code
+	}
+
+	// The failed request trips the circuit breaker, and the next one is rejected.
+	assert.Equal(t, http.StatusInternalServerError, serve())
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+	assert.Equal(t, 1, calls)
+
+	// A failed probe request trips the circuit breaker again.
+	expire(breaker.service)
+	assert.Equal(t, http.StatusInternalServerError, serve())
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+	assert.Equal(t, 2, calls)
+
+	// The circuit breaker closes once all the probe requests succeeded.
+	status = http.StatusOK
+	expire(breaker.service)
+	assert.Equal(t, http.StatusOK, serve())
+
+	state, _ := breaker.service.current()
+	assert.Equal(t, StateRecovering, state)
+
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, 5, calls)
+
+	assert.Equal(t, []string{StateTripped, StateRecovering, StateTripped, StateRecovering, StateStandby}, listener.transitions)
+
+	states := breaker.States()
+	require.Len(t, states, 1)
+	assert.Equal(t, "cb", states[0].Middleware)
+	assert.Equal(t, "foo", states[0].Service)
+	assert.Empty(t, states[0].Server)
+	assert.Equal(t, StateStandby, states[0].State)
+}
+
+func TestCircuitBreaker_recoveringProbes(t *testing.T) {
+	config := dynamic.CircuitBreaker{
+		Expression:  "ResponseCodeRatio(500, 600, 0, 600) > 0.5",
+		CheckPeriod: types.Duration(time.Nanosecond),
+	}
+
+	breaker, err := NewBreaker(config, "cb", "foo", nil)
+	require.NoError(t, err)
+
+	breaker.service.record(false, http.StatusInternalServerError, time.Millisecond)
+	expire(breaker.service)
+
+	// Only one probe request is forwarded until its response is known.
+	allowed, probe := breaker.service.allow()
+	assert.True(t, allowed)
+	assert.True(t, probe)
+
+	allowed, _ = breaker.service.allow()
+	assert.False(t, allowed)
+	assert.False(t, breaker.service.available())
+
+	breaker.service.record(true, http.StatusOK, time.Millisecond)
+
+	state, _ := breaker.service.current()
+	assert.Equal(t, StateStandby, state)
+}
+
+func TestCircuitBreaker_perServer(t *testing.T) {
+	config := dynamic.CircuitBreaker{
+		Expression:  "ResponseCodeRatio(500, 600, 0, 600) > 0.5",
+		CheckPeriod: types.Duration(time.Nanosecond),
+		PerServer:   true,
+	}
+
+	listener := &collectingListener{}
+	breaker, err := NewBreaker(config, "cb", "foo", listener)
+	require.NoError(t, err)
+
+	servers := []*url.URL{testhelpers.MustParseURL("http://10.0.0.1:80"), testhelpers.MustParseURL("http://10.0.0.2:80")}
+
+	var forwarded []string
+	backend := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded = append(forwarded, req.URL.Host)
+		if req.URL.Host == "10.0.0.1:80" {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	wrapped := WrapServers(backend, func() []*url.URL { return servers })
+
+	// The load-balancer always selects the first server.
+	lb := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		outReq := req.WithContext(req.Context())
+		outReq.URL = copyURL(servers[0])
+		wrapped.ServeHTTP(rw, outReq)
+	})
+
+	handler, err := New(context.Background(), lb, breaker, "cb")
+	require.NoError(t, err)
+
+	serve := func() int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return recorder.Code
+	}
+
+	// The failed request trips the circuit breaker of the first server only,
+	// and the next requests are sent to the second one.
+	assert.Equal(t, http.StatusBadGateway, serve())
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.0.2:80"}, forwarded)
+
+	states := breaker.States()
+	require.Len(t, states, 2)
+	assert.Equal(t, "http://10.0.0.1:80", states[0].Server)
+	assert.Equal(t, StateTripped, states[0].State)
+	assert.Equal(t, "http://10.0.0.2:80", states[1].Server)
+	assert.Equal(t, StateStandby, states[1].State)
+
+	// Once the second server is isolated too, the requests are rejected.
+	machine, err := breaker.server("http://10.0.0.2:80", func() []*url.URL { return servers })
+	require.NoError(t, err)
+	machine.record(false, http.StatusInternalServerError, time.Millisecond)
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+	assert.Len(t, forwarded, 3)
+
+	assert.Equal(t, []string{"http://10.0.0.1:80 tripped", "http://10.0.0.2:80 tripped"}, listener.transitions)
+}
+
+func TestCircuitBreaker_perServerCurrentServers(t *testing.T) {
+	config := dynamic.CircuitBreaker{
+		Expression: "ResponseCodeRatio(500, 600, 0, 600) > 0.5",
+		PerServer:  true,
+	}
+
+	breaker, err := NewBreaker(config, "cb", "foo", nil)
+	require.NoError(t, err)
+
+	servers := []*url.URL{testhelpers.MustParseURL("http://10.0.0.1:80"), testhelpers.MustParseURL("http://10.0.0.2:80")}
+	current := func() []*url.URL { return servers }
+
+	for _, u := range servers {
+		machine, err := breaker.server(u.String(), current)
+		require.NoError(t, err)
+		require.NotNil(t, machine)
+	}
+
+	// No state is created for a server which is not part of the service.
+	machine, err := breaker.server("http://10.0.0.3:80", current)
+	require.NoError(t, err)
+	assert.Nil(t, machine)
+	assert.Len(t, breaker.States(), 2)
+
+	// The state of a server removed from the service is dropped when a new server receives a request.
+	servers = []*url.URL{servers[1], testhelpers.MustParseURL("http://10.0.0.3:80")}
+
+	machine, err = breaker.server("http://10.0.0.3:80", current)
+	require.NoError(t, err)
+	require.NotNil(t, machine)
+
+	states := breaker.States()
+	require.Len(t, states, 2)
+	assert.Equal(t, "http://10.0.0.2:80", states[0].Server)
+	assert.Equal(t, "http://10.0.0.3:80", states[1].Server)
+}
+
+func TestCircuitBreaker_perServerStateError(t *testing.T) {
+	defer func(fn func() (*memmetrics.RTMetrics, error)) { newRTMetrics = fn }(newRTMetrics)
+	newRTMetrics = func() (*memmetrics.RTMetrics, error) { return nil, errors.New("boom") }
+
+	config := dynamic.CircuitBreaker{
+		Expression: "ResponseCodeRatio(500, 600, 0, 600) > 0.5",
+		PerServer:  true,
+	}
+
+	breaker, err := NewBreaker(config, "cb", "foo", nil)
+	require.NoError(t, err)
+
+	servers := []*url.URL{testhelpers.MustParseURL("http://10.0.0.1:80")}
+	current := func() []*url.URL { return servers }
+
+	machine, err := breaker.server("http://10.0.0.1:80", current)
+	require.Error(t, err)
+	assert.Nil(t, machine)
+	assert.Empty(t, breaker.States())
+
+	// The request is still forwarded, without being broken.
+	var forwarded int
+	backend := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded++
+		rw.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), breakerKey{}, breaker))
+	req.URL = copyURL(servers[0])
+
+	recorder := httptest.NewRecorder()
+	WrapServers(backend, current).ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, 1, forwarded)
+	assert.Empty(t, breaker.States())
+}
+
+func TestWrapServers_withoutCircuitBreaker(t *testing.T) {
+	var called bool
+	handler := WrapServers(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		called = true
+		rw.WriteHeader(http.StatusBadGateway)
+	}), func() []*url.URL { return nil })
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://10.0.0.1:80", nil))
+
+	assert.True(t, called)
+	assert.Equal(t, http.StatusBadGateway, recorder.Code)
+}
@@ -0,0 +1,158 @@
+package circuitbreaker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vulcand/oxy/memmetrics"
+	"github.com/vulcand/predicate"
+)
+
+// condition tells whether the circuit breaker has to open, according to the metrics of the forwarded requests.
+type condition func(*memmetrics.RTMetrics) bool
+
+type toInt func(*memmetrics.RTMetrics) int
+
+type toFloat64 func(*memmetrics.RTMetrics) float64
+
+// parseExpression parses the expression of a circuit breaker, which has the same syntax as the one of the oxy circuit breaker,
+// e.g. "NetworkErrorRatio() > 0.5 || LatencyAtQuantileMS(50.0) > 100".
+func parseExpression(expression string) (condition, error) {
+	parser, err := predicate.NewParser(predicate.Def{
+		Operators: predicate.Operators{
+			AND: and,
+			OR:  or,
+			EQ:  eq,
+			NEQ: neq,
+			LT:  lt,
+			LE:  le,
+			GT:  gt,
+			GE:  ge,
+		},
+		Functions: map[string]interface{}{
+			"LatencyAtQuantileMS": latencyAtQuantile,
+			"NetworkErrorRatio":   networkErrorRatio,
+			"ResponseCodeRatio":   responseCodeRatio,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := parser.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, ok := out.(condition)
+	if !ok {
+		return nil, fmt.Errorf("expected a condition, got %T", out)
+	}
+
+	return cond, nil
+}
+
+func latencyAtQuantile(quantile float64) toInt {
+	return func(m *memmetrics.RTMetrics) int {
+		h, err := m.LatencyHistogram()
+		if err != nil {
+			return 0
+		}
+		return int(h.LatencyAtQuantile(quantile) / time.Millisecond)
+	}
+}
+
+func networkErrorRatio() toFloat64 {
+	return func(m *memmetrics.RTMetrics) float64 {
+		return m.NetworkErrorRatio()
+	}
+}
+
+func responseCodeRatio(startA, endA, startB, endB int) toFloat64 {
+	return func(m *memmetrics.RTMetrics) float64 {
+		return m.ResponseCodeRatio(startA, endA, startB, endB)
+	}
+}
+
+func or(conditions ...condition) condition {
+	return func(m *memmetrics.RTMetrics) bool {
+		for _, cond := range conditions {
+			if cond(m) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func and(conditions ...condition) condition {
+	return func(m *memmetrics.RTMetrics) bool {
+		for _, cond := range conditions {
+			if !cond(m) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func not(cond condition) condition {
+	return func(m *memmetrics.RTMetrics) bool {
+		return !cond(m)
+	}
+}
+
+func eq(mapper, value interface{}) (condition, error) {
+	return compare("eq", mapper, value, func(a, b float64) bool { return a == b })
+}
+
+func neq(mapper, value interface{}) (condition, error) {
+	cond, err := eq(mapper, value)
+	if err != nil {
+		return nil, err
+	}
+	return not(cond), nil
+}
+
+func lt(mapper, value interface{}) (condition, error) {
+	return compare("lt", mapper, value, func(a, b float64) bool { return a < b })
+}
+
+func le(mapper, value interface{}) (condition, error) {
+	return compare("le", mapper, value, func(a, b float64) bool { return a <= b })
+}
+
+func gt(mapper, value interface{}) (condition, error) {
+	return compare("gt", mapper, value, func(a, b float64) bool { return a > b })
+}
+
+func ge(mapper, value interface{}) (condition, error) {
+	return compare("ge", mapper, value, func(a, b float64) bool { return a >= b })
+}
+
+// compare returns the condition comparing the value of the mapper to a constant,
+// which must be an int for the int mappers, and a float64 for the float64 ones.
+func compare(operator string, mapper, value interface{}, cmp func(a, b float64) bool) (condition, error) {
+	switch m := mapper.(type) {
+	case toInt:
+		v, ok := value.(int)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected int, got %T", operator, value)
+		}
+		return func(metrics *memmetrics.RTMetrics) bool {
+			return cmp(float64(m(metrics)), float64(v))
+		}, nil
+
+	case toFloat64:
+		v, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected float64, got %T", operator, value)
+		}
+		return func(metrics *memmetrics.RTMetrics) bool {
+			return cmp(m(metrics), v)
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%s: unsupported argument: %T", operator, mapper)
+	}
+}
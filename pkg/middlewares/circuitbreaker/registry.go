@@ -0,0 +1,48 @@
+package circuitbreaker
+
+import (
+	"sort"
+	"sync"
+)
+
+// Registry keeps the circuit breakers of the current configuration, so that their states can be reported.
+type Registry struct {
+	mu       sync.RWMutex
+	breakers []*Breaker
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Set replaces the circuit breakers with the ones of a new configuration.
+func (r *Registry) Set(breakers []*Breaker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.breakers = breakers
+}
+
+// States returns the states of the circuit breakers, sorted by middleware, service and server.
+func (r *Registry) States() []State {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	states := make([]State, 0, len(r.breakers))
+	for _, breaker := range r.breakers {
+		states = append(states, breaker.States()...)
+	}
+
+	sort.SliceStable(states, func(i, j int) bool {
+		if states[i].Middleware != states[j].Middleware {
+			return states[i].Middleware < states[j].Middleware
+		}
+		if states[i].Service != states[j].Service {
+			return states[i].Service < states[j].Service
+		}
+		return states[i].Server < states[j].Server
+	})
+
+	return states
+}
@@ -0,0 +1,134 @@
+package circuitbreaker
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+// breakerKey is the context key of the per-server circuit breaker in front of a service.
+type breakerKey struct{}
+
+type serverBreakers struct {
+	next    http.Handler
+	servers func() []*url.URL
+}
+
+// WrapServers applies the per-server circuit breaker in front of the service, if any, to the server selected by its load-balancer.
+// The URL of the request is the URL of the selected server,
+// and the requests for a server whose circuit breaker is open are sent to another server of the service, if possible.
+// When several per-server circuit breakers are in front of a service, the one closest to the service applies.
+func WrapServers(next http.Handler, servers func() []*url.URL) http.Handler {
+	return &serverBreakers{next: next, servers: servers}
+}
+
+func (s *serverBreakers) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	breaker, ok := req.Context().Value(breakerKey{}).(*Breaker)
+	if !ok {
+		s.next.ServeHTTP(rw, req)
+		return
+	}
+
+	machine, err := breaker.server(req.URL.String(), s.servers)
+	if err != nil {
+		log.FromContext(req.Context()).Errorf("Unable to create the circuit breaker of the server %s, the request is not broken: %v", req.URL, err)
+	}
+	if machine == nil {
+		s.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if serve(machine, rw, req, s.next) {
+		return
+	}
+
+	// The selected server is isolated, the request is sent to one of the available servers, if any.
+	servers := s.servers()
+	current := func() []*url.URL { return servers }
+
+	var available []*url.URL
+	var machines []*stateMachine
+	for _, u := range servers {
+		if u.String() == req.URL.String() {
+			continue
+		}
+
+		m, err := breaker.server(u.String(), current)
+		if err != nil || m == nil || !m.available() {
+			continue
+		}
+
+		available = append(available, u)
+		machines = append(machines, m)
+	}
+
+	for _, i := range rand.Perm(len(available)) {
+		outReq := req.WithContext(req.Context())
+		outReq.URL = copyURL(available[i])
+
+		if serve(machines[i], rw, outReq, s.next) {
+			return
+		}
+	}
+
+	breaker.fallback(rw, req)
+}
+
+func copyURL(u *url.URL) *url.URL {
+	c := *u
+	if u.User != nil {
+		user := *u.User
+		c.User = &user
+	}
+	return &c
+}
+
+// statusRecorder records the status code of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 && code >= http.StatusOK {
+		s.status = code
+	}
+
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+
+	return s.ResponseWriter.Write(b)
+}
+
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", s.ResponseWriter)
+	}
+
+	return hijacker.Hijack()
+}
+
+func (s *statusRecorder) CloseNotify() <-chan bool {
+	if notifier, ok := s.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+
+	return make(chan bool)
+}
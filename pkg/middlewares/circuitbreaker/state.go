@@ -0,0 +1,168 @@
+package circuitbreaker
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vulcand/oxy/memmetrics"
+)
+
+// States of a circuit breaker.
+const (
+	// StateStandby is the closed state, in which the requests are forwarded and the expression is evaluated.
+	StateStandby = "standby"
+	// StateTripped is the open state, in which the requests are rejected.
+	StateTripped = "tripped"
+	// StateRecovering is the half-open state, in which a few probe requests are forwarded to decide whether to close again.
+	StateRecovering = "recovering"
+)
+
+// settings are the settings of the state machines of a circuit breaker.
+type settings struct {
+	condition        condition
+	checkPeriod      time.Duration
+	fallbackDuration time.Duration
+	probeRequests    int
+}
+
+// newRTMetrics creates the metrics of a state machine, replaced in the tests.
+var newRTMetrics = func() (*memmetrics.RTMetrics, error) { return memmetrics.NewRTMetrics() }
+
+// stateMachine is the state of the circuit breaker of a service, or of a server.
+type stateMachine struct {
+	settings *settings
+	// onChange is called, with the lock held, when the state changes.
+	onChange func(state string)
+
+	mu        sync.Mutex
+	state     string
+	since     time.Time
+	metrics   *memmetrics.RTMetrics
+	lastCheck time.Time
+	// probesSent and probesSucceeded count the probe requests of the recovering state.
+	probesSent      int
+	probesSucceeded int
+}
+
+func newStateMachine(s *settings, onChange func(state string)) (*stateMachine, error) {
+	metrics, err := newRTMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	return &stateMachine{
+		settings: s,
+		onChange: onChange,
+		state:    StateStandby,
+		since:    time.Now(),
+		metrics:  metrics,
+	}, nil
+}
+
+// allow tells whether a request can be forwarded, and whether it is a probe request.
+func (m *stateMachine) allow() (allowed, probe bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch m.state {
+	case StateStandby:
+		return true, false
+
+	case StateTripped:
+		if time.Since(m.since) < m.settings.fallbackDuration {
+			return false, false
+		}
+		m.setState(StateRecovering)
+	}
+
+	if m.probesSent >= m.settings.probeRequests {
+		return false, false
+	}
+
+	m.probesSent++
+	return true, true
+}
+
+// available tells whether a request could be forwarded, without counting it as a probe request.
+func (m *stateMachine) available() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch m.state {
+	case StateStandby:
+		return true
+	case StateTripped:
+		return time.Since(m.since) >= m.settings.fallbackDuration
+	default:
+		return m.probesSent < m.settings.probeRequests
+	}
+}
+
+// record records the response of a forwarded request.
+// A failed probe request opens the circuit breaker again, and it closes once all the probe requests succeeded.
+func (m *stateMachine) record(probe bool, code int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if probe {
+		// The probe requests sent before the circuit breaker opened again are ignored.
+		if m.state != StateRecovering {
+			return
+		}
+
+		if code >= http.StatusInternalServerError {
+			m.setState(StateTripped)
+			return
+		}
+
+		m.probesSucceeded++
+		if m.probesSucceeded >= m.settings.probeRequests {
+			m.setState(StateStandby)
+		}
+		return
+	}
+
+	// The requests forwarded before the circuit breaker opened are ignored.
+	if m.state != StateStandby {
+		return
+	}
+
+	m.metrics.Record(code, duration)
+
+	now := time.Now()
+	if now.Sub(m.lastCheck) < m.settings.checkPeriod {
+		return
+	}
+	m.lastCheck = now
+
+	if m.settings.condition(m.metrics) {
+		m.setState(StateTripped)
+	}
+}
+
+// current returns the current state, and since when.
+func (m *stateMachine) current() (string, time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.state, m.since
+}
+
+// setState changes the state. It must be called with the lock held.
+func (m *stateMachine) setState(state string) {
+	m.state = state
+	m.since = time.Now()
+	m.probesSent = 0
+	m.probesSucceeded = 0
+
+	if state == StateStandby {
+		// The expression is evaluated on the requests forwarded since the circuit breaker closed.
+		m.metrics.Reset()
+		m.lastCheck = time.Time{}
+	}
+
+	if m.onChange != nil {
+		m.onChange(state)
+	}
+}
@@ -0,0 +1,18 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// StatusClientClosedRequest non-standard HTTP status code for client disconnection.
+const StatusClientClosedRequest = 499
+
+// StatusClientClosedRequestText non-standard HTTP status for client disconnection.
+const StatusClientClosedRequestText = "Client Closed Request"
+
+// ClientAborted reports whether the client went away before the end of the request processing.
+func ClientAborted(req *http.Request) bool {
+	return errors.Is(req.Context().Err(), context.Canceled)
+}
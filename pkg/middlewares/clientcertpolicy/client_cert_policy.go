@@ -0,0 +1,207 @@
+package clientcertpolicy
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "ClientCertPolicy"
+)
+
+var keyUsages = map[string]x509.KeyUsage{
+	"digitalsignature":  x509.KeyUsageDigitalSignature,
+	"contentcommitment": x509.KeyUsageContentCommitment,
+	"keyencipherment":   x509.KeyUsageKeyEncipherment,
+	"dataencipherment":  x509.KeyUsageDataEncipherment,
+	"keyagreement":      x509.KeyUsageKeyAgreement,
+}
+
+var extKeyUsages = map[string]x509.ExtKeyUsage{
+	"clientauth":      x509.ExtKeyUsageClientAuth,
+	"serverauth":      x509.ExtKeyUsageServerAuth,
+	"codesigning":     x509.ExtKeyUsageCodeSigning,
+	"emailprotection": x509.ExtKeyUsageEmailProtection,
+	"timestamping":    x509.ExtKeyUsageTimeStamping,
+	"ocspsigning":     x509.ExtKeyUsageOCSPSigning,
+}
+
+// clientCertPolicy is a middleware that enforces a policy on the client certificates.
+type clientCertPolicy struct {
+	next           http.Handler
+	name           string
+	allowedIssuers []string
+	allowedSANs    []*regexp.Regexp
+	maxAge         time.Duration
+	keyUsage       x509.KeyUsage
+	extKeyUsages   []x509.ExtKeyUsage
+}
+
+// New creates a client certificate policy middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.ClientCertPolicy, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	policy := &clientCertPolicy{
+		next:           next,
+		name:           name,
+		allowedIssuers: config.AllowedIssuers,
+		maxAge:         time.Duration(config.MaxAge),
+	}
+
+	for _, pattern := range config.AllowedSANs {
+		exp, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling SAN pattern %q: %w", pattern, err)
+		}
+		policy.allowedSANs = append(policy.allowedSANs, exp)
+	}
+
+	for _, usage := range config.KeyUsages {
+		if ku, ok := keyUsages[strings.ToLower(usage)]; ok {
+			policy.keyUsage |= ku
+			continue
+		}
+
+		eku, ok := extKeyUsages[strings.ToLower(usage)]
+		if !ok {
+			return nil, fmt.Errorf("unknown key usage %q", usage)
+		}
+		policy.extKeyUsages = append(policy.extKeyUsages, eku)
+	}
+
+	return policy, nil
+}
+
+func (c *clientCertPolicy) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *clientCertPolicy) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if reason := c.check(req, time.Now()); reason != "" {
+		logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName))
+		logger.Debugf("Client certificate rejected: %s", reason)
+		tracing.SetErrorWithEvent(req, "Client certificate rejected: %s", reason)
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusForbidden)
+
+		body := struct {
+			Error  string `json:"error"`
+			Reason string `json:"reason"`
+		}{Error: "client certificate rejected", Reason: reason}
+
+		if err := json.NewEncoder(rw).Encode(body); err != nil {
+			logger.Debugf("Error while writing response: %v", err)
+		}
+		return
+	}
+
+	c.next.ServeHTTP(rw, req)
+}
+
+// check returns the reason why the client certificate of the request does not comply with the policy,
+// or an empty string if it does.
+func (c *clientCertPolicy) check(req *http.Request, now time.Time) string {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return "missing client certificate"
+	}
+
+	// Only the certificates verified by the client authentication of the TLS options can be trusted.
+	if len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return "unverified client certificate"
+	}
+
+	cert := req.TLS.VerifiedChains[0][0]
+
+	if len(c.allowedIssuers) > 0 && !c.allowedIssuer(req.TLS.VerifiedChains) {
+		return fmt.Sprintf("issuer %q is not allowed", cert.Issuer.String())
+	}
+
+	if len(c.allowedSANs) > 0 && !c.allowedSAN(cert) {
+		return "no subject alternative name is allowed"
+	}
+
+	// Only the client certificate is concerned by the max age, the CAs of the chain are long-lived by design.
+	if c.maxAge > 0 && now.Sub(cert.NotBefore) > c.maxAge {
+		return fmt.Sprintf("certificate is older than %s", c.maxAge)
+	}
+
+	if cert.KeyUsage&c.keyUsage != c.keyUsage {
+		return "missing required key usage"
+	}
+
+	for _, eku := range c.extKeyUsages {
+		if !hasExtKeyUsage(cert, eku) {
+			return "missing required extended key usage"
+		}
+	}
+
+	return ""
+}
+
+// allowedIssuer tells whether one of the issuers of a verified chain is allowed,
+// the issuers are the certificates of the chain following the client certificate.
+func (c *clientCertPolicy) allowedIssuer(chains [][]*x509.Certificate) bool {
+	for _, chain := range chains {
+		if len(chain) < 2 {
+			continue
+		}
+
+		for _, issuer := range chain[1:] {
+			for _, allowed := range c.allowedIssuers {
+				if allowed == issuer.Subject.CommonName || allowed == issuer.Subject.String() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (c *clientCertPolicy) allowedSAN(cert *x509.Certificate) bool {
+	for _, san := range getSANs(cert) {
+		for _, exp := range c.allowedSANs {
+			if exp.MatchString(san) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasExtKeyUsage(cert *x509.Certificate, usage x509.ExtKeyUsage) bool {
+	for _, eku := range cert.ExtKeyUsage {
+		if eku == usage || eku == x509.ExtKeyUsageAny {
+			return true
+		}
+	}
+	return false
+}
+
+func getSANs(cert *x509.Certificate) []string {
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	return sans
+}
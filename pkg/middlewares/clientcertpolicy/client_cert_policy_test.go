@@ -0,0 +1,211 @@
+package clientcertpolicy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	ptypes "github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCertPolicy(t *testing.T) {
+	ca, caKey := createCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Partner CA"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	leaf, _ := createCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "client"},
+		NotBefore:   time.Now().Add(-time.Hour),
+		DNSNames:    []string{"client.partner.example.com"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	intermediate, intermediateKey := createCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Partner Issuing CA"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, ca, caKey)
+
+	intermediateLeaf, _ := createCertificate(t, &x509.Certificate{
+		Subject:   pkix.Name{CommonName: "client"},
+		NotBefore: time.Now().Add(-time.Hour),
+	}, intermediate, intermediateKey)
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.ClientCertPolicy
+		noCertificate  bool
+		unverified     bool
+		chain          []*x509.Certificate
+		expectedStatus int
+	}{
+		{
+			desc:           "empty policy",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "missing certificate",
+			noCertificate:  true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "unverified certificate",
+			unverified:     true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "unverified certificate with an allowed issuer",
+			config:         dynamic.ClientCertPolicy{AllowedIssuers: []string{"Partner CA"}},
+			unverified:     true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "allowed issuer",
+			config:         dynamic.ClientCertPolicy{AllowedIssuers: []string{"Other CA", "Partner CA"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "forbidden issuer",
+			config:         dynamic.ClientCertPolicy{AllowedIssuers: []string{"Other CA"}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "allowed root issuer of an intermediate",
+			config:         dynamic.ClientCertPolicy{AllowedIssuers: []string{"Partner CA"}},
+			chain:          []*x509.Certificate{intermediateLeaf, intermediate, ca},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "allowed SAN",
+			config:         dynamic.ClientCertPolicy{AllowedSANs: []string{`^.+\.partner\.example\.com$`}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "forbidden SAN",
+			config:         dynamic.ClientCertPolicy{AllowedSANs: []string{`^.+\.internal$`}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "certificate younger than the max age",
+			config:         dynamic.ClientCertPolicy{MaxAge: ptypes.Duration(48 * time.Hour)},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "fresh certificate issued by a CA older than the max age",
+			config:         dynamic.ClientCertPolicy{MaxAge: ptypes.Duration(2 * time.Hour)},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "certificate older than the max age",
+			config:         dynamic.ClientCertPolicy{MaxAge: ptypes.Duration(30 * time.Minute)},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "allowed key usages",
+			config:         dynamic.ClientCertPolicy{KeyUsages: []string{"digitalSignature", "clientAuth"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "missing key usage",
+			config:         dynamic.ClientCertPolicy{KeyUsages: []string{"keyEncipherment"}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "missing extended key usage",
+			config:         dynamic.ClientCertPolicy{KeyUsages: []string{"codeSigning"}},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			handler, err := New(context.Background(), next, test.config, "policy")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "https://localhost", nil)
+			if !test.noCertificate {
+				chain := test.chain
+				if chain == nil {
+					chain = []*x509.Certificate{leaf, ca}
+				}
+
+				req.TLS = &tls.ConnectionState{PeerCertificates: chain}
+				if !test.unverified {
+					req.TLS.VerifiedChains = [][]*x509.Certificate{chain}
+				}
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+
+			if test.expectedStatus == http.StatusForbidden {
+				assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+				var body map[string]string
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&body))
+				assert.Equal(t, "client certificate rejected", body["error"])
+				assert.NotEmpty(t, body["reason"])
+			}
+		})
+	}
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(context.Background(), next, dynamic.ClientCertPolicy{AllowedSANs: []string{"("}}, "policy")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.ClientCertPolicy{KeyUsages: []string{"foo"}}, "policy")
+	assert.Error(t, err)
+}
+
+func createCertificate(t *testing.T, template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotAfter = time.Now().Add(24 * time.Hour)
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
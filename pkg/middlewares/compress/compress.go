@@ -1,12 +1,13 @@
 package compress
 
 import (
-	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"fmt"
 	"mime"
 	"net/http"
+	"strings"
 
-	"github.com/NYTimes/gziphandler"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
@@ -16,30 +17,102 @@ import (
 
 const (
 	typeName = "Compress"
+
+	acceptEncodingHeader = "Accept-Encoding"
+	varyHeader           = "Vary"
+
+	defaultMinSize = 1400
 )
 
+// defaultEncodings are the encodings used when none is configured.
+var defaultEncodings []*encoding
+
+func init() {
+	gzipDefault, err := newEncoding(gzipEncoding, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	defaultEncodings = []*encoding{gzipDefault}
+}
+
 // Compress is a middleware that allows to compress the response.
 type compress struct {
-	next     http.Handler
-	name     string
-	excludes []string
+	next         http.Handler
+	name         string
+	excludes     []mediaTypePattern
+	includes     []mediaTypePattern
+	encodings    []*encoding
+	minSize      int
+	maxSize      int64
+	dictionaries map[[sha256.Size]byte]*dictionary
 }
 
 // New creates a new compress middleware.
 func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
-	excludes := []string{"application/grpc"}
-	for _, v := range conf.ExcludedContentTypes {
-		mediaType, _, err := mime.ParseMediaType(v)
+	excludes, err := parseMediaTypePatterns(append([]string{"application/grpc"}, conf.ExcludedContentTypes...))
+	if err != nil {
+		return nil, err
+	}
+
+	includes, err := parseMediaTypePatterns(conf.IncludedContentTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.MinResponseBodyBytes < 0 {
+		return nil, fmt.Errorf("minResponseBodyBytes must be positive: %d", conf.MinResponseBodyBytes)
+	}
+
+	if conf.MaxResponseBodyBytes < 0 {
+		return nil, fmt.Errorf("maxResponseBodyBytes must be positive: %d", conf.MaxResponseBodyBytes)
+	}
+
+	levels := map[string]int{gzipEncoding: conf.GzipLevel, zstdEncoding: conf.ZstdLevel}
+
+	var encodings []*encoding
+	seen := make(map[string]struct{})
+	for _, name := range conf.Encodings {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("duplicate encoding %q", name)
+		}
+		seen[name] = struct{}{}
+
+		enc, err := newEncoding(name, levels[name])
+		if err != nil {
+			return nil, err
+		}
+
+		encodings = append(encodings, enc)
+	}
+
+	if len(encodings) == 0 && conf.GzipLevel != 0 {
+		enc, err := newEncoding(gzipEncoding, conf.GzipLevel)
 		if err != nil {
 			return nil, err
 		}
 
-		excludes = append(excludes, mediaType)
+		encodings = append(encodings, enc)
 	}
 
-	return &compress{next: next, name: name, excludes: excludes}, nil
+	dictionaries, err := loadDictionaries(conf.Dictionaries)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compress{
+		next:         next,
+		name:         name,
+		excludes:     excludes,
+		includes:     includes,
+		encodings:    encodings,
+		minSize:      conf.MinResponseBodyBytes,
+		maxSize:      conf.MaxResponseBodyBytes,
+		dictionaries: dictionaries,
+	}, nil
 }
 
 func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -48,34 +121,129 @@ func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		log.FromContext(middlewares.GetLoggerCtx(context.Background(), c.name, typeName)).Debug(err)
 	}
 
-	if contains(c.excludes, mediaType) {
+	if mediaType != "" && matchMediaType(c.excludes, mediaType) {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
+
+	if len(c.dictionaries) > 0 {
+		// The response depends on the dictionary advertised by the client.
+		rw.Header().Add(varyHeader, availableDictionaryHeader)
+
+		if dict := c.getDictionary(req); dict != nil {
+			rw.Header().Add(varyHeader, acceptEncodingHeader)
+
+			dczRW := &dczResponseWriter{rw: rw, dict: dict}
+			c.next.ServeHTTP(dczRW, req)
+
+			if err := dczRW.close(); err != nil {
+				log.FromContext(ctx).Errorf("Error while closing dictionary-compressed response: %v", err)
+			}
+			return
+		}
+	}
+
+	rw.Header().Add(varyHeader, acceptEncodingHeader)
+
+	encodings := c.encodings
+	if len(encodings) == 0 {
+		encodings = defaultEncodings
+	}
+
+	enc := negotiateEncoding(req.Header[acceptEncodingHeader], encodings)
+	if enc == nil {
 		c.next.ServeHTTP(rw, req)
-	} else {
-		ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
-		gzipHandler(ctx, c.next).ServeHTTP(rw, req)
+		return
+	}
+
+	minSize := c.minSize
+	if minSize == 0 {
+		minSize = defaultMinSize
+	}
+
+	compressRW := &compressResponseWriter{
+		rw:       rw,
+		encoding: enc,
+		minSize:  minSize,
+		maxSize:  c.maxSize,
+		includes: c.includes,
+	}
+	c.next.ServeHTTP(compressRW, req)
+
+	if err := compressRW.close(); err != nil {
+		log.FromContext(ctx).Errorf("Error while closing %s-compressed response: %v", enc.name, err)
+	}
+}
+
+// getDictionary returns the dictionary advertised by the client, if it is known and if the client accepts the dcz encoding.
+func (c *compress) getDictionary(req *http.Request) *dictionary {
+	if !acceptsEncoding(req.Header[acceptEncodingHeader], dczEncoding) {
+		return nil
+	}
+
+	hash, ok := parseAvailableDictionary(req.Header.Get(availableDictionaryHeader))
+	if !ok {
+		return nil
 	}
+
+	return c.dictionaries[hash]
 }
 
 func (c *compress) GetTracingInformation() (string, ext.SpanKindEnum) {
 	return c.name, tracing.SpanKindNoneEnum
 }
 
-func gzipHandler(ctx context.Context, h http.Handler) http.Handler {
-	wrapper, err := gziphandler.GzipHandlerWithOpts(
-		gziphandler.CompressionLevel(gzip.DefaultCompression),
-		gziphandler.MinSize(gziphandler.DefaultMinSize))
-	if err != nil {
-		log.FromContext(ctx).Error(err)
+// mediaTypePattern is a media type whose type and subtype can end with a "*" wildcard.
+type mediaTypePattern struct {
+	typ     string
+	subtype string
+}
+
+func parseMediaTypePatterns(values []string) ([]mediaTypePattern, error) {
+	var patterns []mediaTypePattern
+	for _, value := range values {
+		mediaType, _, err := mime.ParseMediaType(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid media type %q: %w", value, err)
+		}
+
+		parts := strings.SplitN(mediaType, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid media type %q: type/subtype expected", value)
+		}
+
+		patterns = append(patterns, mediaTypePattern{typ: parts[0], subtype: parts[1]})
 	}
 
-	return wrapper(h)
+	return patterns, nil
 }
 
-func contains(values []string, val string) bool {
-	for _, v := range values {
-		if v == val {
+// matchMediaType tells whether the media type of the given Content-Type value matches one of the patterns.
+func matchMediaType(patterns []mediaTypePattern, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	parts := strings.SplitN(mediaType, "/", 2)
+	if len(parts) != 2 {
+		return false
+	}
+
+	for _, pattern := range patterns {
+		if matchWildcard(pattern.typ, parts[0]) && matchWildcard(pattern.subtype, parts[1]) {
 			return true
 		}
 	}
+
 	return false
 }
+
+func matchWildcard(pattern, value string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(value, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == value
+}
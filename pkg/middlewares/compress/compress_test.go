@@ -1,24 +1,26 @@
 package compress
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/NYTimes/gziphandler"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 const (
-	acceptEncodingHeader  = "Accept-Encoding"
 	contentEncodingHeader = "Content-Encoding"
 	contentTypeHeader     = "Content-Type"
-	varyHeader            = "Vary"
 	gzipValue             = "gzip"
 )
 
@@ -284,6 +286,170 @@ func TestIntegrationShouldCompress(t *testing.T) {
 	}
 }
 
+func TestShouldCompressWithNegotiatedEncoding(t *testing.T) {
+	baseBody := generateBytes(2000)
+
+	testCases := []struct {
+		desc             string
+		conf             dynamic.Compress
+		acceptEncoding   string
+		contentType      string
+		contentLength    bool
+		bodySize         int
+		expectedEncoding string
+	}{
+		{
+			desc:             "gzip by default",
+			acceptEncoding:   "zstd, gzip",
+			expectedEncoding: gzipEncoding,
+		},
+		{
+			desc:             "zstd preferred",
+			conf:             dynamic.Compress{Encodings: []string{"zstd", "gzip"}, ZstdLevel: 19},
+			acceptEncoding:   "gzip, zstd",
+			expectedEncoding: zstdEncoding,
+		},
+		{
+			desc:             "gzip fallback",
+			conf:             dynamic.Compress{Encodings: []string{"zstd", "gzip"}, GzipLevel: 1},
+			acceptEncoding:   "gzip",
+			expectedEncoding: gzipEncoding,
+		},
+		{
+			desc:           "smaller than the minimum size",
+			conf:           dynamic.Compress{MinResponseBodyBytes: 4096},
+			acceptEncoding: "gzip",
+		},
+		{
+			desc:             "custom minimum size",
+			conf:             dynamic.Compress{MinResponseBodyBytes: 100},
+			acceptEncoding:   "gzip",
+			bodySize:         200,
+			expectedEncoding: gzipEncoding,
+		},
+		{
+			desc:           "larger than the maximum size",
+			conf:           dynamic.Compress{MaxResponseBodyBytes: 1024},
+			acceptEncoding: "gzip",
+			contentLength:  true,
+		},
+		{
+			desc:             "included content type",
+			conf:             dynamic.Compress{IncludedContentTypes: []string{"text/*", "application/json"}},
+			acceptEncoding:   "gzip",
+			contentType:      "text/html; charset=utf-8",
+			expectedEncoding: gzipEncoding,
+		},
+		{
+			desc:           "not included content type",
+			conf:           dynamic.Compress{IncludedContentTypes: []string{"text/*", "application/json"}},
+			acceptEncoding: "gzip",
+			contentType:    "image/png",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			body := baseBody
+			if test.bodySize > 0 {
+				body = baseBody[:test.bodySize]
+			}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if test.contentType != "" {
+					rw.Header().Set(contentTypeHeader, test.contentType)
+				}
+				if test.contentLength {
+					rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				}
+
+				_, err := rw.Write(body)
+				assert.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, test.conf, "test")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set(acceptEncodingHeader, test.acceptEncoding)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedEncoding, rw.Header().Get(contentEncodingHeader))
+			assert.Equal(t, acceptEncodingHeader, rw.Header().Get(varyHeader))
+
+			var decoded []byte
+			switch test.expectedEncoding {
+			case gzipEncoding:
+				reader, err := gzip.NewReader(rw.Body)
+				require.NoError(t, err)
+
+				decoded, err = ioutil.ReadAll(reader)
+				require.NoError(t, err)
+
+			case zstdEncoding:
+				decoder, err := zstd.NewReader(nil)
+				require.NoError(t, err)
+				defer decoder.Close()
+
+				decoded, err = decoder.DecodeAll(rw.Body.Bytes(), nil)
+				require.NoError(t, err)
+
+			default:
+				decoded = rw.Body.Bytes()
+			}
+
+			assert.True(t, bytes.Equal(body, decoded), "unexpected body")
+		})
+	}
+}
+
+func TestNewWithInvalidEncodingConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc string
+		conf dynamic.Compress
+	}{
+		{
+			desc: "unsupported encoding",
+			conf: dynamic.Compress{Encodings: []string{"br"}},
+		},
+		{
+			desc: "duplicate encoding",
+			conf: dynamic.Compress{Encodings: []string{"gzip", "GZIP"}},
+		},
+		{
+			desc: "invalid gzip level",
+			conf: dynamic.Compress{GzipLevel: 10},
+		},
+		{
+			desc: "invalid zstd level",
+			conf: dynamic.Compress{Encodings: []string{"zstd"}, ZstdLevel: 23},
+		},
+		{
+			desc: "invalid included content type",
+			conf: dynamic.Compress{IncludedContentTypes: []string{"text"}},
+		},
+		{
+			desc: "negative maximum size",
+			conf: dynamic.Compress{MaxResponseBodyBytes: -1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.conf, "test")
+			assert.Error(t, err)
+		})
+	}
+}
+
 func generateBytes(len int) []byte {
 	var value []byte
 	for i := 0; i < len; i++ {
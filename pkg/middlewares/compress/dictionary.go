@@ -0,0 +1,202 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	dczEncoding = "dcz"
+
+	availableDictionaryHeader = "Available-Dictionary"
+)
+
+// dczMagic is the header of a Dictionary-Compressed Zstandard stream (a Zstandard skippable frame),
+// it is followed by the SHA-256 hash of the dictionary.
+var dczMagic = []byte{0x5e, 0x2a, 0x4d, 0x18, 0x20, 0x00, 0x00, 0x00}
+
+// zstdDictionaryMagic is the header of a dictionary in the Zstandard format (e.g. built with zstd --train).
+var zstdDictionaryMagic = []byte{0x37, 0xa4, 0x30, 0xec}
+
+// dictionary is a pre-shared Zstandard dictionary.
+type dictionary struct {
+	hash     [sha256.Size]byte
+	encoders sync.Pool
+}
+
+func newDictionary(content []byte) (*dictionary, error) {
+	// The encoder only supports dictionaries in the Zstandard format, raw content dictionaries would not be usable.
+	if !bytes.HasPrefix(content, zstdDictionaryMagic) {
+		return nil, errors.New("not a Zstandard dictionary, raw dictionaries are not supported")
+	}
+
+	// Ensures that the dictionary is usable before it gets used in the pool.
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderDict(content))
+	if err != nil {
+		return nil, err
+	}
+
+	d := &dictionary{hash: sha256.Sum256(content)}
+	d.encoders.Put(enc)
+	d.encoders.New = func() interface{} {
+		// The dictionary has already been validated.
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderDict(content))
+		return enc
+	}
+
+	return d, nil
+}
+
+// loadDictionaries reads the dictionaries files, and indexes them by their SHA-256 hash.
+func loadDictionaries(paths []string) (map[[sha256.Size]byte]*dictionary, error) {
+	dictionaries := make(map[[sha256.Size]byte]*dictionary)
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading dictionary %s: %w", path, err)
+		}
+
+		dict, err := newDictionary(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dictionary %s: %w", path, err)
+		}
+
+		dictionaries[dict.hash] = dict
+	}
+
+	return dictionaries, nil
+}
+
+// parseAvailableDictionary parses the Available-Dictionary header value,
+// which is a structured field byte sequence holding the SHA-256 hash of the dictionary (e.g. ":pZGm1Av0IEBKARczz7exkNYsZb8LzaMrV7J32a2fFG4=:").
+func parseAvailableDictionary(value string) ([sha256.Size]byte, bool) {
+	var hash [sha256.Size]byte
+
+	value = strings.TrimSpace(value)
+	if len(value) < 2 || value[0] != ':' || value[len(value)-1] != ':' {
+		return hash, false
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(value[1 : len(value)-1])
+	if err != nil || len(raw) != sha256.Size {
+		return hash, false
+	}
+
+	copy(hash[:], raw)
+	return hash, true
+}
+
+// acceptsEncoding tells whether the Accept-Encoding header values contain the given encoding with a non-zero quality.
+func acceptsEncoding(values []string, encoding string) bool {
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			token := strings.Split(part, ";")
+			if !strings.EqualFold(strings.TrimSpace(token[0]), encoding) {
+				continue
+			}
+
+			if len(token) > 1 && strings.ReplaceAll(strings.TrimSpace(token[1]), " ", "") == "q=0" {
+				return false
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// dczResponseWriter compresses the response body with a pre-shared dictionary.
+type dczResponseWriter struct {
+	rw   http.ResponseWriter
+	dict *dictionary
+
+	encoder     *zstd.Encoder
+	wroteHeader bool
+}
+
+func (w *dczResponseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *dczResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if w.Header().Get("Content-Encoding") != "" || code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	w.Header().Set("Content-Encoding", dczEncoding)
+	w.Header().Del("Content-Length")
+	w.rw.WriteHeader(code)
+
+	// The errors are reported by the next call to Write.
+	_, _ = w.rw.Write(dczMagic)
+	_, _ = w.rw.Write(w.dict.hash[:])
+
+	w.encoder = w.dict.encoders.Get().(*zstd.Encoder)
+	w.encoder.Reset(w.rw)
+}
+
+func (w *dczResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.encoder == nil {
+		return w.rw.Write(p)
+	}
+
+	return w.encoder.Write(p)
+}
+
+// Flush sends any buffered data to the client.
+func (w *dczResponseWriter) Flush() {
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (w *dczResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.rw.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+}
+
+// close terminates the compressed stream, and gives back the encoder to the pool.
+func (w *dczResponseWriter) close() error {
+	if w.encoder == nil {
+		return nil
+	}
+
+	err := w.encoder.Close()
+	w.encoder.Reset(nil)
+	w.dict.encoders.Put(w.encoder)
+	w.encoder = nil
+
+	return err
+}
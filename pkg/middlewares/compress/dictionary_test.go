@@ -0,0 +1,119 @@
+package compress
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dictionaryPath = "./testdata/dictionary.zstd"
+
+func TestShouldCompressWithDictionary(t *testing.T) {
+	dict, err := ioutil.ReadFile(dictionaryPath)
+	require.NoError(t, err)
+
+	hash := sha256.Sum256(dict)
+	availableDictionary := ":" + base64.StdEncoding.EncodeToString(hash[:]) + ":"
+	unknownDictionary := ":" + base64.StdEncoding.EncodeToString(make([]byte, sha256.Size)) + ":"
+
+	baseBody := []byte(`{"apiVersion":"v1","kind":"Invoice","metadata":{"id":42,"tenant":"acme-1","labels":{"env":"production","region":"eu-west-1"}},"spec":{"currency":"EUR","amount":1234,"status":"paid"}}`)
+
+	testCases := []struct {
+		desc                string
+		acceptEncoding      string
+		availableDictionary string
+		expectedEncoding    string
+	}{
+		{
+			desc:                "known dictionary",
+			acceptEncoding:      "gzip, dcz",
+			availableDictionary: availableDictionary,
+			expectedEncoding:    dczEncoding,
+		},
+		{
+			desc:                "unknown dictionary",
+			acceptEncoding:      "gzip, dcz",
+			availableDictionary: unknownDictionary,
+			expectedEncoding:    "",
+		},
+		{
+			desc:                "dcz not accepted",
+			acceptEncoding:      "gzip, dcz;q=0",
+			availableDictionary: availableDictionary,
+			expectedEncoding:    "",
+		},
+		{
+			desc:                "invalid dictionary header",
+			acceptEncoding:      "dcz",
+			availableDictionary: "foo",
+			expectedEncoding:    "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set(contentTypeHeader, "application/json")
+				_, err := rw.Write(baseBody)
+				assert.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, dynamic.Compress{Dictionaries: []string{dictionaryPath}}, "test")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set(acceptEncodingHeader, test.acceptEncoding)
+			req.Header.Set(availableDictionaryHeader, test.availableDictionary)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedEncoding, rw.Header().Get(contentEncodingHeader))
+			assert.Contains(t, rw.Header()[varyHeader], availableDictionaryHeader)
+
+			if test.expectedEncoding != dczEncoding {
+				// The body is smaller than the gzip minimum size.
+				assert.Equal(t, baseBody, rw.Body.Bytes())
+				return
+			}
+
+			body := rw.Body.Bytes()
+			require.True(t, bytes.HasPrefix(body, dczMagic))
+			assert.Equal(t, hash[:], body[len(dczMagic):len(dczMagic)+sha256.Size])
+
+			decoder, err := zstd.NewReader(bytes.NewReader(body[len(dczMagic)+sha256.Size:]), zstd.WithDecoderDicts(dict))
+			require.NoError(t, err)
+			defer decoder.Close()
+
+			decoded, err := ioutil.ReadAll(decoder)
+			require.NoError(t, err)
+			assert.Equal(t, baseBody, decoded)
+		})
+	}
+}
+
+func TestNewWithInvalidDictionary(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.Compress{Dictionaries: []string{"./testdata/missing.zstd"}}, "test")
+	require.Error(t, err)
+}
+
+func TestNewWithRawDictionary(t *testing.T) {
+	// A raw dictionary is plain content shared with the clients, without the Zstandard dictionary header.
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.Compress{Dictionaries: []string{"./testdata/raw.dict"}}, "test")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "raw dictionaries are not supported")
+}
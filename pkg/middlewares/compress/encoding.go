@@ -0,0 +1,321 @@
+package compress
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	gzipEncoding = "gzip"
+	zstdEncoding = "zstd"
+)
+
+// encoder is a compressing writer, which can be reused with Reset.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// encoding is a content-encoding, with a pool of encoders configured with its compression level.
+type encoding struct {
+	name     string
+	encoders sync.Pool
+}
+
+func newEncoding(name string, level int) (*encoding, error) {
+	e := &encoding{name: name}
+
+	switch name {
+	case gzipEncoding:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		if level != gzip.DefaultCompression && (level < gzip.BestSpeed || level > gzip.BestCompression) {
+			return nil, fmt.Errorf("invalid gzip level %d: must be between %d and %d", level, gzip.BestSpeed, gzip.BestCompression)
+		}
+
+		e.encoders.New = func() interface{} {
+			// The level has already been validated.
+			enc, _ := gzip.NewWriterLevel(nil, level)
+			return enc
+		}
+
+	case zstdEncoding:
+		encoderLevel := zstd.SpeedDefault
+		if level != 0 {
+			if level < 1 || level > 22 {
+				return nil, fmt.Errorf("invalid zstd level %d: must be between 1 and 22", level)
+			}
+			encoderLevel = zstd.EncoderLevelFromZstd(level)
+		}
+
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel), zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+
+		e.encoders.Put(enc)
+		e.encoders.New = func() interface{} {
+			// The options have already been validated.
+			enc, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel), zstd.WithEncoderConcurrency(1))
+			return enc
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q, must be one of %s, %s", name, gzipEncoding, zstdEncoding)
+	}
+
+	return e, nil
+}
+
+func (e *encoding) getEncoder(w io.Writer) encoder {
+	enc := e.encoders.Get().(encoder)
+	enc.Reset(w)
+	return enc
+}
+
+func (e *encoding) putEncoder(enc encoder) {
+	enc.Reset(nil)
+	e.encoders.Put(enc)
+}
+
+// negotiateEncoding returns the encoding preferred by the client, according to the qualities of its Accept-Encoding header.
+// Among the encodings of the same quality, the first one of the given encodings wins.
+func negotiateEncoding(values []string, encodings []*encoding) *encoding {
+	qualities := parseAcceptEncoding(values)
+
+	var best *encoding
+	var bestQuality float64
+	for _, enc := range encodings {
+		quality, ok := qualities[enc.name]
+		if !ok {
+			quality = qualities["*"]
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = enc, quality
+		}
+	}
+
+	return best
+}
+
+// parseAcceptEncoding returns the quality of each encoding of the Accept-Encoding header values.
+func parseAcceptEncoding(values []string) map[string]float64 {
+	qualities := make(map[string]float64)
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			params := strings.Split(part, ";")
+
+			name := strings.ToLower(strings.TrimSpace(params[0]))
+			if name == "" {
+				continue
+			}
+
+			quality := 1.0
+			for _, param := range params[1:] {
+				param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+				if !strings.HasPrefix(param, "q=") {
+					continue
+				}
+
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil || q < 0 || q > 1 {
+					q = 0
+				}
+				quality = q
+			}
+
+			qualities[name] = quality
+		}
+	}
+
+	return qualities
+}
+
+// compressResponseWriter compresses the response body with the negotiated encoding.
+// The body is buffered until it reaches the minimum size, the smaller bodies are sent as is.
+type compressResponseWriter struct {
+	rw       http.ResponseWriter
+	encoding *encoding
+	minSize  int
+	maxSize  int64
+	includes []mediaTypePattern
+
+	code        int
+	wroteHeader bool
+	headerSent  bool
+	ignore      bool
+	buf         []byte
+	encoder     encoder
+}
+
+func (w *compressResponseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *compressResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+
+	if code < http.StatusOK {
+		// Informational responses are sent as is, and are followed by the final response.
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	w.wroteHeader = true
+	w.code = code
+	w.ignore = !w.compressible()
+
+	if w.ignore {
+		w.sendHeader()
+	}
+}
+
+// compressible tells whether the response can be compressed, according to its status code and headers.
+func (w *compressResponseWriter) compressible() bool {
+	if w.code == http.StatusNoContent || w.code == http.StatusNotModified {
+		return false
+	}
+
+	if w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+
+	if contentLength := w.Header().Get("Content-Length"); contentLength != "" {
+		length, err := strconv.ParseInt(contentLength, 10, 64)
+		if err == nil && (length < int64(w.minSize) || w.maxSize > 0 && length > w.maxSize) {
+			return false
+		}
+	}
+
+	if len(w.includes) > 0 {
+		contentType := w.Header().Get("Content-Type")
+		if contentType == "" || !matchMediaType(w.includes, contentType) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (w *compressResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.ignore {
+		return w.rw.Write(p)
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.minSize {
+		if err := w.startEncoding(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// startEncoding sends the header of the compressed response, and compresses the buffered body.
+func (w *compressResponseWriter) startEncoding() error {
+	w.Header().Set("Content-Encoding", w.encoding.name)
+	w.Header().Del("Content-Length")
+	w.sendHeader()
+
+	w.encoder = w.encoding.getEncoder(w.rw)
+
+	buf := w.buf
+	w.buf = nil
+
+	_, err := w.encoder.Write(buf)
+	return err
+}
+
+func (w *compressResponseWriter) sendHeader() {
+	if w.headerSent {
+		return
+	}
+	w.headerSent = true
+
+	w.rw.WriteHeader(w.code)
+}
+
+// Flush sends any buffered data to the client.
+// A response flushed before reaching the minimum size is compressed nonetheless, as it is likely streamed.
+func (w *compressResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.ignore && w.encoder == nil {
+		if err := w.startEncoding(); err != nil {
+			return
+		}
+	}
+
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (w *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.rw.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+}
+
+// close terminates the compressed stream and gives back the encoder to the pool,
+// or sends the buffered body as is when it is smaller than the minimum size.
+func (w *compressResponseWriter) close() error {
+	if w.encoder != nil {
+		err := w.encoder.Close()
+		w.encoding.putEncoder(w.encoder)
+		w.encoder = nil
+
+		return err
+	}
+
+	if !w.wroteHeader {
+		return nil
+	}
+
+	w.sendHeader()
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	_, err := w.rw.Write(w.buf)
+	w.buf = nil
+
+	return err
+}
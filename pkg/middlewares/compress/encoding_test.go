@@ -0,0 +1,79 @@
+package compress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	gzipEnc, err := newEncoding(gzipEncoding, 0)
+	require.NoError(t, err)
+
+	zstdEnc, err := newEncoding(zstdEncoding, 0)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc           string
+		acceptEncoding []string
+		encodings      []*encoding
+		expected       string
+	}{
+		{
+			desc:      "no Accept-Encoding",
+			encodings: []*encoding{gzipEnc, zstdEnc},
+		},
+		{
+			desc:           "configured priority between equal qualities",
+			acceptEncoding: []string{"gzip, zstd"},
+			encodings:      []*encoding{zstdEnc, gzipEnc},
+			expected:       zstdEncoding,
+		},
+		{
+			desc:           "client quality over configured priority",
+			acceptEncoding: []string{"gzip;q=1.0, zstd;q=0.5"},
+			encodings:      []*encoding{zstdEnc, gzipEnc},
+			expected:       gzipEncoding,
+		},
+		{
+			desc:           "refused encoding",
+			acceptEncoding: []string{"zstd;q=0", "gzip"},
+			encodings:      []*encoding{zstdEnc, gzipEnc},
+			expected:       gzipEncoding,
+		},
+		{
+			desc:           "wildcard",
+			acceptEncoding: []string{"br, *;q=0.1"},
+			encodings:      []*encoding{zstdEnc, gzipEnc},
+			expected:       zstdEncoding,
+		},
+		{
+			desc:           "wildcard refusing the unlisted encodings",
+			acceptEncoding: []string{"GZIP, *;q=0"},
+			encodings:      []*encoding{zstdEnc, gzipEnc},
+			expected:       gzipEncoding,
+		},
+		{
+			desc:           "unsupported encodings only",
+			acceptEncoding: []string{"br, deflate"},
+			encodings:      []*encoding{gzipEnc, zstdEnc},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			enc := negotiateEncoding(test.acceptEncoding, test.encodings)
+			if test.expected == "" {
+				assert.Nil(t, enc)
+				return
+			}
+
+			require.NotNil(t, enc)
+			assert.Equal(t, test.expected, enc.name)
+		})
+	}
+}
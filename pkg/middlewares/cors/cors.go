@@ -0,0 +1,223 @@
+package cors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "CORS"
+)
+
+// policy is a CORS policy with its origins compiled.
+type policy struct {
+	anyOrigin        bool
+	origins          map[string]struct{}
+	originPatterns   []*regexp.Regexp
+	allowMethods     string
+	allowHeaders     string
+	exposeHeaders    string
+	allowCredentials bool
+	maxAge           string
+	privateNetwork   bool
+}
+
+// cors is a middleware that answers the CORS preflight requests and sets the CORS headers of the responses,
+// with the policy matching the origin of the request.
+type cors struct {
+	next     http.Handler
+	name     string
+	policies []policy
+}
+
+// New creates a CORS middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.CORS, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Policies) == 0 {
+		return nil, fmt.Errorf("no policy defined")
+	}
+
+	policies := make([]policy, 0, len(config.Policies))
+	for i, cfg := range config.Policies {
+		p, err := newPolicy(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy %d: %w", i, err)
+		}
+		policies = append(policies, p)
+	}
+
+	return &cors{
+		next:     next,
+		name:     name,
+		policies: policies,
+	}, nil
+}
+
+func newPolicy(cfg dynamic.CORSPolicy) (policy, error) {
+	if len(cfg.Origins) == 0 {
+		return policy{}, fmt.Errorf("no origin defined")
+	}
+
+	p := policy{
+		origins:          make(map[string]struct{}),
+		allowMethods:     strings.Join(cfg.AllowMethods, ", "),
+		allowHeaders:     strings.Join(cfg.AllowHeaders, ", "),
+		exposeHeaders:    strings.Join(cfg.ExposeHeaders, ", "),
+		allowCredentials: cfg.AllowCredentials,
+		privateNetwork:   cfg.AllowPrivateNetwork,
+	}
+
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.FormatInt(cfg.MaxAge, 10)
+	}
+
+	for _, origin := range cfg.Origins {
+		switch {
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			// The wildcards match a sequence of characters of a hostname, but not a scheme or a port separator.
+			pattern := strings.ReplaceAll(regexp.QuoteMeta(strings.ToLower(origin)), `\*`, `[a-z0-9.-]+`)
+			exp, err := regexp.Compile("^" + pattern + "$")
+			if err != nil {
+				return policy{}, fmt.Errorf("invalid origin %q: %w", origin, err)
+			}
+			p.originPatterns = append(p.originPatterns, exp)
+		default:
+			p.origins[strings.ToLower(origin)] = struct{}{}
+		}
+	}
+
+	return p, nil
+}
+
+func (p policy) match(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if _, ok := p.origins[origin]; ok {
+		return true
+	}
+
+	for _, exp := range p.originPatterns {
+		if exp.MatchString(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *cors) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *cors) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	preflight := isPreflight(req)
+
+	// The CORS headers depend on the origin, so the responses must not be cached regardless of it.
+	vary := []string{"Origin"}
+	if preflight {
+		vary = append(vary, "Access-Control-Request-Method", "Access-Control-Request-Headers")
+	}
+	addVary(rw.Header(), vary...)
+
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	p, ok := c.matchPolicy(origin)
+	if !ok {
+		// Without CORS headers, the browser blocks the response, unless the service answers with its own.
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	p.setOriginHeaders(rw.Header(), origin)
+
+	if !preflight {
+		if p.exposeHeaders != "" {
+			rw.Header().Set("Access-Control-Expose-Headers", p.exposeHeaders)
+		}
+
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if p.allowMethods != "" {
+		rw.Header().Set("Access-Control-Allow-Methods", p.allowMethods)
+	}
+
+	if p.allowHeaders != "" {
+		rw.Header().Set("Access-Control-Allow-Headers", p.allowHeaders)
+	}
+
+	if p.maxAge != "" {
+		rw.Header().Set("Access-Control-Max-Age", p.maxAge)
+	}
+
+	if p.privateNetwork && req.Header.Get("Access-Control-Request-Private-Network") == "true" {
+		rw.Header().Set("Access-Control-Allow-Private-Network", "true")
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (c *cors) matchPolicy(origin string) (policy, bool) {
+	for _, p := range c.policies {
+		if p.match(origin) {
+			return p, true
+		}
+	}
+	return policy{}, false
+}
+
+func (p policy) setOriginHeaders(header http.Header, origin string) {
+	// The wildcard cannot be used with credentials, so the origin is echoed instead.
+	if p.anyOrigin && !p.allowCredentials {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+
+	if p.allowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func isPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions &&
+		req.Header.Get("Origin") != "" &&
+		req.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// addVary adds the given header names to the Vary header, unless already present.
+func addVary(header http.Header, names ...string) {
+	existing := make(map[string]struct{})
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			existing[http.CanonicalHeaderKey(strings.TrimSpace(name))] = struct{}{}
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := existing[name]; !ok {
+			header.Add("Vary", name)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package cors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.CORS
+		expectedError bool
+	}{
+		{
+			desc:          "no policy",
+			expectedError: true,
+		},
+		{
+			desc:          "policy without origin",
+			config:        dynamic.CORS{Policies: []dynamic.CORSPolicy{{AllowMethods: []string{"GET"}}}},
+			expectedError: true,
+		},
+		{
+			desc:   "valid policies",
+			config: dynamic.CORS{Policies: []dynamic.CORSPolicy{{Origins: []string{"https://*.example.com"}}, {Origins: []string{"*"}}}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, "cors")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	config := dynamic.CORS{
+		Policies: []dynamic.CORSPolicy{
+			{
+				Origins:             []string{"https://app.example.org", "https://*.example.com"},
+				AllowMethods:        []string{"GET", "POST"},
+				AllowHeaders:        []string{"Authorization"},
+				ExposeHeaders:       []string{"X-Total-Count"},
+				AllowCredentials:    true,
+				MaxAge:              600,
+				AllowPrivateNetwork: true,
+			},
+			{
+				Origins:      []string{"*"},
+				AllowMethods: []string{"GET"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc            string
+		method          string
+		headers         map[string]string
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedVary    []string
+	}{
+		{
+			desc:           "no origin",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+			expectedVary: []string{"Origin"},
+		},
+		{
+			desc:           "exact origin",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://app.example.org"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.org",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Total-Count",
+				"Access-Control-Allow-Methods":     "",
+			},
+			expectedVary: []string{"Origin"},
+		},
+		{
+			desc:           "origin matching a wildcard",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://eu.app.Example.com"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://eu.app.Example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+			expectedVary: []string{"Origin"},
+		},
+		{
+			desc:           "origin not matching a wildcard falls back to any origin",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://example.com.evil.org"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "*",
+				"Access-Control-Allow-Credentials": "",
+				"Access-Control-Expose-Headers":    "",
+			},
+			expectedVary: []string{"Origin"},
+		},
+		{
+			desc:   "preflight",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                                 "https://app.example.org",
+				"Access-Control-Request-Method":          "POST",
+				"Access-Control-Request-Private-Network": "true",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":          "https://app.example.org",
+				"Access-Control-Allow-Credentials":     "true",
+				"Access-Control-Allow-Methods":         "GET, POST",
+				"Access-Control-Allow-Headers":         "Authorization",
+				"Access-Control-Max-Age":               "600",
+				"Access-Control-Allow-Private-Network": "true",
+			},
+			expectedVary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			desc:   "preflight of the any origin policy",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                                 "https://other.org",
+				"Access-Control-Request-Method":          "GET",
+				"Access-Control-Request-Private-Network": "true",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":          "*",
+				"Access-Control-Allow-Methods":         "GET",
+				"Access-Control-Max-Age":               "",
+				"Access-Control-Allow-Private-Network": "",
+			},
+			expectedVary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			desc:           "options request without request method is not a preflight",
+			method:         http.MethodOptions,
+			headers:        map[string]string{"Origin": "https://app.example.org"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.org",
+				"Access-Control-Allow-Methods": "",
+			},
+			expectedVary: []string{"Origin"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := New(context.Background(), next, config, "cors")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(test.method, "http://localhost", nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, rw.Header().Get(name), name)
+			}
+			assert.Equal(t, test.expectedVary, rw.Header().Values("Vary"))
+		})
+	}
+}
+
+func TestCORS_unmatchedOrigin(t *testing.T) {
+	config := dynamic.CORS{
+		Policies: []dynamic.CORSPolicy{{Origins: []string{"https://app.example.org"}}},
+	}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+	})
+
+	handler, err := New(context.Background(), next, config, "cors")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodOptions, "http://localhost", nil)
+	req.Header.Set("Origin", "https://other.org")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	assert.Empty(t, rw.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,234 @@
+package debugheaders
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+)
+
+const (
+	typeName = "DebugHeaders"
+
+	// TokenHeader is the request header carrying the signed debug token.
+	TokenHeader = "X-Traefik-Debug-Token"
+
+	routerHeader  = "X-Traefik-Debug-Router"
+	serverHeader  = "X-Traefik-Debug-Server"
+	retriesHeader = "X-Traefik-Debug-Retries"
+	timingHeader  = "Server-Timing"
+)
+
+type key struct{}
+
+// trace holds the routing decisions taken for a request, collected along the handlers chain.
+type trace struct {
+	mu            sync.Mutex
+	server        string
+	attempts      int
+	attemptsStart time.Time
+}
+
+func (t *trace) startAttempt(server string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.server = server
+	t.attempts++
+	t.attemptsStart = time.Now()
+}
+
+// debugHeaders is a middleware that appends, to the responses of the requests carrying a valid debug token,
+// headers identifying the router, the selected server, the number of retries, and a timing breakdown.
+type debugHeaders struct {
+	next       http.Handler
+	secret     []byte
+	routerName string
+}
+
+// New creates a debug headers middleware for the given router.
+func New(ctx context.Context, next http.Handler, secret, routerName string) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, routerName, typeName)).Debug("Creating middleware")
+
+	return &debugHeaders{
+		next:       next,
+		secret:     []byte(secret),
+		routerName: routerName,
+	}
+}
+
+// WrapHandler wraps the debug headers middleware in an alice.Constructor.
+func WrapHandler(ctx context.Context, secret, routerName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return New(ctx, next, secret, routerName), nil
+	}
+}
+
+func (d *debugHeaders) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	token := req.Header.Get(TokenHeader)
+	if token == "" {
+		d.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// The token is never forwarded to the servers.
+	req.Header.Del(TokenHeader)
+
+	if err := VerifyToken(d.secret, token, time.Now()); err != nil {
+		logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), d.routerName, typeName))
+		logger.Debugf("Ignoring debug token: %v", err)
+
+		d.next.ServeHTTP(rw, req)
+		return
+	}
+
+	t := &trace{}
+	recorder := &responseWriter{
+		ResponseWriter: rw,
+		routerName:     d.routerName,
+		trace:          t,
+		start:          time.Now(),
+	}
+
+	d.next.ServeHTTP(recorder, req.WithContext(context.WithValue(req.Context(), key{}, t)))
+}
+
+// WrapServerHandler records the server selected by the load-balancer, for the requests being debugged.
+// It is called once per attempt, so the retries are counted as well.
+func WrapServerHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if t, ok := req.Context().Value(key{}).(*trace); ok {
+			t.startAttempt(req.URL.Scheme + "://" + req.URL.Host)
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// NewToken creates a debug token, signed with the given secret, which is valid until the given expiry.
+func NewToken(secret []byte, expiry time.Time) string {
+	expires := strconv.FormatInt(expiry.Unix(), 10)
+	return expires + "." + sign(secret, expires)
+}
+
+// VerifyToken checks the signature and the expiry of a debug token.
+func VerifyToken(secret []byte, token string, now time.Time) error {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return errors.New("malformed token")
+	}
+
+	if !hmac.Equal([]byte(parts[1]), []byte(sign(secret, parts[0]))) {
+		return errors.New("invalid token signature")
+	}
+
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid token expiry: %w", err)
+	}
+
+	if now.After(time.Unix(expires, 0)) {
+		return fmt.Errorf("token expired at %s", time.Unix(expires, 0).UTC())
+	}
+
+	return nil
+}
+
+func sign(secret []byte, expires string) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+
+	routerName  string
+	trace       *trace
+	start       time.Time
+	wroteHeader bool
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+		r.addHeaders()
+	}
+
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseWriter) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseWriter) addHeaders() {
+	now := time.Now()
+	total := now.Sub(r.start)
+
+	r.trace.mu.Lock()
+	server, attempts, attemptsStart := r.trace.server, r.trace.attempts, r.trace.attemptsStart
+	r.trace.mu.Unlock()
+
+	header := r.Header()
+	header.Set(routerHeader, r.routerName)
+
+	if attempts == 0 {
+		header.Add(timingHeader, fmt.Sprintf("traefik;dur=%s", milliseconds(total)))
+		return
+	}
+
+	upstream := now.Sub(attemptsStart)
+
+	header.Set(serverHeader, server)
+	header.Set(retriesHeader, strconv.Itoa(attempts-1))
+	header.Add(timingHeader, fmt.Sprintf("traefik;dur=%s, upstream;dur=%s", milliseconds(total-upstream), milliseconds(upstream)))
+}
+
+func (r *responseWriter) Flush() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.ResponseWriter)
+	}
+
+	return hijacker.Hijack()
+}
+
+func (r *responseWriter) CloseNotify() <-chan bool {
+	if notifier, ok := r.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+
+	return make(chan bool)
+}
+
+func milliseconds(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
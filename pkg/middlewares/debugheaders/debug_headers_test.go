@@ -0,0 +1,154 @@
+package debugheaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyToken(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1600000000, 0)
+
+	testCases := []struct {
+		desc          string
+		token         string
+		expectedError bool
+	}{
+		{
+			desc:  "valid token",
+			token: NewToken(secret, now.Add(time.Minute)),
+		},
+		{
+			desc:          "expired token",
+			token:         NewToken(secret, now.Add(-time.Minute)),
+			expectedError: true,
+		},
+		{
+			desc:          "token signed with another secret",
+			token:         NewToken([]byte("foo"), now.Add(time.Minute)),
+			expectedError: true,
+		},
+		{
+			desc:          "tampered expiry",
+			token:         "1900000000." + NewToken(secret, now.Add(time.Minute))[11:],
+			expectedError: true,
+		},
+		{
+			desc:          "malformed token",
+			token:         "foo",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := VerifyToken(secret, test.token, now)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDebugHeaders(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		token           string
+		attempts        int
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:     "no token",
+			attempts: 1,
+			expectedHeaders: map[string]string{
+				routerHeader:  "",
+				serverHeader:  "",
+				retriesHeader: "",
+			},
+		},
+		{
+			desc:     "invalid token",
+			token:    NewToken([]byte("foo"), time.Now().Add(time.Minute)),
+			attempts: 1,
+			expectedHeaders: map[string]string{
+				routerHeader:  "",
+				serverHeader:  "",
+				retriesHeader: "",
+			},
+		},
+		{
+			desc:     "valid token",
+			token:    NewToken([]byte("secret"), time.Now().Add(time.Minute)),
+			attempts: 1,
+			expectedHeaders: map[string]string{
+				routerHeader:  "foo@file",
+				serverHeader:  "http://10.0.0.2:80",
+				retriesHeader: "0",
+			},
+		},
+		{
+			desc:     "valid token with retries",
+			token:    NewToken([]byte("secret"), time.Now().Add(time.Minute)),
+			attempts: 3,
+			expectedHeaders: map[string]string{
+				routerHeader:  "foo@file",
+				serverHeader:  "http://10.0.0.2:80",
+				retriesHeader: "2",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := WrapServerHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Empty(t, req.Header.Get(TokenHeader))
+			}))
+
+			// Simulates a load-balancer retrying on another server.
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				for i := test.attempts; i > 0; i-- {
+					attempt := req.Clone(req.Context())
+					attempt.URL = &url.URL{Scheme: "http", Host: "10.0.0." + string(rune('1'+i%2)) + ":80"}
+					server.ServeHTTP(rw, attempt)
+				}
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler := New(context.Background(), next, "secret", "foo@file")
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			if test.token != "" {
+				req.Header.Set(TokenHeader, test.token)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			require.Equal(t, http.StatusOK, rw.Code)
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, rw.Header().Get(name), name)
+			}
+
+			if test.expectedHeaders[routerHeader] != "" {
+				assert.Regexp(t, `^traefik;dur=\d+\.\d{3}, upstream;dur=\d+\.\d{3}$`, rw.Header().Get(timingHeader))
+			} else {
+				assert.Empty(t, rw.Header().Get(timingHeader))
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package etag
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "ETag"
+)
+
+const defaultMaxBodySize int64 = 1024 * 1024
+
+// etag is a middleware that computes and validates ETags on behalf of the services,
+// and answers 304 Not Modified to the matching conditional requests.
+type etag struct {
+	next        http.Handler
+	name        string
+	weak        bool
+	maxBodySize int64
+}
+
+// New creates an ETag middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.ETag, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	maxBodySize := config.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+
+	return &etag{
+		next:        next,
+		name:        name,
+		weak:        config.Weak,
+		maxBodySize: maxBodySize,
+	}, nil
+}
+
+func (e *etag) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return e.name, tracing.SpanKindNoneEnum
+}
+
+func (e *etag) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		e.next.ServeHTTP(rw, req)
+		return
+	}
+
+	recorder := &responseRecorder{rw: rw, maxBodySize: e.maxBodySize, code: http.StatusOK}
+	e.next.ServeHTTP(recorder, req)
+
+	if recorder.streaming {
+		// The response has already been forwarded to the client.
+		return
+	}
+
+	if recorder.code == http.StatusOK {
+		tag := rw.Header().Get("ETag")
+
+		// The body of a response to a HEAD request is empty, and cannot be used to compute the ETag.
+		if tag == "" && req.Method == http.MethodGet {
+			tag = computeETag(recorder.body.Bytes(), e.weak)
+			rw.Header().Set("ETag", tag)
+		}
+
+		if tag != "" && matchIfNoneMatch(req.Header.Get("If-None-Match"), tag) {
+			rw.Header().Del("Content-Length")
+			rw.Header().Del("Content-Type")
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	rw.WriteHeader(recorder.code)
+	if _, err := rw.Write(recorder.body.Bytes()); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), e.name, typeName)).Debugf("Error while writing response: %v", err)
+	}
+}
+
+// computeETag returns an entity tag derived from the SHA-256 hash of the body.
+func computeETag(body []byte, weak bool) string {
+	sum := sha256.Sum256(body)
+	tag := fmt.Sprintf(`"%x"`, sum[:16])
+	if weak {
+		return "W/" + tag
+	}
+	return tag
+}
+
+// matchIfNoneMatch tells whether the If-None-Match header value matches the entity tag,
+// using the weak comparison as required by RFC 7232.
+func matchIfNoneMatch(ifNoneMatch, tag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	opaque := strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == opaque {
+			return true
+		}
+	}
+
+	return false
+}
+
+// responseRecorder buffers the response up to maxBodySize,
+// after what it switches to streaming the response to the client.
+type responseRecorder struct {
+	rw          http.ResponseWriter
+	maxBodySize int64
+
+	code        int
+	wroteHeader bool
+	body        bytes.Buffer
+	streaming   bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.wroteHeader {
+		return
+	}
+
+	r.wroteHeader = true
+	r.code = code
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.streaming {
+		return r.rw.Write(p)
+	}
+
+	r.wroteHeader = true
+
+	if int64(r.body.Len()+len(p)) > r.maxBodySize {
+		if err := r.stream(); err != nil {
+			return 0, err
+		}
+		return r.rw.Write(p)
+	}
+
+	return r.body.Write(p)
+}
+
+// stream forwards the buffered response to the client, and disables the buffering.
+func (r *responseRecorder) stream() error {
+	if r.streaming {
+		return nil
+	}
+
+	r.streaming = true
+	r.rw.WriteHeader(r.code)
+
+	_, err := r.rw.Write(r.body.Bytes())
+	r.body.Reset()
+
+	return err
+}
+
+// Flush sends any buffered data to the client.
+// A flushed response cannot be tagged anymore.
+func (r *responseRecorder) Flush() {
+	if err := r.stream(); err != nil {
+		return
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := r.rw.(http.Hijacker); ok {
+		r.streaming = true
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.rw)
+}
@@ -0,0 +1,165 @@
+package etag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETag(t *testing.T) {
+	body := "hello world"
+	strongTag := computeETag([]byte(body), false)
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.ETag
+		method         string
+		ifNoneMatch    string
+		backendTag     string
+		backendStatus  int
+		expectedStatus int
+		expectedTag    string
+		expectedBody   string
+	}{
+		{
+			desc:           "computes a strong ETag",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+			expectedTag:    strongTag,
+			expectedBody:   body,
+		},
+		{
+			desc:           "computes a weak ETag",
+			config:         dynamic.ETag{Weak: true},
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+			expectedTag:    "W/" + strongTag,
+			expectedBody:   body,
+		},
+		{
+			desc:           "answers not modified",
+			method:         http.MethodGet,
+			ifNoneMatch:    `"foo", ` + strongTag,
+			expectedStatus: http.StatusNotModified,
+			expectedTag:    strongTag,
+		},
+		{
+			desc:           "answers not modified with weak comparison",
+			method:         http.MethodGet,
+			ifNoneMatch:    "W/" + strongTag,
+			expectedStatus: http.StatusNotModified,
+			expectedTag:    strongTag,
+		},
+		{
+			desc:           "answers not modified with wildcard",
+			method:         http.MethodGet,
+			ifNoneMatch:    "*",
+			expectedStatus: http.StatusNotModified,
+			expectedTag:    strongTag,
+		},
+		{
+			desc:           "does not match",
+			method:         http.MethodGet,
+			ifNoneMatch:    `"foo"`,
+			expectedStatus: http.StatusOK,
+			expectedTag:    strongTag,
+			expectedBody:   body,
+		},
+		{
+			desc:           "uses the ETag set by the backend",
+			method:         http.MethodGet,
+			backendTag:     `"bar"`,
+			ifNoneMatch:    `"bar"`,
+			expectedStatus: http.StatusNotModified,
+			expectedTag:    `"bar"`,
+		},
+		{
+			desc:           "does not compute the ETag of a HEAD response",
+			method:         http.MethodHead,
+			expectedStatus: http.StatusOK,
+			expectedBody:   body,
+		},
+		{
+			desc:           "ignores non OK responses",
+			method:         http.MethodGet,
+			ifNoneMatch:    "*",
+			backendStatus:  http.StatusNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   body,
+		},
+		{
+			desc:           "ignores unsafe methods",
+			method:         http.MethodPost,
+			ifNoneMatch:    "*",
+			expectedStatus: http.StatusOK,
+			expectedBody:   body,
+		},
+		{
+			desc:           "ignores the responses larger than the max body size",
+			config:         dynamic.ETag{MaxBodySize: 5},
+			method:         http.MethodGet,
+			ifNoneMatch:    "*",
+			expectedStatus: http.StatusOK,
+			expectedBody:   body,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.backendTag != "" {
+					rw.Header().Set("ETag", test.backendTag)
+				}
+				if test.backendStatus != 0 {
+					rw.WriteHeader(test.backendStatus)
+				}
+				_, _ = rw.Write([]byte(body[:6]))
+				_, _ = rw.Write([]byte(body[6:]))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "etag")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(test.method, "http://localhost", nil)
+			if test.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", test.ifNoneMatch)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedTag, recorder.Header().Get("ETag"))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestETag_flush(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte("foo"))
+		rw.(http.Flusher).Flush()
+		_, _ = rw.Write([]byte("bar"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.ETag{}, "etag")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("ETag"))
+	assert.True(t, recorder.Flushed)
+	assert.Equal(t, "foobar", strings.TrimSpace(recorder.Body.String()))
+}
@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -25,11 +26,12 @@ func GetSourceExtractor(ctx context.Context, sourceMatcher *dynamic.SourceCriter
 		if sourceMatcher.RequestHeaderName != "" && sourceMatcher.RequestHost {
 			return nil, errors.New("requestHost and RequestHeaderName are mutually exclusive")
 		}
+		if CountCriteria(sourceMatcher) > 1 {
+			return nil, errors.New("iPStrategy, RequestHeaderName, RequestHost, RequestJWTClaim and RequestClientCertCN are mutually exclusive")
+		}
 	}
 
-	if sourceMatcher == nil ||
-		sourceMatcher.IPStrategy == nil &&
-			sourceMatcher.RequestHeaderName == "" && !sourceMatcher.RequestHost {
+	if sourceMatcher == nil || CountCriteria(sourceMatcher) == 0 {
 		sourceMatcher = &dynamic.SourceCriterion{
 			IPStrategy: &dynamic.IPStrategy{},
 		}
@@ -58,5 +60,72 @@ func GetSourceExtractor(ctx context.Context, sourceMatcher *dynamic.SourceCriter
 		return utils.NewExtractor("request.host")
 	}
 
+	if sourceMatcher.RequestJWTClaim != "" {
+		logger.Debug("Using RequestJWTClaim")
+		claim := sourceMatcher.RequestJWTClaim
+		return utils.ExtractorFunc(func(req *http.Request) (string, int64, error) {
+			return getJWTClaim(req, claim), 1, nil
+		}), nil
+	}
+
+	if sourceMatcher.RequestClientCertCN {
+		logger.Debug("Using RequestClientCertCN")
+		return utils.ExtractorFunc(func(req *http.Request) (string, int64, error) {
+			// Only a certificate verified against the client CAs is considered, an unverified one could be forged.
+			if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+				return "", 1, nil
+			}
+			return req.TLS.VerifiedChains[0][0].Subject.CommonName, 1, nil
+		}), nil
+	}
+
 	return nil, errors.New("no SourceCriterion criterion defined")
 }
+
+// CountCriteria returns the number of criteria set in the given source criterion.
+func CountCriteria(sourceMatcher *dynamic.SourceCriterion) int {
+	var count int
+	for _, set := range []bool{
+		sourceMatcher.IPStrategy != nil,
+		sourceMatcher.RequestHeaderName != "",
+		sourceMatcher.RequestHost,
+		sourceMatcher.RequestJWTClaim != "",
+		sourceMatcher.RequestClientCertCN,
+	} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+type jwtClaimsKey struct{}
+
+// WithJWTClaims returns a shallow copy of the request holding the claims of its bearer token,
+// once the token has been verified by an authentication middleware.
+func WithJWTClaims(req *http.Request, claims map[string]interface{}) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), jwtClaimsKey{}, claims))
+}
+
+// getJWTClaim returns the value of the given claim of the verified bearer token of the request,
+// or an empty string if there is no such token or claim.
+// The bearer token itself is never read, as the claims of an unverified token could be forged.
+func getJWTClaim(req *http.Request, claim string) string {
+	claims, ok := req.Context().Value(jwtClaimsKey{}).(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	switch value := claims[claim].(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		return string(raw)
+	}
+}
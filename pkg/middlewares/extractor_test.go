@@ -0,0 +1,125 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSourceExtractor(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice","tenant":42}`))
+	token := "eyJhbGciOiJIUzI1NiJ9." + payload + ".c2lnbmF0dXJl"
+	claims := map[string]interface{}{"sub": "alice", "tenant": float64(42)}
+
+	testCases := []struct {
+		desc           string
+		criterion      *dynamic.SourceCriterion
+		authorization  string
+		claims         map[string]interface{}
+		commonName     string
+		unverified     bool
+		expectedSource string
+		expectedError  bool
+	}{
+		{
+			desc:           "default to the remote address",
+			expectedSource: "10.0.0.1",
+		},
+		{
+			desc:           "JWT claim",
+			criterion:      &dynamic.SourceCriterion{RequestJWTClaim: "sub"},
+			authorization:  "Bearer " + token,
+			claims:         claims,
+			expectedSource: "alice",
+		},
+		{
+			desc:           "non string JWT claim",
+			criterion:      &dynamic.SourceCriterion{RequestJWTClaim: "tenant"},
+			authorization:  "Bearer " + token,
+			claims:         claims,
+			expectedSource: "42",
+		},
+		{
+			desc:          "missing JWT claim",
+			criterion:     &dynamic.SourceCriterion{RequestJWTClaim: "email"},
+			authorization: "Bearer " + token,
+			claims:        claims,
+		},
+		{
+			desc:          "unverified bearer token",
+			criterion:     &dynamic.SourceCriterion{RequestJWTClaim: "sub"},
+			authorization: "Bearer " + token,
+		},
+		{
+			desc:          "no bearer token",
+			criterion:     &dynamic.SourceCriterion{RequestJWTClaim: "sub"},
+			authorization: "Basic dGVzdDp0ZXN0",
+		},
+		{
+			desc:           "client certificate common name",
+			criterion:      &dynamic.SourceCriterion{RequestClientCertCN: true},
+			commonName:     "client",
+			expectedSource: "client",
+		},
+		{
+			desc:       "unverified client certificate",
+			criterion:  &dynamic.SourceCriterion{RequestClientCertCN: true},
+			commonName: "client",
+			unverified: true,
+		},
+		{
+			desc:      "no client certificate",
+			criterion: &dynamic.SourceCriterion{RequestClientCertCN: true},
+		},
+		{
+			desc:          "mutually exclusive criteria",
+			criterion:     &dynamic.SourceCriterion{RequestJWTClaim: "sub", RequestClientCertCN: true},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			extractor, err := GetSourceExtractor(context.Background(), test.criterion)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "https://localhost", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			if test.claims != nil {
+				req = WithJWTClaims(req, test.claims)
+			}
+			if test.commonName != "" {
+				cert := &x509.Certificate{Subject: pkix.Name{CommonName: test.commonName}}
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+				if !test.unverified {
+					req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+				}
+			}
+
+			source, amount, err := extractor.Extract(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedSource, source)
+			assert.Equal(t, int64(1), amount)
+		})
+	}
+}
@@ -37,6 +37,18 @@ var xHeaders = []string{
 	xRealIP,
 }
 
+// IsXHeader returns whether the header is one of the X-Forwarded headers set by Traefik,
+// whose values sent by the clients are removed unless they come from a trusted IP.
+func IsXHeader(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	for _, h := range xHeaders {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
 // XForwarded is an HTTP handler wrapper that sets the X-Forwarded headers,
 // and other relevant headers for a reverse-proxy.
 // Unless insecure is set,
@@ -28,9 +28,7 @@ func New(ctx context.Context, next http.Handler, config dynamic.InFlightReq, nam
 	ctxLog := log.With(ctx, log.Str(log.MiddlewareName, name), log.Str(log.MiddlewareType, typeName))
 	log.FromContext(ctxLog).Debug("Creating middleware")
 
-	if config.SourceCriterion == nil ||
-		config.SourceCriterion.IPStrategy == nil &&
-			config.SourceCriterion.RequestHeaderName == "" && !config.SourceCriterion.RequestHost {
+	if config.SourceCriterion == nil || middlewares.CountCriteria(config.SourceCriterion) == 0 {
 		config.SourceCriterion = &dynamic.SourceCriterion{
 			RequestHost: true,
 		}
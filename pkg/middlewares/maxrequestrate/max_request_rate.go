@@ -0,0 +1,89 @@
+package maxrequestrate
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"golang.org/x/time/rate"
+)
+
+const (
+	typeName       = "MaxRequestRate"
+	nameEntrypoint = "maxrequestrate-entrypoint"
+)
+
+// Limiter holds the request rate of an entry point.
+// It is shared by the successive handlers of the entry point, so that the rate is kept across the configuration reloads.
+type Limiter struct {
+	limiter *rate.Limiter
+}
+
+// NewLimiter creates a new Limiter.
+func NewLimiter(conf static.MaxRequestRate) *Limiter {
+	burst := conf.Burst
+	if burst == 0 {
+		burst = conf.Average
+	}
+
+	return &Limiter{limiter: rate.NewLimiter(rate.Limit(conf.Average), int(burst))}
+}
+
+// maxRequestRate is a middleware that rejects the requests exceeding the maximum request rate of an entry point.
+type maxRequestRate struct {
+	next        http.Handler
+	limiter     *Limiter
+	shedCounter gokitmetrics.Counter
+}
+
+// NewEntryPointMiddleware creates a new maximum request rate middleware for an entry point.
+func NewEntryPointMiddleware(ctx context.Context, next http.Handler, limiter *Limiter, registry metrics.Registry, entryPointName string) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, nameEntrypoint, typeName)).Debug("Creating middleware")
+
+	return &maxRequestRate{
+		next:        next,
+		limiter:     limiter,
+		shedCounter: registry.EntryPointRateLimitedReqsCounter().With("entrypoint", entryPointName),
+	}
+}
+
+// WrapEntryPointHandler wraps the maximum request rate middleware in an alice.Constructor.
+func WrapEntryPointHandler(ctx context.Context, limiter *Limiter, registry metrics.Registry, entryPointName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewEntryPointMiddleware(ctx, next, limiter, registry, entryPointName), nil
+	}
+}
+
+func (m *maxRequestRate) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	res := m.limiter.limiter.Reserve()
+	if !res.OK() {
+		m.reject(rw, time.Second)
+		return
+	}
+
+	delay := res.Delay()
+	if delay > 0 {
+		// The request is rejected rather than delayed, its token is given back.
+		res.Cancel()
+		m.reject(rw, delay)
+		return
+	}
+
+	m.next.ServeHTTP(rw, req)
+}
+
+func (m *maxRequestRate) reject(rw http.ResponseWriter, retryAfter time.Duration) {
+	m.shedCounter.Add(1)
+
+	rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	rw.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable)))
+}
@@ -0,0 +1,56 @@
+package maxrequestrate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxRequestRate(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	limiter := NewLimiter(static.MaxRequestRate{Average: 1, Burst: 2})
+	handler := NewEntryPointMiddleware(context.Background(), next, limiter, metrics.NewVoidRegistry(), "web")
+
+	var codes []int
+	for i := 0; i < 3; i++ {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		codes = append(codes, rw.Code)
+
+		if rw.Code == http.StatusServiceUnavailable {
+			assert.Equal(t, "1", rw.Header().Get("Retry-After"))
+		}
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusServiceUnavailable}, codes)
+}
+
+func TestMaxRequestRate_sharedLimiter(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	// The burst defaults to the average.
+	limiter := NewLimiter(static.MaxRequestRate{Average: 1})
+
+	// The handlers rebuilt on a configuration reload share the limiter of the entry point.
+	before := NewEntryPointMiddleware(context.Background(), next, limiter, metrics.NewVoidRegistry(), "web")
+	after := NewEntryPointMiddleware(context.Background(), next, limiter, metrics.NewVoidRegistry(), "web")
+
+	rw := httptest.NewRecorder()
+	before.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	rw = httptest.NewRecorder()
+	after.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
 	"github.com/containous/traefik/v2/pkg/middlewares/retry"
 	traefiktls "github.com/containous/traefik/v2/pkg/tls"
 	gokitmetrics "github.com/go-kit/kit/metrics"
@@ -100,7 +101,13 @@ func (m *metricsMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 
 	m.next.ServeHTTP(recorder, req)
 
-	labels = append(labels, "code", strconv.Itoa(recorder.getCode()))
+	code := recorder.getCode()
+	if !recorder.written() && middlewares.ClientAborted(req) {
+		// The client went away before a response was sent.
+		code = middlewares.StatusClientClosedRequest
+	}
+
+	labels = append(labels, "code", strconv.Itoa(code))
 
 	histograms := m.reqDurationHistogram.With(labels...)
 	histograms.ObserveFromStart(start)
@@ -172,6 +179,7 @@ func getRequestTLSCipher(req *http.Request) string {
 
 type retryMetrics interface {
 	ServiceRetriesCounter() gokitmetrics.Counter
+	ServiceRetriesSucceededCounter() gokitmetrics.Counter
 }
 
 // NewRetryListener instantiates a MetricsRetryListener with the given retryMetrics.
@@ -190,3 +198,38 @@ type RetryListener struct {
 func (m *RetryListener) Retried(req *http.Request, attempt int) {
 	m.retryMetrics.ServiceRetriesCounter().With("service", m.serviceName).Add(1)
 }
+
+// Succeeded tracks the retried request which eventually succeeded in the RequestMetrics implementation.
+func (m *RetryListener) Succeeded(req *http.Request, attempts int) {
+	m.retryMetrics.ServiceRetriesSucceededCounter().With("service", m.serviceName).Add(1)
+}
+
+// NewCircuitBreakerListener instantiates a CircuitBreakerListener for the circuit breakers in front of a service.
+func NewCircuitBreakerListener(registry metrics.Registry, serviceName string) circuitbreaker.Listener {
+	return &CircuitBreakerListener{
+		transitionsCounter: registry.ServiceCircuitBreakerTransitionsCounter(),
+		stateGauge:         registry.ServiceCircuitBreakerStateGauge(),
+		serviceName:        serviceName,
+	}
+}
+
+// CircuitBreakerListener is an implementation of the circuit breaker Listener interface
+// which counts the state transitions, and reports the current state.
+type CircuitBreakerListener struct {
+	transitionsCounter gokitmetrics.Counter
+	stateGauge         gokitmetrics.Gauge
+	serviceName        string
+}
+
+// circuitBreakerStateValues are the values of the state gauge.
+var circuitBreakerStateValues = map[string]float64{
+	circuitbreaker.StateStandby:    0,
+	circuitbreaker.StateRecovering: 1,
+	circuitbreaker.StateTripped:    2,
+}
+
+// StateChanged tracks the state transition of the circuit breaker.
+func (l *CircuitBreakerListener) StateChanged(server, state string) {
+	l.transitionsCounter.With("service", l.serviceName, "server", server, "state", state).Add(1)
+	l.stateGauge.With("service", l.serviceName, "server", server).Set(circuitBreakerStateValues[state])
+}
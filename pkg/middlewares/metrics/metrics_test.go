@@ -1,13 +1,16 @@
 package metrics
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
 
+	"github.com/containous/traefik/v2/pkg/middlewares/retry"
 	"github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // CollectingCounter is a metrics.Counter implementation that enables access to the CounterValue and LastLabelValues.
@@ -45,19 +48,81 @@ func TestMetricsRetryListener(t *testing.T) {
 	}
 }
 
+func TestMetricsRetryListener_succeeded(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	retryMetrics := newCollectingRetryMetrics()
+	retryListener := NewRetryListener(retryMetrics, "serviceName")
+
+	successListener, ok := retryListener.(retry.SuccessListener)
+	require.True(t, ok)
+	successListener.Succeeded(req, 2)
+
+	assert.Equal(t, float64(1), retryMetrics.retriesSucceededCounter.CounterValue)
+	assert.Equal(t, []string{"service", "serviceName"}, retryMetrics.retriesSucceededCounter.LastLabelValues)
+	assert.Equal(t, float64(0), retryMetrics.retriesCounter.CounterValue)
+}
+
 // collectingRetryMetrics is an implementation of the retryMetrics interface that can be used inside tests to collect the times Add() was called.
 type collectingRetryMetrics struct {
-	retriesCounter *CollectingCounter
+	retriesCounter          *CollectingCounter
+	retriesSucceededCounter *CollectingCounter
 }
 
 func newCollectingRetryMetrics() *collectingRetryMetrics {
-	return &collectingRetryMetrics{retriesCounter: &CollectingCounter{}}
+	return &collectingRetryMetrics{
+		retriesCounter:          &CollectingCounter{},
+		retriesSucceededCounter: &CollectingCounter{},
+	}
 }
 
 func (m *collectingRetryMetrics) ServiceRetriesCounter() metrics.Counter {
 	return m.retriesCounter
 }
 
+func (m *collectingRetryMetrics) ServiceRetriesSucceededCounter() metrics.Counter {
+	return m.retriesSucceededCounter
+}
+
+func TestMetricsMiddleware_clientAborted(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		next         http.HandlerFunc
+		expectedCode string
+	}{
+		{
+			desc:         "client went away before the response",
+			next:         func(rw http.ResponseWriter, req *http.Request) {},
+			expectedCode: "499",
+		},
+		{
+			desc: "client went away during the response",
+			next: func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = rw.Write([]byte("partial"))
+			},
+			expectedCode: "200",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			registry := newServiceRegistry()
+			handler := NewServiceMiddleware(context.Background(), test.next, registry, "foo@file")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil).WithContext(ctx)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, float64(1), registry.reqs.CounterValue)
+			assert.Contains(t, registry.reqs.LastLabelValues, test.expectedCode)
+		})
+	}
+}
+
 type rwWithCloseNotify struct {
 	*httptest.ResponseRecorder
 }
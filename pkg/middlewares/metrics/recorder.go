@@ -10,6 +10,7 @@ type recorder interface {
 	http.ResponseWriter
 	http.Flusher
 	getCode() int
+	written() bool
 }
 
 func newResponseRecorder(rw http.ResponseWriter) recorder {
@@ -27,7 +28,8 @@ func newResponseRecorder(rw http.ResponseWriter) recorder {
 // later analysis.
 type responseRecorder struct {
 	http.ResponseWriter
-	statusCode int
+	statusCode  int
+	wroteHeader bool
 }
 
 type responseRecorderWithCloseNotify struct {
@@ -44,14 +46,27 @@ func (r *responseRecorder) getCode() int {
 	return r.statusCode
 }
 
+// written reports whether the response status was sent.
+func (r *responseRecorder) written() bool {
+	return r.wroteHeader
+}
+
 // WriteHeader captures the status code for later retrieval.
 func (r *responseRecorder) WriteHeader(status int) {
 	r.ResponseWriter.WriteHeader(status)
 	r.statusCode = status
+	r.wroteHeader = true
+}
+
+// Write marks the response status as sent, the first write implying a 200 status code.
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
 }
 
 // Hijack hijacks the connection.
 func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.wroteHeader = true
 	return r.ResponseWriter.(http.Hijacker).Hijack()
 }
 
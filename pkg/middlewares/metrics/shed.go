@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+// forwardedKey is the context key telling whether a throttling middleware forwarded the request, unique per middleware instance.
+type forwardedKey struct {
+	middlewareType string
+}
+
+// WrapShedHandler counts the requests shed by the throttling middleware built by the constructor,
+// i.e. the requests it answers by itself instead of forwarding them to the service.
+func WrapShedHandler(registry metrics.Registry, serviceName, middlewareType string, constructor alice.Constructor) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		key := &forwardedKey{middlewareType: middlewareType}
+
+		handler, err := constructor(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if forwarded, ok := req.Context().Value(key).(*bool); ok {
+				*forwarded = true
+			}
+			next.ServeHTTP(rw, req)
+		}))
+		if err != nil || handler == nil {
+			return handler, err
+		}
+
+		return &shedCounter{
+			next:    handler,
+			key:     key,
+			counter: registry.ServiceShedReqsCounter().With("service", serviceName, "type", middlewareType),
+		}, nil
+	}
+}
+
+type shedCounter struct {
+	next    http.Handler
+	key     *forwardedKey
+	counter gokitmetrics.Counter
+}
+
+func (s *shedCounter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	forwarded := false
+	s.next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), s.key, &forwarded)))
+
+	if !forwarded {
+		s.counter.Add(1)
+	}
+}
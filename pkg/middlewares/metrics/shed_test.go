@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type serviceRegistry struct {
+	metrics.Registry
+	reqs        *CollectingCounter
+	shedReqs    *CollectingCounter
+	transitions *CollectingCounter
+	states      *collectingGauge
+}
+
+func newServiceRegistry() *serviceRegistry {
+	return &serviceRegistry{
+		Registry:    metrics.NewVoidRegistry(),
+		reqs:        &CollectingCounter{},
+		shedReqs:    &CollectingCounter{},
+		transitions: &CollectingCounter{},
+		states:      &collectingGauge{},
+	}
+}
+
+func (r *serviceRegistry) ServiceReqsCounter() gokitmetrics.Counter {
+	return r.reqs
+}
+
+func (r *serviceRegistry) ServiceShedReqsCounter() gokitmetrics.Counter {
+	return r.shedReqs
+}
+
+func (r *serviceRegistry) ServiceCircuitBreakerTransitionsCounter() gokitmetrics.Counter {
+	return r.transitions
+}
+
+func (r *serviceRegistry) ServiceCircuitBreakerStateGauge() gokitmetrics.Gauge {
+	return r.states
+}
+
+// collectingGauge is a metrics.Gauge implementation that enables access to the last value and label values.
+type collectingGauge struct {
+	gaugeValue      float64
+	lastLabelValues []string
+}
+
+func (g *collectingGauge) With(labelValues ...string) gokitmetrics.Gauge {
+	g.lastLabelValues = labelValues
+	return g
+}
+
+func (g *collectingGauge) Set(value float64) {
+	g.gaugeValue = value
+}
+
+func (g *collectingGauge) Add(delta float64) {
+	g.gaugeValue += delta
+}
+
+func TestWrapShedHandler(t *testing.T) {
+	registry := newServiceRegistry()
+
+	// Forwards the requests with the X-Allowed header only.
+	throttling := func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("X-Allowed") == "" {
+				rw.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(rw, req)
+		}), nil
+	}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	handler, err := WrapShedHandler(registry, "foo@file", "ratelimit", throttling)(next)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// A request forwarded to the service is not shed, whatever its response.
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Allowed", "true")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, float64(2), registry.shedReqs.CounterValue)
+	assert.Equal(t, []string{"service", "foo@file", "type", "ratelimit"}, registry.shedReqs.LastLabelValues)
+}
+
+func TestCircuitBreakerListener(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		server         string
+		state          string
+		expectedLabels []string
+		expectedValue  float64
+	}{
+		{
+			desc:           "service tripped",
+			state:          circuitbreaker.StateTripped,
+			expectedLabels: []string{"service", "foo@file", "server", "", "state", "tripped"},
+			expectedValue:  2,
+		},
+		{
+			desc:           "server recovering",
+			server:         "http://10.0.0.1:80",
+			state:          circuitbreaker.StateRecovering,
+			expectedLabels: []string{"service", "foo@file", "server", "http://10.0.0.1:80", "state", "recovering"},
+			expectedValue:  1,
+		},
+		{
+			desc:           "server standby",
+			server:         "http://10.0.0.1:80",
+			state:          circuitbreaker.StateStandby,
+			expectedLabels: []string{"service", "foo@file", "server", "http://10.0.0.1:80", "state", "standby"},
+			expectedValue:  0,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			registry := newServiceRegistry()
+			registry.states.gaugeValue = -1
+
+			listener := NewCircuitBreakerListener(registry, "foo@file")
+			listener.StateChanged(test.server, test.state)
+
+			assert.Equal(t, float64(1), registry.transitions.CounterValue)
+			assert.Equal(t, test.expectedLabels, registry.transitions.LastLabelValues)
+			assert.Equal(t, test.expectedValue, registry.states.gaugeValue)
+			assert.Equal(t, test.expectedLabels[:4], registry.states.lastLabelValues)
+		})
+	}
+}
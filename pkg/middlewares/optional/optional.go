@@ -0,0 +1,163 @@
+package optional
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+const (
+	typeName = "Optional"
+)
+
+type stateKey struct{}
+
+// state tracks whether a request went through the optional middleware, or was answered by it.
+type state struct {
+	forwarded bool
+	skipped   bool
+}
+
+// Wrap makes the middleware built by the constructor optional:
+// when it answers a request with a 500, 502, 503 or 504 status code by itself, i.e. without forwarding the request,
+// its external dependency is considered unavailable, and the request is forwarded to the next handler as if the middleware was not there.
+// counter, if not nil, counts the requests skipping the middleware.
+func Wrap(ctx context.Context, name string, constructor alice.Constructor, counter gokitmetrics.Counter) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		o := &optional{
+			next:    next,
+			name:    name,
+			key:     &stateKey{},
+			counter: counter,
+		}
+
+		handler, err := constructor(http.HandlerFunc(o.forward))
+		if err != nil || handler == nil {
+			return handler, err
+		}
+
+		o.handler = handler
+
+		log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("The middleware is skipped when its dependency is unavailable")
+
+		return o, nil
+	}
+}
+
+type optional struct {
+	handler http.Handler
+	next    http.Handler
+	name    string
+	// key is specific to each optional middleware, so that the nested ones do not share their state.
+	key     *stateKey
+	counter gokitmetrics.Counter
+}
+
+func (o *optional) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	st := &state{}
+	header := rw.Header().Clone()
+
+	o.handler.ServeHTTP(&responseWriter{rw: rw, state: st}, req.WithContext(context.WithValue(req.Context(), o.key, st)))
+
+	if !st.skipped {
+		return
+	}
+
+	// The headers set by the middleware before failing are discarded.
+	current := rw.Header()
+	for name := range current {
+		delete(current, name)
+	}
+	for name, values := range header {
+		current[name] = values
+	}
+
+	log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, typeName)).Warn("The dependency of the middleware is unavailable, skipping it")
+
+	if o.counter != nil {
+		o.counter.Add(1)
+	}
+
+	o.next.ServeHTTP(rw, req)
+}
+
+// forward is the next handler of the optional middleware.
+func (o *optional) forward(rw http.ResponseWriter, req *http.Request) {
+	if st, ok := req.Context().Value(o.key).(*state); ok {
+		st.forwarded = true
+	}
+
+	o.next.ServeHTTP(rw, req)
+}
+
+// responseWriter discards the response of the middleware when it reports its dependency as unavailable.
+type responseWriter struct {
+	rw          http.ResponseWriter
+	state       *state
+	wroteHeader bool
+}
+
+func (r *responseWriter) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+
+	if !r.state.forwarded && isUnavailable(code) {
+		r.state.skipped = true
+		return
+	}
+
+	r.rw.WriteHeader(code)
+}
+
+func (r *responseWriter) Write(p []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.state.skipped {
+		return len(p), nil
+	}
+
+	return r.rw.Write(p)
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseWriter) Flush() {
+	if r.state.skipped {
+		return
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := r.rw.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.rw)
+}
+
+func isUnavailable(code int) bool {
+	switch code {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,121 @@
+package optional
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptional(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		middleware      func(next http.Handler) http.Handler
+		expectedStatus  int
+		expectedBody    string
+		expectedSkipped float64
+	}{
+		{
+			desc: "dependency unavailable",
+			middleware: func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					rw.Header().Set("X-Middleware", "failed")
+					rw.WriteHeader(http.StatusServiceUnavailable)
+					_, _ = rw.Write([]byte("unavailable"))
+				})
+			},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "service",
+			expectedSkipped: 1,
+		},
+		{
+			desc: "internal error written without explicit status",
+			middleware: func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					http.Error(rw, "error", http.StatusInternalServerError)
+				})
+			},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "service",
+			expectedSkipped: 1,
+		},
+		{
+			desc: "request denied",
+			middleware: func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					rw.WriteHeader(http.StatusUnauthorized)
+					_, _ = rw.Write([]byte("denied"))
+				})
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "denied",
+		},
+		{
+			desc: "request forwarded",
+			middleware: func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					rw.Header().Set("X-Middleware", "passed")
+					next.ServeHTTP(rw, req)
+				})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "service",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = rw.Write([]byte("service"))
+			})
+
+			counter := &testhelpers.CollectingCounter{}
+			constructor := func(next http.Handler) (http.Handler, error) {
+				return test.middleware(next), nil
+			}
+
+			handler, err := Wrap(context.Background(), "foo", constructor, counter)(next)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+			assert.Equal(t, test.expectedBody, rw.Body.String())
+			assert.Equal(t, test.expectedSkipped, counter.CounterValue)
+
+			if test.expectedSkipped > 0 {
+				assert.Empty(t, rw.Header().Get("X-Middleware"))
+				assert.Empty(t, rw.Header().Get("X-Content-Type-Options"))
+			}
+		})
+	}
+}
+
+func TestOptional_serviceUnavailable(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	constructor := func(next http.Handler) (http.Handler, error) {
+		return next, nil
+	}
+
+	counter := &testhelpers.CollectingCounter{}
+	handler, err := Wrap(context.Background(), "foo", constructor, counter)(next)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+	// The errors of the next handlers are not the ones of the middleware.
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	assert.Equal(t, float64(0), counter.CounterValue)
+}
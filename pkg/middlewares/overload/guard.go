@@ -0,0 +1,113 @@
+package overload
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+)
+
+// resumeRatio is the fraction of the memory limit below which the load stops being shed,
+// so that the overload state does not flap around the limit.
+const resumeRatio = 0.9
+
+// Guard tracks the memory usage of Traefik against a soft limit,
+// and tells whether the load has to be shed.
+type Guard struct {
+	limit       uint64
+	interval    time.Duration
+	memoryUsage func() uint64
+	registry    metrics.Registry
+
+	// overloaded is accessed atomically, as it is read for every request.
+	overloaded int32
+
+	mu sync.Mutex
+	// available is closed while Traefik is not overloaded.
+	available chan struct{}
+}
+
+// NewGuard creates a Guard.
+func NewGuard(config *static.Overload, registry metrics.Registry) *Guard {
+	available := make(chan struct{})
+	close(available)
+
+	return &Guard{
+		limit:       uint64(config.MemoryLimit),
+		interval:    time.Duration(config.CheckInterval),
+		memoryUsage: memoryUsage,
+		registry:    registry,
+		available:   available,
+	}
+}
+
+// Overloaded returns whether the load has to be shed.
+func (g *Guard) Overloaded() bool {
+	return atomic.LoadInt32(&g.overloaded) == 1
+}
+
+// Wait blocks while Traefik is overloaded.
+func (g *Guard) Wait(ctx context.Context) {
+	g.mu.Lock()
+	available := g.available
+	g.mu.Unlock()
+
+	select {
+	case <-available:
+	case <-ctx.Done():
+	}
+}
+
+// Run checks periodically the memory usage, until the context is done.
+func (g *Guard) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			g.check(ctx)
+		}
+	}
+}
+
+func (g *Guard) check(ctx context.Context) {
+	usage := g.memoryUsage()
+	g.registry.OverloadMemoryGauge().Set(float64(usage))
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	overloaded := g.Overloaded()
+
+	switch {
+	case !overloaded && usage > g.limit:
+		log.FromContext(ctx).Warnf("Memory usage (%d bytes) exceeds the limit (%d bytes), shedding the load", usage, g.limit)
+
+		atomic.StoreInt32(&g.overloaded, 1)
+		g.available = make(chan struct{})
+		g.registry.OverloadedGauge().Set(1)
+
+	case overloaded && float64(usage) < resumeRatio*float64(g.limit):
+		log.FromContext(ctx).Infof("Memory usage (%d bytes) is back under the limit (%d bytes), resuming", usage, g.limit)
+
+		atomic.StoreInt32(&g.overloaded, 0)
+		close(g.available)
+		g.registry.OverloadedGauge().Set(0)
+	}
+}
+
+// memoryUsage returns the memory obtained from the OS by the Go runtime, which has not been released yet.
+func memoryUsage() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return stats.Sys - stats.HeapReleased
+}
@@ -0,0 +1,56 @@
+package overload
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+const (
+	typeName       = "Overload"
+	nameEntrypoint = "overload-entrypoint"
+)
+
+// overload is a middleware that rejects the requests while Traefik is overloaded,
+// and closes their connection to release its resources.
+type overload struct {
+	next        http.Handler
+	guard       *Guard
+	shedCounter gokitmetrics.Counter
+}
+
+// NewEntryPointMiddleware creates a new overload middleware for an entry point.
+func NewEntryPointMiddleware(ctx context.Context, next http.Handler, guard *Guard, registry metrics.Registry, entryPointName string) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, nameEntrypoint, typeName)).Debug("Creating middleware")
+
+	return &overload{
+		next:        next,
+		guard:       guard,
+		shedCounter: registry.OverloadShedReqsCounter().With("entrypoint", entryPointName),
+	}
+}
+
+// WrapEntryPointHandler wraps the overload middleware in an alice.Constructor.
+func WrapEntryPointHandler(ctx context.Context, guard *Guard, registry metrics.Registry, entryPointName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewEntryPointMiddleware(ctx, next, guard, registry, entryPointName), nil
+	}
+}
+
+func (o *overload) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !o.guard.Overloaded() {
+		o.next.ServeHTTP(rw, req)
+		return
+	}
+
+	o.shedCounter.Add(1)
+
+	rw.Header().Set("Connection", "close")
+	rw.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable)))
+}
@@ -0,0 +1,91 @@
+package overload
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuard_check(t *testing.T) {
+	guard := NewGuard(&static.Overload{
+		MemoryLimit:   100,
+		CheckInterval: types.Duration(time.Second),
+	}, metrics.NewVoidRegistry())
+
+	testCases := []struct {
+		usage              uint64
+		expectedOverloaded bool
+	}{
+		{usage: 50, expectedOverloaded: false},
+		{usage: 101, expectedOverloaded: true},
+		// The load is shed until the usage gets under the resume ratio.
+		{usage: 95, expectedOverloaded: true},
+		{usage: 89, expectedOverloaded: false},
+		{usage: 95, expectedOverloaded: false},
+	}
+
+	for _, test := range testCases {
+		test := test
+		guard.memoryUsage = func() uint64 { return test.usage }
+		guard.check(context.Background())
+
+		assert.Equal(t, test.expectedOverloaded, guard.Overloaded(), "usage: %d", test.usage)
+	}
+}
+
+func TestGuard_Wait(t *testing.T) {
+	guard := NewGuard(&static.Overload{MemoryLimit: 100}, metrics.NewVoidRegistry())
+
+	usage := uint64(200)
+	guard.memoryUsage = func() uint64 { return usage }
+	guard.check(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	guard.Wait(ctx)
+	assert.Error(t, ctx.Err(), "Wait must block while overloaded")
+
+	usage = 0
+	guard.check(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		guard.Wait(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait must not block once the overload is over")
+	}
+}
+
+func TestOverload(t *testing.T) {
+	guard := NewGuard(&static.Overload{MemoryLimit: 100}, metrics.NewVoidRegistry())
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	handler := NewEntryPointMiddleware(context.Background(), next, guard, metrics.NewVoidRegistry(), "web")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	guard.memoryUsage = func() uint64 { return 200 }
+	guard.check(context.Background())
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	assert.Equal(t, "close", rw.Header().Get("Connection"))
+}
@@ -0,0 +1,223 @@
+package passtlsclientcert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	_ "crypto/sha512" // registers the SHA-384 and SHA-512 hash functions.
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+)
+
+const defaultJWTHeaderName = "X-Forwarded-Tls-Client-Cert-Jwt"
+
+// certificateFields are the fields of a client certificate which can be used as JWT claims.
+var certificateFields = map[string]func(cert *x509.Certificate) interface{}{
+	"subject":                    func(cert *x509.Certificate) interface{} { return cert.Subject.String() },
+	"subject.commonname":         func(cert *x509.Certificate) interface{} { return cert.Subject.CommonName },
+	"subject.organization":       func(cert *x509.Certificate) interface{} { return cert.Subject.Organization },
+	"subject.organizationalunit": func(cert *x509.Certificate) interface{} { return cert.Subject.OrganizationalUnit },
+	"subject.country":            func(cert *x509.Certificate) interface{} { return cert.Subject.Country },
+	"subject.serialnumber":       func(cert *x509.Certificate) interface{} { return cert.Subject.SerialNumber },
+	"issuer":                     func(cert *x509.Certificate) interface{} { return cert.Issuer.String() },
+	"issuer.commonname":          func(cert *x509.Certificate) interface{} { return cert.Issuer.CommonName },
+	"serialnumber":               func(cert *x509.Certificate) interface{} { return cert.SerialNumber.String() },
+	"sans":                       func(cert *x509.Certificate) interface{} { return getSANs(cert) },
+	"notbefore":                  func(cert *x509.Certificate) interface{} { return cert.NotBefore.Unix() },
+	"notafter":                   func(cert *x509.Certificate) interface{} { return cert.NotAfter.Unix() },
+	"fingerprint": func(cert *x509.Certificate) interface{} {
+		sum := sha256.Sum256(cert.Raw)
+		return hex.EncodeToString(sum[:])
+	},
+}
+
+// jwtSigner issues the JWTs conveying the identity of the client certificates.
+type jwtSigner struct {
+	key        crypto.Signer
+	algorithm  string
+	hash       crypto.Hash
+	headerName string
+	issuer     string
+	audience   string
+	expiration time.Duration
+	claims     map[string]string
+}
+
+func newJWTSigner(config *dynamic.TLSClientCertificateJWT) (*jwtSigner, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	key, err := loadSigningKey(config.SigningKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &jwtSigner{
+		key:        key,
+		headerName: config.HeaderName,
+		issuer:     config.Issuer,
+		audience:   config.Audience,
+		expiration: time.Duration(config.Expiration),
+		claims:     make(map[string]string),
+	}
+
+	if signer.headerName == "" {
+		signer.headerName = defaultJWTHeaderName
+	}
+
+	if signer.expiration <= 0 {
+		signer.expiration = time.Minute
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signer.algorithm, signer.hash = "RS256", crypto.SHA256
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			signer.algorithm, signer.hash = "ES256", crypto.SHA256
+		case elliptic.P384():
+			signer.algorithm, signer.hash = "ES384", crypto.SHA384
+		case elliptic.P521():
+			signer.algorithm, signer.hash = "ES512", crypto.SHA512
+		default:
+			return nil, errors.New("unsupported elliptic curve for the signing key")
+		}
+	}
+
+	for claim, field := range config.Claims {
+		field = strings.ToLower(field)
+		if _, ok := certificateFields[field]; !ok {
+			return nil, fmt.Errorf("unknown certificate field %q for claim %q", field, claim)
+		}
+		signer.claims[claim] = field
+	}
+
+	return signer, nil
+}
+
+// loadSigningKey reads a PEM encoded RSA or ECDSA private key.
+func loadSigningKey(path string) (crypto.Signer, error) {
+	if path == "" {
+		return nil, errors.New("the signing key file is required")
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in %s", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T", key)
+	}
+}
+
+// sign returns a JWT holding the identity of the client certificate.
+func (s *jwtSigner) sign(cert *x509.Certificate, now time.Time) (string, error) {
+	claims := map[string]interface{}{
+		"sub": cert.Subject.String(),
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"exp": now.Add(s.expiration).Unix(),
+		// The certificate thumbprint confirmation method, cf https://tools.ietf.org/html/rfc8705#section-3.1
+		"cnf": map[string]string{"x5t#S256": thumbprint(cert)},
+	}
+
+	if s.issuer != "" {
+		claims["iss"] = s.issuer
+	}
+
+	if s.audience != "" {
+		claims["aud"] = s.audience
+	}
+
+	for claim, field := range s.claims {
+		claims[claim] = certificateFields[field](cert)
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": s.algorithm, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	signature, err := s.signature([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (s *jwtSigner) signature(input []byte) ([]byte, error) {
+	h := s.hash.New()
+	_, _ = h.Write(input)
+	digest := h.Sum(nil)
+
+	key, ok := s.key.(*ecdsa.PrivateKey)
+	if !ok {
+		return s.key.Sign(rand.Reader, digest, s.hash)
+	}
+
+	// The ECDSA signatures of a JWS are the concatenation of the fixed size R and S values.
+	r, sig, err := ecdsa.Sign(rand.Reader, key, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	size := (key.Curve.Params().BitSize + 7) / 8
+	signature := make([]byte, 2*size)
+	rBytes, sBytes := r.Bytes(), sig.Bytes()
+	copy(signature[size-len(rBytes):size], rBytes)
+	copy(signature[2*size-len(sBytes):], sBytes)
+
+	return signature, nil
+}
+
+func thumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,182 @@
+package passtlsclientcert
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	ptypes "github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassTLSClientCert_JWT(t *testing.T) {
+	dir, err := ioutil.TempDir("", "passtlsclientcert")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	ecKeyFile := writePEM(t, dir, "ec.pem", "EC PRIVATE KEY", ecDER)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaDER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+	rsaKeyFile := writePEM(t, dir, "rsa.pem", "PRIVATE KEY", rsaDER)
+
+	cert := getCertificate(minimalCheeseCrt)
+
+	testCases := []struct {
+		desc           string
+		keyFile        string
+		publicKey      crypto.PublicKey
+		verified       bool
+		expectedHeader bool
+	}{
+		{
+			desc:           "ECDSA signed JWT",
+			keyFile:        ecKeyFile,
+			publicKey:      &ecKey.PublicKey,
+			verified:       true,
+			expectedHeader: true,
+		},
+		{
+			desc:           "RSA signed JWT",
+			keyFile:        rsaKeyFile,
+			publicKey:      &rsaKey.PublicKey,
+			verified:       true,
+			expectedHeader: true,
+		},
+		{
+			desc:    "certificate not verified",
+			keyFile: ecKeyFile,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			config := dynamic.PassTLSClientCert{
+				JWT: &dynamic.TLSClientCertificateJWT{
+					SigningKeyFile: test.keyFile,
+					Issuer:         "traefik",
+					Audience:       "backend",
+					Expiration:     ptypes.Duration(time.Minute),
+					Claims:         map[string]string{"cn": "Subject.CommonName", "sn": "serialNumber"},
+				},
+			}
+
+			handler, err := New(context.Background(), next, config, "foo")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://example.com/foo", nil)
+			req.Header.Set(defaultJWTHeaderName, "spoofed")
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+			if test.verified {
+				req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			token := req.Header.Get(defaultJWTHeaderName)
+			if !test.expectedHeader {
+				assert.Empty(t, token)
+				return
+			}
+
+			parts := strings.Split(token, ".")
+			require.Len(t, parts, 3)
+
+			signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+			require.NoError(t, err)
+
+			digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			switch key := test.publicKey.(type) {
+			case *ecdsa.PublicKey:
+				require.Len(t, signature, 64)
+				r := new(big.Int).SetBytes(signature[:32])
+				s := new(big.Int).SetBytes(signature[32:])
+				assert.True(t, ecdsa.Verify(key, digest[:], r, s))
+			case *rsa.PublicKey:
+				assert.NoError(t, rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature))
+			}
+
+			payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+			require.NoError(t, err)
+
+			var claims map[string]interface{}
+			require.NoError(t, json.Unmarshal(payload, &claims))
+
+			assert.Equal(t, cert.Subject.String(), claims["sub"])
+			assert.Equal(t, "traefik", claims["iss"])
+			assert.Equal(t, "backend", claims["aud"])
+			assert.Equal(t, cert.Subject.CommonName, claims["cn"])
+			assert.Equal(t, cert.SerialNumber.String(), claims["sn"])
+			assert.Equal(t, 60.0, claims["exp"].(float64)-claims["iat"].(float64))
+			assert.Equal(t, map[string]interface{}{"x5t#S256": thumbprint(cert)}, claims["cnf"])
+		})
+	}
+}
+
+func TestPassTLSClientCert_JWTInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.TLSClientCertificateJWT
+	}{
+		{
+			desc:   "missing signing key",
+			config: dynamic.TLSClientCertificateJWT{},
+		},
+		{
+			desc:   "invalid signing key file",
+			config: dynamic.TLSClientCertificateJWT{SigningKeyFile: "./missing.pem"},
+		},
+		{
+			desc:   "invalid signing key",
+			config: dynamic.TLSClientCertificateJWT{SigningKeyFile: "./pass_tls_client_cert.go"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), next, dynamic.PassTLSClientCert{JWT: &test.config}, "foo")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	require.NoError(t, err)
+
+	return path
+}
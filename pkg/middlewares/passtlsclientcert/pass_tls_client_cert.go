@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
@@ -93,17 +94,24 @@ type passTLSClientCert struct {
 	name string
 	pem  bool                      // pass the sanitized pem to the backend in a specific header
 	info *tlsClientCertificateInfo // pass selected information from the client certificate
+	jwt  *jwtSigner                // pass the identity of the verified client certificate in a signed JWT
 }
 
 // New constructs a new PassTLSClientCert instance from supplied frontend header struct.
 func New(ctx context.Context, next http.Handler, config dynamic.PassTLSClientCert, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
+	signer, err := newJWTSigner(config.JWT)
+	if err != nil {
+		return nil, err
+	}
+
 	return &passTLSClientCert{
 		next: next,
 		name: name,
 		pem:  config.PEM,
 		info: newTLSClientCertificateInfo(config.Info),
+		jwt:  signer,
 	}, nil
 }
 
@@ -132,6 +140,22 @@ func (p *passTLSClientCert) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 		}
 	}
 
+	if p.jwt != nil {
+		// The header cannot be set by the client.
+		req.Header.Del(p.jwt.headerName)
+
+		if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 {
+			token, err := p.jwt.sign(req.TLS.VerifiedChains[0][0], time.Now())
+			if err != nil {
+				logger.Errorf("Unable to sign the client certificate JWT: %v", err)
+			} else {
+				req.Header.Set(p.jwt.headerName, token)
+			}
+		} else {
+			logger.Debug("Tried to sign a JWT on a request without a verified client certificate")
+		}
+	}
+
 	p.next.ServeHTTP(rw, req)
 }
 
@@ -2,9 +2,15 @@
 package ratelimiter
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
@@ -34,17 +40,36 @@ type rateLimiter struct {
 	sourceMatcher utils.SourceExtractor
 	next          http.Handler
 
+	costRules           []costRule
+	costResponseHeader  string
+	costRemainingHeader string
+
 	buckets *ttlmap.TtlMap // actual buckets, keyed by source.
 }
 
+// costRule is the number of tokens charged for the requests matching the methods and the path pattern.
+type costRule struct {
+	methods map[string]struct{}
+	path    *regexp.Regexp
+	cost    int64
+}
+
+func (c costRule) match(req *http.Request) bool {
+	if len(c.methods) > 0 {
+		if _, ok := c.methods[req.Method]; !ok {
+			return false
+		}
+	}
+
+	return c.path == nil || c.path.MatchString(req.URL.Path)
+}
+
 // New returns a rate limiter middleware.
 func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name string) (http.Handler, error) {
 	ctxLog := log.With(ctx, log.Str(log.MiddlewareName, name), log.Str(log.MiddlewareType, typeName))
 	log.FromContext(ctxLog).Debug("Creating middleware")
 
-	if config.SourceCriterion == nil ||
-		config.SourceCriterion.IPStrategy == nil &&
-			config.SourceCriterion.RequestHeaderName == "" && !config.SourceCriterion.RequestHost {
+	if config.SourceCriterion == nil || middlewares.CountCriteria(config.SourceCriterion) == 0 {
 		config.SourceCriterion = &dynamic.SourceCriterion{
 			IPStrategy: &dynamic.IPStrategy{},
 		}
@@ -86,7 +111,7 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		}
 	}
 
-	return &rateLimiter{
+	rl := &rateLimiter{
 		name:          name,
 		rate:          rate.Limit(rtl),
 		burst:         burst,
@@ -94,7 +119,38 @@ func New(ctx context.Context, next http.Handler, config dynamic.RateLimit, name
 		next:          next,
 		sourceMatcher: sourceMatcher,
 		buckets:       buckets,
-	}, nil
+	}
+
+	if config.Cost != nil {
+		rl.costResponseHeader = config.Cost.ResponseHeader
+		rl.costRemainingHeader = config.Cost.RemainingHeader
+
+		for _, rule := range config.Cost.Rules {
+			if rule.Cost < 0 || rule.Cost > burst {
+				return nil, fmt.Errorf("invalid cost %d: it must be between 0 and the burst (%d)", rule.Cost, burst)
+			}
+
+			cr := costRule{cost: rule.Cost}
+
+			if len(rule.Methods) > 0 {
+				cr.methods = make(map[string]struct{})
+				for _, method := range rule.Methods {
+					cr.methods[strings.ToUpper(method)] = struct{}{}
+				}
+			}
+
+			if rule.PathRegex != "" {
+				cr.path, err = regexp.Compile(rule.PathRegex)
+				if err != nil {
+					return nil, fmt.Errorf("error compiling path pattern %q: %w", rule.PathRegex, err)
+				}
+			}
+
+			rl.costRules = append(rl.costRules, cr)
+		}
+	}
+
+	return rl, nil
 }
 
 func (rl *rateLimiter) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -116,11 +172,11 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		logger.Infof("ignoring token bucket amount > 1: %d", amount)
 	}
 
-	var bucket *rate.Limiter
+	var bucket *tokenBucket
 	if rlSource, exists := rl.buckets.Get(source); exists {
-		bucket = rlSource.(*rate.Limiter)
+		bucket = rlSource.(*tokenBucket)
 	} else {
-		bucket = rate.NewLimiter(rl.rate, int(rl.burst))
+		bucket = &tokenBucket{limiter: rate.NewLimiter(rl.rate, int(rl.burst))}
 		if err := rl.buckets.Set(source, bucket, int(rl.maxDelay)*10+1); err != nil {
 			logger.Errorf("could not insert bucket: %v", err)
 			http.Error(w, "could not insert bucket", http.StatusInternalServerError)
@@ -128,7 +184,7 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	res := bucket.Reserve()
+	res := bucket.reserve(int(rl.cost(r)))
 	if !res.OK() {
 		http.Error(w, "No bursty traffic allowed", http.StatusTooManyRequests)
 		return
@@ -136,13 +192,88 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	delay := res.Delay()
 	if delay > rl.maxDelay {
-		res.Cancel()
+		bucket.cancel(res)
 		rl.serveDelayError(ctx, w, r, delay)
 		return
 	}
 
 	time.Sleep(delay)
-	rl.next.ServeHTTP(w, r)
+
+	if rl.costResponseHeader == "" && rl.costRemainingHeader == "" {
+		rl.next.ServeHTTP(w, r)
+		return
+	}
+
+	rw := &responseWriter{
+		ResponseWriter:  w,
+		costHeader:      rl.costResponseHeader,
+		remainingHeader: rl.costRemainingHeader,
+	}
+	rl.next.ServeHTTP(rw, r)
+	rw.readHeaders()
+
+	if rl.costResponseHeader != "" {
+		rl.chargeResponseCost(logger, bucket, rw.cost)
+	}
+
+	if rl.costRemainingHeader != "" {
+		rl.applyRemaining(logger, bucket, rw.remaining)
+	}
+}
+
+// cost returns the number of tokens charged for the request, as defined by the first matching cost rule.
+func (rl *rateLimiter) cost(r *http.Request) int64 {
+	for _, rule := range rl.costRules {
+		if rule.match(r) {
+			return rule.cost
+		}
+	}
+	return 1
+}
+
+// chargeResponseCost consumes the additional cost reported by the service from the bucket.
+// The tokens are reserved without waiting, so that the subsequent requests of the source pay for it.
+func (rl *rateLimiter) chargeResponseCost(logger log.Logger, bucket *tokenBucket, value string) {
+	if value == "" {
+		return
+	}
+
+	cost, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || cost < 0 {
+		logger.Debugf("invalid cost %q in the %s response header", value, rl.costResponseHeader)
+		return
+	}
+
+	// The limiter cannot reserve more tokens than the burst at once, and would not charge the cost at all.
+	if cost > rl.burst {
+		logger.Debugf("cost %d in the %s response header is greater than the burst, charging %d tokens", cost, rl.costResponseHeader, rl.burst)
+		cost = rl.burst
+	}
+
+	if cost > 0 {
+		bucket.reserve(int(cost))
+	}
+}
+
+// applyRemaining lowers the bucket to the remaining budget of the source reported by the service,
+// when the bucket holds more tokens than this budget. The bucket is never refilled by this header.
+func (rl *rateLimiter) applyRemaining(logger log.Logger, bucket *tokenBucket, value string) {
+	// There is no rate limiting without an average.
+	if value == "" || rl.rate == 0 {
+		return
+	}
+
+	remaining, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || remaining < 0 {
+		logger.Debugf("invalid remaining budget %q in the %s response header", value, rl.costRemainingHeader)
+		return
+	}
+
+	if remaining >= rl.burst {
+		return
+	}
+
+	bucket.lowerTo(int(remaining))
 }
 
 func (rl *rateLimiter) serveDelayError(ctx context.Context, w http.ResponseWriter, r *http.Request, delay time.Duration) {
@@ -154,3 +285,102 @@ func (rl *rateLimiter) serveDelayError(ctx context.Context, w http.ResponseWrite
 		log.FromContext(ctx).Errorf("could not serve 429: %v", err)
 	}
 }
+
+// tokenBucket is the bucket of a source.
+// Its operations are serialized, so that the tokens counted by lowerTo are not consumed by other requests in the meantime.
+type tokenBucket struct {
+	mu      sync.Mutex
+	limiter *rate.Limiter
+}
+
+func (b *tokenBucket) reserve(n int) *rate.Reservation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.limiter.ReserveN(time.Now(), n)
+}
+
+func (b *tokenBucket) cancel(res *rate.Reservation) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	res.Cancel()
+}
+
+// lowerTo consumes the tokens of the bucket above the given number.
+func (b *tokenBucket) lowerTo(remaining int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// The limiter does not expose its number of tokens:
+	// it is deduced from the delay needed to get a full bucket, before giving the tokens back.
+	now := time.Now()
+	burst := b.limiter.Burst()
+	probe := b.limiter.ReserveN(now, burst)
+	tokens := float64(burst) - probe.DelayFrom(now).Seconds()*float64(b.limiter.Limit())
+	probe.CancelAt(now)
+
+	if excess := int(tokens) - remaining; excess > 0 {
+		b.limiter.ReserveN(now, excess)
+	}
+}
+
+// responseWriter reads the cost headers of the response, and removes them before they reach the client.
+type responseWriter struct {
+	http.ResponseWriter
+	costHeader      string
+	remainingHeader string
+
+	read      bool
+	cost      string
+	remaining string
+}
+
+func (r *responseWriter) readHeaders() {
+	if r.read {
+		return
+	}
+	r.read = true
+
+	header := r.ResponseWriter.Header()
+	if r.costHeader != "" {
+		r.cost = header.Get(r.costHeader)
+		header.Del(r.costHeader)
+	}
+	if r.remainingHeader != "" {
+		r.remaining = header.Get(r.remainingHeader)
+		header.Del(r.remainingHeader)
+	}
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	r.readHeaders()
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseWriter) Write(b []byte) (int, error) {
+	r.readHeaders()
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseWriter) Flush() {
+	r.readHeaders()
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.ResponseWriter)
+	}
+	return hijacker.Hijack()
+}
+
+func (r *responseWriter) CloseNotify() <-chan bool {
+	if notifier, ok := r.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+	return make(chan bool)
+}
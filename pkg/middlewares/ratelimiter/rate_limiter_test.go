@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vulcand/oxy/utils"
+	"golang.org/x/time/rate"
 )
 
 func TestNewRateLimiter(t *testing.T) {
@@ -73,6 +75,28 @@ func TestNewRateLimiter(t *testing.T) {
 			},
 			expectedError: "iPStrategy and RequestHeaderName are mutually exclusive",
 		},
+		{
+			desc: "cost greater than the burst",
+			config: dynamic.RateLimit{
+				Average: 200,
+				Burst:   10,
+				Cost: &dynamic.RateLimitCost{
+					Rules: []dynamic.RateLimitCostRule{{Cost: 11}},
+				},
+			},
+			expectedError: "invalid cost 11: it must be between 0 and the burst (10)",
+		},
+		{
+			desc: "invalid cost path pattern",
+			config: dynamic.RateLimit{
+				Average: 200,
+				Burst:   10,
+				Cost: &dynamic.RateLimitCost{
+					Rules: []dynamic.RateLimitCostRule{{PathRegex: "(", Cost: 2}},
+				},
+			},
+			expectedError: "error compiling path pattern \"(\": error parsing regexp: missing closing ): `(`",
+		},
 	}
 
 	for _, test := range testCases {
@@ -314,3 +338,215 @@ func TestRateLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimit_cost(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		method        string
+		path          string
+		responseCost  string
+		expectedCount int
+	}{
+		{
+			desc:          "default cost",
+			method:        http.MethodGet,
+			path:          "/",
+			expectedCount: 10,
+		},
+		{
+			desc:          "cost of the matching rule",
+			method:        http.MethodPost,
+			path:          "/api/reports",
+			expectedCount: 2,
+		},
+		{
+			desc:          "method not matching",
+			method:        http.MethodGet,
+			path:          "/api/reports",
+			expectedCount: 5,
+		},
+		{
+			desc:          "free requests",
+			method:        http.MethodGet,
+			path:          "/health",
+			expectedCount: 20,
+		},
+		{
+			desc:          "cost reported by the service",
+			method:        http.MethodGet,
+			path:          "/",
+			responseCost:  "4",
+			expectedCount: 2,
+		},
+		{
+			desc:          "cost greater than the burst reported by the service",
+			method:        http.MethodGet,
+			path:          "/",
+			responseCost:  "25",
+			expectedCount: 1,
+		},
+		{
+			desc:          "invalid cost reported by the service",
+			method:        http.MethodGet,
+			path:          "/",
+			responseCost:  "foo",
+			expectedCount: 10,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Cost", test.responseCost)
+			})
+
+			config := dynamic.RateLimit{
+				// A rate low enough for the bucket to be considered as never refilled during the test.
+				Average: 1,
+				Period:  types.Duration(time.Hour),
+				Burst:   10,
+				Cost: &dynamic.RateLimitCost{
+					Rules: []dynamic.RateLimitCostRule{
+						{Methods: []string{"post"}, PathRegex: "^/api/", Cost: 5},
+						{PathRegex: "^/api/", Cost: 2},
+						{PathRegex: "^/health$", Cost: 0},
+					},
+					ResponseHeader: "X-Request-Cost",
+				},
+			}
+
+			h, err := New(context.Background(), next, config, "rate-limiter")
+			require.NoError(t, err)
+
+			var count int
+			for i := 0; i < 20; i++ {
+				req := testhelpers.MustNewRequest(test.method, "http://localhost"+test.path, nil)
+				req.RemoteAddr = "127.0.0.1:1234"
+
+				recorder := httptest.NewRecorder()
+				h.ServeHTTP(recorder, req)
+
+				if recorder.Code == http.StatusOK {
+					count++
+				}
+
+				// The cost reported by the service does not reach the client.
+				_, ok := recorder.Header()["X-Request-Cost"]
+				assert.False(t, ok)
+			}
+
+			assert.Equal(t, test.expectedCount, count)
+		})
+	}
+}
+
+func TestRateLimit_remaining(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		remaining     string
+		expectedCount int
+	}{
+		{
+			desc:          "no remaining budget reported",
+			expectedCount: 10,
+		},
+		{
+			desc:          "remaining budget lower than the bucket",
+			remaining:     "3",
+			expectedCount: 4,
+		},
+		{
+			desc:          "exhausted budget",
+			remaining:     "0",
+			expectedCount: 1,
+		},
+		{
+			desc:          "remaining budget greater than the burst",
+			remaining:     "100",
+			expectedCount: 10,
+		},
+		{
+			desc:          "invalid remaining budget",
+			remaining:     "-1",
+			expectedCount: 10,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var served int
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Only the first response reports the remaining budget.
+				if served == 0 {
+					w.Header().Set("X-RateLimit-Remaining", test.remaining)
+				}
+				served++
+			})
+
+			config := dynamic.RateLimit{
+				// A rate low enough for the bucket to be considered as never refilled during the test.
+				Average: 1,
+				Period:  types.Duration(time.Hour),
+				Burst:   10,
+				Cost: &dynamic.RateLimitCost{
+					RemainingHeader: "X-RateLimit-Remaining",
+				},
+			}
+
+			h, err := New(context.Background(), next, config, "rate-limiter")
+			require.NoError(t, err)
+
+			var count int
+			for i := 0; i < 20; i++ {
+				req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/", nil)
+				req.RemoteAddr = "127.0.0.1:1234"
+
+				recorder := httptest.NewRecorder()
+				h.ServeHTTP(recorder, req)
+
+				if recorder.Code == http.StatusOK {
+					count++
+				}
+
+				// The remaining budget reported by the service does not reach the client.
+				_, ok := recorder.Header()["X-Ratelimit-Remaining"]
+				assert.False(t, ok)
+			}
+
+			assert.Equal(t, test.expectedCount, count)
+		})
+	}
+}
+
+func TestTokenBucket_lowerTo(t *testing.T) {
+	// A rate low enough for the bucket to be considered as never refilled during the test.
+	bucket := &tokenBucket{limiter: rate.NewLimiter(rate.Every(time.Hour), 10)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bucket.lowerTo(8)
+		}()
+	}
+	wg.Wait()
+
+	bucket.reserve(2)
+	bucket.lowerTo(8)
+
+	var count int
+	for i := 0; i < 10; i++ {
+		if bucket.reserve(1).Delay() == 0 {
+			count++
+		}
+	}
+
+	assert.Equal(t, 6, count)
+}
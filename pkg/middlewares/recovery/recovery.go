@@ -3,10 +3,10 @@ package recovery
 import (
 	"context"
 	"net/http"
-	"runtime"
 
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/panics"
 )
 
 const (
@@ -14,38 +14,53 @@ const (
 )
 
 type recovery struct {
-	next http.Handler
-	name string
+	next       http.Handler
+	name       string
+	entryPoint string
+	reports    *panics.Reports
 }
 
 // New creates recovery middleware.
-func New(ctx context.Context, next http.Handler, name string) (http.Handler, error) {
+// The recovered panics are reported to the given reports, if any.
+func New(ctx context.Context, next http.Handler, reports *panics.Reports, entryPointName, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
 	return &recovery{
-		next: next,
-		name: name,
+		next:       next,
+		name:       name,
+		entryPoint: entryPointName,
+		reports:    reports,
 	}, nil
 }
 
 func (re *recovery) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	defer recoverFunc(middlewares.GetLoggerCtx(req.Context(), re.name, typeName), rw, req)
+	defer re.recoverFunc(middlewares.GetLoggerCtx(req.Context(), re.name, typeName), rw, req)
 	re.next.ServeHTTP(rw, req)
 }
 
-func recoverFunc(ctx context.Context, rw http.ResponseWriter, r *http.Request) {
+func (re *recovery) recoverFunc(ctx context.Context, rw http.ResponseWriter, r *http.Request) {
 	if err := recover(); err != nil {
 		if !shouldLogPanic(err) {
 			log.FromContext(ctx).Debugf("Request has been aborted [%s - %s]: %v", r.RemoteAddr, r.URL, err)
 			return
 		}
 
-		log.FromContext(ctx).Errorf("Recovered from panic in HTTP handler [%s - %s]: %+v", r.RemoteAddr, r.URL, err)
+		p := panics.FromRecovered(err)
 
-		const size = 64 << 10
-		buf := make([]byte, size)
-		buf = buf[:runtime.Stack(buf, false)]
-		log.FromContext(ctx).Errorf("Stack: %s", buf)
+		if p.Router != "" {
+			ctx = log.With(ctx, log.Str(log.RouterName, p.Router))
+		}
+		if p.Middleware != "" {
+			ctx = log.With(ctx, log.Str(log.MiddlewareName, p.Middleware))
+		}
+		logger := log.FromContext(ctx)
+
+		logger.Errorf("Recovered from panic in HTTP handler [%s - %s]: %+v", r.RemoteAddr, r.URL, p.Value)
+		logger.Errorf("Stack: %s", p.Stack)
+
+		if re.reports != nil {
+			re.reports.Add(panics.NewReport(re.entryPoint, p, r))
+		}
 
 		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 	}
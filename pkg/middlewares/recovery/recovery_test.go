@@ -6,6 +6,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/panics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -14,7 +16,7 @@ func TestRecoverHandler(t *testing.T) {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		panic("I love panicing!")
 	}
-	recovery, err := New(context.Background(), http.HandlerFunc(fn), "foo-recovery")
+	recovery, err := New(context.Background(), http.HandlerFunc(fn), nil, "web", "foo-recovery")
 	require.NoError(t, err)
 
 	server := httptest.NewServer(recovery)
@@ -25,3 +27,30 @@ func TestRecoverHandler(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 }
+
+func TestRecoverHandler_report(t *testing.T) {
+	next := panics.WrapRouter(panics.WrapMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("I love panicing!")
+	}), "panicking@file"), "foo@file")
+
+	reports := panics.NewReports(panics.DefaultCapacity, metrics.NewVoidRegistry())
+
+	recovery, err := New(context.Background(), next, reports, "web", "foo-recovery")
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	recovery.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "http://example.com/foo", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+
+	list := reports.List()
+	require.Len(t, list, 1)
+
+	report := list[0]
+	assert.Equal(t, "web", report.EntryPoint)
+	assert.Equal(t, "foo@file", report.Router)
+	assert.Equal(t, "panicking@file", report.Middleware)
+	assert.Equal(t, "I love panicing!", report.Error)
+	assert.NotEmpty(t, report.Stack)
+	assert.Equal(t, panics.RequestSummary{Method: http.MethodPost, Host: "example.com", Path: "/foo", RemoteAddr: "192.0.2.1:1234"}, report.Request)
+}
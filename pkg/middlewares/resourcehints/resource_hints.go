@@ -0,0 +1,309 @@
+package resourcehints
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	typeName = "ResourceHints"
+)
+
+const (
+	// maxHeadSize is the size of the beginning of the HTML responses in which the resources are looked for.
+	maxHeadSize = 64 * 1024
+	// maxLearnedPaths is the maximum number of paths for which the resources are learned.
+	maxLearnedPaths = 1000
+	// maxLearnedHints is the maximum number of resources learned for a path.
+	maxLearnedHints = 10
+)
+
+// resourceHints is a middleware that adds Link headers to the successful responses,
+// so that the browsers preload resources and preconnect to origins before parsing the page.
+type resourceHints struct {
+	next  http.Handler
+	name  string
+	links []string
+	learn bool
+
+	mu sync.RWMutex
+	// learned are the preload links learned from the HTML responses, keyed by path.
+	learned map[string][]string
+}
+
+// New creates a resource hints middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.ResourceHints, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Preload) == 0 && len(config.Preconnect) == 0 && !config.LearnFromHTML {
+		return nil, errors.New("no resource hint configured")
+	}
+
+	var links []string
+	for _, hint := range config.Preload {
+		if hint.URL == "" {
+			return nil, errors.New("preload hint without URL")
+		}
+		links = append(links, preloadLink(hint.URL, hint.As, hint.CrossOrigin))
+	}
+
+	for _, origin := range config.Preconnect {
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid preconnect origin: %q", origin)
+		}
+		links = append(links, fmt.Sprintf("<%s>; rel=preconnect", origin))
+	}
+
+	return &resourceHints{
+		next:    next,
+		name:    name,
+		links:   links,
+		learn:   config.LearnFromHTML,
+		learned: make(map[string][]string),
+	}, nil
+}
+
+func (r *resourceHints) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *resourceHints) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		r.next.ServeHTTP(rw, req)
+		return
+	}
+
+	links := r.links
+	if r.learn {
+		r.mu.RLock()
+		if learned := r.learned[req.URL.Path]; len(learned) > 0 {
+			links = append(append([]string{}, r.links...), learned...)
+		}
+		r.mu.RUnlock()
+	}
+
+	writer := &responseWriter{rw: rw, links: links, learn: r.learn && req.Method == http.MethodGet}
+	r.next.ServeHTTP(writer, req)
+
+	if writer.capture {
+		// The same-origin resources are kept as paths.
+		r.learnFrom(&url.URL{Path: req.URL.Path}, writer.head.Bytes())
+	}
+}
+
+// learnFrom records the resources of the head of an HTML response as the preload links of its path.
+func (r *resourceHints) learnFrom(base *url.URL, head []byte) {
+	links := parseHead(base, head)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(links) == 0 {
+		delete(r.learned, base.Path)
+		return
+	}
+
+	if _, exists := r.learned[base.Path]; !exists && len(r.learned) >= maxLearnedPaths {
+		return
+	}
+
+	r.learned[base.Path] = links
+}
+
+// parseHead returns the preload links of the stylesheets and classic scripts of the head of an HTML document,
+// which URLs are resolved against the URL of the document.
+func parseHead(base *url.URL, head []byte) []string {
+	var links []string
+	seen := make(map[string]struct{})
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(head))
+	for len(links) < maxLearnedHints {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return links
+
+		case html.EndTagToken:
+			if tokenizer.Token().DataAtom == atom.Head {
+				return links
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+
+			var target, as string
+			switch token.DataAtom {
+			case atom.Body:
+				return links
+			case atom.Link:
+				if hasToken(attribute(token, "rel"), "stylesheet") {
+					target, as = attribute(token, "href"), "style"
+				}
+			case atom.Script:
+				// The module scripts are fetched differently, and cannot use a preload link.
+				if !strings.EqualFold(attribute(token, "type"), "module") {
+					target, as = attribute(token, "src"), "script"
+				}
+			}
+
+			link, ok := resolve(base, target, as, hasAttribute(token, "crossorigin"))
+			if !ok {
+				continue
+			}
+
+			if _, exists := seen[link]; !exists {
+				seen[link] = struct{}{}
+				links = append(links, link)
+			}
+		}
+	}
+
+	return links
+}
+
+func resolve(base *url.URL, target, as string, crossOrigin bool) (string, bool) {
+	if target == "" {
+		return "", false
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(target))
+	if err != nil {
+		return "", false
+	}
+
+	resolved := base.ResolveReference(ref)
+	if resolved.Scheme != "" && resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return "", false
+	}
+
+	return preloadLink(resolved.String(), as, crossOrigin), true
+}
+
+func preloadLink(target, as string, crossOrigin bool) string {
+	link := fmt.Sprintf("<%s>; rel=preload", target)
+	if as != "" {
+		link += "; as=" + as
+	}
+	if crossOrigin {
+		link += "; crossorigin"
+	}
+	return link
+}
+
+func attribute(token html.Token, key string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func hasAttribute(token html.Token, key string) bool {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func hasToken(value, token string) bool {
+	for _, field := range strings.Fields(value) {
+		if strings.EqualFold(field, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// responseWriter adds the Link headers to the successful responses,
+// and captures the beginning of the HTML ones, which resources are learned.
+type responseWriter struct {
+	rw    http.ResponseWriter
+	links []string
+	learn bool
+
+	wroteHeader bool
+	capture     bool
+	head        bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if code >= http.StatusOK && code < http.StatusMultipleChoices {
+		for _, link := range w.links {
+			w.rw.Header().Add("Link", link)
+		}
+
+		// The compressed responses cannot be parsed.
+		w.capture = w.learn && isHTML(w.rw.Header().Get("Content-Type")) && w.rw.Header().Get("Content-Encoding") == ""
+	}
+
+	w.rw.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.capture && w.head.Len() < maxHeadSize {
+		n := len(p)
+		if remaining := maxHeadSize - w.head.Len(); n > remaining {
+			n = remaining
+		}
+		w.head.Write(p[:n])
+	}
+
+	return w.rw.Write(p)
+}
+
+// Flush sends any buffered data to the client.
+func (w *responseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.rw.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+}
+
+func isHTML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/html"
+}
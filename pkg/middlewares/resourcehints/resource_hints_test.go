@@ -0,0 +1,178 @@
+package resourcehints
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.ResourceHints
+		expectedError bool
+	}{
+		{
+			desc:          "no hint",
+			expectedError: true,
+		},
+		{
+			desc:          "preload hint without URL",
+			config:        dynamic.ResourceHints{Preload: []dynamic.PreloadHint{{As: "style"}}},
+			expectedError: true,
+		},
+		{
+			desc:          "preconnect to a path",
+			config:        dynamic.ResourceHints{Preconnect: []string{"/static"}},
+			expectedError: true,
+		},
+		{
+			desc: "valid hints",
+			config: dynamic.ResourceHints{
+				Preload:    []dynamic.PreloadHint{{URL: "/app.css", As: "style"}},
+				Preconnect: []string{"https://cdn.example.com"},
+			},
+		},
+		{
+			desc:   "learning only",
+			config: dynamic.ResourceHints{LearnFromHTML: true},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, "resource-hints")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestResourceHints(t *testing.T) {
+	config := dynamic.ResourceHints{
+		Preload: []dynamic.PreloadHint{
+			{URL: "/app.css", As: "style"},
+			{URL: "/fonts/inter.woff2", As: "font", CrossOrigin: true},
+		},
+		Preconnect: []string{"https://cdn.example.com"},
+	}
+
+	testCases := []struct {
+		desc          string
+		method        string
+		status        int
+		expectedLinks []string
+	}{
+		{
+			desc:   "successful response",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			expectedLinks: []string{
+				"</app.css>; rel=preload; as=style",
+				"</fonts/inter.woff2>; rel=preload; as=font; crossorigin",
+				"<https://cdn.example.com>; rel=preconnect",
+				"</api>; rel=canonical",
+			},
+		},
+		{
+			desc:          "error response",
+			method:        http.MethodGet,
+			status:        http.StatusNotFound,
+			expectedLinks: []string{"</api>; rel=canonical"},
+		},
+		{
+			desc:          "POST request",
+			method:        http.MethodPost,
+			status:        http.StatusOK,
+			expectedLinks: []string{"</api>; rel=canonical"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Add("Link", "</api>; rel=canonical")
+				rw.WriteHeader(test.status)
+			})
+
+			handler, err := New(context.Background(), next, config, "resource-hints")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(test.method, "http://localhost/", nil))
+
+			assert.ElementsMatch(t, test.expectedLinks, recorder.Header()["Link"])
+		})
+	}
+}
+
+func TestResourceHints_learnFromHTML(t *testing.T) {
+	const page = `<!DOCTYPE html>
+<html>
+<head>
+  <link rel="stylesheet" href="css/app.css">
+  <link rel="icon" href="/favicon.ico">
+  <script src="https://cdn.example.com/lib.js" crossorigin></script>
+  <script type="module" src="/main.mjs"></script>
+  <script src="/app.js"></script>
+  <script src="/app.js"></script>
+</head>
+<body>
+  <script src="/late.js"></script>
+</body>
+</html>`
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/docs/index.html":
+			rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		case "/docs/compressed.html":
+			rw.Header().Set("Content-Type", "text/html")
+			rw.Header().Set("Content-Encoding", "gzip")
+		default:
+			rw.Header().Set("Content-Type", "text/plain")
+		}
+		_, _ = rw.Write([]byte(page))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.ResourceHints{LearnFromHTML: true}, "resource-hints")
+	require.NoError(t, err)
+
+	serve := func(path string) []string {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+		return recorder.Header()["Link"]
+	}
+
+	// The resources are not known yet.
+	assert.Empty(t, serve("/docs/index.html"))
+
+	expected := []string{
+		"</docs/css/app.css>; rel=preload; as=style",
+		"<https://cdn.example.com/lib.js>; rel=preload; as=script; crossorigin",
+		"</app.js>; rel=preload; as=script",
+	}
+	assert.Equal(t, expected, serve("/docs/index.html"))
+
+	// The resources are learned per path.
+	assert.Empty(t, serve("/docs/other.html"))
+
+	for _, path := range []string{"/docs/compressed.html", "/docs/page.txt"} {
+		serve(path)
+		assert.Empty(t, serve(path), path)
+	}
+}
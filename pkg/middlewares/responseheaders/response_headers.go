@@ -0,0 +1,120 @@
+package responseheaders
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+)
+
+const (
+	typeName = "ResponseHeaders"
+)
+
+// responseHeaders is a middleware that removes headers from the responses,
+// once all the handlers it wraps have set theirs.
+type responseHeaders struct {
+	next    http.Handler
+	headers []string
+}
+
+// New creates a middleware removing the given response headers, except the kept ones.
+// It returns next unchanged if no header is left to remove.
+func New(ctx context.Context, next http.Handler, remove, keep []string, routerName string) http.Handler {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, routerName, typeName))
+
+	kept := make(map[string]struct{}, len(keep))
+	for _, name := range keep {
+		kept[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+
+	var headers []string
+	for _, name := range remove {
+		name = http.CanonicalHeaderKey(name)
+		if _, ok := kept[name]; !ok {
+			headers = append(headers, name)
+		}
+	}
+
+	if len(headers) == 0 {
+		return next
+	}
+
+	logger.Debugf("Creating middleware, removing the response headers %v", headers)
+
+	return &responseHeaders{
+		next:    next,
+		headers: headers,
+	}
+}
+
+// WrapHandler wraps the response headers middleware in an alice.Constructor.
+func WrapHandler(ctx context.Context, remove, keep []string, routerName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return New(ctx, next, remove, keep, routerName), nil
+	}
+}
+
+func (r *responseHeaders) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	r.next.ServeHTTP(&responseWriter{ResponseWriter: rw, headers: r.headers}, req)
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+
+	headers     []string
+	wroteHeader bool
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+
+		header := r.Header()
+		for _, name := range r.headers {
+			header.Del(name)
+		}
+	}
+
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseWriter) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseWriter) Flush() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.ResponseWriter)
+	}
+
+	return hijacker.Hijack()
+}
+
+func (r *responseWriter) CloseNotify() <-chan bool {
+	if notifier, ok := r.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+
+	return make(chan bool)
+}
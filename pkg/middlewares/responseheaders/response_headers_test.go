@@ -0,0 +1,89 @@
+package responseheaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseHeaders(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		remove          []string
+		keep            []string
+		write           bool
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:   "removed headers",
+			remove: []string{"server", "X-Powered-By"},
+			expectedHeaders: map[string]string{
+				"Server":       "",
+				"X-Powered-By": "",
+				"X-Internal":   "debug",
+			},
+		},
+		{
+			desc:   "removed headers on implicit WriteHeader",
+			remove: []string{"Server", "X-Powered-By"},
+			write:  true,
+			expectedHeaders: map[string]string{
+				"Server":       "",
+				"X-Powered-By": "",
+				"X-Internal":   "debug",
+			},
+		},
+		{
+			desc:   "kept headers",
+			remove: []string{"Server", "X-Powered-By", "X-Internal"},
+			keep:   []string{"x-internal"},
+			expectedHeaders: map[string]string{
+				"Server":       "",
+				"X-Powered-By": "",
+				"X-Internal":   "debug",
+			},
+		},
+		{
+			desc:   "all headers kept",
+			remove: []string{"Server"},
+			keep:   []string{"Server"},
+			expectedHeaders: map[string]string{
+				"Server":       "backend",
+				"X-Powered-By": "PHP",
+				"X-Internal":   "debug",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Server", "backend")
+				rw.Header().Set("X-Powered-By", "PHP")
+				rw.Header().Set("X-Internal", "debug")
+
+				if test.write {
+					_, _ = rw.Write([]byte("foo"))
+					return
+				}
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler := New(context.Background(), next, test.remove, test.keep, "router")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, recorder.Header().Get(name), name)
+			}
+		})
+	}
+}
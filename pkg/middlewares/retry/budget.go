@@ -0,0 +1,95 @@
+package retry
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+)
+
+// budgetWindow is the number of seconds over which the requests and the retries are counted.
+const budgetWindow = 10
+
+// Budget limits the share of the requests which are retries, so that the retries do not amplify an outage.
+// The requests and the retries are counted over the last 10 seconds.
+type Budget struct {
+	percent             int
+	minRetriesPerSecond int
+
+	mu      sync.Mutex
+	buckets [budgetWindow]budgetBucket
+	now     func() time.Time
+}
+
+// budgetBucket holds the counts of a second.
+type budgetBucket struct {
+	second   int64
+	requests int
+	retries  int
+}
+
+// NewBudget creates a new Budget.
+func NewBudget(config dynamic.RetryBudget) (*Budget, error) {
+	if config.Percent < 0 || config.Percent > 100 {
+		return nil, fmt.Errorf("incorrect value for the percentage of retries (%d), must be between 0 and 100", config.Percent)
+	}
+
+	if config.MinRetriesPerSecond < 0 {
+		return nil, fmt.Errorf("incorrect value for the minimum number of retries per second (%d)", config.MinRetriesPerSecond)
+	}
+
+	return &Budget{
+		percent:             config.Percent,
+		minRetriesPerSecond: config.MinRetriesPerSecond,
+		now:                 time.Now,
+	}, nil
+}
+
+func (b *Budget) addRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bucket().requests++
+}
+
+func (b *Budget) addRetry() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bucket().retries++
+}
+
+// canRetry tells whether one more retry fits in the budget.
+func (b *Budget) canRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now().Unix()
+
+	var requests, retries int
+	for _, bucket := range b.buckets {
+		if now-bucket.second < budgetWindow {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+
+	if retries < b.minRetriesPerSecond*budgetWindow {
+		return true
+	}
+
+	return (retries+1)*100 <= requests*b.percent
+}
+
+// bucket returns the bucket of the current second.
+func (b *Budget) bucket() *budgetBucket {
+	now := b.now().Unix()
+
+	bucket := &b.buckets[now%budgetWindow]
+	if bucket.second != now {
+		*bucket = budgetBucket{second: now}
+	}
+
+	return bucket
+}
@@ -0,0 +1,45 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget(t *testing.T) {
+	budget, err := NewBudget(dynamic.RetryBudget{Percent: 10, MinRetriesPerSecond: 1})
+	require.NoError(t, err)
+
+	now := time.Unix(1000, 0)
+	budget.now = func() time.Time { return now }
+
+	// The minimum number of retries is allowed without any request.
+	for i := 0; i < 10; i++ {
+		require.True(t, budget.canRetry())
+		budget.addRetry()
+	}
+	assert.False(t, budget.canRetry())
+
+	// 10% of 110 requests allow 11 retries.
+	for i := 0; i < 110; i++ {
+		budget.addRequest()
+	}
+	assert.True(t, budget.canRetry())
+	budget.addRetry()
+	assert.False(t, budget.canRetry())
+
+	// The counts expire after 10 seconds.
+	now = now.Add(10 * time.Second)
+	assert.True(t, budget.canRetry())
+}
+
+func TestNewBudget_invalid(t *testing.T) {
+	_, err := NewBudget(dynamic.RetryBudget{Percent: 150})
+	assert.Error(t, err)
+
+	_, err = NewBudget(dynamic.RetryBudget{Percent: 10, MinRetriesPerSecond: -1})
+	assert.Error(t, err)
+}
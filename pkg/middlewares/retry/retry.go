@@ -8,11 +8,15 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptrace"
+	"strconv"
+	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
 	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/opentracing/opentracing-go/ext"
 )
 
@@ -23,6 +27,9 @@ const (
 	typeName = "Retry"
 )
 
+// defaultMaxInterval is the default maximum delay before a retry.
+const defaultMaxInterval = 10 * time.Second
+
 // Listener is used to inform about retry attempts.
 type Listener interface {
 	// Retried will be called when a retry happens, with the request attempt passed to it.
@@ -30,32 +37,86 @@ type Listener interface {
 	Retried(req *http.Request, attempt int)
 }
 
+// SuccessListener is used to inform about the retried requests which eventually succeeded.
+type SuccessListener interface {
+	// Succeeded will be called when the last attempt of a retried request did not result in a server error,
+	// with the number of attempts passed to it.
+	Succeeded(req *http.Request, attempts int)
+}
+
 // Listeners is a convenience type to construct a list of Listener and notify
 // each of them about a retry attempt.
 type Listeners []Listener
 
+// attemptKey is the context key of the attempt of a request.
+type attemptKey struct{}
+
+// attempt holds what the handlers of an attempt learnt about its failure.
+type attempt struct {
+	connectionReset bool
+}
+
+// MarkConnectionReset records that the connection to the server was reset, or closed, before it answered the current attempt of the request.
+func MarkConnectionReset(req *http.Request) {
+	if a, ok := req.Context().Value(attemptKey{}).(*attempt); ok {
+		a.connectionReset = true
+	}
+}
+
 // retry is a middleware that retries requests.
 type retry struct {
-	attempts int
-	next     http.Handler
-	listener Listener
-	name     string
+	attempts        int
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	statusCodes     types.HTTPCodeRanges
+	connectionReset bool
+	idempotentOnly  bool
+	budget          *Budget
+	next            http.Handler
+	listener        Listener
+	name            string
 }
 
 // New returns a new retry middleware.
-func New(ctx context.Context, next http.Handler, config dynamic.Retry, listener Listener, name string) (http.Handler, error) {
+// The budget, which may be shared by several middlewares, limits the retries, a nil one meaning no limit.
+func New(ctx context.Context, next http.Handler, config dynamic.Retry, budget *Budget, listener Listener, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
 	if config.Attempts <= 0 {
 		return nil, fmt.Errorf("incorrect (or empty) value for attempt (%d)", config.Attempts)
 	}
 
-	return &retry{
-		attempts: config.Attempts,
-		next:     next,
-		listener: listener,
-		name:     name,
-	}, nil
+	if config.InitialInterval < 0 || config.MaxInterval < 0 {
+		return nil, fmt.Errorf("incorrect value for the interval between the attempts (initial: %s, max: %s)", config.InitialInterval, config.MaxInterval)
+	}
+
+	maxInterval := time.Duration(config.MaxInterval)
+	if maxInterval == 0 {
+		maxInterval = defaultMaxInterval
+	}
+
+	r := &retry{
+		attempts:        config.Attempts,
+		initialInterval: time.Duration(config.InitialInterval),
+		maxInterval:     maxInterval,
+		budget:          budget,
+		next:            next,
+		listener:        listener,
+		name:            name,
+	}
+
+	if config.RetryOn != nil {
+		statusCodes, err := types.NewHTTPCodeRanges(config.RetryOn.StatusCodes)
+		if err != nil {
+			return nil, fmt.Errorf("incorrect status codes to retry: %w", err)
+		}
+
+		r.statusCodes = statusCodes
+		r.connectionReset = config.RetryOn.ConnectionReset
+		r.idempotentOnly = config.RetryOn.IdempotentMethodsOnly
+	}
+
+	return r, nil
 }
 
 func (r *retry) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -63,29 +124,59 @@ func (r *retry) GetTracingInformation() (string, ext.SpanKindEnum) {
 }
 
 func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if r.budget != nil {
+		r.budget.addRequest()
+	}
+
+	retryable := r.attempts > 1 && (!r.idempotentOnly || isIdempotent(req.Method))
+
+	// The responses of the server can only be retried when the request can be sent again, i.e. when it has no body.
+	retryResponses := retryable && (len(r.statusCodes) > 0 || r.connectionReset) && (req.Body == nil || req.Body == http.NoBody)
+
 	// if we might make multiple attempts, swap the body for an ioutil.NopCloser
 	// cf https://github.com/containous/traefik/issues/1008
-	if r.attempts > 1 {
+	if r.attempts > 1 && req.Body != nil {
 		body := req.Body
 		defer body.Close()
 		req.Body = ioutil.NopCloser(body)
 	}
 
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName))
+
+	backOff := r.newBackOff()
+
 	attempts := 1
+	var retryResponseWriter responseWriter
 	for {
-		shouldRetry := attempts < r.attempts
-		retryResponseWriter := newResponseWriter(rw, shouldRetry)
+		shouldRetry := retryable && attempts < r.attempts
+		if shouldRetry && r.budget != nil && !r.budget.canRetry() {
+			logger.Debugf("Not retrying the request %v, the retry budget is exhausted", req.URL)
+			shouldRetry = false
+		}
+
+		currentAttempt := &attempt{}
+
+		var retryResponse func(code int, header http.Header) bool
+		if retryResponses {
+			retryResponse = func(code int, header http.Header) bool {
+				return r.retryResponse(currentAttempt, code, header)
+			}
+		}
 
-		// Disable retries when the backend already received request data
+		retryResponseWriter = newResponseWriter(rw, shouldRetry, retryResponse)
+
+		// Disable retries when the backend already received request data,
+		// unless the responses of the server can be retried.
 		trace := &httptrace.ClientTrace{
 			WroteHeaders: func() {
-				retryResponseWriter.DisableRetries()
+				retryResponseWriter.RequestSent()
 			},
 			WroteRequest: func(httptrace.WroteRequestInfo) {
-				retryResponseWriter.DisableRetries()
+				retryResponseWriter.RequestSent()
 			},
 		}
 		newCtx := httptrace.WithClientTrace(req.Context(), trace)
+		newCtx = context.WithValue(newCtx, attemptKey{}, currentAttempt)
 
 		r.next.ServeHTTP(retryResponseWriter, req.WithContext(newCtx))
 
@@ -93,13 +184,117 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 			break
 		}
 
+		if middlewares.ClientAborted(req) {
+			logger.Debugf("Not retrying the request %v, the client went away", req.URL)
+			break
+		}
+
+		delay := backOff.NextBackOff()
+		if retryAfter, ok := parseRetryAfter(retryResponseWriter.Header()); ok {
+			delay = retryAfter
+		}
+
+		if !wait(req.Context(), delay) {
+			logger.Debugf("Not retrying the request %v, the client went away", req.URL)
+			break
+		}
+
+		if r.budget != nil {
+			r.budget.addRetry()
+		}
+
 		attempts++
 
-		log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName)).
-			Debugf("New attempt %d for request: %v", attempts, req.URL)
+		logger.Debugf("New attempt %d for request: %v", attempts, req.URL)
 
 		r.listener.Retried(req, attempts)
 	}
+
+	if attempts > 1 && !middlewares.ClientAborted(req) && retryResponseWriter.StatusCode() < http.StatusInternalServerError {
+		if listener, ok := r.listener.(SuccessListener); ok {
+			listener.Succeeded(req, attempts)
+		}
+	}
+}
+
+// retryResponse tells whether the response of the server to an attempt has to be retried.
+func (r *retry) retryResponse(a *attempt, code int, header http.Header) bool {
+	if !r.statusCodes.Contains(code) && !(r.connectionReset && a.connectionReset) {
+		return false
+	}
+
+	// The server asking to come back later than the maximum interval gets its response.
+	retryAfter, ok := parseRetryAfter(header)
+	return !ok || retryAfter <= r.maxInterval
+}
+
+// newBackOff returns the delays between the attempts of a request.
+func (r *retry) newBackOff() backoff.BackOff {
+	if r.initialInterval <= 0 {
+		return &backoff.ZeroBackOff{}
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = r.initialInterval
+	b.MaxInterval = r.maxInterval
+	b.Multiplier = 2
+	b.MaxElapsedTime = 0
+	b.Reset()
+
+	return b
+}
+
+// wait waits for the given delay, and returns false if the context is done before.
+func wait(ctx context.Context, delay time.Duration) bool {
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// parseRetryAfter returns the delay asked by the Retry-After header, either in seconds or as an HTTP date.
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	delay := time.Until(date)
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay, true
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
 }
 
 // Retried exists to implement the Listener interface. It calls Retried on each of its slice entries.
@@ -109,18 +304,33 @@ func (l Listeners) Retried(req *http.Request, attempt int) {
 	}
 }
 
+// Succeeded exists to implement the SuccessListener interface.
+// It calls Succeeded on each of its slice entries implementing SuccessListener.
+func (l Listeners) Succeeded(req *http.Request, attempts int) {
+	for _, listener := range l {
+		if successListener, ok := listener.(SuccessListener); ok {
+			successListener.Succeeded(req, attempts)
+		}
+	}
+}
+
 type responseWriter interface {
 	http.ResponseWriter
 	http.Flusher
 	ShouldRetry() bool
 	DisableRetries()
+	RequestSent()
+	StatusCode() int
 }
 
-func newResponseWriter(rw http.ResponseWriter, shouldRetry bool) responseWriter {
+// newResponseWriter creates the response writer of an attempt.
+// The retryResponse function, if any, tells whether the response of the server has to be retried.
+func newResponseWriter(rw http.ResponseWriter, shouldRetry bool, retryResponse func(code int, header http.Header) bool) responseWriter {
 	responseWriter := &responseWriterWithoutCloseNotify{
 		responseWriter: rw,
 		headers:        make(http.Header),
 		shouldRetry:    shouldRetry,
+		retryResponse:  retryResponse,
 	}
 	if _, ok := rw.(http.CloseNotifier); ok {
 		return &responseWriterWithCloseNotify{
@@ -134,7 +344,10 @@ type responseWriterWithoutCloseNotify struct {
 	responseWriter http.ResponseWriter
 	headers        http.Header
 	shouldRetry    bool
+	retryResponse  func(code int, header http.Header) bool
+	requestSent    bool
 	written        bool
+	statusCode     int
 }
 
 func (r *responseWriterWithoutCloseNotify) ShouldRetry() bool {
@@ -145,6 +358,21 @@ func (r *responseWriterWithoutCloseNotify) DisableRetries() {
 	r.shouldRetry = false
 }
 
+// RequestSent records that the server received the request.
+// From then on, the attempt is only retried if its response has to.
+func (r *responseWriterWithoutCloseNotify) RequestSent() {
+	if r.retryResponse == nil {
+		r.DisableRetries()
+		return
+	}
+	r.requestSent = true
+}
+
+// StatusCode returns the status code sent to the client, if any.
+func (r *responseWriterWithoutCloseNotify) StatusCode() int {
+	return r.statusCode
+}
+
 func (r *responseWriterWithoutCloseNotify) Header() http.Header {
 	if r.written {
 		return r.responseWriter.Header()
@@ -153,19 +381,29 @@ func (r *responseWriterWithoutCloseNotify) Header() http.Header {
 }
 
 func (r *responseWriterWithoutCloseNotify) Write(buf []byte) (int, error) {
+	if r.ShouldRetry() && r.requestSent {
+		// The server answered without an explicit status code.
+		r.WriteHeader(http.StatusOK)
+	}
 	if r.ShouldRetry() {
 		return len(buf), nil
 	}
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
 	return r.responseWriter.Write(buf)
 }
 
 func (r *responseWriterWithoutCloseNotify) WriteHeader(code int) {
-	if r.ShouldRetry() && code == http.StatusServiceUnavailable {
+	if r.ShouldRetry() && r.requestSent && !r.retryResponse(code, r.headers) {
+		r.DisableRetries()
+	}
+
+	if r.ShouldRetry() && !r.requestSent && code == http.StatusServiceUnavailable {
 		// We get a 503 HTTP Status Code when there is no backend server in the pool
-		// to which the request could be sent.  Also, note that r.ShouldRetry()
-		// will never return true in case there was a connection established to
-		// the backend server and so we can be sure that the 503 was produced
-		// inside Traefik already and we don't have to retry in this cases.
+		// to which the request could be sent. As the request was not sent,
+		// we can be sure that the 503 was produced inside Traefik already
+		// and we don't have to retry in this cases.
 		r.DisableRetries()
 	}
 
@@ -184,6 +422,7 @@ func (r *responseWriterWithoutCloseNotify) WriteHeader(code int) {
 
 	r.responseWriter.WriteHeader(code)
 	r.written = true
+	r.statusCode = code
 }
 
 func (r *responseWriterWithoutCloseNotify) Hijack() (net.Conn, *bufio.ReadWriter, error) {
@@ -3,16 +3,19 @@ package retry
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/middlewares/emptybackendhandler"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,6 +28,7 @@ func TestRetry(t *testing.T) {
 		desc                  string
 		config                dynamic.Retry
 		wantRetryAttempts     int
+		wantRetrySuccess      bool
 		wantResponseStatus    int
 		amountFaultyEndpoints int
 	}{
@@ -46,6 +50,7 @@ func TestRetry(t *testing.T) {
 			desc:                  "one retry when one server is faulty",
 			config:                dynamic.Retry{Attempts: 2},
 			wantRetryAttempts:     1,
+			wantRetrySuccess:      true,
 			wantResponseStatus:    http.StatusOK,
 			amountFaultyEndpoints: 1,
 		},
@@ -53,6 +58,7 @@ func TestRetry(t *testing.T) {
 			desc:                  "two retries when two servers are faulty",
 			config:                dynamic.Retry{Attempts: 3},
 			wantRetryAttempts:     2,
+			wantRetrySuccess:      true,
 			wantResponseStatus:    http.StatusOK,
 			amountFaultyEndpoints: 2,
 		},
@@ -100,7 +106,7 @@ func TestRetry(t *testing.T) {
 			require.NoError(t, err)
 
 			retryListener := &countingRetryListener{}
-			retry, err := New(context.Background(), loadBalancer, test.config, retryListener, "traefikTest")
+			retry, err := New(context.Background(), loadBalancer, test.config, nil, retryListener, "traefikTest")
 			require.NoError(t, err)
 
 			recorder := httptest.NewRecorder()
@@ -110,6 +116,7 @@ func TestRetry(t *testing.T) {
 
 			assert.Equal(t, test.wantResponseStatus, recorder.Code)
 			assert.Equal(t, test.wantRetryAttempts, retryListener.timesCalled)
+			assert.Equal(t, test.wantRetrySuccess, retryListener.timesSucceeded == 1)
 		})
 	}
 }
@@ -126,7 +133,7 @@ func TestRetryEmptyServerList(t *testing.T) {
 	next := emptybackendhandler.New(loadBalancer)
 
 	retryListener := &countingRetryListener{}
-	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 3}, retryListener, "traefikTest")
+	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 3}, nil, retryListener, "traefikTest")
 	require.NoError(t, err)
 
 	recorder := httptest.NewRecorder()
@@ -138,6 +145,29 @@ func TestRetryEmptyServerList(t *testing.T) {
 	assert.Equal(t, 0, retryListener.timesCalled)
 }
 
+func TestRetryClientAborted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts++
+		// The client goes away while the backend cannot be reached.
+		cancel()
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	retryListener := &countingRetryListener{}
+	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 3}, nil, retryListener, "traefikTest")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil).WithContext(ctx)
+	retry.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 0, retryListener.timesCalled)
+	assert.Equal(t, 0, retryListener.timesSucceeded)
+}
+
 func TestRetryListeners(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	retryListeners := Listeners{&countingRetryListener{}, &countingRetryListener{}}
@@ -174,7 +204,7 @@ func TestMultipleRetriesShouldNotLooseHeaders(t *testing.T) {
 		rw.WriteHeader(http.StatusNoContent)
 	})
 
-	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 3}, &countingRetryListener{}, "traefikTest")
+	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 3}, nil, &countingRetryListener{}, "traefikTest")
 	require.NoError(t, err)
 
 	responseRecorder := httptest.NewRecorder()
@@ -199,13 +229,18 @@ func TestMultipleRetriesShouldNotLooseHeaders(t *testing.T) {
 
 // countingRetryListener is a Listener implementation to count the times the Retried fn is called.
 type countingRetryListener struct {
-	timesCalled int
+	timesCalled    int
+	timesSucceeded int
 }
 
 func (l *countingRetryListener) Retried(req *http.Request, attempt int) {
 	l.timesCalled++
 }
 
+func (l *countingRetryListener) Succeeded(req *http.Request, attempts int) {
+	l.timesSucceeded++
+}
+
 func TestRetryWithFlush(t *testing.T) {
 	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.WriteHeader(200)
@@ -220,7 +255,7 @@ func TestRetryWithFlush(t *testing.T) {
 		}
 	})
 
-	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 1}, &countingRetryListener{}, "traefikTest")
+	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 1}, nil, &countingRetryListener{}, "traefikTest")
 	require.NoError(t, err)
 
 	responseRecorder := httptest.NewRecorder()
@@ -296,7 +331,7 @@ func TestRetryWebsocket(t *testing.T) {
 			}
 
 			retryListener := &countingRetryListener{}
-			retryH, err := New(context.Background(), loadBalancer, dynamic.Retry{Attempts: test.maxRequestAttempts}, retryListener, "traefikTest")
+			retryH, err := New(context.Background(), loadBalancer, dynamic.Retry{Attempts: test.maxRequestAttempts}, nil, retryListener, "traefikTest")
 			require.NoError(t, err)
 
 			retryServer := httptest.NewServer(retryH)
@@ -313,3 +348,172 @@ func TestRetryWebsocket(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryOn(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             dynamic.Retry
+		method             string
+		body               io.Reader
+		retryAfter         string
+		connectionReset    bool
+		wantAttempts       int
+		wantResponseStatus int
+	}{
+		{
+			desc:               "server responses are not retried by default",
+			config:             dynamic.Retry{Attempts: 3},
+			method:             http.MethodGet,
+			wantAttempts:       1,
+			wantResponseStatus: http.StatusBadGateway,
+		},
+		{
+			desc:               "retryable status code",
+			config:             dynamic.Retry{Attempts: 3, RetryOn: &dynamic.RetryConditions{StatusCodes: []string{"502-504"}}},
+			method:             http.MethodGet,
+			wantAttempts:       3,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "not retryable status code",
+			config:             dynamic.Retry{Attempts: 3, RetryOn: &dynamic.RetryConditions{StatusCodes: []string{"503"}}},
+			method:             http.MethodGet,
+			wantAttempts:       1,
+			wantResponseStatus: http.StatusBadGateway,
+		},
+		{
+			desc:               "request with a body",
+			config:             dynamic.Retry{Attempts: 3, RetryOn: &dynamic.RetryConditions{StatusCodes: []string{"502"}}},
+			method:             http.MethodPut,
+			body:               strings.NewReader("foo"),
+			wantAttempts:       1,
+			wantResponseStatus: http.StatusBadGateway,
+		},
+		{
+			desc:               "non idempotent method",
+			config:             dynamic.Retry{Attempts: 3, RetryOn: &dynamic.RetryConditions{StatusCodes: []string{"502"}, IdempotentMethodsOnly: true}},
+			method:             http.MethodPost,
+			wantAttempts:       1,
+			wantResponseStatus: http.StatusBadGateway,
+		},
+		{
+			desc:               "idempotent method",
+			config:             dynamic.Retry{Attempts: 3, RetryOn: &dynamic.RetryConditions{StatusCodes: []string{"502"}, IdempotentMethodsOnly: true}},
+			method:             http.MethodDelete,
+			wantAttempts:       3,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "connection reset",
+			config:             dynamic.Retry{Attempts: 3, RetryOn: &dynamic.RetryConditions{ConnectionReset: true}},
+			method:             http.MethodGet,
+			connectionReset:    true,
+			wantAttempts:       3,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "bad gateway without connection reset",
+			config:             dynamic.Retry{Attempts: 3, RetryOn: &dynamic.RetryConditions{ConnectionReset: true}},
+			method:             http.MethodGet,
+			wantAttempts:       1,
+			wantResponseStatus: http.StatusBadGateway,
+		},
+		{
+			desc:               "Retry-After below the maximum interval",
+			config:             dynamic.Retry{Attempts: 3, MaxInterval: types.Duration(time.Second), RetryOn: &dynamic.RetryConditions{StatusCodes: []string{"502"}}},
+			method:             http.MethodGet,
+			retryAfter:         "0",
+			wantAttempts:       3,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "Retry-After above the maximum interval",
+			config:             dynamic.Retry{Attempts: 3, MaxInterval: types.Duration(time.Second), RetryOn: &dynamic.RetryConditions{StatusCodes: []string{"502"}}},
+			method:             http.MethodGet,
+			retryAfter:         "120",
+			wantAttempts:       1,
+			wantResponseStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			attempts := 0
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				attempts++
+
+				// The request reached the server.
+				httptrace.ContextClientTrace(req.Context()).WroteHeaders()
+
+				if attempts < 3 {
+					if test.connectionReset {
+						MarkConnectionReset(req)
+					}
+					if test.retryAfter != "" {
+						rw.Header().Set("Retry-After", test.retryAfter)
+					}
+					rw.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			retryListener := &countingRetryListener{}
+			retry, err := New(context.Background(), next, test.config, nil, retryListener, "traefikTest")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			retry.ServeHTTP(recorder, httptest.NewRequest(test.method, "http://localhost:3000/ok", test.body))
+
+			assert.Equal(t, test.wantResponseStatus, recorder.Code)
+			assert.Equal(t, test.wantAttempts, attempts)
+			assert.Equal(t, test.wantAttempts-1, retryListener.timesCalled)
+		})
+	}
+}
+
+func TestRetryBackOff(t *testing.T) {
+	var times []time.Time
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		times = append(times, time.Now())
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	config := dynamic.Retry{Attempts: 3, InitialInterval: types.Duration(50 * time.Millisecond)}
+	retry, err := New(context.Background(), next, config, nil, &countingRetryListener{}, "traefikTest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+
+	assert.Equal(t, http.StatusBadGateway, recorder.Code)
+	require.Len(t, times, 3)
+
+	// The delays are randomized by up to 50%, around 50ms and 100ms.
+	assert.GreaterOrEqual(t, int64(times[1].Sub(times[0])), int64(25*time.Millisecond))
+	assert.GreaterOrEqual(t, int64(times[2].Sub(times[1])), int64(50*time.Millisecond))
+}
+
+func TestRetryBudget(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	budget, err := NewBudget(dynamic.RetryBudget{Percent: 20})
+	require.NoError(t, err)
+
+	retryListener := &countingRetryListener{}
+	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 2}, budget, retryListener, "traefikTest")
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		retry.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+	}
+
+	// At most 20% of the 10 requests are retried.
+	assert.Equal(t, 2, retryListener.timesCalled)
+}
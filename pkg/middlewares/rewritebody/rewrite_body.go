@@ -0,0 +1,338 @@
+package rewritebody
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "RewriteBody"
+)
+
+const defaultMaxBodySize int64 = 1024 * 1024
+
+var defaultContentTypes = []string{"text/*"}
+
+type rewrite struct {
+	regex       *regexp.Regexp
+	value       []byte
+	replacement []byte
+}
+
+// rewriteBody is a middleware that applies substitutions to the bodies of the responses,
+// and optionally of the requests.
+type rewriteBody struct {
+	next         http.Handler
+	name         string
+	rewrites     []rewrite
+	request      bool
+	contentTypes [][2]string
+	maxBodySize  int64
+}
+
+// New creates a body rewrite middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RewriteBody, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Rewrites) == 0 {
+		return nil, errors.New("no rewrite defined")
+	}
+
+	rewrites := make([]rewrite, 0, len(config.Rewrites))
+	for _, rw := range config.Rewrites {
+		switch {
+		case rw.Regex != "" && rw.Value != "":
+			return nil, fmt.Errorf("a rewrite cannot define both a regex (%q) and a value (%q)", rw.Regex, rw.Value)
+		case rw.Regex != "":
+			exp, err := regexp.Compile(rw.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rewrite regex %q: %w", rw.Regex, err)
+			}
+			rewrites = append(rewrites, rewrite{regex: exp, replacement: []byte(rw.Replacement)})
+		case rw.Value != "":
+			rewrites = append(rewrites, rewrite{value: []byte(rw.Value), replacement: []byte(rw.Replacement)})
+		default:
+			return nil, errors.New("a rewrite requires a regex or a value")
+		}
+	}
+
+	mediaTypes := config.ContentTypes
+	if len(mediaTypes) == 0 {
+		mediaTypes = defaultContentTypes
+	}
+
+	contentTypes := make([][2]string, 0, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		parts := strings.Split(strings.ToLower(strings.TrimSpace(mediaType)), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid content type %q", mediaType)
+		}
+		contentTypes = append(contentTypes, [2]string{parts[0], parts[1]})
+	}
+
+	maxBodySize := config.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+
+	return &rewriteBody{
+		next:         next,
+		name:         name,
+		rewrites:     rewrites,
+		request:      config.Request,
+		contentTypes: contentTypes,
+		maxBodySize:  maxBodySize,
+	}, nil
+}
+
+func (r *rewriteBody) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *rewriteBody) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName))
+
+	if r.request {
+		if err := r.rewriteRequest(req); err != nil {
+			logger.Debugf("Error while reading request body: %v", err)
+			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// The encoded responses cannot be rewritten.
+	req.Header.Del("Accept-Encoding")
+
+	recorder := &responseRecorder{
+		rw:          rw,
+		maxBodySize: r.maxBodySize,
+		code:        http.StatusOK,
+		rewritable: func(header http.Header, code int) bool {
+			return req.Method != http.MethodHead && code != http.StatusNoContent && code != http.StatusNotModified && r.isRewritable(header)
+		},
+	}
+	r.next.ServeHTTP(recorder, req)
+
+	if recorder.streaming {
+		// The response has already been forwarded to the client.
+		return
+	}
+
+	body, changed := r.rewrite(recorder.body.Bytes())
+	if changed {
+		if tag := rw.Header().Get("ETag"); tag != "" && !strings.HasPrefix(tag, "W/") {
+			// The rewritten body is not byte-for-byte identical to the one of the service anymore.
+			rw.Header().Set("ETag", "W/"+tag)
+		}
+	}
+	rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+
+	rw.WriteHeader(recorder.code)
+	if _, err := rw.Write(body); err != nil {
+		logger.Debugf("Error while writing response: %v", err)
+	}
+}
+
+// rewriteRequest rewrites the request body, unless it is larger than the maximum body size.
+func (r *rewriteBody) rewriteRequest(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.ContentLength > r.maxBodySize || !r.isRewritable(req.Header) {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, r.maxBodySize+1))
+	if err != nil {
+		return err
+	}
+
+	if int64(len(body)) > r.maxBodySize {
+		// The body is forwarded as is, including the part which has already been read.
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+		return nil
+	}
+
+	_ = req.Body.Close()
+
+	body, _ = r.rewrite(body)
+
+	req.ContentLength = int64(len(body))
+	req.TransferEncoding = nil
+	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return nil
+}
+
+// rewrite applies the substitutions in order, and reports whether the body has changed.
+func (r *rewriteBody) rewrite(body []byte) ([]byte, bool) {
+	result := body
+	for _, rw := range r.rewrites {
+		if rw.regex != nil {
+			result = rw.regex.ReplaceAll(result, rw.replacement)
+		} else {
+			result = bytes.ReplaceAll(result, rw.value, rw.replacement)
+		}
+	}
+
+	return result, !bytes.Equal(result, body)
+}
+
+// isRewritable tells whether the body described by the headers is not encoded, and has one of the configured content types.
+func (r *rewriteBody) isRewritable(header http.Header) bool {
+	if encoding := header.Get("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	parts := strings.SplitN(mediaType, "/", 2)
+	if len(parts) != 2 {
+		return false
+	}
+
+	for _, pattern := range r.contentTypes {
+		if matchMediaType(pattern, parts[0], parts[1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchMediaType(pattern [2]string, typ, subtype string) bool {
+	if strings.HasSuffix(pattern[0], "*") {
+		if !strings.HasPrefix(typ, strings.TrimSuffix(pattern[0], "*")) {
+			return false
+		}
+	} else if pattern[0] != typ {
+		return false
+	}
+
+	if strings.HasSuffix(pattern[1], "*") {
+		return strings.HasPrefix(subtype, strings.TrimSuffix(pattern[1], "*"))
+	}
+
+	return pattern[1] == subtype
+}
+
+// responseRecorder buffers the rewritable responses up to maxBodySize,
+// and streams the other ones, and the larger ones, to the client as is.
+type responseRecorder struct {
+	rw          http.ResponseWriter
+	maxBodySize int64
+	rewritable  func(header http.Header, code int) bool
+
+	code        int
+	wroteHeader bool
+	body        bytes.Buffer
+	streaming   bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.wroteHeader {
+		return
+	}
+
+	// The informational responses are forwarded, and are followed by the final one.
+	if code >= 100 && code < 200 {
+		r.rw.WriteHeader(code)
+		return
+	}
+
+	r.wroteHeader = true
+	r.code = code
+
+	contentLength, err := strconv.ParseInt(r.rw.Header().Get("Content-Length"), 10, 64)
+	if !r.rewritable(r.rw.Header(), code) || err == nil && contentLength > r.maxBodySize {
+		r.streaming = true
+		r.rw.WriteHeader(code)
+	}
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.streaming {
+		return r.rw.Write(p)
+	}
+
+	if int64(r.body.Len()+len(p)) > r.maxBodySize {
+		if err := r.stream(); err != nil {
+			return 0, err
+		}
+		return r.rw.Write(p)
+	}
+
+	return r.body.Write(p)
+}
+
+// stream forwards the buffered response to the client as is, and disables the buffering.
+func (r *responseRecorder) stream() error {
+	if r.streaming {
+		return nil
+	}
+
+	r.streaming = true
+	r.rw.WriteHeader(r.code)
+
+	_, err := r.rw.Write(r.body.Bytes())
+	r.body.Reset()
+
+	return err
+}
+
+// Flush sends any buffered data to the client.
+// A flushed response is not rewritten.
+func (r *responseRecorder) Flush() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if err := r.stream(); err != nil {
+		return
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := r.rw.(http.Hijacker); ok {
+		r.streaming = true
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.rw)
+}
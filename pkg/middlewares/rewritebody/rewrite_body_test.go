@@ -0,0 +1,302 @@
+package rewritebody
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteBody(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.RewriteBody
+		contentType     string
+		contentEncoding string
+		etag            string
+		body            string
+		expectedBody    string
+		expectedETag    string
+	}{
+		{
+			desc: "literal value",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Value: "http://backend:8080", Replacement: "https://example.com"}},
+			},
+			contentType:  "text/html; charset=utf-8",
+			body:         `<a href="http://backend:8080/foo">foo</a>`,
+			expectedBody: `<a href="https://example.com/foo">foo</a>`,
+		},
+		{
+			desc: "regex with capture groups",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Regex: `http://backend-([a-z]+):8080`, Replacement: "https://$1.example.com"}},
+			},
+			contentType:  "text/html",
+			body:         `<a href="http://backend-api:8080/foo">foo</a>`,
+			expectedBody: `<a href="https://api.example.com/foo">foo</a>`,
+		},
+		{
+			desc: "rewrites applied in order",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{
+					{Value: "foo", Replacement: "bar"},
+					{Value: "bar", Replacement: "baz"},
+				},
+			},
+			contentType:  "text/plain",
+			body:         "foo bar",
+			expectedBody: "baz baz",
+		},
+		{
+			desc: "content type not matching",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+			},
+			contentType:  "application/json",
+			body:         `{"foo":1}`,
+			expectedBody: `{"foo":1}`,
+		},
+		{
+			desc: "content type matching a wildcard",
+			config: dynamic.RewriteBody{
+				Rewrites:     []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+				ContentTypes: []string{"application/*json"},
+			},
+			contentType:  "application/problem+json",
+			body:         `{"foo":1}`,
+			expectedBody: `{"bar":1}`,
+		},
+		{
+			desc: "no content type",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+			},
+			body:         "foo",
+			expectedBody: "foo",
+		},
+		{
+			desc: "encoded body",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+			},
+			contentType:     "text/plain",
+			contentEncoding: "br",
+			body:            "foo",
+			expectedBody:    "foo",
+		},
+		{
+			desc: "body larger than the maximum size",
+			config: dynamic.RewriteBody{
+				Rewrites:    []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+				MaxBodySize: 5,
+			},
+			contentType:  "text/plain",
+			body:         "foo foo",
+			expectedBody: "foo foo",
+		},
+		{
+			desc: "weakens the ETag of a rewritten body",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+			},
+			contentType:  "text/plain",
+			etag:         `"123"`,
+			body:         "foo",
+			expectedBody: "bar",
+			expectedETag: `W/"123"`,
+		},
+		{
+			desc: "keeps the ETag of an unchanged body",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+			},
+			contentType:  "text/plain",
+			etag:         `"123"`,
+			body:         "baz",
+			expectedBody: "baz",
+			expectedETag: `"123"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Empty(t, req.Header.Get("Accept-Encoding"))
+
+				if test.contentType != "" {
+					rw.Header().Set("Content-Type", test.contentType)
+				}
+				if test.contentEncoding != "" {
+					rw.Header().Set("Content-Encoding", test.contentEncoding)
+				}
+				if test.etag != "" {
+					rw.Header().Set("ETag", test.etag)
+				}
+				rw.Header().Set("Content-Length", strconv.Itoa(len(test.body)))
+
+				// The body is written in several chunks.
+				for _, chunk := range strings.SplitAfter(test.body, " ") {
+					_, _ = rw.Write([]byte(chunk))
+				}
+			})
+
+			handler, err := New(context.Background(), next, test.config, "foo")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, http.StatusOK, rw.Code)
+			assert.Equal(t, test.expectedBody, rw.Body.String())
+			assert.Equal(t, strconv.Itoa(len(test.expectedBody)), rw.Header().Get("Content-Length"))
+			assert.Equal(t, test.expectedETag, rw.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestRewriteBody_request(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		config       dynamic.RewriteBody
+		contentType  string
+		body         string
+		expectedBody string
+	}{
+		{
+			desc: "rewritten request",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Value: "https://example.com", Replacement: "http://backend:8080"}},
+				Request:  true,
+			},
+			contentType:  "text/xml",
+			body:         "<url>https://example.com/foo</url>",
+			expectedBody: "<url>http://backend:8080/foo</url>",
+		},
+		{
+			desc: "request rewrite disabled",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+			},
+			contentType:  "text/plain",
+			body:         "foo",
+			expectedBody: "foo",
+		},
+		{
+			desc: "request larger than the maximum size",
+			config: dynamic.RewriteBody{
+				Rewrites:    []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+				Request:     true,
+				MaxBodySize: 5,
+			},
+			contentType:  "text/plain",
+			body:         "foo foo",
+			expectedBody: "foo foo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, test.expectedBody, string(body))
+				assert.Equal(t, int64(len(test.expectedBody)), req.ContentLength)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "foo")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		})
+	}
+}
+
+func TestRewriteBody_flush(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/event-stream")
+		_, _ = rw.Write([]byte("data: foo\n\n"))
+		rw.(http.Flusher).Flush()
+		_, _ = rw.Write([]byte("data: foo\n\n"))
+	})
+
+	config := dynamic.RewriteBody{
+		Rewrites: []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+	}
+
+	handler, err := New(context.Background(), next, config, "foo")
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+	// A flushed response is streamed as is.
+	assert.True(t, rw.Flushed)
+	assert.Equal(t, "data: foo\n\ndata: foo\n\n", rw.Body.String())
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.RewriteBody
+	}{
+		{
+			desc: "no rewrite",
+		},
+		{
+			desc: "neither regex nor value",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Replacement: "foo"}},
+			},
+		},
+		{
+			desc: "both regex and value",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Regex: "foo", Value: "foo", Replacement: "bar"}},
+			},
+		},
+		{
+			desc: "invalid regex",
+			config: dynamic.RewriteBody{
+				Rewrites: []dynamic.BodyRewrite{{Regex: "(foo", Replacement: "bar"}},
+			},
+		},
+		{
+			desc: "invalid content type",
+			config: dynamic.RewriteBody{
+				Rewrites:     []dynamic.BodyRewrite{{Value: "foo", Replacement: "bar"}},
+				ContentTypes: []string{"text"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, "foo")
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package shutdown
+
+import (
+	"sync"
+	"time"
+)
+
+// Coordinator tells the routers and the services how far the shutdown of Traefik has gone.
+// The shutdown goes through stages, started by ascending shutdown order of the entry points.
+type Coordinator struct {
+	timeout time.Duration
+
+	mu        sync.RWMutex
+	order     int
+	startedAt time.Time
+	// started is closed once the first stage of the shutdown started.
+	started chan struct{}
+}
+
+// NewCoordinator creates a Coordinator, whose shutdown lasts at most the given timeout, if not zero.
+func NewCoordinator(timeout time.Duration) *Coordinator {
+	return &Coordinator{
+		timeout: timeout,
+		started: make(chan struct{}),
+	}
+}
+
+// Timeout returns the maximum duration of the whole shutdown, or zero if it is not configured.
+func (c *Coordinator) Timeout() time.Duration {
+	return c.timeout
+}
+
+// StartStage records that the shutdown reached the stage of the given order.
+func (c *Coordinator) StartStage(order int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order = order
+
+	select {
+	case <-c.started:
+	default:
+		c.startedAt = time.Now()
+		close(c.started)
+	}
+}
+
+// Reached returns whether the shutdown reached a stage whose order is greater than or equal to the given one.
+func (c *Coordinator) Reached(order int) bool {
+	select {
+	case <-c.started:
+	default:
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.order >= order
+}
+
+// Started returns a channel closed once the shutdown started.
+func (c *Coordinator) Started() <-chan struct{} {
+	return c.started
+}
+
+// Elapsed returns the time elapsed since the start of the shutdown, which must have started.
+func (c *Coordinator) Elapsed() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return time.Since(c.startedAt)
+}
@@ -0,0 +1,117 @@
+package shutdown
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+)
+
+const typeName = "Shutdown"
+
+// router is a middleware rejecting the requests of a router once the shutdown reached its stage,
+// and closing their connection so that the clients reconnect elsewhere.
+type router struct {
+	next        http.Handler
+	coordinator *Coordinator
+	order       int
+}
+
+// NewRouterMiddleware creates a shutdown middleware for a router with the given shutdown order.
+func NewRouterMiddleware(ctx context.Context, next http.Handler, coordinator *Coordinator, routerName string, order int) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, routerName, typeName)).Debug("Creating middleware")
+
+	return &router{
+		next:        next,
+		coordinator: coordinator,
+		order:       order,
+	}
+}
+
+// WrapRouterHandler wraps the shutdown middleware of a router in an alice.Constructor.
+func WrapRouterHandler(ctx context.Context, coordinator *Coordinator, routerName string, order int) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewRouterMiddleware(ctx, next, coordinator, routerName, order), nil
+	}
+}
+
+func (r *router) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !r.coordinator.Reached(r.order) {
+		r.next.ServeHTTP(rw, req)
+		return
+	}
+
+	unavailable(rw)
+}
+
+// service is a middleware canceling the requests in flight to the servers of a service
+// once its grace period has elapsed since the start of the shutdown.
+type service struct {
+	next        http.Handler
+	coordinator *Coordinator
+	gracePeriod time.Duration
+	serviceName string
+}
+
+// NewServiceMiddleware creates a shutdown middleware for a service with the given grace period.
+func NewServiceMiddleware(ctx context.Context, next http.Handler, coordinator *Coordinator, serviceName string, gracePeriod time.Duration) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, serviceName, typeName)).Debug("Creating middleware")
+
+	return &service{
+		next:        next,
+		coordinator: coordinator,
+		gracePeriod: gracePeriod,
+		serviceName: serviceName,
+	}
+}
+
+// WrapServiceHandler wraps the shutdown middleware of a service in an alice.Constructor.
+func WrapServiceHandler(ctx context.Context, coordinator *Coordinator, serviceName string, gracePeriod time.Duration) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewServiceMiddleware(ctx, next, coordinator, serviceName, gracePeriod), nil
+	}
+}
+
+func (s *service) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	select {
+	case <-s.coordinator.Started():
+		if s.coordinator.Elapsed() >= s.gracePeriod {
+			unavailable(rw)
+			return
+		}
+	default:
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-s.coordinator.Started():
+		case <-ctx.Done():
+			return
+		}
+
+		timer := time.NewTimer(s.gracePeriod - s.coordinator.Elapsed())
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			log.FromContext(middlewares.GetLoggerCtx(req.Context(), s.serviceName, typeName)).
+				Debugf("Canceling the request, the grace period of %s has elapsed since the start of the shutdown", s.gracePeriod)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	s.next.ServeHTTP(rw, req.WithContext(ctx))
+}
+
+func unavailable(rw http.ResponseWriter) {
+	rw.Header().Set("Connection", "close")
+	rw.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable)))
+}
@@ -0,0 +1,106 @@
+package shutdown
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterMiddleware(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		stages         []int
+		order          int
+		expectedStatus int
+	}{
+		{
+			desc:           "shutdown not started",
+			order:          0,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "stage of the router reached",
+			stages:         []int{0},
+			order:          0,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:           "stage after the one of the router reached",
+			stages:         []int{-1, 5},
+			order:          1,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:           "stage of the router not reached",
+			stages:         []int{-1, 0},
+			order:          1,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			coordinator := NewCoordinator(0)
+			for _, order := range test.stages {
+				coordinator.StartStage(order)
+			}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			handler := NewRouterMiddleware(context.Background(), next, coordinator, "router", test.order)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+			if test.expectedStatus == http.StatusServiceUnavailable {
+				assert.Equal(t, "close", rw.Header().Get("Connection"))
+			}
+		})
+	}
+}
+
+func TestServiceMiddleware_gracePeriod(t *testing.T) {
+	coordinator := NewCoordinator(0)
+
+	canceled := make(chan bool, 1)
+	inFlight := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		close(inFlight)
+
+		select {
+		case <-req.Context().Done():
+			canceled <- true
+		case <-time.After(5 * time.Second):
+			canceled <- false
+		}
+	})
+	handler := NewServiceMiddleware(context.Background(), next, coordinator, "service", 50*time.Millisecond)
+
+	// The requests in flight are not canceled before the start of the shutdown.
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	<-inFlight
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, canceled)
+
+	start := time.Now()
+	coordinator.StartStage(0)
+
+	assert.True(t, <-canceled)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+
+	// The requests received once the grace period has elapsed are rejected.
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	assert.Equal(t, "close", rw.Header().Get("Connection"))
+}
@@ -0,0 +1,174 @@
+// Package panics attributes the panics raised while serving the HTTP requests to the routers and middlewares they occurred in,
+// and keeps the reports of the last recovered panics, so that they can be diagnosed without scraping the logs.
+package panics
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/metrics"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+// DefaultCapacity is the default number of kept reports.
+const DefaultCapacity = 100
+
+const stackSize = 64 << 10
+
+// Panic is the value of a panic, annotated with the router and the middleware it occurred in.
+type Panic struct {
+	Value interface{}
+	// Stack is the stack trace of the goroutine, at the time of the panic.
+	Stack      []byte
+	Router     string
+	Middleware string
+}
+
+func (p *Panic) Error() string {
+	return fmt.Sprint(p.Value)
+}
+
+// FromRecovered returns the annotated panic of a recovered value.
+func FromRecovered(value interface{}) *Panic {
+	if p, ok := value.(*Panic); ok {
+		return p
+	}
+
+	buf := make([]byte, stackSize)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	return &Panic{Value: value, Stack: buf}
+}
+
+// annotator annotates the panics of a handler with the router or the middleware it belongs to.
+type annotator struct {
+	next       http.Handler
+	router     string
+	middleware string
+}
+
+// WrapRouter wraps the handler of a router, so that its panics are annotated with the router name.
+func WrapRouter(next http.Handler, routerName string) http.Handler {
+	return &annotator{next: next, router: routerName}
+}
+
+// WrapMiddleware wraps a middleware, so that its panics are annotated with the middleware name.
+// The panics of the handlers it calls are annotated by their own wrappers.
+func WrapMiddleware(next http.Handler, middlewareName string) http.Handler {
+	return &annotator{next: next, middleware: middlewareName}
+}
+
+func (a *annotator) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	defer func() {
+		if value := recover(); value != nil {
+			// http.ErrAbortHandler is the expected way to abort a response, and must be raised as is.
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+
+			p := FromRecovered(value)
+			if p.Router == "" {
+				p.Router = a.router
+			}
+			if p.Middleware == "" {
+				p.Middleware = a.middleware
+			}
+
+			panic(p)
+		}
+	}()
+
+	a.next.ServeHTTP(rw, req)
+}
+
+// RequestSummary is a summary of the request served when a panic occurred.
+type RequestSummary struct {
+	Method     string `json:"method"`
+	Host       string `json:"host"`
+	Path       string `json:"path"`
+	RemoteAddr string `json:"remoteAddr"`
+}
+
+// Report is the report of a recovered panic.
+type Report struct {
+	Time       time.Time      `json:"time"`
+	EntryPoint string         `json:"entryPoint"`
+	Router     string         `json:"router,omitempty"`
+	Middleware string         `json:"middleware,omitempty"`
+	Error      string         `json:"error"`
+	Stack      string         `json:"stack"`
+	Request    RequestSummary `json:"request"`
+}
+
+// NewReport creates the report of a panic recovered while serving the given request.
+func NewReport(entryPointName string, p *Panic, req *http.Request) Report {
+	return Report{
+		Time:       time.Now().UTC(),
+		EntryPoint: entryPointName,
+		Router:     p.Router,
+		Middleware: p.Middleware,
+		Error:      p.Error(),
+		Stack:      string(p.Stack),
+		Request: RequestSummary{
+			Method:     req.Method,
+			Host:       req.Host,
+			Path:       req.URL.Path,
+			RemoteAddr: req.RemoteAddr,
+		},
+	}
+}
+
+// Reports keeps the reports of the last recovered panics, and counts them.
+type Reports struct {
+	mu       sync.Mutex
+	capacity int
+	reports  []Report
+	// next is the index of the next report in the ring.
+	next int
+
+	counter gokitmetrics.Counter
+}
+
+// NewReports creates a new Reports keeping the given number of reports.
+func NewReports(capacity int, metricsRegistry metrics.Registry) *Reports {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+
+	return &Reports{
+		capacity: capacity,
+		counter:  metricsRegistry.RecoveredPanicsCounter(),
+	}
+}
+
+// Add records a report, replacing the oldest one when the capacity is reached.
+func (r *Reports) Add(report Report) {
+	r.counter.With("entrypoint", report.EntryPoint, "middleware", report.Middleware).Add(1)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.reports) < r.capacity {
+		r.reports = append(r.reports, report)
+		return
+	}
+
+	r.reports[r.next] = report
+	r.next = (r.next + 1) % r.capacity
+}
+
+// List returns the kept reports, the most recent first.
+func (r *Reports) List() []Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reports := make([]Report, 0, len(r.reports))
+	for i := len(r.reports) - 1; i >= 0; i-- {
+		reports = append(reports, r.reports[(r.next+i)%len(r.reports)])
+	}
+
+	return reports
+}
@@ -0,0 +1,108 @@
+package panics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		panicking          string
+		expectedRouter     string
+		expectedMiddleware string
+	}{
+		{
+			desc:               "panic in the inner middleware",
+			panicking:          "inner",
+			expectedRouter:     "router@file",
+			expectedMiddleware: "inner@file",
+		},
+		{
+			desc:               "panic in the outer middleware",
+			panicking:          "outer",
+			expectedRouter:     "router@file",
+			expectedMiddleware: "outer@file",
+		},
+		{
+			desc:           "panic in the service",
+			panicking:      "service",
+			expectedRouter: "router@file",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			service := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.panicking == "service" {
+					panic("service panic")
+				}
+			})
+
+			inner := WrapMiddleware(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.panicking == "inner" {
+					panic("inner panic")
+				}
+				service.ServeHTTP(rw, req)
+			}), "inner@file")
+
+			outer := WrapMiddleware(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.panicking == "outer" {
+					panic("outer panic")
+				}
+				inner.ServeHTTP(rw, req)
+			}), "outer@file")
+
+			handler := WrapRouter(outer, "router@file")
+
+			var recovered interface{}
+			func() {
+				defer func() { recovered = recover() }()
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			}()
+
+			p, ok := recovered.(*Panic)
+			require.True(t, ok)
+
+			assert.Equal(t, test.panicking+" panic", p.Error())
+			assert.Equal(t, test.expectedRouter, p.Router)
+			assert.Equal(t, test.expectedMiddleware, p.Middleware)
+			assert.Contains(t, string(p.Stack), "TestWrap")
+		})
+	}
+}
+
+func TestWrap_abortHandler(t *testing.T) {
+	handler := WrapRouter(WrapMiddleware(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), "middleware@file"), "router@file")
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestReports(t *testing.T) {
+	reports := NewReports(3, metrics.NewVoidRegistry())
+	assert.Empty(t, reports.List())
+
+	for i := 0; i < 5; i++ {
+		reports.Add(Report{Error: strconv.Itoa(i)})
+	}
+
+	var errs []string
+	for _, report := range reports.List() {
+		errs = append(errs, report.Error)
+	}
+
+	assert.Equal(t, []string{"4", "3", "2"}, errs)
+}
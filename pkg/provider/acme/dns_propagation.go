@@ -0,0 +1,126 @@
+package acme
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/miekg/dns"
+)
+
+const (
+	defaultPropagationRetries = 5
+	defaultPropagationBackoff = 2 * time.Second
+	maxPropagationBackoff     = time.Minute
+)
+
+// propagationChecker checks that the TXT record of a DNS-01 challenge is served by all the given authoritative nameservers.
+type propagationChecker struct {
+	nameservers []string
+	retries     int
+	backoff     time.Duration
+	client      *dns.Client
+}
+
+func newPropagationChecker(nameservers []string, retries int, backoff time.Duration) *propagationChecker {
+	if retries <= 0 {
+		retries = defaultPropagationRetries
+	}
+
+	if backoff <= 0 {
+		backoff = defaultPropagationBackoff
+	}
+
+	var servers []string
+	for _, nameserver := range nameservers {
+		if _, _, err := net.SplitHostPort(nameserver); err != nil {
+			nameserver = net.JoinHostPort(nameserver, "53")
+		}
+		servers = append(servers, nameserver)
+	}
+
+	return &propagationChecker{
+		nameservers: servers,
+		retries:     retries,
+		backoff:     backoff,
+		client:      &dns.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// check checks the propagation of the TXT record, retrying with an exponential backoff.
+func (c *propagationChecker) check(fqdn, value string) (bool, error) {
+	backoff := c.backoff
+
+	var err error
+	for attempt := 1; attempt <= c.retries; attempt++ {
+		err = c.checkOnce(fqdn, value)
+		if err == nil {
+			return true, nil
+		}
+
+		if attempt == c.retries {
+			break
+		}
+
+		log.WithoutContext().Debugf("DNS record %s not propagated yet (attempt %d/%d), checking again in %s: %v", fqdn, attempt, c.retries, backoff, err)
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > maxPropagationBackoff {
+			backoff = maxPropagationBackoff
+		}
+	}
+
+	return false, fmt.Errorf("DNS record %s not propagated after %d attempts: %w", fqdn, c.retries, err)
+}
+
+func (c *propagationChecker) checkOnce(fqdn, value string) error {
+	for _, nameserver := range c.nameservers {
+		values, err := c.queryTXT(nameserver, fqdn)
+		if err != nil {
+			return err
+		}
+
+		if !hasValue(values, value) {
+			return fmt.Errorf("nameserver %s does not serve the expected TXT record", nameserver)
+		}
+	}
+
+	return nil
+}
+
+func hasValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *propagationChecker) queryTXT(nameserver, fqdn string) ([]string, error) {
+	m := &dns.Msg{}
+	m.SetQuestion(dns.Fqdn(fqdn), dns.TypeTXT)
+	// Authoritative nameservers are asked directly, no recursion is needed.
+	m.RecursionDesired = false
+
+	resp, _, err := c.client.Exchange(m, nameserver)
+	if err != nil {
+		return nil, fmt.Errorf("exchange error for nameserver %s: %w", nameserver, err)
+	}
+
+	if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+		return nil, fmt.Errorf("nameserver %s returned %s for %s", nameserver, dns.RcodeToString[resp.Rcode], fqdn)
+	}
+
+	var values []string
+	for _, rr := range resp.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			values = append(values, strings.Join(txt.Txt, ""))
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,98 @@
+package acme
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startNameserver starts a DNS server answering the TXT queries with the given value,
+// once it has been asked the given number of times.
+func startNameserver(t *testing.T, value string, propagatedAfter int32) (string, func()) {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var queries int32
+	server := &dns.Server{
+		PacketConn: pc,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			m := &dns.Msg{}
+			m.SetReply(r)
+			m.Authoritative = true
+
+			if atomic.AddInt32(&queries, 1) > propagatedAfter {
+				m.Answer = append(m.Answer, &dns.TXT{
+					Hdr: dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+					Txt: []string{value},
+				})
+			}
+
+			_ = w.WriteMsg(m)
+		}),
+	}
+
+	started := make(chan struct{})
+	server.NotifyStartedFunc = func() { close(started) }
+
+	go func() { _ = server.ActivateAndServe() }()
+	<-started
+
+	return pc.LocalAddr().String(), func() { _ = server.Shutdown() }
+}
+
+func TestPropagationChecker(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		propagatedAfter int32
+		expected        bool
+	}{
+		{
+			desc:     "record propagated",
+			expected: true,
+		},
+		{
+			desc:            "record propagated after retries",
+			propagatedAfter: 2,
+			expected:        true,
+		},
+		{
+			desc:            "record not propagated",
+			propagatedAfter: 10,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			addr, shutdown := startNameserver(t, "token", test.propagatedAfter)
+			defer shutdown()
+
+			checker := newPropagationChecker([]string{addr}, 3, time.Millisecond)
+
+			ok, err := checker.check("_acme-challenge.example.com.", "token")
+			assert.Equal(t, test.expected, ok)
+			if test.expected {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewPropagationChecker(t *testing.T) {
+	checker := newPropagationChecker([]string{"ns1.example.com", "192.0.2.1:5353"}, 0, 0)
+
+	assert.Equal(t, []string{"ns1.example.com:53", "192.0.2.1:5353"}, checker.nameservers)
+	assert.Equal(t, defaultPropagationRetries, checker.retries)
+	assert.Equal(t, defaultPropagationBackoff, checker.backoff)
+}
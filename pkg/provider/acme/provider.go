@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -25,8 +26,12 @@ import (
 	"github.com/go-acme/lego/v3/lego"
 	"github.com/go-acme/lego/v3/providers/dns"
 	"github.com/go-acme/lego/v3/registration"
+	"golang.org/x/net/publicsuffix"
 )
 
+// maxCertificateDomains is the maximum number of domains of a certificate accepted by Let's Encrypt.
+const maxCertificateDomains = 100
+
 var (
 	// oscpMustStaple enables OSCP stapling as from https://github.com/go-acme/lego/issues/270.
 	oscpMustStaple = false
@@ -34,13 +39,15 @@ var (
 
 // Configuration holds ACME configuration provided by users.
 type Configuration struct {
-	Email         string         `description:"Email address used for registration." json:"email,omitempty" toml:"email,omitempty" yaml:"email,omitempty"`
-	CAServer      string         `description:"CA server to use." json:"caServer,omitempty" toml:"caServer,omitempty" yaml:"caServer,omitempty"`
-	Storage       string         `description:"Storage to use." json:"storage,omitempty" toml:"storage,omitempty" yaml:"storage,omitempty"`
-	KeyType       string         `description:"KeyType used for generating certificate private key. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096', 'RSA8192'." json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty"`
-	DNSChallenge  *DNSChallenge  `description:"Activate DNS-01 Challenge." json:"dnsChallenge,omitempty" toml:"dnsChallenge,omitempty" yaml:"dnsChallenge,omitempty" label:"allowEmpty"`
-	HTTPChallenge *HTTPChallenge `description:"Activate HTTP-01 Challenge." json:"httpChallenge,omitempty" toml:"httpChallenge,omitempty" yaml:"httpChallenge,omitempty" label:"allowEmpty"`
-	TLSChallenge  *TLSChallenge  `description:"Activate TLS-ALPN-01 Challenge." json:"tlsChallenge,omitempty" toml:"tlsChallenge,omitempty" yaml:"tlsChallenge,omitempty" label:"allowEmpty"`
+	Email                     string         `description:"Email address used for registration." json:"email,omitempty" toml:"email,omitempty" yaml:"email,omitempty"`
+	CAServer                  string         `description:"CA server to use." json:"caServer,omitempty" toml:"caServer,omitempty" yaml:"caServer,omitempty"`
+	Storage                   string         `description:"Storage to use." json:"storage,omitempty" toml:"storage,omitempty" yaml:"storage,omitempty"`
+	KeyType                   string         `description:"KeyType used for generating certificate private key. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096', 'RSA8192'." json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty"`
+	DNSChallenge              *DNSChallenge  `description:"Activate DNS-01 Challenge." json:"dnsChallenge,omitempty" toml:"dnsChallenge,omitempty" yaml:"dnsChallenge,omitempty" label:"allowEmpty"`
+	HTTPChallenge             *HTTPChallenge `description:"Activate HTTP-01 Challenge." json:"httpChallenge,omitempty" toml:"httpChallenge,omitempty" yaml:"httpChallenge,omitempty" label:"allowEmpty"`
+	TLSChallenge              *TLSChallenge  `description:"Activate TLS-ALPN-01 Challenge." json:"tlsChallenge,omitempty" toml:"tlsChallenge,omitempty" yaml:"tlsChallenge,omitempty" label:"allowEmpty"`
+	ReuseWildcardCertificates bool           `description:"Do not request certificates for the domains covered by an existing wildcard certificate." json:"reuseWildcardCertificates,omitempty" toml:"reuseWildcardCertificates,omitempty" yaml:"reuseWildcardCertificates,omitempty"`
+	CoalesceSANs              bool           `description:"Request a single certificate per registered domain for the domains found in the routers rules." json:"coalesceSANs,omitempty" toml:"coalesceSANs,omitempty" yaml:"coalesceSANs,omitempty"`
 }
 
 // SetDefaults sets the default values.
@@ -65,10 +72,14 @@ type Certificate struct {
 
 // DNSChallenge contains DNS challenge Configuration.
 type DNSChallenge struct {
-	Provider                string         `description:"Use a DNS-01 based challenge provider rather than HTTPS." json:"provider,omitempty" toml:"provider,omitempty" yaml:"provider,omitempty"`
-	DelayBeforeCheck        types.Duration `description:"Assume DNS propagates after a delay in seconds rather than finding and querying nameservers." json:"delayBeforeCheck,omitempty" toml:"delayBeforeCheck,omitempty" yaml:"delayBeforeCheck,omitempty"`
-	Resolvers               []string       `description:"Use following DNS servers to resolve the FQDN authority." json:"resolvers,omitempty" toml:"resolvers,omitempty" yaml:"resolvers,omitempty"`
-	DisablePropagationCheck bool           `description:"Disable the DNS propagation checks before notifying ACME that the DNS challenge is ready. [not recommended]" json:"disablePropagationCheck,omitempty" toml:"disablePropagationCheck,omitempty" yaml:"disablePropagationCheck,omitempty"`
+	Provider                 string         `description:"Use a DNS-01 based challenge provider rather than HTTPS." json:"provider,omitempty" toml:"provider,omitempty" yaml:"provider,omitempty"`
+	DelayBeforeCheck         types.Duration `description:"Assume DNS propagates after a delay in seconds rather than finding and querying nameservers." json:"delayBeforeCheck,omitempty" toml:"delayBeforeCheck,omitempty" yaml:"delayBeforeCheck,omitempty"`
+	Resolvers                []string       `description:"Use following DNS servers to resolve the FQDN authority." json:"resolvers,omitempty" toml:"resolvers,omitempty" yaml:"resolvers,omitempty"`
+	DisablePropagationCheck  bool           `description:"Disable the DNS propagation checks before notifying ACME that the DNS challenge is ready. [not recommended]" json:"disablePropagationCheck,omitempty" toml:"disablePropagationCheck,omitempty" yaml:"disablePropagationCheck,omitempty"`
+	AuthoritativeNameservers []string       `description:"Check the DNS propagation against these authoritative nameservers before notifying ACME that the DNS challenge is ready." json:"authoritativeNameservers,omitempty" toml:"authoritativeNameservers,omitempty" yaml:"authoritativeNameservers,omitempty"`
+	PropagationRetries       int            `description:"Number of DNS propagation checks against the authoritative nameservers." json:"propagationRetries,omitempty" toml:"propagationRetries,omitempty" yaml:"propagationRetries,omitempty"`
+	PropagationBackoff       types.Duration `description:"Delay before the second DNS propagation check against the authoritative nameservers, doubled after each check." json:"propagationBackoff,omitempty" toml:"propagationBackoff,omitempty" yaml:"propagationBackoff,omitempty"`
+	WildcardOnly             bool           `description:"Use the DNS-01 challenge only for the certificates with wildcard domains, and the HTTP-01 or TLS-ALPN-01 challenge for the others." json:"wildcardOnly,omitempty" toml:"wildcardOnly,omitempty" yaml:"wildcardOnly,omitempty"`
 }
 
 // HTTPChallenge contains HTTP challenge Configuration.
@@ -88,6 +99,7 @@ type Provider struct {
 	certificates           []*CertAndStore
 	account                *Account
 	client                 *lego.Client
+	wildcardClient         *lego.Client
 	certsChan              chan *CertAndStore
 	configurationChan      chan<- dynamic.Message
 	tlsManager             *traefiktls.Manager
@@ -194,20 +206,39 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 	return nil
 }
 
-func (p *Provider) getClient() (*lego.Client, error) {
+// getClient returns the client able to solve the challenges of the given domains.
+func (p *Provider) getClient(domains []string) (*lego.Client, error) {
 	p.clientMutex.Lock()
 	defer p.clientMutex.Unlock()
 
-	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".acme"))
-	logger := log.FromContext(ctx)
+	if p.client == nil {
+		if err := p.buildClients(); err != nil {
+			return nil, err
+		}
+	}
 
-	if p.client != nil {
-		return p.client, nil
+	if p.wildcardClient != nil && hasWildcardDomain(domains) {
+		return p.wildcardClient, nil
 	}
+	return p.client, nil
+}
+
+func hasWildcardDomain(domains []string) bool {
+	for _, domain := range domains {
+		if strings.HasPrefix(domain, "*.") {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Provider) buildClients() error {
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".acme"))
+	logger := log.FromContext(ctx)
 
 	account, err := p.initAccount(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	logger.Debug("Building ACME client...")
@@ -225,7 +256,7 @@ func (p *Provider) getClient() (*lego.Client, error) {
 
 	client, err := lego.NewClient(config)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// New users will need to register; be sure to save it
@@ -234,7 +265,7 @@ func (p *Provider) getClient() (*lego.Client, error) {
 
 		reg, errR := client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
 		if errR != nil {
-			return nil, errR
+			return errR
 		}
 
 		account.Registration = reg
@@ -244,37 +275,65 @@ func (p *Provider) getClient() (*lego.Client, error) {
 	// No certificate can be generated if account is not initialized
 	err = p.Store.SaveAccount(p.ResolverName, account)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if (p.DNSChallenge == nil || len(p.DNSChallenge.Provider) == 0) &&
 		(p.HTTPChallenge == nil || len(p.HTTPChallenge.EntryPoint) == 0) &&
 		p.TLSChallenge == nil {
-		return nil, errors.New("ACME challenge not specified, please select TLS or HTTP or DNS Challenge")
+		return errors.New("ACME challenge not specified, please select TLS or HTTP or DNS Challenge")
+	}
+
+	wildcardOnly := p.DNSChallenge != nil && p.DNSChallenge.WildcardOnly
+	if wildcardOnly && (p.HTTPChallenge == nil || len(p.HTTPChallenge.EntryPoint) == 0) && p.TLSChallenge == nil {
+		return errors.New("the DNS Challenge is only used for the wildcard domains, please select TLS or HTTP Challenge for the other domains")
 	}
 
+	// The clients are only set once they are fully configured, so that a failure is retried by the next call.
+	var wildcardClient *lego.Client
+
 	if p.DNSChallenge != nil && len(p.DNSChallenge.Provider) > 0 {
 		logger.Debugf("Using DNS Challenge provider: %s", p.DNSChallenge.Provider)
 
+		dnsClient := client
+		if wildcardOnly {
+			logger.Debug("Using DNS Challenge only for the wildcard domains.")
+
+			dnsClient, err = lego.NewClient(config)
+			if err != nil {
+				return err
+			}
+			wildcardClient = dnsClient
+		}
+
 		var provider challenge.Provider
 		provider, err = dns.NewDNSChallengeProviderByName(p.DNSChallenge.Provider)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		var checker *propagationChecker
+		if len(p.DNSChallenge.AuthoritativeNameservers) > 0 && !p.DNSChallenge.DisablePropagationCheck {
+			checker = newPropagationChecker(p.DNSChallenge.AuthoritativeNameservers, p.DNSChallenge.PropagationRetries, time.Duration(p.DNSChallenge.PropagationBackoff))
 		}
 
-		err = client.Challenge.SetDNS01Provider(provider,
+		err = dnsClient.Challenge.SetDNS01Provider(provider,
 			dns01.CondOption(len(p.DNSChallenge.Resolvers) > 0, dns01.AddRecursiveNameservers(p.DNSChallenge.Resolvers)),
-			dns01.CondOption(p.DNSChallenge.DisablePropagationCheck || p.DNSChallenge.DelayBeforeCheck > 0,
-				dns01.AddPreCheck(func(_, _ string) (bool, error) {
+			dns01.CondOption(p.DNSChallenge.DisablePropagationCheck || p.DNSChallenge.DelayBeforeCheck > 0 || checker != nil,
+				dns01.AddPreCheck(func(fqdn, value string) (bool, error) {
 					if p.DNSChallenge.DelayBeforeCheck > 0 {
 						log.Debugf("Delaying %d rather than validating DNS propagation now.", p.DNSChallenge.DelayBeforeCheck)
 						time.Sleep(time.Duration(p.DNSChallenge.DelayBeforeCheck))
 					}
+
+					if checker != nil {
+						return checker.check(fqdn, value)
+					}
 					return true, nil
 				})),
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
@@ -283,7 +342,7 @@ func (p *Provider) getClient() (*lego.Client, error) {
 
 		err = client.Challenge.SetHTTP01Provider(&challengeHTTP{Store: p.ChallengeStore})
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
@@ -292,12 +351,13 @@ func (p *Provider) getClient() (*lego.Client, error) {
 
 		err = client.Challenge.SetTLSALPN01Provider(&challengeTLSALPN{Store: p.ChallengeStore})
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	p.client = client
-	return p.client, nil
+	p.wildcardClient = wildcardClient
+	return nil
 }
 
 func (p *Provider) initAccount(ctx context.Context) (*Account, error) {
@@ -340,11 +400,71 @@ func (p *Provider) resolveDomains(ctx context.Context, domains []string, tlsStor
 	}
 }
 
+// filterCertifiableDomains removes the HostSNI values which cannot be the domain of a certificate:
+// the catch-all "*" and the wildcard patterns other than a leading single level wildcard.
+// The certificates for these routers have to be defined with the tls.domains option.
+func filterCertifiableDomains(ctx context.Context, domains []string) []string {
+	var result []string
+	for _, domain := range domains {
+		if domain == "*" || strings.Contains(strings.TrimPrefix(domain, "*."), "*") {
+			log.FromContext(ctx).Debugf("Skipping HostSNI %q: no certificate can be requested for it, use the tls.domains option instead", domain)
+			continue
+		}
+		result = append(result, domain)
+	}
+	return result
+}
+
+// coalesceDomains adds the domains to the given domains keyed by registered domain (eTLD+1).
+func coalesceDomains(coalesced map[string][]string, domains []string) {
+	for _, domain := range domains {
+		if domain == "*" {
+			continue
+		}
+
+		registeredDomain, err := publicsuffix.EffectiveTLDPlusOne(strings.TrimPrefix(domain, "*."))
+		if err != nil {
+			registeredDomain = domain
+		}
+
+		if !isDomainIn(domain, coalesced[registeredDomain]) {
+			coalesced[registeredDomain] = append(coalesced[registeredDomain], domain)
+		}
+	}
+}
+
+// chunkDomains sorts the domains, so that a domain is always requested in the same certificate whatever the order of the routers,
+// and splits them in chunks of at most maxCertificateDomains domains.
+func chunkDomains(domains []string) [][]string {
+	sorted := make([]string, len(domains))
+	copy(sorted, domains)
+	sort.Strings(sorted)
+
+	var chunks [][]string
+	for len(sorted) > maxCertificateDomains {
+		chunks = append(chunks, sorted[:maxCertificateDomains])
+		sorted = sorted[maxCertificateDomains:]
+	}
+	return append(chunks, sorted)
+}
+
+func isDomainIn(domain string, domains []string) bool {
+	for _, d := range domains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Provider) watchNewDomains(ctx context.Context) {
 	p.pool.GoCtx(func(ctxPool context.Context) {
 		for {
 			select {
 			case config := <-p.configFromListenerChan:
+				// Domains found in the routers rules, keyed by registered domain, when the SANs are coalesced.
+				coalescedDomains := make(map[string][]string)
+
 				if config.TCP != nil {
 					for routerName, route := range config.TCP.Routers {
 						if route.TLS == nil || route.TLS.CertResolver != p.ResolverName {
@@ -383,6 +503,12 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 								logger.Errorf("Error parsing domains in provider ACME: %v", err)
 								continue
 							}
+							domains = filterCertifiableDomains(ctxRouter, domains)
+
+							if p.CoalesceSANs {
+								coalesceDomains(coalescedDomains, domains)
+								continue
+							}
 							p.resolveDomains(ctxRouter, domains, tlsStore)
 						}
 					}
@@ -412,9 +538,32 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 							log.FromContext(ctxRouter).Errorf("Error parsing domains in provider ACME: %v", err)
 							continue
 						}
+
+						if p.CoalesceSANs {
+							coalesceDomains(coalescedDomains, domains)
+							continue
+						}
 						p.resolveDomains(ctxRouter, domains, tlsStore)
 					}
 				}
+
+				registeredDomains := make([]string, 0, len(coalescedDomains))
+				for registeredDomain := range coalescedDomains {
+					registeredDomains = append(registeredDomains, registeredDomain)
+				}
+				sort.Strings(registeredDomains)
+
+				for _, registeredDomain := range registeredDomains {
+					domains := coalescedDomains[registeredDomain]
+					if p.ReuseWildcardCertificates {
+						// The covered domains are removed before chunking, so that they don't move the other domains between the certificates.
+						domains = p.getDomainsWithoutWildcard(ctx, domains, "default")
+					}
+
+					for _, chunk := range chunkDomains(domains) {
+						p.resolveDomains(ctx, chunk, "default")
+					}
+				}
 			case <-ctxPool.Done():
 				return
 			}
@@ -428,6 +577,13 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 		return nil, err
 	}
 
+	if p.ReuseWildcardCertificates {
+		domains = p.getDomainsWithoutWildcard(ctx, domains, tlsStore)
+		if len(domains) == 0 {
+			return nil, nil
+		}
+	}
+
 	// Check provided certificates
 	uncheckedDomains := p.getUncheckedDomains(ctx, domains, tlsStore)
 	if len(uncheckedDomains) == 0 {
@@ -440,7 +596,7 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 	logger := log.FromContext(ctx)
 	logger.Debugf("Loading ACME certificates %+v...", uncheckedDomains)
 
-	client, err := p.getClient()
+	client, err := p.getClient(domains)
 	if err != nil {
 		return nil, fmt.Errorf("cannot get ACME client %w", err)
 	}
@@ -627,7 +783,7 @@ func (p *Provider) renewCertificates(ctx context.Context) {
 		// If there's an error, we assume the cert is broken, and needs update
 		// <= 30 days left, renew certificate
 		if err != nil || crt == nil || crt.NotAfter.Before(time.Now().Add(24*30*time.Hour)) {
-			client, err := p.getClient()
+			client, err := p.getClient(cert.Domain.ToStrArray())
 			if err != nil {
 				logger.Infof("Error renewing certificate from LE : %+v, %v", cert.Domain, err)
 				continue
@@ -679,6 +835,37 @@ func (p *Provider) getUncheckedDomains(ctx context.Context, domainsToCheck []str
 	return searchUncheckedDomains(ctx, domainsToCheck, allDomains)
 }
 
+// getDomainsWithoutWildcard returns the domains which are not covered by an existing wildcard certificate.
+func (p *Provider) getDomainsWithoutWildcard(ctx context.Context, domains []string, tlsStore string) []string {
+	existentDomains := p.tlsManager.GetStore(tlsStore).GetAllDomains()
+	for _, cert := range p.certificates {
+		existentDomains = append(existentDomains, strings.Join(cert.Domain.ToStrArray(), ","))
+	}
+
+	return removeWildcardCoveredDomains(ctx, domains, existentDomains)
+}
+
+func removeWildcardCoveredDomains(ctx context.Context, domains []string, existentDomains []string) []string {
+	var wildcards []string
+	for _, certDomains := range existentDomains {
+		for _, certDomain := range strings.Split(certDomains, ",") {
+			if strings.HasPrefix(certDomain, "*.") {
+				wildcards = append(wildcards, certDomain)
+			}
+		}
+	}
+
+	var result []string
+	for _, domain := range domains {
+		if isDomainAlreadyChecked(domain, wildcards) {
+			log.FromContext(ctx).Debugf("Domain %q is covered by an existing wildcard certificate, no certificate will be requested for it.", domain)
+			continue
+		}
+		result = append(result, domain)
+	}
+	return result
+}
+
 func searchUncheckedDomains(ctx context.Context, domainsToCheck []string, existentDomains []string) []string {
 	var uncheckedDomains []string
 	for _, domainToCheck := range domainsToCheck {
@@ -731,6 +918,10 @@ func (p *Provider) getValidDomains(ctx context.Context, domain types.Domain) ([]
 		if strings.HasPrefix(domain.Main, "*.*") {
 			return nil, fmt.Errorf("unable to generate a wildcard certificate in ACME provider for domain %q : ACME does not allow '*.*' wildcard domain", strings.Join(domains, ","))
 		}
+
+		if strings.HasPrefix(domain.Main, "**") {
+			return nil, fmt.Errorf("unable to generate a wildcard certificate in ACME provider for domain %q : ACME does not allow '**' wildcard domain", strings.Join(domains, ","))
+		}
 	}
 
 	var cleanDomains []string
@@ -3,12 +3,14 @@ package acme
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"testing"
 
 	"github.com/containous/traefik/v2/pkg/safe"
 	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/go-acme/lego/v3/certcrypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetUncheckedCertificates(t *testing.T) {
@@ -230,6 +232,13 @@ func TestGetValidDomain(t *testing.T) {
 			expectedErr:     "unable to generate a wildcard certificate in ACME provider for domain \"*.*.traefik.wtf,foo.traefik.wtf\" : ACME does not allow '*.*' wildcard domain",
 			expectedDomains: nil,
 		},
+		{
+			desc:            "unauthorized multi-level wildcard",
+			domains:         types.Domain{Main: "**.traefik.wtf"},
+			dnsChallenge:    &DNSChallenge{},
+			expectedErr:     "unable to generate a wildcard certificate in ACME provider for domain \"**.traefik.wtf\" : ACME does not allow '**' wildcard domain",
+			expectedDomains: nil,
+		},
 		{
 			desc:            "wildcard and SANs",
 			domains:         types.Domain{Main: "*.traefik.wtf", SANs: []string{"traefik.wtf"}},
@@ -439,6 +448,90 @@ func TestDeleteUnnecessaryDomains(t *testing.T) {
 	}
 }
 
+func TestRemoveWildcardCoveredDomains(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		domains         []string
+		existentDomains []string
+		expectedDomains []string
+	}{
+		{
+			desc:            "no wildcard certificate",
+			domains:         []string{"foo.acme.wtf", "bar.acme.wtf"},
+			existentDomains: []string{"foo.acme.wtf"},
+			expectedDomains: []string{"foo.acme.wtf", "bar.acme.wtf"},
+		},
+		{
+			desc:            "covered by a wildcard certificate",
+			domains:         []string{"foo.acme.wtf", "foo.bar.acme.wtf", "traefik.wtf"},
+			existentDomains: []string{"acme.wtf,*.acme.wtf"},
+			expectedDomains: []string{"foo.bar.acme.wtf", "traefik.wtf"},
+		},
+		{
+			desc:            "all covered",
+			domains:         []string{"foo.acme.wtf"},
+			existentDomains: []string{"*.acme.wtf"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			domains := removeWildcardCoveredDomains(context.Background(), test.domains, test.existentDomains)
+			assert.Equal(t, test.expectedDomains, domains)
+		})
+	}
+}
+
+func TestFilterCertifiableDomains(t *testing.T) {
+	domains := filterCertifiableDomains(context.Background(), []string{"*", "foo.acme.wtf", "*.acme.wtf", "**.acme.wtf", "api.*.acme.wtf"})
+
+	assert.Equal(t, []string{"foo.acme.wtf", "*.acme.wtf"}, domains)
+}
+
+func TestHasWildcardDomain(t *testing.T) {
+	assert.True(t, hasWildcardDomain([]string{"acme.wtf", "*.acme.wtf"}))
+	assert.False(t, hasWildcardDomain([]string{"acme.wtf", "foo.acme.wtf"}))
+}
+
+func TestCoalesceDomains(t *testing.T) {
+	coalesced := make(map[string][]string)
+
+	coalesceDomains(coalesced, []string{"foo.acme.wtf", "*", "bar.acme.wtf"})
+	coalesceDomains(coalesced, []string{"foo.acme.wtf", "foo.acme.co.uk", "*.bar.acme.co.uk", "localhost"})
+
+	expected := map[string][]string{
+		"acme.wtf":   {"foo.acme.wtf", "bar.acme.wtf"},
+		"acme.co.uk": {"foo.acme.co.uk", "*.bar.acme.co.uk"},
+		"localhost":  {"localhost"},
+	}
+	assert.Equal(t, expected, coalesced)
+}
+
+func TestChunkDomains(t *testing.T) {
+	var domains []string
+	for i := 0; i < maxCertificateDomains+1; i++ {
+		domains = append(domains, fmt.Sprintf("foo%03d.acme.wtf", maxCertificateDomains-i))
+	}
+
+	chunks := chunkDomains(domains)
+	require.Len(t, chunks, 2)
+	assert.Len(t, chunks[0], maxCertificateDomains)
+	assert.Equal(t, "foo000.acme.wtf", chunks[0][0])
+	assert.Equal(t, []string{"foo100.acme.wtf"}, chunks[1])
+
+	// The order of the given domains does not change the chunks.
+	reversed := make([]string, len(domains))
+	for i, domain := range domains {
+		reversed[len(domains)-1-i] = domain
+	}
+	assert.Equal(t, chunks, chunkDomains(reversed))
+
+	assert.Equal(t, [][]string{{"*.acme.wtf", "bar.acme.wtf", "foo.acme.wtf"}}, chunkDomains([]string{"foo.acme.wtf", "bar.acme.wtf", "*.acme.wtf"}))
+}
+
 func TestIsAccountMatchingCaServer(t *testing.T) {
 	testCases := []struct {
 		desc       string
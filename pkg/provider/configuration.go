@@ -3,6 +3,7 @@ package provider
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"reflect"
 	"sort"
 	"strings"
@@ -63,6 +64,14 @@ func Merge(ctx context.Context, configurations map[string]*dynamic.Configuration
 
 	for _, root := range sortedKeys {
 		conf := configurations[root]
+
+		if conf.Origins != nil {
+			if configuration.Origins == nil {
+				configuration.Origins = &dynamic.Origins{}
+			}
+			configuration.Origins.Merge(conf.Origins, nil)
+		}
+
 		for serviceName, service := range conf.HTTP.Services {
 			services[serviceName] = append(services[serviceName], root)
 			if !AddService(configuration.HTTP, serviceName, service) {
@@ -117,42 +126,63 @@ func Merge(ctx context.Context, configurations map[string]*dynamic.Configuration
 		logger.WithField(log.ServiceName, serviceName).
 			Errorf("Service defined multiple times with different configurations in %v", services[serviceName])
 		delete(configuration.HTTP.Services, serviceName)
+		if configuration.Origins != nil {
+			delete(configuration.Origins.Services, serviceName)
+		}
 	}
 
 	for routerName := range routersToDelete {
 		logger.WithField(log.RouterName, routerName).
 			Errorf("Router defined multiple times with different configurations in %v", routers[routerName])
 		delete(configuration.HTTP.Routers, routerName)
+		if configuration.Origins != nil {
+			delete(configuration.Origins.Routers, routerName)
+		}
 	}
 
 	for serviceName := range servicesTCPToDelete {
 		logger.WithField(log.ServiceName, serviceName).
 			Errorf("Service TCP defined multiple times with different configurations in %v", servicesTCP[serviceName])
 		delete(configuration.TCP.Services, serviceName)
+		if configuration.Origins != nil {
+			delete(configuration.Origins.TCPServices, serviceName)
+		}
 	}
 
 	for routerName := range routersTCPToDelete {
 		logger.WithField(log.RouterName, routerName).
 			Errorf("Router TCP defined multiple times with different configurations in %v", routersTCP[routerName])
 		delete(configuration.TCP.Routers, routerName)
+		if configuration.Origins != nil {
+			delete(configuration.Origins.TCPRouters, routerName)
+		}
 	}
 
 	for serviceName := range servicesUDPToDelete {
 		logger.WithField(log.ServiceName, serviceName).
 			Errorf("UDP service defined multiple times with different configurations in %v", servicesUDP[serviceName])
 		delete(configuration.UDP.Services, serviceName)
+		if configuration.Origins != nil {
+			delete(configuration.Origins.UDPServices, serviceName)
+		}
 	}
 
 	for routerName := range routersUDPToDelete {
 		logger.WithField(log.RouterName, routerName).
 			Errorf("UDP router defined multiple times with different configurations in %v", routersUDP[routerName])
 		delete(configuration.UDP.Routers, routerName)
+		if configuration.Origins != nil {
+			delete(configuration.Origins.UDPRouters, routerName)
+		}
 	}
 
 	for middlewareName := range middlewaresToDelete {
 		logger.WithField(log.MiddlewareName, middlewareName).
 			Errorf("Middleware defined multiple times with different configurations in %v", middlewares[middlewareName])
 		delete(configuration.HTTP.Middlewares, middlewareName)
+		if configuration.Origins != nil {
+			delete(configuration.Origins.Middlewares, middlewareName)
+		}
 	}
 
 	return configuration
@@ -255,6 +285,80 @@ func MakeDefaultRuleTemplate(defaultRule string, funcMap template.FuncMap) (*tem
 	return template.New("defaultRule").Funcs(defaultFuncMap).Parse(defaultRule)
 }
 
+// RouterDefaults holds the templates of the default values of the routers built by a provider.
+type RouterDefaults struct {
+	middlewaresTpl  []*template.Template
+	certResolverTpl *template.Template
+}
+
+// MakeRouterDefaults creates the router defaults from the templates of the default middlewares and certificate resolver.
+func MakeRouterDefaults(middlewares []string, certResolver string, funcMap template.FuncMap) (*RouterDefaults, error) {
+	defaults := &RouterDefaults{}
+
+	for _, middleware := range middlewares {
+		tpl, err := MakeDefaultRuleTemplate(middleware, funcMap)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing default middleware %q: %w", middleware, err)
+		}
+		defaults.middlewaresTpl = append(defaults.middlewaresTpl, tpl)
+	}
+
+	if certResolver != "" {
+		tpl, err := MakeDefaultRuleTemplate(certResolver, funcMap)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing default certificate resolver: %w", err)
+		}
+		defaults.certResolverTpl = tpl
+	}
+
+	return defaults, nil
+}
+
+// ApplyRouterDefaults sets the default middlewares of the routers without middlewares,
+// and the default certificate resolver of the TLS routers without certificate resolver.
+// The templates evaluating to an empty string are ignored.
+func ApplyRouterDefaults(ctx context.Context, configuration *dynamic.HTTPConfiguration, defaults *RouterDefaults, model interface{}) {
+	if defaults == nil {
+		return
+	}
+
+	for routerName, router := range configuration.Routers {
+		loggerRouter := log.FromContext(ctx).WithField(log.RouterName, routerName)
+
+		if len(router.Middlewares) == 0 {
+			for _, tpl := range defaults.middlewaresTpl {
+				middleware, err := executeTemplate(tpl, model)
+				if err != nil {
+					loggerRouter.Errorf("Error while executing default middleware template: %v", err)
+					continue
+				}
+
+				if middleware != "" {
+					router.Middlewares = append(router.Middlewares, middleware)
+				}
+			}
+		}
+
+		if router.TLS != nil && router.TLS.CertResolver == "" && defaults.certResolverTpl != nil {
+			certResolver, err := executeTemplate(defaults.certResolverTpl, model)
+			if err != nil {
+				loggerRouter.Errorf("Error while executing default certificate resolver template: %v", err)
+				continue
+			}
+
+			router.TLS.CertResolver = certResolver
+		}
+	}
+}
+
+func executeTemplate(tpl *template.Template, model interface{}) (string, error) {
+	writer := &bytes.Buffer{}
+	if err := tpl.Execute(writer, model); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(writer.String()), nil
+}
+
 // BuildTCPRouterConfiguration Builds a router configuration.
 func BuildTCPRouterConfiguration(ctx context.Context, configuration *dynamic.TCPConfiguration) {
 	for routerName, router := range configuration.Routers {
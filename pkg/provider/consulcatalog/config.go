@@ -146,6 +146,11 @@ func (p *Provider) buildUDPServiceConfiguration(ctx context.Context, item itemDa
 	}
 
 	for name, service := range configuration.Services {
+		if service.Weighted != nil {
+			// A weighted service balances between other services, and has no server of its own.
+			continue
+		}
+
 		ctxSvc := log.With(ctx, log.Str(log.ServiceName, name))
 		err := p.addServerUDP(ctxSvc, item, service.LoadBalancer)
 		if err != nil {
@@ -215,15 +220,19 @@ func (p *Provider) addServerUDP(ctx context.Context, item itemData, loadBalancer
 		return errors.New("load-balancer is not defined")
 	}
 
+	var port string
+	if len(loadBalancer.Servers) > 0 {
+		port = loadBalancer.Servers[0].Port
+	}
+
 	if len(loadBalancer.Servers) == 0 {
 		loadBalancer.Servers = []dynamic.UDPServer{{}}
 	}
 
-	var port string
-	if item.Port != "" {
+	if item.Port != "" && port == "" {
 		port = item.Port
-		loadBalancer.Servers[0].Port = ""
 	}
+	loadBalancer.Servers[0].Port = ""
 
 	if port == "" {
 		return errors.New("port is missing")
@@ -47,6 +47,7 @@ type Provider struct {
 	Cache             bool            `description:"Use local agent caching for catalog reads." json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" export:"true"`
 	ExposedByDefault  bool            `description:"Expose containers by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
 	DefaultRule       string          `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+	DryRun            bool            `description:"Validates the configuration of the provider and shows it in the API, without routing any traffic to it." json:"dryRun,omitempty" toml:"dryRun,omitempty" yaml:"dryRun,omitempty" export:"true"`
 
 	client         *api.Client
 	defaultRuleTpl *template.Template
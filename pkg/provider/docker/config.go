@@ -60,6 +60,7 @@ func (p *Provider) buildConfiguration(ctx context.Context, containersInspected [
 		if tcpOrUDP && len(confFromLabel.HTTP.Routers) == 0 &&
 			len(confFromLabel.HTTP.Middlewares) == 0 &&
 			len(confFromLabel.HTTP.Services) == 0 {
+			setOrigins(confFromLabel, container)
 			configurations[containerName] = confFromLabel
 			continue
 		}
@@ -81,13 +82,29 @@ func (p *Provider) buildConfiguration(ctx context.Context, containersInspected [
 		}
 
 		provider.BuildRouterConfiguration(ctx, confFromLabel.HTTP, serviceName, p.defaultRuleTpl, model)
+		provider.ApplyRouterDefaults(ctx, confFromLabel.HTTP, p.routerDefaults, model)
 
+		setOrigins(confFromLabel, container)
 		configurations[containerName] = confFromLabel
 	}
 
 	return provider.Merge(ctx, configurations)
 }
 
+// setOrigins records the container as the origin of the routers, services and middlewares defined by its labels.
+func setOrigins(conf *dynamic.Configuration, container dockerData) {
+	if container.ID == "" {
+		return
+	}
+
+	origin := dynamic.Origin{ContainerID: container.ID}
+
+	conf.Origins = &dynamic.Origins{}
+	conf.Origins.AddHTTP(conf.HTTP, origin)
+	conf.Origins.AddTCP(conf.TCP, origin)
+	conf.Origins.AddUDP(conf.UDP, origin)
+}
+
 func (p *Provider) buildTCPServiceConfiguration(ctx context.Context, container dockerData, configuration *dynamic.TCPConfiguration) error {
 	serviceName := getServiceName(container)
 
@@ -123,6 +140,11 @@ func (p *Provider) buildUDPServiceConfiguration(ctx context.Context, container d
 	}
 
 	for name, service := range configuration.Services {
+		if service.Weighted != nil {
+			// A weighted service balances between other services, and has no server of its own.
+			continue
+		}
+
 		ctxSvc := log.With(ctx, log.Str(log.ServiceName, name))
 		err := p.addServerUDP(ctxSvc, container, service.LoadBalancer)
 		if err != nil {
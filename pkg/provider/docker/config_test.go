@@ -4,8 +4,10 @@ import (
 	"context"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/types"
 	docker "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/go-connections/nat"
@@ -361,6 +363,146 @@ func TestDefaultRule(t *testing.T) {
 	}
 }
 
+func TestRouterDefaults(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		labels              map[string]string
+		defaultMiddlewares  []string
+		defaultCertResolver string
+		expected            map[string]*dynamic.Router
+	}{
+		{
+			desc:   "no defaults",
+			labels: map[string]string{},
+			expected: map[string]*dynamic.Router{
+				"Test": {
+					Service: "Test",
+					Rule:    "Host(`Test.traefik.wtf`)",
+				},
+			},
+		},
+		{
+			desc:               "default middlewares",
+			labels:             map[string]string{},
+			defaultMiddlewares: []string{"auth@file", "{{ .Name }}-headers@file"},
+			expected: map[string]*dynamic.Router{
+				"Test": {
+					Service:     "Test",
+					Rule:        "Host(`Test.traefik.wtf`)",
+					Middlewares: []string{"auth@file", "Test-headers@file"},
+				},
+			},
+		},
+		{
+			desc:               "default middlewares evaluating to an empty string",
+			labels:             map[string]string{},
+			defaultMiddlewares: []string{"{{ index .Labels \"middleware\" }}", "auth@file"},
+			expected: map[string]*dynamic.Router{
+				"Test": {
+					Service:     "Test",
+					Rule:        "Host(`Test.traefik.wtf`)",
+					Middlewares: []string{"auth@file"},
+				},
+			},
+		},
+		{
+			desc: "default middlewares with middlewares from labels",
+			labels: map[string]string{
+				"traefik.http.routers.Test.middlewares": "other@file",
+			},
+			defaultMiddlewares: []string{"auth@file"},
+			expected: map[string]*dynamic.Router{
+				"Test": {
+					Service:     "Test",
+					Rule:        "Host(`Test.traefik.wtf`)",
+					Middlewares: []string{"other@file"},
+				},
+			},
+		},
+		{
+			desc:                "default cert resolver without TLS",
+			labels:              map[string]string{},
+			defaultCertResolver: "myresolver",
+			expected: map[string]*dynamic.Router{
+				"Test": {
+					Service: "Test",
+					Rule:    "Host(`Test.traefik.wtf`)",
+				},
+			},
+		},
+		{
+			desc: "default cert resolver",
+			labels: map[string]string{
+				"traefik.http.routers.Test.tls": "true",
+				"resolver":                      "myresolver",
+			},
+			defaultCertResolver: "{{ index .Labels \"resolver\" }}",
+			expected: map[string]*dynamic.Router{
+				"Test": {
+					Service: "Test",
+					Rule:    "Host(`Test.traefik.wtf`)",
+					TLS:     &dynamic.RouterTLSConfig{CertResolver: "myresolver"},
+				},
+			},
+		},
+		{
+			desc: "default cert resolver with cert resolver from labels",
+			labels: map[string]string{
+				"traefik.http.routers.Test.tls.certresolver": "other",
+			},
+			defaultCertResolver: "myresolver",
+			expected: map[string]*dynamic.Router{
+				"Test": {
+					Service: "Test",
+					Rule:    "Host(`Test.traefik.wtf`)",
+					TLS:     &dynamic.RouterTLSConfig{CertResolver: "other"},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{
+				ExposedByDefault:    true,
+				DefaultRule:         "Host(`{{ normalize .Name }}.traefik.wtf`)",
+				DefaultMiddlewares:  test.defaultMiddlewares,
+				DefaultCertResolver: test.defaultCertResolver,
+			}
+
+			err := p.Init()
+			require.NoError(t, err)
+
+			container := dockerData{
+				ServiceName: "Test",
+				Name:        "Test",
+				Labels:      test.labels,
+				NetworkSettings: networkSettings{
+					Ports: nat.PortMap{
+						nat.Port("80/tcp"): []nat.PortBinding{},
+					},
+					Networks: map[string]*networkData{
+						"bridge": {
+							Name: "bridge",
+							Addr: "127.0.0.1",
+						},
+					},
+				},
+			}
+
+			container.ExtraConf, err = p.getConfiguration(container)
+			require.NoError(t, err)
+
+			configuration := p.buildConfiguration(context.Background(), []dockerData{container})
+
+			assert.Equal(t, test.expected, configuration.HTTP.Routers)
+		})
+	}
+}
+
 func Test_buildConfiguration(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -2630,6 +2772,86 @@ func Test_buildConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "udp with weighted service and load-balancer options",
+			containers: []dockerData{
+				{
+					ServiceName: "Test",
+					Name:        "Test",
+					Labels: map[string]string{
+						"traefik.udp.routers.foo.entrypoints":                      "mydns",
+						"traefik.udp.routers.foo.service":                          "wrr",
+						"traefik.udp.services.wrr.weighted.services[0].name":       "foo",
+						"traefik.udp.services.wrr.weighted.services[0].weight":     "3",
+						"traefik.udp.services.wrr.weighted.services[1].name":       "bar@file",
+						"traefik.udp.services.foo.loadbalancer.server.port":        "8080",
+						"traefik.udp.services.foo.loadbalancer.server.weight":      "2",
+						"traefik.udp.services.foo.loadbalancer.strategy":           "sourceip",
+						"traefik.udp.services.foo.loadbalancer.sourceip.port":      "true",
+						"traefik.udp.services.foo.loadbalancer.healthcheck.send":   "PING",
+						"traefik.udp.services.foo.loadbalancer.healthcheck.expect": "PONG",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/udp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"bridge": {
+								Name: "bridge",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers: map[string]*dynamic.UDPRouter{
+						"foo": {
+							Service:     "wrr",
+							EntryPoints: []string{"mydns"},
+						},
+					},
+					Services: map[string]*dynamic.UDPService{
+						"wrr": {
+							Weighted: &dynamic.UDPWeightedRoundRobin{
+								Services: []dynamic.UDPWRRService{
+									{Name: "foo", Weight: Int(3)},
+									{Name: "bar@file", Weight: Int(1)},
+								},
+							},
+						},
+						"foo": {
+							LoadBalancer: &dynamic.UDPServersLoadBalancer{
+								Strategy: "sourceip",
+								SourceIP: &dynamic.UDPSourceIP{Port: true},
+								Servers: []dynamic.UDPServer{
+									{
+										Address: "127.0.0.1:8080",
+										Weight:  2,
+									},
+								},
+								HealthCheck: &dynamic.UDPHealthCheck{
+									Interval: types.Duration(30 * time.Second),
+									Timeout:  types.Duration(5 * time.Second),
+									Send:     "PING",
+									Expect:   "PONG",
+								},
+							},
+						},
+					},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+			},
+		},
 		{
 			desc: "udp with label and port and http service",
 			containers: []dockerData{
@@ -2914,11 +3136,74 @@ func Test_buildConfiguration(t *testing.T) {
 
 			configuration := p.buildConfiguration(context.Background(), test.containers)
 
+			// The origins are checked by Test_buildConfiguration_origins.
+			configuration.Origins = nil
+
 			assert.Equal(t, test.expected, configuration)
 		})
 	}
 }
 
+func Test_buildConfiguration_origins(t *testing.T) {
+	container := func(id, addr string, labels map[string]string) dockerData {
+		return dockerData{
+			ID:          id,
+			ServiceName: "Test",
+			Name:        "Test",
+			Labels:      labels,
+			NetworkSettings: networkSettings{
+				Ports: nat.PortMap{
+					nat.Port("80/tcp"): []nat.PortBinding{},
+				},
+				Networks: map[string]*networkData{
+					"bridge": {
+						Name: "bridge",
+						Addr: addr,
+					},
+				},
+			},
+		}
+	}
+
+	containers := []dockerData{
+		container("1", "127.0.0.1", map[string]string{
+			"traefik.http.middlewares.Middleware1.inflightreq.amount": "42",
+		}),
+		container("2", "127.0.0.2", map[string]string{}),
+		container("", "127.0.0.3", map[string]string{}),
+	}
+
+	p := Provider{
+		ExposedByDefault: true,
+		DefaultRule:      "Host(`{{ normalize .Name }}.traefik.wtf`)",
+	}
+
+	err := p.Init()
+	require.NoError(t, err)
+
+	for i := 0; i < len(containers); i++ {
+		var err error
+		containers[i].ExtraConf, err = p.getConfiguration(containers[i])
+		require.NoError(t, err)
+	}
+
+	configuration := p.buildConfiguration(context.Background(), containers)
+
+	expected := &dynamic.Origins{
+		Routers: map[string][]dynamic.Origin{
+			"Test": {{ContainerID: "1"}, {ContainerID: "2"}},
+		},
+		Services: map[string][]dynamic.Origin{
+			"Test": {{ContainerID: "1"}, {ContainerID: "2"}},
+		},
+		Middlewares: map[string][]dynamic.Origin{
+			"Middleware1": {{ContainerID: "1"}},
+		},
+	}
+
+	assert.Equal(t, expected, configuration.Origins)
+}
+
 func TestDockerGetIPPort(t *testing.T) {
 	type expected struct {
 		ip    string
@@ -0,0 +1,68 @@
+package docker
+
+import (
+	"context"
+	"sort"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/client"
+)
+
+// containerCache holds the inspected containers, kept up to date from the Docker events,
+// so that an event only requires the container it is about to be inspected.
+// It is not safe for concurrent use.
+type containerCache struct {
+	containers map[string]dockerData
+}
+
+func newContainerCache(containers []dockerData) *containerCache {
+	c := &containerCache{}
+	c.reset(containers)
+	return c
+}
+
+// reset replaces the content of the cache, e.g. with the result of a full listing of the containers.
+func (c *containerCache) reset(containers []dockerData) {
+	c.containers = make(map[string]dockerData, len(containers))
+	for _, container := range containers {
+		c.containers[container.ID] = container
+	}
+}
+
+// update applies an event to the cache, and tells whether the cache changed.
+func (c *containerCache) update(ctx context.Context, p *Provider, dockerClient client.ContainerAPIClient, event eventtypes.Message) bool {
+	id := event.Actor.ID
+	if id == "" {
+		id = event.ID
+	}
+
+	if event.Action == "die" {
+		_, ok := c.containers[id]
+		delete(c.containers, id)
+		return ok
+	}
+
+	dData, ok := p.inspectContainer(ctx, dockerClient, id)
+	if !ok {
+		_, exists := c.containers[id]
+		delete(c.containers, id)
+		return exists
+	}
+
+	c.containers[id] = dData
+	return true
+}
+
+// list returns the containers of the cache, ordered by name.
+func (c *containerCache) list() []dockerData {
+	containers := make([]dockerData, 0, len(c.containers))
+	for _, container := range c.containers {
+		containers = append(containers, container)
+	}
+
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].Name < containers[j].Name
+	})
+
+	return containers
+}
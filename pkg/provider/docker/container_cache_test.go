@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	docker "github.com/docker/docker/api/types"
+	eventtypes "github.com/docker/docker/api/types/events"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeInspectClient struct {
+	dockerclient.ContainerAPIClient
+	containers map[string]docker.ContainerJSON
+	inspected  []string
+}
+
+func (c *fakeInspectClient) ContainerInspect(ctx context.Context, containerID string) (docker.ContainerJSON, error) {
+	c.inspected = append(c.inspected, containerID)
+
+	container, ok := c.containers[containerID]
+	if !ok {
+		return docker.ContainerJSON{}, errors.New("no such container")
+	}
+	return container, nil
+}
+
+func running(id string) func(*docker.ContainerJSON) {
+	return func(c *docker.ContainerJSON) {
+		c.ContainerJSONBase.ID = id
+		c.ContainerJSONBase.State = &docker.ContainerState{Running: true}
+	}
+}
+
+func TestContainerCache_update(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		cached            []dockerData
+		containers        map[string]docker.ContainerJSON
+		event             eventtypes.Message
+		expectedChanged   bool
+		expectedNames     []string
+		expectedInspected []string
+	}{
+		{
+			desc: "started container",
+			cached: []dockerData{
+				{ID: "1", Name: "bar"},
+			},
+			containers: map[string]docker.ContainerJSON{
+				"2": containerJSON(name("foo"), running("2")),
+			},
+			event:             eventtypes.Message{Action: "start", Actor: eventtypes.Actor{ID: "2"}},
+			expectedChanged:   true,
+			expectedNames:     []string{"bar", "foo"},
+			expectedInspected: []string{"2"},
+		},
+		{
+			desc: "health status of a cached container",
+			cached: []dockerData{
+				{ID: "1", Name: "foo"},
+			},
+			containers: map[string]docker.ContainerJSON{
+				"1": containerJSON(name("foo"), running("1")),
+			},
+			event:             eventtypes.Message{Action: "health_status: healthy", Actor: eventtypes.Actor{ID: "1"}},
+			expectedChanged:   true,
+			expectedNames:     []string{"foo"},
+			expectedInspected: []string{"1"},
+		},
+		{
+			desc: "died container",
+			cached: []dockerData{
+				{ID: "1", Name: "foo"},
+				{ID: "2", Name: "bar"},
+			},
+			event:           eventtypes.Message{Action: "die", Actor: eventtypes.Actor{ID: "1"}},
+			expectedChanged: true,
+			expectedNames:   []string{"bar"},
+		},
+		{
+			desc: "died unknown container",
+			cached: []dockerData{
+				{ID: "2", Name: "bar"},
+			},
+			event:         eventtypes.Message{Action: "die", Actor: eventtypes.Actor{ID: "1"}},
+			expectedNames: []string{"bar"},
+		},
+		{
+			desc: "started container already gone",
+			cached: []dockerData{
+				{ID: "1", Name: "foo"},
+			},
+			event:             eventtypes.Message{Action: "start", Actor: eventtypes.Actor{ID: "1"}},
+			expectedChanged:   true,
+			expectedNames:     []string{},
+			expectedInspected: []string{"1"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{ExposedByDefault: true}
+			client := &fakeInspectClient{containers: test.containers}
+
+			cache := newContainerCache(test.cached)
+			changed := cache.update(context.Background(), p, client, test.event)
+
+			assert.Equal(t, test.expectedChanged, changed)
+			assert.Equal(t, test.expectedInspected, client.inspected)
+
+			names := []string{}
+			for _, container := range cache.list() {
+				names = append(names, container.Name)
+			}
+			assert.Equal(t, test.expectedNames, names)
+		})
+	}
+}
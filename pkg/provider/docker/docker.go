@@ -50,13 +50,18 @@ type Provider struct {
 	Watch                   bool             `description:"Watch Docker Swarm events." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
 	Endpoint                string           `description:"Docker server endpoint. Can be a tcp or a unix socket endpoint." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
 	DefaultRule             string           `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+	DefaultMiddlewares      []string         `description:"Default middlewares templates, for the routers without middlewares." json:"defaultMiddlewares,omitempty" toml:"defaultMiddlewares,omitempty" yaml:"defaultMiddlewares,omitempty"`
+	DefaultCertResolver     string           `description:"Default certificate resolver template, for the TLS routers without certificate resolver." json:"defaultCertResolver,omitempty" toml:"defaultCertResolver,omitempty" yaml:"defaultCertResolver,omitempty"`
 	TLS                     *types.ClientTLS `description:"Enable Docker TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
 	ExposedByDefault        bool             `description:"Expose containers by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
 	UseBindPortIP           bool             `description:"Use the ip address from the bound port, rather than from the inner network." json:"useBindPortIP,omitempty" toml:"useBindPortIP,omitempty" yaml:"useBindPortIP,omitempty" export:"true"`
 	SwarmMode               bool             `description:"Use Docker on Swarm Mode." json:"swarmMode,omitempty" toml:"swarmMode,omitempty" yaml:"swarmMode,omitempty" export:"true"`
 	Network                 string           `description:"Default Docker network used." json:"network,omitempty" toml:"network,omitempty" yaml:"network,omitempty" export:"true"`
 	SwarmModeRefreshSeconds types.Duration   `description:"Polling interval for swarm mode." json:"swarmModeRefreshSeconds,omitempty" toml:"swarmModeRefreshSeconds,omitempty" yaml:"swarmModeRefreshSeconds,omitempty" export:"true"`
+	ReconciliationInterval  types.Duration   `description:"Interval of the full listing of the containers, reconciling the ones tracked from the events (0 to disable)." json:"reconciliationInterval,omitempty" toml:"reconciliationInterval,omitempty" yaml:"reconciliationInterval,omitempty" export:"true"`
+	DryRun                  bool             `description:"Validates the configuration of the provider and shows it in the API, without routing any traffic to it." json:"dryRun,omitempty" toml:"dryRun,omitempty" yaml:"dryRun,omitempty" export:"true"`
 	defaultRuleTpl          *template.Template
+	routerDefaults          *provider.RouterDefaults
 }
 
 // SetDefaults sets the default values.
@@ -66,6 +71,7 @@ func (p *Provider) SetDefaults() {
 	p.Endpoint = "unix:///var/run/docker.sock"
 	p.SwarmMode = false
 	p.SwarmModeRefreshSeconds = types.Duration(15 * time.Second)
+	p.ReconciliationInterval = types.Duration(5 * time.Minute)
 	p.DefaultRule = DefaultTemplateRule
 }
 
@@ -77,6 +83,13 @@ func (p *Provider) Init() error {
 	}
 
 	p.defaultRuleTpl = defaultRuleTpl
+
+	routerDefaults, err := provider.MakeRouterDefaults(p.DefaultMiddlewares, p.DefaultCertResolver, nil)
+	if err != nil {
+		return err
+	}
+
+	p.routerDefaults = routerDefaults
 	return nil
 }
 
@@ -274,16 +287,19 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 						Filters: f,
 					}
 
-					startStopHandle := func(m eventtypes.Message) {
-						logger.Debugf("Provider event received %+v", m)
-						containers, err := p.listContainers(ctx, dockerClient)
-						if err != nil {
-							logger.Errorf("Failed to list containers for docker, error %s", err)
-							// Call cancel to get out of the monitor
-							return
-						}
+					// The containers are tracked from the events, and fully listed again periodically,
+					// in case an event was missed.
+					cache := newContainerCache(dockerDataList)
 
-						configuration := p.buildConfiguration(ctx, containers)
+					var reconcile <-chan time.Time
+					if p.ReconciliationInterval > 0 {
+						ticker := time.NewTicker(time.Duration(p.ReconciliationInterval))
+						defer ticker.Stop()
+						reconcile = ticker.C
+					}
+
+					sendConfiguration := func() {
+						configuration := p.buildConfiguration(ctx, cache.list())
 						if configuration != nil {
 							message := dynamic.Message{
 								ProviderName:  "docker",
@@ -296,6 +312,13 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 						}
 					}
 
+					startStopHandle := func(m eventtypes.Message) {
+						logger.Debugf("Provider event received %+v", m)
+						if cache.update(ctx, p, dockerClient, m) {
+							sendConfiguration()
+						}
+					}
+
 					eventsc, errc := dockerClient.Events(ctx, options)
 					for {
 						select {
@@ -305,6 +328,15 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 								strings.HasPrefix(event.Action, "health_status") {
 								startStopHandle(event)
 							}
+						case <-reconcile:
+							containers, err := p.listContainers(ctx, dockerClient)
+							if err != nil {
+								logger.Errorf("Failed to list containers for docker, error %s", err)
+								continue
+							}
+
+							cache.reset(containers)
+							sendConfiguration()
 						case err := <-errc:
 							if err == io.EOF {
 								logger.Debug("Provider event stream closed")
@@ -340,21 +372,28 @@ func (p *Provider) listContainers(ctx context.Context, dockerClient client.Conta
 	var inspectedContainers []dockerData
 	// get inspect containers
 	for _, container := range containerList {
-		dData := inspectContainers(ctx, dockerClient, container.ID)
-		if len(dData.Name) == 0 {
-			continue
+		if dData, ok := p.inspectContainer(ctx, dockerClient, container.ID); ok {
+			inspectedContainers = append(inspectedContainers, dData)
 		}
+	}
+	return inspectedContainers, nil
+}
 
-		extraConf, err := p.getConfiguration(dData)
-		if err != nil {
-			log.FromContext(ctx).Errorf("Skip container %s: %v", getServiceName(dData), err)
-			continue
-		}
-		dData.ExtraConf = extraConf
+// inspectContainer returns the data of a running container, with its configuration parsed from its labels.
+func (p *Provider) inspectContainer(ctx context.Context, dockerClient client.ContainerAPIClient, containerID string) (dockerData, bool) {
+	dData := inspectContainers(ctx, dockerClient, containerID)
+	if len(dData.Name) == 0 {
+		return dockerData{}, false
+	}
 
-		inspectedContainers = append(inspectedContainers, dData)
+	extraConf, err := p.getConfiguration(dData)
+	if err != nil {
+		log.FromContext(ctx).Errorf("Skip container %s: %v", getServiceName(dData), err)
+		return dockerData{}, false
 	}
-	return inspectedContainers, nil
+	dData.ExtraConf = extraConf
+
+	return dData, true
 }
 
 func inspectContainers(ctx context.Context, dockerClient client.ContainerAPIClient, containerID string) dockerData {
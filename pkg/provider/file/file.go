@@ -32,6 +32,7 @@ type Provider struct {
 	Watch                     bool   `description:"Watch provider." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
 	Filename                  string `description:"Load dynamic configuration from a file." json:"filename,omitempty" toml:"filename,omitempty" yaml:"filename,omitempty" export:"true"`
 	DebugLogGeneratedTemplate bool   `description:"Enable debug logging of generated configuration template." json:"debugLogGeneratedTemplate,omitempty" toml:"debugLogGeneratedTemplate,omitempty" yaml:"debugLogGeneratedTemplate,omitempty" export:"true"`
+	DryRun                    bool   `description:"Validates the configuration of the provider and shows it in the API, without routing any traffic to it." json:"dryRun,omitempty" toml:"dryRun,omitempty" yaml:"dryRun,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -223,6 +224,7 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 				Routers:  make(map[string]*dynamic.UDPRouter),
 				Services: make(map[string]*dynamic.UDPService),
 			},
+			Origins: &dynamic.Origins{},
 		}
 	}
 
@@ -255,6 +257,7 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 		for name, conf := range c.HTTP.Routers {
 			if _, exists := configuration.HTTP.Routers[name]; exists {
 				logger.WithField(log.RouterName, name).Warn("HTTP router already configured, skipping")
+				delete(c.Origins.Routers, name)
 			} else {
 				configuration.HTTP.Routers[name] = conf
 			}
@@ -263,6 +266,7 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 		for name, conf := range c.HTTP.Middlewares {
 			if _, exists := configuration.HTTP.Middlewares[name]; exists {
 				logger.WithField(log.MiddlewareName, name).Warn("HTTP middleware already configured, skipping")
+				delete(c.Origins.Middlewares, name)
 			} else {
 				configuration.HTTP.Middlewares[name] = conf
 			}
@@ -271,6 +275,7 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 		for name, conf := range c.HTTP.Services {
 			if _, exists := configuration.HTTP.Services[name]; exists {
 				logger.WithField(log.ServiceName, name).Warn("HTTP service already configured, skipping")
+				delete(c.Origins.Services, name)
 			} else {
 				configuration.HTTP.Services[name] = conf
 			}
@@ -279,6 +284,7 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 		for name, conf := range c.TCP.Routers {
 			if _, exists := configuration.TCP.Routers[name]; exists {
 				logger.WithField(log.RouterName, name).Warn("TCP router already configured, skipping")
+				delete(c.Origins.TCPRouters, name)
 			} else {
 				configuration.TCP.Routers[name] = conf
 			}
@@ -287,6 +293,7 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 		for name, conf := range c.TCP.Services {
 			if _, exists := configuration.TCP.Services[name]; exists {
 				logger.WithField(log.ServiceName, name).Warn("TCP service already configured, skipping")
+				delete(c.Origins.TCPServices, name)
 			} else {
 				configuration.TCP.Services[name] = conf
 			}
@@ -295,6 +302,7 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 		for name, conf := range c.UDP.Routers {
 			if _, exists := configuration.UDP.Routers[name]; exists {
 				logger.WithField(log.RouterName, name).Warn("UDP router already configured, skipping")
+				delete(c.Origins.UDPRouters, name)
 			} else {
 				configuration.UDP.Routers[name] = conf
 			}
@@ -303,11 +311,14 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 		for name, conf := range c.UDP.Services {
 			if _, exists := configuration.UDP.Services[name]; exists {
 				logger.WithField(log.ServiceName, name).Warn("UDP service already configured, skipping")
+				delete(c.Origins.UDPServices, name)
 			} else {
 				configuration.UDP.Services[name] = conf
 			}
 		}
 
+		configuration.Origins.Merge(c.Origins, nil)
+
 		for _, conf := range c.TLS.Certificates {
 			if _, exists := configTLSMaps[conf]; exists {
 				logger.Warnf("TLS configuration %v already configured, skipping", conf)
@@ -436,6 +447,8 @@ func (p *Provider) decodeConfiguration(filePath string, content string) (*dynami
 		return nil, fmt.Errorf("unsupported file extension: %s", filePath)
 	}
 
+	configuration.Origins = buildOrigins(filePath, content, configuration)
+
 	return configuration, nil
 }
 
@@ -0,0 +1,164 @@
+package file
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+)
+
+// elementPath is the path of the definition of a router, a service or a middleware, e.g. [http routers foo].
+type elementPath [3]string
+
+var yamlKeyRegexp = regexp.MustCompile(`^(\s*)(-\s+)?("[^"]*"|'[^']*'|[^\s"'#:][^:#]*?)\s*:(\s|$)`)
+
+// buildOrigins returns the origins of the routers, services and middlewares of conf, defined in filename.
+// The lines are the ones of the rendered content, which are the ones of the file unless it is a template.
+func buildOrigins(filename, content string, conf *dynamic.Configuration) *dynamic.Origins {
+	origin := dynamic.Origin{File: filename}
+
+	origins := &dynamic.Origins{}
+	origins.AddHTTP(conf.HTTP, origin)
+	origins.AddTCP(conf.TCP, origin)
+	origins.AddUDP(conf.UDP, origin)
+
+	var lines map[elementPath]int
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".toml":
+		lines = tomlDefinitionLines(content)
+	case ".yml", ".yaml":
+		lines = yamlDefinitionLines(content)
+	}
+
+	sections := map[[2]string]map[string][]dynamic.Origin{
+		{"http", "routers"}:     origins.Routers,
+		{"http", "services"}:    origins.Services,
+		{"http", "middlewares"}: origins.Middlewares,
+		{"tcp", "routers"}:      origins.TCPRouters,
+		{"tcp", "services"}:     origins.TCPServices,
+		{"udp", "routers"}:      origins.UDPRouters,
+		{"udp", "services"}:     origins.UDPServices,
+	}
+
+	for section, elements := range sections {
+		for name, elementOrigins := range elements {
+			elementOrigins[0].Line = lines[elementPath{section[0], section[1], name}]
+		}
+	}
+
+	return origins
+}
+
+// tomlDefinitionLines returns the first line of the definition of the elements of a TOML content,
+// declared with a table header (e.g. [http.routers.foo]) or with a dotted key (e.g. foo.rule = "").
+func tomlDefinitionLines(content string) map[elementPath]int {
+	lines := make(map[elementPath]int)
+
+	var table []string
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var path []string
+		if strings.HasPrefix(line, "[") {
+			header := strings.TrimLeft(line, "[")
+			end := strings.Index(header, "]")
+			if end < 0 {
+				continue
+			}
+
+			table = splitTOMLKey(header[:end])
+			path = table
+		} else {
+			end := strings.Index(line, "=")
+			if end < 0 {
+				continue
+			}
+
+			path = append(append([]string{}, table...), splitTOMLKey(line[:end])...)
+		}
+
+		recordLine(lines, path, i+1)
+	}
+
+	return lines
+}
+
+// splitTOMLKey splits a dotted TOML key into its parts, which can be quoted.
+func splitTOMLKey(key string) []string {
+	var parts []string
+	var current strings.Builder
+	var quote rune
+
+	for _, r := range key {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '.':
+			parts = append(parts, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	return append(parts, strings.TrimSpace(current.String()))
+}
+
+// yamlDefinitionLines returns the first line of the definition of the elements of a YAML content,
+// in block style.
+func yamlDefinitionLines(content string) map[elementPath]int {
+	lines := make(map[elementPath]int)
+
+	type key struct {
+		indent int
+		name   string
+	}
+
+	var stack []key
+	for i, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "---" {
+			stack = nil
+			continue
+		}
+
+		match := yamlKeyRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		indent := len(match[1]) + len(match[2])
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		stack = append(stack, key{indent: indent, name: strings.Trim(match[3], `"'`)})
+
+		path := make([]string, len(stack))
+		for j, k := range stack {
+			path[j] = k.name
+		}
+
+		recordLine(lines, path, i+1)
+	}
+
+	return lines
+}
+
+func recordLine(lines map[elementPath]int, path []string, line int) {
+	if len(path) < 3 {
+		return
+	}
+
+	element := elementPath{strings.ToLower(path[0]), strings.ToLower(path[1]), path[2]}
+	if _, ok := lines[element]; !ok {
+		lines[element] = line
+	}
+}
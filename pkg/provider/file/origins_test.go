@@ -0,0 +1,94 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildOrigins(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		filename string
+		content  string
+		expected map[string]int
+	}{
+		{
+			desc:     "TOML",
+			filename: "/etc/traefik/dynamic.toml",
+			content: `
+[http.routers]
+  [http.routers.router1]
+    rule = "Host(` + "`foo.bar`" + `)"
+    service = "service1"
+
+  [http.routers."router.2"]
+    service = "service1"
+
+[http.services]
+  [http.services.service1.loadBalancer]
+    [[http.services.service1.loadBalancer.servers]]
+      url = "http://127.0.0.1"
+
+[tcp.services]
+  service1 = { loadBalancer = { terminationDelay = 42 } }
+`,
+			expected: map[string]int{"router1": 3, "router.2": 7, "service1": 11, "tcp-service1": 16},
+		},
+		{
+			desc:     "YAML",
+			filename: "/etc/traefik/dynamic.yml",
+			content: `
+http:
+  routers:
+    router1:
+      rule: Host(` + "`foo.bar`" + `)
+      service: service1
+
+    "router.2":
+      service: service1
+
+  services:
+    service1:
+      loadBalancer:
+        servers:
+          - url: http://127.0.0.1
+
+tcp:
+  services:
+    service1:
+      loadBalancer:
+        terminationDelay: 42
+`,
+			expected: map[string]int{"router1": 4, "router.2": 8, "service1": 12, "tcp-service1": 19},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{}
+			conf, err := p.decodeConfiguration(test.filename, test.content)
+			require.NoError(t, err)
+
+			expected := &dynamic.Origins{
+				Routers: map[string][]dynamic.Origin{
+					"router1":  {{File: test.filename, Line: test.expected["router1"]}},
+					"router.2": {{File: test.filename, Line: test.expected["router.2"]}},
+				},
+				Services: map[string][]dynamic.Origin{
+					"service1": {{File: test.filename, Line: test.expected["service1"]}},
+				},
+				TCPServices: map[string][]dynamic.Origin{
+					"service1": {{File: test.filename, Line: test.expected["tcp-service1"]}},
+				},
+			}
+
+			assert.Equal(t, expected, conf.Origins)
+		})
+	}
+}
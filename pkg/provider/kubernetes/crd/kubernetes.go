@@ -24,6 +24,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/mitchellh/hashstructure"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -39,17 +40,27 @@ const (
 
 // Provider holds configurations of the provider.
 type Provider struct {
-	Endpoint               string         `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
-	Token                  string         `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
-	CertAuthFilePath       string         `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
-	DisablePassHostHeaders bool           `description:"Kubernetes disable PassHost Headers." json:"disablePassHostHeaders,omitempty" toml:"disablePassHostHeaders,omitempty" yaml:"disablePassHostHeaders,omitempty" export:"true"`
-	Namespaces             []string       `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
-	LabelSelector          string         `description:"Kubernetes label selector to use." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
-	IngressClass           string         `description:"Value of kubernetes.io/ingress.class annotation to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
-	ThrottleDuration       types.Duration `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty"`
+	Endpoint               string                           `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Token                  string                           `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	CertAuthFilePath       string                           `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
+	DisablePassHostHeaders bool                             `description:"Kubernetes disable PassHost Headers." json:"disablePassHostHeaders,omitempty" toml:"disablePassHostHeaders,omitempty" yaml:"disablePassHostHeaders,omitempty" export:"true"`
+	Namespaces             []string                         `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
+	LabelSelector          string                           `description:"Kubernetes label selector to use." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
+	IngressClass           string                           `description:"Value of kubernetes.io/ingress.class annotation to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
+	ThrottleDuration       types.Duration                   `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty"`
+	Webhook                *Webhook                         `description:"Enables the validating admission webhook server for the IngressRoute and Middleware resources." json:"webhook,omitempty" toml:"webhook,omitempty" yaml:"webhook,omitempty" label:"allowEmpty" export:"true"`
+	DryRun                 bool                             `description:"Validates the configuration of the provider and shows it in the API, without routing any traffic to it." json:"dryRun,omitempty" toml:"dryRun,omitempty" yaml:"dryRun,omitempty" export:"true"`
+	NamespaceTLSDefaults   map[string]*NamespaceTLSDefaults `description:"Default TLS options and certificate resolver of the routes, per namespace." json:"namespaceTLSDefaults,omitempty" toml:"namespaceTLSDefaults,omitempty" yaml:"namespaceTLSDefaults,omitempty" export:"true"`
 	lastConfiguration      safe.Safe
 }
 
+// NamespaceTLSDefaults holds the TLS options and certificate resolver applied to the routes of a namespace
+// with a TLS section which does not set them, instead of the global defaults.
+type NamespaceTLSDefaults struct {
+	Options      string `description:"Default TLS options of the routes, as a TLSOption of the namespace or a cross-provider reference." json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty" export:"true"`
+	CertResolver string `description:"Default certificate resolver of the routes." json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
+}
+
 func (p *Provider) newK8sClient(ctx context.Context, labelSelector string) (*clientWrapper, error) {
 	labelSel, err := labels.Parse(labelSelector)
 	if err != nil {
@@ -99,6 +110,12 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 		return err
 	}
 
+	if p.Webhook != nil {
+		pool.GoCtx(func(ctxPool context.Context) {
+			p.serveWebhook(log.With(ctxPool, log.Str(log.ProviderName, providerName)), k8sClient)
+		})
+	}
+
 	pool.GoCtx(func(ctxPool context.Context) {
 		operation := func() error {
 			eventsChan, err := k8sClient.WatchAll(p.Namespaces, ctxPool.Done())
@@ -166,15 +183,17 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client) *dynamic.Configuration {
 	tlsConfigs := make(map[string]*tls.CertAndStores)
+	origins := &dynamic.Origins{}
 	conf := &dynamic.Configuration{
-		HTTP: p.loadIngressRouteConfiguration(ctx, client, tlsConfigs),
-		TCP:  p.loadIngressRouteTCPConfiguration(ctx, client, tlsConfigs),
-		UDP:  p.loadIngressRouteUDPConfiguration(ctx, client),
+		HTTP: p.loadIngressRouteConfiguration(ctx, client, tlsConfigs, origins),
+		TCP:  p.loadIngressRouteTCPConfiguration(ctx, client, tlsConfigs, origins),
+		UDP:  p.loadIngressRouteUDPConfiguration(ctx, client, origins),
 		TLS: &dynamic.TLSConfiguration{
 			Certificates: getTLSConfig(tlsConfigs),
 			Options:      buildTLSOptions(ctx, client),
 			Stores:       buildTLSStores(ctx, client),
 		},
+		Origins: origins,
 	}
 
 	for _, middleware := range client.GetMiddlewares() {
@@ -212,28 +231,42 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		}
 
 		conf.HTTP.Middlewares[id] = &dynamic.Middleware{
-			AddPrefix:         middleware.Spec.AddPrefix,
-			StripPrefix:       middleware.Spec.StripPrefix,
-			StripPrefixRegex:  middleware.Spec.StripPrefixRegex,
-			ReplacePath:       middleware.Spec.ReplacePath,
-			ReplacePathRegex:  middleware.Spec.ReplacePathRegex,
-			Chain:             createChainMiddleware(ctxMid, middleware.Namespace, middleware.Spec.Chain),
-			IPWhiteList:       middleware.Spec.IPWhiteList,
-			Headers:           middleware.Spec.Headers,
-			Errors:            errorPage,
-			RateLimit:         middleware.Spec.RateLimit,
-			RedirectRegex:     middleware.Spec.RedirectRegex,
-			RedirectScheme:    middleware.Spec.RedirectScheme,
-			BasicAuth:         basicAuth,
-			DigestAuth:        digestAuth,
-			ForwardAuth:       forwardAuth,
-			InFlightReq:       middleware.Spec.InFlightReq,
-			Buffering:         middleware.Spec.Buffering,
-			CircuitBreaker:    middleware.Spec.CircuitBreaker,
-			Compress:          middleware.Spec.Compress,
-			PassTLSClientCert: middleware.Spec.PassTLSClientCert,
-			Retry:             middleware.Spec.Retry,
+			AddPrefix:           middleware.Spec.AddPrefix,
+			StripPrefix:         middleware.Spec.StripPrefix,
+			StripPrefixRegex:    middleware.Spec.StripPrefixRegex,
+			ReplacePath:         middleware.Spec.ReplacePath,
+			ReplacePathRegex:    middleware.Spec.ReplacePathRegex,
+			Chain:               createChainMiddleware(ctxMid, middleware.Namespace, middleware.Spec.Chain),
+			IPWhiteList:         middleware.Spec.IPWhiteList,
+			Headers:             middleware.Spec.Headers,
+			Errors:              errorPage,
+			RateLimit:           middleware.Spec.RateLimit,
+			RedirectRegex:       middleware.Spec.RedirectRegex,
+			RedirectScheme:      middleware.Spec.RedirectScheme,
+			BasicAuth:           basicAuth,
+			DigestAuth:          digestAuth,
+			ForwardAuth:         forwardAuth,
+			InFlightReq:         middleware.Spec.InFlightReq,
+			Buffering:           middleware.Spec.Buffering,
+			CircuitBreaker:      middleware.Spec.CircuitBreaker,
+			Compress:            middleware.Spec.Compress,
+			PassTLSClientCert:   middleware.Spec.PassTLSClientCert,
+			Retry:               middleware.Spec.Retry,
+			ETag:                middleware.Spec.ETag,
+			Aggregate:           middleware.Spec.Aggregate,
+			Introspection:       middleware.Spec.Introspection,
+			ClientCertPolicy:    middleware.Spec.ClientCertPolicy,
+			AdaptiveConcurrency: middleware.Spec.AdaptiveConcurrency,
+			CORS:                middleware.Spec.CORS,
+			ResourceHints:       middleware.Spec.ResourceHints,
+			OIDC:                middleware.Spec.OIDC,
+			JWT:                 middleware.Spec.JWT,
+			Cache:               middleware.Spec.Cache,
+			RewriteBody:         middleware.Spec.RewriteBody,
+			Optional:            middleware.Spec.Optional,
 		}
+
+		origins.AddHTTP(conf.HTTP, makeOrigin("Middleware", middleware.ObjectMeta))
 	}
 
 	cb := configBuilder{client}
@@ -244,6 +277,8 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 				Errorf("Error while building TraefikService: %v", err)
 			continue
 		}
+
+		origins.AddHTTP(conf.HTTP, makeOrigin("TraefikService", service.ObjectMeta))
 	}
 
 	return conf
@@ -625,6 +660,32 @@ func makeServiceKey(rule, ingressName string) (string, error) {
 	return key, nil
 }
 
+// tlsDefaults returns the default TLS options, already qualified, and certificate resolver of the routes of the namespace.
+func (p *Provider) tlsDefaults(namespace string) (options, certResolver string) {
+	defaults, ok := p.NamespaceTLSDefaults[namespace]
+	if !ok || defaults == nil {
+		return "", ""
+	}
+
+	options = defaults.Options
+	if options != "" && !strings.Contains(options, providerNamespaceSeparator) {
+		options = makeID(namespace, options)
+	}
+
+	return options, defaults.CertResolver
+}
+
+// makeOrigin returns the origin of the routers, services and middlewares defined by a Kubernetes object.
+func makeOrigin(kind string, object metav1.ObjectMeta) dynamic.Origin {
+	return dynamic.Origin{
+		Kind:            kind,
+		Namespace:       object.Namespace,
+		Name:            object.Name,
+		UID:             string(object.UID),
+		ResourceVersion: object.ResourceVersion,
+	}
+}
+
 func makeID(namespace, name string) string {
 	if namespace == "" {
 		return name
@@ -20,7 +20,7 @@ const (
 	httpProtocol       = "http"
 )
 
-func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores) *dynamic.HTTPConfiguration {
+func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores, origins *dynamic.Origins) *dynamic.HTTPConfiguration {
 	conf := &dynamic.HTTPConfiguration{
 		Routers:     map[string]*dynamic.Router{},
 		Middlewares: map[string]*dynamic.Middleware{},
@@ -118,11 +118,14 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 			}
 
 			conf.Routers[normalized] = &dynamic.Router{
-				Middlewares: mds,
-				Priority:    route.Priority,
-				EntryPoints: ingressRoute.Spec.EntryPoints,
-				Rule:        route.Match,
-				Service:     serviceName,
+				Middlewares:         mds,
+				Priority:            route.Priority,
+				EntryPoints:         ingressRoute.Spec.EntryPoints,
+				Rule:                route.Match,
+				Service:             serviceName,
+				DebugHeaders:        route.DebugHeaders,
+				Quota:               route.Quota,
+				KeepResponseHeaders: route.KeepResponseHeaders,
 			}
 
 			if ingressRoute.Spec.TLS != nil {
@@ -148,9 +151,20 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 
 					tlsConf.Options = tlsOptionsName
 				}
+
+				defaultOptions, defaultCertResolver := p.tlsDefaults(ingressRoute.Namespace)
+				if tlsConf.Options == "" {
+					tlsConf.Options = defaultOptions
+				}
+				if tlsConf.CertResolver == "" {
+					tlsConf.CertResolver = defaultCertResolver
+				}
+
 				conf.Routers[normalized].TLS = tlsConf
 			}
 		}
+
+		origins.AddHTTP(conf, makeOrigin("IngressRoute", ingressRoute.ObjectMeta))
 	}
 
 	return conf
@@ -243,6 +257,7 @@ func (c configBuilder) buildMirroring(ctx context.Context, tService *v1alpha1.Tr
 			Service:     fullNameMain,
 			Mirrors:     mirrorServices,
 			MaxBodySize: tService.Spec.Mirroring.MaxBodySize,
+			Streaming:   tService.Spec.Mirroring.Streaming,
 		},
 	}
 
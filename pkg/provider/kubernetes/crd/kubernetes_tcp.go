@@ -13,7 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores) *dynamic.TCPConfiguration {
+func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores, origins *dynamic.Origins) *dynamic.TCPConfiguration {
 	conf := &dynamic.TCPConfiguration{
 		Routers:  map[string]*dynamic.TCPRouter{},
 		Services: map[string]*dynamic.TCPService{},
@@ -92,6 +92,7 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 			conf.Routers[serviceName] = &dynamic.TCPRouter{
 				EntryPoints: ingressRouteTCP.Spec.EntryPoints,
 				Rule:        route.Match,
+				Priority:    route.Priority,
 				Service:     serviceName,
 			}
 
@@ -102,6 +103,14 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 					Domains:      ingressRouteTCP.Spec.TLS.Domains,
 				}
 
+				if !ingressRouteTCP.Spec.TLS.Passthrough {
+					defaultOptions, defaultCertResolver := p.tlsDefaults(ingressRouteTCP.Namespace)
+					conf.Routers[serviceName].TLS.Options = defaultOptions
+					if conf.Routers[serviceName].TLS.CertResolver == "" {
+						conf.Routers[serviceName].TLS.CertResolver = defaultCertResolver
+					}
+				}
+
 				if ingressRouteTCP.Spec.TLS.Options == nil || len(ingressRouteTCP.Spec.TLS.Options.Name) == 0 {
 					continue
 				}
@@ -123,6 +132,8 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 				conf.Routers[serviceName].TLS.Options = tlsOptionsName
 			}
 		}
+
+		origins.AddTCP(conf, makeOrigin("IngressRouteTCP", ingressRouteTCP.ObjectMeta))
 	}
 
 	return conf
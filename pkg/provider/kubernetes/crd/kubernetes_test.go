@@ -19,10 +19,11 @@ func Bool(v bool) *bool { return &v }
 
 func TestLoadIngressRouteTCPs(t *testing.T) {
 	testCases := []struct {
-		desc         string
-		ingressClass string
-		paths        []string
-		expected     *dynamic.Configuration
+		desc                 string
+		ingressClass         string
+		namespaceTLSDefaults map[string]*NamespaceTLSDefaults
+		paths                []string
+		expected             *dynamic.Configuration
 	}{
 		{
 			desc: "Empty",
@@ -815,6 +816,54 @@ func TestLoadIngressRouteTCPs(t *testing.T) {
 				TLS: &dynamic.TLSConfiguration{},
 			},
 		},
+		{
+			desc:  "TLS with the defaults of the namespace",
+			paths: []string{"tcp/services.yml", "tcp/with_tls_acme.yml"},
+			namespaceTLSDefaults: map[string]*NamespaceTLSDefaults{
+				"default": {Options: "foo@file", CertResolver: "default-resolver"},
+			},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers: map[string]*dynamic.TCPRouter{
+						"default-test.route-fdd3e9338e47a45efefc": {
+							EntryPoints: []string{"foo"},
+							Service:     "default-test.route-fdd3e9338e47a45efefc",
+							Rule:        "HostSNI(`foo.com`)",
+							TLS: &dynamic.RouterTCPTLSConfig{
+								Options:      "foo@file",
+								CertResolver: "default-resolver",
+							},
+						},
+					},
+					Services: map[string]*dynamic.TCPService{
+						"default-test.route-fdd3e9338e47a45efefc": {
+							LoadBalancer: &dynamic.TCPServersLoadBalancer{
+								Servers: []dynamic.TCPServer{
+									{
+										Address: "10.10.0.1:8000",
+										Port:    "",
+									},
+									{
+										Address: "10.10.0.2:8000",
+										Port:    "",
+									},
+								},
+							},
+						},
+					},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
 		{
 			desc:  "TCP with terminationDelay",
 			paths: []string{"tcp/services.yml", "tcp/with_termination_delay.yml"},
@@ -1020,8 +1069,12 @@ func TestLoadIngressRouteTCPs(t *testing.T) {
 				return
 			}
 
-			p := Provider{IngressClass: test.ingressClass}
+			p := Provider{IngressClass: test.ingressClass, NamespaceTLSDefaults: test.namespaceTLSDefaults}
 			conf := p.loadConfigurationFromCRD(context.Background(), newClientMock(test.paths...))
+
+			// The origins are checked by TestLoadIngressRoutes_origins.
+			conf.Origins = nil
+
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -1029,10 +1082,11 @@ func TestLoadIngressRouteTCPs(t *testing.T) {
 
 func TestLoadIngressRoutes(t *testing.T) {
 	testCases := []struct {
-		desc         string
-		ingressClass string
-		paths        []string
-		expected     *dynamic.Configuration
+		desc                 string
+		ingressClass         string
+		namespaceTLSDefaults map[string]*NamespaceTLSDefaults
+		paths                []string
+		expected             *dynamic.Configuration
 	}{
 		{
 			desc: "Empty",
@@ -2657,6 +2711,55 @@ func TestLoadIngressRoutes(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:  "TLS with the defaults of the namespace",
+			paths: []string{"services.yml", "with_tls_acme.yml"},
+			namespaceTLSDefaults: map[string]*NamespaceTLSDefaults{
+				"default": {Options: "strict", CertResolver: "default-resolver"},
+				"other":   {Options: "foo@file", CertResolver: "other-resolver"},
+			},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"default-test-route-6b204d94623b3df4370c": {
+							EntryPoints: []string{"web"},
+							Service:     "default-test-route-6b204d94623b3df4370c",
+							Rule:        "Host(`foo.com`) && PathPrefix(`/bar`)",
+							Priority:    12,
+							TLS: &dynamic.RouterTLSConfig{
+								Options:      "default-strict",
+								CertResolver: "default-resolver",
+							},
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"default-test-route-6b204d94623b3df4370c": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.1:80",
+									},
+									{
+										URL: "http://10.10.0.2:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc:  "Simple Ingress Route, defaulting to https for servers",
 			paths: []string{"services.yml", "with_https_default.yml"},
@@ -3102,13 +3205,73 @@ func TestLoadIngressRoutes(t *testing.T) {
 				return
 			}
 
-			p := Provider{IngressClass: test.ingressClass}
+			p := Provider{IngressClass: test.ingressClass, NamespaceTLSDefaults: test.namespaceTLSDefaults}
 			conf := p.loadConfigurationFromCRD(context.Background(), newClientMock(test.paths...))
+
+			// The origins are checked by TestLoadIngressRoutes_origins.
+			conf.Origins = nil
+
 			assert.Equal(t, test.expected, conf)
 		})
 	}
 }
 
+func TestLoadIngressRoutes_origins(t *testing.T) {
+	p := Provider{}
+	conf := p.loadConfigurationFromCRD(context.Background(), newClientMock("services.yml", "with_middleware.yml", "tcp/services.yml", "tcp/simple.yml"))
+
+	expected := &dynamic.Origins{
+		Routers: map[string][]dynamic.Origin{
+			"default-test2-route-23c7f4c450289ee29016": {{
+				Kind:            "IngressRoute",
+				Namespace:       "default",
+				Name:            "test2.route",
+				UID:             "8f3c1e5a-7a0b-4a43-bf0e-3d2f5e1c9a77",
+				ResourceVersion: "1043",
+			}},
+		},
+		Services: map[string][]dynamic.Origin{
+			"default-test2-route-23c7f4c450289ee29016": {{
+				Kind:            "IngressRoute",
+				Namespace:       "default",
+				Name:            "test2.route",
+				UID:             "8f3c1e5a-7a0b-4a43-bf0e-3d2f5e1c9a77",
+				ResourceVersion: "1043",
+			}},
+		},
+		Middlewares: map[string][]dynamic.Origin{
+			"default-stripprefix": {{
+				Kind:            "Middleware",
+				Namespace:       "default",
+				Name:            "stripprefix",
+				UID:             "2c4ea3ee-1d4b-4d0e-9b55-5f6fd0d3c1a2",
+				ResourceVersion: "1042",
+			}},
+			"foo-addprefix": {{
+				Kind:      "Middleware",
+				Namespace: "foo",
+				Name:      "addprefix",
+			}},
+		},
+		TCPRouters: map[string][]dynamic.Origin{
+			"default-test.route-fdd3e9338e47a45efefc": {{
+				Kind:      "IngressRouteTCP",
+				Namespace: "default",
+				Name:      "test.route",
+			}},
+		},
+		TCPServices: map[string][]dynamic.Origin{
+			"default-test.route-fdd3e9338e47a45efefc": {{
+				Kind:      "IngressRouteTCP",
+				Namespace: "default",
+				Name:      "test.route",
+			}},
+		},
+	}
+
+	assert.Equal(t, expected, conf.Origins)
+}
+
 func TestLoadIngressRouteUDPs(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -3412,6 +3575,10 @@ func TestLoadIngressRouteUDPs(t *testing.T) {
 
 			p := Provider{IngressClass: test.ingressClass}
 			conf := p.loadConfigurationFromCRD(context.Background(), newClientMock(test.paths...))
+
+			// The origins are checked by TestLoadIngressRoutes_origins.
+			conf.Origins = nil
+
 			assert.Equal(t, test.expected, conf)
 		})
 	}
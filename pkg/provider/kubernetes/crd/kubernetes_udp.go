@@ -11,7 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client Client) *dynamic.UDPConfiguration {
+func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client Client, origins *dynamic.Origins) *dynamic.UDPConfiguration {
 	conf := &dynamic.UDPConfiguration{
 		Routers:  map[string]*dynamic.UDPRouter{},
 		Services: map[string]*dynamic.UDPService{},
@@ -70,6 +70,8 @@ func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client
 				Service:     serviceName,
 			}
 		}
+
+		origins.AddUDP(conf, makeOrigin("IngressRouteUDP", ingressRouteUDP.ObjectMeta))
 	}
 
 	return conf
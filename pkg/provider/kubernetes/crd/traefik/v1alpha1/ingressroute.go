@@ -15,21 +15,24 @@ type IngressRouteSpec struct {
 
 // Route contains the set of routes.
 type Route struct {
-	Match       string          `json:"match"`
-	Kind        string          `json:"kind"`
-	Priority    int             `json:"priority"`
-	Services    []Service       `json:"services,omitempty"`
-	Middlewares []MiddlewareRef `json:"middlewares"`
+	Match               string               `json:"match"`
+	Kind                string               `json:"kind"`
+	Priority            int                  `json:"priority"`
+	Services            []Service            `json:"services,omitempty"`
+	Middlewares         []MiddlewareRef      `json:"middlewares"`
+	DebugHeaders        bool                 `json:"debugHeaders,omitempty"`
+	Quota               *dynamic.RouterQuota `json:"quota,omitempty"`
+	KeepResponseHeaders []string             `json:"keepResponseHeaders,omitempty"`
 }
 
 // TLS contains the TLS certificates configuration of the routes.
 // To enable Let's Encrypt, use an empty TLS struct,
 // e.g. in YAML:
 //
-//	 tls: {} # inline format
+//	tls: {} # inline format
 //
-//	 tls:
-//	   secretName: # block format
+//	tls:
+//	  secretName: # block format
 type TLS struct {
 	// SecretName is the name of the referenced Kubernetes Secret to specify the
 	// certificate details.
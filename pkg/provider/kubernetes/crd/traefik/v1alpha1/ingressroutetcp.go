@@ -15,6 +15,7 @@ type IngressRouteTCPSpec struct {
 // RouteTCP contains the set of routes.
 type RouteTCP struct {
 	Match    string       `json:"match"`
+	Priority int          `json:"priority,omitempty"`
 	Services []ServiceTCP `json:"services,omitempty"`
 }
 
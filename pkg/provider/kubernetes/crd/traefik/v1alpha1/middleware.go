@@ -20,28 +20,40 @@ type Middleware struct {
 
 // MiddlewareSpec holds the Middleware configuration.
 type MiddlewareSpec struct {
-	AddPrefix         *dynamic.AddPrefix         `json:"addPrefix,omitempty"`
-	StripPrefix       *dynamic.StripPrefix       `json:"stripPrefix,omitempty"`
-	StripPrefixRegex  *dynamic.StripPrefixRegex  `json:"stripPrefixRegex,omitempty"`
-	ReplacePath       *dynamic.ReplacePath       `json:"replacePath,omitempty"`
-	ReplacePathRegex  *dynamic.ReplacePathRegex  `json:"replacePathRegex,omitempty"`
-	Chain             *Chain                     `json:"chain,omitempty"`
-	IPWhiteList       *dynamic.IPWhiteList       `json:"ipWhiteList,omitempty"`
-	Headers           *dynamic.Headers           `json:"headers,omitempty"`
-	Errors            *ErrorPage                 `json:"errors,omitempty"`
-	RateLimit         *dynamic.RateLimit         `json:"rateLimit,omitempty"`
-	RedirectRegex     *dynamic.RedirectRegex     `json:"redirectRegex,omitempty"`
-	RedirectScheme    *dynamic.RedirectScheme    `json:"redirectScheme,omitempty"`
-	BasicAuth         *BasicAuth                 `json:"basicAuth,omitempty"`
-	DigestAuth        *DigestAuth                `json:"digestAuth,omitempty"`
-	ForwardAuth       *ForwardAuth               `json:"forwardAuth,omitempty"`
-	InFlightReq       *dynamic.InFlightReq       `json:"inFlightReq,omitempty"`
-	Buffering         *dynamic.Buffering         `json:"buffering,omitempty"`
-	CircuitBreaker    *dynamic.CircuitBreaker    `json:"circuitBreaker,omitempty"`
-	Compress          *dynamic.Compress          `json:"compress,omitempty"`
-	PassTLSClientCert *dynamic.PassTLSClientCert `json:"passTLSClientCert,omitempty"`
-	Retry             *dynamic.Retry             `json:"retry,omitempty"`
-	ContentType       *dynamic.ContentType       `json:"contentType,omitempty"`
+	AddPrefix           *dynamic.AddPrefix           `json:"addPrefix,omitempty"`
+	StripPrefix         *dynamic.StripPrefix         `json:"stripPrefix,omitempty"`
+	StripPrefixRegex    *dynamic.StripPrefixRegex    `json:"stripPrefixRegex,omitempty"`
+	ReplacePath         *dynamic.ReplacePath         `json:"replacePath,omitempty"`
+	ReplacePathRegex    *dynamic.ReplacePathRegex    `json:"replacePathRegex,omitempty"`
+	Chain               *Chain                       `json:"chain,omitempty"`
+	IPWhiteList         *dynamic.IPWhiteList         `json:"ipWhiteList,omitempty"`
+	Headers             *dynamic.Headers             `json:"headers,omitempty"`
+	Errors              *ErrorPage                   `json:"errors,omitempty"`
+	RateLimit           *dynamic.RateLimit           `json:"rateLimit,omitempty"`
+	RedirectRegex       *dynamic.RedirectRegex       `json:"redirectRegex,omitempty"`
+	RedirectScheme      *dynamic.RedirectScheme      `json:"redirectScheme,omitempty"`
+	BasicAuth           *BasicAuth                   `json:"basicAuth,omitempty"`
+	DigestAuth          *DigestAuth                  `json:"digestAuth,omitempty"`
+	ForwardAuth         *ForwardAuth                 `json:"forwardAuth,omitempty"`
+	InFlightReq         *dynamic.InFlightReq         `json:"inFlightReq,omitempty"`
+	Buffering           *dynamic.Buffering           `json:"buffering,omitempty"`
+	CircuitBreaker      *dynamic.CircuitBreaker      `json:"circuitBreaker,omitempty"`
+	Compress            *dynamic.Compress            `json:"compress,omitempty"`
+	PassTLSClientCert   *dynamic.PassTLSClientCert   `json:"passTLSClientCert,omitempty"`
+	Retry               *dynamic.Retry               `json:"retry,omitempty"`
+	ContentType         *dynamic.ContentType         `json:"contentType,omitempty"`
+	ETag                *dynamic.ETag                `json:"etag,omitempty"`
+	Aggregate           *dynamic.Aggregate           `json:"aggregate,omitempty"`
+	Introspection       *dynamic.Introspection       `json:"introspection,omitempty"`
+	ClientCertPolicy    *dynamic.ClientCertPolicy    `json:"clientCertPolicy,omitempty"`
+	AdaptiveConcurrency *dynamic.AdaptiveConcurrency `json:"adaptiveConcurrency,omitempty"`
+	CORS                *dynamic.CORS                `json:"cors,omitempty"`
+	ResourceHints       *dynamic.ResourceHints       `json:"resourceHints,omitempty"`
+	OIDC                *dynamic.OIDC                `json:"oidc,omitempty"`
+	JWT                 *dynamic.JWT                 `json:"jwt,omitempty"`
+	Cache               *dynamic.Cache               `json:"cache,omitempty"`
+	RewriteBody         *dynamic.RewriteBody         `json:"rewriteBody,omitempty"`
+	Optional            bool                         `json:"optional,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -45,7 +45,8 @@ type ServiceSpec struct {
 type Mirroring struct {
 	LoadBalancerSpec
 	MaxBodySize *int64
-	Mirrors     []MirrorService `json:"mirrors,omitempty"`
+	Streaming   *dynamic.MirroringStreaming `json:"streaming,omitempty"`
+	Mirrors     []MirrorService             `json:"mirrors,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -680,13 +680,68 @@ func (in *MiddlewareSpec) DeepCopyInto(out *MiddlewareSpec) {
 	if in.Retry != nil {
 		in, out := &in.Retry, &out.Retry
 		*out = new(dynamic.Retry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ContentType != nil {
 		in, out := &in.ContentType, &out.ContentType
 		*out = new(dynamic.ContentType)
 		**out = **in
 	}
+	if in.ETag != nil {
+		in, out := &in.ETag, &out.ETag
+		*out = new(dynamic.ETag)
+		**out = **in
+	}
+	if in.Aggregate != nil {
+		in, out := &in.Aggregate, &out.Aggregate
+		*out = new(dynamic.Aggregate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Introspection != nil {
+		in, out := &in.Introspection, &out.Introspection
+		*out = new(dynamic.Introspection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertPolicy != nil {
+		in, out := &in.ClientCertPolicy, &out.ClientCertPolicy
+		*out = new(dynamic.ClientCertPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdaptiveConcurrency != nil {
+		in, out := &in.AdaptiveConcurrency, &out.AdaptiveConcurrency
+		*out = new(dynamic.AdaptiveConcurrency)
+		**out = **in
+	}
+	if in.CORS != nil {
+		in, out := &in.CORS, &out.CORS
+		*out = new(dynamic.CORS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceHints != nil {
+		in, out := &in.ResourceHints, &out.ResourceHints
+		*out = new(dynamic.ResourceHints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(dynamic.OIDC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(dynamic.JWT)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(dynamic.Cache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RewriteBody != nil {
+		in, out := &in.RewriteBody, &out.RewriteBody
+		*out = new(dynamic.RewriteBody)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -726,6 +781,11 @@ func (in *Mirroring) DeepCopyInto(out *Mirroring) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Streaming != nil {
+		in, out := &in.Streaming, &out.Streaming
+		*out = new(dynamic.MirroringStreaming)
+		**out = **in
+	}
 	if in.Mirrors != nil {
 		in, out := &in.Mirrors, &out.Mirrors
 		*out = make([]MirrorService, len(*in))
@@ -761,6 +821,16 @@ func (in *Route) DeepCopyInto(out *Route) {
 		*out = make([]MiddlewareRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(dynamic.RouterQuota)
+		**out = **in
+	}
+	if in.KeepResponseHeaders != nil {
+		in, out := &in.KeepResponseHeaders, &out.KeepResponseHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -0,0 +1,283 @@
+package crd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/containous/traefik/v2/pkg/rules"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Webhook holds the configuration of the validating admission webhook server.
+type Webhook struct {
+	Address  string `description:"Address the validating webhook server listens on." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	CertFile string `description:"TLS certificate of the validating webhook server." json:"certFile,omitempty" toml:"certFile,omitempty" yaml:"certFile,omitempty"`
+	KeyFile  string `description:"TLS key of the validating webhook server." json:"keyFile,omitempty" toml:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+
+	RejectMissingReferences bool `description:"Rejects the objects referencing a service or a middleware which does not exist, instead of only logging them." json:"rejectMissingReferences,omitempty" toml:"rejectMissingReferences,omitempty" yaml:"rejectMissingReferences,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (w *Webhook) SetDefaults() {
+	w.Address = ":8443"
+}
+
+// webhookHandler validates the IngressRoute and Middleware objects submitted to the Kubernetes API server,
+// and checks their references against the resources of the cluster known by the client.
+type webhookHandler struct {
+	client Client
+	// rejectMissingReferences rejects the objects referencing a missing resource, which are otherwise only logged,
+	// as the referenced resource is often applied along with, or right after, the object referencing it.
+	rejectMissingReferences bool
+}
+
+func (p *Provider) serveWebhook(ctx context.Context, client Client) {
+	logger := log.FromContext(ctx)
+
+	server := &http.Server{
+		Addr:    p.Webhook.Address,
+		Handler: webhookHandler{client: client, rejectMissingReferences: p.Webhook.RejectMissingReferences},
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		ctxShutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(ctxShutdown); err != nil {
+			logger.Errorf("Error while stopping the validating webhook server: %v", err)
+		}
+	}()
+
+	logger.Infof("Starting the validating webhook server on %s", p.Webhook.Address)
+
+	err := server.ListenAndServeTLS(p.Webhook.CertFile, p.Webhook.KeyFile)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Errorf("Error while running the validating webhook server: %v", err)
+	}
+}
+
+func (h webhookHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review := admissionv1.AdmissionReview{}
+	if err = json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(rw, "invalid admission review", http.StatusBadRequest)
+		return
+	}
+
+	response := &admissionv1.AdmissionResponse{
+		UID:     review.Request.UID,
+		Allowed: true,
+	}
+
+	if err := h.validate(req.Context(), review.Request); err != nil {
+		log.FromContext(req.Context()).Debugf("Rejecting %s %s/%s: %v", review.Request.Kind.Kind, review.Request.Namespace, review.Request.Name, err)
+
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		}
+	}
+
+	review.Response = response
+	review.Request = nil
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(review); err != nil {
+		log.FromContext(req.Context()).Errorf("Unable to write the admission review: %v", err)
+	}
+}
+
+func (h webhookHandler) validate(ctx context.Context, req *admissionv1.AdmissionRequest) error {
+	if req.Operation == admissionv1.Delete {
+		return nil
+	}
+
+	switch req.Kind.Kind {
+	case "IngressRoute":
+		ingressRoute := &v1alpha1.IngressRoute{}
+		if err := json.Unmarshal(req.Object.Raw, ingressRoute); err != nil {
+			return fmt.Errorf("unable to decode the IngressRoute: %w", err)
+		}
+		if ingressRoute.Namespace == "" {
+			ingressRoute.Namespace = req.Namespace
+		}
+
+		return h.validateIngressRoute(log.With(ctx, log.Str("ingress", ingressRoute.Name), log.Str("namespace", ingressRoute.Namespace)), ingressRoute)
+
+	case "Middleware":
+		middleware := &v1alpha1.Middleware{}
+		if err := json.Unmarshal(req.Object.Raw, middleware); err != nil {
+			return fmt.Errorf("unable to decode the Middleware: %w", err)
+		}
+		if middleware.Namespace == "" {
+			middleware.Namespace = req.Namespace
+		}
+
+		return h.validateMiddleware(log.With(ctx, log.Str("middleware", middleware.Name), log.Str("namespace", middleware.Namespace)), middleware)
+
+	default:
+		return nil
+	}
+}
+
+func (h webhookHandler) validateIngressRoute(ctx context.Context, ingressRoute *v1alpha1.IngressRoute) error {
+	for i, route := range ingressRoute.Spec.Routes {
+		if route.Kind != "Rule" {
+			return fmt.Errorf("routes[%d]: unsupported match kind %q, only \"Rule\" is supported", i, route.Kind)
+		}
+
+		if err := validateRule(route.Match, route.Priority); err != nil {
+			return fmt.Errorf("routes[%d]: %w", i, err)
+		}
+
+		for _, service := range route.Services {
+			if err := h.checkServiceRef(ctx, ingressRoute.Namespace, service.LoadBalancerSpec); err != nil {
+				return fmt.Errorf("routes[%d]: %w", i, err)
+			}
+		}
+
+		for _, mi := range route.Middlewares {
+			if err := h.checkMiddlewareRef(ctx, ingressRoute.Namespace, mi); err != nil {
+				return fmt.Errorf("routes[%d]: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (h webhookHandler) validateMiddleware(ctx context.Context, middleware *v1alpha1.Middleware) error {
+	raw, err := json.Marshal(middleware.Spec)
+	if err != nil {
+		return err
+	}
+
+	if string(raw) == "{}" {
+		return errors.New("no middleware type is configured")
+	}
+
+	if middleware.Spec.Chain != nil {
+		for _, mi := range middleware.Spec.Chain.Middlewares {
+			if err := h.checkMiddlewareRef(ctx, middleware.Namespace, mi); err != nil {
+				return fmt.Errorf("chain: %w", err)
+			}
+		}
+	}
+
+	if middleware.Spec.Errors != nil {
+		if err := h.checkServiceRef(ctx, middleware.Namespace, middleware.Spec.Errors.Service.LoadBalancerSpec); err != nil {
+			return fmt.Errorf("errors: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func validateRule(rule string, priority int) error {
+	if len(rule) == 0 {
+		return errors.New("empty match rule")
+	}
+
+	if err := checkStringQuoteValidity(rule); err != nil {
+		return fmt.Errorf("invalid syntax for match rule %q", rule)
+	}
+
+	router, err := rules.NewRouter()
+	if err != nil {
+		return err
+	}
+
+	if err := router.AddRoute(rule, priority, http.NotFoundHandler()); err != nil {
+		return fmt.Errorf("invalid match rule %q: %w", rule, err)
+	}
+
+	return nil
+}
+
+// checkServiceRef checks the kind of the Kubernetes Service or TraefikService referenced by a load-balancer spec,
+// and whether it exists.
+// The references to the services of other providers cannot be checked, and are accepted.
+func (h webhookHandler) checkServiceRef(ctx context.Context, namespace string, service v1alpha1.LoadBalancerSpec) error {
+	if strings.Contains(service.Name, providerNamespaceSeparator) && !strings.HasSuffix(service.Name, providerNamespaceSeparator+providerName) {
+		return nil
+	}
+
+	ns := namespaceOrFallback(service, namespace)
+	name := strings.TrimSuffix(service.Name, providerNamespaceSeparator+providerName)
+
+	switch service.Kind {
+	case "", "Service":
+		_, exists, err := h.client.GetService(ns, name)
+		if err != nil {
+			return h.missingReference(ctx, err)
+		}
+		if !exists {
+			return h.missingReference(ctx, fmt.Errorf("kubernetes service not found: %s/%s", ns, name))
+		}
+
+	case "TraefikService":
+		_, exists, err := h.client.GetTraefikService(ns, name)
+		if err != nil {
+			return h.missingReference(ctx, err)
+		}
+		if !exists {
+			return h.missingReference(ctx, fmt.Errorf("traefik service not found: %s/%s", ns, name))
+		}
+
+	default:
+		return fmt.Errorf("unsupported service kind %q", service.Kind)
+	}
+
+	return nil
+}
+
+// checkMiddlewareRef checks whether a referenced middleware exists.
+// The references to the middlewares of other providers cannot be checked, and are accepted.
+func (h webhookHandler) checkMiddlewareRef(ctx context.Context, namespace string, ref v1alpha1.MiddlewareRef) error {
+	if strings.Contains(ref.Name, providerNamespaceSeparator) {
+		return nil
+	}
+
+	ns := ref.Namespace
+	if len(ns) == 0 {
+		ns = namespace
+	}
+
+	for _, middleware := range h.client.GetMiddlewares() {
+		if middleware.Namespace == ns && middleware.Name == ref.Name {
+			return nil
+		}
+	}
+
+	return h.missingReference(ctx, fmt.Errorf("middleware not found: %s/%s", ns, ref.Name))
+}
+
+// missingReference returns the error of a reference to a resource which could not be found,
+// or only logs it when the webhook does not reject the missing references.
+func (h webhookHandler) missingReference(ctx context.Context, err error) error {
+	if h.rejectMissingReferences {
+		return err
+	}
+
+	log.FromContext(ctx).Warnf("Accepting a reference which cannot be resolved yet: %v", err)
+	return nil
+}
@@ -0,0 +1,261 @@
+package crd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestWebhook_ingressRoute(t *testing.T) {
+	testCases := []struct {
+		desc                    string
+		routes                  []v1alpha1.Route
+		rejectMissingReferences bool
+		expectedAllow           bool
+	}{
+		{
+			desc: "valid route",
+			routes: []v1alpha1.Route{{
+				Match:       "Host(`foo.com`) && PathPrefix(`/bar`)",
+				Kind:        "Rule",
+				Services:    []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: 80}}},
+				Middlewares: []v1alpha1.MiddlewareRef{{Name: "stripprefix"}, {Name: "addprefix", Namespace: "foo"}},
+			}},
+			expectedAllow: true,
+		},
+		{
+			desc: "traefik service",
+			routes: []v1alpha1.Route{{
+				Match:    "Host(`foo.com`)",
+				Kind:     "Rule",
+				Services: []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "mirror1", Kind: "TraefikService"}}},
+			}},
+			expectedAllow: true,
+		},
+		{
+			desc: "cross-provider references",
+			routes: []v1alpha1.Route{{
+				Match:       "Host(`foo.com`)",
+				Kind:        "Rule",
+				Services:    []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "api@internal", Kind: "TraefikService"}}},
+				Middlewares: []v1alpha1.MiddlewareRef{{Name: "auth@file"}},
+			}},
+			expectedAllow: true,
+		},
+		{
+			desc: "invalid rule",
+			routes: []v1alpha1.Route{{
+				Match:    "Hos(`foo.com`)",
+				Kind:     "Rule",
+				Services: []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: 80}}},
+			}},
+		},
+		{
+			desc: "empty rule",
+			routes: []v1alpha1.Route{{
+				Kind:     "Rule",
+				Services: []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: 80}}},
+			}},
+		},
+		{
+			desc: "wrong rule kind",
+			routes: []v1alpha1.Route{{
+				Match:    "Host(`foo.com`)",
+				Kind:     "Wrong",
+				Services: []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: 80}}},
+			}},
+		},
+		{
+			desc: "unsupported service kind",
+			routes: []v1alpha1.Route{{
+				Match:    "Host(`foo.com`)",
+				Kind:     "Rule",
+				Services: []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Kind: "Wrong"}}},
+			}},
+		},
+		{
+			desc: "unknown kubernetes service",
+			routes: []v1alpha1.Route{{
+				Match:    "Host(`foo.com`)",
+				Kind:     "Rule",
+				Services: []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "unknown", Port: 80}}},
+			}},
+			expectedAllow: true,
+		},
+		{
+			desc: "unknown kubernetes service, rejecting the missing references",
+			routes: []v1alpha1.Route{{
+				Match:    "Host(`foo.com`)",
+				Kind:     "Rule",
+				Services: []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "unknown", Port: 80}}},
+			}},
+			rejectMissingReferences: true,
+		},
+		{
+			desc: "unknown traefik service, rejecting the missing references",
+			routes: []v1alpha1.Route{{
+				Match:    "Host(`foo.com`)",
+				Kind:     "Rule",
+				Services: []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "unknown", Kind: "TraefikService"}}},
+			}},
+			rejectMissingReferences: true,
+		},
+		{
+			desc: "middleware in another namespace",
+			routes: []v1alpha1.Route{{
+				Match:       "Host(`foo.com`)",
+				Kind:        "Rule",
+				Services:    []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: 80}}},
+				Middlewares: []v1alpha1.MiddlewareRef{{Name: "addprefix"}},
+			}},
+			expectedAllow: true,
+		},
+		{
+			desc: "middleware in another namespace, rejecting the missing references",
+			routes: []v1alpha1.Route{{
+				Match:       "Host(`foo.com`)",
+				Kind:        "Rule",
+				Services:    []v1alpha1.Service{{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: 80}}},
+				Middlewares: []v1alpha1.MiddlewareRef{{Name: "addprefix"}},
+			}},
+			rejectMissingReferences: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ingressRoute := &v1alpha1.IngressRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       v1alpha1.IngressRouteSpec{Routes: test.routes},
+			}
+
+			response := review(t, "IngressRoute", ingressRoute, test.rejectMissingReferences)
+
+			assert.Equal(t, test.expectedAllow, response.Allowed)
+			if !test.expectedAllow {
+				require.NotNil(t, response.Result)
+				assert.NotEmpty(t, response.Result.Message)
+			}
+		})
+	}
+}
+
+func TestWebhook_middleware(t *testing.T) {
+	testCases := []struct {
+		desc                    string
+		spec                    v1alpha1.MiddlewareSpec
+		rejectMissingReferences bool
+		expectedAllow           bool
+	}{
+		{
+			desc:          "valid middleware",
+			spec:          v1alpha1.MiddlewareSpec{AddPrefix: &dynamic.AddPrefix{Prefix: "/foo"}},
+			expectedAllow: true,
+		},
+		{
+			desc: "valid chain",
+			spec: v1alpha1.MiddlewareSpec{Chain: &v1alpha1.Chain{
+				Middlewares: []v1alpha1.MiddlewareRef{{Name: "stripprefix"}, {Name: "auth@file"}},
+			}},
+			expectedAllow: true,
+		},
+		{
+			desc: "empty middleware",
+			spec: v1alpha1.MiddlewareSpec{},
+		},
+		{
+			desc: "chain with an unknown middleware",
+			spec: v1alpha1.MiddlewareSpec{Chain: &v1alpha1.Chain{
+				Middlewares: []v1alpha1.MiddlewareRef{{Name: "unknown"}},
+			}},
+			expectedAllow: true,
+		},
+		{
+			desc: "chain with an unknown middleware, rejecting the missing references",
+			spec: v1alpha1.MiddlewareSpec{Chain: &v1alpha1.Chain{
+				Middlewares: []v1alpha1.MiddlewareRef{{Name: "unknown"}},
+			}},
+			rejectMissingReferences: true,
+		},
+		{
+			desc: "error page with an unknown service, rejecting the missing references",
+			spec: v1alpha1.MiddlewareSpec{Errors: &v1alpha1.ErrorPage{
+				Status:  []string{"500-599"},
+				Service: v1alpha1.Service{LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "unknown", Port: 80}},
+			}},
+			rejectMissingReferences: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			middleware := &v1alpha1.Middleware{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       test.spec,
+			}
+
+			response := review(t, "Middleware", middleware, test.rejectMissingReferences)
+
+			assert.Equal(t, test.expectedAllow, response.Allowed)
+		})
+	}
+}
+
+func TestWebhook_invalidReview(t *testing.T) {
+	handler := webhookHandler{client: newClientMock()}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString("{}")))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func review(t *testing.T, kind string, obj interface{}, rejectMissingReferences bool) *admissionv1.AdmissionResponse {
+	t.Helper()
+
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	body, err := json.Marshal(admissionv1.AdmissionReview{
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "uid",
+			Kind:      metav1.GroupVersionKind{Group: "traefik.containo.us", Version: "v1alpha1", Kind: kind},
+			Namespace: "default",
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	})
+	require.NoError(t, err)
+
+	handler := webhookHandler{
+		client:                  newClientMock("services.yml", "with_middleware.yml", "with_mirroring.yml"),
+		rejectMissingReferences: rejectMissingReferences,
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var result admissionv1.AdmissionReview
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	require.NotNil(t, result.Response)
+	assert.Equal(t, "uid", string(result.Response.UID))
+
+	return result.Response
+}
@@ -43,6 +43,7 @@ type Provider struct {
 	IngressClass           string           `description:"Value of kubernetes.io/ingress.class annotation to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
 	IngressEndpoint        *EndpointIngress `description:"Kubernetes Ingress Endpoint." json:"ingressEndpoint,omitempty" toml:"ingressEndpoint,omitempty" yaml:"ingressEndpoint,omitempty"`
 	ThrottleDuration       types.Duration   `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty"`
+	DryRun                 bool             `description:"Validates the configuration of the provider and shows it in the API, without routing any traffic to it." json:"dryRun,omitempty" toml:"dryRun,omitempty" yaml:"dryRun,omitempty" export:"true"`
 	lastConfiguration      safe.Safe
 }
 
@@ -179,7 +180,8 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 			Middlewares: map[string]*dynamic.Middleware{},
 			Services:    map[string]*dynamic.Service{},
 		},
-		TCP: &dynamic.TCPConfiguration{},
+		TCP:     &dynamic.TCPConfiguration{},
+		Origins: &dynamic.Origins{},
 	}
 
 	ingresses := client.GetIngresses()
@@ -271,6 +273,14 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 				conf.HTTP.Routers[routerKey] = loadRouter(rule, pa, rtConfig, serviceName)
 			}
 		}
+
+		conf.Origins.AddHTTP(conf.HTTP, dynamic.Origin{
+			Kind:            "Ingress",
+			Namespace:       ingress.Namespace,
+			Name:            ingress.Name,
+			UID:             string(ingress.UID),
+			ResourceVersion: ingress.ResourceVersion,
+		})
 	}
 
 	certs := getTLSConfig(certConfigs)
@@ -1039,11 +1039,44 @@ func TestLoadConfigurationFromIngresses(t *testing.T) {
 			p := Provider{IngressClass: test.ingressClass}
 			conf := p.loadConfigurationFromIngresses(context.Background(), clientMock)
 
+			// The origins are checked by TestLoadConfigurationFromIngresses_origins.
+			conf.Origins = nil
+
 			assert.Equal(t, test.expected, conf)
 		})
 	}
 }
 
+func TestLoadConfigurationFromIngresses_origins(t *testing.T) {
+	desc := "Ingress one rule with one path and one host"
+	clientMock := newClientMock(
+		generateTestFilename("_ingress", desc),
+		generateTestFilename("_endpoint", desc),
+		generateTestFilename("_service", desc),
+	)
+
+	p := Provider{}
+	conf := p.loadConfigurationFromIngresses(context.Background(), clientMock)
+
+	origin := dynamic.Origin{
+		Kind:            "Ingress",
+		Namespace:       "testing",
+		UID:             "5b1b2a8e-52cd-4a8b-a7e3-6c0f4c7d2e10",
+		ResourceVersion: "2207",
+	}
+
+	expected := &dynamic.Origins{
+		Routers: map[string][]dynamic.Origin{
+			"testing-traefik-tchouk-bar": {origin},
+		},
+		Services: map[string][]dynamic.Origin{
+			"testing-service1-80": {origin},
+		},
+	}
+
+	assert.Equal(t, expected, conf.Origins)
+}
+
 func generateTestFilename(suffix, desc string) string {
 	return "./fixtures/" + strings.ReplaceAll(desc, " ", "-") + suffix + ".yml"
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/abronan/valkeyrie"
@@ -24,12 +25,14 @@ import (
 
 // Provider holds configurations of the provider.
 type Provider struct {
-	RootKey string `description:"Root key used for KV store" export:"true" json:"rootKey,omitempty" toml:"rootKey,omitempty" yaml:"rootKey,omitempty"`
+	RootKey    string `description:"Root key used for KV store" export:"true" json:"rootKey,omitempty" toml:"rootKey,omitempty" yaml:"rootKey,omitempty"`
+	VersionKey string `description:"Key, relative to the root key, holding the version of the configuration to apply, which is read under the root key followed by this version." export:"true" json:"versionKey,omitempty" toml:"versionKey,omitempty" yaml:"versionKey,omitempty"`
 
 	Endpoints []string         `description:"KV store endpoints" json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty"`
 	Username  string           `description:"KV Username" json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
 	Password  string           `description:"KV Password" json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
 	TLS       *types.ClientTLS `description:"Enable TLS support" export:"true" json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
+	DryRun    bool             `description:"Validates the configuration of the provider and shows it in the API, without routing any traffic to it." json:"dryRun,omitempty" toml:"dryRun,omitempty" yaml:"dryRun,omitempty" export:"true"`
 
 	storeType store.Backend
 	kvClient  store.Store
@@ -81,7 +84,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 	configuration, err := p.buildConfiguration()
 	if err != nil {
 		logger.Errorf("Cannot build the configuration: %v", err)
-	} else {
+	} else if configuration != nil {
 		configurationChan <- dynamic.Message{
 			ProviderName:  p.name,
 			Configuration: configuration,
@@ -149,6 +152,17 @@ func (p *Provider) buildConfiguration() (*dynamic.Configuration, error) {
 		return nil, err
 	}
 
+	if p.VersionKey != "" {
+		pairs, err = p.versionedPairs(pairs)
+		if err != nil {
+			return nil, err
+		}
+
+		if pairs == nil {
+			return nil, nil
+		}
+	}
+
 	cfg := &dynamic.Configuration{}
 	err = kv.Decode(pairs, cfg, p.RootKey)
 	if err != nil {
@@ -158,6 +172,47 @@ func (p *Provider) buildConfiguration() (*dynamic.Configuration, error) {
 	return cfg, nil
 }
 
+// versionedPairs returns the pairs of the version of the configuration referenced by the version key,
+// with their keys rewritten relatively to the root key.
+// As the pairs come from the same listing, the configuration is consistent as long as the writers
+// fill in a new version entirely before updating the version key.
+func (p *Provider) versionedPairs(pairs []*store.KVPair) ([]*store.KVPair, error) {
+	versionKey := path.Join(p.RootKey, p.VersionKey)
+
+	var version string
+	for _, pair := range pairs {
+		if pair.Key == versionKey {
+			version = strings.TrimSpace(string(pair.Value))
+			break
+		}
+	}
+
+	if version == "" {
+		log.WithoutContext().WithField(log.ProviderName, p.name).Debugf("The version key %q is not set, skipping the configuration", versionKey)
+		return nil, nil
+	}
+
+	prefix := path.Join(p.RootKey, version) + "/"
+	if prefix == versionKey+"/" {
+		return nil, fmt.Errorf("the version %q cannot be the version key", version)
+	}
+
+	result := make([]*store.KVPair, 0, len(pairs))
+	for _, pair := range pairs {
+		if !strings.HasPrefix(pair.Key, prefix) {
+			continue
+		}
+
+		result = append(result, &store.KVPair{
+			Key:       path.Join(p.RootKey, strings.TrimPrefix(pair.Key, prefix)),
+			Value:     pair.Value,
+			LastIndex: pair.LastIndex,
+		})
+	}
+
+	return result, nil
+}
+
 func (p *Provider) createKVClient(ctx context.Context) (store.Store, error) {
 	storeConfig := &store.Config{
 		ConnectionTimeout: 3 * time.Second,
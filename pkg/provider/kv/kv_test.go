@@ -875,6 +875,67 @@ func Test_buildConfiguration(t *testing.T) {
 	assert.Equal(t, expected, cfg)
 }
 
+func Test_buildConfiguration_versionKey(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		pairs    map[string]string
+		expected *dynamic.Configuration
+	}{
+		{
+			desc: "version key set",
+			pairs: map[string]string{
+				"traefik/version":                         "v2",
+				"traefik/v1/http/routers/Router0/rule":    "Host(`old.localhost`)",
+				"traefik/v1/http/routers/Router0/service": "Service0",
+				"traefik/v2/http/routers/Router0/rule":    "Host(`new.localhost`)",
+				"traefik/v2/http/routers/Router0/service": "Service0",
+				"traefik/v3/http/routers/Router0/rule":    "Host(`partial.localhost`)",
+			},
+			expected: &dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"Router0": {
+							Rule:    "Host(`new.localhost`)",
+							Service: "Service0",
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "version key not set",
+			pairs: map[string]string{
+				"traefik/v1/http/routers/Router0/rule":    "Host(`old.localhost`)",
+				"traefik/v1/http/routers/Router0/service": "Service0",
+			},
+		},
+		{
+			desc: "version without keys",
+			pairs: map[string]string{
+				"traefik/version":                         "v3",
+				"traefik/v1/http/routers/Router0/rule":    "Host(`old.localhost`)",
+				"traefik/v1/http/routers/Router0/service": "Service0",
+			},
+			expected: &dynamic.Configuration{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := newProviderMock(mapToPairs(test.pairs))
+			provider.VersionKey = "version"
+
+			cfg, err := provider.buildConfiguration()
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, cfg)
+		})
+	}
+}
+
 func Test_buildConfiguration_KV_error(t *testing.T) {
 	provider := &Provider{
 		RootKey: "traefik",
@@ -205,6 +205,11 @@ func (p *Provider) buildUDPServiceConfiguration(ctx context.Context, app maratho
 	}
 
 	for serviceName, service := range conf.Services {
+		if service.Weighted != nil {
+			// A weighted service balances between other services, and has no server of its own.
+			continue
+		}
+
 		var servers []dynamic.UDPServer
 
 		defaultServer := dynamic.UDPServer{}
@@ -299,6 +304,7 @@ func (p *Provider) getUDPServer(app marathon.Application, task marathon.Task, ex
 
 	server := dynamic.UDPServer{
 		Address: net.JoinHostPort(host, port),
+		Weight:  defaultServer.Weight,
 	}
 
 	return server, nil
@@ -119,6 +119,11 @@ func (p *Provider) buildUDPServiceConfiguration(ctx context.Context, service ran
 	}
 
 	for _, confService := range configuration.Services {
+		if confService.Weighted != nil {
+			// A weighted service balances between other services, and has no server of its own.
+			continue
+		}
+
 		err := p.addServerUDP(ctx, service, confService.LoadBalancer)
 		if err != nil {
 			return err
@@ -223,9 +228,11 @@ func (p *Provider) addServerUDP(ctx context.Context, service rancherData, loadBa
 	log.FromContext(ctx).Debugf("Trying to add servers for service  %s \n", service.Name)
 
 	serverPort := ""
+	serverWeight := 0
 
 	if loadBalancer != nil && len(loadBalancer.Servers) > 0 {
 		serverPort = loadBalancer.Servers[0].Port
+		serverWeight = loadBalancer.Servers[0].Weight
 	}
 
 	port := getServicePort(service)
@@ -249,6 +256,7 @@ func (p *Provider) addServerUDP(ctx context.Context, service rancherData, loadBa
 	for _, containerIP := range service.Containers {
 		servers = append(servers, dynamic.UDPServer{
 			Address: net.JoinHostPort(containerIP, port),
+			Weight:  serverWeight,
 		})
 	}
 
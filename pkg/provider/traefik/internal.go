@@ -6,6 +6,7 @@ import (
 	"math"
 	"net"
 	"regexp"
+	"strings"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/static"
@@ -17,6 +18,11 @@ import (
 
 const defaultInternalEntryPointName = "traefik"
 
+// redirectRuleRegex captures the scheme, host, port and path of the URL of a redirected request.
+const redirectRuleRegex = `^(https?)://([^/:]+)(:[0-9]+)?(.*)$`
+
+var redirectRuleReplacer = strings.NewReplacer("{scheme}", "${1}", "{host}", "${2}", "{port}", "${3}", "{path}", "${4}")
+
 var _ provider.Provider = (*Provider)(nil)
 
 // Provider is a provider.Provider implementation that provides the internal routers.
@@ -66,6 +72,7 @@ func (i *Provider) createConfiguration(ctx context.Context) *dynamic.Configurati
 
 	i.apiConfiguration(cfg)
 	i.pingConfiguration(cfg)
+	i.healthConfiguration(cfg)
 	i.restConfiguration(cfg)
 	i.prometheusConfiguration(cfg)
 	i.entryPointModels(cfg)
@@ -85,6 +92,18 @@ func (i *Provider) redirection(ctx context.Context, cfg *dynamic.Configuration)
 		logger := log.FromContext(log.With(ctx, log.Str(log.EntryPointName, name)))
 
 		def := ep.HTTP.Redirections
+
+		priority := math.MaxInt32
+		if def.EntryPoint != nil {
+			priority = def.EntryPoint.Priority
+		}
+
+		excludedHosts := i.redirectionRules(ctx, cfg, name, def.Rules, priority)
+
+		if def.EntryPoint == nil && len(def.Rules) > 0 {
+			continue
+		}
+
 		if def.EntryPoint == nil || def.EntryPoint.To == "" {
 			logger.Error("Unable to create redirection: the entry point or the port is missing")
 			continue
@@ -100,7 +119,7 @@ func (i *Provider) redirection(ctx context.Context, cfg *dynamic.Configuration)
 		mdName := "redirect-" + rtName
 
 		rt := &dynamic.Router{
-			Rule:        "HostRegexp(`{host:.+}`)",
+			Rule:        "HostRegexp(`{host:.+}`)" + excludeHostsRule(excludedHosts),
 			EntryPoints: []string{name},
 			Middlewares: []string{mdName},
 			Service:     "noop@internal",
@@ -121,6 +140,61 @@ func (i *Provider) redirection(ctx context.Context, cfg *dynamic.Configuration)
 	}
 }
 
+// redirectionRules creates the redirections of the rules of an entry point,
+// and returns the host patterns of the rules, which have to be excluded from the entry point redirection.
+func (i *Provider) redirectionRules(ctx context.Context, cfg *dynamic.Configuration, name string, rules []static.RedirectRule, priority int) []string {
+	logger := log.FromContext(log.With(ctx, log.Str(log.EntryPointName, name)))
+
+	var excludedHosts []string
+	for idx, rule := range rules {
+		if len(rule.Hosts) == 0 {
+			logger.Errorf("Unable to create redirection rule %d: the hosts are missing", idx)
+			continue
+		}
+
+		// A rule without target is an exception to the following rules and to the entry point redirection.
+		if rule.To != "" {
+			rtName := provider.Normalize(fmt.Sprintf("%s-redirect-%d", name, idx))
+			mdName := "redirect-" + rtName
+
+			cfg.HTTP.Routers[rtName] = &dynamic.Router{
+				Rule:        "HostRegexp(" + quoteHosts(rule.Hosts) + ")" + excludeHostsRule(excludedHosts),
+				EntryPoints: []string{name},
+				Middlewares: []string{mdName},
+				Service:     "noop@internal",
+				Priority:    priority,
+			}
+
+			cfg.HTTP.Middlewares[mdName] = &dynamic.Middleware{
+				RedirectRegex: &dynamic.RedirectRegex{
+					Regex:       redirectRuleRegex,
+					Replacement: redirectRuleReplacer.Replace(rule.To),
+					Permanent:   rule.Permanent,
+				},
+			}
+		}
+
+		excludedHosts = append(excludedHosts, rule.Hosts...)
+	}
+
+	return excludedHosts
+}
+
+func excludeHostsRule(hosts []string) string {
+	if len(hosts) == 0 {
+		return ""
+	}
+	return " && !HostRegexp(" + quoteHosts(hosts) + ")"
+}
+
+func quoteHosts(hosts []string) string {
+	quoted := make([]string, len(hosts))
+	for i, host := range hosts {
+		quoted[i] = "`" + host + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
 func (i *Provider) getRedirectPort(name string, def *static.Redirections) (string, error) {
 	exp := regexp.MustCompile(`^:(\d+)$`)
 
@@ -178,6 +252,11 @@ func (i *Provider) apiConfiguration(cfg *dynamic.Configuration) {
 		return
 	}
 
+	// The API served by the internal listener must not be reachable from the entry points.
+	if i.staticCfg.InternalListener != nil && i.staticCfg.InternalListener.API {
+		return
+	}
+
 	if i.staticCfg.API.Insecure {
 		cfg.HTTP.Routers["api"] = &dynamic.Router{
 			EntryPoints: []string{defaultInternalEntryPointName},
@@ -241,6 +320,23 @@ func (i *Provider) pingConfiguration(cfg *dynamic.Configuration) {
 	cfg.HTTP.Services["ping"] = &dynamic.Service{}
 }
 
+func (i *Provider) healthConfiguration(cfg *dynamic.Configuration) {
+	if i.staticCfg.Health == nil {
+		return
+	}
+
+	if !i.staticCfg.Health.ManualRouting {
+		cfg.HTTP.Routers["health"] = &dynamic.Router{
+			EntryPoints: []string{i.staticCfg.Health.EntryPoint},
+			Service:     "health@internal",
+			Priority:    math.MaxInt32,
+			Rule:        "PathPrefix(`/health`)",
+		}
+	}
+
+	cfg.HTTP.Services["health"] = &dynamic.Service{}
+}
+
 func (i *Provider) restConfiguration(cfg *dynamic.Configuration) {
 	if i.staticCfg.Providers == nil || i.staticCfg.Providers.Rest == nil {
 		return
@@ -263,6 +359,10 @@ func (i *Provider) prometheusConfiguration(cfg *dynamic.Configuration) {
 		return
 	}
 
+	if i.staticCfg.InternalListener != nil && i.staticCfg.InternalListener.Prometheus {
+		return
+	}
+
 	if !i.staticCfg.Metrics.Prometheus.ManualRouting {
 		cfg.HTTP.Routers["prometheus"] = &dynamic.Router{
 			EntryPoints: []string{i.staticCfg.Metrics.Prometheus.EntryPoint},
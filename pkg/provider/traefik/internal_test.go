@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/health"
 	"github.com/containous/traefik/v2/pkg/ping"
 	"github.com/containous/traefik/v2/pkg/provider/rest"
 	"github.com/containous/traefik/v2/pkg/types"
@@ -126,6 +127,15 @@ func Test_createConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "health_simple.json",
+			staticCfg: static.Configuration{
+				Health: &health.Handler{
+					EntryPoint: "test",
+					Services:   []string{"foo@file"},
+				},
+			},
+		},
 		{
 			desc: "rest_insecure.json",
 			staticCfg: static.Configuration{
@@ -168,6 +178,26 @@ func Test_createConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "internal_listener.json",
+			staticCfg: static.Configuration{
+				API: &static.API{
+					Insecure:  true,
+					Dashboard: true,
+				},
+				Metrics: &types.Metrics{
+					Prometheus: &types.Prometheus{
+						EntryPoint:    "test",
+						ManualRouting: false,
+					},
+				},
+				InternalListener: &static.InternalListener{
+					Address:    ":9000",
+					API:        true,
+					Prometheus: true,
+				},
+			},
+		},
 		{
 			desc: "models.json",
 			staticCfg: static.Configuration{
@@ -209,6 +239,36 @@ func Test_createConfiguration(t *testing.T) {
 					},
 				},
 			},
+		}, {
+			desc: "redirection_rules.json",
+			staticCfg: static.Configuration{
+				EntryPoints: map[string]*static.EntryPoint{
+					"web": {
+						Address: ":80",
+						HTTP: static.HTTPConfig{
+							Redirections: &static.Redirections{
+								EntryPoint: &static.RedirectEntryPoint{
+									To:        "websecure",
+									Scheme:    "https",
+									Permanent: true,
+								},
+								Rules: []static.RedirectRule{
+									{
+										Hosts: []string{"legacy.example.com"},
+									},
+									{
+										Hosts: []string{"{sub:[a-z]+}.example.org", "example.org"},
+										To:    "https://{host}:8443/app{path}",
+									},
+								},
+							},
+						},
+					},
+					"websecure": {
+						Address: ":443",
+					},
+				},
+			},
 		}, {
 			desc: "redirection_port.json",
 			staticCfg: static.Configuration{
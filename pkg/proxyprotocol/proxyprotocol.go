@@ -0,0 +1,91 @@
+// Package proxyprotocol makes the addresses received with the PROXY protocol available to the HTTP handlers.
+package proxyprotocol
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+)
+
+type infoKey struct{}
+
+// Addr is an address received in the PROXY protocol header of a connection.
+type Addr struct {
+	net.Addr
+}
+
+// Info holds the addresses received in the PROXY protocol header of a connection.
+type Info struct {
+	// Source is the address of the client, which is also the remote address of the connection.
+	Source net.Addr
+	// Destination is the address the client connected to on the proxy sending the header.
+	Destination net.Addr
+}
+
+// Conn is a connection accepted with the PROXY protocol,
+// whose addresses are reported as Addr when they come from its PROXY protocol header.
+type Conn struct {
+	// Conn is the connection whose addresses are read from its PROXY protocol header, if it is trusted.
+	net.Conn
+	raw net.Conn
+
+	once       sync.Once
+	remoteAddr net.Addr
+	localAddr  net.Addr
+}
+
+// NewConn creates a Conn from the PROXY protocol connection, and the raw connection it reads the header from.
+func NewConn(conn, raw net.Conn) *Conn {
+	return &Conn{Conn: conn, raw: raw}
+}
+
+// RemoteAddr returns the source address of the PROXY protocol header, as an Addr,
+// or the remote address of the raw connection, if there is no header, or it is not trusted.
+func (c *Conn) RemoteAddr() net.Addr {
+	c.once.Do(c.resolve)
+	return c.remoteAddr
+}
+
+// LocalAddr returns the destination address of the PROXY protocol header, as an Addr,
+// or the local address of the raw connection, if there is no header, or it is not trusted.
+func (c *Conn) LocalAddr() net.Addr {
+	c.once.Do(c.resolve)
+	return c.localAddr
+}
+
+// resolve reads the addresses, which waits for the PROXY protocol header to be received.
+func (c *Conn) resolve() {
+	c.remoteAddr, c.localAddr = c.Conn.RemoteAddr(), c.Conn.LocalAddr()
+
+	// The addresses of the raw connection are returned as they are, when they do not come from a header.
+	if c.remoteAddr != c.raw.RemoteAddr() {
+		c.remoteAddr = Addr{Addr: c.remoteAddr}
+	}
+	if c.localAddr != c.raw.LocalAddr() {
+		c.localAddr = Addr{Addr: c.localAddr}
+	}
+}
+
+// ContextWithConn returns a copy of ctx carrying the addresses of the PROXY protocol header of the connection, if any.
+// The connection can be a TLS one, as its addresses are the ones of the underlying connection.
+// It is meant to be used in the ConnContext of the entry point servers.
+func ContextWithConn(ctx context.Context, conn net.Conn) context.Context {
+	source, ok := conn.RemoteAddr().(Addr)
+	if !ok {
+		return ctx
+	}
+
+	info := Info{Source: source.Addr, Destination: conn.LocalAddr()}
+	if destination, ok := info.Destination.(Addr); ok {
+		info.Destination = destination.Addr
+	}
+
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// GetInfo returns the addresses received in the PROXY protocol header of the connection of the request, if any.
+func GetInfo(req *http.Request) (Info, bool) {
+	info, ok := req.Context().Value(infoKey{}).(Info)
+	return info, ok
+}
@@ -50,6 +50,26 @@ func ParseHostSNI(rule string) ([]string, error) {
 	return lower(parseDomain(buildTree())), nil
 }
 
+// ParseHostSNIRegexp extracts the HostSNIRegexps declared in a rule.
+func ParseHostSNIRegexp(rule string) ([]string, error) {
+	parser, err := newTCPParser()
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := parser.Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return nil, errors.New("cannot parse")
+	}
+
+	return parseMatcherValues(buildTree(), "HostSNIRegexp"), nil
+}
+
 func lower(slice []string) []string {
 	var lowerStrings []string
 	for _, value := range slice {
@@ -63,6 +83,23 @@ func parseDomain(tree *tree) []string {
 	case "and", "or":
 		return append(parseDomain(tree.ruleLeft), parseDomain(tree.ruleRight)...)
 	case "Host", "HostSNI":
+		if tree.not {
+			return nil
+		}
+		return tree.value
+	default:
+		return nil
+	}
+}
+
+func parseMatcherValues(tree *tree, matcherName string) []string {
+	switch tree.matcher {
+	case "and", "or":
+		return append(parseMatcherValues(tree.ruleLeft, matcherName), parseMatcherValues(tree.ruleRight, matcherName)...)
+	case matcherName:
+		if tree.not {
+			return nil
+		}
 		return tree.value
 	default:
 		return nil
@@ -89,6 +126,14 @@ func orFunc(left, right treeBuilder) treeBuilder {
 	}
 }
 
+func notFunc(elem treeBuilder) treeBuilder {
+	return func() *tree {
+		t := elem()
+		t.negate()
+		return t
+	}
+}
+
 func newParser() (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
@@ -112,6 +157,7 @@ func newParser() (predicate.Parser, error) {
 		Operators: predicate.Operators{
 			AND: andFunc,
 			OR:  orFunc,
+			NOT: notFunc,
 		},
 		Functions: parserFuncs,
 	})
@@ -121,19 +167,21 @@ func newTCPParser() (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
 	// FIXME quircky way of waiting for new rules
-	matcherName := "HostSNI"
-	fn := func(value ...string) treeBuilder {
-		return func() *tree {
-			return &tree{
-				matcher: matcherName,
-				value:   value,
+	for _, matcherName := range []string{"HostSNI", "HostSNIRegexp"} {
+		matcherName := matcherName
+		fn := func(value ...string) treeBuilder {
+			return func() *tree {
+				return &tree{
+					matcher: matcherName,
+					value:   value,
+				}
 			}
 		}
+		parserFuncs[matcherName] = fn
+		parserFuncs[strings.ToLower(matcherName)] = fn
+		parserFuncs[strings.ToUpper(matcherName)] = fn
+		parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 	}
-	parserFuncs[matcherName] = fn
-	parserFuncs[strings.ToLower(matcherName)] = fn
-	parserFuncs[strings.ToUpper(matcherName)] = fn
-	parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 
 	return predicate.NewParser(predicate.Def{
 		Operators: predicate.Operators{
@@ -2,7 +2,9 @@ package rules
 
 import (
 	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/containous/traefik/v2/pkg/log"
@@ -12,14 +14,17 @@ import (
 )
 
 var funcs = map[string]func(*mux.Route, ...string) error{
-	"Host":          host,
-	"HostRegexp":    hostRegexp,
-	"Path":          path,
-	"PathPrefix":    pathPrefix,
-	"Method":        methods,
-	"Headers":       headers,
-	"HeadersRegexp": headersRegexp,
-	"Query":         query,
+	"Host":            host,
+	"HostRegexp":      hostRegexp,
+	"Path":            path,
+	"PathPrefix":      pathPrefix,
+	"Method":          methods,
+	"Headers":         headers,
+	"HeadersRegexp":   headersRegexp,
+	"Query":           query,
+	"ContentLengthLt": contentLengthLt,
+	"ContentLengthGt": contentLengthGt,
+	"ContentType":     contentType,
 }
 
 // Router handle routing with rules.
@@ -61,13 +66,51 @@ func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error
 	return addRuleOnRoute(route, buildTree())
 }
 
+// Match reports whether a request would be matched by a router built with the rule.
+func Match(rule string, req *http.Request) (bool, error) {
+	router, err := NewRouter()
+	if err != nil {
+		return false, err
+	}
+
+	err = router.AddRoute(rule, 0, http.NotFoundHandler())
+	if err != nil {
+		return false, err
+	}
+
+	var matched bool
+	// The Host matcher relies on the canonical host, which is added to the context by the request decorator.
+	requestdecorator.New(nil).ServeHTTP(nil, req, func(_ http.ResponseWriter, req *http.Request) {
+		matched = router.Match(req, &mux.RouteMatch{})
+	})
+
+	return matched, nil
+}
+
 type tree struct {
 	matcher   string
+	not       bool
 	value     []string
 	ruleLeft  *tree
 	ruleRight *tree
 }
 
+// negate applies De Morgan's laws, so that only the leaf matchers are negated.
+func (t *tree) negate() {
+	switch t.matcher {
+	case "and":
+		t.matcher = "or"
+		t.ruleLeft.negate()
+		t.ruleRight.negate()
+	case "or":
+		t.matcher = "and"
+		t.ruleLeft.negate()
+		t.ruleRight.negate()
+	default:
+		t.not = !t.not
+	}
+}
+
 func path(route *mux.Route, paths ...string) error {
 	rt := route.Subrouter()
 
@@ -175,6 +218,104 @@ func query(route *mux.Route, query ...string) error {
 	return route.GetError()
 }
 
+// contentLengthLt matches the requests whose body is known to be smaller than the given number of bytes.
+// The requests of an unknown length (e.g. chunked ones) are not matched.
+func contentLengthLt(route *mux.Route, values ...string) error {
+	limit, err := parseContentLength(values)
+	if err != nil {
+		return err
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		return req.ContentLength >= 0 && req.ContentLength < limit
+	})
+	return nil
+}
+
+// contentLengthGt matches the requests whose body is larger than the given number of bytes,
+// and the requests of an unknown length (e.g. chunked ones), which are likely to be uploads.
+func contentLengthGt(route *mux.Route, values ...string) error {
+	limit, err := parseContentLength(values)
+	if err != nil {
+		return err
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		return req.ContentLength < 0 || req.ContentLength > limit
+	})
+	return nil
+}
+
+func parseContentLength(values []string) (int64, error) {
+	if len(values) != 1 {
+		return 0, fmt.Errorf("a content length matcher expects a single number of bytes, got %d values", len(values))
+	}
+
+	limit, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid number of bytes %q for a content length matcher", values[0])
+	}
+
+	return limit, nil
+}
+
+// contentType matches the requests whose media type is one of the given ones,
+// the subtype being possibly ended by a wildcard (e.g. application/grpc*, image/*).
+// The requests without a Content-Type header are not matched.
+func contentType(route *mux.Route, mediaTypes ...string) error {
+	patterns := make([][2]string, 0, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		parts := strings.Split(strings.ToLower(strings.TrimSpace(mediaType)), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid media type %q for a content type matcher", mediaType)
+		}
+
+		if parts[0] == "*" && parts[1] != "*" {
+			return fmt.Errorf("invalid media type %q for a content type matcher, a wildcard type requires a wildcard subtype", mediaType)
+		}
+
+		patterns = append(patterns, [2]string{parts[0], parts[1]})
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		value := req.Header.Get("Content-Type")
+		if value == "" {
+			return false
+		}
+
+		mediaType, _, err := mime.ParseMediaType(value)
+		if err != nil {
+			// Some clients send parameters which do not follow the RFC, only the media type matters.
+			mediaType = strings.ToLower(strings.TrimSpace(strings.SplitN(value, ";", 2)[0]))
+		}
+
+		parts := strings.SplitN(mediaType, "/", 2)
+		if len(parts) != 2 {
+			return false
+		}
+
+		for _, pattern := range patterns {
+			if matchMediaType(pattern, parts[0], parts[1]) {
+				return true
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+func matchMediaType(pattern [2]string, typ, subtype string) bool {
+	if pattern[0] != "*" && pattern[0] != typ {
+		return false
+	}
+
+	if strings.HasSuffix(pattern[1], "*") {
+		return strings.HasPrefix(subtype, strings.TrimSuffix(pattern[1], "*"))
+	}
+
+	return pattern[1] == subtype
+}
+
 func addRuleOnRouter(router *mux.Router, rule *tree) error {
 	switch rule.matcher {
 	case "and":
@@ -198,7 +339,7 @@ func addRuleOnRouter(router *mux.Router, rule *tree) error {
 			return err
 		}
 
-		return funcs[rule.matcher](router.NewRoute(), rule.value...)
+		return matcher(rule)(router.NewRoute(), rule.value...)
 	}
 }
 
@@ -226,7 +367,31 @@ func addRuleOnRoute(route *mux.Route, rule *tree) error {
 			return err
 		}
 
-		return funcs[rule.matcher](route, rule.value...)
+		return matcher(rule)(route, rule.value...)
+	}
+}
+
+func matcher(rule *tree) func(*mux.Route, ...string) error {
+	if rule.not {
+		return not(funcs[rule.matcher])
+	}
+	return funcs[rule.matcher]
+}
+
+// not returns a matcher matching the requests which are not matched by m.
+func not(m func(*mux.Route, ...string) error) func(*mux.Route, ...string) error {
+	return func(route *mux.Route, values ...string) error {
+		router := mux.NewRouter().SkipClean(true)
+
+		err := m(router.NewRoute(), values...)
+		if err != nil {
+			return err
+		}
+
+		route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+			return !router.Match(req, &mux.RouteMatch{})
+		})
+		return nil
 	}
 }
 
@@ -78,6 +78,32 @@ func Test_addRoute(t *testing.T) {
 				"http://localhost/foo": http.StatusNotFound,
 			},
 		},
+		{
+			desc: "Not Host",
+			rule: "!Host(`localhost`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+				"http://foo.bar/foo":   http.StatusOK,
+			},
+		},
+		{
+			desc: "Not HostRegexp and PathPrefix",
+			rule: "!HostRegexp(`{sub:[a-z]+}.localhost`) && PathPrefix(`/foo`)",
+			expected: map[string]int{
+				"http://localhost/foo":     http.StatusOK,
+				"http://foo.localhost/foo": http.StatusNotFound,
+				"http://localhost/bar":     http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Not of a conjunction",
+			rule: "!(Host(`localhost`) && PathPrefix(`/foo`))",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+				"http://localhost/bar": http.StatusOK,
+				"http://foo.bar/foo":   http.StatusOK,
+			},
+		},
 		{
 			desc: "Host and PathPrefix",
 			rule: "Host(`localhost`) && PathPrefix(`/foo`)",
@@ -668,6 +694,186 @@ func TestHostRegexp(t *testing.T) {
 	}
 }
 
+func TestContentLength(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		lengths       map[int64]bool
+		expectedError bool
+	}{
+		{
+			desc: "lower than",
+			rule: "ContentLengthLt(`1024`)",
+			lengths: map[int64]bool{
+				0:    true,
+				1023: true,
+				1024: false,
+				4096: false,
+				-1:   false,
+			},
+		},
+		{
+			desc: "greater than",
+			rule: "ContentLengthGt(`1024`)",
+			lengths: map[int64]bool{
+				0:    false,
+				1024: false,
+				1025: true,
+				-1:   true,
+			},
+		},
+		{
+			desc: "range",
+			rule: "ContentLengthGt(`1024`) && ContentLengthLt(`4096`)",
+			lengths: map[int64]bool{
+				1024: false,
+				2048: true,
+				4096: false,
+				-1:   false,
+			},
+		},
+		{
+			desc: "negated",
+			rule: "!ContentLengthGt(`1024`)",
+			lengths: map[int64]bool{
+				0:    true,
+				1025: false,
+				-1:   false,
+			},
+		},
+		{
+			desc:          "invalid number of bytes",
+			rule:          "ContentLengthLt(`1k`)",
+			expectedError: true,
+		},
+		{
+			desc:          "negative number of bytes",
+			rule:          "ContentLengthGt(`-1`)",
+			expectedError: true,
+		},
+		{
+			desc:          "several values",
+			rule:          "ContentLengthLt(`1024`, `2048`)",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			err = router.AddRoute(test.rule, 0, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for length, match := range test.lengths {
+				req := testhelpers.MustNewRequest(http.MethodPost, "http://localhost/foo", nil)
+				req.ContentLength = length
+				assert.Equal(t, match, router.Match(req, &mux.RouteMatch{}), "content length %d", length)
+			}
+		})
+	}
+}
+
+func TestContentType(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		contentTypes  map[string]bool
+		expectedError bool
+	}{
+		{
+			desc: "exact media type",
+			rule: "ContentType(`application/json`)",
+			contentTypes: map[string]bool{
+				"application/json":                true,
+				"Application/JSON; charset=utf-8": true,
+				"application/json-patch+json":     false,
+				"text/plain":                      false,
+				"":                                false,
+			},
+		},
+		{
+			desc: "wildcard subtype",
+			rule: "ContentType(`application/grpc*`)",
+			contentTypes: map[string]bool{
+				"application/grpc":          true,
+				"application/grpc+proto":    true,
+				"application/grpc-web+json": true,
+				"application/json":          false,
+			},
+		},
+		{
+			desc: "any subtype",
+			rule: "ContentType(`multipart/*`, `image/*`)",
+			contentTypes: map[string]bool{
+				"multipart/form-data; boundary=foo": true,
+				"image/png":                         true,
+				"application/octet-stream":          false,
+			},
+		},
+		{
+			desc: "any media type",
+			rule: "ContentType(`*/*`)",
+			contentTypes: map[string]bool{
+				"text/plain": true,
+				"":           false,
+			},
+		},
+		{
+			desc: "negated",
+			rule: "!ContentType(`application/grpc*`)",
+			contentTypes: map[string]bool{
+				"application/grpc": false,
+				"application/json": true,
+				"":                 true,
+			},
+		},
+		{
+			desc:          "missing subtype",
+			rule:          "ContentType(`application`)",
+			expectedError: true,
+		},
+		{
+			desc:          "wildcard type with a subtype",
+			rule:          "ContentType(`*/json`)",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			err = router.AddRoute(test.rule, 0, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for contentType, match := range test.contentTypes {
+				req := testhelpers.MustNewRequest(http.MethodPost, "http://localhost/foo", nil)
+				if contentType != "" {
+					req.Header.Set("Content-Type", contentType)
+				}
+				assert.Equal(t, match, router.Match(req, &mux.RouteMatch{}), "content type %q", contentType)
+			}
+		})
+	}
+}
+
 func TestParseDomains(t *testing.T) {
 	testCases := []struct {
 		description   string
@@ -698,6 +904,12 @@ func TestParseDomains(t *testing.T) {
 			domain:        []string{"foo.bar"},
 			errorExpected: false,
 		},
+		{
+			description:   "Negated host rule",
+			expression:    "!Host(`foo.bar`) && Host(`test.bar`)",
+			domain:        []string{"test.bar"},
+			errorExpected: false,
+		},
 		{
 			description:   "Host rule with no domain",
 			expression:    "Host() && Path(`/test`)",
@@ -722,3 +934,121 @@ func TestParseDomains(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHostSNIRegexp(t *testing.T) {
+	testCases := []struct {
+		description string
+		expression  string
+		hostSNIs    []string
+		expressions []string
+	}{
+		{
+			description: "HostSNI rule",
+			expression:  "HostSNI(`Foo.bar`, `*.test.bar`)",
+			hostSNIs:    []string{"foo.bar", "*.test.bar"},
+		},
+		{
+			description: "HostSNIRegexp rule",
+			expression:  "HostSNIRegexp(`^[a-z]+\\.Bar$`)",
+			expressions: []string{`^[a-z]+\.Bar$`},
+		},
+		{
+			description: "HostSNI and HostSNIRegexp rules",
+			expression:  "HostSNI(`foo.bar`) || hostsniregexp(`^[a-z]+\\.bar$`)",
+			hostSNIs:    []string{"foo.bar"},
+			expressions: []string{`^[a-z]+\.bar$`},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			t.Parallel()
+
+			hostSNIs, err := ParseHostSNI(test.expression)
+			require.NoError(t, err)
+			assert.EqualValues(t, test.hostSNIs, hostSNIs)
+
+			expressions, err := ParseHostSNIRegexp(test.expression)
+			require.NoError(t, err)
+			assert.EqualValues(t, test.expressions, expressions)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		method        string
+		url           string
+		headers       map[string]string
+		expected      bool
+		expectedError bool
+	}{
+		{
+			desc:     "Host matching",
+			rule:     "Host(`Foo.bar`)",
+			url:      "http://foo.bar:8080/",
+			expected: true,
+		},
+		{
+			desc: "Host not matching",
+			rule: "Host(`foo.bar`)",
+			url:  "http://bar.foo/",
+		},
+		{
+			desc:     "Host and PathPrefix matching",
+			rule:     "Host(`foo.bar`) && PathPrefix(`/api`)",
+			url:      "http://foo.bar/api/v1",
+			expected: true,
+		},
+		{
+			desc:     "Method and Headers matching",
+			rule:     "Method(`POST`) && Headers(`X-Foo`, `bar`)",
+			method:   http.MethodPost,
+			url:      "http://foo.bar/",
+			headers:  map[string]string{"X-Foo": "bar"},
+			expected: true,
+		},
+		{
+			desc:   "Method not matching",
+			rule:   "Method(`POST`)",
+			method: http.MethodGet,
+			url:    "http://foo.bar/",
+		},
+		{
+			desc:     "Or rule matching its right side",
+			rule:     "Path(`/foo`) || Path(`/bar`)",
+			url:      "http://foo.bar/bar",
+			expected: true,
+		},
+		{
+			desc:          "Invalid rule",
+			rule:          "Host(`foo.bar`) &&",
+			url:           "http://foo.bar/",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(test.method, test.url, nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			matched, err := Match(test.rule, req)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, matched)
+		})
+	}
+}
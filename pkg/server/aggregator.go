@@ -1,6 +1,8 @@
 package server
 
 import (
+	"strings"
+
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/server/provider"
@@ -20,18 +22,24 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 			Services: make(map[string]*dynamic.TCPService),
 		},
 		UDP: &dynamic.UDPConfiguration{
-			Routers:  make(map[string]*dynamic.UDPRouter),
-			Services: make(map[string]*dynamic.UDPService),
+			Routers:           make(map[string]*dynamic.UDPRouter),
+			Services:          make(map[string]*dynamic.UDPService),
+			ServersTransports: make(map[string]*dynamic.UDPServersTransport),
 		},
 		TLS: &dynamic.TLSConfiguration{
 			Stores:  make(map[string]tls.Store),
 			Options: make(map[string]tls.Options),
 		},
+		Origins: &dynamic.Origins{},
 	}
 
 	var defaultTLSOptionProviders []string
 	var defaultTLSStoreProviders []string
 	for pvd, configuration := range configurations {
+		conf.Origins.Merge(configuration.Origins, func(name string) string {
+			return provider.MakeQualifiedName(pvd, name)
+		})
+
 		if configuration.HTTP != nil {
 			for routerName, router := range configuration.HTTP.Routers {
 				if len(router.EntryPoints) == 0 {
@@ -39,6 +47,8 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 						WithField(log.RouterName, routerName).
 						Debugf("No entryPoint defined for this router, using the default one(s) instead: %+v", defaultEntryPoints)
 					router.EntryPoints = defaultEntryPoints
+				} else {
+					router.EntryPoints = resolveEntryPoints(router.EntryPoints, defaultEntryPoints)
 				}
 
 				conf.HTTP.Routers[provider.MakeQualifiedName(pvd, routerName)] = router
@@ -70,6 +80,9 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 			for serviceName, service := range configuration.UDP.Services {
 				conf.UDP.Services[provider.MakeQualifiedName(pvd, serviceName)] = service
 			}
+			for transportName, transport := range configuration.UDP.ServersTransports {
+				conf.UDP.ServersTransports[provider.MakeQualifiedName(pvd, transportName)] = transport
+			}
 		}
 
 		if configuration.TLS != nil {
@@ -113,6 +126,36 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 	return conf
 }
 
+// resolveEntryPoints removes the entry points excluded with a leading "!" from the entry points of a router.
+// When a router only has excluded entry points, they are removed from the default ones.
+func resolveEntryPoints(entryPoints, defaultEntryPoints []string) []string {
+	excluded := make(map[string]struct{})
+	var included []string
+	for _, entryPoint := range entryPoints {
+		if strings.HasPrefix(entryPoint, "!") {
+			excluded[strings.TrimPrefix(entryPoint, "!")] = struct{}{}
+			continue
+		}
+		included = append(included, entryPoint)
+	}
+
+	if len(excluded) == 0 {
+		return entryPoints
+	}
+
+	if len(included) == 0 {
+		included = defaultEntryPoints
+	}
+
+	var resolved []string
+	for _, entryPoint := range included {
+		if _, ok := excluded[entryPoint]; !ok {
+			resolved = append(resolved, entryPoint)
+		}
+	}
+	return resolved
+}
+
 func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 	if cfg.HTTP == nil || len(cfg.HTTP.Models) == 0 {
 		return cfg
@@ -142,6 +185,10 @@ func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 				rtName := name
 				if len(eps) > 1 {
 					rtName = epName + "-" + name
+
+					if cfg.Origins != nil && cfg.Origins.Routers[name] != nil {
+						cfg.Origins.Routers[rtName] = cfg.Origins.Routers[name]
+					}
 				}
 				rts[rtName] = cp
 			} else {
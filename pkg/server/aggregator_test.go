@@ -396,6 +396,37 @@ func Test_mergeConfiguration_tlsStore(t *testing.T) {
 	}
 }
 
+func Test_mergeConfiguration_origins(t *testing.T) {
+	origin := dynamic.Origin{ContainerID: "abc"}
+
+	given := dynamic.Configurations{
+		"provider-1": &dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"router-1": {},
+				},
+			},
+			Origins: &dynamic.Origins{
+				Routers: map[string][]dynamic.Origin{"router-1": {origin}},
+			},
+		},
+		"provider-2": &dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"router-1": {},
+				},
+			},
+		},
+	}
+
+	actual := mergeConfiguration(given, []string{"defaultEP"})
+
+	expected := &dynamic.Origins{
+		Routers: map[string][]dynamic.Origin{"router-1@provider-1": {origin}},
+	}
+	assert.Equal(t, expected, actual.Origins)
+}
+
 func Test_applyModel(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -589,3 +620,55 @@ func Test_applyModel(t *testing.T) {
 		})
 	}
 }
+
+func Test_mergeConfiguration_entryPoints(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		entryPoints []string
+		expected    []string
+	}{
+		{
+			desc:     "default entry points",
+			expected: []string{"metrics", "web", "websecure"},
+		},
+		{
+			desc:        "explicit entry points",
+			entryPoints: []string{"web"},
+			expected:    []string{"web"},
+		},
+		{
+			desc:        "excluded from the default entry points",
+			entryPoints: []string{"!metrics"},
+			expected:    []string{"web", "websecure"},
+		},
+		{
+			desc:        "excluded from the explicit entry points",
+			entryPoints: []string{"web", "metrics", "!metrics"},
+			expected:    []string{"web"},
+		},
+		{
+			desc:        "all entry points excluded",
+			entryPoints: []string{"!metrics", "!web", "!websecure"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			given := dynamic.Configurations{
+				"provider-1": &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"router-1": {EntryPoints: test.entryPoints},
+						},
+					},
+				},
+			}
+
+			actual := mergeConfiguration(given, []string{"metrics", "web", "websecure"})
+			assert.Equal(t, test.expected, actual.HTTP.Routers["router-1@provider-1"].EntryPoints)
+		})
+	}
+}
@@ -3,13 +3,18 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/freeze"
+	"github.com/containous/traefik/v2/pkg/generations"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/provider"
 	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/containous/traefik/v2/pkg/tls"
 	"github.com/eapache/channels"
 	"github.com/sirupsen/logrus"
 )
@@ -30,9 +35,36 @@ type ConfigurationWatcher struct {
 
 	configurationListeners []func(dynamic.Configuration)
 
+	// dryRunProviders are the providers in dry-run mode, whose TLS configuration shared by all the routers is ignored.
+	dryRunProviders map[string]struct{}
+
+	// history keeps the last configurations applied, if enabled.
+	history      *generations.History
+	rollbackChan chan rollbackRequest
+
+	// freeze queues the configuration changes while the configuration is frozen, if enabled.
+	freeze       *freeze.Freeze
+	unfreezeChan chan unfreezeRequest
+
+	// stopped is closed once the configurations are no longer listened to.
+	stopped chan struct{}
+
 	routinesPool *safe.Pool
 }
 
+var errWatcherStopped = errors.New("the configuration watcher is stopped")
+
+type unfreezeRequest struct {
+	providers []string
+	done      chan struct{}
+}
+
+type rollbackRequest struct {
+	id             uint64
+	configurations dynamic.Configurations
+	done           chan struct{}
+}
+
 // NewConfigurationWatcher creates a new ConfigurationWatcher.
 func NewConfigurationWatcher(
 	routinesPool *safe.Pool,
@@ -45,6 +77,9 @@ func NewConfigurationWatcher(
 		configurationChan:          make(chan dynamic.Message, 100),
 		configurationValidatedChan: make(chan dynamic.Message, 100),
 		providerConfigUpdateMap:    make(map[string]chan dynamic.Message),
+		rollbackChan:               make(chan rollbackRequest),
+		unfreezeChan:               make(chan unfreezeRequest),
+		stopped:                    make(chan struct{}),
 		providersThrottleDuration:  providersThrottleDuration,
 		routinesPool:               routinesPool,
 		defaultEntryPoints:         defaultEntryPoints,
@@ -69,6 +104,56 @@ func (c *ConfigurationWatcher) Stop() {
 	close(c.configurationValidatedChan)
 }
 
+// SetDryRunProviders sets the providers in dry-run mode.
+func (c *ConfigurationWatcher) SetDryRunProviders(providers []string) {
+	c.dryRunProviders = make(map[string]struct{}, len(providers))
+	for _, name := range providers {
+		c.dryRunProviders[name] = struct{}{}
+	}
+}
+
+// SetHistory sets the history recording the configurations applied, and allows to roll back to them.
+func (c *ConfigurationWatcher) SetHistory(history *generations.History) {
+	c.history = history
+	history.SetRollbackFunc(c.rollback)
+}
+
+// rollback applies again the configurations of a previous generation,
+// in between the configuration changes of the providers.
+func (c *ConfigurationWatcher) rollback(id uint64, configurations dynamic.Configurations) error {
+	done := make(chan struct{})
+
+	select {
+	case c.rollbackChan <- rollbackRequest{id: id, configurations: configurations, done: done}:
+	case <-c.stopped:
+		return errWatcherStopped
+	}
+
+	<-done
+
+	return nil
+}
+
+// SetFreeze sets the freeze suspending the application of the configuration changes.
+func (c *ConfigurationWatcher) SetFreeze(configFreeze *freeze.Freeze) {
+	c.freeze = configFreeze
+	configFreeze.SetUnfreezeFunc(c.unfreeze)
+}
+
+// unfreeze applies the configurations accumulated during the freeze,
+// in between the configuration changes of the providers.
+func (c *ConfigurationWatcher) unfreeze(providers []string) {
+	done := make(chan struct{})
+
+	select {
+	case c.unfreezeChan <- unfreezeRequest{providers: providers, done: done}:
+	case <-c.stopped:
+		return
+	}
+
+	<-done
+}
+
 // AddListener adds a new listener function used when new configuration is provided.
 func (c *ConfigurationWatcher) AddListener(listener func(dynamic.Configuration)) {
 	if c.configurationListeners == nil {
@@ -121,6 +206,8 @@ func (c *ConfigurationWatcher) listenProviders(ctx context.Context) {
 }
 
 func (c *ConfigurationWatcher) listenConfigurations(ctx context.Context) {
+	defer close(c.stopped)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -130,6 +217,29 @@ func (c *ConfigurationWatcher) listenConfigurations(ctx context.Context) {
 				return
 			}
 			c.loadMessage(configMsg)
+		case req := <-c.rollbackChan:
+			log.WithoutContext().Infof("Rolling back to the configuration generation %d", req.id)
+
+			c.currentConfigurations.Set(req.configurations)
+			c.applyConfigurations(req.configurations)
+			c.history.SetCurrent(req.id)
+
+			// The configuration changes queued during the freeze are superseded by the rolled back configurations.
+			if c.freeze != nil {
+				c.freeze.ClearPending()
+			}
+
+			close(req.done)
+		case req := <-c.unfreezeChan:
+			currentConfigurations := c.currentConfigurations.Get().(dynamic.Configurations)
+
+			if c.history != nil {
+				c.history.Add(strings.Join(req.providers, ","), currentConfigurations)
+			}
+
+			c.applyConfigurations(currentConfigurations)
+
+			close(req.done)
 		}
 	}
 }
@@ -143,7 +253,22 @@ func (c *ConfigurationWatcher) loadMessage(configMsg dynamic.Message) {
 
 	c.currentConfigurations.Set(newConfigurations)
 
-	conf := mergeConfiguration(newConfigurations, c.defaultEntryPoints)
+	if c.freeze != nil && c.freeze.Queue(configMsg.ProviderName) {
+		log.WithoutContext().WithField(log.ProviderName, configMsg.ProviderName).
+			Info("The dynamic configuration is frozen, the configuration change is queued")
+		return
+	}
+
+	if c.history != nil {
+		c.history.Add(configMsg.ProviderName, newConfigurations)
+	}
+
+	c.applyConfigurations(newConfigurations)
+}
+
+// applyConfigurations merges the configurations of the providers, and sends the result to the listeners.
+func (c *ConfigurationWatcher) applyConfigurations(newConfigurations dynamic.Configurations) {
+	conf := mergeConfiguration(c.withoutDryRunTLS(newConfigurations), c.defaultEntryPoints)
 	conf = applyModel(conf)
 
 	for _, listener := range c.configurationListeners {
@@ -151,6 +276,43 @@ func (c *ConfigurationWatcher) loadMessage(configMsg dynamic.Message) {
 	}
 }
 
+// withoutDryRunTLS removes from the configurations of the providers in dry-run mode the TLS elements
+// which would apply to all the routers: the certificates, and the default TLS options and store.
+func (c *ConfigurationWatcher) withoutDryRunTLS(configurations dynamic.Configurations) dynamic.Configurations {
+	if len(c.dryRunProviders) == 0 {
+		return configurations
+	}
+
+	result := make(dynamic.Configurations, len(configurations))
+	for name, conf := range configurations {
+		if _, ok := c.dryRunProviders[name]; !ok || conf == nil || conf.TLS == nil {
+			result[name] = conf
+			continue
+		}
+
+		tlsConf := &dynamic.TLSConfiguration{
+			Options: make(map[string]tls.Options),
+			Stores:  make(map[string]tls.Store),
+		}
+		for key, options := range conf.TLS.Options {
+			if key != "default" {
+				tlsConf.Options[key] = options
+			}
+		}
+		for key, store := range conf.TLS.Stores {
+			if key != "default" {
+				tlsConf.Stores[key] = store
+			}
+		}
+
+		confCopy := *conf
+		confCopy.TLS = tlsConf
+		result[name] = &confCopy
+	}
+
+	return result
+}
+
 func (c *ConfigurationWatcher) preLoadConfiguration(configMsg dynamic.Message) {
 	logger := log.WithoutContext().WithField(log.ProviderName, configMsg.ProviderName)
 	if log.GetLevel() == logrus.DebugLevel {
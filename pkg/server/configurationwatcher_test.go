@@ -8,10 +8,13 @@ import (
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/freeze"
+	"github.com/containous/traefik/v2/pkg/generations"
 	"github.com/containous/traefik/v2/pkg/safe"
 	th "github.com/containous/traefik/v2/pkg/testhelpers"
 	"github.com/containous/traefik/v2/pkg/tls"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type mockProvider struct {
@@ -80,8 +83,9 @@ func TestNewConfigurationWatcher(t *testing.T) {
 				Stores: map[string]tls.Store{},
 			},
 			UDP: &dynamic.UDPConfiguration{
-				Routers:  map[string]*dynamic.UDPRouter{},
-				Services: map[string]*dynamic.UDPService{},
+				Routers:           map[string]*dynamic.UDPRouter{},
+				Services:          map[string]*dynamic.UDPService{},
+				ServersTransports: map[string]*dynamic.UDPServersTransport{},
 			},
 		}
 
@@ -229,8 +233,9 @@ func TestListenProvidersDoesNotSkipFlappingConfiguration(t *testing.T) {
 			Services: map[string]*dynamic.TCPService{},
 		},
 		UDP: &dynamic.UDPConfiguration{
-			Routers:  map[string]*dynamic.UDPRouter{},
-			Services: map[string]*dynamic.UDPService{},
+			Routers:           map[string]*dynamic.UDPRouter{},
+			Services:          map[string]*dynamic.UDPService{},
+			ServersTransports: map[string]*dynamic.UDPServersTransport{},
 		},
 		TLS: &dynamic.TLSConfiguration{
 			Options: map[string]tls.Options{
@@ -294,10 +299,255 @@ func TestListenProvidersPublishesConfigForEachProvider(t *testing.T) {
 			Stores: map[string]tls.Store{},
 		},
 		UDP: &dynamic.UDPConfiguration{
-			Routers:  map[string]*dynamic.UDPRouter{},
-			Services: map[string]*dynamic.UDPService{},
+			Routers:           map[string]*dynamic.UDPRouter{},
+			Services:          map[string]*dynamic.UDPService{},
+			ServersTransports: map[string]*dynamic.UDPServersTransport{},
 		},
 	}
 
 	assert.Equal(t, expected, publishedProviderConfig)
 }
+
+func TestConfigurationWatcherRollback(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	pvd := &mockProvider{
+		messages: []dynamic.Message{
+			{
+				ProviderName:  "mock",
+				Configuration: &dynamic.Configuration{HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("good")))},
+			},
+			{
+				ProviderName:  "mock",
+				Configuration: &dynamic.Configuration{HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("bad")))},
+			},
+		},
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{"defaultEP"})
+
+	history := generations.NewHistory(10)
+	watcher.SetHistory(history)
+
+	published := make(chan []string, 10)
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		var routers []string
+		for name := range conf.HTTP.Routers {
+			routers = append(routers, name)
+		}
+		published <- routers
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	for _, expected := range []string{"good@mock", "bad@mock"} {
+		select {
+		case routers := <-published:
+			assert.Equal(t, []string{expected}, routers)
+		case <-time.After(time.Second):
+			t.Fatal("the configuration should have been published")
+		}
+	}
+
+	require.NoError(t, history.Rollback(1))
+
+	select {
+	case routers := <-published:
+		assert.Equal(t, []string{"good@mock"}, routers)
+	case <-time.After(time.Second):
+		t.Fatal("the rolled back configuration should have been published")
+	}
+
+	results := history.List()
+	require.Len(t, results, 2)
+	assert.False(t, results[0].Current)
+	assert.True(t, results[1].Current)
+
+	assert.Error(t, history.Rollback(3))
+}
+
+func TestConfigurationWatcherRollback_stopped(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	watcher := NewConfigurationWatcher(routinesPool, &mockProvider{}, 0, []string{"defaultEP"})
+
+	history := generations.NewHistory(10)
+	watcher.SetHistory(history)
+	id := history.Add("mock", dynamic.Configurations{})
+
+	watcher.Start()
+	routinesPool.Stop()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- history.Rollback(id)
+	}()
+
+	select {
+	case err := <-done:
+		assert.Equal(t, errWatcherStopped, err)
+	case <-time.After(time.Second):
+		t.Fatal("the rollback should not wait for a stopped configuration watcher")
+	}
+}
+
+func TestConfigurationWatcherRollback_frozen(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	pvd := &mockProvider{
+		messages: []dynamic.Message{
+			{
+				ProviderName:  "mock",
+				Configuration: &dynamic.Configuration{HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("queued")))},
+			},
+		},
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{"defaultEP"})
+
+	history := generations.NewHistory(10)
+	watcher.SetHistory(history)
+	id := history.Add("mock", dynamic.Configurations{
+		"mock": &dynamic.Configuration{HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("previous")))},
+	})
+
+	configFreeze := freeze.New()
+	watcher.SetFreeze(configFreeze)
+	configFreeze.Freeze("peak", 0)
+
+	published := make(chan []string, 10)
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		var routers []string
+		for name := range conf.HTTP.Routers {
+			routers = append(routers, name)
+		}
+		published <- routers
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	// Waits for the provider to send its configuration.
+	time.Sleep(100 * time.Millisecond)
+
+	require.Equal(t, []string{"mock"}, configFreeze.Status().PendingProviders)
+
+	require.NoError(t, history.Rollback(id))
+
+	select {
+	case routers := <-published:
+		assert.Equal(t, []string{"previous@mock"}, routers)
+	case <-time.After(time.Second):
+		t.Fatal("the rolled back configuration should have been published")
+	}
+
+	// The queued configuration change is superseded by the rollback, and is not applied when unfreezing.
+	assert.Empty(t, configFreeze.Status().PendingProviders)
+	require.NoError(t, configFreeze.Unfreeze())
+
+	select {
+	case routers := <-published:
+		t.Fatalf("no configuration should have been published when unfreezing, got %v", routers)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestConfigurationWatcherFreeze(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	pvd := &mockProvider{
+		messages: []dynamic.Message{
+			{
+				ProviderName:  "mock",
+				Configuration: &dynamic.Configuration{HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("first")))},
+			},
+			{
+				ProviderName:  "mock",
+				Configuration: &dynamic.Configuration{HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("second")))},
+			},
+		},
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{"defaultEP"})
+
+	history := generations.NewHistory(10)
+	watcher.SetHistory(history)
+
+	configFreeze := freeze.New()
+	watcher.SetFreeze(configFreeze)
+	configFreeze.Freeze("peak", 0)
+
+	published := make(chan []string, 10)
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		var routers []string
+		for name := range conf.HTTP.Routers {
+			routers = append(routers, name)
+		}
+		published <- routers
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	// Waits for the provider to send all its configurations.
+	time.Sleep(100 * time.Millisecond)
+
+	select {
+	case routers := <-published:
+		t.Fatalf("no configuration should have been published while frozen, got %v", routers)
+	default:
+	}
+
+	assert.Equal(t, []string{"mock"}, configFreeze.Status().PendingProviders)
+
+	require.NoError(t, configFreeze.Unfreeze())
+
+	// The accumulated configuration is applied at once.
+	select {
+	case routers := <-published:
+		assert.Equal(t, []string{"second@mock"}, routers)
+	case <-time.After(time.Second):
+		t.Fatal("the configuration should have been published")
+	}
+
+	results := history.List()
+	require.Len(t, results, 1)
+	assert.Equal(t, "mock", results[0].Provider)
+	assert.True(t, results[0].Current)
+}
+
+func TestWithoutDryRunTLS(t *testing.T) {
+	watcher := NewConfigurationWatcher(safe.NewPool(context.Background()), &mockProvider{}, 0, []string{})
+	watcher.SetDryRunProviders([]string{"shadow"})
+
+	newTLS := func() *dynamic.TLSConfiguration {
+		return &dynamic.TLSConfiguration{
+			Certificates: []*tls.CertAndStores{{Certificate: tls.Certificate{CertFile: "cert", KeyFile: "key"}}},
+			Options: map[string]tls.Options{
+				"default": {MinVersion: "VersionTLS12"},
+				"foo":     {MinVersion: "VersionTLS13"},
+			},
+			Stores: map[string]tls.Store{
+				"default": {},
+				"bar":     {},
+			},
+		}
+	}
+
+	configurations := dynamic.Configurations{
+		"live":   &dynamic.Configuration{TLS: newTLS()},
+		"shadow": &dynamic.Configuration{TLS: newTLS()},
+	}
+
+	result := watcher.withoutDryRunTLS(configurations)
+
+	assert.Equal(t, newTLS(), result["live"].TLS)
+	assert.Equal(t, &dynamic.TLSConfiguration{
+		Options: map[string]tls.Options{"foo": {MinVersion: "VersionTLS13"}},
+		Stores:  map[string]tls.Store{"bar": {}},
+	}, result["shadow"].TLS)
+
+	// The configurations of the providers are left untouched.
+	assert.Equal(t, newTLS(), configurations["shadow"].TLS)
+}
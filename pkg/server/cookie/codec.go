@@ -0,0 +1,173 @@
+package cookie
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+)
+
+const minKeyLength = 32
+
+// opaqueIDLength is the length of the opaque identifiers, in bytes.
+const opaqueIDLength = 12
+
+// Codec protects the values of a sticky cookie.
+// A signed value is "<base64url value>.<base64url HMAC-SHA256 of the cookie name and the value>",
+// and an encrypted one is the base64url encoded nonce and AES-256-GCM ciphertext of the value, authenticated with the cookie name.
+type Codec struct {
+	name      string
+	keys      []codecKey
+	opaqueIDs bool
+}
+
+// codecKey holds the keys derived from one of the configured keys.
+type codecKey struct {
+	signing []byte
+	id      []byte
+	aead    cipher.AEAD // nil when the values are only signed.
+}
+
+// NewCodec creates the codec of the cookie with the given name.
+func NewCodec(cookieName string, config *dynamic.CookieProtection) (*Codec, error) {
+	if len(config.Keys) == 0 {
+		return nil, errors.New("at least one key is required to protect the cookie")
+	}
+
+	codec := &Codec{name: cookieName, opaqueIDs: config.OpaqueIDs}
+
+	for i, key := range config.Keys {
+		if len(key) < minKeyLength {
+			return nil, fmt.Errorf("key %d is too short: at least %d characters are required", i, minKeyLength)
+		}
+
+		k := codecKey{
+			signing: deriveKey(key, "signing"),
+			id:      deriveKey(key, "id"),
+		}
+
+		if config.Encrypt {
+			block, err := aes.NewCipher(deriveKey(key, "encryption"))
+			if err != nil {
+				return nil, err
+			}
+
+			k.aead, err = cipher.NewGCM(block)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		codec.keys = append(codec.keys, k)
+	}
+
+	return codec, nil
+}
+
+// Encode returns the protected cookie value of the given value, which is the URL of a server or the name of a service.
+func (c *Codec) Encode(value string) (string, error) {
+	key := c.keys[0]
+
+	if c.opaqueIDs {
+		value = key.opaqueID(value)
+	}
+
+	if key.aead == nil {
+		return base64.RawURLEncoding.EncodeToString([]byte(value)) + "." + base64.RawURLEncoding.EncodeToString(key.sign(c.name, value)), nil
+	}
+
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error while generating a nonce: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(key.aead.Seal(nonce, nonce, []byte(value), []byte(c.name))), nil
+}
+
+// Decode returns the candidate the protected cookie value was encoded from, if any.
+// The values which were tampered with, or protected with an unknown key, are rejected.
+func (c *Codec) Decode(cookieValue string, candidates []string) (string, bool) {
+	for _, key := range c.keys {
+		value, ok := key.open(c.name, cookieValue)
+		if !ok {
+			continue
+		}
+
+		for _, candidate := range candidates {
+			if c.opaqueIDs && hmac.Equal([]byte(key.opaqueID(candidate)), []byte(value)) {
+				return candidate, true
+			}
+
+			if !c.opaqueIDs && candidate == value {
+				return candidate, true
+			}
+		}
+
+		return "", false
+	}
+
+	return "", false
+}
+
+// open returns the value of a cookie value protected with the key.
+func (k codecKey) open(cookieName, cookieValue string) (string, bool) {
+	if k.aead == nil {
+		parts := strings.Split(cookieValue, ".")
+		if len(parts) != 2 {
+			return "", false
+		}
+
+		value, err := base64.RawURLEncoding.DecodeString(parts[0])
+		if err != nil {
+			return "", false
+		}
+
+		signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil || !hmac.Equal(signature, k.sign(cookieName, string(value))) {
+			return "", false
+		}
+
+		return string(value), true
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(cookieValue)
+	if err != nil || len(sealed) < k.aead.NonceSize() {
+		return "", false
+	}
+
+	value, err := k.aead.Open(nil, sealed[:k.aead.NonceSize()], sealed[k.aead.NonceSize():], []byte(cookieName))
+	if err != nil {
+		return "", false
+	}
+
+	return string(value), true
+}
+
+// sign returns the HMAC-SHA256 of the cookie name and of its value,
+// so that a value cannot be used in the cookie of another service.
+func (k codecKey) sign(cookieName, value string) []byte {
+	mac := hmac.New(sha256.New, k.signing)
+	_, _ = mac.Write([]byte(cookieName + ";" + value))
+	return mac.Sum(nil)
+}
+
+// opaqueID returns the opaque identifier of a value.
+func (k codecKey) opaqueID(value string) string {
+	mac := hmac.New(sha256.New, k.id)
+	_, _ = mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:opaqueIDLength])
+}
+
+// deriveKey derives the key of the given purpose from a configured key, so that no key is used for two purposes.
+func deriveKey(key, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(key))
+	_, _ = mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
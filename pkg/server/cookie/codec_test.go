@@ -0,0 +1,157 @@
+package cookie
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	key1 = "0123456789abcdef0123456789abcdef"
+	key2 = "fedcba9876543210fedcba9876543210"
+)
+
+var servers = []string{"http://10.0.0.1:80", "http://10.0.0.2:80"}
+
+func TestNewCodec(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.CookieProtection
+		expectedError string
+	}{
+		{
+			desc:          "without key",
+			config:        dynamic.CookieProtection{},
+			expectedError: "at least one key is required to protect the cookie",
+		},
+		{
+			desc:          "short key",
+			config:        dynamic.CookieProtection{Keys: []string{key1, "foobar"}},
+			expectedError: "key 1 is too short: at least 32 characters are required",
+		},
+		{
+			desc:   "encryption",
+			config: dynamic.CookieProtection{Keys: []string{key1}, Encrypt: true},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewCodec("sticky", &test.config)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCodec(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.CookieProtection
+	}{
+		{
+			desc:   "signed",
+			config: dynamic.CookieProtection{Keys: []string{key1}},
+		},
+		{
+			desc:   "signed opaque identifiers",
+			config: dynamic.CookieProtection{Keys: []string{key1}, OpaqueIDs: true},
+		},
+		{
+			desc:   "encrypted",
+			config: dynamic.CookieProtection{Keys: []string{key1}, Encrypt: true},
+		},
+		{
+			desc:   "encrypted opaque identifiers",
+			config: dynamic.CookieProtection{Keys: []string{key1}, Encrypt: true, OpaqueIDs: true},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			codec, err := NewCodec("sticky", &test.config)
+			require.NoError(t, err)
+
+			value, err := codec.Encode(servers[1])
+			require.NoError(t, err)
+
+			if test.config.Encrypt || test.config.OpaqueIDs {
+				assert.NotContains(t, value, "10.0.0.2")
+			}
+
+			server, ok := codec.Decode(value, servers)
+			require.True(t, ok)
+			assert.Equal(t, servers[1], server)
+
+			// The server is no longer a candidate.
+			_, ok = codec.Decode(value, servers[:1])
+			assert.False(t, ok)
+
+			// The value of another cookie is rejected.
+			other, err := NewCodec("other", &test.config)
+			require.NoError(t, err)
+			_, ok = other.Decode(value, servers)
+			assert.False(t, ok)
+
+			// A tampered value is rejected.
+			tampered := []byte(value)
+			if tampered[0] == 'A' {
+				tampered[0] = 'B'
+			} else {
+				tampered[0] = 'A'
+			}
+			_, ok = codec.Decode(string(tampered), servers)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestCodec_unprotectedValue(t *testing.T) {
+	codec, err := NewCodec("sticky", &dynamic.CookieProtection{Keys: []string{key1}})
+	require.NoError(t, err)
+
+	_, ok := codec.Decode(servers[0], servers)
+	assert.False(t, ok)
+}
+
+func TestCodec_keyRotation(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		oldCodec, err := NewCodec("sticky", &dynamic.CookieProtection{Keys: []string{key1}, Encrypt: encrypt, OpaqueIDs: true})
+		require.NoError(t, err)
+
+		value, err := oldCodec.Encode(servers[0])
+		require.NoError(t, err)
+
+		// The new key protects the new values, and the old one still reads the existing values.
+		codec, err := NewCodec("sticky", &dynamic.CookieProtection{Keys: []string{key2, key1}, Encrypt: encrypt, OpaqueIDs: true})
+		require.NoError(t, err)
+
+		server, ok := codec.Decode(value, servers)
+		require.True(t, ok)
+		assert.Equal(t, servers[0], server)
+
+		newValue, err := codec.Encode(servers[0])
+		require.NoError(t, err)
+
+		_, ok = oldCodec.Decode(newValue, servers)
+		assert.False(t, ok)
+
+		// Once the old key is removed, the existing values are rejected.
+		newCodec, err := NewCodec("sticky", &dynamic.CookieProtection{Keys: []string{key2}, Encrypt: encrypt, OpaqueIDs: true})
+		require.NoError(t, err)
+
+		_, ok = newCodec.Decode(value, servers)
+		assert.False(t, ok)
+	}
+}
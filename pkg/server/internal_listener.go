@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/api"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/ip"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/gorilla/mux"
+)
+
+// internalReadHeaderTimeout is the time allowed to the clients of the internal listener to send the request headers,
+// so that slow clients cannot hold the connections open.
+const internalReadHeaderTimeout = 10 * time.Second
+
+// InternalListener serves the API, the dashboard and the Prometheus metrics on a dedicated address,
+// outside of the entry points and the routing.
+type InternalListener struct {
+	address string
+	server  *http.Server
+
+	api        func(configuration *runtime.Configuration) http.Handler
+	apiHandler *middlewares.HTTPHandlerSwitcher
+}
+
+// NewInternalListener creates a new InternalListener, whose API reports the given states.
+func NewInternalListener(staticConfiguration static.Configuration, apiOptions api.BuilderOptions) (*InternalListener, error) {
+	config := staticConfiguration.InternalListener
+
+	listener := &InternalListener{address: config.Address}
+
+	router := mux.NewRouter()
+
+	if config.API && staticConfiguration.API != nil {
+		listener.api = api.NewBuilder(staticConfiguration, apiOptions)
+		listener.apiHandler = middlewares.NewHandlerSwitcher(http.NotFoundHandler())
+
+		router.PathPrefix("/api").Handler(listener.apiHandler)
+		if staticConfiguration.API.Debug {
+			router.PathPrefix("/debug").Handler(listener.apiHandler)
+		}
+
+		if staticConfiguration.API.Dashboard {
+			router.Path("/").Handler(http.RedirectHandler("/dashboard/", http.StatusMovedPermanently))
+			router.PathPrefix("/dashboard/").Handler(http.StripPrefix("/dashboard", http.FileServer(staticConfiguration.API.DashboardAssets)))
+		}
+	}
+
+	if config.Prometheus {
+		router.Path("/metrics").Handler(metrics.PrometheusHandler())
+	}
+
+	var handler http.Handler = router
+	if len(config.SourceRange) > 0 {
+		checker, err := ip.NewChecker(config.SourceRange)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source range of the internal listener: %w", err)
+		}
+
+		handler = sourceRangeHandler(checker, router)
+	}
+
+	listener.server = &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: internalReadHeaderTimeout,
+		IdleTimeout:       static.DefaultIdleTimeout,
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := buildInternalTLSConfig(config.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS configuration of the internal listener: %w", err)
+		}
+
+		listener.server.TLSConfig = tlsConfig
+	}
+
+	return listener, nil
+}
+
+// Switch updates the runtime configuration exposed by the API.
+func (l *InternalListener) Switch(configuration *runtime.Configuration) {
+	if l.api == nil {
+		return
+	}
+
+	l.apiHandler.UpdateHandler(l.api(configuration))
+}
+
+// Start starts listening on the address of the internal listener.
+func (l *InternalListener) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", l.address)
+	if err != nil {
+		return fmt.Errorf("error opening internal listener: %w", err)
+	}
+
+	if l.server.TLSConfig != nil {
+		listener = tls.NewListener(listener, l.server.TLSConfig)
+	}
+
+	go func() {
+		logger := log.FromContext(ctx)
+		logger.Infof("Starting internal listener on %s", listener.Addr())
+
+		if err := l.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("Error while serving the internal listener: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown stops the internal listener.
+func (l *InternalListener) Shutdown(ctx context.Context) {
+	if err := l.server.Shutdown(ctx); err != nil {
+		log.FromContext(ctx).Errorf("Error while shutting down the internal listener: %v", err)
+	}
+}
+
+func sourceRangeHandler(checker *ip.Checker, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if err := checker.IsAuthorized(req.RemoteAddr); err != nil {
+			log.FromContext(req.Context()).Debugf("Rejecting request to the internal listener: %v", err)
+			http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+func buildInternalTLSConfig(config *static.InternalListenerTLS) (*tls.Config, error) {
+	certContent, err := config.CertFile.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	keyContent, err := config.KeyFile.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.X509KeyPair(certContent, keyContent)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if len(config.CAFiles) == 0 {
+		return tlsConfig, nil
+	}
+
+	pool := x509.NewCertPool()
+	for _, caFile := range config.CAFiles {
+		data, err := caFile.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("invalid CA: %s", caFile)
+		}
+	}
+
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+
+	return tlsConfig, nil
+}
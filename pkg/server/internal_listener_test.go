@@ -0,0 +1,142 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/api"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInternalListener(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		internalListener *static.InternalListener
+		remoteAddr       string
+		expected         map[string]int
+	}{
+		{
+			desc: "API and metrics",
+			internalListener: &static.InternalListener{
+				Address:    ":0",
+				API:        true,
+				Prometheus: true,
+			},
+			remoteAddr: "10.0.0.1:1234",
+			expected: map[string]int{
+				"/api/rawdata": http.StatusOK,
+				"/metrics":     http.StatusOK,
+				"/foo":         http.StatusNotFound,
+			},
+		},
+		{
+			desc: "metrics only",
+			internalListener: &static.InternalListener{
+				Address:    ":0",
+				Prometheus: true,
+			},
+			remoteAddr: "10.0.0.1:1234",
+			expected: map[string]int{
+				"/api/rawdata": http.StatusNotFound,
+				"/metrics":     http.StatusOK,
+			},
+		},
+		{
+			desc: "allowed source",
+			internalListener: &static.InternalListener{
+				Address:     ":0",
+				SourceRange: []string{"10.0.0.0/8"},
+				API:         true,
+				Prometheus:  true,
+			},
+			remoteAddr: "10.0.0.1:1234",
+			expected: map[string]int{
+				"/api/rawdata": http.StatusOK,
+				"/metrics":     http.StatusOK,
+			},
+		},
+		{
+			desc: "rejected source",
+			internalListener: &static.InternalListener{
+				Address:     ":0",
+				SourceRange: []string{"10.0.0.0/8"},
+				API:         true,
+				Prometheus:  true,
+			},
+			remoteAddr: "192.168.0.1:1234",
+			expected: map[string]int{
+				"/api/rawdata": http.StatusForbidden,
+				"/metrics":     http.StatusForbidden,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			staticConfiguration := static.Configuration{
+				API:              &static.API{},
+				Metrics:          &types.Metrics{Prometheus: &types.Prometheus{}},
+				InternalListener: test.internalListener,
+			}
+
+			listener, err := NewInternalListener(staticConfiguration, api.BuilderOptions{})
+			require.NoError(t, err)
+
+			listener.Switch(runtime.NewConfig(dynamic.Configuration{}))
+
+			for path, code := range test.expected {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+				req.RemoteAddr = test.remoteAddr
+
+				rw := httptest.NewRecorder()
+				listener.server.Handler.ServeHTTP(rw, req)
+
+				assert.Equal(t, code, rw.Code, path)
+			}
+		})
+	}
+}
+
+func TestNewInternalListenerInvalid(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		internalListener *static.InternalListener
+	}{
+		{
+			desc: "invalid source range",
+			internalListener: &static.InternalListener{
+				Address:     ":0",
+				SourceRange: []string{"foo"},
+			},
+		},
+		{
+			desc: "invalid certificate",
+			internalListener: &static.InternalListener{
+				Address: ":0",
+				TLS: &static.InternalListenerTLS{
+					CertFile: "foo",
+					KeyFile:  "bar",
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewInternalListener(static.Configuration{InternalListener: test.internalListener}, api.BuilderOptions{})
+			assert.Error(t, err)
+		})
+	}
+}
@@ -4,12 +4,19 @@ import (
 	"context"
 
 	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/middlewares/bandwidth"
+	"github.com/containous/traefik/v2/pkg/middlewares/debugheaders"
+	"github.com/containous/traefik/v2/pkg/middlewares/maxrequestrate"
 	metricsmiddleware "github.com/containous/traefik/v2/pkg/middlewares/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares/overload"
 	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/containous/traefik/v2/pkg/middlewares/responseheaders"
+	"github.com/containous/traefik/v2/pkg/middlewares/shutdown"
 	mTracing "github.com/containous/traefik/v2/pkg/middlewares/tracing"
 	"github.com/containous/traefik/v2/pkg/tracing"
 	"github.com/containous/traefik/v2/pkg/tracing/jaeger"
@@ -21,22 +28,56 @@ type ChainBuilder struct {
 	accessLoggerMiddleware *accesslog.Handler
 	tracer                 *tracing.Tracing
 	requestDecorator       *requestdecorator.RequestDecorator
+	debugHeadersSecret     string
+	removedHeaders         []string
+	overloadGuard          *overload.Guard
+	shutdownCoordinator    *shutdown.Coordinator
+	quotaTracker           *bandwidth.QuotaTracker
+	// requestRateLimiters are the limiters of the entry points with a maximum request rate.
+	requestRateLimiters map[string]*maxrequestrate.Limiter
 }
 
 // NewChainBuilder Creates a new ChainBuilder.
 func NewChainBuilder(staticConfiguration static.Configuration, metricsRegistry metrics.Registry, accessLoggerMiddleware *accesslog.Handler) *ChainBuilder {
-	return &ChainBuilder{
+	chainBuilder := &ChainBuilder{
 		metricsRegistry:        metricsRegistry,
 		accessLoggerMiddleware: accessLoggerMiddleware,
 		tracer:                 setupTracing(staticConfiguration.Tracing),
 		requestDecorator:       requestdecorator.New(staticConfiguration.HostResolver),
+		quotaTracker:           bandwidth.NewQuotaTracker(),
+		requestRateLimiters:    make(map[string]*maxrequestrate.Limiter),
 	}
+
+	for name, ep := range staticConfiguration.EntryPoints {
+		if ep.HTTP.MaxRequestRate != nil {
+			chainBuilder.requestRateLimiters[name] = maxrequestrate.NewLimiter(*ep.HTTP.MaxRequestRate)
+		}
+	}
+
+	if staticConfiguration.DebugHeaders != nil {
+		chainBuilder.debugHeadersSecret = staticConfiguration.DebugHeaders.Secret
+	}
+
+	if staticConfiguration.ResponseHeaders != nil {
+		chainBuilder.removedHeaders = staticConfiguration.ResponseHeaders.Remove
+	}
+
+	return chainBuilder
 }
 
 // Build a middleware chain by entry point.
 func (c *ChainBuilder) Build(ctx context.Context, entryPointName string) alice.Chain {
 	chain := alice.New()
 
+	// The excess requests are rejected first, before any other work.
+	if limiter, ok := c.requestRateLimiters[entryPointName]; ok {
+		chain = chain.Append(maxrequestrate.WrapEntryPointHandler(ctx, limiter, c.metricsRegistry, entryPointName))
+	}
+
+	if c.overloadGuard != nil {
+		chain = chain.Append(overload.WrapEntryPointHandler(ctx, c.overloadGuard, c.metricsRegistry, entryPointName))
+	}
+
 	if c.accessLoggerMiddleware != nil {
 		chain = chain.Append(accesslog.WrapHandler(c.accessLoggerMiddleware))
 	}
@@ -47,11 +88,68 @@ func (c *ChainBuilder) Build(ctx context.Context, entryPointName string) alice.C
 
 	if c.metricsRegistry != nil && c.metricsRegistry.IsEpEnabled() {
 		chain = chain.Append(metricsmiddleware.WrapEntryPointHandler(ctx, c.metricsRegistry, entryPointName))
+		chain = chain.Append(bandwidth.WrapEntryPointHandler(ctx, c.metricsRegistry, entryPointName))
 	}
 
 	return chain.Append(requestdecorator.WrapHandler(c.requestDecorator))
 }
 
+// SetOverloadGuard sets the guard telling whether the requests have to be rejected, Traefik being overloaded.
+func (c *ChainBuilder) SetOverloadGuard(guard *overload.Guard) {
+	c.overloadGuard = guard
+}
+
+// SetShutdownCoordinator sets the coordinator telling the routers which stage of the shutdown has been reached.
+func (c *ChainBuilder) SetShutdownCoordinator(coordinator *shutdown.Coordinator) {
+	c.shutdownCoordinator = coordinator
+}
+
+// BuildShutdown returns the middleware rejecting the requests of a router once the shutdown reached its order,
+// or nil if the router has no shutdown order.
+func (c *ChainBuilder) BuildShutdown(ctx context.Context, routerName string, order *int) alice.Constructor {
+	if c.shutdownCoordinator == nil || order == nil {
+		return nil
+	}
+
+	return shutdown.WrapRouterHandler(ctx, c.shutdownCoordinator, routerName, *order)
+}
+
+// BuildDebugHeaders returns the debug headers middleware of a router,
+// or nil if no secret is configured to sign the debug tokens.
+func (c *ChainBuilder) BuildDebugHeaders(ctx context.Context, routerName string) alice.Constructor {
+	if c.debugHeadersSecret == "" {
+		return nil
+	}
+
+	return debugheaders.WrapHandler(ctx, c.debugHeadersSecret, routerName)
+}
+
+// BuildResponseHeaders returns the middleware removing the configured response headers, except the ones kept by the router,
+// or nil if no header is configured to be removed.
+func (c *ChainBuilder) BuildResponseHeaders(ctx context.Context, routerName string, keep []string) alice.Constructor {
+	if len(c.removedHeaders) == 0 {
+		return nil
+	}
+
+	return responseheaders.WrapHandler(ctx, c.removedHeaders, keep, routerName)
+}
+
+// BuildBandwidth returns the bandwidth middleware of a router, accounting for its bytes and enforcing its quota,
+// or nil if neither the router metrics nor a quota are enabled.
+func (c *ChainBuilder) BuildBandwidth(ctx context.Context, routerName string, quota *dynamic.RouterQuota) alice.Constructor {
+	metricsEnabled := c.metricsRegistry != nil && c.metricsRegistry.IsRouterEnabled()
+	if !metricsEnabled && quota == nil {
+		return nil
+	}
+
+	registry := c.metricsRegistry
+	if !metricsEnabled {
+		registry = metrics.NewVoidRegistry()
+	}
+
+	return bandwidth.WrapRouterHandler(ctx, registry, c.quotaTracker, routerName, quota)
+}
+
 // Close accessLogger and tracer.
 func (c *ChainBuilder) Close() {
 	if c.accessLoggerMiddleware != nil {
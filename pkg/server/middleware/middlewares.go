@@ -8,39 +8,60 @@ import (
 	"strings"
 
 	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/middlewares/adaptiveconcurrency"
 	"github.com/containous/traefik/v2/pkg/middlewares/addprefix"
+	"github.com/containous/traefik/v2/pkg/middlewares/aggregate"
 	"github.com/containous/traefik/v2/pkg/middlewares/auth"
 	"github.com/containous/traefik/v2/pkg/middlewares/buffering"
+	"github.com/containous/traefik/v2/pkg/middlewares/cache"
 	"github.com/containous/traefik/v2/pkg/middlewares/chain"
 	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
+	"github.com/containous/traefik/v2/pkg/middlewares/clientcertpolicy"
 	"github.com/containous/traefik/v2/pkg/middlewares/compress"
+	"github.com/containous/traefik/v2/pkg/middlewares/cors"
 	"github.com/containous/traefik/v2/pkg/middlewares/customerrors"
+	"github.com/containous/traefik/v2/pkg/middlewares/etag"
 	"github.com/containous/traefik/v2/pkg/middlewares/headers"
 	"github.com/containous/traefik/v2/pkg/middlewares/inflightreq"
 	"github.com/containous/traefik/v2/pkg/middlewares/ipwhitelist"
+	metricsmiddleware "github.com/containous/traefik/v2/pkg/middlewares/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares/optional"
 	"github.com/containous/traefik/v2/pkg/middlewares/passtlsclientcert"
 	"github.com/containous/traefik/v2/pkg/middlewares/ratelimiter"
 	"github.com/containous/traefik/v2/pkg/middlewares/redirect"
 	"github.com/containous/traefik/v2/pkg/middlewares/replacepath"
 	"github.com/containous/traefik/v2/pkg/middlewares/replacepathregex"
+	"github.com/containous/traefik/v2/pkg/middlewares/resourcehints"
 	"github.com/containous/traefik/v2/pkg/middlewares/retry"
+	"github.com/containous/traefik/v2/pkg/middlewares/rewritebody"
 	"github.com/containous/traefik/v2/pkg/middlewares/stripprefix"
 	"github.com/containous/traefik/v2/pkg/middlewares/stripprefixregex"
 	"github.com/containous/traefik/v2/pkg/middlewares/tracing"
+	"github.com/containous/traefik/v2/pkg/panics"
 	"github.com/containous/traefik/v2/pkg/server/provider"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 )
 
 type middlewareStackType int
 
 const (
 	middlewareStackKey middlewareStackType = iota
+	serviceNameKey
 )
 
 // Builder the middleware builder.
 type Builder struct {
-	configs        map[string]*runtime.MiddlewareInfo
-	serviceBuilder serviceBuilder
+	configs         map[string]*runtime.MiddlewareInfo
+	serviceBuilder  serviceBuilder
+	metricsRegistry metrics.Registry
+	// retryBudgets are the retry budgets, by service and middleware.
+	retryBudgets map[string]*retry.Budget
+	// circuitBreakers are the states of the circuit breakers, by service and middleware.
+	circuitBreakers map[string]*circuitbreaker.Breaker
 }
 
 type serviceBuilder interface {
@@ -48,8 +69,14 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder}
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, metricsRegistry metrics.Registry) *Builder {
+	return &Builder{
+		configs:         configs,
+		serviceBuilder:  serviceBuilder,
+		metricsRegistry: metricsRegistry,
+		retryBudgets:    make(map[string]*retry.Budget),
+		circuitBreakers: make(map[string]*circuitbreaker.Breaker),
+	}
 }
 
 // BuildChain creates a middleware chain.
@@ -82,7 +109,8 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.C
 				return nil, err
 			}
 
-			return handler, nil
+			// The recovered panics are reported with the name of the middleware they occurred in.
+			return panics.WrapMiddleware(handler, middlewareName), nil
 		})
 	}
 	return &chain
@@ -116,6 +144,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// AdaptiveConcurrency
+	if config.AdaptiveConcurrency != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return adaptiveconcurrency.New(ctx, next, *config.AdaptiveConcurrency, middlewareName)
+		}
+	}
+
+	// Aggregate
+	if config.Aggregate != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return aggregate.New(ctx, next, *config.Aggregate, middlewareName)
+		}
+	}
+
 	// BasicAuth
 	if config.BasicAuth != nil {
 		if middleware != nil {
@@ -136,6 +184,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Cache
+	if config.Cache != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return cache.New(ctx, next, *config.Cache, middlewareName)
+		}
+	}
+
 	// Chain
 	if config.Chain != nil {
 		if middleware != nil {
@@ -158,7 +216,22 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return circuitbreaker.New(ctx, next, *config.CircuitBreaker, middlewareName)
+			breaker, err := b.circuitBreaker(ctx, middlewareName, *config.CircuitBreaker)
+			if err != nil {
+				return nil, err
+			}
+
+			return circuitbreaker.New(ctx, next, breaker, middlewareName)
+		}
+	}
+
+	// ClientCertPolicy
+	if config.ClientCertPolicy != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return clientcertpolicy.New(ctx, next, *config.ClientCertPolicy, middlewareName)
 		}
 	}
 
@@ -187,6 +260,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// CORS
+	if config.CORS != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return cors.New(ctx, next, *config.CORS, middlewareName)
+		}
+	}
+
 	// CustomErrors
 	if config.Errors != nil {
 		if middleware != nil {
@@ -207,6 +290,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// ETag
+	if config.ETag != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return etag.New(ctx, next, *config.ETag, middlewareName)
+		}
+	}
+
 	// ForwardAuth
 	if config.ForwardAuth != nil {
 		if middleware != nil {
@@ -227,6 +320,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Introspection
+	if config.Introspection != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewIntrospection(ctx, next, *config.Introspection, middlewareName)
+		}
+	}
+
 	// IPWhiteList
 	if config.IPWhiteList != nil {
 		if middleware != nil {
@@ -247,6 +350,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// JWT
+	if config.JWT != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewJWT(ctx, next, *config.JWT, middlewareName)
+		}
+	}
+
+	// OIDC
+	if config.OIDC != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewOIDC(ctx, next, *config.OIDC, middlewareName)
+		}
+	}
+
 	// PassTLSClientCert
 	if config.PassTLSClientCert != nil {
 		if middleware != nil {
@@ -307,14 +430,38 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// ResourceHints
+	if config.ResourceHints != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return resourcehints.New(ctx, next, *config.ResourceHints, middlewareName)
+		}
+	}
+
 	// Retry
 	if config.Retry != nil {
 		if middleware != nil {
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			// FIXME missing metrics / accessLog
-			return retry.New(ctx, next, *config.Retry, retry.Listeners{}, middlewareName)
+			budget, err := b.retryBudget(ctx, middlewareName, config.Retry.Budget)
+			if err != nil {
+				return nil, err
+			}
+
+			return retry.New(ctx, next, *config.Retry, budget, b.retryListeners(ctx), middlewareName)
+		}
+	}
+
+	// RewriteBody
+	if config.RewriteBody != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return rewritebody.New(ctx, next, *config.RewriteBody, middlewareName)
 		}
 	}
 
@@ -342,7 +489,110 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
 	}
 
-	return tracing.Wrap(ctx, middleware), nil
+	if config.Optional {
+		middleware = optional.Wrap(ctx, middlewareName, middleware, b.middlewareSkippedCounter(ctx, middlewareName))
+	}
+
+	middleware = tracing.Wrap(ctx, middleware)
+
+	if serviceName, ok := b.serviceMetrics(ctx); ok {
+		switch {
+		case config.InFlightReq != nil:
+			middleware = metricsmiddleware.WrapShedHandler(b.metricsRegistry, serviceName, "inflightreq", middleware)
+		case config.RateLimit != nil:
+			middleware = metricsmiddleware.WrapShedHandler(b.metricsRegistry, serviceName, "ratelimit", middleware)
+		}
+	}
+
+	return accesslog.WrapMiddlewareDuration(middlewareName, middleware), nil
+}
+
+// serviceMetrics returns the name of the service the middleware is in front of,
+// and whether the metrics of this service have to be recorded.
+func (b *Builder) serviceMetrics(ctx context.Context) (string, bool) {
+	if b.metricsRegistry == nil || !b.metricsRegistry.IsSvcEnabled() {
+		return "", false
+	}
+
+	serviceName, ok := ctx.Value(serviceNameKey).(string)
+	return serviceName, ok && serviceName != ""
+}
+
+func (b *Builder) retryListeners(ctx context.Context) retry.Listeners {
+	// The retry attempts are only saved when the access log data table is in the request context.
+	listeners := retry.Listeners{&accesslog.SaveRetries{}}
+	if serviceName, ok := b.serviceMetrics(ctx); ok {
+		listeners = append(listeners, metricsmiddleware.NewRetryListener(b.metricsRegistry, serviceName))
+	}
+	return listeners
+}
+
+// retryBudget returns the retry budget of the middleware, shared by the routers using it in front of the same service.
+func (b *Builder) retryBudget(ctx context.Context, middlewareName string, config *dynamic.RetryBudget) (*retry.Budget, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	serviceName, _ := ctx.Value(serviceNameKey).(string)
+	key := serviceName + "/" + middlewareName
+
+	if budget, ok := b.retryBudgets[key]; ok {
+		return budget, nil
+	}
+
+	budget, err := retry.NewBudget(*config)
+	if err != nil {
+		return nil, err
+	}
+
+	b.retryBudgets[key] = budget
+	return budget, nil
+}
+
+func (b *Builder) middlewareSkippedCounter(ctx context.Context, middlewareName string) gokitmetrics.Counter {
+	if serviceName, ok := b.serviceMetrics(ctx); ok {
+		return b.metricsRegistry.ServiceMiddlewareSkippedReqsCounter().With("service", serviceName, "middleware", middlewareName)
+	}
+	return nil
+}
+
+// circuitBreaker returns the states of the circuit breaker middleware, shared by the routers using it in front of the same service.
+func (b *Builder) circuitBreaker(ctx context.Context, middlewareName string, config dynamic.CircuitBreaker) (*circuitbreaker.Breaker, error) {
+	serviceName, _ := ctx.Value(serviceNameKey).(string)
+	key := serviceName + "/" + middlewareName
+
+	if breaker, ok := b.circuitBreakers[key]; ok {
+		return breaker, nil
+	}
+
+	breaker, err := circuitbreaker.NewBreaker(config, middlewareName, serviceName, b.circuitBreakerListener(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	b.circuitBreakers[key] = breaker
+	return breaker, nil
+}
+
+// CircuitBreakers returns the states of the circuit breakers built so far.
+func (b *Builder) CircuitBreakers() []*circuitbreaker.Breaker {
+	breakers := make([]*circuitbreaker.Breaker, 0, len(b.circuitBreakers))
+	for _, breaker := range b.circuitBreakers {
+		breakers = append(breakers, breaker)
+	}
+	return breakers
+}
+
+func (b *Builder) circuitBreakerListener(ctx context.Context) circuitbreaker.Listener {
+	if serviceName, ok := b.serviceMetrics(ctx); ok {
+		return metricsmiddleware.NewCircuitBreakerListener(b.metricsRegistry, serviceName)
+	}
+	return nil
+}
+
+// AddServiceInContext adds the name of the service, the middlewares of the chain are in front of, to the context.
+func AddServiceInContext(ctx context.Context, serviceName string) context.Context {
+	return context.WithValue(ctx, serviceNameKey, serviceName)
 }
 
 func inSlice(element string, stack []string) bool {
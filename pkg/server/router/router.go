@@ -9,8 +9,10 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/middlewares/bodytimeout"
 	"github.com/containous/traefik/v2/pkg/middlewares/recovery"
 	"github.com/containous/traefik/v2/pkg/middlewares/tracing"
+	"github.com/containous/traefik/v2/pkg/panics"
 	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/server/middleware"
 	"github.com/containous/traefik/v2/pkg/server/provider"
@@ -41,6 +43,7 @@ type Manager struct {
 	chainBuilder       *middleware.ChainBuilder
 	modifierBuilder    responseModifierBuilder
 	conf               *runtime.Configuration
+	panicReports       *panics.Reports
 }
 
 // NewManager Creates a new Manager.
@@ -49,6 +52,7 @@ func NewManager(conf *runtime.Configuration,
 	middlewaresBuilder middlewareBuilder,
 	modifierBuilder responseModifierBuilder,
 	chainBuilder *middleware.ChainBuilder,
+	panicReports *panics.Reports,
 ) *Manager {
 	return &Manager{
 		routerHandlers:     make(map[string]http.Handler),
@@ -57,6 +61,7 @@ func NewManager(conf *runtime.Configuration,
 		modifierBuilder:    modifierBuilder,
 		chainBuilder:       chainBuilder,
 		conf:               conf,
+		panicReports:       panicReports,
 	}
 }
 
@@ -76,7 +81,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 		entryPointName := entryPointName
 		ctx := log.With(rootCtx, log.Str(log.EntryPointName, entryPointName))
 
-		handler, err := m.buildEntryPointHandler(ctx, routers)
+		handler, err := m.buildEntryPointHandler(ctx, entryPointName, routers)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
@@ -112,7 +117,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 	return entryPointHandlers
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.RouterInfo) (http.Handler, error) {
+func (m *Manager) buildEntryPointHandler(ctx context.Context, entryPointName string, configs map[string]*runtime.RouterInfo) (http.Handler, error) {
 	router, err := rules.NewRouter()
 	if err != nil {
 		return nil, err
@@ -141,7 +146,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 
 	chain := alice.New()
 	chain = chain.Append(func(next http.Handler) (http.Handler, error) {
-		return recovery.New(ctx, next, recoveryMiddlewareName)
+		return recovery.New(ctx, next, m.panicReports, entryPointName, recoveryMiddlewareName)
 	})
 
 	return chain.Then(router)
@@ -157,9 +162,39 @@ func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, rou
 		return nil, err
 	}
 
-	handlerWithAccessLog, err := alice.New(func(next http.Handler) (http.Handler, error) {
+	chain := alice.New(func(next http.Handler) (http.Handler, error) {
 		return accesslog.NewFieldHandler(next, accesslog.RouterName, routerName, nil), nil
-	}).Then(handler)
+	}, func(next http.Handler) (http.Handler, error) {
+		// The recovered panics are reported with the name of the router they occurred in.
+		return panics.WrapRouter(next, routerName), nil
+	})
+
+	if shutdown := m.chainBuilder.BuildShutdown(ctx, routerName, routerConfig.ShutdownOrder); shutdown != nil {
+		chain = chain.Append(shutdown)
+	}
+
+	// The response headers are removed once all the other middlewares of the router have set theirs.
+	if responseHeaders := m.chainBuilder.BuildResponseHeaders(ctx, routerName, routerConfig.KeepResponseHeaders); responseHeaders != nil {
+		chain = chain.Append(responseHeaders)
+	}
+
+	if bandwidth := m.chainBuilder.BuildBandwidth(ctx, routerName, routerConfig.Quota); bandwidth != nil {
+		chain = chain.Append(bandwidth)
+	}
+
+	if routerConfig.BodyTimeouts != nil {
+		chain = chain.Append(bodytimeout.WrapRouterHandler(ctx, routerName, *routerConfig.BodyTimeouts))
+	}
+
+	if routerConfig.DebugHeaders {
+		if debugHeaders := m.chainBuilder.BuildDebugHeaders(ctx, routerName); debugHeaders != nil {
+			chain = chain.Append(debugHeaders)
+		} else {
+			log.FromContext(ctx).Warn("Debug headers are enabled on the router, but no secret is configured to sign the debug tokens")
+		}
+	}
+
+	handlerWithAccessLog, err := chain.Then(handler)
 	if err != nil {
 		log.FromContext(ctx).Error(err)
 		m.routerHandlers[routerName] = handler
@@ -187,7 +222,8 @@ func (m *Manager) buildHTTPHandler(ctx context.Context, router *runtime.RouterIn
 		return nil, err
 	}
 
-	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
+	ctxMiddlewares := middleware.AddServiceInContext(ctx, provider.GetQualifiedName(ctx, router.Service))
+	mHandler := m.middlewaresBuilder.BuildChain(ctxMiddlewares, router.Middlewares)
 
 	tHandler := func(next http.Handler) (http.Handler, error) {
 		return tracing.NewForwarder(ctx, routerName, router.Service, next), nil
@@ -7,8 +7,11 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/connections"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/server/provider"
 	tcpservice "github.com/containous/traefik/v2/pkg/server/service/tcp"
@@ -27,23 +30,33 @@ func NewManager(conf *runtime.Configuration,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *traefiktls.Manager,
+	connectionTable *connections.Table,
+	metricsRegistry metrics.Registry,
+	catchAllPriorities map[string]int,
 ) *Manager {
 	return &Manager{
-		serviceManager: serviceManager,
-		httpHandlers:   httpHandlers,
-		httpsHandlers:  httpsHandlers,
-		tlsManager:     tlsManager,
-		conf:           conf,
+		serviceManager:     serviceManager,
+		httpHandlers:       httpHandlers,
+		httpsHandlers:      httpsHandlers,
+		tlsManager:         tlsManager,
+		connectionTable:    connectionTable,
+		metricsRegistry:    metricsRegistry,
+		catchAllPriorities: catchAllPriorities,
+		conf:               conf,
 	}
 }
 
 // Manager is a route/router manager.
 type Manager struct {
-	serviceManager *tcpservice.Manager
-	httpHandlers   map[string]http.Handler
-	httpsHandlers  map[string]http.Handler
-	tlsManager     *traefiktls.Manager
-	conf           *runtime.Configuration
+	serviceManager  *tcpservice.Manager
+	httpHandlers    map[string]http.Handler
+	httpsHandlers   map[string]http.Handler
+	tlsManager      *traefiktls.Manager
+	connectionTable *connections.Table
+	metricsRegistry metrics.Registry
+	// catchAllPriorities holds the default priority of the HostSNI(`*`) routers, keyed by entry point.
+	catchAllPriorities map[string]int
+	conf               *runtime.Configuration
 }
 
 func (m *Manager) getTCPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.TCPRouterInfo {
@@ -75,7 +88,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 
 		ctx := log.With(rootCtx, log.Str(log.EntryPointName, entryPointName))
 
-		handler, err := m.buildEntryPointHandler(ctx, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName])
+		handler, err := m.buildEntryPointHandler(ctx, entryPointName, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName], m.catchAllPriorities[entryPointName])
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
@@ -90,8 +103,24 @@ type nameAndConfig struct {
 	TLSConfig  *tls.Config
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP http.Handler, handlerHTTPS http.Handler) (*tcp.Router, error) {
+// tlsConfigKey identifies the TLS configuration of a router: its TLS options and its pinned certificate.
+type tlsConfigKey struct {
+	options     string
+	certificate string
+}
+
+func (k tlsConfigKey) String() string {
+	if k.certificate == "" {
+		return k.options
+	}
+	return fmt.Sprintf("%s (certificate %s)", k.options, k.certificate)
+}
+
+func (m *Manager) buildEntryPointHandler(ctx context.Context, entryPointName string, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP http.Handler, handlerHTTPS http.Handler, catchAllPriority int) (*tcp.Router, error) {
 	router := &tcp.Router{}
+	if m.metricsRegistry != nil && m.metricsRegistry.IsEpEnabled() {
+		router.SetTLSHandshakeMetrics(tcp.NewTLSHandshakeMetrics(m.metricsRegistry, entryPointName))
+	}
 	router.HTTPHandler(handlerHTTP)
 
 	defaultTLSConf, err := m.tlsManager.Get(defaultTLSStoreName, defaultTLSConfigName)
@@ -105,10 +134,10 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		router.AddRouteHTTPTLS("*", defaultTLSConf)
 	}
 
-	// Keyed by domain, then by options reference.
-	tlsOptionsForHostSNI := map[string]map[string]nameAndConfig{}
+	// Keyed by domain, then by options reference and certificate.
+	tlsOptionsForHostSNI := map[string]map[tlsConfigKey]nameAndConfig{}
 	for routerHTTPName, routerHTTPConfig := range configsHTTP {
-		if len(routerHTTPConfig.TLS.Options) == 0 || routerHTTPConfig.TLS.Options == defaultTLSConfigName {
+		if (len(routerHTTPConfig.TLS.Options) == 0 || routerHTTPConfig.TLS.Options == defaultTLSConfigName) && routerHTTPConfig.TLS.Certificate == "" {
 			continue
 		}
 
@@ -129,12 +158,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 
 		for _, domain := range domains {
 			if routerHTTPConfig.TLS != nil {
-				tlsOptionsName := routerHTTPConfig.TLS.Options
-				if tlsOptionsName != defaultTLSConfigName {
-					tlsOptionsName = provider.GetQualifiedName(ctxRouter, routerHTTPConfig.TLS.Options)
-				}
-
-				tlsConf, err := m.tlsManager.Get(defaultTLSStoreName, tlsOptionsName)
+				tlsConf, err := m.getTLSConfig(ctxRouter, routerHTTPConfig.TLS.Options, routerHTTPConfig.TLS.Certificate)
 				if err != nil {
 					routerHTTPConfig.AddError(err, true)
 					logger.Debug(err)
@@ -142,9 +166,10 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 				}
 
 				if tlsOptionsForHostSNI[domain] == nil {
-					tlsOptionsForHostSNI[domain] = make(map[string]nameAndConfig)
+					tlsOptionsForHostSNI[domain] = make(map[tlsConfigKey]nameAndConfig)
 				}
-				tlsOptionsForHostSNI[domain][routerHTTPConfig.TLS.Options] = nameAndConfig{
+				key := tlsConfigKey{options: routerHTTPConfig.TLS.Options, certificate: routerHTTPConfig.TLS.Certificate}
+				tlsOptionsForHostSNI[domain][key] = nameAndConfig{
 					routerName: routerHTTPName,
 					TLSConfig:  tlsConf,
 				}
@@ -155,7 +180,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 	logger := log.FromContext(ctx)
 	for hostSNI, tlsConfigs := range tlsOptionsForHostSNI {
 		if len(tlsConfigs) == 1 {
-			var optionsName string
+			var optionsName tlsConfigKey
 			var config *tls.Config
 			for k, v := range tlsConfigs {
 				optionsName = k
@@ -204,6 +229,23 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
+		if routerConfig.ProxyProtocol != nil && len(routerConfig.ProxyProtocol.TLVs) > 0 {
+			handler, err = tcp.NewProxyProtocolTLVsHandler(buildProxyProtocolTLVs(routerConfig.ProxyProtocol), handler)
+			if err != nil {
+				routerConfig.AddError(err, true)
+				logger.Error(err)
+				continue
+			}
+		}
+
+		if m.connectionTable != nil {
+			handler = tcp.NewTrackingHandler(m.connectionTable, routerName, handler)
+		}
+
+		if m.metricsRegistry != nil && m.metricsRegistry.IsRouterEnabled() {
+			handler = tcp.NewRouterMetricsHandler(m.metricsRegistry, routerName, handler)
+		}
+
 		domains, err := rules.ParseHostSNI(routerConfig.Rule)
 		if err != nil {
 			routerErr := fmt.Errorf("unknown rule %s", routerConfig.Rule)
@@ -212,39 +254,147 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
+		matchers, err := buildSNIMatchers(routerConfig, domains)
+		if err != nil {
+			routerConfig.AddError(err, true)
+			logger.Error(err)
+			continue
+		}
+
+		var tlsConf *tls.Config
+		if routerConfig.TLS != nil && !routerConfig.TLS.Passthrough {
+			tlsConf, err = m.getTLSConfig(ctxRouter, routerConfig.TLS.Options, routerConfig.TLS.Certificate)
+			if err != nil {
+				routerConfig.AddError(err, true)
+				logger.Debug(err)
+				continue
+			}
+		}
+
 		for _, domain := range domains {
+			if tcp.IsSNIPattern(domain) {
+				continue
+			}
+
 			logger.Debugf("Adding route %s on TCP", domain)
 			switch {
 			case routerConfig.TLS != nil:
 				if routerConfig.TLS.Passthrough {
 					router.AddRoute(domain, handler)
 				} else {
-					tlsOptionsName := routerConfig.TLS.Options
-
-					if len(tlsOptionsName) == 0 {
-						tlsOptionsName = defaultTLSConfigName
-					}
-
-					if tlsOptionsName != defaultTLSConfigName {
-						tlsOptionsName = provider.GetQualifiedName(ctxRouter, tlsOptionsName)
-					}
-
-					tlsConf, err := m.tlsManager.Get(defaultTLSStoreName, tlsOptionsName)
-					if err != nil {
-						routerConfig.AddError(err, true)
-						logger.Debug(err)
-						continue
-					}
-
 					router.AddRouteTLS(domain, handler, tlsConf)
 				}
+
+				if domain == "*" {
+					setCatchAllPriority(router, routerConfig, len(configsHTTP) > 0, catchAllPriority)
+				}
 			case domain == "*":
 				router.AddCatchAllNoTLS(handler)
 			default:
 				logger.Warn("TCP Router ignored, cannot specify a Host rule without TLS")
 			}
 		}
+
+		for _, matcher := range matchers {
+			logger.Debugf("Adding route %s on TCP with priority %d", matcher.Pattern, matcher.Priority)
+			switch {
+			case routerConfig.TLS == nil:
+				logger.Warn("TCP Router ignored, cannot specify a Host rule without TLS")
+			case routerConfig.TLS.Passthrough:
+				router.AddRouteMatcher(matcher, handler)
+			default:
+				router.AddRouteMatcherTLS(matcher, handler, tlsConf)
+			}
+		}
 	}
 
 	return router, nil
 }
+
+// getTLSConfig returns the TLS configuration built from the given TLS options,
+// presenting the given certificate of the default store if it is not empty.
+func (m *Manager) getTLSConfig(ctx context.Context, options, certificate string) (*tls.Config, error) {
+	tlsOptionsName := options
+	if len(tlsOptionsName) == 0 {
+		tlsOptionsName = defaultTLSConfigName
+	}
+
+	if tlsOptionsName != defaultTLSConfigName {
+		tlsOptionsName = provider.GetQualifiedName(ctx, tlsOptionsName)
+	}
+
+	if certificate != "" {
+		return m.tlsManager.GetWithCertificate(defaultTLSStoreName, tlsOptionsName, certificate)
+	}
+
+	return m.tlsManager.Get(defaultTLSStoreName, tlsOptionsName)
+}
+
+// setCatchAllPriority applies the priority of the HostSNI(`*`) router, or else the default one of the entry point,
+// and records it in the router runtime information.
+func setCatchAllPriority(router *tcp.Router, routerConfig *runtime.TCPRouterInfo, hasHTTPSRouters bool, defaultPriority int) {
+	priority := routerConfig.Priority
+	if priority == 0 {
+		priority = defaultPriority
+	}
+
+	// The * route is taken over by the HTTPS routers of the entry point, which are only used when no matcher matches.
+	if priority == 0 || hasHTTPSRouters {
+		return
+	}
+
+	router.SetCatchAllPriority(priority)
+
+	if routerConfig.SNIPriorities == nil {
+		routerConfig.SNIPriorities = make(map[string]int)
+	}
+	routerConfig.SNIPriorities["*"] = priority
+}
+
+// buildSNIMatchers creates the matchers of the wildcard HostSNI and HostSNIRegexp values of the router rule,
+// and records their effective priority in the router runtime information.
+func buildSNIMatchers(routerConfig *runtime.TCPRouterInfo, domains []string) ([]*tcp.SNIMatcher, error) {
+	expressions, err := rules.ParseHostSNIRegexp(routerConfig.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	var matchers []*tcp.SNIMatcher
+	for _, domain := range domains {
+		if !tcp.IsSNIPattern(domain) {
+			continue
+		}
+
+		matcher, err := tcp.NewHostSNIMatcher(domain, routerConfig.Priority)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	for _, expr := range expressions {
+		matcher, err := tcp.NewHostSNIRegexpMatcher(expr, routerConfig.Priority)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	if len(matchers) > 0 {
+		routerConfig.SNIPriorities = make(map[string]int, len(matchers))
+		for _, matcher := range matchers {
+			routerConfig.SNIPriorities[matcher.Pattern] = matcher.Priority
+		}
+	}
+
+	return matchers, nil
+}
+
+func buildProxyProtocolTLVs(config *dynamic.RouterTCPProxyProtocol) []tcp.ProxyProtocolTLV {
+	tlvs := make([]tcp.ProxyProtocolTLV, 0, len(config.TLVs))
+	for _, tlv := range config.TLVs {
+		tlvs = append(tlvs, tcp.ProxyProtocolTLV{Type: tlv.Type, Value: []byte(tlv.Value)})
+	}
+
+	return tlvs
+}
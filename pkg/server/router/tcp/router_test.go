@@ -6,9 +6,12 @@ import (
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/rules"
 	"github.com/containous/traefik/v2/pkg/server/service/tcp"
+	traefiktcp "github.com/containous/traefik/v2/pkg/tcp"
 	"github.com/containous/traefik/v2/pkg/tls"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRuntimeConfiguration(t *testing.T) {
@@ -132,6 +135,41 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 2,
 		},
+		{
+			desc: "Router with invalid HostSNIRegexp",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNIRegexp(`(foo`)",
+						TLS:         &dynamic.RouterTCPTLSConfig{Passthrough: true},
+					},
+				},
+				"bar": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`**.bar.foo`) || HostSNIRegexp(`[a-z]+\\.foo`)",
+						TLS:         &dynamic.RouterTCPTLSConfig{Passthrough: true},
+					},
+				},
+			},
+			expectedError: 1,
+		},
 		{
 			desc: "Router with unknown service",
 			serviceConfig: map[string]*runtime.TCPServiceInfo{
@@ -186,6 +224,46 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 2,
 		},
+		{
+			desc: "Router with an invalid PROXY protocol TLV",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							ProxyProtocol: &dynamic.TCPProxyProtocol{},
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`bar.foo`)",
+						ProxyProtocol: &dynamic.RouterTCPProxyProtocol{
+							TLVs: []dynamic.TCPProxyProtocolTLV{{Type: 0x01, Value: "h2"}},
+						},
+					},
+				},
+				"bar": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`foo.bar`)",
+						ProxyProtocol: &dynamic.RouterTCPProxyProtocol{
+							TLVs: []dynamic.TCPProxyProtocolTLV{{Type: 0xE0, Value: "tenant-a"}},
+						},
+					},
+				},
+			},
+			expectedError: 1,
+		},
 	}
 
 	for _, test := range testCases {
@@ -200,7 +278,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 				TCPServices: test.serviceConfig,
 				TCPRouters:  test.routerConfig,
 			}
-			serviceManager := tcp.NewManager(conf)
+			serviceManager := tcp.NewManager(conf, nil)
 			tlsManager := tls.NewManager()
 			tlsManager.UpdateConfigs(
 				context.Background(),
@@ -219,7 +297,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 				[]*tls.CertAndStores{})
 
 			routerManager := NewManager(conf, serviceManager,
-				nil, nil, tlsManager)
+				nil, nil, tlsManager, nil, nil, nil)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
 
@@ -241,3 +319,75 @@ func TestRuntimeConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSNIMatchers(t *testing.T) {
+	routerConfig := &runtime.TCPRouterInfo{
+		TCPRouter: &dynamic.TCPRouter{
+			Rule: "HostSNI(`foo.bar`, `*.foo.bar`) || HostSNIRegexp(`^[a-z]+\\.bar$`)",
+		},
+	}
+
+	domains, err := rules.ParseHostSNI(routerConfig.Rule)
+	require.NoError(t, err)
+
+	matchers, err := buildSNIMatchers(routerConfig, domains)
+	require.NoError(t, err)
+
+	require.Len(t, matchers, 2)
+	assert.Equal(t, map[string]int{"*.foo.bar": 9, `^[a-z]+\.bar$`: 13}, routerConfig.SNIPriorities)
+
+	routerConfig.Priority = 42
+
+	_, err = buildSNIMatchers(routerConfig, domains)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"*.foo.bar": 42, `^[a-z]+\.bar$`: 42}, routerConfig.SNIPriorities)
+}
+
+func TestSetCatchAllPriority(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		priority           int
+		defaultPriority    int
+		hasHTTPSRouters    bool
+		expectedPriorities map[string]int
+	}{
+		{
+			desc: "no priority",
+		},
+		{
+			desc:               "router priority",
+			priority:           42,
+			defaultPriority:    10,
+			expectedPriorities: map[string]int{"*": 42},
+		},
+		{
+			desc:               "entry point priority",
+			defaultPriority:    10,
+			expectedPriorities: map[string]int{"*": 10},
+		},
+		{
+			desc:            "taken over by the HTTPS routers",
+			priority:        42,
+			hasHTTPSRouters: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			routerConfig := &runtime.TCPRouterInfo{
+				TCPRouter: &dynamic.TCPRouter{
+					Rule:     "HostSNI(`*`)",
+					Priority: test.priority,
+				},
+			}
+
+			setCatchAllPriority(&traefiktcp.Router{}, routerConfig, test.hasHTTPSRouters, test.defaultPriority)
+
+			assert.Equal(t, test.expectedPriorities, routerConfig.SNIPriorities)
+		})
+	}
+}
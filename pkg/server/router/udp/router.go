@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/connections"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/server/provider"
 	udpservice "github.com/containous/traefik/v2/pkg/server/service/udp"
@@ -15,17 +16,20 @@ import (
 // NewManager Creates a new Manager.
 func NewManager(conf *runtime.Configuration,
 	serviceManager *udpservice.Manager,
+	connectionTable *connections.Table,
 ) *Manager {
 	return &Manager{
-		serviceManager: serviceManager,
-		conf:           conf,
+		serviceManager:  serviceManager,
+		connectionTable: connectionTable,
+		conf:            conf,
 	}
 }
 
 // Manager is a route/router manager.
 type Manager struct {
-	serviceManager *udpservice.Manager
-	conf           *runtime.Configuration
+	serviceManager  *udpservice.Manager
+	connectionTable *connections.Table
+	conf            *runtime.Configuration
 }
 
 func (m *Manager) getUDPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.UDPRouterInfo {
@@ -98,6 +102,10 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 			continue
 		}
 
+		if m.connectionTable != nil {
+			handler = udp.NewTrackingHandler(m.connectionTable, routerName, handler)
+		}
+
 		handlers = append(handlers, handler)
 	}
 
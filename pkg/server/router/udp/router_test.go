@@ -119,8 +119,8 @@ func TestRuntimeConfiguration(t *testing.T) {
 				UDPServices: test.serviceConfig,
 				UDPRouters:  test.routerConfig,
 			}
-			serviceManager := udp.NewManager(conf)
-			routerManager := NewManager(conf, serviceManager)
+			serviceManager := udp.NewManager(conf, nil)
+			routerManager := NewManager(conf, serviceManager, nil)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
 
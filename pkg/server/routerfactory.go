@@ -2,11 +2,16 @@ package server
 
 import (
 	"context"
+	"strings"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/connections"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
+	"github.com/containous/traefik/v2/pkg/panics"
 	"github.com/containous/traefik/v2/pkg/responsemodifiers"
 	"github.com/containous/traefik/v2/pkg/server/middleware"
 	"github.com/containous/traefik/v2/pkg/server/router"
@@ -27,13 +32,38 @@ type RouterFactory struct {
 
 	managerFactory *service.ManagerFactory
 
-	chainBuilder *middleware.ChainBuilder
-	tlsManager   *tls.Manager
+	chainBuilder    *middleware.ChainBuilder
+	tlsManager      *tls.Manager
+	connectionTable *connections.Table
+	metricsRegistry metrics.Registry
+	panicReports    *panics.Reports
+	circuitBreakers *circuitbreaker.Registry
+
+	internalListener *InternalListener
+
+	// catchAllPriorities holds the default priority of the HostSNI(`*`) TCP routers, keyed by entry point.
+	catchAllPriorities map[string]int
+
+	// dryRunProviders are the providers whose configuration is only validated, and reported in the API.
+	dryRunProviders map[string]struct{}
+}
+
+// RouterFactoryOptions holds the optional dependencies of a RouterFactory.
+type RouterFactoryOptions struct {
+	// ConnectionTable tracks the TCP connections and UDP sessions of the routers.
+	ConnectionTable *connections.Table
+	// MetricsRegistry records the metrics of the routers, services and middlewares.
+	MetricsRegistry metrics.Registry
+	// PanicReports keeps the reports of the panics recovered by the HTTP routers.
+	PanicReports *panics.Reports
+	// CircuitBreakers is updated with the circuit breakers of each new configuration.
+	CircuitBreakers *circuitbreaker.Registry
 }
 
 // NewRouterFactory creates a new RouterFactory.
-func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *service.ManagerFactory, tlsManager *tls.Manager, chainBuilder *middleware.ChainBuilder) *RouterFactory {
+func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *service.ManagerFactory, tlsManager *tls.Manager, chainBuilder *middleware.ChainBuilder, opts RouterFactoryOptions) *RouterFactory {
 	var entryPointsTCP, entryPointsUDP []string
+	catchAllPriorities := make(map[string]int)
 	for name, cfg := range staticConfiguration.EntryPoints {
 		protocol, err := cfg.GetProtocol()
 		if err != nil {
@@ -45,49 +75,253 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 			entryPointsUDP = append(entryPointsUDP, name)
 		} else {
 			entryPointsTCP = append(entryPointsTCP, name)
+			catchAllPriorities[name] = cfg.TCP.CatchAllPriority
 		}
 	}
 
+	dryRunProviders := make(map[string]struct{})
+	for _, name := range staticConfiguration.Providers.DryRunProviders() {
+		dryRunProviders[name] = struct{}{}
+	}
+
 	return &RouterFactory{
-		entryPointsTCP: entryPointsTCP,
-		entryPointsUDP: entryPointsUDP,
-		managerFactory: managerFactory,
-		tlsManager:     tlsManager,
-		chainBuilder:   chainBuilder,
+		entryPointsTCP:     entryPointsTCP,
+		entryPointsUDP:     entryPointsUDP,
+		managerFactory:     managerFactory,
+		tlsManager:         tlsManager,
+		chainBuilder:       chainBuilder,
+		connectionTable:    opts.ConnectionTable,
+		metricsRegistry:    opts.MetricsRegistry,
+		panicReports:       opts.PanicReports,
+		circuitBreakers:    opts.CircuitBreakers,
+		catchAllPriorities: catchAllPriorities,
+		dryRunProviders:    dryRunProviders,
 	}
 }
 
+// SetInternalListener sets the internal listener whose API is updated with the runtime configuration of the routers.
+func (f *RouterFactory) SetInternalListener(internalListener *InternalListener) {
+	f.internalListener = internalListener
+}
+
 // CreateRouters creates new TCPRouters and UDPRouters.
 func (f *RouterFactory) CreateRouters(conf dynamic.Configuration) (map[string]*tcpCore.Router, map[string]udpCore.Handler) {
 	ctx := context.Background()
 
-	rtConf := runtime.NewConfig(conf)
+	rtConf := runtime.NewConfig(f.withoutDryRun(conf))
 
 	// HTTP
 	serviceManager := f.managerFactory.Build(rtConf)
 
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.metricsRegistry)
 	responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
 
-	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, f.chainBuilder)
+	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, f.chainBuilder, f.panicReports)
 
 	handlersNonTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, false)
 	handlersTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, true)
 
+	if f.circuitBreakers != nil {
+		f.circuitBreakers.Set(middlewaresBuilder.CircuitBreakers())
+	}
+
 	serviceManager.LaunchHealthCheck()
 
 	// TCP
-	svcTCPManager := tcp.NewManager(rtConf)
+	svcTCPManager := tcp.NewManager(rtConf, f.metricsRegistry)
 
-	rtTCPManager := routertcp.NewManager(rtConf, svcTCPManager, handlersNonTLS, handlersTLS, f.tlsManager)
+	rtTCPManager := routertcp.NewManager(rtConf, svcTCPManager, handlersNonTLS, handlersTLS, f.tlsManager, f.connectionTable, f.metricsRegistry, f.catchAllPriorities)
 	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
 
+	svcTCPManager.LaunchHealthCheck()
+
 	// UDP
-	svcUDPManager := udp.NewManager(rtConf)
-	rtUDPManager := routerudp.NewManager(rtConf, svcUDPManager)
+	svcUDPManager := udp.NewManager(rtConf, udpServersTransports(conf))
+	rtUDPManager := routerudp.NewManager(rtConf, svcUDPManager, f.connectionTable)
 	routersUDP := rtUDPManager.BuildHandlers(ctx, f.entryPointsUDP)
 
+	svcUDPManager.LaunchHealthCheck()
+
+	if len(f.dryRunProviders) > 0 {
+		f.addDryRun(ctx, conf, rtConf)
+	}
+
 	rtConf.PopulateUsedBy()
 
+	if f.internalListener != nil {
+		f.internalListener.Switch(rtConf)
+	}
+
 	return routersTCP, routersUDP
 }
+
+func (f *RouterFactory) isDryRun(qualifiedName string) bool {
+	idx := strings.LastIndex(qualifiedName, "@")
+	if idx < 0 {
+		return false
+	}
+
+	_, ok := f.dryRunProviders[qualifiedName[idx+1:]]
+	return ok
+}
+
+// withoutDryRun returns the configuration without the routers, middlewares and services of the providers in dry-run mode.
+func (f *RouterFactory) withoutDryRun(conf dynamic.Configuration) dynamic.Configuration {
+	if len(f.dryRunProviders) == 0 {
+		return conf
+	}
+
+	if conf.HTTP != nil {
+		httpConf := *conf.HTTP
+		httpConf.Routers = make(map[string]*dynamic.Router)
+		for name, rt := range conf.HTTP.Routers {
+			if !f.isDryRun(name) {
+				httpConf.Routers[name] = rt
+			}
+		}
+		httpConf.Middlewares = make(map[string]*dynamic.Middleware)
+		for name, mi := range conf.HTTP.Middlewares {
+			if !f.isDryRun(name) {
+				httpConf.Middlewares[name] = mi
+			}
+		}
+		httpConf.Services = make(map[string]*dynamic.Service)
+		for name, svc := range conf.HTTP.Services {
+			if !f.isDryRun(name) {
+				httpConf.Services[name] = svc
+			}
+		}
+		conf.HTTP = &httpConf
+	}
+
+	if conf.TCP != nil {
+		tcpConf := *conf.TCP
+		tcpConf.Routers = make(map[string]*dynamic.TCPRouter)
+		for name, rt := range conf.TCP.Routers {
+			if !f.isDryRun(name) {
+				tcpConf.Routers[name] = rt
+			}
+		}
+		tcpConf.Services = make(map[string]*dynamic.TCPService)
+		for name, svc := range conf.TCP.Services {
+			if !f.isDryRun(name) {
+				tcpConf.Services[name] = svc
+			}
+		}
+		conf.TCP = &tcpConf
+	}
+
+	if conf.UDP != nil {
+		udpConf := *conf.UDP
+		udpConf.Routers = make(map[string]*dynamic.UDPRouter)
+		for name, rt := range conf.UDP.Routers {
+			if !f.isDryRun(name) {
+				udpConf.Routers[name] = rt
+			}
+		}
+		udpConf.Services = make(map[string]*dynamic.UDPService)
+		for name, svc := range conf.UDP.Services {
+			if !f.isDryRun(name) {
+				udpConf.Services[name] = svc
+			}
+		}
+		conf.UDP = &udpConf
+	}
+
+	return conf
+}
+
+// addDryRun builds the whole configuration, including the providers in dry-run mode, to validate it,
+// and adds the resulting routers, middlewares and services of the providers in dry-run mode to rtConf, as shadows.
+// The handlers built are dropped, so they never receive traffic.
+func (f *RouterFactory) addDryRun(ctx context.Context, conf dynamic.Configuration, rtConf *runtime.Configuration) {
+	shadowConf := runtime.NewConfig(conf)
+
+	serviceManager := f.managerFactory.Build(shadowConf)
+
+	middlewaresBuilder := middleware.NewBuilder(shadowConf.Middlewares, serviceManager, f.metricsRegistry)
+	responseModifierFactory := responsemodifiers.NewBuilder(shadowConf.Middlewares)
+
+	routerManager := router.NewManager(shadowConf, serviceManager, middlewaresBuilder, responseModifierFactory, f.chainBuilder, f.panicReports)
+
+	handlersNonTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, false)
+	handlersTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, true)
+
+	rtTCPManager := routertcp.NewManager(shadowConf, tcp.NewManager(shadowConf, nil), handlersNonTLS, handlersTLS, f.tlsManager, nil, nil, f.catchAllPriorities)
+	rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
+
+	rtUDPManager := routerudp.NewManager(shadowConf, udp.NewManager(shadowConf, udpServersTransports(conf)), nil)
+	rtUDPManager.BuildHandlers(ctx, f.entryPointsUDP)
+
+	for name, rt := range shadowConf.Routers {
+		if f.isDryRun(name) {
+			if rtConf.Routers == nil {
+				rtConf.Routers = make(map[string]*runtime.RouterInfo)
+			}
+			rt.Shadow = true
+			rtConf.Routers[name] = rt
+		}
+	}
+	for name, mi := range shadowConf.Middlewares {
+		if f.isDryRun(name) {
+			if rtConf.Middlewares == nil {
+				rtConf.Middlewares = make(map[string]*runtime.MiddlewareInfo)
+			}
+			mi.Shadow = true
+			rtConf.Middlewares[name] = mi
+		}
+	}
+	for name, svc := range shadowConf.Services {
+		if f.isDryRun(name) {
+			if rtConf.Services == nil {
+				rtConf.Services = make(map[string]*runtime.ServiceInfo)
+			}
+			svc.Shadow = true
+			rtConf.Services[name] = svc
+		}
+	}
+	for name, rt := range shadowConf.TCPRouters {
+		if f.isDryRun(name) {
+			if rtConf.TCPRouters == nil {
+				rtConf.TCPRouters = make(map[string]*runtime.TCPRouterInfo)
+			}
+			rt.Shadow = true
+			rtConf.TCPRouters[name] = rt
+		}
+	}
+	for name, svc := range shadowConf.TCPServices {
+		if f.isDryRun(name) {
+			if rtConf.TCPServices == nil {
+				rtConf.TCPServices = make(map[string]*runtime.TCPServiceInfo)
+			}
+			svc.Shadow = true
+			rtConf.TCPServices[name] = svc
+		}
+	}
+	for name, rt := range shadowConf.UDPRouters {
+		if f.isDryRun(name) {
+			if rtConf.UDPRouters == nil {
+				rtConf.UDPRouters = make(map[string]*runtime.UDPRouterInfo)
+			}
+			rt.Shadow = true
+			rtConf.UDPRouters[name] = rt
+		}
+	}
+	for name, svc := range shadowConf.UDPServices {
+		if f.isDryRun(name) {
+			if rtConf.UDPServices == nil {
+				rtConf.UDPServices = make(map[string]*runtime.UDPServiceInfo)
+			}
+			svc.Shadow = true
+			rtConf.UDPServices[name] = svc
+		}
+	}
+}
+
+// udpServersTransports returns the serversTransports of the UDP configuration, if any.
+func udpServersTransports(conf dynamic.Configuration) map[string]*dynamic.UDPServersTransport {
+	if conf.UDP == nil {
+		return nil
+	}
+	return conf.UDP.ServersTransports
+}
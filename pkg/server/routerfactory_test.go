@@ -1,18 +1,23 @@
 package server
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/containous/traefik/v2/pkg/api"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/provider/file"
 	"github.com/containous/traefik/v2/pkg/server/middleware"
 	"github.com/containous/traefik/v2/pkg/server/service"
 	th "github.com/containous/traefik/v2/pkg/testhelpers"
 	"github.com/containous/traefik/v2/pkg/tls"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReuseService(t *testing.T) {
@@ -47,10 +52,10 @@ func TestReuseService(t *testing.T) {
 		),
 	)
 
-	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry())
+	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), api.BuilderOptions{})
 	tlsManager := tls.NewManager()
 
-	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil))
+	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), RouterFactoryOptions{MetricsRegistry: metrics.NewVoidRegistry()})
 
 	entryPointsHandlers, _ := factory.CreateRouters(dynamic.Configuration{HTTP: dynamicConfigs})
 
@@ -181,10 +186,10 @@ func TestServerResponseEmptyBackend(t *testing.T) {
 				},
 			}
 
-			managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry())
+			managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), api.BuilderOptions{})
 			tlsManager := tls.NewManager()
 
-			factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil))
+			factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), RouterFactoryOptions{MetricsRegistry: metrics.NewVoidRegistry()})
 
 			entryPointsHandlers, _ := factory.CreateRouters(dynamic.Configuration{HTTP: test.config(testServer.URL)})
 
@@ -220,10 +225,10 @@ func TestInternalServices(t *testing.T) {
 		),
 	)
 
-	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry())
+	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), api.BuilderOptions{})
 	tlsManager := tls.NewManager()
 
-	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil))
+	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), RouterFactoryOptions{MetricsRegistry: metrics.NewVoidRegistry()})
 
 	entryPointsHandlers, _ := factory.CreateRouters(dynamic.Configuration{HTTP: dynamicConfigs})
 
@@ -234,3 +239,68 @@ func TestInternalServices(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, responseRecorderOk.Result().StatusCode, "status code")
 }
+
+func TestDryRunProviders(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	staticConfig := static.Configuration{
+		EntryPoints: map[string]*static.EntryPoint{
+			"web": {},
+		},
+		Providers: &static.Providers{
+			File: &file.Provider{DryRun: true},
+		},
+	}
+
+	dynamicConfigs := th.BuildConfiguration(
+		th.WithRouters(
+			th.WithRouter("live@docker",
+				th.WithEntryPoints("web"),
+				th.WithServiceName("bar@docker"),
+				th.WithRule("Path(`/live`)")),
+			th.WithRouter("shadow@file",
+				th.WithEntryPoints("web"),
+				th.WithServiceName("bar@docker"),
+				th.WithRule("Path(`/shadow`)")),
+			th.WithRouter("broken@file",
+				th.WithEntryPoints("web"),
+				th.WithServiceName("unknown@file"),
+				th.WithRule("Path(`/broken`)")),
+		),
+		th.WithLoadBalancerServices(th.WithService("bar@docker",
+			th.WithServers(th.WithServer(testServer.URL))),
+		),
+	)
+	conf := dynamic.Configuration{HTTP: dynamicConfigs}
+
+	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), api.BuilderOptions{})
+	tlsManager := tls.NewManager()
+
+	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), RouterFactoryOptions{MetricsRegistry: metrics.NewVoidRegistry()})
+
+	entryPointsHandlers, _ := factory.CreateRouters(conf)
+
+	for path, expected := range map[string]int{"/live": http.StatusOK, "/shadow": http.StatusNotFound, "/broken": http.StatusNotFound} {
+		recorder := httptest.NewRecorder()
+		entryPointsHandlers["web"].GetHTTPHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, testServer.URL+path, nil))
+
+		assert.Equal(t, expected, recorder.Code, path)
+	}
+
+	rtConf := runtime.NewConfig(factory.withoutDryRun(conf))
+	factory.addDryRun(context.Background(), conf, rtConf)
+
+	require.Contains(t, rtConf.Routers, "live@docker")
+	assert.False(t, rtConf.Routers["live@docker"].Shadow)
+
+	require.Contains(t, rtConf.Routers, "shadow@file")
+	assert.True(t, rtConf.Routers["shadow@file"].Shadow)
+	assert.Equal(t, runtime.StatusEnabled, rtConf.Routers["shadow@file"].Status)
+
+	require.Contains(t, rtConf.Routers, "broken@file")
+	assert.True(t, rtConf.Routers["broken@file"].Shadow)
+	assert.NotEmpty(t, rtConf.Routers["broken@file"].Err)
+}
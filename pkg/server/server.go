@@ -4,11 +4,15 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/middlewares/shutdown"
 	"github.com/containous/traefik/v2/pkg/safe"
 	"github.com/containous/traefik/v2/pkg/server/middleware"
 )
@@ -20,6 +24,10 @@ type Server struct {
 	udpEntryPoints UDPEntryPoints
 	chainBuilder   *middleware.ChainBuilder
 
+	internalListener *InternalListener
+
+	shutdownCoordinator *shutdown.Coordinator
+
 	accessLoggerMiddleware *accesslog.Handler
 
 	signals  chan os.Signal
@@ -47,6 +55,16 @@ func NewServer(routinesPool *safe.Pool, entryPoints TCPEntryPoints, entryPointsU
 	return srv
 }
 
+// SetShutdownCoordinator sets the coordinator told about the stages of the shutdown, which also bounds its duration.
+func (s *Server) SetShutdownCoordinator(coordinator *shutdown.Coordinator) {
+	s.shutdownCoordinator = coordinator
+}
+
+// SetInternalListener sets the internal listener started and stopped along with the entry points.
+func (s *Server) SetInternalListener(internalListener *InternalListener) {
+	s.internalListener = internalListener
+}
+
 // Start starts the server and Stop/Close it when context is Done.
 func (s *Server) Start(ctx context.Context) {
 	go func() {
@@ -59,6 +77,13 @@ func (s *Server) Start(ctx context.Context) {
 
 	s.tcpEntryPoints.Start()
 	s.udpEntryPoints.Start()
+
+	if s.internalListener != nil {
+		if err := s.internalListener.Start(ctx); err != nil {
+			log.FromContext(ctx).Error(err)
+		}
+	}
+
 	s.watcher.Start()
 
 	s.routinesPool.GoCtx(s.listenSignals)
@@ -73,12 +98,113 @@ func (s *Server) Wait() {
 func (s *Server) Stop() {
 	defer log.WithoutContext().Info("Server stopped")
 
-	s.tcpEntryPoints.Stop()
-	s.udpEntryPoints.Stop()
+	timeout := shutdownTimeout(s.tcpEntryPoints, s.udpEntryPoints)
+	if s.shutdownCoordinator != nil && s.shutdownCoordinator.Timeout() > 0 {
+		timeout = s.shutdownCoordinator.Timeout()
+	}
+
+	// All the stages share the same deadline, the last ones being cut short if the first ones take too long.
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, stage := range shutdownStages(s.tcpEntryPoints, s.udpEntryPoints) {
+		log.WithoutContext().Debugf("Stopping entry points with shutdown order %d", stage.order)
+
+		if s.shutdownCoordinator != nil {
+			s.shutdownCoordinator.StartStage(stage.order)
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func(eps TCPEntryPoints) {
+			defer wg.Done()
+			eps.Stop(ctx)
+		}(stage.tcp)
+		go func(eps UDPEntryPoints) {
+			defer wg.Done()
+			eps.Stop(ctx)
+		}(stage.udp)
+		wg.Wait()
+	}
+
+	if s.internalListener != nil {
+		listenerCtx, listenerCancel := context.WithTimeout(ctx, static.DefaultGraceTimeout)
+		s.internalListener.Shutdown(listenerCtx)
+		listenerCancel()
+	}
 
 	s.stopChan <- true
 }
 
+// shutdownTimeout returns the default duration of the whole shutdown,
+// which is the longest lifeCycle of the entry points, so that the stages do not add up their grace timeouts.
+func shutdownTimeout(tcpEntryPoints TCPEntryPoints, udpEntryPoints UDPEntryPoints) time.Duration {
+	timeout := static.DefaultGraceTimeout
+
+	lifeCycle := func(transport *static.EntryPointsTransport) {
+		if transport == nil || transport.LifeCycle == nil {
+			return
+		}
+
+		if d := time.Duration(transport.LifeCycle.RequestAcceptGraceTimeout + transport.LifeCycle.GraceTimeOut); d > timeout {
+			timeout = d
+		}
+	}
+
+	for _, ep := range tcpEntryPoints {
+		lifeCycle(ep.transportConfiguration)
+	}
+
+	for _, ep := range udpEntryPoints {
+		lifeCycle(ep.transportConfiguration)
+	}
+
+	return timeout
+}
+
+// shutdownStage holds the entry points sharing the same shutdown order.
+type shutdownStage struct {
+	order int
+	tcp   TCPEntryPoints
+	udp   UDPEntryPoints
+}
+
+// shutdownStages groups the entry points by shutdown order, sorted by ascending order.
+// The entry points of a stage are stopped concurrently, once the ones of the previous stage are stopped.
+func shutdownStages(tcpEntryPoints TCPEntryPoints, udpEntryPoints UDPEntryPoints) []*shutdownStage {
+	stages := make(map[int]*shutdownStage)
+	getStage := func(transport *static.EntryPointsTransport) *shutdownStage {
+		var order int
+		if transport != nil && transport.LifeCycle != nil {
+			order = transport.LifeCycle.ShutdownOrder
+		}
+
+		if _, ok := stages[order]; !ok {
+			stages[order] = &shutdownStage{order: order, tcp: TCPEntryPoints{}, udp: UDPEntryPoints{}}
+		}
+		return stages[order]
+	}
+
+	for name, ep := range tcpEntryPoints {
+		getStage(ep.transportConfiguration).tcp[name] = ep
+	}
+
+	for name, ep := range udpEntryPoints {
+		getStage(ep.transportConfiguration).udp[name] = ep
+	}
+
+	var sorted []*shutdownStage
+	for _, stage := range stages {
+		sorted = append(sorted, stage)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].order < sorted[j].order
+	})
+
+	return sorted
+}
+
 // Close destroys the server.
 func (s *Server) Close() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
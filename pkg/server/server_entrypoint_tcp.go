@@ -6,6 +6,8 @@ import (
 	stdlog "log"
 	"net"
 	"net/http"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,7 +16,10 @@ import (
 	"github.com/containous/traefik/v2/pkg/ip"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/bodytimeout"
 	"github.com/containous/traefik/v2/pkg/middlewares/forwardedheaders"
+	"github.com/containous/traefik/v2/pkg/middlewares/overload"
+	traefikproxyprotocol "github.com/containous/traefik/v2/pkg/proxyprotocol"
 	"github.com/containous/traefik/v2/pkg/safe"
 	"github.com/containous/traefik/v2/pkg/server/router"
 	"github.com/containous/traefik/v2/pkg/tcp"
@@ -83,7 +88,7 @@ func (eps TCPEntryPoints) Start() {
 }
 
 // Stop the server entry points.
-func (eps TCPEntryPoints) Stop() {
+func (eps TCPEntryPoints) Stop(ctx context.Context) {
 	var wg sync.WaitGroup
 
 	for epn, ep := range eps {
@@ -92,7 +97,7 @@ func (eps TCPEntryPoints) Stop() {
 		go func(entryPointName string, entryPoint *TCPEntryPoint) {
 			defer wg.Done()
 
-			ctx := log.With(context.Background(), log.Str(log.EntryPointName, entryPointName))
+			ctx := log.With(ctx, log.Str(log.EntryPointName, entryPointName))
 			entryPoint.Shutdown(ctx)
 
 			log.FromContext(ctx).Debugf("Entry point %s closed", entryPointName)
@@ -102,6 +107,13 @@ func (eps TCPEntryPoints) Stop() {
 	wg.Wait()
 }
 
+// SetOverloadGuard sets the guard pausing the acceptance of new connections while Traefik is overloaded.
+func (eps TCPEntryPoints) SetOverloadGuard(guard *overload.Guard) {
+	for _, ep := range eps {
+		ep.overloadGuard = guard
+	}
+}
+
 // Switch the TCP routers.
 func (eps TCPEntryPoints) Switch(routersTCP map[string]*tcp.Router) {
 	for entryPointName, rt := range routersTCP {
@@ -112,11 +124,13 @@ func (eps TCPEntryPoints) Switch(routersTCP map[string]*tcp.Router) {
 // TCPEntryPoint is the TCP server.
 type TCPEntryPoint struct {
 	listener               net.Listener
+	handler                tcp.Handler
 	switcher               *tcp.HandlerSwitcher
 	transportConfiguration *static.EntryPointsTransport
 	tracker                *connectionTracker
 	httpServer             *httpServer
 	httpsServer            *httpServer
+	overloadGuard          *overload.Guard
 }
 
 // NewTCPEntryPoint creates a new TCPEntryPoint.
@@ -147,8 +161,19 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*T
 	tcpSwitcher := &tcp.HandlerSwitcher{}
 	tcpSwitcher.Switch(router)
 
+	var handler tcp.Handler = tcpSwitcher
+	if configuration.TLSFilter != nil {
+		reject, err := buildTLSFilter(configuration.TLSFilter)
+		if err != nil {
+			return nil, fmt.Errorf("error creating TLS filter: %w", err)
+		}
+
+		handler = tcp.NewHelloFilter(tcpSwitcher, reject)
+	}
+
 	return &TCPEntryPoint{
 		listener:               listener,
+		handler:                handler,
 		switcher:               tcpSwitcher,
 		transportConfiguration: configuration.Transport,
 		tracker:                tracker,
@@ -163,6 +188,11 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 	logger.Debugf("Start TCP Server")
 
 	for {
+		if e.overloadGuard != nil && e.overloadGuard.Overloaded() {
+			logger.Debug("Pausing the acceptance of new connections, Traefik being overloaded")
+			e.overloadGuard.Wait(ctx)
+		}
+
 		conn, err := e.listener.Accept()
 		if err != nil {
 			logger.Error(err)
@@ -196,7 +226,7 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 				}
 			}
 
-			e.switcher.ServeTCP(newTrackedConnection(writeCloser, e.tracker))
+			e.handler.ServeTCP(newTrackedConnection(writeCloser, e.tracker))
 		})
 	}
 }
@@ -208,7 +238,10 @@ func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 	reqAcceptGraceTimeOut := time.Duration(e.transportConfiguration.LifeCycle.RequestAcceptGraceTimeout)
 	if reqAcceptGraceTimeOut > 0 {
 		logger.Infof("Waiting %s for incoming requests to cease", reqAcceptGraceTimeOut)
-		time.Sleep(reqAcceptGraceTimeOut)
+		select {
+		case <-time.After(reqAcceptGraceTimeOut):
+		case <-ctx.Done():
+		}
 	}
 
 	graceTimeOut := time.Duration(e.transportConfiguration.LifeCycle.GraceTimeOut)
@@ -303,6 +336,12 @@ func (c *writeCloserWrapper) CloseWrite() error {
 // implementation, if any was found within the underlying conn.
 func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
 	switch typedConn := conn.(type) {
+	case *traefikproxyprotocol.Conn:
+		underlying, err := writeCloser(typedConn.Conn)
+		if err != nil {
+			return nil, err
+		}
+		return &writeCloserWrapper{writeCloser: underlying, Conn: typedConn}, nil
 	case *proxyprotocol.Conn:
 		underlying, err := writeCloser(typedConn.Conn)
 		if err != nil {
@@ -372,9 +411,31 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 
 	log.FromContext(ctx).Infof("Enabling ProxyProtocol for trusted IPs %v", entryPoint.ProxyProtocol.TrustedIPs)
 
-	return proxyprotocol.NewDefaultListener(listener).
+	ppListener := proxyprotocol.NewDefaultListener(listener).
 		WithSourceChecker(sourceCheck).
-		WithLogger(proxyProtocolLogger{Logger: log.FromContext(ctx)}), nil
+		WithLogger(proxyProtocolLogger{Logger: log.FromContext(ctx)})
+
+	return proxyProtocolListener{Listener: ppListener}, nil
+}
+
+// proxyProtocolListener marks the addresses read from the PROXY protocol headers of the accepted connections,
+// so that they are reported to the HTTP handlers.
+type proxyProtocolListener struct {
+	net.Listener
+}
+
+func (l proxyProtocolListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	ppConn, ok := conn.(*proxyprotocol.Conn)
+	if !ok {
+		return conn, nil
+	}
+
+	return traefikproxyprotocol.NewConn(ppConn, ppConn.Conn), nil
 }
 
 func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.Listener, error) {
@@ -395,6 +456,84 @@ func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.List
 	return listener, nil
 }
 
+// buildTLSFilter returns the function giving the reason why a ClientHello is rejected by the TLS filter, if any.
+func buildTLSFilter(config *static.TLSFilter) (func(hello *tcp.ClientHello) string, error) {
+	allowedServerNames, err := compileRegexps(config.AllowedServerNames)
+	if err != nil {
+		return nil, err
+	}
+
+	deniedServerNames, err := compileRegexps(config.DeniedServerNames)
+	if err != nil {
+		return nil, err
+	}
+
+	deniedJA3 := make(map[string]struct{})
+	for _, fingerprint := range config.DeniedJA3Fingerprints {
+		deniedJA3[strings.ToLower(fingerprint)] = struct{}{}
+	}
+
+	return func(hello *tcp.ClientHello) string {
+		if hello.ServerName == "" {
+			if config.RequireServerName {
+				return "missing SNI"
+			}
+		} else {
+			serverName := strings.ToLower(hello.ServerName)
+
+			if len(allowedServerNames) > 0 && !matchAny(allowedServerNames, serverName) {
+				return fmt.Sprintf("SNI %q is not allowed", serverName)
+			}
+
+			if matchAny(deniedServerNames, serverName) {
+				return fmt.Sprintf("SNI %q is denied", serverName)
+			}
+		}
+
+		if len(config.AllowedALPNProtocols) > 0 && !containsAny(config.AllowedALPNProtocols, hello.ALPNProtocols) {
+			return fmt.Sprintf("ALPN protocols %v are not allowed", hello.ALPNProtocols)
+		}
+
+		if _, ok := deniedJA3[hello.JA3]; ok {
+			return fmt.Sprintf("JA3 fingerprint %s is denied", hello.JA3)
+		}
+
+		return ""
+	}, nil
+}
+
+func compileRegexps(patterns []string) ([]*regexp.Regexp, error) {
+	var exps []*regexp.Regexp
+	for _, pattern := range patterns {
+		exp, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server name pattern %q: %w", pattern, err)
+		}
+		exps = append(exps, exp)
+	}
+	return exps, nil
+}
+
+func matchAny(exps []*regexp.Regexp, value string) bool {
+	for _, exp := range exps {
+		if exp.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(allowed, values []string) bool {
+	for _, value := range values {
+		for _, a := range allowed {
+			if a == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func newConnectionTracker() *connectionTracker {
 	return &connectionTracker{
 		conns: make(map[net.Conn]struct{}),
@@ -490,6 +629,9 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		ReadTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.ReadTimeout),
 		WriteTimeout: time.Duration(configuration.Transport.RespondingTimeouts.WriteTimeout),
 		IdleTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.IdleTimeout),
+		ConnContext: func(ctx context.Context, conn net.Conn) context.Context {
+			return traefikproxyprotocol.ContextWithConn(bodytimeout.ContextWithConn(ctx, conn), conn)
+		},
 	}
 
 	listener := newHTTPForwarder(ln)
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/proxyprotocol"
 	"github.com/containous/traefik/v2/pkg/tcp"
 	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -221,3 +222,135 @@ func TestReadTimeoutWithFirstByte(t *testing.T) {
 		t.Error("Timeout while read")
 	}
 }
+
+func TestProxyProtocolInfo(t *testing.T) {
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address:          ":0",
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+		ProxyProtocol:    &static.ProxyProtocol{Insecure: true},
+	})
+	require.NoError(t, err)
+
+	type result struct {
+		remoteAddr string
+		info       proxyprotocol.Info
+		ok         bool
+	}
+	results := make(chan result, 2)
+
+	router := &tcp.Router{}
+	router.HTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		info, ok := proxyprotocol.GetInfo(req)
+		results <- result{remoteAddr: req.RemoteAddr, info: info, ok: ok}
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	conn, err := startEntrypoint(entryPoint, router)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	_, err = conn.Write([]byte("PROXY TCP4 192.168.1.10 10.0.0.1 51234 443\r\nGET / HTTP/1.1\r\nHost: foo\r\n\r\n"))
+	require.NoError(t, err)
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	res := <-results
+	require.True(t, res.ok)
+	assert.Equal(t, "192.168.1.10:51234", res.remoteAddr)
+	assert.Equal(t, "192.168.1.10:51234", res.info.Source.String())
+	assert.Equal(t, "10.0.0.1:443", res.info.Destination.String())
+
+	// Without the header, the connection is accepted, but there is no PROXY protocol information.
+	plainConn, err := net.Dial("tcp", entryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = plainConn.Close() }()
+
+	_, err = plainConn.Write([]byte("GET / HTTP/1.1\r\nHost: foo\r\n\r\n"))
+	require.NoError(t, err)
+
+	resp, err = http.ReadResponse(bufio.NewReader(plainConn), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	res = <-results
+	assert.False(t, res.ok)
+	assert.Equal(t, plainConn.LocalAddr().String(), res.remoteAddr)
+}
+
+func TestBuildTLSFilter(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   *static.TLSFilter
+		hello    *tcp.ClientHello
+		rejected bool
+	}{
+		{
+			desc:   "empty filter",
+			config: &static.TLSFilter{},
+			hello:  &tcp.ClientHello{},
+		},
+		{
+			desc:     "missing required SNI",
+			config:   &static.TLSFilter{RequireServerName: true},
+			hello:    &tcp.ClientHello{},
+			rejected: true,
+		},
+		{
+			desc:   "allowed SNI",
+			config: &static.TLSFilter{AllowedServerNames: []string{`\.example\.com$`}},
+			hello:  &tcp.ClientHello{ServerName: "Foo.Example.com"},
+		},
+		{
+			desc:     "SNI not allowed",
+			config:   &static.TLSFilter{AllowedServerNames: []string{`\.example\.com$`}},
+			hello:    &tcp.ClientHello{ServerName: "foo.example.org"},
+			rejected: true,
+		},
+		{
+			desc:     "denied SNI",
+			config:   &static.TLSFilter{DeniedServerNames: []string{`^internal\.`}},
+			hello:    &tcp.ClientHello{ServerName: "internal.example.com"},
+			rejected: true,
+		},
+		{
+			desc:   "allowed ALPN protocol",
+			config: &static.TLSFilter{AllowedALPNProtocols: []string{"h2"}},
+			hello:  &tcp.ClientHello{ALPNProtocols: []string{"h2", "http/1.1"}},
+		},
+		{
+			desc:     "ALPN protocols not allowed",
+			config:   &static.TLSFilter{AllowedALPNProtocols: []string{"h2"}},
+			hello:    &tcp.ClientHello{ALPNProtocols: []string{"http/1.1"}},
+			rejected: true,
+		},
+		{
+			desc:     "denied JA3 fingerprint",
+			config:   &static.TLSFilter{DeniedJA3Fingerprints: []string{"E7D705A3286E19EA42F587B344EE6865"}},
+			hello:    &tcp.ClientHello{JA3: "e7d705a3286e19ea42f587b344ee6865"},
+			rejected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			reject, err := buildTLSFilter(test.config)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.rejected, reject(test.hello) != "")
+		})
+	}
+}
+
+func TestBuildTLSFilterInvalidPattern(t *testing.T) {
+	_, err := buildTLSFilter(&static.TLSFilter{DeniedServerNames: []string{"("}})
+	assert.Error(t, err)
+}
@@ -46,7 +46,7 @@ func (eps UDPEntryPoints) Start() {
 }
 
 // Stop makes all the entry points stop listening, and release associated resources.
-func (eps UDPEntryPoints) Stop() {
+func (eps UDPEntryPoints) Stop(ctx context.Context) {
 	var wg sync.WaitGroup
 
 	for epn, ep := range eps {
@@ -55,7 +55,7 @@ func (eps UDPEntryPoints) Stop() {
 		go func(entryPointName string, entryPoint *UDPEntryPoint) {
 			defer wg.Done()
 
-			ctx := log.With(context.Background(), log.Str(log.EntryPointName, entryPointName))
+			ctx := log.With(ctx, log.Str(log.EntryPointName, entryPointName))
 			entryPoint.Shutdown(ctx)
 
 			log.FromContext(ctx).Debugf("Entry point %s closed", entryPointName)
@@ -120,10 +120,21 @@ func (ep *UDPEntryPoint) Shutdown(ctx context.Context) {
 	reqAcceptGraceTimeOut := time.Duration(ep.transportConfiguration.LifeCycle.RequestAcceptGraceTimeout)
 	if reqAcceptGraceTimeOut > 0 {
 		logger.Infof("Waiting %s for incoming requests to cease", reqAcceptGraceTimeOut)
-		time.Sleep(reqAcceptGraceTimeOut)
+		select {
+		case <-time.After(reqAcceptGraceTimeOut):
+		case <-ctx.Done():
+		}
 	}
 
 	graceTimeOut := time.Duration(ep.transportConfiguration.LifeCycle.GraceTimeOut)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < graceTimeOut {
+		// The grace timeout is cut short by the deadline of the whole shutdown.
+		graceTimeOut = time.Until(deadline)
+		if graceTimeOut < 0 {
+			graceTimeOut = 0
+		}
+	}
+
 	if err := ep.listener.Shutdown(graceTimeOut); err != nil {
 		logger.Error(err)
 	}
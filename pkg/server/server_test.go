@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/middlewares/shutdown"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownStages(t *testing.T) {
+	transport := func(order int) *static.EntryPointsTransport {
+		return &static.EntryPointsTransport{LifeCycle: &static.LifeCycle{ShutdownOrder: order}}
+	}
+
+	web := &TCPEntryPoint{transportConfiguration: transport(0)}
+	websecure := &TCPEntryPoint{transportConfiguration: transport(0)}
+	health := &TCPEntryPoint{transportConfiguration: transport(10)}
+	dns := &UDPEntryPoint{transportConfiguration: transport(5)}
+	metrics := &UDPEntryPoint{transportConfiguration: transport(10)}
+
+	stages := shutdownStages(
+		TCPEntryPoints{"web": web, "websecure": websecure, "health": health},
+		UDPEntryPoints{"dns": dns, "metrics": metrics},
+	)
+
+	expected := []*shutdownStage{
+		{
+			order: 0,
+			tcp:   TCPEntryPoints{"web": web, "websecure": websecure},
+			udp:   UDPEntryPoints{},
+		},
+		{
+			order: 5,
+			tcp:   TCPEntryPoints{},
+			udp:   UDPEntryPoints{"dns": dns},
+		},
+		{
+			order: 10,
+			tcp:   TCPEntryPoints{"health": health},
+			udp:   UDPEntryPoints{"metrics": metrics},
+		},
+	}
+
+	assert.Equal(t, expected, stages)
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	transport := func(requestAcceptGraceTimeout, graceTimeOut time.Duration) *static.EntryPointsTransport {
+		return &static.EntryPointsTransport{LifeCycle: &static.LifeCycle{
+			RequestAcceptGraceTimeout: types.Duration(requestAcceptGraceTimeout),
+			GraceTimeOut:              types.Duration(graceTimeOut),
+		}}
+	}
+
+	testCases := []struct {
+		desc     string
+		tcp      TCPEntryPoints
+		udp      UDPEntryPoints
+		expected time.Duration
+	}{
+		{
+			desc:     "no entry points",
+			expected: static.DefaultGraceTimeout,
+		},
+		{
+			desc: "longest lifeCycle of the entry points",
+			tcp: TCPEntryPoints{
+				"web":    {transportConfiguration: transport(5*time.Second, 20*time.Second)},
+				"health": {transportConfiguration: transport(0, 15*time.Second)},
+			},
+			udp: UDPEntryPoints{
+				"dns": {transportConfiguration: transport(10*time.Second, 10*time.Second)},
+			},
+			expected: 25 * time.Second,
+		},
+		{
+			desc: "lifeCycles shorter than the default grace timeout",
+			tcp: TCPEntryPoints{
+				"web": {transportConfiguration: transport(0, time.Second)},
+			},
+			expected: static.DefaultGraceTimeout,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, shutdownTimeout(test.tcp, test.udp))
+		})
+	}
+}
+
+func TestServerStop_deadline(t *testing.T) {
+	newEntryPoint := func(order int) *TCPEntryPoint {
+		transport := &static.EntryPointsTransport{}
+		transport.SetDefaults()
+		transport.LifeCycle.RequestAcceptGraceTimeout = types.Duration(time.Second)
+		transport.LifeCycle.ShutdownOrder = order
+
+		entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+			Address:          "127.0.0.1:0",
+			Transport:        transport,
+			ForwardedHeaders: &static.ForwardedHeaders{},
+		})
+		require.NoError(t, err)
+
+		return entryPoint
+	}
+
+	coordinator := shutdown.NewCoordinator(200 * time.Millisecond)
+
+	srv := &Server{
+		tcpEntryPoints:      TCPEntryPoints{"web": newEntryPoint(0), "health": newEntryPoint(10)},
+		udpEntryPoints:      UDPEntryPoints{},
+		shutdownCoordinator: coordinator,
+		stopChan:            make(chan bool, 1),
+	}
+
+	start := time.Now()
+	srv.Stop()
+
+	// The requestAcceptGraceTimeout of the stages would add up to 2s without the deadline of the whole shutdown.
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.True(t, coordinator.Reached(10))
+}
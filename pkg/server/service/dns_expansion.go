@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/healthcheck"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+const defaultDNSRefreshInterval = 30 * time.Second
+
+type ipResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// dnsRefresher runs the refresh of the expanded servers of the current configuration,
+// and keeps the last DNS answers from one configuration to the next.
+type dnsRefresher struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	// resolved holds the last successful expansion of each server, keyed by server URL.
+	resolved map[string][]*url.URL
+}
+
+func newDNSRefresher() *dnsRefresher {
+	return &dnsRefresher{resolved: make(map[string][]*url.URL)}
+}
+
+func (r *dnsRefresher) lastResolved(server string) []*url.URL {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.resolved[server]
+}
+
+func (r *dnsRefresher) setResolved(server string, expanded []*url.URL) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resolved[server] = expanded
+}
+
+// launch starts the periodic refresh of the expanded servers,
+// and stops the one of the servers of the previous configuration.
+func (r *dnsRefresher) launch(expanders []*dnsExpander) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	for _, expander := range expanders {
+		expander := expander
+		safe.Go(func() {
+			expander.run(log.With(ctx, log.Str(log.ServiceName, expander.serviceName)))
+		})
+	}
+}
+
+// dnsExpander keeps the servers of a load-balancer in sync with the IP addresses their hostname resolves to,
+// so that each IP address is balanced, and health checked, as an individual server.
+type dnsExpander struct {
+	serviceName string
+	servers     []*url.URL
+	interval    time.Duration
+	resolver    ipResolver
+	refresher   *dnsRefresher
+
+	lb          healthcheck.Balancer
+	serviceInfo *runtime.ServiceInfo
+
+	mu sync.RWMutex
+	// current holds the expanded servers, keyed by URL.
+	current map[string]*url.URL
+	// hosts maps the host of the expanded servers to the host of the server they are expanded from.
+	hosts map[string]string
+}
+
+func newDNSExpander(serviceName string, servers []dynamic.Server, config *dynamic.DNSExpansion, refresher *dnsRefresher) (*dnsExpander, error) {
+	interval := defaultDNSRefreshInterval
+	if config.RefreshInterval != "" {
+		var err error
+		interval, err = time.ParseDuration(config.RefreshInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DNS refresh interval: %w", err)
+		}
+
+		if interval <= 0 {
+			return nil, fmt.Errorf("DNS refresh interval must be greater than zero: %s", config.RefreshInterval)
+		}
+	}
+
+	expander := &dnsExpander{
+		serviceName: serviceName,
+		interval:    interval,
+		resolver:    net.DefaultResolver,
+		refresher:   refresher,
+		current:     make(map[string]*url.URL),
+		hosts:       make(map[string]string),
+	}
+
+	for _, server := range servers {
+		u, err := url.Parse(server.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing server URL %s: %w", server.URL, err)
+		}
+
+		// The TLS server name would be the IP address instead of the hostname of the server.
+		if u.Scheme == "https" {
+			return nil, fmt.Errorf("the DNS expansion cannot be used with the HTTPS server %s", server.URL)
+		}
+
+		expander.servers = append(expander.servers, u)
+	}
+
+	return expander, nil
+}
+
+// seed adds to the load-balancer the servers of the previous DNS answers, without resolving the hostnames,
+// so that building the configuration does not wait for the DNS.
+func (e *dnsExpander) seed(ctx context.Context) {
+	desired := make(map[string]*url.URL)
+	hosts := make(map[string]string)
+	for _, server := range e.servers {
+		expanded := e.refresher.lastResolved(server.String())
+		if net.ParseIP(server.Hostname()) != nil {
+			expanded = []*url.URL{server}
+		}
+
+		for _, u := range expanded {
+			desired[u.String()] = u
+			hosts[u.Host] = server.Host
+		}
+	}
+
+	e.update(ctx, desired, hosts)
+}
+
+// refresh resolves the hostname of the servers, and updates the servers of the load-balancer accordingly.
+func (e *dnsExpander) refresh(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	desired := make(map[string]*url.URL)
+	hosts := make(map[string]string)
+	for _, server := range e.servers {
+		expanded, err := e.expand(ctx, server)
+		if err != nil {
+			logger.Errorf("Unable to resolve the server %s, keeping its previous addresses: %v", server, err)
+			expanded = e.refresher.lastResolved(server.String())
+		} else {
+			e.refresher.setResolved(server.String(), expanded)
+		}
+
+		for _, u := range expanded {
+			desired[u.String()] = u
+			hosts[u.Host] = server.Host
+		}
+	}
+
+	e.update(ctx, desired, hosts)
+}
+
+func (e *dnsExpander) update(ctx context.Context, desired map[string]*url.URL, hosts map[string]string) {
+	logger := log.FromContext(ctx)
+
+	e.mu.Lock()
+	previous := e.current
+	e.current = desired
+	e.hosts = hosts
+	e.mu.Unlock()
+
+	enabled := make(map[string]struct{})
+	for _, u := range e.lb.Servers() {
+		if _, ok := desired[u.String()]; ok {
+			enabled[u.String()] = struct{}{}
+			continue
+		}
+
+		logger.Debugf("Removing server %s, its hostname no longer resolves to it", u)
+		if err := e.lb.RemoveServer(u); err != nil {
+			logger.Error(err)
+		}
+		e.removeServerStatus(u)
+	}
+
+	for key, u := range previous {
+		if _, ok := desired[key]; !ok {
+			e.removeServerStatus(u)
+		}
+	}
+
+	for key, u := range desired {
+		if _, ok := enabled[key]; ok {
+			continue
+		}
+
+		// An expanded server missing from the load-balancer has been disabled by the health check,
+		// which is in charge of enabling it again.
+		if _, ok := previous[key]; ok {
+			continue
+		}
+
+		logger.Debugf("Creating server %s", u)
+		if err := e.lb.UpsertServer(u, roundrobin.Weight(1)); err != nil {
+			logger.Errorf("Error adding server %s to load balancer: %v", u, err)
+		}
+	}
+}
+
+func (e *dnsExpander) expand(ctx context.Context, server *url.URL) ([]*url.URL, error) {
+	host := server.Hostname()
+	if net.ParseIP(host) != nil {
+		return []*url.URL{server}, nil
+	}
+
+	addrs, err := e.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	port := server.Port()
+	if port == "" {
+		port = "80"
+	}
+
+	var expanded []*url.URL
+	for _, addr := range addrs {
+		u := *server
+		u.Host = net.JoinHostPort(addr.IP.String(), port)
+		expanded = append(expanded, &u)
+	}
+
+	sort.Slice(expanded, func(i, j int) bool {
+		return expanded[i].Host < expanded[j].Host
+	})
+
+	return expanded, nil
+}
+
+func (e *dnsExpander) isCurrent(u *url.URL) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_, ok := e.current[u.String()]
+	return ok
+}
+
+func (e *dnsExpander) removeServerStatus(u *url.URL) {
+	if e.serviceInfo != nil {
+		e.serviceInfo.RemoveServerStatus(u.String())
+	}
+}
+
+// originalHost sets the Host header of the forwarded requests to the host of the server
+// the selected IP address is expanded from.
+func (e *dnsExpander) originalHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		e.mu.RLock()
+		host, ok := e.hosts[req.URL.Host]
+		e.mu.RUnlock()
+
+		if ok {
+			req.Host = host
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+func (e *dnsExpander) run(ctx context.Context) {
+	e.refresh(ctx)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.refresh(ctx)
+		}
+	}
+}
+
+// expandedBalancer prevents the health check from enabling again the expanded servers
+// whose hostname no longer resolves to them.
+type expandedBalancer struct {
+	healthcheck.BalancerHandler
+	expander *dnsExpander
+}
+
+func (b *expandedBalancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	if !b.expander.isCurrent(u) {
+		return fmt.Errorf("the hostname of the server %s no longer resolves to it", u)
+	}
+
+	return b.BalancerHandler.UpsertServer(u, options...)
+}
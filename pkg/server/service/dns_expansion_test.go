@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	var addrs []net.IPAddr
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func serverURLs(lb *roundrobin.RoundRobin) []string {
+	var urls []string
+	for _, u := range lb.Servers() {
+		urls = append(urls, u.String())
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+func TestDNSExpander_refresh(t *testing.T) {
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	expander, err := newDNSExpander("foo", []dynamic.Server{
+		{URL: "http://backend:8080"},
+		{URL: "http://other"},
+		{URL: "http://10.0.0.9:80"},
+	}, &dynamic.DNSExpansion{}, newDNSRefresher())
+	require.NoError(t, err)
+
+	resolver := fakeResolver{
+		"backend": {"10.0.0.1", "10.0.0.2"},
+		"other":   {"10.0.1.1", "fd00::1"},
+	}
+	expander.resolver = resolver
+	expander.lb = lb
+
+	expander.refresh(context.Background())
+	assert.Equal(t, []string{
+		"http://10.0.0.1:8080",
+		"http://10.0.0.2:8080",
+		"http://10.0.0.9:80",
+		"http://10.0.1.1:80",
+		"http://[fd00::1]:80",
+	}, serverURLs(lb))
+
+	// The health check disables a server.
+	disabled, err := url.Parse("http://10.0.0.2:8080")
+	require.NoError(t, err)
+	require.NoError(t, lb.RemoveServer(disabled))
+
+	// The DNS answer changes, and the resolution of a hostname fails.
+	resolver["backend"] = []string{"10.0.0.2", "10.0.0.3"}
+	delete(resolver, "other")
+
+	expander.refresh(context.Background())
+	assert.Equal(t, []string{
+		"http://10.0.0.3:8080",
+		"http://10.0.0.9:80",
+		"http://10.0.1.1:80",
+		"http://[fd00::1]:80",
+	}, serverURLs(lb))
+
+	// The health check enables the server again, once its hostname no longer resolves to it.
+	resolver["backend"] = []string{"10.0.0.3"}
+	require.NoError(t, lb.UpsertServer(disabled))
+
+	expander.refresh(context.Background())
+	assert.Equal(t, []string{
+		"http://10.0.0.3:8080",
+		"http://10.0.0.9:80",
+		"http://10.0.1.1:80",
+		"http://[fd00::1]:80",
+	}, serverURLs(lb))
+}
+
+func TestDNSExpander_seed(t *testing.T) {
+	refresher := newDNSRefresher()
+
+	previous, err := newDNSExpander("foo", []dynamic.Server{{URL: "http://backend:8080"}}, &dynamic.DNSExpansion{}, refresher)
+	require.NoError(t, err)
+
+	previousLB, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	previous.resolver = fakeResolver{"backend": {"10.0.0.1"}}
+	previous.lb = previousLB
+	previous.refresh(context.Background())
+
+	// The expander of the next configuration starts with the previous DNS answers, without resolving the hostnames.
+	expander, err := newDNSExpander("foo", []dynamic.Server{
+		{URL: "http://backend:8080"},
+		{URL: "http://other:8080"},
+		{URL: "http://10.0.0.9:80"},
+	}, &dynamic.DNSExpansion{}, refresher)
+	require.NoError(t, err)
+
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	expander.resolver = fakeResolver{}
+	expander.lb = lb
+	expander.seed(context.Background())
+
+	assert.Equal(t, []string{"http://10.0.0.1:8080", "http://10.0.0.9:80"}, serverURLs(lb))
+}
+
+func TestExpandedBalancer_UpsertServer(t *testing.T) {
+	expander, err := newDNSExpander("foo", []dynamic.Server{{URL: "http://backend:8080"}}, &dynamic.DNSExpansion{}, newDNSRefresher())
+	require.NoError(t, err)
+
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	resolver := fakeResolver{"backend": {"10.0.0.1", "10.0.0.2"}}
+	expander.resolver = resolver
+	expander.lb = lb
+	expander.refresh(context.Background())
+
+	balancer := &expandedBalancer{BalancerHandler: lb, expander: expander}
+
+	// The health check disables a server, which the DNS then stops resolving to.
+	disabled, err := url.Parse("http://10.0.0.2:8080")
+	require.NoError(t, err)
+	require.NoError(t, balancer.RemoveServer(disabled))
+
+	resolver["backend"] = []string{"10.0.0.1"}
+	expander.refresh(context.Background())
+
+	// The health check cannot enable it again.
+	assert.Error(t, balancer.UpsertServer(disabled))
+	assert.Equal(t, []string{"http://10.0.0.1:8080"}, serverURLs(lb))
+}
+
+func TestDNSExpander_originalHost(t *testing.T) {
+	expander, err := newDNSExpander("foo", []dynamic.Server{{URL: "http://backend:8080"}}, &dynamic.DNSExpansion{}, newDNSRefresher())
+	require.NoError(t, err)
+
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	expander.resolver = fakeResolver{"backend": {"10.0.0.1"}}
+	expander.lb = lb
+	expander.refresh(context.Background())
+
+	var host string
+	handler := expander.originalHost(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		host = req.Host
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "http://10.0.0.1:8080/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "backend:8080", host)
+}
+
+func TestNewDNSExpander_invalidRefreshInterval(t *testing.T) {
+	for _, interval := range []string{"foo", "-1s"} {
+		_, err := newDNSExpander("foo", nil, &dynamic.DNSExpansion{RefreshInterval: interval}, newDNSRefresher())
+		assert.Error(t, err, interval)
+	}
+}
+
+func TestNewDNSExpander_https(t *testing.T) {
+	_, err := newDNSExpander("foo", []dynamic.Server{{URL: "https://secure"}}, &dynamic.DNSExpansion{}, newDNSRefresher())
+	assert.Error(t, err)
+}
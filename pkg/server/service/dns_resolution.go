@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/log"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/miekg/dns"
+)
+
+// hostLookup resolves a hostname, and returns its IP addresses along with the TTL of the answer.
+type hostLookup func(ctx context.Context, host string) ([]string, time.Duration, error)
+
+type resolvedHost struct {
+	ips     []string
+	expires time.Time
+}
+
+// dnsResolver dials the servers by the IP addresses their hostname resolves to,
+// caching each DNS answer for its TTL, clamped between a minimum and a maximum.
+// When an answer changes, the connections still open to the IP addresses which are no longer part of it are stale:
+// they are closed as soon as they are idle, so that the keep-alive pools do not stay pinned to the previous addresses.
+type dnsResolver struct {
+	dialer *net.Dialer
+	minTTL time.Duration
+	maxTTL time.Duration
+	lookup hostLookup
+
+	staleConnsGauge gokitmetrics.Gauge
+	closeIdleConns  func()
+
+	mu    sync.Mutex
+	hosts map[string]*resolvedHost
+	conns map[*trackedConn]struct{}
+}
+
+func newDNSResolver(config *static.DNSResolution, dialer *net.Dialer, staleConnsGauge gokitmetrics.Gauge) *dnsResolver {
+	resolver := &dnsResolver{
+		dialer:          dialer,
+		minTTL:          time.Duration(config.MinTTL),
+		maxTTL:          time.Duration(config.MaxTTL),
+		staleConnsGauge: staleConnsGauge,
+		closeIdleConns:  func() {},
+		hosts:           make(map[string]*resolvedHost),
+		conns:           make(map[*trackedConn]struct{}),
+	}
+
+	resolvConfig := config.ResolvConfig
+	resolver.lookup = func(ctx context.Context, host string) ([]string, time.Duration, error) {
+		return lookupHost(ctx, resolvConfig, host, resolver.minTTL)
+	}
+
+	return resolver
+}
+
+// DialContext connects to the given address, resolving its host with the cached DNS answers.
+func (r *dnsResolver) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if net.ParseIP(host) != nil {
+		return r.dialer.DialContext(ctx, network, addr)
+	}
+
+	ips, err := r.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialErr error
+	for _, ip := range ips {
+		conn, err := r.dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err != nil {
+			dialErr = err
+			continue
+		}
+
+		return r.track(conn, host, ip), nil
+	}
+
+	return nil, dialErr
+}
+
+// resolve returns the IP addresses of the given host, from the cache if its answer has not expired yet.
+func (r *dnsResolver) resolve(ctx context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	resolved, ok := r.hosts[host]
+	r.mu.Unlock()
+
+	if ok && time.Now().Before(resolved.expires) {
+		return resolved.ips, nil
+	}
+
+	return r.refreshHost(ctx, host)
+}
+
+// refreshHost resolves the given host again, and marks as stale the connections to the IP addresses it no longer resolves to.
+// When the resolution fails, the previous answer is kept for the minimum TTL.
+func (r *dnsResolver) refreshHost(ctx context.Context, host string) ([]string, error) {
+	ips, ttl, err := r.lookup(ctx, host)
+	if err != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		previous, ok := r.hosts[host]
+		if !ok {
+			return nil, err
+		}
+
+		log.FromContext(ctx).Errorf("Unable to resolve %s, keeping its previous addresses: %v", host, err)
+		previous.expires = time.Now().Add(r.minTTL)
+		return previous.ips, nil
+	}
+
+	current := make(map[string]struct{}, len(ips))
+	for _, ip := range ips {
+		current[ip] = struct{}{}
+	}
+
+	r.mu.Lock()
+	r.hosts[host] = &resolvedHost{ips: ips, expires: time.Now().Add(r.clamp(ttl))}
+
+	var stale bool
+	for conn := range r.conns {
+		if conn.host != host || conn.stale {
+			continue
+		}
+
+		if _, ok := current[conn.ip]; !ok {
+			log.FromContext(ctx).Debugf("%s no longer resolves to %s, closing its connections once idle", host, conn.ip)
+			conn.stale = true
+			stale = true
+		}
+	}
+
+	if stale {
+		r.updateStaleConnsGauge(host)
+	}
+	r.mu.Unlock()
+
+	// Closing the idle connections untracks them, hence it must be done without holding the lock.
+	if stale {
+		r.closeIdleConns()
+	}
+
+	return ips, nil
+}
+
+func (r *dnsResolver) clamp(ttl time.Duration) time.Duration {
+	if ttl < r.minTTL {
+		return r.minTTL
+	}
+
+	if ttl > r.maxTTL {
+		return r.maxTTL
+	}
+
+	return ttl
+}
+
+// refresh resolves again the expired hosts which still have open connections, forgets the other expired hosts,
+// and closes the stale connections which became idle since the last refresh.
+func (r *dnsResolver) refresh(ctx context.Context) {
+	now := time.Now()
+
+	r.mu.Lock()
+	inUse := make(map[string]struct{})
+	var stale bool
+	for conn := range r.conns {
+		inUse[conn.host] = struct{}{}
+		stale = stale || conn.stale
+	}
+
+	var expired []string
+	for host, resolved := range r.hosts {
+		if now.Before(resolved.expires) {
+			continue
+		}
+
+		if _, ok := inUse[host]; !ok {
+			delete(r.hosts, host)
+			continue
+		}
+
+		expired = append(expired, host)
+	}
+	r.mu.Unlock()
+
+	if stale {
+		r.closeIdleConns()
+	}
+
+	for _, host := range expired {
+		_, _ = r.refreshHost(ctx, host)
+	}
+}
+
+func (r *dnsResolver) run(ctx context.Context) {
+	ticker := time.NewTicker(r.minTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+func (r *dnsResolver) track(conn net.Conn, host, ip string) net.Conn {
+	tracked := &trackedConn{Conn: conn, host: host, ip: ip, resolver: r}
+
+	r.mu.Lock()
+	r.conns[tracked] = struct{}{}
+	r.mu.Unlock()
+
+	return tracked
+}
+
+func (r *dnsResolver) untrack(conn *trackedConn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.conns, conn)
+	if conn.stale {
+		r.updateStaleConnsGauge(conn.host)
+	}
+}
+
+// updateStaleConnsGauge must be called with the lock held.
+func (r *dnsResolver) updateStaleConnsGauge(host string) {
+	if r.staleConnsGauge == nil {
+		return
+	}
+
+	var count int
+	for conn := range r.conns {
+		if conn.host == host && conn.stale {
+			count++
+		}
+	}
+
+	r.staleConnsGauge.With("host", host).Set(float64(count))
+}
+
+// trackedConn is a connection to a server, opened to one of the IP addresses its hostname resolved to.
+type trackedConn struct {
+	net.Conn
+
+	host     string
+	ip       string
+	resolver *dnsResolver
+	// stale is guarded by the lock of the resolver.
+	stale bool
+
+	closeOnce sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.resolver.untrack(c)
+	})
+
+	return c.Conn.Close()
+}
+
+// lookupHost resolves the given host with the nameservers of the resolv.conf file,
+// trying the search domains it defines, and returns the lowest TTL of the answer.
+// The hosts not known by the nameservers (e.g. defined in /etc/hosts) are resolved by the system resolver,
+// and cached for the given default TTL.
+func lookupHost(ctx context.Context, resolvConfig, host string, defaultTTL time.Duration) ([]string, time.Duration, error) {
+	config, err := dns.ClientConfigFromFile(resolvConfig)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid resolver configuration file %s: %w", resolvConfig, err)
+	}
+
+	client := &dns.Client{Timeout: 5 * time.Second}
+
+	for _, name := range config.NameList(host) {
+		ips, ttl, err := exchangeAddresses(ctx, client, config, name)
+		if err != nil {
+			log.FromContext(ctx).Debugf("Unable to resolve %s: %v", name, err)
+			continue
+		}
+
+		if len(ips) > 0 {
+			return ips, ttl, nil
+		}
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var ips []string
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP.String())
+	}
+
+	return ips, defaultTTL, nil
+}
+
+func exchangeAddresses(ctx context.Context, client *dns.Client, config *dns.ClientConfig, name string) ([]string, time.Duration, error) {
+	var ips []string
+	var ttl uint32
+	for _, qType := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		msg := &dns.Msg{}
+		msg.SetQuestion(name, qType)
+
+		resp, err := exchange(ctx, client, config, msg)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, rr := range resp.Answer {
+			switch record := rr.(type) {
+			case *dns.A:
+				ips = append(ips, record.A.String())
+			case *dns.AAAA:
+				ips = append(ips, record.AAAA.String())
+			}
+
+			// The CNAME records of the chain bound the TTL of the answer as well.
+			if ttl == 0 || rr.Header().Ttl < ttl {
+				ttl = rr.Header().Ttl
+			}
+		}
+	}
+
+	sort.Strings(ips)
+
+	return ips, time.Duration(ttl) * time.Second, nil
+}
+
+func exchange(ctx context.Context, client *dns.Client, config *dns.ClientConfig, msg *dns.Msg) (*dns.Msg, error) {
+	var err error
+	for _, server := range config.Servers {
+		var resp *dns.Msg
+		resp, _, err = client.ExchangeContext(ctx, msg, net.JoinHostPort(server, config.Port))
+		if err != nil {
+			continue
+		}
+
+		if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+			err = fmt.Errorf("unexpected response code from %s: %s", server, dns.RcodeToString[resp.Rcode])
+			continue
+		}
+
+		return resp, nil
+	}
+
+	if err == nil {
+		err = errors.New("no nameserver configured")
+	}
+
+	return nil, err
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/types"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hostGauge struct {
+	mu     *sync.Mutex
+	host   string
+	values map[string]float64
+}
+
+func newHostGauge() *hostGauge {
+	return &hostGauge{mu: &sync.Mutex{}, values: make(map[string]float64)}
+}
+
+func (g *hostGauge) With(labelValues ...string) gokitmetrics.Gauge {
+	return &hostGauge{mu: g.mu, host: labelValues[1], values: g.values}
+}
+
+func (g *hostGauge) Set(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.values[g.host] = value
+}
+
+func (g *hostGauge) Add(delta float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.values[g.host] += delta
+}
+
+func (g *hostGauge) value(host string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.values[host]
+}
+
+type fakeLookup struct {
+	mu      sync.Mutex
+	ips     map[string][]string
+	ttl     time.Duration
+	lookups int
+}
+
+func (l *fakeLookup) lookup(_ context.Context, host string) ([]string, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lookups++
+
+	ips, ok := l.ips[host]
+	if !ok {
+		return nil, 0, errors.New("no such host")
+	}
+	return ips, l.ttl, nil
+}
+
+func newTestDNSResolver(t *testing.T, lookup *fakeLookup, gauge gokitmetrics.Gauge) *dnsResolver {
+	t.Helper()
+
+	resolver := newDNSResolver(&static.DNSResolution{
+		MinTTL: types.Duration(5 * time.Second),
+		MaxTTL: types.Duration(time.Minute),
+	}, &net.Dialer{}, gauge)
+	resolver.lookup = lookup.lookup
+
+	return resolver
+}
+
+func TestDNSResolver_clamp(t *testing.T) {
+	resolver := newTestDNSResolver(t, &fakeLookup{}, nil)
+
+	assert.Equal(t, 5*time.Second, resolver.clamp(0))
+	assert.Equal(t, 30*time.Second, resolver.clamp(30*time.Second))
+	assert.Equal(t, time.Minute, resolver.clamp(time.Hour))
+}
+
+func TestDNSResolver_DialContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	go func() {
+		for {
+			if _, err := listener.Accept(); err != nil {
+				return
+			}
+		}
+	}()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	lookup := &fakeLookup{
+		ips: map[string][]string{"backend": {"127.0.0.1"}},
+		ttl: time.Hour,
+	}
+	gauge := newHostGauge()
+	resolver := newTestDNSResolver(t, lookup, gauge)
+
+	var idleClosed int
+	resolver.closeIdleConns = func() { idleClosed++ }
+
+	conn, err := resolver.DialContext(context.Background(), "tcp", net.JoinHostPort("backend", port))
+	require.NoError(t, err)
+
+	other, err := resolver.DialContext(context.Background(), "tcp", net.JoinHostPort("backend", port))
+	require.NoError(t, err)
+	require.NoError(t, other.Close())
+
+	// The answer is cached.
+	assert.Equal(t, 1, lookup.lookups)
+
+	_, err = resolver.DialContext(context.Background(), "tcp", net.JoinHostPort("unknown", port))
+	assert.Error(t, err)
+
+	// The answer of a host with open connections changes once its TTL, clamped to the maximum, expired.
+	resolver.mu.Lock()
+	assert.Equal(t, time.Minute, time.Until(resolver.hosts["backend"].expires).Round(time.Minute))
+	resolver.hosts["backend"].expires = time.Now().Add(-time.Second)
+	resolver.mu.Unlock()
+
+	lookup.ips["backend"] = []string{"127.0.0.2"}
+	resolver.refresh(context.Background())
+
+	assert.Equal(t, 1, idleClosed)
+	assert.Equal(t, float64(1), gauge.value("backend"))
+
+	// The stale connections are closed again at the next refresh, in case they became idle meanwhile.
+	resolver.refresh(context.Background())
+	assert.Equal(t, 2, idleClosed)
+
+	require.NoError(t, conn.Close())
+	assert.Equal(t, float64(0), gauge.value("backend"))
+
+	// A failing resolution keeps the previous answer.
+	resolver.mu.Lock()
+	resolver.hosts["backend"].expires = time.Now().Add(-time.Second)
+	resolver.mu.Unlock()
+
+	delete(lookup.ips, "backend")
+	ips, err := resolver.resolve(context.Background(), "backend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.2"}, ips)
+
+	// The expired hosts without open connections are forgotten.
+	resolver.mu.Lock()
+	resolver.hosts["backend"].expires = time.Now().Add(-time.Second)
+	resolver.mu.Unlock()
+
+	resolver.refresh(context.Background())
+	assert.Empty(t, resolver.hosts)
+}
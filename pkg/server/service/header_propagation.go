@@ -0,0 +1,97 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares/forwardedheaders"
+)
+
+// headerPropagation applies a HeaderPropagation policy to the requests before forwarding them.
+type headerPropagation struct {
+	next      http.Handler
+	forwarded map[string]struct{}
+	stripped  []string
+	added     map[string]*template.Template
+}
+
+func newHeaderPropagation(next http.Handler, config *dynamic.HeaderPropagation) (http.Handler, error) {
+	if config == nil {
+		return next, nil
+	}
+
+	hp := &headerPropagation{
+		next:  next,
+		added: make(map[string]*template.Template),
+	}
+
+	if len(config.ForwardedHeaders) > 0 {
+		hp.forwarded = make(map[string]struct{})
+		for _, name := range config.ForwardedHeaders {
+			hp.forwarded[http.CanonicalHeaderKey(name)] = struct{}{}
+		}
+	}
+
+	for _, name := range config.StrippedHeaders {
+		hp.stripped = append(hp.stripped, http.CanonicalHeaderKey(name))
+	}
+
+	for name, value := range config.AddedHeaders {
+		tmpl, err := template.New(name).Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing template for header %q: %w", name, err)
+		}
+		hp.added[http.CanonicalHeaderKey(name)] = tmpl
+	}
+
+	return hp, nil
+}
+
+func (h *headerPropagation) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	data := struct{ Request *http.Request }{Request: req}
+
+	// The templates are evaluated against the inbound request, before any header is removed.
+	added := make(map[string]string, len(h.added))
+	for name, tmpl := range h.added {
+		var value bytes.Buffer
+		if err := tmpl.Execute(&value, data); err != nil {
+			log.FromContext(req.Context()).Errorf("Error while evaluating template for header %q: %v", name, err)
+			continue
+		}
+		added[name] = value.String()
+	}
+
+	// Works on a copy of the headers, so that the inbound request as seen by the access logs is left untouched.
+	outReq := new(http.Request)
+	*outReq = *req
+	outReq.Header = req.Header.Clone()
+
+	// The allowlist only applies to the headers sent by the client:
+	// the X-Forwarded headers are set by the entry point, which already removed the values sent by untrusted clients.
+	if h.forwarded != nil {
+		for name := range req.Header {
+			if _, ok := h.forwarded[name]; !ok && !isConnectionHeader(name) && !forwardedheaders.IsXHeader(name) {
+				outReq.Header.Del(name)
+			}
+		}
+	}
+
+	for _, name := range h.stripped {
+		outReq.Header.Del(name)
+	}
+
+	for name, value := range added {
+		outReq.Header.Set(name, value)
+	}
+
+	h.next.ServeHTTP(rw, outReq)
+}
+
+// isConnectionHeader tells whether the header is needed by the proxy itself to handle the connection (e.g. websockets).
+func isConnectionHeader(name string) bool {
+	return name == "Connection" || name == "Upgrade"
+}
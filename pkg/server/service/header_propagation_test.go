@@ -0,0 +1,122 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderPropagation(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          *dynamic.HeaderPropagation
+		reqHeaders      map[string]string
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:            "no policy",
+			reqHeaders:      map[string]string{"X-Internal": "secret", "Accept": "*/*"},
+			expectedHeaders: map[string]string{"X-Internal": "secret", "Accept": "*/*"},
+		},
+		{
+			desc: "only forwarded headers",
+			config: &dynamic.HeaderPropagation{
+				ForwardedHeaders: []string{"accept", "Connection"},
+			},
+			reqHeaders:      map[string]string{"X-Internal": "secret", "Accept": "*/*", "Upgrade": "websocket"},
+			expectedHeaders: map[string]string{"X-Internal": "", "Accept": "*/*", "Upgrade": "websocket"},
+		},
+		{
+			desc: "X-Forwarded headers set by the entry point",
+			config: &dynamic.HeaderPropagation{
+				ForwardedHeaders: []string{"Accept"},
+				StrippedHeaders:  []string{"X-Forwarded-Server"},
+			},
+			reqHeaders: map[string]string{
+				"X-Internal":         "secret",
+				"Accept":             "*/*",
+				"X-Forwarded-Proto":  "https",
+				"X-Forwarded-Host":   "foo.bar",
+				"X-Real-Ip":          "10.0.0.1",
+				"X-Forwarded-Server": "traefik-1",
+			},
+			expectedHeaders: map[string]string{
+				"X-Internal":         "",
+				"Accept":             "*/*",
+				"X-Forwarded-Proto":  "https",
+				"X-Forwarded-Host":   "foo.bar",
+				"X-Real-Ip":          "10.0.0.1",
+				"X-Forwarded-Server": "",
+			},
+		},
+		{
+			desc: "stripped headers",
+			config: &dynamic.HeaderPropagation{
+				StrippedHeaders: []string{"x-internal"},
+			},
+			reqHeaders:      map[string]string{"X-Internal": "secret", "Accept": "*/*"},
+			expectedHeaders: map[string]string{"X-Internal": "", "Accept": "*/*"},
+		},
+		{
+			desc: "added headers with template",
+			config: &dynamic.HeaderPropagation{
+				ForwardedHeaders: []string{"Accept"},
+				AddedHeaders: map[string]string{
+					"X-Original-Host":     "{{ .Request.Host }}",
+					"X-Original-Internal": `{{ .Request.Header.Get "X-Internal" }}`,
+					"X-Static":            "foo",
+				},
+			},
+			reqHeaders: map[string]string{"X-Internal": "secret", "Accept": "*/*"},
+			expectedHeaders: map[string]string{
+				"X-Internal":          "",
+				"Accept":              "*/*",
+				"X-Original-Host":     "foo.bar",
+				"X-Original-Internal": "secret",
+				"X-Static":            "foo",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwarded http.Header
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req.Header
+			})
+
+			handler, err := newHeaderPropagation(next, test.config)
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/", nil)
+			for name, value := range test.reqHeaders {
+				req.Header.Set(name, value)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, forwarded.Get(name), name)
+			}
+
+			for name, value := range test.reqHeaders {
+				assert.Equal(t, value, req.Header.Get(name), "inbound request must not be altered")
+			}
+		})
+	}
+}
+
+func TestHeaderPropagation_invalidTemplate(t *testing.T) {
+	_, err := newHeaderPropagation(http.NotFoundHandler(), &dynamic.HeaderPropagation{
+		AddedHeaders: map[string]string{"X-Foo": "{{ .Request.Host "},
+	})
+	require.Error(t, err)
+}
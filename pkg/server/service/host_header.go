@@ -0,0 +1,88 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+// Modes of the HostHeader policy.
+const (
+	hostHeaderPreserve = "preserve"
+	hostHeaderServer   = "server"
+	hostHeaderTemplate = "template"
+)
+
+// passHost tells whether the inbound Host header is passed to the servers.
+// The HostHeader option, when set, takes precedence over the PassHostHeader one.
+func passHost(service *dynamic.ServersLoadBalancer) bool {
+	if service.HostHeader != nil && service.HostHeader.Mode != "" {
+		return service.HostHeader.Mode != hostHeaderServer
+	}
+
+	return service.PassHostHeader == nil || *service.PassHostHeader
+}
+
+// hostHeader sets the Host header of the forwarded requests from a template.
+type hostHeader struct {
+	next http.Handler
+	tmpl *template.Template
+}
+
+// newHostHeader returns the handler applying the template mode of the HostHeader policy,
+// the other modes being handled by the proxy itself.
+func newHostHeader(next http.Handler, config *dynamic.HostHeader) (http.Handler, error) {
+	if config == nil {
+		return next, nil
+	}
+
+	switch config.Mode {
+	case "", hostHeaderPreserve, hostHeaderServer:
+		if config.Value != "" {
+			return nil, fmt.Errorf("a host header value is only allowed with the %s mode", hostHeaderTemplate)
+		}
+		return next, nil
+	case hostHeaderTemplate:
+		if config.Value == "" {
+			return nil, fmt.Errorf("a host header value is required with the %s mode", hostHeaderTemplate)
+		}
+	default:
+		return nil, fmt.Errorf("unknown host header mode: %q", config.Mode)
+	}
+
+	tmpl, err := template.New("hostHeader").Parse(config.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing host header template: %w", err)
+	}
+
+	return &hostHeader{next: next, tmpl: tmpl}, nil
+}
+
+func (h *hostHeader) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// At this point, the URL of the request is the one of the server selected by the load-balancer.
+	data := struct {
+		Request *http.Request
+		Server  string
+	}{
+		Request: req,
+		Server:  req.URL.Host,
+	}
+
+	var value bytes.Buffer
+	if err := h.tmpl.Execute(&value, data); err != nil {
+		log.FromContext(req.Context()).Errorf("Error while evaluating the host header template: %v", err)
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// Works on a copy of the request, so that the inbound Host as seen by the access logs is left untouched.
+	outReq := new(http.Request)
+	*outReq = *req
+	outReq.Host = value.String()
+
+	h.next.ServeHTTP(rw, outReq)
+}
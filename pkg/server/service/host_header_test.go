@@ -0,0 +1,145 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostHeader(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		config       *dynamic.HostHeader
+		expectedHost string
+		expectedErr  bool
+	}{
+		{
+			desc:         "no policy",
+			expectedHost: "example.com",
+		},
+		{
+			desc:         "preserve",
+			config:       &dynamic.HostHeader{Mode: "preserve"},
+			expectedHost: "example.com",
+		},
+		{
+			desc:         "server",
+			config:       &dynamic.HostHeader{Mode: "server"},
+			expectedHost: "example.com",
+		},
+		{
+			desc:         "template",
+			config:       &dynamic.HostHeader{Mode: "template", Value: "internal.{{ .Request.Host }}"},
+			expectedHost: "internal.example.com",
+		},
+		{
+			desc:         "template with the server",
+			config:       &dynamic.HostHeader{Mode: "template", Value: "{{ .Server }}.nip.io"},
+			expectedHost: "10.0.0.1:8080.nip.io",
+		},
+		{
+			desc:        "template without value",
+			config:      &dynamic.HostHeader{Mode: "template"},
+			expectedErr: true,
+		},
+		{
+			desc:        "value without template",
+			config:      &dynamic.HostHeader{Mode: "preserve", Value: "example.org"},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid template",
+			config:      &dynamic.HostHeader{Mode: "template", Value: "{{ .Request.Host "},
+			expectedErr: true,
+		},
+		{
+			desc:        "unknown mode",
+			config:      &dynamic.HostHeader{Mode: "rewrite"},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var host string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				host = req.Host
+			})
+
+			handler, err := newHostHeader(next, test.config)
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// The URL of the request is the one of the server, as rewritten by the load-balancer.
+			req := httptest.NewRequest(http.MethodGet, "http://10.0.0.1:8080/foo", nil)
+			req.Host = "example.com"
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expectedHost, host)
+			assert.Equal(t, "example.com", req.Host)
+		})
+	}
+}
+
+func TestPassHost(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		service  *dynamic.ServersLoadBalancer
+		expected bool
+	}{
+		{
+			desc:     "default",
+			service:  &dynamic.ServersLoadBalancer{},
+			expected: true,
+		},
+		{
+			desc:     "passHostHeader disabled",
+			service:  &dynamic.ServersLoadBalancer{PassHostHeader: Bool(false)},
+			expected: false,
+		},
+		{
+			desc: "preserve mode overrides passHostHeader",
+			service: &dynamic.ServersLoadBalancer{
+				PassHostHeader: Bool(false),
+				HostHeader:     &dynamic.HostHeader{Mode: "preserve"},
+			},
+			expected: true,
+		},
+		{
+			desc: "server mode overrides passHostHeader",
+			service: &dynamic.ServersLoadBalancer{
+				PassHostHeader: Bool(true),
+				HostHeader:     &dynamic.HostHeader{Mode: "server"},
+			},
+			expected: false,
+		},
+		{
+			desc: "template mode",
+			service: &dynamic.ServersLoadBalancer{
+				PassHostHeader: Bool(false),
+				HostHeader:     &dynamic.HostHeader{Mode: "template", Value: "example.org"},
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, passHost(test.service))
+		})
+	}
+}
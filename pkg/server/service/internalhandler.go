@@ -22,22 +22,29 @@ type InternalHandlers struct {
 	rest       http.Handler
 	prometheus http.Handler
 	ping       http.Handler
+	health     http.Handler
 	serviceManager
 }
 
 // NewInternalHandlers creates a new InternalHandlers.
-func NewInternalHandlers(api func(configuration *runtime.Configuration) http.Handler, configuration *runtime.Configuration, rest http.Handler, metricsHandler http.Handler, pingHandler http.Handler, dashboard http.Handler, next serviceManager) *InternalHandlers {
+func NewInternalHandlers(api func(configuration *runtime.Configuration) http.Handler, configuration *runtime.Configuration, rest http.Handler, metricsHandler http.Handler, pingHandler http.Handler, health func(configuration *runtime.Configuration) http.Handler, dashboard http.Handler, next serviceManager) *InternalHandlers {
 	var apiHandler http.Handler
 	if api != nil {
 		apiHandler = api(configuration)
 	}
 
+	var healthHandler http.Handler
+	if health != nil {
+		healthHandler = health(configuration)
+	}
+
 	return &InternalHandlers{
 		api:            apiHandler,
 		dashboard:      dashboard,
 		rest:           rest,
 		prometheus:     metricsHandler,
 		ping:           pingHandler,
+		health:         healthHandler,
 		serviceManager: next,
 	}
 }
@@ -82,6 +89,12 @@ func (m *InternalHandlers) get(serviceName string) (http.Handler, error) {
 		}
 		return m.ping, nil
 
+	case "health@internal":
+		if m.health == nil {
+			return nil, errors.New("health is not enabled")
+		}
+		return m.health, nil
+
 	case "prometheus@internal":
 		if m.prometheus == nil {
 			return nil, errors.New("prometheus is not enabled")
@@ -0,0 +1,202 @@
+package hashring
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// replicas is the number of points of each server on the ring,
+// which spreads the keys evenly between the servers.
+const replicas = 160
+
+type point struct {
+	hash   uint64
+	server *url.URL
+}
+
+// Balancer forwards the requests to the server owning the hash of their key on a ring,
+// so that adding or removing a server only moves the keys of the ring segments it owns.
+type Balancer struct {
+	next   http.Handler
+	header string
+	cookie string
+	query  string
+
+	mu      sync.RWMutex
+	servers []*url.URL
+	ring    []point
+}
+
+// New creates a consistent hashing load balancer forwarding the requests to next.
+func New(next http.Handler, config dynamic.ConsistentHash) (*Balancer, error) {
+	var keys int
+	for _, key := range []string{config.Header, config.Cookie, config.Query} {
+		if key != "" {
+			keys++
+		}
+	}
+
+	if keys > 1 {
+		return nil, errors.New("only one of header, cookie, and query can be used as the key")
+	}
+
+	return &Balancer{
+		next:   next,
+		header: config.Header,
+		cookie: config.Cookie,
+		query:  config.Query,
+	}, nil
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	server := b.lookup(b.key(req))
+	if server == nil {
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	// A shallow copy of the request is made to avoid side effects.
+	newReq := *req
+	newReq.URL = copyURL(server)
+
+	b.next.ServeHTTP(rw, &newReq)
+}
+
+// key returns the configured attribute of the request, or its client IP.
+func (b *Balancer) key(req *http.Request) string {
+	switch {
+	case b.header != "":
+		if value := req.Header.Get(b.header); value != "" {
+			return value
+		}
+	case b.cookie != "":
+		if cookie, err := req.Cookie(b.cookie); err == nil && cookie.Value != "" {
+			return cookie.Value
+		}
+	case b.query != "":
+		if value := req.URL.Query().Get(b.query); value != "" {
+			return value
+		}
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// lookup returns the server owning the first point of the ring following the hash of the key.
+func (b *Balancer) lookup(key string) *url.URL {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.ring) == 0 {
+		return nil
+	}
+
+	h := hash(key)
+	i := sort.Search(len(b.ring), func(i int) bool { return b.ring[i].hash >= h })
+	if i == len(b.ring) {
+		i = 0
+	}
+
+	return b.ring[i].server
+}
+
+// Servers returns the servers of the ring.
+func (b *Balancer) Servers() []*url.URL {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	servers := make([]*url.URL, len(b.servers))
+	copy(servers, b.servers)
+	return servers
+}
+
+// UpsertServer adds the server to the ring, if it is not already in it.
+// The options, such as the weight, are ignored, as all the servers own the same share of the ring.
+func (b *Balancer) UpsertServer(u *url.URL, _ ...roundrobin.ServerOption) error {
+	if u == nil {
+		return errors.New("server URL can't be nil")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.indexOf(u) >= 0 {
+		return nil
+	}
+
+	b.servers = append(b.servers, copyURL(u))
+	b.buildRing()
+
+	return nil
+}
+
+// RemoveServer removes the server from the ring.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i := b.indexOf(u)
+	if i < 0 {
+		return fmt.Errorf("server not found")
+	}
+
+	b.servers = append(b.servers[:i], b.servers[i+1:]...)
+	b.buildRing()
+
+	return nil
+}
+
+func (b *Balancer) indexOf(u *url.URL) int {
+	for i, server := range b.servers {
+		if server.String() == u.String() {
+			return i
+		}
+	}
+	return -1
+}
+
+// buildRing places the points of the servers on the ring.
+// The points of a server only depend on its URL, so the other servers keep theirs.
+func (b *Balancer) buildRing() {
+	ring := make([]point, 0, len(b.servers)*replicas)
+	for _, server := range b.servers {
+		for i := 0; i < replicas; i++ {
+			ring = append(ring, point{hash: hash(server.String() + "#" + strconv.Itoa(i)), server: server})
+		}
+	}
+
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+
+	b.ring = ring
+}
+
+// hash returns the position of the key on the ring.
+// A cryptographic hash is used, as close keys (e.g. user1 and user2) must land on distant positions.
+func hash(key string) uint64 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+func copyURL(u *url.URL) *url.URL {
+	out := *u
+	if u.User != nil {
+		user := *u.User
+		out.User = &user
+	}
+	return &out
+}
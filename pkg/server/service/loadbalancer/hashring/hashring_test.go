@@ -0,0 +1,145 @@
+package hashring
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(http.NotFoundHandler(), dynamic.ConsistentHash{Header: "X-User", Query: "user"})
+	assert.Error(t, err)
+
+	_, err = New(http.NotFoundHandler(), dynamic.ConsistentHash{Cookie: "session"})
+	assert.NoError(t, err)
+}
+
+func TestBalancer_key(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   dynamic.ConsistentHash
+		request  func(req *http.Request)
+		expected string
+	}{
+		{
+			desc:     "client IP",
+			expected: "10.0.0.1",
+		},
+		{
+			desc:   "header",
+			config: dynamic.ConsistentHash{Header: "X-User"},
+			request: func(req *http.Request) {
+				req.Header.Set("X-User", "bob")
+			},
+			expected: "bob",
+		},
+		{
+			desc:     "missing header",
+			config:   dynamic.ConsistentHash{Header: "X-User"},
+			expected: "10.0.0.1",
+		},
+		{
+			desc:   "cookie",
+			config: dynamic.ConsistentHash{Cookie: "session"},
+			request: func(req *http.Request) {
+				req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+			},
+			expected: "abc",
+		},
+		{
+			desc:   "query parameter",
+			config: dynamic.ConsistentHash{Query: "tenant"},
+			request: func(req *http.Request) {
+				req.URL.RawQuery = "tenant=acme"
+			},
+			expected: "acme",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer, err := New(http.NotFoundHandler(), test.config)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			if test.request != nil {
+				test.request(req)
+			}
+
+			assert.Equal(t, test.expected, balancer.key(req))
+		})
+	}
+}
+
+func TestBalancer(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", req.URL.Host)
+	})
+
+	balancer, err := New(next, dynamic.ConsistentHash{Header: "X-User"})
+	require.NoError(t, err)
+
+	// No server.
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, balancer.UpsertServer(&url.URL{Scheme: "http", Host: fmt.Sprintf("10.0.0.%d", i)}))
+	}
+	// A server is only added once.
+	require.NoError(t, balancer.UpsertServer(&url.URL{Scheme: "http", Host: "10.0.0.0"}))
+	assert.Len(t, balancer.Servers(), 4)
+
+	serve := func() map[string]string {
+		servers := make(map[string]string)
+		for i := 0; i < 1000; i++ {
+			user := fmt.Sprintf("user%d", i)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			req.Header.Set("X-User", user)
+
+			recorder := httptest.NewRecorder()
+			balancer.ServeHTTP(recorder, req)
+			servers[user] = recorder.Header().Get("server")
+		}
+		return servers
+	}
+
+	before := serve()
+
+	// The mapping is stable, and the keys are spread between all the servers.
+	assert.Equal(t, before, serve())
+
+	counts := make(map[string]int)
+	for _, server := range before {
+		counts[server]++
+	}
+	assert.Len(t, counts, 4)
+	for server, count := range counts {
+		assert.InDelta(t, 250, count, 100, server)
+	}
+
+	// Only the keys of the removed server move.
+	require.NoError(t, balancer.RemoveServer(&url.URL{Scheme: "http", Host: "10.0.0.3"}))
+	assert.Error(t, balancer.RemoveServer(&url.URL{Scheme: "http", Host: "10.0.0.3"}))
+
+	after := serve()
+	for user, server := range before {
+		if server == "10.0.0.3" {
+			assert.NotEqual(t, "10.0.0.3", after[user], user)
+		} else {
+			assert.Equal(t, server, after[user], user)
+		}
+	}
+}
@@ -25,6 +25,8 @@ type Mirroring struct {
 	routinePool    *safe.Pool
 
 	maxBodySize int64
+	// streamMaxLag is how many bytes of the body the mirrors can lag behind the main request, when the bodies are streamed.
+	streamMaxLag int64
 
 	lock  sync.RWMutex
 	total uint64
@@ -40,6 +42,13 @@ func New(handler http.Handler, pool *safe.Pool, maxBodySize int64) *Mirroring {
 	}
 }
 
+// SetStreaming streams the request bodies to the mirrors while they are read by the main handler, instead of buffering them:
+// the requests are mirrored whatever the size of their body,
+// and a mirror lagging by more than maxLag bytes behind the main request gets its request aborted.
+func (m *Mirroring) SetStreaming(maxLag int64) {
+	m.streamMaxLag = maxLag
+}
+
 func (m *Mirroring) inc() uint64 {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -79,6 +88,11 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if m.streamMaxLag > 0 && req.Body != nil && req.Body != http.NoBody {
+		m.serveStreaming(rw, req, mirrors)
+		return
+	}
+
 	logger := log.FromContext(req.Context())
 	rr, bytesRead, err := newReusableRequest(req, m.maxBodySize)
 	if err != nil && err != errBodyTooLarge {
@@ -126,6 +140,34 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	})
 }
 
+// serveStreaming forwards the request to the main handler and to the mirrors at the same time,
+// the mirrors reading the body as the main handler reads it.
+func (m *Mirroring) serveStreaming(rw http.ResponseWriter, req *http.Request, mirrors []http.Handler) {
+	tee := &teeBody{ReadCloser: req.Body}
+
+	for _, handler := range mirrors {
+		body := newStreamBody(m.streamMaxLag)
+		tee.mirrors = append(tee.mirrors, body)
+
+		// See ServeHTTP for the reasons of the new context.
+		ctx := context.WithValue(req.Context(), accesslog.DataTableKey, nil)
+		r := req.Clone(contextStopPropagation{ctx})
+		r.Body = body
+
+		handler := handler
+		m.routinePool.GoCtx(func(_ context.Context) {
+			handler.ServeHTTP(m.rw, r)
+		})
+	}
+
+	r := req.Clone(req.Context())
+	r.Body = tee
+
+	m.handler.ServeHTTP(rw, r)
+
+	tee.abort()
+}
+
 // AddMirror adds an httpHandler to mirror to.
 func (m *Mirroring) AddMirror(handler http.Handler, percent int) error {
 	if percent < 0 || percent > 100 {
@@ -177,6 +177,99 @@ func TestMirroringWithBody(t *testing.T) {
 	assert.Equal(t, numMirrors, int(val))
 }
 
+func TestMirroringStreaming(t *testing.T) {
+	body := bytes.Repeat([]byte("body"), 16*1024)
+
+	pool := safe.NewPool(context.Background())
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		bb, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, bb)
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	// The body is larger than maxBodySize, which only applies to the buffered bodies.
+	mirror := New(handler, pool, 8)
+	mirror.SetStreaming(1024 * 1024)
+
+	var countMirror int32
+	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		bb, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, bb)
+		atomic.AddInt32(&countMirror, 1)
+	}), 100)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(body))
+
+	rw := httptest.NewRecorder()
+	mirror.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	pool.Stop()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&countMirror))
+}
+
+func TestMirroringStreaming_aborted(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		readMain      bool
+		expectedError error
+	}{
+		{
+			desc:          "mirror lagging behind",
+			readMain:      true,
+			expectedError: errMirrorLagging,
+		},
+		{
+			desc:          "body not read by the main handler",
+			expectedError: errMainBodyUnread,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			body := bytes.Repeat([]byte("body"), 16)
+
+			pool := safe.NewPool(context.Background())
+
+			release := make(chan struct{})
+			handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				defer close(release)
+
+				if test.readMain {
+					bb, err := ioutil.ReadAll(r.Body)
+					assert.NoError(t, err)
+					assert.Equal(t, body, bb)
+				}
+			})
+
+			mirror := New(handler, pool, defaultMaxBodySize)
+			mirror.SetStreaming(16)
+
+			mirrorErr := make(chan error, 1)
+			err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				<-release
+				_, err := ioutil.ReadAll(r.Body)
+				mirrorErr <- err
+			}), 100)
+			assert.NoError(t, err)
+
+			mirror.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(body)))
+
+			pool.Stop()
+
+			assert.Equal(t, test.expectedError, <-mirrorErr)
+		})
+	}
+}
+
 func TestCloneRequest(t *testing.T) {
 	t.Run("http request body is nil", func(t *testing.T) {
 		req, err := http.NewRequest(http.MethodPost, "/", nil)
@@ -0,0 +1,133 @@
+package mirror
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
+
+var (
+	errMirrorLagging    = errors.New("mirror lagging behind the main request")
+	errMainBodyUnread   = errors.New("main request ended before its body was fully read")
+	errStreamBodyClosed = errors.New("mirrored body closed")
+)
+
+// teeBody is the body of the main request, copying the bytes read from the client to the bodies of the mirrored requests.
+type teeBody struct {
+	io.ReadCloser
+	mirrors []*streamBody
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	for _, mirror := range b.mirrors {
+		if n > 0 {
+			mirror.feed(p[:n])
+		}
+		if err != nil {
+			mirror.end(err)
+		}
+	}
+
+	return n, err
+}
+
+// abort ends the bodies of the mirrored requests which did not get the whole body,
+// so that they do not send a truncated one.
+func (b *teeBody) abort() {
+	for _, mirror := range b.mirrors {
+		mirror.end(errMainBodyUnread)
+	}
+}
+
+// streamBody is the body of a mirrored request, reading the bytes sent by the client from a bounded buffer:
+// a mirror lagging by more than the size of the buffer gets an error instead.
+type streamBody struct {
+	maxLag int64
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buffer bytes.Buffer
+	// err is returned once the buffer is drained: io.EOF when the whole body was fed, the failure reason otherwise.
+	err error
+	// failed is set when the mirror is cut off, which drops the buffered bytes.
+	failed bool
+}
+
+func newStreamBody(maxLag int64) *streamBody {
+	b := &streamBody{maxLag: maxLag}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+func (b *streamBody) feed(p []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err != nil {
+		return
+	}
+
+	if int64(b.buffer.Len()+len(p)) > b.maxLag {
+		b.fail(errMirrorLagging)
+		return
+	}
+
+	b.buffer.Write(p)
+	b.cond.Broadcast()
+}
+
+// end stops the feeding of the body, with io.EOF when the whole body was fed.
+// Any other error cuts off the mirror.
+func (b *streamBody) end(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err != nil {
+		return
+	}
+
+	if err != io.EOF {
+		b.fail(err)
+		return
+	}
+
+	b.err = io.EOF
+	b.cond.Broadcast()
+}
+
+// fail cuts off the mirror, b.mu being held.
+func (b *streamBody) fail(err error) {
+	b.err = err
+	b.failed = true
+	b.buffer.Reset()
+	b.cond.Broadcast()
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for b.buffer.Len() == 0 && b.err == nil {
+		b.cond.Wait()
+	}
+
+	if b.failed || b.buffer.Len() == 0 {
+		return 0, b.err
+	}
+
+	return b.buffer.Read(p)
+}
+
+func (b *streamBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err == nil || b.err == io.EOF {
+		b.fail(errStreamBodyClosed)
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package p2c
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+const defaultDecayTime = 10 * time.Second
+
+type server struct {
+	url      *url.URL
+	inflight int
+	// latency is the exponentially weighted moving average of the response times, in seconds.
+	latency float64
+	updated time.Time
+}
+
+// Balancer forwards each request to the least loaded of two servers picked at random,
+// the load being the number of in-flight requests of the servers,
+// optionally weighted with the moving average of their response times.
+type Balancer struct {
+	next      http.Handler
+	ewma      bool
+	decayTime time.Duration
+
+	mu      sync.Mutex
+	servers []*server
+	rand    *rand.Rand
+	now     func() time.Time
+}
+
+// New creates a power of two choices load balancer forwarding the requests to next.
+func New(next http.Handler, config dynamic.P2C) (*Balancer, error) {
+	decayTime := defaultDecayTime
+	if config.DecayTime != "" {
+		var err error
+		decayTime, err = time.ParseDuration(config.DecayTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid decay time: %w", err)
+		}
+
+		if decayTime <= 0 {
+			return nil, errors.New("decay time must be positive")
+		}
+	}
+
+	return &Balancer{
+		next:      next,
+		ewma:      config.EWMA,
+		decayTime: decayTime,
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:       time.Now,
+	}, nil
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	srv, start := b.acquire()
+	if srv == nil {
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	defer b.release(srv, start)
+
+	// A shallow copy of the request is made to avoid side effects.
+	newReq := *req
+	newReq.URL = copyURL(srv.url)
+
+	b.next.ServeHTTP(rw, &newReq)
+}
+
+// acquire picks two distinct servers at random, and counts a new in-flight request on the least loaded one.
+func (b *Balancer) acquire() (*server, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch len(b.servers) {
+	case 0:
+		return nil, time.Time{}
+	case 1:
+		b.servers[0].inflight++
+		return b.servers[0], b.now()
+	}
+
+	i := b.rand.Intn(len(b.servers))
+	j := b.rand.Intn(len(b.servers) - 1)
+	if j >= i {
+		j++
+	}
+
+	srv := b.servers[i]
+	if b.load(b.servers[j]) < b.load(srv) {
+		srv = b.servers[j]
+	}
+
+	srv.inflight++
+
+	return srv, b.now()
+}
+
+// release ends the in-flight request of the server,
+// and accounts for its response time in the moving average.
+func (b *Balancer) release(srv *server, start time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	srv.inflight--
+
+	if !b.ewma {
+		return
+	}
+
+	now := b.now()
+	latency := now.Sub(start).Seconds()
+
+	if srv.updated.IsZero() {
+		srv.latency = latency
+	} else {
+		w := math.Exp(-float64(now.Sub(srv.updated)) / float64(b.decayTime))
+		srv.latency = srv.latency*w + latency*(1-w)
+	}
+	srv.updated = now
+}
+
+// load returns the cost of one more request on the server, b.mu being held.
+// With EWMA, a server without any response yet costs nothing, so it is probed first.
+func (b *Balancer) load(srv *server) float64 {
+	if !b.ewma {
+		return float64(srv.inflight)
+	}
+
+	return srv.latency * float64(srv.inflight+1)
+}
+
+// Servers returns the servers of the balancer.
+func (b *Balancer) Servers() []*url.URL {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	servers := make([]*url.URL, 0, len(b.servers))
+	for _, srv := range b.servers {
+		servers = append(servers, copyURL(srv.url))
+	}
+	return servers
+}
+
+// UpsertServer adds the server to the balancer, if it is not already in it.
+// The options, such as the weight, are ignored.
+func (b *Balancer) UpsertServer(u *url.URL, _ ...roundrobin.ServerOption) error {
+	if u == nil {
+		return errors.New("server URL can't be nil")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.indexOf(u) >= 0 {
+		return nil
+	}
+
+	b.servers = append(b.servers, &server{url: copyURL(u)})
+
+	return nil
+}
+
+// RemoveServer removes the server from the balancer.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i := b.indexOf(u)
+	if i < 0 {
+		return fmt.Errorf("server not found")
+	}
+
+	b.servers = append(b.servers[:i], b.servers[i+1:]...)
+
+	return nil
+}
+
+func (b *Balancer) indexOf(u *url.URL) int {
+	for i, srv := range b.servers {
+		if srv.url.String() == u.String() {
+			return i
+		}
+	}
+	return -1
+}
+
+func copyURL(u *url.URL) *url.URL {
+	out := *u
+	if u.User != nil {
+		user := *u.User
+		out.User = &user
+	}
+	return &out
+}
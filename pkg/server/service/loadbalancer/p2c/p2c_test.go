@@ -0,0 +1,113 @@
+package p2c
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(http.NotFoundHandler(), dynamic.P2C{DecayTime: "foo"})
+	assert.Error(t, err)
+
+	_, err = New(http.NotFoundHandler(), dynamic.P2C{DecayTime: "-1s"})
+	assert.Error(t, err)
+
+	balancer, err := New(http.NotFoundHandler(), dynamic.P2C{EWMA: true})
+	require.NoError(t, err)
+	assert.Equal(t, defaultDecayTime, balancer.decayTime)
+}
+
+func TestBalancer(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", req.URL.Host)
+	})
+
+	balancer, err := New(next, dynamic.P2C{})
+	require.NoError(t, err)
+
+	// No server.
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, balancer.UpsertServer(&url.URL{Scheme: "http", Host: fmt.Sprintf("10.0.0.%d", i)}))
+	}
+	// A server is only added once.
+	require.NoError(t, balancer.UpsertServer(&url.URL{Scheme: "http", Host: "10.0.0.0"}))
+	assert.Len(t, balancer.Servers(), 3)
+
+	require.NoError(t, balancer.RemoveServer(&url.URL{Scheme: "http", Host: "10.0.0.2"}))
+	assert.Error(t, balancer.RemoveServer(&url.URL{Scheme: "http", Host: "10.0.0.2"}))
+
+	counts := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		counts[recorder.Header().Get("server")]++
+	}
+	assert.Len(t, counts, 2)
+
+	for _, srv := range balancer.servers {
+		assert.Equal(t, 0, srv.inflight)
+	}
+}
+
+func TestBalancer_acquire(t *testing.T) {
+	balancer, err := New(http.NotFoundHandler(), dynamic.P2C{})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, balancer.UpsertServer(&url.URL{Scheme: "http", Host: fmt.Sprintf("10.0.0.%d", i)}))
+	}
+	balancer.servers[0].inflight = 3
+
+	// With two servers, both are always picked, so the one with fewer in-flight requests wins.
+	for i := 0; i < 3; i++ {
+		srv, _ := balancer.acquire()
+		assert.Equal(t, "10.0.0.1", srv.url.Host)
+	}
+
+	assert.Equal(t, 3, balancer.servers[1].inflight)
+}
+
+func TestBalancer_ewma(t *testing.T) {
+	balancer, err := New(http.NotFoundHandler(), dynamic.P2C{EWMA: true, DecayTime: "10s"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	balancer.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, balancer.UpsertServer(&url.URL{Scheme: "http", Host: fmt.Sprintf("10.0.0.%d", i)}))
+	}
+
+	fast, slow := balancer.servers[0], balancer.servers[1]
+
+	fast.inflight++
+	balancer.release(fast, now.Add(-100*time.Millisecond))
+	slow.inflight++
+	balancer.release(slow, now.Add(-time.Second))
+
+	assert.InDelta(t, 0.1, fast.latency, 0.001)
+	assert.InDelta(t, 1, slow.latency, 0.001)
+
+	// The slow server loses, even with fewer in-flight requests.
+	fast.inflight = 2
+	srv, _ := balancer.acquire()
+	assert.Equal(t, fast, srv)
+
+	// The past response times decay.
+	now = now.Add(10 * time.Second)
+	slow.inflight++
+	balancer.release(slow, now.Add(-100*time.Millisecond))
+	assert.InDelta(t, 1/2.718+0.1*(1-1/2.718), slow.latency, 0.01)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/server/cookie"
 )
 
 type namedHandler struct {
@@ -21,6 +22,7 @@ type stickyCookie struct {
 	name     string
 	secure   bool
 	httpOnly bool
+	codec    *cookie.Codec // nil when the cookie value is the name of the service.
 }
 
 // New creates a new load balancer.
@@ -36,6 +38,13 @@ func New(sticky *dynamic.Sticky) *Balancer {
 	return balancer
 }
 
+// SetCookieCodec protects the value of the sticky cookie with the given codec.
+func (b *Balancer) SetCookieCodec(codec *cookie.Codec) {
+	if b.stickyCookie != nil {
+		b.stickyCookie.codec = codec
+	}
+}
+
 // Len implements heap.Interface/sort.Interface.
 func (b *Balancer) Len() int { return len(b.handlers) }
 
@@ -110,11 +119,9 @@ func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		}
 
 		if err == nil && cookie != nil {
-			for _, handler := range b.handlers {
-				if handler.name == cookie.Value {
-					handler.ServeHTTP(w, req)
-					return
-				}
+			if handler := b.stickyHandler(cookie.Value); handler != nil {
+				handler.ServeHTTP(w, req)
+				return
 			}
 		}
 	}
@@ -126,13 +133,51 @@ func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 
 	if b.stickyCookie != nil {
-		cookie := &http.Cookie{Name: b.stickyCookie.name, Value: server.name, Path: "/", HttpOnly: b.stickyCookie.httpOnly, Secure: b.stickyCookie.secure}
-		http.SetCookie(w, cookie)
+		value := server.name
+		if b.stickyCookie.codec != nil {
+			value, err = b.stickyCookie.codec.Encode(server.name)
+		}
+
+		if err != nil {
+			log.WithoutContext().Warnf("Error while protecting the sticky cookie: %v", err)
+		} else {
+			cookie := &http.Cookie{Name: b.stickyCookie.name, Value: value, Path: "/", HttpOnly: b.stickyCookie.httpOnly, Secure: b.stickyCookie.secure}
+			http.SetCookie(w, cookie)
+		}
 	}
 
 	server.ServeHTTP(w, req)
 }
 
+// stickyHandler returns the handler the sticky cookie value refers to, if any.
+func (b *Balancer) stickyHandler(value string) *namedHandler {
+	if b.stickyCookie.codec == nil {
+		for _, handler := range b.handlers {
+			if handler.name == value {
+				return handler
+			}
+		}
+		return nil
+	}
+
+	names := make([]string, 0, len(b.handlers))
+	for _, handler := range b.handlers {
+		names = append(names, handler.name)
+	}
+
+	name, ok := b.stickyCookie.codec.Decode(value, names)
+	if !ok {
+		return nil
+	}
+
+	for _, handler := range b.handlers {
+		if handler.name == name {
+			return handler
+		}
+	}
+	return nil
+}
+
 // AddService adds a handler.
 // It is not thread safe with ServeHTTP.
 // A handler with a non-positive weight is ignored.
@@ -7,6 +7,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares/shutdown"
 	"github.com/containous/traefik/v2/pkg/safe"
 )
 
@@ -15,26 +16,35 @@ type ManagerFactory struct {
 	metricsRegistry metrics.Registry
 
 	defaultRoundTripper http.RoundTripper
+	transports          *transportPool
+	dnsRefresher        *dnsRefresher
 
 	api              func(configuration *runtime.Configuration) http.Handler
 	restHandler      http.Handler
 	dashboardHandler http.Handler
 	metricsHandler   http.Handler
 	pingHandler      http.Handler
+	healthHandler    func(configuration *runtime.Configuration) http.Handler
 
 	routinesPool *safe.Pool
+
+	shutdownCoordinator *shutdown.Coordinator
 }
 
-// NewManagerFactory creates a new ManagerFactory.
-func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry) *ManagerFactory {
+// NewManagerFactory creates a new ManagerFactory, whose API reports the given states.
+func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry, apiOptions api.BuilderOptions) *ManagerFactory {
 	factory := &ManagerFactory{
 		metricsRegistry:     metricsRegistry,
-		defaultRoundTripper: setupDefaultRoundTripper(staticConfiguration.ServersTransport),
+		defaultRoundTripper: setupDefaultRoundTripper(staticConfiguration.ServersTransport, metricsRegistry, routinesPool),
+		transports:          newTransportPool(staticConfiguration.ServersTransport, metricsRegistry),
+		dnsRefresher:        newDNSRefresher(),
 		routinesPool:        routinesPool,
 	}
 
-	if staticConfiguration.API != nil {
-		factory.api = api.NewBuilder(staticConfiguration)
+	internalListener := staticConfiguration.InternalListener
+
+	if staticConfiguration.API != nil && (internalListener == nil || !internalListener.API) {
+		factory.api = api.NewBuilder(staticConfiguration, apiOptions)
 
 		if staticConfiguration.API.Dashboard {
 			factory.dashboardHandler = http.FileServer(staticConfiguration.API.DashboardAssets)
@@ -45,7 +55,7 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 		factory.restHandler = staticConfiguration.Providers.Rest.CreateRouter()
 	}
 
-	if staticConfiguration.Metrics != nil && staticConfiguration.Metrics.Prometheus != nil {
+	if staticConfiguration.Metrics != nil && staticConfiguration.Metrics.Prometheus != nil && (internalListener == nil || !internalListener.Prometheus) {
 		factory.metricsHandler = metrics.PrometheusHandler()
 	}
 
@@ -56,11 +66,23 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 		factory.pingHandler = staticConfiguration.Ping
 	}
 
+	if staticConfiguration.Health != nil {
+		factory.healthHandler = staticConfiguration.Health.CreateHandler
+	}
+
 	return factory
 }
 
+// SetShutdownCoordinator sets the coordinator telling the services when the shutdown started.
+func (f *ManagerFactory) SetShutdownCoordinator(coordinator *shutdown.Coordinator) {
+	f.shutdownCoordinator = coordinator
+}
+
 // Build creates a service manager.
 func (f *ManagerFactory) Build(configuration *runtime.Configuration) *InternalHandlers {
 	svcManager := NewManager(configuration.Services, f.defaultRoundTripper, f.metricsRegistry, f.routinesPool)
-	return NewInternalHandlers(f.api, configuration, f.restHandler, f.metricsHandler, f.pingHandler, f.dashboardHandler, svcManager)
+	svcManager.transports = f.transports
+	svcManager.dnsRefresher = f.dnsRefresher
+	svcManager.shutdownCoordinator = f.shutdownCoordinator
+	return NewInternalHandlers(f.api, configuration, f.restHandler, f.metricsHandler, f.pingHandler, f.healthHandler, f.dashboardHandler, svcManager)
 }
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/healthcheck"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+const (
+	defaultConsecutiveErrors  = 5
+	defaultBaseEjectionTime   = 30 * time.Second
+	defaultMaxEjectionTime    = 300 * time.Second
+	defaultMaxEjectionPercent = 50
+)
+
+// outlierState holds the passive health of a server.
+type outlierState struct {
+	consecutiveErrors int
+	ejected           bool
+	// ejections is the number of recent ejections of the server, which doubles the duration of the next one.
+	ejections int
+	// returnedAt is when the server last returned to the load-balancer, after an ejection of lastEjection.
+	returnedAt   time.Time
+	lastEjection time.Duration
+}
+
+// outlierDetector ejects from the load-balancer the servers answering the forwarded requests with consecutive errors,
+// and returns them after an ejection time.
+type outlierDetector struct {
+	ctx                context.Context
+	serviceName        string
+	consecutiveErrors  int
+	baseEjectionTime   time.Duration
+	maxEjectionTime    time.Duration
+	maxEjectionPercent int
+
+	lb healthcheck.Balancer
+
+	mu      sync.Mutex
+	servers map[string]*outlierState
+	// afterFunc schedules the return of the ejected servers, and is replaced in the tests.
+	afterFunc func(d time.Duration, f func())
+}
+
+func newOutlierDetector(ctx context.Context, serviceName string, config *dynamic.OutlierDetection) (*outlierDetector, error) {
+	detector := &outlierDetector{
+		ctx:                ctx,
+		serviceName:        serviceName,
+		consecutiveErrors:  defaultConsecutiveErrors,
+		baseEjectionTime:   defaultBaseEjectionTime,
+		maxEjectionTime:    defaultMaxEjectionTime,
+		maxEjectionPercent: defaultMaxEjectionPercent,
+		servers:            make(map[string]*outlierState),
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+
+	if config.ConsecutiveErrors < 0 {
+		return nil, fmt.Errorf("consecutive errors must not be negative: %d", config.ConsecutiveErrors)
+	}
+	if config.ConsecutiveErrors > 0 {
+		detector.consecutiveErrors = config.ConsecutiveErrors
+	}
+
+	if config.MaxEjectionPercent < 0 || config.MaxEjectionPercent > 100 {
+		return nil, fmt.Errorf("max ejection percent must be between 0 and 100: %d", config.MaxEjectionPercent)
+	}
+	if config.MaxEjectionPercent > 0 {
+		detector.maxEjectionPercent = config.MaxEjectionPercent
+	}
+
+	var err error
+	detector.baseEjectionTime, err = parseEjectionTime(config.BaseEjectionTime, detector.baseEjectionTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base ejection time: %w", err)
+	}
+
+	detector.maxEjectionTime, err = parseEjectionTime(config.MaxEjectionTime, detector.maxEjectionTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max ejection time: %w", err)
+	}
+
+	if detector.maxEjectionTime < detector.baseEjectionTime {
+		return nil, fmt.Errorf("max ejection time %s must not be lower than the base ejection time %s", detector.maxEjectionTime, detector.baseEjectionTime)
+	}
+
+	return detector, nil
+}
+
+func parseEjectionTime(value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("must be greater than zero: %s", value)
+	}
+
+	return d, nil
+}
+
+// observe records the status code of the responses of the servers selected by the load-balancer.
+func (o *outlierDetector) observe(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The URL of the request is the URL of the server, until it is rewritten by the forwarder.
+		server := *req.URL
+
+		recorder := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+		next.ServeHTTP(recorder, req)
+
+		o.record(&server, recorder.status >= http.StatusInternalServerError)
+	})
+}
+
+func (o *outlierDetector) record(server *url.URL, failed bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := server.String()
+	state, ok := o.servers[key]
+	if !ok {
+		state = &outlierState{}
+		o.servers[key] = state
+	}
+
+	// The responses of the requests in flight when the server was ejected are ignored.
+	if state.ejected {
+		return
+	}
+
+	if !failed {
+		state.consecutiveErrors = 0
+		return
+	}
+
+	state.consecutiveErrors++
+	if state.consecutiveErrors < o.consecutiveErrors {
+		return
+	}
+
+	o.eject(server, state)
+}
+
+// eject removes the server from the load-balancer, unless too many servers are already ejected.
+// It must be called with the lock held.
+func (o *outlierDetector) eject(server *url.URL, state *outlierState) {
+	logger := log.FromContext(o.ctx).WithField(log.ServerName, server.String())
+
+	var ejected int
+	for _, s := range o.servers {
+		if s.ejected {
+			ejected++
+		}
+	}
+
+	total := len(o.lb.Servers()) + ejected
+	if (ejected+1)*100 > o.maxEjectionPercent*total {
+		logger.Warnf("Outlier detection: not ejecting the server of service %s, %d of its %d servers are already ejected", o.serviceName, ejected, total)
+		return
+	}
+
+	// The ejections are forgotten once the server has stayed in the load-balancer longer than its last ejection.
+	now := time.Now()
+	if state.ejections > 0 && now.Sub(state.returnedAt) > state.lastEjection {
+		state.ejections = 0
+	}
+
+	duration := o.baseEjectionTime
+	for i := 0; i < state.ejections && duration < o.maxEjectionTime; i++ {
+		duration *= 2
+	}
+	if duration > o.maxEjectionTime {
+		duration = o.maxEjectionTime
+	}
+
+	if err := o.lb.RemoveServer(server); err != nil {
+		// The server has already been removed, e.g. by the active health check.
+		logger.Debugf("Outlier detection: unable to eject the server: %v", err)
+		state.consecutiveErrors = 0
+		return
+	}
+
+	logger.Warnf("Outlier detection: ejecting the server of service %s for %s, after %d consecutive errors", o.serviceName, duration, state.consecutiveErrors)
+
+	state.ejected = true
+	state.ejections++
+	state.lastEjection = duration
+
+	o.afterFunc(duration, func() {
+		o.restore(server, state)
+	})
+}
+
+// restore returns an ejected server to the load-balancer.
+func (o *outlierDetector) restore(server *url.URL, state *outlierState) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	logger := log.FromContext(o.ctx).WithField(log.ServerName, server.String())
+	logger.Infof("Outlier detection: returning the server of service %s to the load-balancer", o.serviceName)
+
+	if err := o.lb.UpsertServer(server, roundrobin.Weight(1)); err != nil {
+		logger.Errorf("Outlier detection: unable to return the server: %v", err)
+	}
+
+	state.ejected = false
+	state.consecutiveErrors = 0
+	state.returnedAt = time.Now()
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.wroteHeader = true
+		s.status = code
+	}
+
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b)
+}
+
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", s.ResponseWriter)
+	}
+
+	return hijacker.Hijack()
+}
+
+func (s *statusRecorder) CloseNotify() <-chan bool {
+	if notifier, ok := s.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+
+	return make(chan bool)
+}
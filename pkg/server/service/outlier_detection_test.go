@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestNewOutlierDetector(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.OutlierDetection
+		expectedError bool
+	}{
+		{
+			desc: "defaults",
+		},
+		{
+			desc: "valid configuration",
+			config: dynamic.OutlierDetection{
+				ConsecutiveErrors:  3,
+				BaseEjectionTime:   "10s",
+				MaxEjectionTime:    "1m",
+				MaxEjectionPercent: 100,
+			},
+		},
+		{
+			desc:          "negative consecutive errors",
+			config:        dynamic.OutlierDetection{ConsecutiveErrors: -1},
+			expectedError: true,
+		},
+		{
+			desc:          "max ejection percent above 100",
+			config:        dynamic.OutlierDetection{MaxEjectionPercent: 101},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid base ejection time",
+			config:        dynamic.OutlierDetection{BaseEjectionTime: "foo"},
+			expectedError: true,
+		},
+		{
+			desc:          "max ejection time lower than the base ejection time",
+			config:        dynamic.OutlierDetection{BaseEjectionTime: "1m", MaxEjectionTime: "10s"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newOutlierDetector(context.Background(), "foo", &test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOutlierDetector(t *testing.T) {
+	failing := map[string]bool{"127.0.0.1:8001": true}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if failing[req.URL.Host] {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	detector, err := newOutlierDetector(context.Background(), "foo", &dynamic.OutlierDetection{
+		ConsecutiveErrors: 3,
+		BaseEjectionTime:  "10s",
+		MaxEjectionTime:   "25s",
+	})
+	require.NoError(t, err)
+
+	var scheduled []time.Duration
+	var restore []func()
+	detector.afterFunc = func(d time.Duration, f func()) {
+		scheduled = append(scheduled, d)
+		restore = append(restore, f)
+	}
+
+	lb, err := roundrobin.New(detector.observe(next))
+	require.NoError(t, err)
+	detector.lb = lb
+
+	for i := 1; i <= 4; i++ {
+		u := testhelpers.MustParseURL(fmt.Sprintf("http://127.0.0.1:800%d", i))
+		require.NoError(t, lb.UpsertServer(u, roundrobin.Weight(1)))
+	}
+
+	serve := func(n int) {
+		for i := 0; i < n; i++ {
+			lb.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		}
+	}
+
+	// The failing server gets one request in four.
+	serve(8)
+	assert.Len(t, lb.Servers(), 4)
+
+	serve(4)
+	assert.Len(t, lb.Servers(), 3)
+	assert.Equal(t, []time.Duration{10 * time.Second}, scheduled)
+
+	restore[0]()
+	assert.Len(t, lb.Servers(), 4)
+
+	// An ejection right after the return lasts twice as long, up to the max ejection time.
+	serve(12)
+	assert.Len(t, lb.Servers(), 3)
+	restore[1]()
+
+	serve(12)
+	assert.Len(t, lb.Servers(), 3)
+	restore[2]()
+
+	assert.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second, 25 * time.Second}, scheduled)
+}
+
+func TestOutlierDetector_maxEjectionPercent(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	detector, err := newOutlierDetector(context.Background(), "foo", &dynamic.OutlierDetection{ConsecutiveErrors: 1})
+	require.NoError(t, err)
+	detector.afterFunc = func(time.Duration, func()) {}
+
+	lb, err := roundrobin.New(detector.observe(next))
+	require.NoError(t, err)
+	detector.lb = lb
+
+	for i := 1; i <= 4; i++ {
+		u := testhelpers.MustParseURL(fmt.Sprintf("http://127.0.0.1:800%d", i))
+		require.NoError(t, lb.UpsertServer(u, roundrobin.Weight(1)))
+	}
+
+	for i := 0; i < 20; i++ {
+		lb.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	}
+
+	// At most half of the servers are ejected.
+	assert.Len(t, lb.Servers(), 2)
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/log"
+	"golang.org/x/net/http/httpguts"
+	"golang.org/x/net/http2"
+)
+
+const (
+	// minProbeBackoff is the duration an unreachable server is considered to speak HTTP/1.1 before probing it again,
+	// doubled with each consecutive failure up to the cache duration.
+	minProbeBackoff = time.Second
+
+	// h2cUpgradeSettings is the HTTP2-Settings header of the upgrade probe,
+	// the base64url encoding of a SETTINGS frame payload disabling the server push.
+	h2cUpgradeSettings = "AAIAAAAA"
+)
+
+type detectedProtocol struct {
+	h2c     bool
+	expires time.Time
+	// failures is the number of consecutive probes which could not reach the server.
+	failures int
+}
+
+// protocolDetector forwards the requests to the servers reached over cleartext HTTP with HTTP/2 (h2c) when they speak it,
+// and with the given round tripper otherwise.
+// The protocol of each server is probed with an h2c upgrade request, then with the HTTP/2 connection preface (prior knowledge),
+// and the result is cached, so that the servers of a service do not all have to speak the same protocol.
+type protocolDetector struct {
+	next http.RoundTripper
+	h2c  http.RoundTripper
+
+	dialContext   func(ctx context.Context, network, addr string) (net.Conn, error)
+	probeTimeout  time.Duration
+	cacheDuration time.Duration
+
+	mu        sync.Mutex
+	protocols map[string]detectedProtocol
+	// probing holds the probes in progress, so that concurrent requests to a server wait for the same probe.
+	probing map[string]chan struct{}
+}
+
+func newProtocolDetector(config *static.ProtocolDetection, next, h2c http.RoundTripper, dialContext func(ctx context.Context, network, addr string) (net.Conn, error)) *protocolDetector {
+	return &protocolDetector{
+		next:          next,
+		h2c:           h2c,
+		dialContext:   dialContext,
+		probeTimeout:  time.Duration(config.ProbeTimeout),
+		cacheDuration: time.Duration(config.CacheDuration),
+		protocols:     make(map[string]detectedProtocol),
+		probing:       make(map[string]chan struct{}),
+	}
+}
+
+func (d *protocolDetector) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The protocols starting with a Connection Upgrade, such as Websocket, are only spoken over HTTP/1.1.
+	if req.URL.Scheme != "http" || httpguts.HeaderValuesContainsToken(req.Header["Connection"], "Upgrade") {
+		return d.next.RoundTrip(req)
+	}
+
+	addr := probeAddress(req.URL)
+	if !d.isH2C(req.Context(), addr) {
+		return d.next.RoundTrip(req)
+	}
+
+	resp, err := d.h2c.RoundTrip(req)
+	if err != nil {
+		// The server may have been replaced by one which does not speak h2c anymore.
+		d.forget(addr)
+	}
+
+	return resp, err
+}
+
+// CloseIdleConnections closes the idle connections of both round trippers.
+func (d *protocolDetector) CloseIdleConnections() {
+	if rt, ok := d.next.(closeIdler); ok {
+		rt.CloseIdleConnections()
+	}
+
+	if rt, ok := d.h2c.(closeIdler); ok {
+		rt.CloseIdleConnections()
+	}
+}
+
+// isH2C returns whether the server at the given address speaks h2c, probing it if its protocol is unknown or expired.
+func (d *protocolDetector) isH2C(ctx context.Context, addr string) bool {
+	for {
+		d.mu.Lock()
+		protocol, ok := d.protocols[addr]
+		if ok && time.Now().Before(protocol.expires) {
+			d.mu.Unlock()
+			return protocol.h2c
+		}
+
+		wait, probing := d.probing[addr]
+		if !probing {
+			done := make(chan struct{})
+			d.probing[addr] = done
+			d.mu.Unlock()
+
+			return d.probe(ctx, addr, done)
+		}
+		d.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (d *protocolDetector) probe(ctx context.Context, addr string, done chan struct{}) bool {
+	h2c, err := probeH2CUpgrade(ctx, d.dialContext, addr, d.probeTimeout)
+	if err == nil && !h2c {
+		// The servers which do not answer the upgrade can still accept the connection preface.
+		h2c, err = probeH2C(ctx, d.dialContext, addr, d.probeTimeout)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.probing, addr)
+	close(done)
+
+	if err != nil {
+		// The server is considered to speak HTTP/1.1 until the next probe, which is delayed more with each failure.
+		failures := d.protocols[addr].failures + 1
+		backoff := d.backoff(failures)
+
+		log.FromContext(ctx).Debugf("Unable to probe the protocol of %s, probing it again in %s: %v", addr, backoff, err)
+		d.protocols[addr] = detectedProtocol{expires: time.Now().Add(backoff), failures: failures}
+		return false
+	}
+
+	log.FromContext(ctx).Debugf("Detected protocol for %s: h2c=%t", addr, h2c)
+	d.protocols[addr] = detectedProtocol{h2c: h2c, expires: time.Now().Add(d.cacheDuration)}
+
+	return h2c
+}
+
+// backoff returns the delay before probing again a server after the given number of consecutive failures.
+func (d *protocolDetector) backoff(failures int) time.Duration {
+	backoff := minProbeBackoff
+	for i := 1; i < failures && backoff < d.cacheDuration; i++ {
+		backoff *= 2
+	}
+
+	if backoff > d.cacheDuration {
+		return d.cacheDuration
+	}
+	return backoff
+}
+
+func (d *protocolDetector) forget(addr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.protocols, addr)
+}
+
+// probeAddress returns the address of the server of the given URL, with the default port of its scheme if it has none.
+func probeAddress(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+
+	port := "80"
+	if u.Scheme == "https" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// probeH2CUpgrade sends an HTTP/1.1 request asking the server at the given address to upgrade the connection to h2c,
+// which it speaks if it switches the protocols.
+// A server supporting the upgrade is known to speak HTTP/2,
+// so the requests are then sent to it with the connection preface (prior knowledge), cf RFC 7540 section 3.4.
+// An error is returned only when the server is unreachable.
+func probeH2CUpgrade(ctx context.Context, dialContext func(ctx context.Context, network, addr string) (net.Conn, error), addr string, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := dialContext(ctx, "tcp", addr)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err = conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return false, err
+	}
+
+	req := &http.Request{
+		Method: http.MethodOptions,
+		URL:    &url.URL{Path: "/"},
+		Host:   addr,
+		Header: http.Header{
+			"Connection":     {"Upgrade, HTTP2-Settings"},
+			"Upgrade":        {"h2c"},
+			"Http2-Settings": {h2cUpgradeSettings},
+		},
+	}
+
+	if err = req.Write(conn); err != nil {
+		return false, nil
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return false, nil
+	}
+	_ = resp.Body.Close()
+
+	return resp.StatusCode == http.StatusSwitchingProtocols && strings.EqualFold(resp.Header.Get("Upgrade"), "h2c"), nil
+}
+
+// probeH2C sends the HTTP/2 connection preface to the server at the given address,
+// which speaks h2c if it answers with a SETTINGS frame.
+// An error is returned only when the server is unreachable.
+func probeH2C(ctx context.Context, dialContext func(ctx context.Context, network, addr string) (net.Conn, error), addr string, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := dialContext(ctx, "tcp", addr)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err = conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return false, err
+	}
+
+	if _, err = io.WriteString(conn, http2.ClientPreface); err != nil {
+		return false, nil
+	}
+
+	framer := http2.NewFramer(conn, conn)
+	if err = framer.WriteSettings(); err != nil {
+		return false, nil
+	}
+
+	// An HTTP/1.1 server answers with an error status line, which is not a valid frame.
+	frame, err := framer.ReadFrame()
+	if err != nil {
+		return false, nil
+	}
+
+	_, ok := frame.(*http2.SettingsFrame)
+	return ok, nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestProtocolDetector(t *testing.T) {
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Proto", req.Proto)
+	})
+
+	h2cServer := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer h2cServer.Close()
+
+	http1Server := httptest.NewServer(handler)
+	defer http1Server.Close()
+
+	dialer := &net.Dialer{}
+	h2cTransport := &h2cTransportWrapper{
+		Transport: &http2.Transport{
+			DialTLS: func(netw, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(netw, addr)
+			},
+			AllowHTTP: true,
+		},
+	}
+
+	detector := newProtocolDetector(&static.ProtocolDetection{
+		ProbeTimeout:  types.Duration(time.Second),
+		CacheDuration: types.Duration(time.Minute),
+	}, http.DefaultTransport, h2cTransport, dialer.DialContext)
+
+	testCases := []struct {
+		desc          string
+		url           string
+		upgrade       bool
+		expectedProto string
+	}{
+		{
+			desc:          "h2c server",
+			url:           h2cServer.URL,
+			expectedProto: "HTTP/2.0",
+		},
+		{
+			desc:          "HTTP/1.1 server",
+			url:           http1Server.URL,
+			expectedProto: "HTTP/1.1",
+		},
+		{
+			desc:          "connection upgrade to an h2c server",
+			url:           h2cServer.URL,
+			upgrade:       true,
+			expectedProto: "HTTP/1.1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			require.NoError(t, err)
+
+			if test.upgrade {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "foo")
+			}
+
+			// The second request uses the cached protocol.
+			for i := 0; i < 2; i++ {
+				resp, err := detector.RoundTrip(req)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+
+				assert.Equal(t, test.expectedProto, resp.Header.Get("X-Proto"))
+			}
+		})
+	}
+
+	assert.Len(t, detector.protocols, 2)
+}
+
+func TestProbeH2CUpgrade(t *testing.T) {
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	h2cServer := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer h2cServer.Close()
+
+	http1Server := httptest.NewServer(handler)
+	defer http1Server.Close()
+
+	dialer := &net.Dialer{}
+
+	upgraded, err := probeH2CUpgrade(context.Background(), dialer.DialContext, h2cServer.Listener.Addr().String(), time.Second)
+	require.NoError(t, err)
+	assert.True(t, upgraded)
+
+	upgraded, err = probeH2CUpgrade(context.Background(), dialer.DialContext, http1Server.Listener.Addr().String(), time.Second)
+	require.NoError(t, err)
+	assert.False(t, upgraded)
+}
+
+func TestProtocolDetector_unreachable(t *testing.T) {
+	var dials int
+	dialContext := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials++
+		return nil, errors.New("connection refused")
+	}
+
+	detector := newProtocolDetector(&static.ProtocolDetection{
+		ProbeTimeout:  types.Duration(time.Second),
+		CacheDuration: types.Duration(time.Minute),
+	}, http.DefaultTransport, http.DefaultTransport, dialContext)
+
+	// The failure is cached, so the second request does not probe the server again.
+	assert.False(t, detector.isH2C(context.Background(), "backend:80"))
+	assert.False(t, detector.isH2C(context.Background(), "backend:80"))
+	assert.Equal(t, 1, dials)
+
+	assert.Equal(t, time.Second, detector.backoff(1))
+	assert.Equal(t, 4*time.Second, detector.backoff(3))
+	assert.Equal(t, time.Minute, detector.backoff(10))
+}
+
+func TestProbeAddress(t *testing.T) {
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{url: "http://backend:8080", expected: "backend:8080"},
+		{url: "http://backend", expected: "backend:80"},
+		{url: "https://backend", expected: "backend:443"},
+		{url: "http://[fd00::1]", expected: "[fd00::1]:80"},
+	}
+
+	for _, test := range testCases {
+		u, err := url.Parse(test.url)
+		require.NoError(t, err)
+
+		assert.Equal(t, test.expected, probeAddress(u), test.url)
+	}
+}
+
+func TestProbeH2C_unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	dialer := &net.Dialer{}
+	_, err = probeH2C(context.Background(), dialer.DialContext, addr, time.Second)
+	assert.Error(t, err)
+}
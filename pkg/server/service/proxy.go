@@ -2,32 +2,82 @@ package service
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/http/httputil"
 	"net/url"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/middlewares/bodytimeout"
+	"github.com/containous/traefik/v2/pkg/middlewares/retry"
 	"github.com/containous/traefik/v2/pkg/types"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 )
 
 // StatusClientClosedRequest non-standard HTTP status code for client disconnection.
-const StatusClientClosedRequest = 499
+const StatusClientClosedRequest = middlewares.StatusClientClosedRequest
 
 // StatusClientClosedRequestText non-standard HTTP status for client disconnection.
-const StatusClientClosedRequestText = "Client Closed Request"
+const StatusClientClosedRequestText = middlewares.StatusClientClosedRequestText
+
+// Causes of the 502 and 504 responses generated by the proxy.
+const (
+	causeDNS               = "dns"
+	causeDialTimeout       = "dial_timeout"
+	causeConnectionRefused = "connection_refused"
+	causeDial              = "dial"
+	causeTLS               = "tls"
+	causeConnectionReset   = "connection_reset"
+	causeHeaderTimeout     = "header_timeout"
+	causeTimeout           = "timeout"
+	causeEOF               = "eof"
+	causeUnknown           = "unknown"
+)
+
+// proxyOptions are the optional settings of the proxy.
+type proxyOptions struct {
+	// errorsCounter counts the 502 and 504 responses generated by the proxy, by cause.
+	errorsCounter gokitmetrics.Counter
+}
+
+// proxyOption sets an optional setting of the proxy.
+type proxyOption func(*proxyOptions)
+
+// withErrorsCounter counts the 502 and 504 responses generated by the proxy with the given counter.
+func withErrorsCounter(counter gokitmetrics.Counter) proxyOption {
+	return func(opts *proxyOptions) {
+		opts.errorsCounter = counter
+	}
+}
+
+func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwarding, defaultRoundTripper http.RoundTripper, bufferPool httputil.BufferPool, responseModifier func(*http.Response) error, options ...proxyOption) (http.Handler, error) {
+	var opts proxyOptions
+	for _, option := range options {
+		option(&opts)
+	}
 
-func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwarding, defaultRoundTripper http.RoundTripper, bufferPool httputil.BufferPool, responseModifier func(*http.Response) error) (http.Handler, error) {
 	var flushInterval types.Duration
+	var errorCauseHeader string
 	if responseForwarding != nil {
-		err := flushInterval.Set(responseForwarding.FlushInterval)
-		if err != nil {
-			return nil, fmt.Errorf("error creating flush interval: %w", err)
+		if responseForwarding.FlushInterval != "" {
+			err := flushInterval.Set(responseForwarding.FlushInterval)
+			if err != nil {
+				return nil, fmt.Errorf("error creating flush interval: %w", err)
+			}
 		}
+		errorCauseHeader = responseForwarding.ErrorCauseHeader
 	}
 	if flushInterval == 0 {
 		flushInterval = types.Duration(100 * time.Millisecond)
@@ -84,10 +134,14 @@ func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwar
 			statusCode := http.StatusInternalServerError
 
 			switch {
-			case err == io.EOF:
-				statusCode = http.StatusBadGateway
-			case err == context.Canceled:
+			case errors.Is(err, context.Canceled), middlewares.ClientAborted(request):
+				// The error caused by the client going away is not always a context.Canceled one,
+				// e.g. "net/http: request canceled" while reading the response headers.
 				statusCode = StatusClientClosedRequest
+			case errors.Is(err, bodytimeout.ErrTimeout):
+				statusCode = http.StatusRequestTimeout
+			case err == io.EOF, isTLSError(err):
+				statusCode = http.StatusBadGateway
 			default:
 				if e, ok := err.(net.Error); ok {
 					if e.Timeout() {
@@ -99,6 +153,28 @@ func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwar
 			}
 
 			log.Debugf("'%d %s' caused by: %v", statusCode, statusText(statusCode), err)
+
+			if statusCode == http.StatusBadGateway || statusCode == http.StatusGatewayTimeout {
+				progress, _ := request.Context().Value(progressKey{}).(*requestProgress)
+				cause := errorCause(err, progress)
+
+				if logData := accesslog.GetLogData(request); logData != nil {
+					logData.Core[accesslog.OriginErrorCause] = cause
+				}
+
+				if opts.errorsCounter != nil {
+					opts.errorsCounter.With("cause", cause).Add(1)
+				}
+
+				if cause == causeConnectionReset || cause == causeEOF {
+					retry.MarkConnectionReset(request)
+				}
+
+				if errorCauseHeader != "" {
+					w.Header().Set(errorCauseHeader, cause)
+				}
+			}
+
 			w.WriteHeader(statusCode)
 			_, werr := w.Write([]byte(statusText(statusCode)))
 			if werr != nil {
@@ -107,7 +183,113 @@ func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwar
 		},
 	}
 
-	return proxy, nil
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		proxy.ServeHTTP(rw, withProgress(req))
+	}), nil
+}
+
+type progressKey struct{}
+
+// requestProgress records how far the request to the server went,
+// to tell apart the timeouts of the transport, whose errors are not exported.
+type requestProgress struct {
+	tlsHandshakeErr int32
+	requestWritten  int32
+}
+
+// withProgress records the progress of the request to the server in its context.
+func withProgress(req *http.Request) *http.Request {
+	progress := &requestProgress{}
+
+	trace := &httptrace.ClientTrace{
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err != nil {
+				atomic.StoreInt32(&progress.tlsHandshakeErr, 1)
+			}
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				atomic.StoreInt32(&progress.requestWritten, 1)
+			}
+		},
+	}
+
+	ctx := httptrace.WithClientTrace(req.Context(), trace)
+	return req.WithContext(context.WithValue(ctx, progressKey{}, progress))
+}
+
+func (p *requestProgress) tlsHandshakeFailed() bool {
+	return p != nil && atomic.LoadInt32(&p.tlsHandshakeErr) == 1
+}
+
+func (p *requestProgress) wroteRequest() bool {
+	return p != nil && atomic.LoadInt32(&p.requestWritten) == 1
+}
+
+// errorCause returns the machine-readable cause of the given error returned by the transport,
+// with the progress of the request, if known.
+func errorCause(err error, progress *requestProgress) string {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return causeDNS
+	}
+
+	// The TLS handshake timeout of the transport is only known from the progress of the request.
+	if isTLSError(err) || progress.tlsHandshakeFailed() {
+		return causeTLS
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		switch {
+		case opErr.Timeout():
+			return causeDialTimeout
+		case errors.Is(err, syscall.ECONNREFUSED):
+			return causeConnectionRefused
+		default:
+			return causeDial
+		}
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return causeConnectionReset
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return causeEOF
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		// The error of the ResponseHeaderTimeout of the transport is not exported,
+		// it is the timeout of a request written to a connection, other than the deadline of the request.
+		var opErr *net.OpError
+		if progress.wroteRequest() && !errors.As(err, &opErr) && !errors.Is(err, context.DeadlineExceeded) {
+			return causeHeaderTimeout
+		}
+		return causeTimeout
+	}
+
+	return causeUnknown
+}
+
+// isTLSError tells whether the error is a failure of the TLS handshake with the server, other than a timeout.
+func isTLSError(err error) bool {
+	var recordHeaderErr tls.RecordHeaderError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateInvalidErr x509.CertificateInvalidError
+	var systemRootsErr x509.SystemRootsError
+	var opErr *net.OpError
+
+	return errors.Is(err, errNoPinnedPublicKey) ||
+		errors.As(err, &recordHeaderErr) ||
+		errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &certificateInvalidErr) ||
+		errors.As(err, &systemRootsErr) ||
+		// The TLS alerts, sent by the server or by Traefik, are reported as "remote error" and "local error" operations.
+		errors.As(err, &opErr) && (opErr.Op == "remote error" || opErr.Op == "local error")
 }
 
 func statusText(statusCode int) string {
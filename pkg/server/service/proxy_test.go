@@ -1,13 +1,26 @@
 package service
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
+	"os"
 	"strings"
+	"syscall"
 	"testing"
 
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type staticTransport struct {
@@ -18,6 +31,194 @@ func (t *staticTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	return t.res, nil
 }
 
+// errorTransport fails the requests with the given error,
+// once it has reported the failed TLS handshake or the written request to the trace of the request, if any.
+type errorTransport struct {
+	err                error
+	tlsHandshakeFailed bool
+	wroteRequest       bool
+}
+
+func (t *errorTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if trace := httptrace.ContextClientTrace(r.Context()); trace != nil {
+		if t.tlsHandshakeFailed && trace.TLSHandshakeDone != nil {
+			trace.TLSHandshakeDone(tls.ConnectionState{}, t.err)
+		}
+		if t.wroteRequest && trace.WroteRequest != nil {
+			trace.WroteRequest(httptrace.WroteRequestInfo{})
+		}
+	}
+
+	return nil, t.err
+}
+
+type timeoutError struct {
+	msg string
+}
+
+func (e timeoutError) Error() string   { return e.msg }
+func (e timeoutError) Timeout() bool   { return true }
+func (e timeoutError) Temporary() bool { return true }
+
+func Test_errorCause(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		progress *requestProgress
+		expected string
+	}{
+		{
+			desc:     "DNS error",
+			err:      &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "backend.local", IsNotFound: true}},
+			expected: causeDNS,
+		},
+		{
+			desc:     "dial timeout",
+			err:      &net.OpError{Op: "dial", Err: timeoutError{msg: "i/o timeout"}},
+			expected: causeDialTimeout,
+		},
+		{
+			desc:     "connection refused",
+			err:      &net.OpError{Op: "dial", Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}},
+			expected: causeConnectionRefused,
+		},
+		{
+			desc:     "unknown certificate authority",
+			err:      x509.UnknownAuthorityError{},
+			expected: causeTLS,
+		},
+		{
+			desc:     "no pinned public key",
+			err:      errNoPinnedPublicKey,
+			expected: causeTLS,
+		},
+		{
+			desc:     "TLS alert",
+			err:      &net.OpError{Op: "remote error", Err: errors.New("tls: bad certificate")},
+			expected: causeTLS,
+		},
+		{
+			desc:     "TLS handshake timeout",
+			err:      timeoutError{msg: "net/http: TLS handshake timeout"},
+			progress: &requestProgress{tlsHandshakeErr: 1},
+			expected: causeTLS,
+		},
+		{
+			desc:     "connection reset",
+			err:      &net.OpError{Op: "read", Err: &os.SyscallError{Syscall: "read", Err: syscall.ECONNRESET}},
+			expected: causeConnectionReset,
+		},
+		{
+			desc:     "response header timeout",
+			err:      timeoutError{msg: "net/http: timeout awaiting response headers"},
+			progress: &requestProgress{requestWritten: 1},
+			expected: causeHeaderTimeout,
+		},
+		{
+			desc:     "timeout before writing the request",
+			err:      timeoutError{msg: "timeout"},
+			expected: causeTimeout,
+		},
+		{
+			desc:     "request deadline",
+			err:      context.DeadlineExceeded,
+			progress: &requestProgress{requestWritten: 1},
+			expected: causeTimeout,
+		},
+		{
+			desc:     "read timeout",
+			err:      &net.OpError{Op: "read", Err: timeoutError{msg: "i/o timeout"}},
+			expected: causeTimeout,
+		},
+		{
+			desc:     "EOF",
+			err:      fmt.Errorf("wrapped: %w", io.EOF),
+			expected: causeEOF,
+		},
+		{
+			desc:     "unknown",
+			err:      errors.New("oops"),
+			expected: causeUnknown,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, errorCause(test.err, test.progress))
+		})
+	}
+}
+
+func TestProxy_errorCause(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		transport          *errorTransport
+		expectedStatusCode int
+		expectedCause      string
+	}{
+		{
+			desc:               "bad gateway",
+			transport:          &errorTransport{err: &net.OpError{Op: "dial", Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}}},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedCause:      causeConnectionRefused,
+		},
+		{
+			desc:               "gateway timeout",
+			transport:          &errorTransport{err: timeoutError{msg: "net/http: timeout awaiting response headers"}, wroteRequest: true},
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedCause:      causeHeaderTimeout,
+		},
+		{
+			desc:               "TLS handshake timeout",
+			transport:          &errorTransport{err: timeoutError{msg: "net/http: TLS handshake timeout"}, tlsHandshakeFailed: true},
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedCause:      causeTLS,
+		},
+		{
+			desc:               "TLS handshake failure",
+			transport:          &errorTransport{err: x509.HostnameError{Certificate: &x509.Certificate{}, Host: "foo.bar"}, tlsHandshakeFailed: true},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedCause:      causeTLS,
+		},
+		{
+			desc:               "internal server error",
+			transport:          &errorTransport{err: errors.New("oops")},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &testhelpers.CollectingCounter{}
+			responseForwarding := &dynamic.ResponseForwarding{ErrorCauseHeader: "X-Error-Cause"}
+
+			handler, err := buildProxy(Bool(true), responseForwarding, test.transport, nil, nil, withErrorsCounter(counter))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil).WithContext(context.Background())
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatusCode, rw.Code)
+			assert.Equal(t, test.expectedCause, rw.Header().Get("X-Error-Cause"))
+
+			if test.expectedCause == "" {
+				assert.Zero(t, counter.CounterValue)
+				return
+			}
+
+			assert.Equal(t, float64(1), counter.CounterValue)
+			assert.Equal(t, []string{"cause", test.expectedCause}, counter.LastLabelValues)
+		})
+	}
+}
+
 func BenchmarkProxy(b *testing.B) {
 	res := &http.Response{
 		StatusCode: 200,
@@ -0,0 +1,68 @@
+package redirect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+// Redirect is an http.Handler answering all the requests with a redirection.
+type Redirect struct {
+	url        *template.Template
+	statusCode int
+}
+
+// New returns a new instance of *Redirect.
+func New(config dynamic.RedirectService) (*Redirect, error) {
+	if config.URL == "" {
+		return nil, errors.New("the redirection URL is required")
+	}
+
+	statusCode := config.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusFound
+	}
+	if statusCode < http.StatusMultipleChoices || statusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("invalid redirection status code: %d", statusCode)
+	}
+
+	tmpl, err := template.New("url").Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing URL template: %w", err)
+	}
+
+	return &Redirect{url: tmpl, statusCode: statusCode}, nil
+}
+
+func (r *Redirect) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	data := struct {
+		Scheme string
+		Host   string
+		Path   string
+		Query  string
+	}{
+		Scheme: scheme,
+		Host:   req.Host,
+		Path:   req.URL.EscapedPath(),
+		Query:  req.URL.RawQuery,
+	}
+
+	var location bytes.Buffer
+	if err := r.url.Execute(&location, data); err != nil {
+		log.FromContext(req.Context()).Errorf("Error while evaluating redirection URL template: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(rw, req, location.String(), r.statusCode)
+}
@@ -0,0 +1,97 @@
+package redirect
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirect(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           dynamic.RedirectService
+		url              string
+		secured          bool
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			desc:             "static URL",
+			config:           dynamic.RedirectService{URL: "https://example.com/"},
+			url:              "http://example.org/foo",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://example.com/",
+		},
+		{
+			desc:             "apex to www",
+			config:           dynamic.RedirectService{URL: "{{ .Scheme }}://www.{{ .Host }}{{ .Path }}", StatusCode: http.StatusMovedPermanently},
+			url:              "http://example.com/foo/bar",
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "http://www.example.com/foo/bar",
+		},
+		{
+			desc:             "keeps the scheme and the query",
+			config:           dynamic.RedirectService{URL: "{{ .Scheme }}://example.com{{ .Path }}?{{ .Query }}", StatusCode: http.StatusPermanentRedirect},
+			url:              "https://example.org/foo?bar=baz",
+			secured:          true,
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "https://example.com/foo?bar=baz",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := New(test.config)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, test.url, nil)
+			if test.secured {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedLocation, recorder.Header().Get("Location"))
+		})
+	}
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.RedirectService
+	}{
+		{
+			desc:   "missing URL",
+			config: dynamic.RedirectService{},
+		},
+		{
+			desc:   "invalid status code",
+			config: dynamic.RedirectService{URL: "https://example.com", StatusCode: http.StatusOK},
+		},
+		{
+			desc:   "invalid template",
+			config: dynamic.RedirectService{URL: "https://{{ .Host"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(test.config)
+			assert.Error(t, err)
+		})
+	}
+}
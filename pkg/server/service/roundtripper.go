@@ -1,16 +1,27 @@
 package service
 
 import (
+	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/safe"
 	traefiktls "github.com/containous/traefik/v2/pkg/tls"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"golang.org/x/net/http2"
 )
 
@@ -23,14 +34,34 @@ func (t *h2cTransportWrapper) RoundTrip(req *http.Request) (*http.Response, erro
 	return t.Transport.RoundTrip(req)
 }
 
+type closeIdler interface {
+	CloseIdleConnections()
+}
+
 // createRoundtripper creates an http.Roundtripper configured with the Transport configuration settings.
 // For the settings that can't be configured in Traefik it uses the default http.Transport settings.
 // An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHost
 // in Traefik at this point in time. Setting this value to the default of 100 could lead to confusing
 // behavior and backwards compatibility issues.
-func createRoundtripper(transportConfiguration *static.ServersTransport) (http.RoundTripper, error) {
+func createRoundtripper(transportConfiguration *static.ServersTransport, metricsRegistry metrics.Registry, routinesPool *safe.Pool) (http.RoundTripper, error) {
+	roundTripper, resolver, err := buildRoundTripper(transportConfiguration, metricsRegistry, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolver != nil && routinesPool != nil {
+		routinesPool.GoCtx(resolver.run)
+	}
+
+	return roundTripper, nil
+}
+
+// buildRoundTripper creates the round tripper of the given Transport configuration,
+// along with its DNS resolver, if any, which is left to the caller to run.
+// When conns is not nil, it counts the connections opened by the round tripper.
+func buildRoundTripper(transportConfiguration *static.ServersTransport, metricsRegistry metrics.Registry, conns *connCounter) (http.RoundTripper, *dnsResolver, error) {
 	if transportConfiguration == nil {
-		return nil, errors.New("no transport configuration given")
+		return nil, nil, errors.New("no transport configuration given")
 	}
 
 	dialer := &net.Dialer{
@@ -43,42 +74,119 @@ func createRoundtripper(transportConfiguration *static.ServersTransport) (http.R
 		dialer.Timeout = time.Duration(transportConfiguration.ForwardingTimeouts.DialTimeout)
 	}
 
+	dialContext := dialer.DialContext
+
+	var resolver *dnsResolver
+	if transportConfiguration.DNSResolution != nil {
+		var staleConnsGauge gokitmetrics.Gauge
+		if metricsRegistry != nil {
+			staleConnsGauge = metricsRegistry.ServiceStaleConnsGauge()
+		}
+
+		resolver = newDNSResolver(transportConfiguration.DNSResolution, dialer, staleConnsGauge)
+		dialContext = resolver.DialContext
+	}
+
+	if conns != nil {
+		dialContext = conns.wrap(dialContext)
+	}
+
 	transport := &http.Transport{
 		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
+		DialContext:           dialContext,
 		MaxIdleConnsPerHost:   transportConfiguration.MaxIdleConnsPerHost,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
 	}
 
-	transport.RegisterProtocol("h2c", &h2cTransportWrapper{
+	h2cTransport := &h2cTransportWrapper{
 		Transport: &http2.Transport{
 			DialTLS: func(netw, addr string, cfg *tls.Config) (net.Conn, error) {
-				return net.Dial(netw, addr)
+				return dialContext(context.Background(), netw, addr)
 			},
 			AllowHTTP: true,
 		},
-	})
+	}
+
+	transport.RegisterProtocol("h2c", h2cTransport)
 
 	if transportConfiguration.ForwardingTimeouts != nil {
 		transport.ResponseHeaderTimeout = time.Duration(transportConfiguration.ForwardingTimeouts.ResponseHeaderTimeout)
 		transport.IdleConnTimeout = time.Duration(transportConfiguration.ForwardingTimeouts.IdleConnTimeout)
 	}
 
-	if transportConfiguration.InsecureSkipVerify || len(transportConfiguration.RootCAs) > 0 {
+	// The server name templated from the host of the requests is set by a round tripper per server name.
+	templatedServerName := strings.Contains(transportConfiguration.ServerName, hostPlaceholder)
+
+	if transportConfiguration.InsecureSkipVerify || len(transportConfiguration.RootCAs) > 0 ||
+		transportConfiguration.ServerName != "" || len(transportConfiguration.PinnedPublicKeys) > 0 ||
+		transportConfiguration.ClientCertificate != nil {
 		transport.TLSClientConfig = &tls.Config{
 			InsecureSkipVerify: transportConfiguration.InsecureSkipVerify,
 			RootCAs:            createRootCACertPool(transportConfiguration.RootCAs),
 		}
+
+		if !templatedServerName {
+			transport.TLSClientConfig.ServerName = transportConfiguration.ServerName
+		}
+
+		if len(transportConfiguration.PinnedPublicKeys) > 0 {
+			verify, err := verifyPinnedPublicKeys(transportConfiguration.PinnedPublicKeys)
+			if err != nil {
+				return nil, nil, err
+			}
+			transport.TLSClientConfig.VerifyPeerCertificate = verify
+		}
+
+		if transportConfiguration.ClientCertificate != nil {
+			cert, err := loadClientCertificate(transportConfiguration.ClientCertificate)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to load the client certificate: %w", err)
+			}
+			transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+		}
+	}
+
+	var smartTransport http.RoundTripper
+	if templatedServerName {
+		smartTransport = newServerNameRoundTripper(transportConfiguration.ServerName, func(serverName string) (http.RoundTripper, error) {
+			serverNameTransport := transport.Clone()
+			serverNameTransport.TLSClientConfig.ServerName = serverName
+			return newSmartRoundTripper(serverNameTransport)
+		})
+	} else {
+		var err error
+		smartTransport, err = newSmartRoundTripper(transport)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	roundTripper := smartTransport
+	if transportConfiguration.ProtocolDetection != nil {
+		roundTripper = newProtocolDetector(transportConfiguration.ProtocolDetection, smartTransport, h2cTransport, dialContext)
 	}
 
-	smartTransport, err := newSmartRoundTripper(transport)
+	if resolver != nil {
+		resolver.closeIdleConns = roundTripper.(closeIdler).CloseIdleConnections
+	}
+
+	return roundTripper, resolver, nil
+}
+
+func loadClientCertificate(config *static.ClientCertificate) (tls.Certificate, error) {
+	certContent, err := config.CertFile.Read()
 	if err != nil {
-		return nil, err
+		return tls.Certificate{}, err
+	}
+
+	keyContent, err := config.KeyFile.Read()
+	if err != nil {
+		return tls.Certificate{}, err
 	}
 
-	return smartTransport, nil
+	return tls.X509KeyPair(certContent, keyContent)
 }
 
 func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
@@ -100,8 +208,149 @@ func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
 	return roots
 }
 
-func setupDefaultRoundTripper(conf *static.ServersTransport) http.RoundTripper {
-	transport, err := createRoundtripper(conf)
+var errNoPinnedPublicKey = errors.New("no public key of the server certificates matches the pinned public keys")
+
+// verifyPinnedPublicKeys returns a certificate verification function succeeding when the public key
+// of one of the certificates presented by the server matches one of the pins.
+// It complements the usual verification of the chain, which still applies unless InsecureSkipVerify is set.
+func verifyPinnedPublicKeys(pins []string) (func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error, error) {
+	hashes := make(map[[sha256.Size]byte]struct{}, len(pins))
+	for _, pin := range pins {
+		decoded, err := base64.StdEncoding.DecodeString(pin)
+		if err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("invalid pinned public key %q: not a base64 encoded SHA-256 hash", pin)
+		}
+
+		var hash [sha256.Size]byte
+		copy(hash[:], decoded)
+		hashes[hash] = struct{}{}
+	}
+
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		for _, rawCert := range rawCerts {
+			cert, err := x509.ParseCertificate(rawCert)
+			if err != nil {
+				continue
+			}
+
+			if _, ok := hashes[sha256.Sum256(cert.RawSubjectPublicKeyInfo)]; ok {
+				return nil
+			}
+		}
+
+		return errNoPinnedPublicKey
+	}, nil
+}
+
+// transportPool creates and caches the round trippers of the services overriding the TLS options of the serversTransport,
+// so that their connections are reused across the configuration reloads.
+type transportPool struct {
+	conf               *static.ServersTransport
+	metricsRegistry    metrics.Registry
+	drainingConnsGauge gokitmetrics.Gauge
+
+	mu         sync.Mutex
+	transports map[string]*pooledTransport
+	// draining are the transports removed from the pool whose connections are not all closed yet.
+	draining map[*pooledTransport]struct{}
+}
+
+// pooledTransport is a round tripper of the transportPool, along with the cancellation of its DNS resolver,
+// and the count of its open connections.
+type pooledTransport struct {
+	roundTripper http.RoundTripper
+	stop         context.CancelFunc
+	conns        *connCounter
+}
+
+func (t *pooledTransport) closeIdleConnections() {
+	if idler, ok := t.roundTripper.(closeIdler); ok {
+		idler.CloseIdleConnections()
+	}
+}
+
+func newTransportPool(conf *static.ServersTransport, metricsRegistry metrics.Registry) *transportPool {
+	pool := &transportPool{
+		conf:            conf,
+		metricsRegistry: metricsRegistry,
+		transports:      make(map[string]*pooledTransport),
+		draining:        make(map[*pooledTransport]struct{}),
+	}
+
+	if metricsRegistry != nil {
+		pool.drainingConnsGauge = metricsRegistry.ServiceDrainingConnsGauge()
+	}
+
+	return pool
+}
+
+// transportKey identifies the round tripper of the given servers TLS options in the transportPool.
+func transportKey(serversTLS *dynamic.ServersTLS) string {
+	pins := make([]string, len(serversTLS.PinnedPublicKeys))
+	copy(pins, serversTLS.PinnedPublicKeys)
+	sort.Strings(pins)
+
+	return serversTLS.ServerName + "|" + strings.Join(pins, ",")
+}
+
+func (p *transportPool) get(serversTLS *dynamic.ServersTLS) (http.RoundTripper, error) {
+	key := transportKey(serversTLS)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if transport, ok := p.transports[key]; ok {
+		return transport.roundTripper, nil
+	}
+
+	conf := &static.ServersTransport{}
+	if p.conf != nil {
+		*conf = *p.conf
+	}
+
+	if serversTLS.ServerName != "" {
+		conf.ServerName = serversTLS.ServerName
+	}
+
+	if len(serversTLS.PinnedPublicKeys) > 0 {
+		conf.PinnedPublicKeys = serversTLS.PinnedPublicKeys
+	}
+
+	conns := &connCounter{}
+	roundTripper, resolver, err := buildRoundTripper(conf, p.metricsRegistry, conns)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if resolver != nil {
+		safe.Go(func() {
+			resolver.run(ctx)
+		})
+	}
+
+	p.transports[key] = &pooledTransport{roundTripper: roundTripper, stop: cancel, conns: conns}
+	return roundTripper, nil
+}
+
+// retain removes from the pool the round trippers not used by the current configuration,
+// stopping their DNS resolver and draining their connections.
+func (p *transportPool) retain(keys map[string]struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, transport := range p.transports {
+		if _, ok := keys[key]; ok {
+			continue
+		}
+
+		delete(p.transports, key)
+		p.drain(transport)
+	}
+}
+
+func setupDefaultRoundTripper(conf *static.ServersTransport, metricsRegistry metrics.Registry, routinesPool *safe.Pool) http.RoundTripper {
+	transport, err := createRoundtripper(conf, metricsRegistry, routinesPool)
 	if err != nil {
 		log.WithoutContext().Errorf("Could not configure HTTP Transport, fallbacking on default transport: %v", err)
 		return http.DefaultTransport
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"container/list"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// hostPlaceholder is replaced, in the server name of a serversTransport, by the host of each request.
+const hostPlaceholder = "{host}"
+
+// maxServerNames is the maximum number of round trippers kept by a serverNameRoundTripper,
+// as the hosts of the requests are chosen by the clients.
+const maxServerNames = 100
+
+// serverNameRoundTripper sends each request with a server name templated from its host.
+// The connections of a round tripper are pooled by server address only,
+// so a round tripper is created for each server name, and the least recently used ones are dropped.
+type serverNameRoundTripper struct {
+	template        string
+	newRoundTripper func(serverName string) (http.RoundTripper, error)
+
+	mu            sync.Mutex
+	roundTrippers map[string]*list.Element
+	lru           *list.List
+}
+
+type serverNameEntry struct {
+	serverName   string
+	roundTripper http.RoundTripper
+}
+
+func newServerNameRoundTripper(template string, newRoundTripper func(serverName string) (http.RoundTripper, error)) *serverNameRoundTripper {
+	return &serverNameRoundTripper{
+		template:        template,
+		newRoundTripper: newRoundTripper,
+		roundTrippers:   make(map[string]*list.Element),
+		lru:             list.New(),
+	}
+}
+
+func (s *serverNameRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	roundTripper, err := s.get(strings.ReplaceAll(s.template, hostPlaceholder, host))
+	if err != nil {
+		return nil, err
+	}
+
+	return roundTripper.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the round trippers of all the server names.
+func (s *serverNameRoundTripper) CloseIdleConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for elt := s.lru.Front(); elt != nil; elt = elt.Next() {
+		closeIdleConnections(elt.Value.(*serverNameEntry).roundTripper)
+	}
+}
+
+func (s *serverNameRoundTripper) get(serverName string) (http.RoundTripper, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elt, ok := s.roundTrippers[serverName]; ok {
+		s.lru.MoveToFront(elt)
+		return elt.Value.(*serverNameEntry).roundTripper, nil
+	}
+
+	roundTripper, err := s.newRoundTripper(serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.roundTrippers[serverName] = s.lru.PushFront(&serverNameEntry{serverName: serverName, roundTripper: roundTripper})
+
+	// The requests in flight on a dropped round tripper still complete, its remaining connections being closed by the idle timeout.
+	for s.lru.Len() > maxServerNames {
+		oldest := s.lru.Remove(s.lru.Back()).(*serverNameEntry)
+		delete(s.roundTrippers, oldest.serverName)
+		closeIdleConnections(oldest.roundTripper)
+	}
+
+	return roundTripper, nil
+}
+
+func closeIdleConnections(roundTripper http.RoundTripper) {
+	if idler, ok := roundTripper.(closeIdler); ok {
+		idler.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type serverNameTransport struct {
+	serverName string
+	closed     bool
+}
+
+func (t *serverNameTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"X-Server-Name": {t.serverName}}}, nil
+}
+
+func (t *serverNameTransport) CloseIdleConnections() {
+	t.closed = true
+}
+
+func TestServerNameRoundTripper(t *testing.T) {
+	transports := make(map[string]*serverNameTransport)
+	roundTripper := newServerNameRoundTripper("{host}.internal", func(serverName string) (http.RoundTripper, error) {
+		transport := &serverNameTransport{serverName: serverName}
+		transports[serverName] = transport
+		return transport, nil
+	})
+
+	serverName := func(host string) string {
+		req := httptest.NewRequest(http.MethodGet, "http://10.0.0.1:8080/", nil)
+		req.Host = host
+
+		resp, err := roundTripper.RoundTrip(req)
+		require.NoError(t, err)
+
+		return resp.Header.Get("X-Server-Name")
+	}
+
+	assert.Equal(t, "foo.internal", serverName("foo:8443"))
+	assert.Equal(t, "bar.internal", serverName("bar"))
+	assert.Len(t, transports, 2)
+
+	// The round tripper of a server name is reused.
+	assert.Equal(t, "foo.internal", serverName("foo"))
+	assert.Len(t, transports, 2)
+
+	// The least recently used round trippers are dropped, and their idle connections closed.
+	for i := 0; i < maxServerNames-1; i++ {
+		serverName(fmt.Sprintf("host%d", i))
+	}
+
+	assert.True(t, transports["bar.internal"].closed)
+	assert.False(t, transports["foo.internal"].closed)
+	assert.Len(t, roundTripper.roundTrippers, maxServerNames)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/healthcheck"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+const defaultServerOverrideHeader = "X-Traefik-Server"
+
+// serverOverride forwards the requests with a valid server override header straight to the server it names,
+// and the other ones to the load-balancer.
+// The header value is "<server URL>;<expiration Unix time>;<signature>",
+// where the signature is the base64url encoded HMAC-SHA256 of "<server URL>;<expiration Unix time>".
+type serverOverride struct {
+	healthcheck.BalancerHandler
+	serviceName string
+	headerName  string
+	secret      []byte
+	// fwd forwards the requests to the server of their URL.
+	fwd     http.Handler
+	counter gokitmetrics.Counter // can be nil
+}
+
+func newServerOverride(serviceName string, config *dynamic.ServerOverride, lb healthcheck.BalancerHandler, fwd http.Handler, counter gokitmetrics.Counter) (*serverOverride, error) {
+	if config.Secret == "" {
+		return nil, errors.New("a secret is required for the server override")
+	}
+
+	headerName := config.HeaderName
+	if headerName == "" {
+		headerName = defaultServerOverrideHeader
+	}
+
+	return &serverOverride{
+		BalancerHandler: lb,
+		serviceName:     serviceName,
+		headerName:      http.CanonicalHeaderKey(headerName),
+		secret:          []byte(config.Secret),
+		fwd:             fwd,
+		counter:         counter,
+	}, nil
+}
+
+func (s *serverOverride) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	value := req.Header.Get(s.headerName)
+	if value == "" {
+		s.BalancerHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	// The header is meant for Traefik only.
+	req.Header.Del(s.headerName)
+
+	server, err := s.server(value, time.Now())
+	if err != nil {
+		log.FromContext(req.Context()).Debugf("Ignoring the server override header of service %s: %v", s.serviceName, err)
+		s.BalancerHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.ServerOverride] = true
+	}
+
+	if s.counter != nil {
+		s.counter.With("service", s.serviceName, "url", server.String()).Add(1)
+	}
+
+	// Like the load-balancer, works on a copy of the request with the URL of the server.
+	outReq := new(http.Request)
+	*outReq = *req
+	outReq.URL = server
+
+	s.fwd.ServeHTTP(rw, outReq)
+}
+
+// server checks the signature and the expiration time of the header value,
+// and returns a copy of the URL of the server it names, which must be a server of the load-balancer.
+func (s *serverOverride) server(value string, now time.Time) (*url.URL, error) {
+	parts := strings.Split(value, ";")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed header value")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	if !hmac.Equal(signature, signServerOverride(s.secret, parts[0], parts[1])) {
+		return nil, errors.New("invalid signature")
+	}
+
+	expiration, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiration time: %w", err)
+	}
+
+	if now.Unix() >= expiration {
+		return nil, errors.New("expired header value")
+	}
+
+	target, err := url.Parse(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+
+	for _, server := range s.Servers() {
+		if server.String() == target.String() {
+			u := *server
+			return &u, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown server %s", target)
+}
+
+// signServerOverride returns the HMAC-SHA256 of the server URL and the expiration time of a header value.
+func signServerOverride(secret []byte, serverURL, expiration string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(serverURL + ";" + expiration))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestServerOverride(t *testing.T) {
+	fwd := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Server", req.URL.String())
+		rw.Header().Set("X-Override", req.Header.Get("X-Traefik-Server"))
+	})
+
+	lb, err := roundrobin.New(fwd)
+	require.NoError(t, err)
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://10.0.0.1:80")))
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://10.0.0.2:80")))
+
+	override, err := newServerOverride("foo", &dynamic.ServerOverride{Secret: "secret"}, lb, fwd, nil)
+	require.NoError(t, err)
+
+	sign := func(secret, server string, expiration time.Time) string {
+		exp := strconv.FormatInt(expiration.Unix(), 10)
+		signature := signServerOverride([]byte(secret), server, exp)
+		return server + ";" + exp + ";" + base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	later := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		desc           string
+		header         string
+		expectedServer string
+	}{
+		{
+			desc: "no header",
+		},
+		{
+			desc:           "valid header",
+			header:         sign("secret", "http://10.0.0.2:80", later),
+			expectedServer: "http://10.0.0.2:80",
+		},
+		{
+			desc:   "expired header",
+			header: sign("secret", "http://10.0.0.2:80", time.Now().Add(-time.Minute)),
+		},
+		{
+			desc:   "other secret",
+			header: sign("other", "http://10.0.0.2:80", later),
+		},
+		{
+			desc:   "unknown server",
+			header: sign("secret", "http://10.0.0.3:80", later),
+		},
+		{
+			desc:   "malformed header",
+			header: "http://10.0.0.2:80",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			// The subtests are not run in parallel, as they share the round robin of the load-balancer.
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			if test.header != "" {
+				req.Header.Set("X-Traefik-Server", test.header)
+			}
+
+			rw := httptest.NewRecorder()
+			override.ServeHTTP(rw, req)
+
+			assert.Empty(t, rw.Header().Get("X-Override"))
+			if test.expectedServer != "" {
+				assert.Equal(t, test.expectedServer, rw.Header().Get("X-Server"))
+			} else {
+				assert.NotEmpty(t, rw.Header().Get("X-Server"))
+			}
+		})
+	}
+}
+
+func TestNewServerOverride_missingSecret(t *testing.T) {
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	_, err = newServerOverride("foo", &dynamic.ServerOverride{HeaderName: "X-Server"}, lb, http.NotFoundHandler(), nil)
+	assert.Error(t, err)
+}
@@ -17,14 +17,22 @@ import (
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
+	"github.com/containous/traefik/v2/pkg/middlewares/debugheaders"
 	"github.com/containous/traefik/v2/pkg/middlewares/emptybackendhandler"
 	metricsMiddle "github.com/containous/traefik/v2/pkg/middlewares/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares/pipelining"
+	"github.com/containous/traefik/v2/pkg/middlewares/shutdown"
 	"github.com/containous/traefik/v2/pkg/safe"
 	"github.com/containous/traefik/v2/pkg/server/cookie"
 	"github.com/containous/traefik/v2/pkg/server/provider"
+	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/hashring"
 	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/mirror"
+	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/p2c"
 	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/wrr"
+	"github.com/containous/traefik/v2/pkg/server/service/redirect"
+	"github.com/containous/traefik/v2/pkg/server/service/static"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/vulcand/oxy/roundrobin"
 )
 
@@ -35,6 +43,9 @@ const (
 
 const defaultMaxBodySize int64 = -1
 
+// defaultStreamingMaxLag is how many bytes of the body a mirror can lag behind the main request, when not configured.
+const defaultStreamingMaxLag int64 = 1024 * 1024
+
 // NewManager creates a new Manager.
 func NewManager(configs map[string]*runtime.ServiceInfo, defaultRoundTripper http.RoundTripper, metricsRegistry metrics.Registry, routinePool *safe.Pool) *Manager {
 	return &Manager{
@@ -53,12 +64,22 @@ type Manager struct {
 	metricsRegistry     metrics.Registry
 	bufferPool          httputil.BufferPool
 	defaultRoundTripper http.RoundTripper
+	// transports provides the round trippers of the services with servers TLS options.
+	transports *transportPool
+	// transportKeys are the keys of the round trippers of the transports used by the configuration.
+	transportKeys map[string]struct{}
 	// balancers is the map of all Balancers, keyed by service name.
 	// There is one Balancer per service handler, and there is one service handler per reference to a service
 	// (e.g. if 2 routers refer to the same service name, 2 service handlers are created),
 	// which is why there is not just one Balancer per service name.
 	balancers map[string]healthcheck.Balancers
 	configs   map[string]*runtime.ServiceInfo
+	// dnsExpanders refresh the servers of the load-balancers expanding the DNS answers.
+	dnsExpanders []*dnsExpander
+	// dnsRefresher runs the refresh of the dnsExpanders, and stops the one of the previous configuration.
+	dnsRefresher *dnsRefresher
+	// shutdownCoordinator tells the services with a shutdown grace period when the shutdown started.
+	shutdownCoordinator *shutdown.Coordinator
 }
 
 // BuildHTTP Creates a http.Handler for a service configuration.
@@ -110,6 +131,20 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string, respons
 			conf.AddError(err, true)
 			return nil, err
 		}
+	case conf.Static != nil:
+		var err error
+		lb, err = static.New(*conf.Static)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+	case conf.Redirect != nil:
+		var err error
+		lb, err = redirect.New(*conf.Redirect)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
 	default:
 		sErr := fmt.Errorf("the service %q does not have any type defined", serviceName)
 		conf.AddError(sErr, true)
@@ -130,6 +165,15 @@ func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.M
 		maxBodySize = *config.MaxBodySize
 	}
 	handler := mirror.New(serviceHandler, m.routinePool, maxBodySize)
+
+	if config.Streaming != nil {
+		maxLag := config.Streaming.MaxLag
+		if maxLag <= 0 {
+			maxLag = defaultStreamingMaxLag
+		}
+		handler.SetStreaming(maxLag)
+	}
+
 	for _, mirrorConfig := range config.Mirrors {
 		mirrorHandler, err := m.BuildHTTP(ctx, mirrorConfig.Name, responseModifier)
 		if err != nil {
@@ -151,6 +195,15 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 	}
 
 	balancer := wrr.New(config.Sticky)
+	if config.Sticky != nil && config.Sticky.Cookie != nil && config.Sticky.Cookie.Protection != nil {
+		codec, err := cookie.NewCodec(config.Sticky.Cookie.Name, config.Sticky.Cookie.Protection)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring the sticky cookie of service %s: %w", serviceName, err)
+		}
+
+		balancer.SetCookieCodec(codec)
+	}
+
 	for _, service := range config.Services {
 		serviceHandler, err := m.BuildHTTP(ctx, service.Name, responseModifier)
 		if err != nil {
@@ -173,7 +226,30 @@ func (m *Manager) getLoadBalancerServiceHandler(
 		service.PassHostHeader = &defaultPassHostHeader
 	}
 
-	fwd, err := buildProxy(service.PassHostHeader, service.ResponseForwarding, m.defaultRoundTripper, m.bufferPool, responseModifier)
+	// The Host header is set from the hostname of the servers by the DNS expansion.
+	passHostHeader := passHost(service) || service.DNSExpansion != nil
+
+	roundTripper, err := m.getRoundTripper(service)
+	if err != nil {
+		return nil, err
+	}
+
+	var proxyOpts []proxyOption
+	if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() && m.metricsRegistry.ServiceProxyErrorsCounter() != nil {
+		proxyOpts = append(proxyOpts, withErrorsCounter(m.metricsRegistry.ServiceProxyErrorsCounter().With("service", serviceName)))
+	}
+
+	fwd, err := buildProxy(&passHostHeader, service.ResponseForwarding, roundTripper, m.bufferPool, responseModifier, proxyOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	fwd, err = newHostHeader(fwd, service.HostHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	fwd, err = newHeaderPropagation(fwd, service.HeaderPropagation)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +262,11 @@ func (m *Manager) getLoadBalancerServiceHandler(
 		chain = chain.Append(metricsMiddle.WrapServiceHandler(ctx, m.metricsRegistry, serviceName))
 	}
 
-	handler, err := chain.Append(alHandler).Then(pipelining.New(ctx, fwd, "pipelining"))
+	if gracePeriod := time.Duration(service.ShutdownGracePeriod); gracePeriod > 0 && m.shutdownCoordinator != nil {
+		chain = chain.Append(shutdown.WrapServiceHandler(ctx, m.shutdownCoordinator, serviceName, gracePeriod))
+	}
+
+	handler, err := chain.Append(alHandler).Then(debugheaders.WrapServerHandler(pipelining.New(ctx, fwd, "pipelining")))
 	if err != nil {
 		return nil, err
 	}
@@ -218,7 +298,14 @@ func (m *Manager) LaunchHealthCheck() {
 		if hcOpts := buildHealthCheckOptions(ctx, balancers, serviceName, service.HealthCheck); hcOpts != nil {
 			log.FromContext(ctx).Debugf("Setting up healthcheck for service %s with %s", serviceName, *hcOpts)
 
-			hcOpts.Transport = m.defaultRoundTripper
+			roundTripper, err := m.getRoundTripper(service)
+			if err != nil {
+				log.FromContext(ctx).Errorf("Error while getting the round tripper of the health check: %v", err)
+				continue
+			}
+
+			hcOpts.Transport = roundTripper
+			hcOpts.Servers = buildServersHealthCheckOptions(service.Servers)
 			backendHealthCheck = healthcheck.NewBackendConfig(*hcOpts, serviceName)
 		}
 
@@ -229,6 +316,40 @@ func (m *Manager) LaunchHealthCheck() {
 
 	// FIXME metrics and context
 	healthcheck.GetHealthCheck().SetBackendsConfiguration(context.Background(), backendConfigs)
+
+	if m.transports != nil {
+		m.transports.retain(m.transportKeys)
+	}
+
+	if len(m.dnsExpanders) > 0 {
+		m.getDNSRefresher().launch(m.dnsExpanders)
+	}
+}
+
+func (m *Manager) getDNSRefresher() *dnsRefresher {
+	if m.dnsRefresher == nil {
+		m.dnsRefresher = newDNSRefresher()
+	}
+
+	return m.dnsRefresher
+}
+
+func (m *Manager) getRoundTripper(service *dynamic.ServersLoadBalancer) (http.RoundTripper, error) {
+	if service.ServersTLS == nil {
+		return m.defaultRoundTripper, nil
+	}
+
+	if m.transports == nil {
+		m.transports = newTransportPool(nil, m.metricsRegistry)
+	}
+
+	if m.transportKeys == nil {
+		m.transportKeys = make(map[string]struct{})
+	}
+
+	m.transportKeys[transportKey(service.ServersTLS)] = struct{}{}
+
+	return m.transports.get(service.ServersTLS)
 }
 
 func buildHealthCheckOptions(ctx context.Context, lb healthcheck.Balancer, backend string, hc *dynamic.HealthCheck) *healthcheck.Options {
@@ -286,6 +407,28 @@ func buildHealthCheckOptions(ctx context.Context, lb healthcheck.Balancer, backe
 	}
 }
 
+// buildServersHealthCheckOptions returns the health check endpoints overridden by the servers, keyed by server URL.
+func buildServersHealthCheckOptions(servers []dynamic.Server) map[string]healthcheck.ServerOptions {
+	options := make(map[string]healthcheck.ServerOptions)
+	for _, server := range servers {
+		if server.HealthCheck == nil {
+			continue
+		}
+
+		u, err := url.Parse(server.URL)
+		if err != nil {
+			continue
+		}
+
+		options[u.String()] = healthcheck.ServerOptions{
+			Path: server.HealthCheck.Path,
+			Port: server.HealthCheck.Port,
+		}
+	}
+
+	return options
+}
+
 func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer, fwd http.Handler) (healthcheck.BalancerHandler, error) {
 	logger := log.FromContext(ctx)
 	logger.Debug("Creating load-balancer")
@@ -293,9 +436,18 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 	var options []roundrobin.LBOption
 
 	var cookieName string
+	var cookieCodec *cookie.Codec
 	if service.Sticky != nil && service.Sticky.Cookie != nil {
 		cookieName = cookie.GetName(service.Sticky.Cookie.Name, serviceName)
 
+		if service.Sticky.Cookie.Protection != nil {
+			var err error
+			cookieCodec, err = cookie.NewCodec(cookieName, service.Sticky.Cookie.Protection)
+			if err != nil {
+				return nil, fmt.Errorf("error configuring the sticky cookie of service %s: %w", serviceName, err)
+			}
+		}
+
 		opts := roundrobin.CookieOptions{
 			HTTPOnly: service.Sticky.Cookie.HTTPOnly,
 			Secure:   service.Sticky.Cookie.Secure,
@@ -307,17 +459,119 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 		logger.Debugf("Sticky session cookie name: %v", cookieName)
 	}
 
-	lb, err := roundrobin.New(fwd, options...)
-	if err != nil {
-		return nil, err
+	var expander *dnsExpander
+	if service.DNSExpansion != nil {
+		var err error
+		expander, err = newDNSExpander(serviceName, service.Servers, service.DNSExpansion, m.getDNSRefresher())
+		if err != nil {
+			return nil, fmt.Errorf("error configuring the DNS expansion of service %s: %w", serviceName, err)
+		}
+
+		if !passHost(service) {
+			fwd = expander.originalHost(fwd)
+		}
+	}
+
+	var detector *outlierDetector
+	if service.OutlierDetection != nil {
+		var err error
+		detector, err = newOutlierDetector(ctx, serviceName, service.OutlierDetection)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring the outlier detection of service %s: %w", serviceName, err)
+		}
+
+		fwd = detector.observe(fwd)
+	}
+
+	// The per-server circuit breakers in front of the service send the requests of an isolated server to its other servers.
+	var lbsu *healthcheck.LbStatusUpdater
+	fwd = circuitbreaker.WrapServers(fwd, func() []*url.URL { return lbsu.Servers() })
+
+	var lb healthcheck.BalancerHandler
+	switch {
+	case service.ConsistentHash != nil:
+		if service.Sticky != nil {
+			return nil, fmt.Errorf("sticky sessions cannot be used with consistent hashing for service %s", serviceName)
+		}
+
+		if service.P2C != nil {
+			return nil, fmt.Errorf("power of two choices cannot be used with consistent hashing for service %s", serviceName)
+		}
+
+		var err error
+		lb, err = hashring.New(fwd, *service.ConsistentHash)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring the consistent hashing of service %s: %w", serviceName, err)
+		}
+	case service.P2C != nil:
+		if service.Sticky != nil {
+			return nil, fmt.Errorf("sticky sessions cannot be used with power of two choices for service %s", serviceName)
+		}
+
+		var err error
+		lb, err = p2c.New(fwd, *service.P2C)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring the power of two choices of service %s: %w", serviceName, err)
+		}
+	default:
+		var err error
+		lb, err = roundrobin.New(fwd, options...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if service.SlowStart != nil {
+		if service.ConsistentHash != nil || service.P2C != nil {
+			return nil, fmt.Errorf("slow start can only be used with the round robin for service %s", serviceName)
+		}
+
+		var err error
+		lb, err = newSlowStarter(ctx, lb, service.SlowStart)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring the slow start of service %s: %w", serviceName, err)
+		}
+	}
+
+	lbsu = healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName])
+
+	if detector != nil {
+		detector.lb = lbsu
+	}
+
+	var balancer healthcheck.BalancerHandler = lbsu
+	if cookieCodec != nil {
+		balancer = newProtectedStickyCookie(cookieName, cookieCodec, balancer)
+	}
+
+	if service.ServerOverride != nil {
+		var counter gokitmetrics.Counter
+		if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+			counter = m.metricsRegistry.ServiceServerOverrideReqsCounter()
+		}
+
+		var err error
+		balancer, err = newServerOverride(serviceName, service.ServerOverride, balancer, fwd, counter)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring the server override of service %s: %w", serviceName, err)
+		}
+	}
+
+	if expander != nil {
+		expander.lb = lbsu
+		expander.serviceInfo = m.configs[serviceName]
+		// The hostnames are resolved in the background once the configuration is applied.
+		expander.seed(ctx)
+
+		m.dnsExpanders = append(m.dnsExpanders, expander)
+		return &expandedBalancer{BalancerHandler: balancer, expander: expander}, nil
 	}
 
-	lbsu := healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName])
 	if err := m.upsertServers(ctx, lbsu, service.Servers); err != nil {
 		return nil, fmt.Errorf("error configuring load balancer for service %s: %w", serviceName, err)
 	}
 
-	return lbsu, nil
+	return balancer, nil
 }
 
 func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHandler, servers []dynamic.Server) error {
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/server/provider"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
 	"github.com/stretchr/testify/assert"
@@ -60,6 +63,85 @@ func TestGetLoadBalancer(t *testing.T) {
 			fwd:         &MockForwarder{},
 			expectError: false,
 		},
+		{
+			desc:        "Succeeds when consistentHash is set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				ConsistentHash: &dynamic.ConsistentHash{Header: "X-User"},
+				Servers:        []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails when consistentHash and sticky are set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Sticky:         &dynamic.Sticky{Cookie: &dynamic.Cookie{}},
+				ConsistentHash: &dynamic.ConsistentHash{},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Succeeds when p2c is set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				P2C:     &dynamic.P2C{EWMA: true},
+				Servers: []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Succeeds when slowStart is set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				SlowStart: &dynamic.SlowStart{Duration: "30s", InitialPercent: 10},
+				Servers:   []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails when slowStart and p2c are set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				SlowStart: &dynamic.SlowStart{Duration: "30s"},
+				P2C:       &dynamic.P2C{},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Fails when p2c and consistentHash are set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				P2C:            &dynamic.P2C{},
+				ConsistentHash: &dynamic.ConsistentHash{},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Succeeds when outlierDetection is set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				OutlierDetection: &dynamic.OutlierDetection{},
+				Servers:          []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails when outlierDetection is invalid",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				OutlierDetection: &dynamic.OutlierDetection{BaseEjectionTime: "foo"},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -284,6 +366,112 @@ func TestGetLoadBalancerServiceHandler(t *testing.T) {
 	}
 }
 
+func TestGetLoadBalancerServiceHandler_serversTLS(t *testing.T) {
+	serverNames := make(chan string, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		serverNames <- req.TLS.ServerName
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	hash := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+	pin := base64.StdEncoding.EncodeToString(hash[:])
+
+	otherHash := sha256.Sum256([]byte("other"))
+	otherPin := base64.StdEncoding.EncodeToString(otherHash[:])
+
+	sm := NewManager(nil, http.DefaultTransport, nil, nil)
+	sm.transports = newTransportPool(&static.ServersTransport{InsecureSkipVerify: true}, nil)
+
+	testCases := []struct {
+		desc               string
+		serversTLS         *dynamic.ServersTLS
+		expectedError      bool
+		expectedStatusCode int
+		expectedServerName string
+	}{
+		{
+			desc:               "server name",
+			serversTLS:         &dynamic.ServersTLS{ServerName: "backend.internal"},
+			expectedStatusCode: http.StatusOK,
+			expectedServerName: "backend.internal",
+		},
+		{
+			desc:               "server name templated from the request host",
+			serversTLS:         &dynamic.ServersTLS{ServerName: "{host}.internal"},
+			expectedStatusCode: http.StatusOK,
+			expectedServerName: "callme.internal",
+		},
+		{
+			desc:               "matching pinned public key",
+			serversTLS:         &dynamic.ServersTLS{PinnedPublicKeys: []string{otherPin, pin}},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "no matching pinned public key",
+			serversTLS:         &dynamic.ServersTLS{PinnedPublicKeys: []string{otherPin}},
+			expectedStatusCode: http.StatusBadGateway,
+		},
+		{
+			desc:          "invalid pinned public key",
+			serversTLS:    &dynamic.ServersTLS{PinnedPublicKeys: []string{"foobar"}},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			service := &dynamic.ServersLoadBalancer{
+				Servers:    []dynamic.Server{{URL: server.URL}},
+				ServersTLS: test.serversTLS,
+			}
+
+			handler, err := sm.getLoadBalancerServiceHandler(context.Background(), "foobar", service, nil)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://callme", nil))
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+
+			if test.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, test.expectedServerName, <-serverNames)
+			}
+		})
+	}
+}
+
+func TestTransportPool_retain(t *testing.T) {
+	pool := newTransportPool(&static.ServersTransport{}, nil)
+
+	kept := &dynamic.ServersTLS{ServerName: "kept.internal"}
+	removed := &dynamic.ServersTLS{ServerName: "removed.internal"}
+
+	keptRoundTripper, err := pool.get(kept)
+	require.NoError(t, err)
+
+	_, err = pool.get(removed)
+	require.NoError(t, err)
+
+	pool.retain(map[string]struct{}{transportKey(kept): {}})
+
+	require.Len(t, pool.transports, 1)
+	assert.Contains(t, pool.transports, transportKey(kept))
+
+	roundTripper, err := pool.get(kept)
+	require.NoError(t, err)
+	assert.Same(t, keptRoundTripper, roundTripper)
+
+	pool.retain(nil)
+
+	assert.Empty(t, pool.transports)
+}
+
 func TestManager_Build(t *testing.T) {
 	testCases := []struct {
 		desc         string
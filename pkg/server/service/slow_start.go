@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/healthcheck"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+const (
+	// slowStartWeight is the full weight of the servers with a slow start,
+	// so that a ramping weight can be a small fraction of it.
+	slowStartWeight = 100
+	// slowStartSteps is the number of increases of the weight of a server during its ramp.
+	slowStartSteps = 10
+)
+
+// slowStarter ramps up linearly the weight of the servers returning to the load-balancer,
+// after they were removed by the health check or the outlier detection.
+type slowStarter struct {
+	healthcheck.BalancerHandler
+
+	ctx      context.Context
+	duration time.Duration
+	initial  int
+
+	mu sync.Mutex
+	// removed are the servers removed from the load-balancer, whose return starts a ramp.
+	removed map[string]struct{}
+	// generations are incremented on each removal of a server, which stops its ongoing ramp.
+	generations map[string]int
+	// afterFunc schedules the steps of the ramps, and is replaced in the tests.
+	afterFunc func(d time.Duration, f func())
+}
+
+func newSlowStarter(ctx context.Context, lb healthcheck.BalancerHandler, config *dynamic.SlowStart) (*slowStarter, error) {
+	duration, err := time.ParseDuration(config.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration: %w", err)
+	}
+
+	if duration <= 0 {
+		return nil, fmt.Errorf("duration must be greater than zero: %s", config.Duration)
+	}
+
+	if config.InitialPercent < 0 || config.InitialPercent > 100 {
+		return nil, fmt.Errorf("initial percent must be between 0 and 100: %d", config.InitialPercent)
+	}
+
+	initial := slowStartWeight * config.InitialPercent / 100
+	if initial == 0 {
+		initial = 1
+	}
+
+	return &slowStarter{
+		BalancerHandler: lb,
+		ctx:             ctx,
+		duration:        duration,
+		initial:         initial,
+		removed:         make(map[string]struct{}),
+		generations:     make(map[string]int),
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}, nil
+}
+
+// RemoveServer removes the server from the load-balancer, and stops its ramp.
+func (s *slowStarter) RemoveServer(u *url.URL) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.BalancerHandler.RemoveServer(u); err != nil {
+		return err
+	}
+
+	s.removed[u.String()] = struct{}{}
+	s.generations[u.String()]++
+
+	return nil
+}
+
+// UpsertServer adds the server to the load-balancer, with its full weight if it is new,
+// or with the initial weight of its ramp if it returns after a removal.
+func (s *slowStarter) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := u.String()
+	if _, ok := s.removed[key]; !ok {
+		return s.BalancerHandler.UpsertServer(u, append(options, roundrobin.Weight(slowStartWeight))...)
+	}
+
+	if err := s.BalancerHandler.UpsertServer(u, append(options, roundrobin.Weight(s.initial))...); err != nil {
+		return err
+	}
+
+	delete(s.removed, key)
+	s.schedule(u, s.generations[key], 1)
+
+	return nil
+}
+
+// schedule plans the given step of the ramp of the server, s.mu being held.
+func (s *slowStarter) schedule(u *url.URL, generation, step int) {
+	s.afterFunc(s.duration/slowStartSteps, func() {
+		s.ramp(u, generation, step)
+	})
+}
+
+// ramp increases the weight of the server, unless it was removed since the start of the ramp.
+func (s *slowStarter) ramp(u *url.URL, generation, step int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.generations[u.String()] != generation {
+		return
+	}
+
+	weight := s.initial + (slowStartWeight-s.initial)*step/slowStartSteps
+	if err := s.BalancerHandler.UpsertServer(u, roundrobin.Weight(weight)); err != nil {
+		log.FromContext(s.ctx).WithField(log.ServerName, u.String()).Errorf("Slow start: unable to update the weight of the server: %v", err)
+		return
+	}
+
+	if step < slowStartSteps {
+		s.schedule(u, generation, step+1)
+	}
+}
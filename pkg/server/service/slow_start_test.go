@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestNewSlowStarter(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.SlowStart
+		expectedError bool
+	}{
+		{
+			desc:   "valid configuration",
+			config: dynamic.SlowStart{Duration: "30s", InitialPercent: 10},
+		},
+		{
+			desc:          "missing duration",
+			config:        dynamic.SlowStart{InitialPercent: 10},
+			expectedError: true,
+		},
+		{
+			desc:          "negative duration",
+			config:        dynamic.SlowStart{Duration: "-30s"},
+			expectedError: true,
+		},
+		{
+			desc:          "initial percent above 100",
+			config:        dynamic.SlowStart{Duration: "30s", InitialPercent: 101},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			lb, err := roundrobin.New(http.NotFoundHandler())
+			require.NoError(t, err)
+
+			_, err = newSlowStarter(context.Background(), lb, &test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSlowStarter(t *testing.T) {
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	starter, err := newSlowStarter(context.Background(), lb, &dynamic.SlowStart{Duration: "10s", InitialPercent: 10})
+	require.NoError(t, err)
+
+	var scheduled []time.Duration
+	var steps []func()
+	starter.afterFunc = func(d time.Duration, f func()) {
+		scheduled = append(scheduled, d)
+		steps = append(steps, f)
+	}
+
+	u := testhelpers.MustParseURL("http://127.0.0.1:8001")
+
+	weight := func() int {
+		w, ok := lb.ServerWeight(u)
+		require.True(t, ok)
+		return w
+	}
+
+	// A new server gets its full weight.
+	require.NoError(t, starter.UpsertServer(u, roundrobin.Weight(1)))
+	assert.Equal(t, 100, weight())
+	assert.Empty(t, steps)
+
+	// A returning server ramps up.
+	require.NoError(t, starter.RemoveServer(u))
+	require.NoError(t, starter.UpsertServer(u, roundrobin.Weight(1)))
+	assert.Equal(t, 10, weight())
+
+	steps[0]()
+	assert.Equal(t, 19, weight())
+
+	for i := 1; i < 10; i++ {
+		steps[i]()
+	}
+	assert.Equal(t, 100, weight())
+	assert.Len(t, steps, 10)
+	assert.Equal(t, time.Second, scheduled[0])
+
+	// A removal stops the ongoing ramp.
+	require.NoError(t, starter.RemoveServer(u))
+	require.NoError(t, starter.UpsertServer(u, roundrobin.Weight(1)))
+	steps[10]()
+	require.NoError(t, starter.RemoveServer(u))
+	require.NoError(t, starter.UpsertServer(u, roundrobin.Weight(1)))
+
+	steps[11]()
+	assert.Equal(t, 10, weight())
+	assert.Len(t, steps, 13)
+}
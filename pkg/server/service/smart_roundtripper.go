@@ -36,3 +36,9 @@ func (m *smartRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 
 	return m.http2.RoundTrip(req)
 }
+
+// CloseIdleConnections closes the idle connections of both transports.
+func (m *smartRoundTripper) CloseIdleConnections() {
+	m.http2.CloseIdleConnections()
+	m.http.CloseIdleConnections()
+}
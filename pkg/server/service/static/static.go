@@ -0,0 +1,109 @@
+package static
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+)
+
+// Static is an http.Handler serving the files of a local directory.
+type Static struct {
+	root         http.FileSystem
+	indexFiles   []string
+	cacheControl string
+	spaFallback  string
+}
+
+// New returns a new instance of *Static.
+func New(config dynamic.StaticFiles) (*Static, error) {
+	if config.Root == "" {
+		return nil, errors.New("the root directory is required")
+	}
+
+	info, err := os.Stat(config.Root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("invalid root directory: %s is not a directory", config.Root)
+	}
+
+	indexFiles := config.IndexFiles
+	if len(indexFiles) == 0 {
+		indexFiles = []string{"index.html"}
+	}
+
+	return &Static{
+		root:         http.Dir(config.Root),
+		indexFiles:   indexFiles,
+		cacheControl: config.CacheControl,
+		spaFallback:  config.SPAFallback,
+	}, nil
+}
+
+func (s *Static) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		rw.Header().Set("Allow", "GET, HEAD")
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	file, info, ok := s.open(path.Clean("/" + req.URL.Path))
+	if !ok && s.spaFallback != "" {
+		file, info, ok = s.open(path.Clean("/" + s.spaFallback))
+	}
+	if !ok {
+		http.NotFound(rw, req)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	if s.cacheControl != "" {
+		rw.Header().Set("Cache-Control", s.cacheControl)
+	}
+
+	// ServeContent handles the Range and the conditional requests.
+	http.ServeContent(rw, req, info.Name(), info.ModTime(), file)
+}
+
+// open opens the regular file matching the name,
+// or one of the index files when the name matches a directory.
+func (s *Static) open(name string) (http.File, os.FileInfo, bool) {
+	file, info, ok := s.openFile(name)
+	if !ok || !info.IsDir() {
+		return file, info, ok
+	}
+	_ = file.Close()
+
+	for _, index := range s.indexFiles {
+		file, info, ok = s.openFile(path.Join(name, index))
+		if !ok {
+			continue
+		}
+		if !info.IsDir() {
+			return file, info, true
+		}
+		_ = file.Close()
+	}
+
+	return nil, nil, false
+}
+
+func (s *Static) openFile(name string) (http.File, os.FileInfo, bool) {
+	file, err := s.root.Open(name)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, false
+	}
+
+	return file, info, true
+}
@@ -0,0 +1,142 @@
+package static
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatic(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.StaticFiles
+		method         string
+		path           string
+		rangeHeader    string
+		expectedStatus int
+		expectedBody   string
+		expectedCache  string
+	}{
+		{
+			desc:           "serves a file",
+			method:         http.MethodGet,
+			path:           "/hello.txt",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "hello world",
+		},
+		{
+			desc:           "serves the index file of the root",
+			method:         http.MethodGet,
+			path:           "/",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "<h1>home</h1>",
+		},
+		{
+			desc:           "serves the index file of a directory",
+			method:         http.MethodGet,
+			path:           "/app/",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "<h1>app</h1>",
+		},
+		{
+			desc:           "does not list a directory without index file",
+			method:         http.MethodGet,
+			path:           "/app/docs/",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			desc:           "custom index files",
+			config:         dynamic.StaticFiles{IndexFiles: []string{"readme.txt"}},
+			method:         http.MethodGet,
+			path:           "/app/docs",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "docs",
+		},
+		{
+			desc:           "does not escape the root",
+			method:         http.MethodGet,
+			path:           "/../static.go",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			desc:           "serves a range",
+			method:         http.MethodGet,
+			path:           "/hello.txt",
+			rangeHeader:    "bytes=6-",
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "world",
+		},
+		{
+			desc:           "sets the cache control header",
+			config:         dynamic.StaticFiles{CacheControl: "max-age=3600"},
+			method:         http.MethodGet,
+			path:           "/hello.txt",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "hello world",
+			expectedCache:  "max-age=3600",
+		},
+		{
+			desc:           "serves the SPA fallback",
+			config:         dynamic.StaticFiles{SPAFallback: "/app/index.html"},
+			method:         http.MethodGet,
+			path:           "/app/users/42",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "<h1>app</h1>",
+		},
+		{
+			desc:           "not found without SPA fallback",
+			method:         http.MethodGet,
+			path:           "/app/users/42",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "404 page not found\n",
+		},
+		{
+			desc:           "method not allowed",
+			method:         http.MethodPost,
+			path:           "/hello.txt",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "Method Not Allowed\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.config.Root = "./fixtures"
+			handler, err := New(test.config)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(test.method, "http://localhost", nil)
+			req.URL.Path = test.path
+			if test.rangeHeader != "" {
+				req.Header.Set("Range", test.rangeHeader)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, test.expectedCache, recorder.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestNew_invalidRoot(t *testing.T) {
+	_, err := New(dynamic.StaticFiles{})
+	assert.Error(t, err)
+
+	_, err = New(dynamic.StaticFiles{Root: "./fixtures/hello.txt"})
+	assert.Error(t, err)
+
+	_, err = New(dynamic.StaticFiles{Root: "./fixtures/missing"})
+	assert.Error(t, err)
+}
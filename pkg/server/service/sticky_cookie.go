@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/healthcheck"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/server/cookie"
+)
+
+// protectedStickyCookie protects the sticky cookie of a load-balancer, which holds the URL of a server:
+// the cookie of the requests is decoded back to the URL before reaching the load-balancer,
+// and the cookie set by the load-balancer is encoded before reaching the client.
+type protectedStickyCookie struct {
+	healthcheck.BalancerHandler
+	name  string
+	codec *cookie.Codec
+}
+
+func newProtectedStickyCookie(name string, codec *cookie.Codec, lb healthcheck.BalancerHandler) *protectedStickyCookie {
+	return &protectedStickyCookie{
+		BalancerHandler: lb,
+		name:            name,
+		codec:           codec,
+	}
+}
+
+func (p *protectedStickyCookie) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	var servers []string
+	for _, u := range p.Servers() {
+		servers = append(servers, u.String())
+	}
+
+	if values, ok := req.Header["Cookie"]; ok {
+		// The request is not modified, as it can be sent again, by the retry middleware for instance.
+		outReq := new(http.Request)
+		*outReq = *req
+		outReq.Header = req.Header.Clone()
+
+		var decoded []string
+		for _, value := range values {
+			if value = p.decode(req, value, servers); value != "" {
+				decoded = append(decoded, value)
+			}
+		}
+
+		if len(decoded) > 0 {
+			outReq.Header["Cookie"] = decoded
+		} else {
+			outReq.Header.Del("Cookie")
+		}
+		req = outReq
+	}
+
+	p.BalancerHandler.ServeHTTP(&stickyCookieWriter{ResponseWriter: rw, name: p.name, codec: p.codec, servers: servers}, req)
+}
+
+// decode replaces the value of the sticky cookie in a Cookie header with the server URL it was encoded from,
+// and drops the invalid values, so that a new server is selected.
+func (p *protectedStickyCookie) decode(req *http.Request, header string, servers []string) string {
+	pairs := strings.Split(header, ";")
+
+	kept := pairs[:0]
+	for _, pair := range pairs {
+		name, value := cookiePair(pair)
+		if name != p.name {
+			kept = append(kept, pair)
+			continue
+		}
+
+		server, ok := p.codec.Decode(value, servers)
+		if !ok {
+			log.FromContext(req.Context()).Debugf("Ignoring the invalid value of the sticky cookie %s", p.name)
+			continue
+		}
+
+		kept = append(kept, " "+p.name+"="+server)
+	}
+
+	return strings.TrimSpace(strings.Join(kept, ";"))
+}
+
+// stickyCookieWriter encodes the value of the sticky cookie set by the load-balancer, before the headers are written.
+type stickyCookieWriter struct {
+	http.ResponseWriter
+	name    string
+	codec   *cookie.Codec
+	servers []string
+	encoded bool
+}
+
+func (s *stickyCookieWriter) WriteHeader(code int) {
+	s.encode()
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *stickyCookieWriter) Write(b []byte) (int, error) {
+	s.encode()
+	return s.ResponseWriter.Write(b)
+}
+
+func (s *stickyCookieWriter) encode() {
+	if s.encoded {
+		return
+	}
+	s.encoded = true
+
+	values := s.Header()["Set-Cookie"]
+	for i, value := range values {
+		pair := value
+		attributes := ""
+		if n := strings.Index(value, ";"); n >= 0 {
+			pair, attributes = value[:n], value[n:]
+		}
+
+		name, server := cookiePair(pair)
+		if name != s.name || !contains(s.servers, server) {
+			continue
+		}
+
+		encoded, err := s.codec.Encode(server)
+		if err != nil {
+			log.WithoutContext().Warnf("Error while protecting the sticky cookie %s: %v", s.name, err)
+			s.Header()["Set-Cookie"] = append(values[:i], values[i+1:]...)
+			return
+		}
+
+		values[i] = s.name + "=" + encoded + attributes
+		return
+	}
+}
+
+func (s *stickyCookieWriter) Flush() {
+	s.encode()
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (s *stickyCookieWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", s.ResponseWriter)
+	}
+
+	return hijacker.Hijack()
+}
+
+func (s *stickyCookieWriter) CloseNotify() <-chan bool {
+	if notifier, ok := s.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+
+	return make(chan bool)
+}
+
+// cookiePair returns the name and the value of a "name=value" cookie pair.
+func cookiePair(pair string) (string, string) {
+	pair = strings.TrimSpace(pair)
+
+	n := strings.Index(pair, "=")
+	if n < 0 {
+		return pair, ""
+	}
+
+	return pair[:n], pair[n+1:]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
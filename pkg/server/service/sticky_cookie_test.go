@@ -0,0 +1,73 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/server/cookie"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestProtectedStickyCookie(t *testing.T) {
+	fwd := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Server", req.URL.String())
+		rw.Header().Set("X-Cookie", req.Header.Get("Cookie"))
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	lb, err := roundrobin.New(fwd, roundrobin.EnableStickySession(roundrobin.NewStickySession("sticky")))
+	require.NoError(t, err)
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://10.0.0.1:80")))
+	require.NoError(t, lb.UpsertServer(testhelpers.MustParseURL("http://10.0.0.2:80")))
+
+	codec, err := cookie.NewCodec("sticky", &dynamic.CookieProtection{Keys: []string{"0123456789abcdef0123456789abcdef"}, Encrypt: true})
+	require.NoError(t, err)
+
+	handler := newProtectedStickyCookie("sticky", codec, lb)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.localhost/", nil))
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "sticky", cookies[0].Name)
+	assert.NotContains(t, cookies[0].Value, "10.0.0.")
+
+	server := recorder.Header().Get("X-Server")
+	require.NotEmpty(t, server)
+
+	// The requests with the protected cookie stick to the server, which receives its URL.
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "http://foo.localhost/", nil)
+		req.AddCookie(&http.Cookie{Name: "other", Value: "foo"})
+		req.AddCookie(cookies[0])
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, server, recorder.Header().Get("X-Server"))
+		assert.Equal(t, "other=foo; sticky="+server, recorder.Header().Get("X-Cookie"))
+		assert.Empty(t, recorder.Result().Cookies())
+
+		// The request itself is not modified.
+		assert.Equal(t, "other=foo; sticky="+cookies[0].Value, req.Header.Get("Cookie"))
+	}
+
+	// The requests with an unprotected cookie are balanced again.
+	req := httptest.NewRequest(http.MethodGet, "http://foo.localhost/", nil)
+	req.AddCookie(&http.Cookie{Name: "sticky", Value: server})
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Empty(t, recorder.Header().Get("X-Cookie"))
+
+	cookies = recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.NotContains(t, cookies[0].Value, "10.0.0.")
+}
@@ -5,23 +5,69 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"reflect"
 	"time"
 
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/healthcheck"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/server/provider"
 	"github.com/containous/traefik/v2/pkg/tcp"
 )
 
+// Balancing strategies of the TCP load-balancers.
+const (
+	strategyWRR       = "wrr"
+	strategyLeastConn = "leastconn"
+	strategySourceIP  = "sourceip"
+)
+
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+)
+
+// defaultMirrorBufferSize is the maximum number of bytes waiting to be sent to a mirror, when not configured.
+const defaultMirrorBufferSize = 64 * 1024
+
+// balancer is a TCP load-balancer.
+type balancer interface {
+	tcp.Handler
+	AddNamedServer(name string, serverHandler tcp.Handler, weight *int)
+	SetStatus(name string, up bool) error
+	SetMaxConnections(max int)
+	SetServerMaxConnections(name string, max int) error
+}
+
+// slowStarter is a TCP load-balancer ramping up the weight of the servers coming back up.
+type slowStarter interface {
+	SetSlowStart(duration time.Duration, initialPercent int) error
+}
+
+// statusNotifier is a TCP handler notifying when it goes down, because all its servers are down, or back up.
+type statusNotifier interface {
+	RegisterStatusUpdater(fn func(up bool))
+}
+
 // Manager is the TCPHandlers factory.
 type Manager struct {
 	configs map[string]*runtime.TCPServiceInfo
+	// balancers are the load-balancers of the services with a health check, keyed by service name.
+	balancers map[string]healthcheck.TCPBalancers
+	// servers are the addresses of the servers of the services with a health check, keyed by service name.
+	servers         map[string][]string
+	metricsRegistry metrics.Registry
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+func NewManager(conf *runtime.Configuration, metricsRegistry metrics.Registry) *Manager {
 	return &Manager{
-		configs: conf.TCPServices,
+		configs:         conf.TCPServices,
+		balancers:       make(map[string]healthcheck.TCPBalancers),
+		servers:         make(map[string][]string),
+		metricsRegistry: metricsRegistry,
 	}
 }
 
@@ -36,7 +82,14 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		return nil, fmt.Errorf("the service %q does not exist", serviceQualifiedName)
 	}
 
-	if conf.LoadBalancer != nil && conf.Weighted != nil {
+	value := reflect.ValueOf(*conf.TCPService)
+	var count int
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsNil() {
+			count++
+		}
+	}
+	if count > 1 {
 		err := errors.New("cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
 		conf.AddError(err, true)
 		return nil, err
@@ -45,7 +98,29 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	logger := log.FromContext(ctx)
 	switch {
 	case conf.LoadBalancer != nil:
-		loadBalancer := tcp.NewWRRLoadBalancer()
+		var loadBalancer balancer
+		switch conf.LoadBalancer.Strategy {
+		case "", strategyWRR:
+			loadBalancer = tcp.NewWRRLoadBalancer()
+		case strategyLeastConn:
+			loadBalancer = tcp.NewLeastConnLoadBalancer()
+		case strategySourceIP:
+			var ipv4Prefix, ipv6Prefix int
+			if conf.LoadBalancer.SourceIP != nil {
+				ipv4Prefix, ipv6Prefix = conf.LoadBalancer.SourceIP.IPv4Prefix, conf.LoadBalancer.SourceIP.IPv6Prefix
+			}
+
+			lb, err := tcp.NewHashLoadBalancer(ipv4Prefix, ipv6Prefix)
+			if err != nil {
+				conf.AddError(err, true)
+				return nil, err
+			}
+			loadBalancer = lb
+		default:
+			err := fmt.Errorf("unknown balancing strategy %q", conf.LoadBalancer.Strategy)
+			conf.AddError(err, true)
+			return nil, err
+		}
 
 		if conf.LoadBalancer.TerminationDelay == nil {
 			defaultTerminationDelay := 100
@@ -53,21 +128,75 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		}
 		duration := time.Duration(*conf.LoadBalancer.TerminationDelay) * time.Millisecond
 
+		if conf.LoadBalancer.SlowStart != nil {
+			lb, ok := loadBalancer.(slowStarter)
+			if !ok {
+				err := fmt.Errorf("slow start cannot be used with the %s strategy", conf.LoadBalancer.Strategy)
+				conf.AddError(err, true)
+				return nil, err
+			}
+
+			slowStart := conf.LoadBalancer.SlowStart
+			if err := lb.SetSlowStart(time.Duration(slowStart.Duration), slowStart.InitialPercent); err != nil {
+				conf.AddError(err, true)
+				return nil, err
+			}
+		}
+
+		loadBalancer.SetMaxConnections(conf.LoadBalancer.MaxConnections)
+
+		proxyProtocol, err := buildProxyProtocol(conf.LoadBalancer.ProxyProtocol)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		var addresses []string
 		for name, server := range conf.LoadBalancer.Servers {
 			if _, _, err := net.SplitHostPort(server.Address); err != nil {
 				logger.Errorf("In service %q: %v", serviceQualifiedName, err)
 				continue
 			}
 
-			handler, err := tcp.NewProxy(server.Address, duration)
+			proxy, err := tcp.NewProxy(server.Address, duration, proxyProtocol)
 			if err != nil {
 				logger.Errorf("In service %q server %q: %v", serviceQualifiedName, server.Address, err)
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			var handler tcp.Handler = proxy
+
+			if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+				handler = tcp.NewServerMetricsHandler(m.metricsRegistry, serviceQualifiedName, server.Address, handler)
+			}
+
+			weight := 1
+			if server.Weight != 0 {
+				weight = server.Weight
+			}
+
+			loadBalancer.AddNamedServer(server.Address, handler, &weight)
+			if server.MaxConnections > 0 {
+				if err := loadBalancer.SetServerMaxConnections(server.Address, server.MaxConnections); err != nil {
+					logger.Errorf("In service %q server %q: %v", serviceQualifiedName, server.Address, err)
+				}
+			}
+			addresses = append(addresses, server.Address)
 			logger.WithField(log.ServerName, name).Debugf("Creating TCP server %d at %s", name, server.Address)
 		}
+
+		if conf.LoadBalancer.HealthCheck != nil {
+			lbsu := healthcheck.NewTCPLBStatusUpdater(loadBalancer, conf)
+			for _, address := range addresses {
+				if err := lbsu.SetStatus(address, true); err != nil {
+					return nil, fmt.Errorf("error adding server %s to load balancer: %w", address, err)
+				}
+			}
+
+			m.balancers[serviceQualifiedName] = append(m.balancers[serviceQualifiedName], lbsu)
+			m.servers[serviceQualifiedName] = addresses
+		}
+
 		return loadBalancer, nil
 	case conf.Weighted != nil:
 		loadBalancer := tcp.NewWRRLoadBalancer()
@@ -77,12 +206,152 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				logger.Errorf("In service %q: %v", serviceQualifiedName, err)
 				return nil, err
 			}
-			loadBalancer.AddWeightServer(handler, service.Weight)
+			loadBalancer.AddNamedServer(service.Name, handler, service.Weight)
+
+			// The status of the service propagates, so that the balancer skips it while it is down.
+			if notifier, ok := handler.(statusNotifier); ok {
+				name := service.Name
+				notifier.RegisterStatusUpdater(func(up bool) {
+					if err := loadBalancer.SetStatus(name, up); err != nil {
+						logger.Error(err)
+					}
+				})
+			}
 		}
 		return loadBalancer, nil
+	case conf.Mirroring != nil:
+		handler, err := m.BuildTCP(rootCtx, conf.Mirroring.Service)
+		if err != nil {
+			logger.Errorf("In service %q: %v", serviceQualifiedName, err)
+			return nil, err
+		}
+
+		bufferSize := conf.Mirroring.BufferSize
+		if bufferSize <= 0 {
+			bufferSize = defaultMirrorBufferSize
+		}
+
+		mirroring := tcp.NewMirroring(handler, bufferSize)
+		for _, mirror := range conf.Mirroring.Mirrors {
+			mirrorHandler, err := m.BuildTCP(rootCtx, mirror.Name)
+			if err != nil {
+				logger.Errorf("In service %q: %v", serviceQualifiedName, err)
+				return nil, err
+			}
+
+			if err := mirroring.AddMirror(mirrorHandler, mirror.Percent); err != nil {
+				conf.AddError(err, true)
+				return nil, err
+			}
+		}
+		return mirroring, nil
+	case conf.Failover != nil:
+		handler, err := m.BuildTCP(rootCtx, conf.Failover.Service)
+		if err != nil {
+			logger.Errorf("In service %q: %v", serviceQualifiedName, err)
+			return nil, err
+		}
+
+		fallbackHandler, err := m.BuildTCP(rootCtx, conf.Failover.Fallback)
+		if err != nil {
+			logger.Errorf("In service %q: %v", serviceQualifiedName, err)
+			return nil, err
+		}
+
+		failover := tcp.NewFailover(handler, fallbackHandler)
+
+		notifier, ok := handler.(statusNotifier)
+		if !ok {
+			err := fmt.Errorf("the main service %q of a failover does not report its status", conf.Failover.Service)
+			conf.AddError(err, true)
+			return nil, err
+		}
+		notifier.RegisterStatusUpdater(failover.SetHandlerStatus)
+
+		if notifier, ok := fallbackHandler.(statusNotifier); ok {
+			notifier.RegisterStatusUpdater(failover.SetFallbackHandlerStatus)
+		}
+
+		return failover, nil
 	default:
 		err := fmt.Errorf("the service %q does not have any type defined", serviceQualifiedName)
 		conf.AddError(err, true)
 		return nil, err
 	}
 }
+
+// LaunchHealthCheck launches the health checks of the TCP services, and stops the previous ones.
+func (m *Manager) LaunchHealthCheck() {
+	backendConfigs := make(map[string]*healthcheck.TCPBackendConfig)
+
+	for serviceName, balancers := range m.balancers {
+		ctx := log.With(context.Background(), log.Str(log.ServiceName, serviceName))
+
+		opts := buildHealthCheckOptions(ctx, serviceName, m.configs[serviceName].LoadBalancer.HealthCheck)
+		log.FromContext(ctx).Debugf("Setting up healthcheck for TCP service %s with %s", serviceName, opts)
+
+		backendConfigs[serviceName] = healthcheck.NewTCPBackendConfig(opts, serviceName, balancers, uniq(m.servers[serviceName]))
+	}
+
+	healthcheck.GetTCPHealthCheck().SetBackendsConfiguration(context.Background(), backendConfigs)
+}
+
+func buildHealthCheckOptions(ctx context.Context, backend string, hc *dynamic.TCPHealthCheck) healthcheck.TCPOptions {
+	logger := log.FromContext(ctx)
+
+	interval := defaultHealthCheckInterval
+	if hc.Interval > 0 {
+		interval = time.Duration(hc.Interval)
+	}
+
+	timeout := defaultHealthCheckTimeout
+	if hc.Timeout > 0 {
+		timeout = time.Duration(hc.Timeout)
+	}
+
+	if timeout >= interval {
+		logger.Warnf("Health check timeout for TCP backend '%s' should be lower than the health check interval (%s).", backend, interval)
+	}
+
+	return healthcheck.TCPOptions{
+		Interval: interval,
+		Timeout:  timeout,
+		Send:     hc.Send,
+		Expect:   hc.Expect,
+		TLS:      hc.TLS,
+	}
+}
+
+// uniq returns the addresses without duplicates, keeping their order.
+func uniq(addresses []string) []string {
+	seen := make(map[string]struct{}, len(addresses))
+
+	var result []string
+	for _, address := range addresses {
+		if _, ok := seen[address]; ok {
+			continue
+		}
+		seen[address] = struct{}{}
+		result = append(result, address)
+	}
+
+	return result
+}
+
+// buildProxyProtocol returns the PROXY protocol header options of the servers of a TCP service, defaulting to the version 2.
+func buildProxyProtocol(config *dynamic.TCPProxyProtocol) (*tcp.ProxyProtocol, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	proxyProtocol := &tcp.ProxyProtocol{Version: config.Version}
+	if proxyProtocol.Version == 0 {
+		proxyProtocol.Version = 2
+	}
+
+	if err := proxyProtocol.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid PROXY protocol configuration: %w", err)
+	}
+
+	return proxyProtocol, nil
+}
@@ -3,10 +3,12 @@ package tcp
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/server/provider"
+	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -65,6 +67,267 @@ func TestManager_BuildTCP(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:        "least connections strategy",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: "leastconn",
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.12:80", Weight: 2},
+								{Address: "192.168.0.13:80"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "source IP strategy",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: "sourceip",
+							SourceIP: &dynamic.TCPSourceIP{IPv4Prefix: 24, IPv6Prefix: 64},
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.12:80"},
+								{Address: "192.168.0.13:80"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "source IP strategy with an invalid prefix",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: "sourceip",
+							SourceIP: &dynamic.TCPSourceIP{IPv4Prefix: 33},
+						},
+					},
+				},
+			},
+			expectedError: "invalid IPv4 prefix length 33",
+		},
+		{
+			desc:        "slow start",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy:  "leastconn",
+							SlowStart: &dynamic.TCPSlowStart{Duration: types.Duration(30 * time.Second), InitialPercent: 10},
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.12:80"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "slow start with the source IP strategy",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy:  "sourceip",
+							SlowStart: &dynamic.TCPSlowStart{Duration: types.Duration(30 * time.Second)},
+						},
+					},
+				},
+			},
+			expectedError: "slow start cannot be used with the sourceip strategy",
+		},
+		{
+			desc:        "PROXY protocol",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							ProxyProtocol: &dynamic.TCPProxyProtocol{},
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.12:80"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "unsupported PROXY protocol version",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							ProxyProtocol: &dynamic.TCPProxyProtocol{Version: 3},
+						},
+					},
+				},
+			},
+			expectedError: "invalid PROXY protocol configuration: unsupported PROXY protocol version 3",
+		},
+		{
+			desc:        "unknown strategy",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: "random",
+						},
+					},
+				},
+			},
+			expectedError: `unknown balancing strategy "random"`,
+		},
+		{
+			desc:        "multi-types service",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{},
+						Mirroring:    &dynamic.TCPMirroring{Service: "main"},
+					},
+				},
+			},
+			expectedError: "cannot create service: multi-types service not supported, consider declaring two different pieces of service instead",
+		},
+		{
+			desc:        "mirroring",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{
+							Service: "main",
+							Mirrors: []dynamic.TCPMirrorService{
+								{Name: "mirror", Percent: 10},
+							},
+						},
+					},
+				},
+				"main": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.12:80"},
+							},
+						},
+					},
+				},
+				"mirror": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.13:80"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "mirroring with an invalid percent",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{
+							Service: "main",
+							Mirrors: []dynamic.TCPMirrorService{
+								{Name: "main", Percent: 101},
+							},
+						},
+					},
+				},
+				"main": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.12:80"},
+							},
+						},
+					},
+				},
+			},
+			expectedError: "percent must be between 0 and 100",
+		},
+		{
+			desc:        "failover",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						Failover: &dynamic.TCPFailover{
+							Service:  "main",
+							Fallback: "fallback",
+						},
+					},
+				},
+				"main": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.12:80"},
+							},
+							HealthCheck: &dynamic.TCPHealthCheck{},
+						},
+					},
+				},
+				"fallback": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.13:80"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "failover with a main service not reporting its status",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						Failover: &dynamic.TCPFailover{
+							Service:  "main",
+							Fallback: "fallback",
+						},
+					},
+				},
+				"main": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{Service: "fallback"},
+					},
+				},
+				"fallback": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.13:80"},
+							},
+						},
+					},
+				},
+			},
+			expectedError: `the main service "main" of a failover does not report its status`,
+		},
 		{
 			desc:        "Simple service name",
 			serviceName: "serviceName",
@@ -180,7 +443,7 @@ func TestManager_BuildTCP(t *testing.T) {
 
 			manager := NewManager(&runtime.Configuration{
 				TCPServices: test.configs,
-			})
+			}, nil)
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/safe"
+)
+
+// drainInterval is how often the idle connections of a transport removed from the pool are closed,
+// until none of its connections is left open.
+const drainInterval = time.Second
+
+type dialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// connCounter counts the connections opened by a transport, and not closed yet.
+type connCounter struct {
+	open int64
+}
+
+func (c *connCounter) wrap(dialContext dialContextFunc) dialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		atomic.AddInt64(&c.open, 1)
+		return &countedConn{Conn: conn, counter: c}, nil
+	}
+}
+
+func (c *connCounter) count() int64 {
+	return atomic.LoadInt64(&c.open)
+}
+
+type countedConn struct {
+	net.Conn
+	counter *connCounter
+	once    sync.Once
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(func() {
+		atomic.AddInt64(&c.counter.open, -1)
+	})
+
+	return c.Conn.Close()
+}
+
+// drain closes the idle connections of a transport removed from the pool,
+// and keeps closing them as the requests of the previous configuration still using the transport are done,
+// until none of its connections is left open.
+// It must be called with the lock held.
+func (p *transportPool) drain(transport *pooledTransport) {
+	transport.stop()
+	transport.closeIdleConnections()
+
+	if transport.conns.count() == 0 {
+		return
+	}
+
+	p.draining[transport] = struct{}{}
+	p.updateDrainingConnsGauge()
+
+	safe.Go(func() {
+		ticker := time.NewTicker(drainInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			transport.closeIdleConnections()
+
+			p.mu.Lock()
+			drained := transport.conns.count() == 0
+			if drained {
+				delete(p.draining, transport)
+			}
+			p.updateDrainingConnsGauge()
+			p.mu.Unlock()
+
+			if drained {
+				return
+			}
+		}
+	})
+}
+
+// updateDrainingConnsGauge must be called with the lock held.
+func (p *transportPool) updateDrainingConnsGauge() {
+	if p.drainingConnsGauge == nil {
+		return
+	}
+
+	var count int64
+	for transport := range p.draining {
+		count += transport.conns.count()
+	}
+
+	p.drainingConnsGauge.Set(float64(count))
+}
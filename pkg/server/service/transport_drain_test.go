@@ -0,0 +1,68 @@
+package service
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportPool_drain(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		close(started)
+		<-release
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	gauge := newHostGauge()
+
+	pool := newTransportPool(&static.ServersTransport{}, nil)
+	pool.drainingConnsGauge = gauge
+
+	roundTripper, err := pool.get(&dynamic.ServersTLS{ServerName: "backend.internal"})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		resp, err := roundTripper.RoundTrip(req)
+		if err != nil {
+			done <- err
+			return
+		}
+
+		_, err = ioutil.ReadAll(resp.Body)
+		done <- err
+		_ = resp.Body.Close()
+	}()
+
+	<-started
+
+	pool.retain(nil)
+
+	assert.Empty(t, pool.transports)
+	assert.Equal(t, float64(1), gauge.value(""))
+
+	close(release)
+	require.NoError(t, <-done)
+
+	assert.Eventually(t, func() bool {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+
+		return len(pool.draining) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	assert.Equal(t, float64(0), gauge.value(""))
+}
@@ -5,22 +5,51 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"time"
 
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/healthcheck"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/server/provider"
 	"github.com/containous/traefik/v2/pkg/udp"
 )
 
+const (
+	strategyWRR      = "wrr"
+	strategySourceIP = "sourceip"
+)
+
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+)
+
+// balancer is a UDP load-balancer.
+type balancer interface {
+	udp.Handler
+	AddNamedServer(name string, serverHandler udp.Handler, weight *int)
+	SetStatus(name string, up bool) error
+}
+
 // Manager handles UDP services creation.
 type Manager struct {
 	configs map[string]*runtime.UDPServiceInfo
+	// balancers are the load-balancers of the services with a health check, keyed by service name.
+	balancers map[string]healthcheck.UDPBalancers
+	// servers are the addresses of the servers of the services with a health check, keyed by service name.
+	servers map[string][]string
+	// serversTransports are the options of the sockets opened to the servers, keyed by qualified name.
+	serversTransports map[string]*dynamic.UDPServersTransport
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+func NewManager(conf *runtime.Configuration, serversTransports map[string]*dynamic.UDPServersTransport) *Manager {
 	return &Manager{
-		configs: conf.UDPServices,
+		configs:           conf.UDPServices,
+		balancers:         make(map[string]healthcheck.UDPBalancers),
+		servers:           make(map[string][]string),
+		serversTransports: serversTransports,
 	}
 }
 
@@ -44,23 +73,71 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 	logger := log.FromContext(ctx)
 	switch {
 	case conf.LoadBalancer != nil:
-		loadBalancer := udp.NewWRRLoadBalancer()
+		var loadBalancer balancer
+		switch conf.LoadBalancer.Strategy {
+		case "", strategyWRR:
+			loadBalancer = udp.NewWRRLoadBalancer()
+		case strategySourceIP:
+			var ipv4Prefix, ipv6Prefix int
+			var withPort bool
+			if conf.LoadBalancer.SourceIP != nil {
+				ipv4Prefix, ipv6Prefix = conf.LoadBalancer.SourceIP.IPv4Prefix, conf.LoadBalancer.SourceIP.IPv6Prefix
+				withPort = conf.LoadBalancer.SourceIP.Port
+			}
+
+			lb, err := udp.NewHashLoadBalancer(ipv4Prefix, ipv6Prefix, withPort)
+			if err != nil {
+				conf.AddError(err, true)
+				return nil, err
+			}
+			loadBalancer = lb
+		default:
+			err := fmt.Errorf("unknown balancing strategy %q", conf.LoadBalancer.Strategy)
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		dialOptions, err := m.getDialOptions(ctx, conf.LoadBalancer.ServersTransport)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
 
+		var addresses []string
 		for name, server := range conf.LoadBalancer.Servers {
 			if _, _, err := net.SplitHostPort(server.Address); err != nil {
 				logger.Errorf("In udp service %q: %v", serviceQualifiedName, err)
 				continue
 			}
 
-			handler, err := udp.NewProxy(server.Address)
+			handler, err := udp.NewProxy(server.Address, dialOptions)
 			if err != nil {
 				logger.Errorf("In udp service %q server %q: %v", serviceQualifiedName, server.Address, err)
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			weight := 1
+			if server.Weight != 0 {
+				weight = server.Weight
+			}
+
+			loadBalancer.AddNamedServer(server.Address, handler, &weight)
+			addresses = append(addresses, server.Address)
 			logger.WithField(log.ServerName, name).Debugf("Creating UDP server %d at %s", name, server.Address)
 		}
+
+		if conf.LoadBalancer.HealthCheck != nil {
+			lbsu := healthcheck.NewUDPLBStatusUpdater(loadBalancer, conf)
+			for _, address := range addresses {
+				if err := lbsu.SetStatus(address, true); err != nil {
+					return nil, fmt.Errorf("error adding server %s to load balancer: %w", address, err)
+				}
+			}
+
+			m.balancers[serviceQualifiedName] = append(m.balancers[serviceQualifiedName], lbsu)
+			m.servers[serviceQualifiedName] = addresses
+		}
+
 		return loadBalancer, nil
 	case conf.Weighted != nil:
 		loadBalancer := udp.NewWRRLoadBalancer()
@@ -79,3 +156,94 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 		return nil, err
 	}
 }
+
+// getDialOptions returns the options of the serversTransport with the given name, if any.
+func (m *Manager) getDialOptions(ctx context.Context, serversTransportName string) (udp.DialOptions, error) {
+	if serversTransportName == "" {
+		return udp.DialOptions{}, nil
+	}
+
+	qualifiedName := provider.GetQualifiedName(ctx, serversTransportName)
+
+	transport, ok := m.serversTransports[qualifiedName]
+	if !ok || transport == nil {
+		return udp.DialOptions{}, fmt.Errorf("the udp serversTransport %q does not exist", qualifiedName)
+	}
+
+	opts := udp.DialOptions{
+		Timeout:         time.Duration(transport.DialTimeout),
+		ReadBufferSize:  transport.ReadBufferSize,
+		WriteBufferSize: transport.WriteBufferSize,
+		DSCP:            transport.DSCP,
+	}
+
+	if transport.SourceAddress != "" {
+		opts.SourceAddress = net.ParseIP(transport.SourceAddress)
+		if opts.SourceAddress == nil {
+			return udp.DialOptions{}, fmt.Errorf("invalid source address %q in the udp serversTransport %q", transport.SourceAddress, qualifiedName)
+		}
+	}
+
+	if err := opts.Validate(); err != nil {
+		return udp.DialOptions{}, fmt.Errorf("invalid udp serversTransport %q: %w", qualifiedName, err)
+	}
+
+	return opts, nil
+}
+
+// LaunchHealthCheck launches the health checks of the UDP services, and stops the previous ones.
+func (m *Manager) LaunchHealthCheck() {
+	backendConfigs := make(map[string]*healthcheck.UDPBackendConfig)
+
+	for serviceName, balancers := range m.balancers {
+		ctx := log.With(context.Background(), log.Str(log.ServiceName, serviceName))
+
+		opts := buildHealthCheckOptions(ctx, serviceName, m.configs[serviceName].LoadBalancer.HealthCheck)
+		log.FromContext(ctx).Debugf("Setting up healthcheck for UDP service %s with %s", serviceName, opts)
+
+		backendConfigs[serviceName] = healthcheck.NewUDPBackendConfig(opts, serviceName, balancers, uniq(m.servers[serviceName]))
+	}
+
+	healthcheck.GetUDPHealthCheck().SetBackendsConfiguration(context.Background(), backendConfigs)
+}
+
+func buildHealthCheckOptions(ctx context.Context, backend string, hc *dynamic.UDPHealthCheck) healthcheck.UDPOptions {
+	logger := log.FromContext(ctx)
+
+	interval := defaultHealthCheckInterval
+	if hc.Interval > 0 {
+		interval = time.Duration(hc.Interval)
+	}
+
+	timeout := defaultHealthCheckTimeout
+	if hc.Timeout > 0 {
+		timeout = time.Duration(hc.Timeout)
+	}
+
+	if timeout >= interval {
+		logger.Warnf("Health check timeout for UDP backend '%s' should be lower than the health check interval (%s).", backend, interval)
+	}
+
+	return healthcheck.UDPOptions{
+		Interval: interval,
+		Timeout:  timeout,
+		Send:     hc.Send,
+		Expect:   hc.Expect,
+	}
+}
+
+// uniq returns the addresses without duplicates, keeping their order.
+func uniq(addresses []string) []string {
+	seen := make(map[string]struct{}, len(addresses))
+
+	var result []string
+	for _, address := range addresses {
+		if _, ok := seen[address]; ok {
+			continue
+		}
+		seen[address] = struct{}{}
+		result = append(result, address)
+	}
+
+	return result
+}
@@ -13,11 +13,12 @@ import (
 
 func TestManager_BuildUDP(t *testing.T) {
 	testCases := []struct {
-		desc          string
-		serviceName   string
-		configs       map[string]*runtime.UDPServiceInfo
-		providerName  string
-		expectedError string
+		desc              string
+		serviceName       string
+		configs           map[string]*runtime.UDPServiceInfo
+		serversTransports map[string]*dynamic.UDPServersTransport
+		providerName      string
+		expectedError     string
 	}{
 		{
 			desc:          "without configuration",
@@ -135,6 +136,70 @@ func TestManager_BuildUDP(t *testing.T) {
 			},
 			providerName: "provider-1",
 		},
+		{
+			desc:        "weighted servers with a health check",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{Address: "192.168.0.12:53", Weight: 3},
+								{Address: "192.168.0.13:53"},
+							},
+							HealthCheck: &dynamic.UDPHealthCheck{Send: "PING", Expect: "PONG"},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "source IP strategy",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Strategy: "sourceip",
+							SourceIP: &dynamic.UDPSourceIP{IPv4Prefix: 24, Port: true},
+							Servers: []dynamic.UDPServer{
+								{Address: "192.168.0.12:53"},
+								{Address: "192.168.0.13:53"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "source IP strategy with an invalid prefix",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Strategy: "sourceip",
+							SourceIP: &dynamic.UDPSourceIP{IPv6Prefix: 129},
+						},
+					},
+				},
+			},
+			expectedError: "invalid IPv6 prefix length 129",
+		},
+		{
+			desc:        "unknown strategy",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Strategy: "random",
+						},
+					},
+				},
+			},
+			expectedError: `unknown balancing strategy "random"`,
+		},
 		{
 			desc:        "missing port in address with hostname, server is skipped, error is logged",
 			serviceName: "serviceName",
@@ -171,6 +236,124 @@ func TestManager_BuildUDP(t *testing.T) {
 			},
 			providerName: "provider-1",
 		},
+		{
+			desc:        "serversTransport of the provider",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							ServersTransport: "transport",
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			serversTransports: map[string]*dynamic.UDPServersTransport{
+				"transport@provider-1": {
+					SourceAddress:  "127.0.0.1",
+					ReadBufferSize: 65536,
+					DSCP:           46,
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "serversTransport of another provider",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							ServersTransport: "transport@provider-2",
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			serversTransports: map[string]*dynamic.UDPServersTransport{
+				"transport@provider-2": {},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "unknown serversTransport",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							ServersTransport: "transport",
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `the udp serversTransport "transport@provider-1" does not exist`,
+		},
+		{
+			desc:        "serversTransport with an invalid source address",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							ServersTransport: "transport",
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			serversTransports: map[string]*dynamic.UDPServersTransport{
+				"transport@provider-1": {
+					SourceAddress: "foobar",
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `invalid source address "foobar" in the udp serversTransport "transport@provider-1"`,
+		},
+		{
+			desc:        "serversTransport with an invalid DSCP",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							ServersTransport: "transport",
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			serversTransports: map[string]*dynamic.UDPServersTransport{
+				"transport@provider-1": {
+					DSCP: 64,
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `invalid udp serversTransport "transport@provider-1": invalid DSCP 64: it must be between 0 and 63`,
+		},
 	}
 
 	for _, test := range testCases {
@@ -180,7 +363,7 @@ func TestManager_BuildUDP(t *testing.T) {
 
 			manager := NewManager(&runtime.Configuration{
 				UDPServices: test.configs,
-			})
+			}, test.serversTransports)
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
@@ -0,0 +1,217 @@
+package tcp
+
+import (
+	"crypto/md5" // #nosec - JA3 fingerprints are MD5 hashes by definition.
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	extensionServerName   = 0
+	extensionCurves       = 10
+	extensionPointFormats = 11
+	extensionALPN         = 16
+	handshakeClientHello  = 1
+	handshakeRecordType   = 0x16
+	recordHeaderLength    = 5
+	handshakeHeaderLength = 4
+	// maxPlaintextLength is the maximum length of the payload of a TLS record.
+	maxPlaintextLength = 16384
+)
+
+var errInvalidClientHello = errors.New("invalid ClientHello")
+
+// ClientHello holds the fields of a TLS ClientHello message which can be used to filter the connections.
+type ClientHello struct {
+	ServerName    string
+	ALPNProtocols []string
+	// JA3 is the JA3 fingerprint of the ClientHello, cf https://github.com/salesforce/ja3.
+	JA3 string
+}
+
+// parseClientHello parses the ClientHello message contained in the given TLS record.
+func parseClientHello(record []byte) (*ClientHello, error) {
+	if len(record) < recordHeaderLength+handshakeHeaderLength || record[recordHeaderLength] != handshakeClientHello {
+		return nil, errInvalidClientHello
+	}
+
+	msg := bytesReader(record[recordHeaderLength+handshakeHeaderLength:])
+
+	version, ok := msg.uint16()
+	if !ok || !msg.skip(32) {
+		return nil, errInvalidClientHello
+	}
+
+	if sessionID, ok := msg.vector8(); !ok || len(sessionID) > 32 {
+		return nil, errInvalidClientHello
+	}
+
+	cipherSuites, ok := msg.vector16()
+	if !ok {
+		return nil, errInvalidClientHello
+	}
+
+	if _, ok = msg.vector8(); !ok {
+		return nil, errInvalidClientHello
+	}
+
+	hello := &ClientHello{}
+	var extensions, curves, pointFormats []uint16
+
+	// The extensions are optional.
+	extensionsData, _ := msg.vector16()
+	for len(extensionsData) > 0 {
+		extType, ok := extensionsData.uint16()
+		if !ok {
+			return nil, errInvalidClientHello
+		}
+
+		data, ok := extensionsData.vector16()
+		if !ok {
+			return nil, errInvalidClientHello
+		}
+
+		if !isGREASE(extType) {
+			extensions = append(extensions, extType)
+		}
+
+		switch extType {
+		case extensionServerName:
+			hello.ServerName = parseServerName(data)
+		case extensionALPN:
+			protocols, _ := data.vector16()
+			for len(protocols) > 0 {
+				protocol, ok := protocols.vector8()
+				if !ok {
+					break
+				}
+				hello.ALPNProtocols = append(hello.ALPNProtocols, string(protocol))
+			}
+		case extensionCurves:
+			list, _ := data.vector16()
+			for len(list) > 0 {
+				curve, ok := list.uint16()
+				if !ok {
+					break
+				}
+				if !isGREASE(curve) {
+					curves = append(curves, curve)
+				}
+			}
+		case extensionPointFormats:
+			list, _ := data.vector8()
+			for _, format := range list {
+				pointFormats = append(pointFormats, uint16(format))
+			}
+		}
+	}
+
+	var ciphers []uint16
+	for len(cipherSuites) > 0 {
+		cipher, ok := cipherSuites.uint16()
+		if !ok {
+			return nil, errInvalidClientHello
+		}
+		if !isGREASE(cipher) {
+			ciphers = append(ciphers, cipher)
+		}
+	}
+
+	ja3 := strings.Join([]string{
+		strconv.Itoa(int(version)),
+		joinUint16(ciphers),
+		joinUint16(extensions),
+		joinUint16(curves),
+		joinUint16(pointFormats),
+	}, ",")
+
+	sum := md5.Sum([]byte(ja3)) // #nosec
+	hello.JA3 = hex.EncodeToString(sum[:])
+
+	return hello, nil
+}
+
+func parseServerName(data bytesReader) string {
+	list, _ := data.vector16()
+	for len(list) > 0 {
+		nameType, ok := list.uint8()
+		if !ok {
+			return ""
+		}
+
+		name, ok := list.vector16()
+		if !ok {
+			return ""
+		}
+
+		if nameType == 0 {
+			return string(name)
+		}
+	}
+	return ""
+}
+
+// isGREASE reports whether the value is one of the reserved GREASE values (RFC 8701), which are ignored by JA3.
+func isGREASE(value uint16) bool {
+	return value&0x0f0f == 0x0a0a && value>>8 == value&0xff
+}
+
+func joinUint16(values []uint16) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = strconv.Itoa(int(value))
+	}
+	return strings.Join(parts, "-")
+}
+
+// bytesReader reads the fields of a TLS message.
+type bytesReader []byte
+
+func (b *bytesReader) skip(n int) bool {
+	if len(*b) < n {
+		return false
+	}
+	*b = (*b)[n:]
+	return true
+}
+
+func (b *bytesReader) uint8() (uint8, bool) {
+	if len(*b) < 1 {
+		return 0, false
+	}
+	v := (*b)[0]
+	*b = (*b)[1:]
+	return v, true
+}
+
+func (b *bytesReader) uint16() (uint16, bool) {
+	if len(*b) < 2 {
+		return 0, false
+	}
+	v := binary.BigEndian.Uint16(*b)
+	*b = (*b)[2:]
+	return v, true
+}
+
+func (b *bytesReader) vector8() (bytesReader, bool) {
+	length, ok := b.uint8()
+	if !ok || len(*b) < int(length) {
+		return nil, false
+	}
+	v := (*b)[:length]
+	*b = (*b)[length:]
+	return v, true
+}
+
+func (b *bytesReader) vector16() (bytesReader, bool) {
+	length, ok := b.uint16()
+	if !ok || len(*b) < int(length) {
+		return nil, false
+	}
+	v := (*b)[:length]
+	*b = (*b)[length:]
+	return v, true
+}
@@ -0,0 +1,79 @@
+package tcp
+
+import (
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+// Failover is a TCP handler forwarding the connections to a main handler,
+// and to a fallback handler while the main one is down.
+type Failover struct {
+	handler         Handler
+	fallbackHandler Handler
+
+	lock       sync.RWMutex
+	handlerUp  bool
+	fallbackUp bool
+	updaters   statusUpdaters
+}
+
+// NewFailover creates a new Failover, forwarding the connections to the given handler,
+// and to the fallback handler while the main one is down.
+// Both handlers start up.
+func NewFailover(handler, fallbackHandler Handler) *Failover {
+	return &Failover{
+		handler:         handler,
+		fallbackHandler: fallbackHandler,
+		handlerUp:       true,
+		fallbackUp:      true,
+	}
+}
+
+// ServeTCP forwards the connection to the main handler if it is up, and to the fallback one otherwise.
+func (f *Failover) ServeTCP(conn WriteCloser) {
+	f.lock.RLock()
+	handlerUp, fallbackUp := f.handlerUp, f.fallbackUp
+	f.lock.RUnlock()
+
+	switch {
+	case handlerUp:
+		f.handler.ServeTCP(conn)
+	case fallbackUp:
+		f.fallbackHandler.ServeTCP(conn)
+	default:
+		log.WithoutContext().Error("Error during failover: the main and fallback services are down")
+		conn.Close()
+	}
+}
+
+// SetHandlerStatus marks the main handler as up or down.
+func (f *Failover) SetHandlerStatus(up bool) {
+	f.setStatus(func() { f.handlerUp = up })
+}
+
+// SetFallbackHandlerStatus marks the fallback handler as up or down.
+func (f *Failover) SetFallbackHandlerStatus(up bool) {
+	f.setStatus(func() { f.fallbackUp = up })
+}
+
+// RegisterStatusUpdater registers a function called when the failover goes down, because both its handlers are down,
+// or back up.
+func (f *Failover) RegisterStatusUpdater(fn func(up bool)) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.updaters = append(f.updaters, fn)
+}
+
+func (f *Failover) setStatus(update func()) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	wasUp := f.handlerUp || f.fallbackUp
+	update()
+
+	if isUp := f.handlerUp || f.fallbackUp; isUp != wasUp {
+		f.updaters.notify(isUp)
+	}
+}
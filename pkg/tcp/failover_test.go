@@ -0,0 +1,45 @@
+package tcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailover(t *testing.T) {
+	var served []string
+	newHandler := func(name string) Handler {
+		return HandlerFunc(func(conn WriteCloser) {
+			served = append(served, name)
+		})
+	}
+
+	failover := NewFailover(newHandler("main"), newHandler("fallback"))
+
+	var statuses []bool
+	failover.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	})
+
+	serveFailover := func() {
+		client, server := net.Pipe()
+		defer client.Close()
+
+		failover.ServeTCP(pipeConn{Conn: server})
+	}
+
+	serveFailover()
+
+	failover.SetHandlerStatus(false)
+	serveFailover()
+
+	failover.SetFallbackHandlerStatus(false)
+	serveFailover()
+
+	failover.SetHandlerStatus(true)
+	serveFailover()
+
+	assert.Equal(t, []string{"main", "fallback", "main"}, served)
+	assert.Equal(t, []bool{false, true}, statuses)
+}
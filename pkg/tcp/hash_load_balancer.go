@@ -0,0 +1,274 @@
+package tcp
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+// hashReplicas is the number of points of each server on the ring,
+// which spreads the clients evenly between the servers.
+const hashReplicas = 160
+
+type hashServer struct {
+	Handler
+	name   string
+	down   bool
+	active int
+	// maxConns is the maximum number of concurrent connections forwarded to the server, 0 meaning no limit.
+	maxConns int
+}
+
+type hashPoint struct {
+	hash   uint64
+	server *hashServer
+}
+
+// HashLoadBalancer is a load balancer for TCP services forwarding the connections
+// to the server owning the hash of the client IP on a ring,
+// so that a reconnecting client lands on the same server, even when the other servers change.
+type HashLoadBalancer struct {
+	// ipv4Mask and ipv6Mask are applied to the client IP before it is hashed,
+	// so that the clients of a subnet share their server.
+	ipv4Mask net.IPMask
+	ipv6Mask net.IPMask
+
+	lock    sync.Mutex
+	servers []*hashServer
+	ring    []hashPoint
+	// maxConns is the maximum number of concurrent connections of the balancer, 0 meaning no limit.
+	maxConns int
+	active   int
+	updaters statusUpdaters
+}
+
+// NewHashLoadBalancer creates a new HashLoadBalancer,
+// hashing the client IPs masked with the given prefix lengths (0 meaning the whole address).
+func NewHashLoadBalancer(ipv4Prefix, ipv6Prefix int) (*HashLoadBalancer, error) {
+	if ipv4Prefix < 0 || ipv4Prefix > 32 {
+		return nil, fmt.Errorf("invalid IPv4 prefix length %d", ipv4Prefix)
+	}
+	if ipv6Prefix < 0 || ipv6Prefix > 128 {
+		return nil, fmt.Errorf("invalid IPv6 prefix length %d", ipv6Prefix)
+	}
+
+	if ipv4Prefix == 0 {
+		ipv4Prefix = 32
+	}
+	if ipv6Prefix == 0 {
+		ipv6Prefix = 128
+	}
+
+	return &HashLoadBalancer{
+		ipv4Mask: net.CIDRMask(ipv4Prefix, 32),
+		ipv6Mask: net.CIDRMask(ipv6Prefix, 128),
+	}, nil
+}
+
+// ServeTCP forwards the connection to the server owning the hash of the client IP.
+func (b *HashLoadBalancer) ServeTCP(conn WriteCloser) {
+	srv, err := b.acquire(b.key(conn.RemoteAddr()))
+	if err != nil {
+		log.WithoutContext().Errorf("Error during load balancing: %v", err)
+		conn.Close()
+		return
+	}
+	defer b.release(srv)
+
+	srv.ServeTCP(conn)
+}
+
+// AddServer appends a server to the existing list.
+func (b *HashLoadBalancer) AddServer(serverHandler Handler) {
+	b.AddNamedServer("", serverHandler, nil)
+}
+
+// AddWeightServer appends a server to the existing list.
+// The weight is ignored, as all the servers own the same share of the ring.
+func (b *HashLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
+	b.AddNamedServer("", serverHandler, weight)
+}
+
+// AddNamedServer appends a server to the existing list,
+// under a name which allows to change its status later on.
+// The weight is ignored, as all the servers own the same share of the ring.
+func (b *HashLoadBalancer) AddNamedServer(name string, serverHandler Handler, _ *int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.servers = append(b.servers, &hashServer{Handler: serverHandler, name: name})
+	b.buildRing()
+}
+
+// SetStatus marks the named server as up or down.
+// A server down leaves the ring, so that only its clients move to the other servers,
+// while its active connections are left untouched.
+func (b *HashLoadBalancer) SetStatus(name string, up bool) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	wasUp := len(b.ring) > 0
+
+	found := false
+	for _, srv := range b.servers {
+		if srv.name == name {
+			srv.down = !up
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	b.buildRing()
+
+	if isUp := len(b.ring) > 0; isUp != wasUp {
+		b.updaters.notify(isUp)
+	}
+
+	return nil
+}
+
+// RegisterStatusUpdater registers a function called when the balancer goes down, because all its servers are down,
+// or back up.
+func (b *HashLoadBalancer) RegisterStatusUpdater(fn func(up bool)) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.updaters = append(b.updaters, fn)
+}
+
+// SetMaxConnections sets the maximum number of concurrent connections of the balancer, 0 meaning no limit.
+// Once it is reached, the new connections are closed.
+func (b *HashLoadBalancer) SetMaxConnections(max int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.maxConns = max
+}
+
+// SetServerMaxConnections sets the maximum number of concurrent connections of the named server, 0 meaning no limit.
+// Once it is reached, its new clients are forwarded to the next server of the ring.
+func (b *HashLoadBalancer) SetServerMaxConnections(name string, max int) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	found := false
+	for _, srv := range b.servers {
+		if srv.name == name {
+			srv.maxConns = max
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	return nil
+}
+
+// key returns the masked client IP of the address.
+func (b *HashLoadBalancer) key(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return host
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(b.ipv4Mask).String()
+	}
+	return ip.Mask(b.ipv6Mask).String()
+}
+
+// acquire selects the server owning the first point of the ring following the hash of the key,
+// or the next one having room for a connection, and counts the new connection.
+func (b *HashLoadBalancer) acquire(key string) (*hashServer, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if len(b.servers) == 0 {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	if len(b.ring) == 0 {
+		return nil, errors.New("all servers are down")
+	}
+
+	if b.maxConns > 0 && b.active >= b.maxConns {
+		return nil, fmt.Errorf("maximum number of connections reached (%d)", b.maxConns)
+	}
+
+	h := hashKey(key)
+	start := sort.Search(len(b.ring), func(i int) bool { return b.ring[i].hash >= h })
+
+	for i := 0; i < len(b.ring); i++ {
+		srv := b.ring[(start+i)%len(b.ring)].server
+		if srv.maxConns > 0 && srv.active >= srv.maxConns {
+			continue
+		}
+
+		srv.active++
+		b.active++
+
+		return srv, nil
+	}
+
+	return nil, errors.New("all servers reached their maximum number of connections")
+}
+
+func (b *HashLoadBalancer) release(srv *hashServer) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	srv.active--
+	b.active--
+}
+
+// buildRing places the points of the servers up on the ring, b.lock being held.
+// The points of a server only depend on its name and rank, so the other servers keep theirs.
+func (b *HashLoadBalancer) buildRing() {
+	ring := make([]hashPoint, 0, len(b.servers)*hashReplicas)
+	for rank, srv := range b.servers {
+		if srv.down {
+			continue
+		}
+
+		id := srv.name
+		if id == "" {
+			id = "#" + strconv.Itoa(rank)
+		}
+
+		for i := 0; i < hashReplicas; i++ {
+			ring = append(ring, hashPoint{hash: hashKey(id + "#" + strconv.Itoa(i)), server: srv})
+		}
+	}
+
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+
+	b.ring = ring
+}
+
+// hashKey returns the position of the key on the ring.
+// A cryptographic hash is used, as close keys (e.g. consecutive IPs) must land on distant positions.
+func hashKey(key string) uint64 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
@@ -0,0 +1,173 @@
+package tcp
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashLoadBalancer_key(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		ipv4Prefix int
+		ipv6Prefix int
+		addr       net.Addr
+		expected   string
+	}{
+		{
+			desc:     "whole IPv4 address",
+			addr:     &net.TCPAddr{IP: net.ParseIP("10.0.1.42"), Port: 1234},
+			expected: "10.0.1.42",
+		},
+		{
+			desc:       "masked IPv4 address",
+			ipv4Prefix: 24,
+			addr:       &net.TCPAddr{IP: net.ParseIP("10.0.1.42"), Port: 1234},
+			expected:   "10.0.1.0",
+		},
+		{
+			desc:     "whole IPv6 address",
+			addr:     &net.TCPAddr{IP: net.ParseIP("2001:db8:1:2:3:4:5:6"), Port: 1234},
+			expected: "2001:db8:1:2:3:4:5:6",
+		},
+		{
+			desc:       "masked IPv6 address",
+			ipv6Prefix: 64,
+			addr:       &net.TCPAddr{IP: net.ParseIP("2001:db8:1:2:3:4:5:6"), Port: 1234},
+			expected:   "2001:db8:1:2::",
+		},
+		{
+			desc:     "no address",
+			expected: "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer, err := NewHashLoadBalancer(test.ipv4Prefix, test.ipv6Prefix)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, balancer.key(test.addr))
+		})
+	}
+}
+
+func TestNewHashLoadBalancer_invalidPrefix(t *testing.T) {
+	_, err := NewHashLoadBalancer(33, 0)
+	assert.Error(t, err)
+
+	_, err = NewHashLoadBalancer(0, -1)
+	assert.Error(t, err)
+}
+
+func TestHashLoadBalancer_acquire(t *testing.T) {
+	balancer, err := NewHashLoadBalancer(0, 0)
+	require.NoError(t, err)
+
+	for _, server := range []string{"h1", "h2", "h3"} {
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {}), nil)
+	}
+
+	owners := make(map[string]string)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("10.0.0.%d", i)
+
+		srv, err := balancer.acquire(key)
+		require.NoError(t, err)
+		balancer.release(srv)
+
+		owners[key] = srv.name
+	}
+
+	shares := make(map[string]int)
+	for _, owner := range owners {
+		shares[owner]++
+	}
+	assert.Len(t, shares, 3)
+
+	// The clients of the other servers keep their server, when one goes down.
+	require.NoError(t, balancer.SetStatus("h2", false))
+
+	for key, owner := range owners {
+		srv, err := balancer.acquire(key)
+		require.NoError(t, err)
+		balancer.release(srv)
+
+		if owner != "h2" {
+			assert.Equal(t, owner, srv.name)
+		} else {
+			assert.NotEqual(t, "h2", srv.name)
+		}
+	}
+
+	// And they all get their server back, when it comes back up.
+	require.NoError(t, balancer.SetStatus("h2", true))
+
+	for key, owner := range owners {
+		srv, err := balancer.acquire(key)
+		require.NoError(t, err)
+		balancer.release(srv)
+
+		assert.Equal(t, owner, srv.name)
+	}
+
+	assert.Error(t, balancer.SetStatus("h5", false))
+
+	// Only the clients of the new server move, when one is added.
+	balancer.AddNamedServer("h4", HandlerFunc(func(conn WriteCloser) {}), nil)
+
+	for key, owner := range owners {
+		srv, err := balancer.acquire(key)
+		require.NoError(t, err)
+		balancer.release(srv)
+
+		if srv.name != "h4" {
+			assert.Equal(t, owner, srv.name)
+		}
+	}
+}
+
+func TestHashLoadBalancer_maxConnections(t *testing.T) {
+	balancer, err := NewHashLoadBalancer(0, 0)
+	require.NoError(t, err)
+
+	for _, server := range []string{"h1", "h2"} {
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {}), nil)
+	}
+
+	owner, err := balancer.acquire("10.0.0.1")
+	require.NoError(t, err)
+
+	require.NoError(t, balancer.SetServerMaxConnections(owner.name, 1))
+	assert.Error(t, balancer.SetServerMaxConnections("h3", 1))
+
+	// The owner is full, so the client is forwarded to the next server of the ring.
+	next, err := balancer.acquire("10.0.0.1")
+	require.NoError(t, err)
+	assert.NotEqual(t, owner.name, next.name)
+
+	balancer.SetMaxConnections(2)
+
+	_, err = balancer.acquire("10.0.0.1")
+	assert.Error(t, err)
+}
+
+func TestHashLoadBalancer_noServer(t *testing.T) {
+	balancer, err := NewHashLoadBalancer(0, 0)
+	require.NoError(t, err)
+
+	_, err = balancer.acquire("10.0.0.1")
+	assert.Error(t, err)
+
+	balancer.AddNamedServer("h1", HandlerFunc(func(conn WriteCloser) {}), nil)
+	require.NoError(t, balancer.SetStatus("h1", false))
+
+	_, err = balancer.acquire("10.0.0.1")
+	assert.Error(t, err)
+}
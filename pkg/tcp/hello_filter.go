@@ -0,0 +1,63 @@
+package tcp
+
+import (
+	"bufio"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+// HelloFilter rejects the TLS connections based on their ClientHello,
+// before the handshake and the routing take place.
+type HelloFilter struct {
+	next Handler
+	// reject returns the reason why the connection must be rejected, or an empty string.
+	reject func(hello *ClientHello) string
+}
+
+// NewHelloFilter creates a new HelloFilter.
+func NewHelloFilter(next Handler, reject func(hello *ClientHello) string) *HelloFilter {
+	return &HelloFilter{next: next, reject: reject}
+}
+
+// ServeTCP closes the connection if its ClientHello is rejected, and forwards it to the next handler otherwise.
+func (f *HelloFilter) ServeTCP(conn WriteCloser) {
+	// The buffer must hold a whole record, as the ClientHello can be larger than the default buffer size.
+	br := bufio.NewReaderSize(conn, recordHeaderLength+maxPlaintextLength)
+
+	hdr, err := br.Peek(1)
+	if err != nil || hdr[0] != handshakeRecordType {
+		// Not TLS: the router decides what to do with the connection.
+		f.next.ServeTCP(&Conn{Peeked: []byte(getPeeked(br)), WriteCloser: conn})
+		return
+	}
+
+	hdr, err = br.Peek(recordHeaderLength)
+	if err != nil {
+		log.WithoutContext().Debugf("Error while peeking ClientHello from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	recLen := int(hdr[3])<<8 | int(hdr[4])
+	record, err := br.Peek(recordHeaderLength + recLen)
+	if err != nil {
+		log.WithoutContext().Debugf("Error while peeking ClientHello from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	hello, err := parseClientHello(record)
+	if err != nil {
+		log.WithoutContext().Debugf("Rejecting connection from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	if reason := f.reject(hello); reason != "" {
+		log.WithoutContext().Debugf("Rejecting connection from %s: %s", conn.RemoteAddr(), reason)
+		conn.Close()
+		return
+	}
+
+	f.next.ServeTCP(&Conn{Peeked: []byte(getPeeked(br)), WriteCloser: conn})
+}
@@ -0,0 +1,132 @@
+package tcp
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pipeConn struct {
+	net.Conn
+}
+
+func (c pipeConn) CloseWrite() error {
+	return c.Close()
+}
+
+func TestParseClientHello(t *testing.T) {
+	record := readClientHello(t, &tls.Config{
+		ServerName: "foo.example.com",
+		NextProtos: []string{"h2", "http/1.1"},
+	})
+
+	hello, err := parseClientHello(record)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo.example.com", hello.ServerName)
+	assert.Equal(t, []string{"h2", "http/1.1"}, hello.ALPNProtocols)
+	assert.Regexp(t, "^[0-9a-f]{32}$", hello.JA3)
+
+	_, err = parseClientHello(record[:recordHeaderLength+handshakeHeaderLength+10])
+	assert.Error(t, err)
+}
+
+func TestHelloFilter(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		serverName     string
+		nextProtos     []string
+		expectedServed bool
+	}{
+		{
+			desc:           "accepted ClientHello",
+			serverName:     "foo.example.com",
+			expectedServed: true,
+		},
+		{
+			desc:           "ClientHello larger than 4 KiB",
+			serverName:     "foo.example.com",
+			nextProtos:     largeNextProtos(),
+			expectedServed: true,
+		},
+		{
+			desc:       "rejected ClientHello",
+			serverName: "scanner.example.com",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			serverConn, clientConn := net.Pipe()
+			defer func() { _ = clientConn.Close() }()
+
+			go func() {
+				_ = tls.Client(clientConn, &tls.Config{ServerName: test.serverName, NextProtos: test.nextProtos}).Handshake()
+			}()
+
+			var served bool
+			next := HandlerFunc(func(conn WriteCloser) {
+				served = true
+
+				// The peeked ClientHello is still readable by the next handler.
+				first, err := bufio.NewReader(conn).Peek(1)
+				require.NoError(t, err)
+				assert.Equal(t, byte(handshakeRecordType), first[0])
+
+				_ = conn.Close()
+			})
+
+			filter := NewHelloFilter(next, func(hello *ClientHello) string {
+				if hello.ServerName == "scanner.example.com" {
+					return "denied"
+				}
+				return ""
+			})
+
+			filter.ServeTCP(pipeConn{Conn: serverConn})
+
+			assert.Equal(t, test.expectedServed, served)
+		})
+	}
+}
+
+// largeNextProtos returns ALPN protocols making the ClientHello larger than the default bufio buffer size.
+func largeNextProtos() []string {
+	var protos []string
+	for i := 0; i < 20; i++ {
+		protos = append(protos, fmt.Sprintf("%03d-%s", i, strings.Repeat("x", 250)))
+	}
+	return protos
+}
+
+// readClientHello returns the first TLS record sent by a client using the given configuration.
+func readClientHello(t *testing.T, config *tls.Config) []byte {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+	defer func() { _ = serverConn.Close() }()
+	defer func() { _ = clientConn.Close() }()
+
+	go func() {
+		_ = tls.Client(clientConn, config).Handshake()
+	}()
+
+	br := bufio.NewReader(serverConn)
+
+	hdr, err := br.Peek(recordHeaderLength)
+	require.NoError(t, err)
+
+	record, err := br.Peek(recordHeaderLength + (int(hdr[3])<<8 | int(hdr[4])))
+	require.NoError(t, err)
+
+	return append([]byte(nil), record...)
+}
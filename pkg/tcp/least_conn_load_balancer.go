@@ -0,0 +1,267 @@
+package tcp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+type leastConnServer struct {
+	Handler
+	name   string
+	weight int
+	active int
+	down   bool
+	// upSince is when the server came back up, which starts the ramp of its weight with a slow start.
+	upSince time.Time
+	// effectiveWeight is the weight of the server during a selection, ramping with a slow start.
+	effectiveWeight int
+	// maxConns is the maximum number of concurrent connections forwarded to the server, 0 meaning no limit.
+	maxConns int
+}
+
+// LeastConnLoadBalancer is a load balancer for TCP services forwarding the connections
+// to the server with the fewest active connections, relative to its weight.
+type LeastConnLoadBalancer struct {
+	servers []*leastConnServer
+	lock    sync.Mutex
+	// index is where the next lookup starts, so that the servers on par are selected in turn.
+	index int
+	// maxConns is the maximum number of concurrent connections of the balancer, 0 meaning no limit.
+	maxConns  int
+	active    int
+	slowStart *slowStart
+	updaters  statusUpdaters
+}
+
+// NewLeastConnLoadBalancer creates a new LeastConnLoadBalancer.
+func NewLeastConnLoadBalancer() *LeastConnLoadBalancer {
+	return &LeastConnLoadBalancer{}
+}
+
+// ServeTCP forwards the connection to the server with the fewest active connections.
+func (b *LeastConnLoadBalancer) ServeTCP(conn WriteCloser) {
+	srv, err := b.acquire()
+	if err != nil {
+		log.WithoutContext().Errorf("Error during load balancing: %v", err)
+		conn.Close()
+		return
+	}
+	defer b.release(srv)
+
+	srv.ServeTCP(conn)
+}
+
+// AddServer appends a server to the existing list.
+func (b *LeastConnLoadBalancer) AddServer(serverHandler Handler) {
+	w := 1
+	b.AddWeightServer(serverHandler, &w)
+}
+
+// AddWeightServer appends a server to the existing list with a weight.
+func (b *LeastConnLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
+	b.AddNamedServer("", serverHandler, weight)
+}
+
+// AddNamedServer appends a server to the existing list with a weight,
+// under a name which allows to change its status later on.
+func (b *LeastConnLoadBalancer) AddNamedServer(name string, serverHandler Handler, weight *int) {
+	w := 1
+	if weight != nil {
+		w = *weight
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.servers = append(b.servers, &leastConnServer{Handler: serverHandler, name: name, weight: w})
+}
+
+// SetStatus marks the named server as up or down.
+// A server down no longer receives new connections, while its active connections are left untouched.
+// A server coming back up starts the ramp of its weight, with a slow start.
+func (b *LeastConnLoadBalancer) SetStatus(name string, up bool) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	wasUp := b.hasServerUp()
+
+	found := false
+	for _, srv := range b.servers {
+		if srv.name == name {
+			if srv.down && up {
+				srv.upSince = time.Now()
+			}
+			srv.down = !up
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	if isUp := b.hasServerUp(); isUp != wasUp {
+		b.updaters.notify(isUp)
+	}
+
+	return nil
+}
+
+// RegisterStatusUpdater registers a function called when the balancer goes down, because all its servers are down,
+// or back up.
+func (b *LeastConnLoadBalancer) RegisterStatusUpdater(fn func(up bool)) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.updaters = append(b.updaters, fn)
+}
+
+func (b *LeastConnLoadBalancer) hasServerUp() bool {
+	for _, srv := range b.servers {
+		if !srv.down {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSlowStart ramps up, over the duration, the weight of the servers coming back up,
+// from the initial percentage of their weight. A zero duration disables the slow start.
+func (b *LeastConnLoadBalancer) SetSlowStart(duration time.Duration, initialPercent int) error {
+	ss, err := newSlowStart(duration, initialPercent)
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.slowStart = ss
+
+	return nil
+}
+
+// SetMaxConnections sets the maximum number of concurrent connections of the balancer, 0 meaning no limit.
+// Once it is reached, the new connections are closed.
+func (b *LeastConnLoadBalancer) SetMaxConnections(max int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.maxConns = max
+}
+
+// SetServerMaxConnections sets the maximum number of concurrent connections of the named server, 0 meaning no limit.
+// Once it is reached, the server is skipped until one of its connections ends.
+func (b *LeastConnLoadBalancer) SetServerMaxConnections(name string, max int) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	found := false
+	for _, srv := range b.servers {
+		if srv.name == name {
+			srv.maxConns = max
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	return nil
+}
+
+// acquire selects the server with the lowest ratio of active connections to weight,
+// the heaviest one on a tie, and counts the new connection.
+func (b *LeastConnLoadBalancer) acquire() (*leastConnServer, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if len(b.servers) == 0 {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	if b.maxConns > 0 && b.active >= b.maxConns {
+		return nil, fmt.Errorf("maximum number of connections reached (%d)", b.maxConns)
+	}
+
+	for _, srv := range b.servers {
+		srv.effectiveWeight = b.slowStart.weight(srv.weight, srv.upSince)
+	}
+
+	var selected *leastConnServer
+	selectedIndex := 0
+	allDown := true
+	for i := 0; i < len(b.servers); i++ {
+		index := (b.index + i) % len(b.servers)
+		srv := b.servers[index]
+		if srv.down {
+			continue
+		}
+		allDown = false
+
+		if srv.weight <= 0 {
+			continue
+		}
+
+		if srv.maxConns > 0 && srv.active >= srv.maxConns {
+			continue
+		}
+
+		if selected == nil || srv.lessLoadedThan(selected) {
+			selected = srv
+			selectedIndex = index
+		}
+	}
+
+	if allDown {
+		return nil, errors.New("all servers are down")
+	}
+
+	if selected == nil && b.hasWeightedServer() {
+		return nil, errors.New("all servers reached their maximum number of connections")
+	}
+
+	if selected == nil {
+		return nil, errors.New("all servers have 0 weight")
+	}
+
+	b.index = (selectedIndex + 1) % len(b.servers)
+	selected.active++
+	b.active++
+
+	return selected, nil
+}
+
+func (b *LeastConnLoadBalancer) release(srv *leastConnServer) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	srv.active--
+	b.active--
+}
+
+// hasWeightedServer reports whether a server up has a weight, b.lock being held.
+func (b *LeastConnLoadBalancer) hasWeightedServer() bool {
+	for _, srv := range b.servers {
+		if !srv.down && srv.weight > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// lessLoadedThan compares the ratios of active connections to weight of the servers,
+// without dividing them.
+func (s *leastConnServer) lessLoadedThan(other *leastConnServer) bool {
+	load, otherLoad := s.active*other.effectiveWeight, other.active*s.effectiveWeight
+	if load != otherLoad {
+		return load < otherLoad
+	}
+
+	return s.effectiveWeight > other.effectiveWeight
+}
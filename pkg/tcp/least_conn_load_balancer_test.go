@@ -0,0 +1,185 @@
+package tcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeastConnLoadBalancer_acquire(t *testing.T) {
+	type server struct {
+		name   string
+		weight int
+		active int
+	}
+
+	testCases := []struct {
+		desc     string
+		servers  []server
+		acquired int
+		expected map[string]int
+	}{
+		{
+			desc: "same weights",
+			servers: []server{
+				{name: "h1", weight: 1},
+				{name: "h2", weight: 1},
+			},
+			acquired: 4,
+			expected: map[string]int{"h1": 2, "h2": 2},
+		},
+		{
+			desc: "same weights, with active connections",
+			servers: []server{
+				{name: "h1", weight: 1, active: 3},
+				{name: "h2", weight: 1},
+			},
+			acquired: 5,
+			expected: map[string]int{"h1": 4, "h2": 4},
+		},
+		{
+			desc: "weights scale the connections",
+			servers: []server{
+				{name: "h1", weight: 3},
+				{name: "h2", weight: 1},
+			},
+			acquired: 8,
+			expected: map[string]int{"h1": 6, "h2": 2},
+		},
+		{
+			desc: "the heaviest server wins a tie",
+			servers: []server{
+				{name: "h1", weight: 1},
+				{name: "h2", weight: 2},
+			},
+			acquired: 1,
+			expected: map[string]int{"h1": 0, "h2": 1},
+		},
+		{
+			desc: "0 weight server",
+			servers: []server{
+				{name: "h1", weight: 0},
+				{name: "h2", weight: 1},
+			},
+			acquired: 3,
+			expected: map[string]int{"h1": 0, "h2": 3},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := NewLeastConnLoadBalancer()
+			for _, srv := range test.servers {
+				srv := srv
+				balancer.AddWeightServer(HandlerFunc(func(conn WriteCloser) {}), &srv.weight)
+				balancer.servers[len(balancer.servers)-1].active = srv.active
+			}
+
+			for i := 0; i < test.acquired; i++ {
+				_, err := balancer.acquire()
+				require.NoError(t, err)
+			}
+
+			active := make(map[string]int)
+			for i, srv := range test.servers {
+				active[srv.name] = balancer.servers[i].active
+			}
+
+			assert.Equal(t, test.expected, active)
+		})
+	}
+}
+
+func TestLeastConnLoadBalancer_ServeTCP(t *testing.T) {
+	balancer := NewLeastConnLoadBalancer()
+	for _, server := range []string{"h1", "h2"} {
+		server := server
+		balancer.AddServer(HandlerFunc(func(conn WriteCloser) {
+			_, err := conn.Write([]byte(server))
+			require.NoError(t, err)
+		}))
+	}
+
+	conn := &fakeConn{call: make(map[string]int)}
+	for i := 0; i < 4; i++ {
+		balancer.ServeTCP(conn)
+	}
+
+	// The connections are over, so the servers are selected in turn.
+	assert.Equal(t, map[string]int{"h1": 2, "h2": 2}, conn.call)
+
+	for _, srv := range balancer.servers {
+		assert.Equal(t, 0, srv.active)
+	}
+}
+
+func TestLeastConnLoadBalancer_SetStatus(t *testing.T) {
+	balancer := NewLeastConnLoadBalancer()
+	for _, server := range []string{"h1", "h2"} {
+		server := server
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {
+			_, err := conn.Write([]byte(server))
+			require.NoError(t, err)
+		}), nil)
+	}
+
+	require.NoError(t, balancer.SetStatus("h1", false))
+
+	conn := &fakeConn{call: make(map[string]int)}
+	for i := 0; i < 4; i++ {
+		balancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h2": 4}, conn.call)
+
+	require.NoError(t, balancer.SetStatus("h2", false))
+
+	_, err := balancer.acquire()
+	assert.Error(t, err)
+
+	assert.Error(t, balancer.SetStatus("h3", false))
+}
+
+func TestLeastConnLoadBalancer_maxConnections(t *testing.T) {
+	balancer := NewLeastConnLoadBalancer()
+	for _, server := range []string{"h1", "h2"} {
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {}), nil)
+	}
+
+	require.NoError(t, balancer.SetServerMaxConnections("h1", 1))
+	require.NoError(t, balancer.SetServerMaxConnections("h2", 2))
+	assert.Error(t, balancer.SetServerMaxConnections("h3", 1))
+
+	var acquired []string
+	for i := 0; i < 3; i++ {
+		srv, err := balancer.acquire()
+		require.NoError(t, err)
+		acquired = append(acquired, srv.name)
+	}
+	assert.Equal(t, []string{"h1", "h2", "h2"}, acquired)
+
+	_, err := balancer.acquire()
+	assert.Error(t, err)
+
+	balancer.release(balancer.servers[0])
+
+	srv, err := balancer.acquire()
+	require.NoError(t, err)
+	assert.Equal(t, "h1", srv.name)
+
+	balancer.release(srv)
+	balancer.SetMaxConnections(2)
+
+	_, err = balancer.acquire()
+	assert.Error(t, err)
+}
+
+func TestLeastConnLoadBalancer_noServer(t *testing.T) {
+	balancer := NewLeastConnLoadBalancer()
+
+	_, err := balancer.acquire()
+	assert.Error(t, err)
+}
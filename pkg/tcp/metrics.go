@@ -0,0 +1,135 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/metrics"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+// NewRouterMetricsHandler returns a handler recording the open connections, the bytes exchanged with the clients,
+// and the duration of the connections of the given router.
+func NewRouterMetricsHandler(registry metrics.Registry, routerName string, next Handler) Handler {
+	openConns := registry.TCPRouterOpenConnsGauge().With("router", routerName)
+	readBytes := registry.TCPRouterReadBytesCounter().With("router", routerName)
+	writtenBytes := registry.TCPRouterWrittenBytesCounter().With("router", routerName)
+	durations := registry.TCPRouterConnDurationHistogram().With("router", routerName)
+
+	return HandlerFunc(func(conn WriteCloser) {
+		start := time.Now()
+
+		openConns.Add(1)
+		defer func() {
+			openConns.Add(-1)
+			durations.ObserveFromStart(start)
+		}()
+
+		next.ServeTCP(&meteredConn{WriteCloser: conn, readBytes: readBytes, writtenBytes: writtenBytes})
+	})
+}
+
+// NewServerMetricsHandler returns a handler recording the open connections to the given server of a service.
+func NewServerMetricsHandler(registry metrics.Registry, serviceName, address string, next Handler) Handler {
+	serviceOpenConns := registry.TCPServiceOpenConnsGauge().With("service", serviceName)
+	serverOpenConns := registry.TCPServiceServerOpenConnsGauge().With("service", serviceName, "address", address)
+
+	return HandlerFunc(func(conn WriteCloser) {
+		serviceOpenConns.Add(1)
+		serverOpenConns.Add(1)
+		defer func() {
+			serviceOpenConns.Add(-1)
+			serverOpenConns.Add(-1)
+		}()
+
+		next.ServeTCP(conn)
+	})
+}
+
+// TLSHandshakeMetrics records the durations of the TLS handshakes of an entry point,
+// the resumed sessions, and the HelloRetryRequests sent.
+type TLSHandshakeMetrics struct {
+	entryPointName     string
+	durations          metrics.ScalableHistogram
+	handshakes         gokitmetrics.Counter
+	helloRetryRequests gokitmetrics.Counter
+}
+
+// NewTLSHandshakeMetrics creates the TLS handshake metrics of the given entry point.
+func NewTLSHandshakeMetrics(registry metrics.Registry, entryPointName string) *TLSHandshakeMetrics {
+	return &TLSHandshakeMetrics{
+		entryPointName:     entryPointName,
+		durations:          registry.EntryPointTLSHandshakeDurationHistogram(),
+		handshakes:         registry.EntryPointTLSHandshakesCounter(),
+		helloRetryRequests: registry.EntryPointTLSHelloRetryRequestsCounter().With("entrypoint", entryPointName),
+	}
+}
+
+// record records a completed TLS handshake.
+func (m *TLSHandshakeMetrics) record(state tls.ConnectionState, helloRetryRequest bool, start time.Time) {
+	version := tlsVersion(state.Version)
+
+	// With TLS 1.3 the sessions are resumed with pre-shared keys, and with session tickets otherwise.
+	resumption := "none"
+	if state.DidResume {
+		resumption = "ticket"
+		if state.Version == tls.VersionTLS13 {
+			resumption = "psk"
+		}
+	}
+
+	m.durations.With("tls_version", version, "entrypoint", m.entryPointName).ObserveFromStart(start)
+	m.handshakes.With("tls_version", version, "resumption", resumption, "entrypoint", m.entryPointName).Add(1)
+
+	if helloRetryRequest {
+		m.helloRetryRequests.Add(1)
+	}
+}
+
+func tlsVersion(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "1.0"
+	case tls.VersionTLS11:
+		return "1.1"
+	case tls.VersionTLS12:
+		return "1.2"
+	case tls.VersionTLS13:
+		return "1.3"
+	default:
+		return "unknown"
+	}
+}
+
+// meteredConn counts the bytes exchanged with the client.
+type meteredConn struct {
+	WriteCloser
+	readBytes    gokitmetrics.Counter
+	writtenBytes gokitmetrics.Counter
+}
+
+func (c *meteredConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	if n > 0 {
+		c.readBytes.Add(float64(n))
+	}
+	return n, err
+}
+
+func (c *meteredConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	if n > 0 {
+		c.writtenBytes.Add(float64(n))
+	}
+	return n, err
+}
+
+func (c *meteredConn) setBackend(address string) {
+	if conn, ok := c.WriteCloser.(backendSetter); ok {
+		conn.setBackend(address)
+	}
+}
+
+func (c *meteredConn) unwrapConn() WriteCloser {
+	return c.WriteCloser
+}
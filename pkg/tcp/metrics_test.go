@@ -0,0 +1,188 @@
+package tcp
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/metrics"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterMetricsHandler(t *testing.T) {
+	registry := newRegistryMock()
+
+	handler := NewRouterMetricsHandler(registry, "foo@file", HandlerFunc(func(conn WriteCloser) {
+		assert.Equal(t, float64(1), registry.routerOpenConns.value)
+
+		buf := make([]byte, 5)
+		_, err := conn.Read(buf)
+		require.NoError(t, err)
+
+		_, err = conn.Write([]byte("world!"))
+		require.NoError(t, err)
+	}))
+
+	client, server := net.Pipe()
+
+	go func() {
+		_, err := client.Write([]byte("hello"))
+		require.NoError(t, err)
+
+		_, err = ioutil.ReadAll(client)
+		require.NoError(t, err)
+	}()
+
+	handler.ServeTCP(pipeConn{Conn: server})
+	require.NoError(t, server.Close())
+
+	assert.Equal(t, float64(0), registry.routerOpenConns.value)
+	assert.Equal(t, float64(5), registry.routerReadBytes.value)
+	assert.Equal(t, float64(6), registry.routerWrittenBytes.value)
+	assert.Equal(t, 1, registry.routerConnDurations.count)
+
+	assert.Equal(t, []string{"router", "foo@file"}, registry.routerOpenConns.labelValues)
+	assert.Equal(t, []string{"router", "foo@file"}, registry.routerReadBytes.labelValues)
+	assert.Equal(t, []string{"router", "foo@file"}, registry.routerWrittenBytes.labelValues)
+	assert.Equal(t, []string{"router", "foo@file"}, registry.routerConnDurations.labelValues)
+}
+
+func TestServerMetricsHandler(t *testing.T) {
+	registry := newRegistryMock()
+
+	handler := NewServerMetricsHandler(registry, "foo@file", "10.0.0.1:80", HandlerFunc(func(conn WriteCloser) {
+		assert.Equal(t, float64(1), registry.serviceOpenConns.value)
+		assert.Equal(t, float64(1), registry.serverOpenConns.value)
+	}))
+
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+
+	handler.ServeTCP(pipeConn{Conn: server})
+	require.NoError(t, server.Close())
+
+	assert.Equal(t, float64(0), registry.serviceOpenConns.value)
+	assert.Equal(t, float64(0), registry.serverOpenConns.value)
+
+	assert.Equal(t, []string{"service", "foo@file"}, registry.serviceOpenConns.labelValues)
+	assert.Equal(t, []string{"service", "foo@file", "address", "10.0.0.1:80"}, registry.serverOpenConns.labelValues)
+}
+
+type registryMock struct {
+	metrics.Registry
+
+	routerOpenConns     *gaugeMock
+	routerReadBytes     *counterMock
+	routerWrittenBytes  *counterMock
+	routerConnDurations *histogramMock
+	serviceOpenConns    *gaugeMock
+	serverOpenConns     *gaugeMock
+
+	tlsHandshakeDurations *histogramMock
+	tlsHandshakes         *counterMock
+	tlsHelloRetryRequests *counterMock
+}
+
+func newRegistryMock() *registryMock {
+	return &registryMock{
+		routerOpenConns:     &gaugeMock{},
+		routerReadBytes:     &counterMock{},
+		routerWrittenBytes:  &counterMock{},
+		routerConnDurations: &histogramMock{},
+		serviceOpenConns:    &gaugeMock{},
+		serverOpenConns:     &gaugeMock{},
+
+		tlsHandshakeDurations: &histogramMock{},
+		tlsHandshakes:         &counterMock{},
+		tlsHelloRetryRequests: &counterMock{},
+	}
+}
+
+func (r *registryMock) TCPRouterOpenConnsGauge() gokitmetrics.Gauge {
+	return r.routerOpenConns
+}
+
+func (r *registryMock) TCPRouterReadBytesCounter() gokitmetrics.Counter {
+	return r.routerReadBytes
+}
+
+func (r *registryMock) TCPRouterWrittenBytesCounter() gokitmetrics.Counter {
+	return r.routerWrittenBytes
+}
+
+func (r *registryMock) TCPRouterConnDurationHistogram() metrics.ScalableHistogram {
+	return r.routerConnDurations
+}
+
+func (r *registryMock) TCPServiceOpenConnsGauge() gokitmetrics.Gauge {
+	return r.serviceOpenConns
+}
+
+func (r *registryMock) TCPServiceServerOpenConnsGauge() gokitmetrics.Gauge {
+	return r.serverOpenConns
+}
+
+func (r *registryMock) EntryPointTLSHandshakeDurationHistogram() metrics.ScalableHistogram {
+	return r.tlsHandshakeDurations
+}
+
+func (r *registryMock) EntryPointTLSHandshakesCounter() gokitmetrics.Counter {
+	return r.tlsHandshakes
+}
+
+func (r *registryMock) EntryPointTLSHelloRetryRequestsCounter() gokitmetrics.Counter {
+	return r.tlsHelloRetryRequests
+}
+
+type gaugeMock struct {
+	value       float64
+	labelValues []string
+}
+
+func (g *gaugeMock) With(labelValues ...string) gokitmetrics.Gauge {
+	g.labelValues = labelValues
+	return g
+}
+
+func (g *gaugeMock) Set(value float64) {
+	g.value = value
+}
+
+func (g *gaugeMock) Add(delta float64) {
+	g.value += delta
+}
+
+type counterMock struct {
+	value       float64
+	labelValues []string
+}
+
+func (c *counterMock) With(labelValues ...string) gokitmetrics.Counter {
+	c.labelValues = labelValues
+	return c
+}
+
+func (c *counterMock) Add(delta float64) {
+	c.value += delta
+}
+
+type histogramMock struct {
+	count       int
+	labelValues []string
+}
+
+func (h *histogramMock) With(labelValues ...string) metrics.ScalableHistogram {
+	h.labelValues = labelValues
+	return h
+}
+
+func (h *histogramMock) Observe(float64) {
+	h.count++
+}
+
+func (h *histogramMock) ObserveFromStart(time.Time) {
+	h.count++
+}
@@ -0,0 +1,228 @@
+package tcp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/safe"
+)
+
+var errMirrorBufferFull = errors.New("mirror buffer full")
+
+// Mirroring is a TCP handler forwarding the connections to a main handler,
+// and duplicating the bytes sent by the clients to mirror handlers, whose responses are discarded.
+// The mirroring is best-effort: a mirror which does not keep up with the client loses its connection,
+// without slowing down the main one.
+type Mirroring struct {
+	handler    Handler
+	mirrors    []*mirrorHandler
+	bufferSize int
+
+	mu    sync.Mutex
+	total uint64
+}
+
+type mirrorHandler struct {
+	Handler
+	percent int
+	count   uint64
+}
+
+// NewMirroring creates a new Mirroring, buffering at most bufferSize bytes per mirrored connection.
+func NewMirroring(handler Handler, bufferSize int) *Mirroring {
+	return &Mirroring{handler: handler, bufferSize: bufferSize}
+}
+
+// AddMirror adds a handler receiving the given percentage of the connections.
+func (m *Mirroring) AddMirror(handler Handler, percent int) error {
+	if percent < 0 || percent > 100 {
+		return errors.New("percent must be between 0 and 100")
+	}
+
+	m.mirrors = append(m.mirrors, &mirrorHandler{Handler: handler, percent: percent})
+	return nil
+}
+
+// ServeTCP forwards the connection to the main handler, and duplicates it to the active mirrors.
+func (m *Mirroring) ServeTCP(conn WriteCloser) {
+	mirrors := m.getActiveMirrors()
+	if len(mirrors) == 0 {
+		m.handler.ServeTCP(conn)
+		return
+	}
+
+	tee := &teeConn{WriteCloser: conn}
+	for _, mirror := range mirrors {
+		mirrorConn := newMirrorConn(conn, m.bufferSize)
+		tee.mirrors = append(tee.mirrors, mirrorConn)
+
+		mirror := mirror
+		safe.Go(func() {
+			mirror.ServeTCP(mirrorConn)
+		})
+	}
+
+	m.handler.ServeTCP(tee)
+
+	// The main handler may return without reading the whole stream.
+	tee.closeMirrors()
+}
+
+func (m *Mirroring) getActiveMirrors() []Handler {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.total++
+
+	var mirrors []Handler
+	for _, mirror := range m.mirrors {
+		if mirror.count*100 < m.total*uint64(mirror.percent) {
+			mirror.count++
+			mirrors = append(mirrors, mirror)
+		}
+	}
+	return mirrors
+}
+
+// teeConn copies the bytes read from the client to the mirrored connections.
+type teeConn struct {
+	WriteCloser
+	mirrors []*mirrorConn
+}
+
+func (c *teeConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+
+	for _, mirror := range c.mirrors {
+		if n > 0 {
+			mirror.feed(p[:n])
+		}
+		if err != nil {
+			mirror.closeFeed()
+		}
+	}
+
+	return n, err
+}
+
+func (c *teeConn) setBackend(address string) {
+	if conn, ok := c.WriteCloser.(backendSetter); ok {
+		conn.setBackend(address)
+	}
+}
+
+func (c *teeConn) unwrapConn() WriteCloser {
+	return c.WriteCloser
+}
+
+func (c *teeConn) closeMirrors() {
+	for _, mirror := range c.mirrors {
+		mirror.closeFeed()
+	}
+}
+
+// mirrorConn is the connection handed to a mirror: it reads the bytes sent by the client from a bounded buffer,
+// and discards the bytes written to it.
+type mirrorConn struct {
+	WriteCloser // the client connection, only used for its addresses.
+
+	maxSize int
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buffer bytes.Buffer
+	// eof is set when the client stream ends.
+	eof bool
+	// err is set when the connection is closed by the mirror, or when the buffer overflows.
+	err error
+}
+
+func newMirrorConn(conn WriteCloser, maxSize int) *mirrorConn {
+	c := &mirrorConn{WriteCloser: conn, maxSize: maxSize}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *mirrorConn) feed(p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil || c.eof {
+		return
+	}
+
+	if c.buffer.Len()+len(p) > c.maxSize {
+		log.WithoutContext().Debugf("Stopping the mirroring of the connection from %s: %v", c.RemoteAddr(), errMirrorBufferFull)
+		c.err = errMirrorBufferFull
+		c.buffer.Reset()
+		c.cond.Broadcast()
+		return
+	}
+
+	c.buffer.Write(p)
+	c.cond.Broadcast()
+}
+
+func (c *mirrorConn) closeFeed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.eof = true
+	c.cond.Broadcast()
+}
+
+func (c *mirrorConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.buffer.Len() == 0 && c.err == nil && !c.eof {
+		c.cond.Wait()
+	}
+
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	if c.buffer.Len() == 0 {
+		return 0, io.EOF
+	}
+
+	return c.buffer.Read(p)
+}
+
+func (c *mirrorConn) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (c *mirrorConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err == nil {
+		c.err = io.ErrClosedPipe
+	}
+	c.buffer.Reset()
+	c.cond.Broadcast()
+
+	return nil
+}
+
+func (c *mirrorConn) CloseWrite() error {
+	return nil
+}
+
+func (c *mirrorConn) SetDeadline(time.Time) error {
+	return nil
+}
+
+func (c *mirrorConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+func (c *mirrorConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
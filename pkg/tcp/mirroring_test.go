@@ -0,0 +1,92 @@
+package tcp
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirroring(t *testing.T) {
+	mainData := make(chan string, 1)
+	mirroring := NewMirroring(HandlerFunc(func(conn WriteCloser) {
+		data, err := ioutil.ReadAll(conn)
+		require.NoError(t, err)
+		mainData <- string(data)
+	}), 1024)
+
+	mirrorData := make(chan string, 1)
+	err := mirroring.AddMirror(HandlerFunc(func(conn WriteCloser) {
+		data, err := ioutil.ReadAll(conn)
+		require.NoError(t, err)
+
+		_, err = conn.Write([]byte("discarded"))
+		require.NoError(t, err)
+
+		mirrorData <- string(data)
+	}), 100)
+	require.NoError(t, err)
+
+	serve(t, mirroring, "hello world")
+
+	assert.Equal(t, "hello world", <-mainData)
+	assert.Equal(t, "hello world", <-mirrorData)
+}
+
+func TestMirroring_bufferFull(t *testing.T) {
+	release := make(chan struct{})
+	mirroring := NewMirroring(HandlerFunc(func(conn WriteCloser) {
+		_, err := ioutil.ReadAll(conn)
+		require.NoError(t, err)
+		close(release)
+	}), 4)
+
+	mirrorErr := make(chan error, 1)
+	err := mirroring.AddMirror(HandlerFunc(func(conn WriteCloser) {
+		<-release
+		_, err := ioutil.ReadAll(conn)
+		mirrorErr <- err
+	}), 100)
+	require.NoError(t, err)
+
+	serve(t, mirroring, "hello world")
+
+	assert.Equal(t, errMirrorBufferFull, <-mirrorErr)
+}
+
+func TestMirroring_percent(t *testing.T) {
+	mirroring := NewMirroring(HandlerFunc(func(conn WriteCloser) {}), 1024)
+
+	noop := HandlerFunc(func(conn WriteCloser) {})
+	require.NoError(t, mirroring.AddMirror(noop, 10))
+	require.NoError(t, mirroring.AddMirror(noop, 50))
+	require.NoError(t, mirroring.AddMirror(noop, 0))
+
+	for i := 0; i < 100; i++ {
+		mirroring.getActiveMirrors()
+	}
+
+	assert.Equal(t, uint64(10), mirroring.mirrors[0].count)
+	assert.Equal(t, uint64(50), mirroring.mirrors[1].count)
+	assert.Equal(t, uint64(0), mirroring.mirrors[2].count)
+
+	assert.Error(t, mirroring.AddMirror(noop, 101))
+	assert.Error(t, mirroring.AddMirror(noop, -1))
+}
+
+// serve makes the handler serve a connection on which the given data is sent.
+func serve(t *testing.T, handler Handler, data string) {
+	t.Helper()
+
+	client, server := net.Pipe()
+
+	go func() {
+		_, err := client.Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, client.Close())
+	}()
+
+	handler.ServeTCP(pipeConn{Conn: server})
+}
@@ -8,20 +8,39 @@ import (
 	"github.com/containous/traefik/v2/pkg/log"
 )
 
+// backendSetter is implemented by the connections recording the backend they are forwarded to.
+type backendSetter interface {
+	setBackend(address string)
+}
+
+// connUnwrapper is implemented by the connections wrapping the client connection,
+// so that the TLS information of the client connection can be reported to the backend.
+type connUnwrapper interface {
+	unwrapConn() WriteCloser
+}
+
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
 	target           *net.TCPAddr
 	terminationDelay time.Duration
+	proxyProtocol    *ProxyProtocol
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string, terminationDelay time.Duration) (*Proxy, error) {
+// When proxyProtocol is not nil, a PROXY protocol header is sent to the backend before the data of the connection.
+func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *ProxyProtocol) (*Proxy, error) {
+	if proxyProtocol != nil {
+		if err := proxyProtocol.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	tcpAddr, err := net.ResolveTCPAddr("tcp", address)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Proxy{target: tcpAddr, terminationDelay: terminationDelay}, nil
+	return &Proxy{target: tcpAddr, terminationDelay: terminationDelay, proxyProtocol: proxyProtocol}, nil
 }
 
 // ServeTCP forwards the connection to a service.
@@ -31,6 +50,16 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
+	var header []byte
+	if p.proxyProtocol != nil {
+		var err error
+		header, err = p.proxyProtocol.header(conn)
+		if err != nil {
+			log.Errorf("Error while building the PROXY protocol header: %v", err)
+			return
+		}
+	}
+
 	connBackend, err := net.DialTCP("tcp", nil, p.target)
 	if err != nil {
 		log.Errorf("Error while connection to backend: %v", err)
@@ -40,6 +69,17 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 
+	if header != nil {
+		if _, err := connBackend.Write(header); err != nil {
+			log.Errorf("Error while sending the PROXY protocol header: %v", err)
+			return
+		}
+	}
+
+	if tracked, ok := conn.(backendSetter); ok {
+		tracked.setBackend(p.target.String())
+	}
+
 	errChan := make(chan error)
 	go p.connCopy(conn, connBackend, errChan)
 	go p.connCopy(connBackend, conn, errChan)
@@ -0,0 +1,313 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// PROXY protocol version 2 constants, cf https://www.haproxy.org/download/2.3/doc/proxy-protocol.txt.
+const (
+	pp2CommandProxy = 0x21
+
+	pp2FamilyUnspec = 0x00
+	pp2FamilyTCP4   = 0x11
+	pp2FamilyTCP6   = 0x21
+
+	pp2TypeALPN      = 0x01
+	pp2TypeAuthority = 0x02
+	pp2TypeSSL       = 0x20
+	pp2TypeMinCustom = 0xE0
+	pp2TypeMaxCustom = 0xEF
+
+	pp2SubtypeSSLVersion = 0x21
+	pp2SubtypeSSLCN      = 0x22
+	pp2SubtypeSSLCipher  = 0x23
+
+	pp2ClientSSL      = 0x01
+	pp2ClientCertConn = 0x02
+	pp2ClientCertSess = 0x04
+)
+
+// tlsHandshakeTimeout bounds the handshake of a TLS connection terminated by Traefik, completed to report its TLS information,
+// so that a client stalling it does not hold the connection forever.
+var tlsHandshakeTimeout = 10 * time.Second
+
+var pp2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLSv1",
+	tls.VersionTLS11: "TLSv1.1",
+	tls.VersionTLS12: "TLSv1.2",
+	tls.VersionTLS13: "TLSv1.3",
+}
+
+// ProxyProtocol holds the PROXY protocol header sent to the backend, before the data of the connection.
+type ProxyProtocol struct {
+	// Version is the version of the PROXY protocol, 1 or 2.
+	Version int
+}
+
+// ProxyProtocolTLV is a custom TLV of the PROXY protocol version 2 headers.
+type ProxyProtocolTLV struct {
+	Type  int
+	Value []byte
+}
+
+// Validate checks the PROXY protocol options.
+func (p *ProxyProtocol) Validate() error {
+	if p.Version != 1 && p.Version != 2 {
+		return fmt.Errorf("unsupported PROXY protocol version %d", p.Version)
+	}
+
+	return nil
+}
+
+// header builds the PROXY protocol header of the connection.
+// The version 2 headers hold the TLS information of the connection and the custom TLVs of its router.
+func (p *ProxyProtocol) header(conn WriteCloser) ([]byte, error) {
+	src, dst := tcpAddr(conn.RemoteAddr()), tcpAddr(conn.LocalAddr())
+	if src != nil && dst != nil && (src.IP.To4() == nil) != (dst.IP.To4() == nil) {
+		src, dst = nil, nil
+	}
+
+	if p.Version == 1 {
+		return headerV1(src, dst), nil
+	}
+
+	tlvs, err := tlsTLVs(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tlv := range routerTLVs(conn) {
+		tlvs = appendTLV(tlvs, byte(tlv.Type), tlv.Value)
+	}
+
+	return headerV2(src, dst, tlvs)
+}
+
+func headerV1(src, dst *net.TCPAddr) []byte {
+	if src == nil || dst == nil {
+		return []byte("PROXY UNKNOWN\r\n")
+	}
+
+	protocol := "TCP4"
+	if src.IP.To4() == nil {
+		protocol = "TCP6"
+	}
+
+	return []byte(fmt.Sprintf("PROXY %s %s %s %d %d\r\n", protocol, src.IP, dst.IP, src.Port, dst.Port))
+}
+
+func headerV2(src, dst *net.TCPAddr, tlvs []byte) ([]byte, error) {
+	family := byte(pp2FamilyUnspec)
+	var addresses []byte
+
+	switch {
+	case src == nil || dst == nil:
+	case src.IP.To4() != nil:
+		family = pp2FamilyTCP4
+		addresses = append(addresses, src.IP.To4()...)
+		addresses = append(addresses, dst.IP.To4()...)
+	default:
+		family = pp2FamilyTCP6
+		addresses = append(addresses, src.IP.To16()...)
+		addresses = append(addresses, dst.IP.To16()...)
+	}
+
+	if family != pp2FamilyUnspec {
+		addresses = appendUint16(addresses, uint16(src.Port))
+		addresses = appendUint16(addresses, uint16(dst.Port))
+	}
+
+	length := len(addresses) + len(tlvs)
+	if length > 0xFFFF {
+		return nil, fmt.Errorf("PROXY protocol header too long: %d bytes", length)
+	}
+
+	header := make([]byte, 0, len(pp2Signature)+4+length)
+	header = append(header, pp2Signature...)
+	header = append(header, pp2CommandProxy, family)
+	header = appendUint16(header, uint16(length))
+	header = append(header, addresses...)
+	header = append(header, tlvs...)
+
+	return header, nil
+}
+
+// tlsTLVs returns the TLVs describing the TLS connection of the client:
+// all the TLS information when Traefik terminates it, or the server name of the ClientHello when it is passed through.
+func tlsTLVs(conn WriteCloser) ([]byte, error) {
+	for conn != nil {
+		switch c := conn.(type) {
+		case *tls.Conn:
+			if !c.ConnectionState().HandshakeComplete {
+				if err := handshake(c); err != nil {
+					return nil, fmt.Errorf("TLS handshake: %w", err)
+				}
+			}
+
+			return connectionStateTLVs(c.ConnectionState()), nil
+
+		case *Conn:
+			// The ClientHello of a passed through TLS connection is still in the peeked bytes.
+			if len(c.Peeked) > 0 && c.Peeked[0] == handshakeRecordType {
+				var tlvs []byte
+				if hello, err := parseClientHello(c.Peeked); err == nil && hello.ServerName != "" {
+					tlvs = appendTLV(tlvs, pp2TypeAuthority, []byte(hello.ServerName))
+				}
+				return tlvs, nil
+			}
+
+			conn = c.WriteCloser
+
+		case connUnwrapper:
+			conn = c.unwrapConn()
+
+		default:
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// handshake completes the handshake of a TLS connection, within tlsHandshakeTimeout.
+func handshake(conn *tls.Conn) error {
+	if err := conn.SetDeadline(time.Now().Add(tlsHandshakeTimeout)); err != nil {
+		return err
+	}
+
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+
+	return conn.SetDeadline(time.Time{})
+}
+
+// routerTLVs returns the custom TLVs of the router of the connection, if any.
+func routerTLVs(conn WriteCloser) []ProxyProtocolTLV {
+	for conn != nil {
+		switch c := conn.(type) {
+		case *tlvConn:
+			return c.tlvs
+		case connUnwrapper:
+			conn = c.unwrapConn()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func connectionStateTLVs(state tls.ConnectionState) []byte {
+	var tlvs []byte
+
+	if state.NegotiatedProtocol != "" {
+		tlvs = appendTLV(tlvs, pp2TypeALPN, []byte(state.NegotiatedProtocol))
+	}
+
+	if state.ServerName != "" {
+		tlvs = appendTLV(tlvs, pp2TypeAuthority, []byte(state.ServerName))
+	}
+
+	client := byte(pp2ClientSSL)
+	verify := uint32(1)
+	if len(state.PeerCertificates) > 0 {
+		client |= pp2ClientCertSess
+		if !state.DidResume {
+			client |= pp2ClientCertConn
+		}
+	}
+	if len(state.VerifiedChains) > 0 {
+		verify = 0
+	}
+
+	ssl := []byte{client, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(ssl[1:], verify)
+
+	if version, ok := tlsVersionNames[state.Version]; ok {
+		ssl = appendTLV(ssl, pp2SubtypeSSLVersion, []byte(version))
+	}
+
+	if len(state.PeerCertificates) > 0 && state.PeerCertificates[0].Subject.CommonName != "" {
+		ssl = appendTLV(ssl, pp2SubtypeSSLCN, []byte(state.PeerCertificates[0].Subject.CommonName))
+	}
+
+	ssl = appendTLV(ssl, pp2SubtypeSSLCipher, []byte(tls.CipherSuiteName(state.CipherSuite)))
+
+	return appendTLV(tlvs, pp2TypeSSL, ssl)
+}
+
+func appendTLV(b []byte, typ byte, value []byte) []byte {
+	b = append(b, typ)
+	b = appendUint16(b, uint16(len(value)))
+	return append(b, value...)
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+// tcpAddr returns the IP address and port of the given address, or nil if it is not an IP address.
+func tcpAddr(addr net.Addr) *net.TCPAddr {
+	if addr == nil {
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return nil
+	}
+
+	return &net.TCPAddr{IP: ip, Port: portNum}
+}
+
+// NewProxyProtocolTLVsHandler adds the given custom TLVs to the PROXY protocol version 2 headers
+// sent to the servers of the services the connections are forwarded to.
+func NewProxyProtocolTLVsHandler(tlvs []ProxyProtocolTLV, next Handler) (Handler, error) {
+	for _, tlv := range tlvs {
+		if tlv.Type < pp2TypeMinCustom || tlv.Type > pp2TypeMaxCustom {
+			return nil, fmt.Errorf("invalid PROXY protocol TLV type %#x: the custom TLVs range from %#x to %#x", tlv.Type, pp2TypeMinCustom, pp2TypeMaxCustom)
+		}
+
+		if len(tlv.Value) > 0xFFFF {
+			return nil, fmt.Errorf("PROXY protocol TLV %#x too long: %d bytes", tlv.Type, len(tlv.Value))
+		}
+	}
+
+	return HandlerFunc(func(conn WriteCloser) {
+		next.ServeTCP(&tlvConn{WriteCloser: conn, tlvs: tlvs})
+	}), nil
+}
+
+// tlvConn holds the custom TLVs of the router of a connection.
+type tlvConn struct {
+	WriteCloser
+	tlvs []ProxyProtocolTLV
+}
+
+func (c *tlvConn) setBackend(address string) {
+	if conn, ok := c.WriteCloser.(backendSetter); ok {
+		conn.setBackend(address)
+	}
+}
+
+func (c *tlvConn) unwrapConn() WriteCloser {
+	return c.WriteCloser
+}
@@ -0,0 +1,272 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/tls/generate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addrConn is a connection only used for its addresses.
+type addrConn struct {
+	WriteCloser
+	remote net.Addr
+	local  net.Addr
+}
+
+func (c addrConn) RemoteAddr() net.Addr { return c.remote }
+
+func (c addrConn) LocalAddr() net.Addr { return c.local }
+
+func TestProxyProtocol_Validate(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		version       int
+		expectedError bool
+	}{
+		{
+			desc:    "version 1",
+			version: 1,
+		},
+		{
+			desc:    "version 2",
+			version: 2,
+		},
+		{
+			desc:          "unsupported version",
+			version:       3,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			proxyProtocol := &ProxyProtocol{Version: test.version}
+
+			err := proxyProtocol.Validate()
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewProxyProtocolTLVsHandler(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		tlvs          []ProxyProtocolTLV
+		expectedError bool
+	}{
+		{
+			desc: "custom TLV",
+			tlvs: []ProxyProtocolTLV{{Type: 0xE0, Value: []byte("foo")}},
+		},
+		{
+			desc:          "TLV type out of the custom range",
+			tlvs:          []ProxyProtocolTLV{{Type: pp2TypeAuthority, Value: []byte("foo")}},
+			expectedError: true,
+		},
+		{
+			desc:          "TLV too long",
+			tlvs:          []ProxyProtocolTLV{{Type: 0xE0, Value: make([]byte, 0x10000)}},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var tlvs []ProxyProtocolTLV
+			handler, err := NewProxyProtocolTLVsHandler(test.tlvs, HandlerFunc(func(conn WriteCloser) {
+				tlvs = routerTLVs(conn)
+			}))
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			handler.ServeTCP(addrConn{})
+
+			assert.Equal(t, test.tlvs, tlvs)
+		})
+	}
+}
+
+func TestProxyProtocol_header(t *testing.T) {
+	ipv4Conn := addrConn{
+		remote: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 51000},
+		local:  &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 443},
+	}
+
+	testCases := []struct {
+		desc           string
+		proxyProtocol  ProxyProtocol
+		conn           WriteCloser
+		expectedHeader []byte
+	}{
+		{
+			desc:           "version 1",
+			proxyProtocol:  ProxyProtocol{Version: 1},
+			conn:           ipv4Conn,
+			expectedHeader: []byte("PROXY TCP4 10.0.0.1 10.0.0.2 51000 443\r\n"),
+		},
+		{
+			desc:          "version 1 with mixed address families",
+			proxyProtocol: ProxyProtocol{Version: 1},
+			conn: addrConn{
+				remote: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 51000},
+				local:  &net.TCPAddr{IP: net.ParseIP("::1"), Port: 443},
+			},
+			expectedHeader: []byte("PROXY UNKNOWN\r\n"),
+		},
+		{
+			desc:          "version 2 with a custom TLV of the router",
+			proxyProtocol: ProxyProtocol{Version: 2},
+			conn:          &tlvConn{WriteCloser: ipv4Conn, tlvs: []ProxyProtocolTLV{{Type: 0xE0, Value: []byte("foo")}}},
+			expectedHeader: append(append([]byte{}, pp2Signature...),
+				0x21, 0x11, 0x00, 0x12,
+				10, 0, 0, 1, 10, 0, 0, 2, 0xC7, 0x38, 0x01, 0xBB,
+				0xE0, 0x00, 0x03, 'f', 'o', 'o',
+			),
+		},
+		{
+			desc:          "version 2 without IP addresses",
+			proxyProtocol: ProxyProtocol{Version: 2},
+			conn:          addrConn{},
+			expectedHeader: append(append([]byte{}, pp2Signature...),
+				0x21, 0x00, 0x00, 0x00,
+			),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header, err := test.proxyProtocol.header(test.conn)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedHeader, header)
+		})
+	}
+}
+
+func TestProxyProtocol_headerTLSTermination(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	serverConn, clientConn := net.Pipe()
+	defer func() { _ = serverConn.Close() }()
+	defer func() { _ = clientConn.Close() }()
+
+	go func() {
+		_ = tls.Client(clientConn, &tls.Config{
+			ServerName:         "foo.example.com",
+			NextProtos:         []string{"h2"},
+			InsecureSkipVerify: true, // #nosec - self-signed test certificate.
+		}).Handshake()
+	}()
+
+	// The TLS connection is wrapped by the router of the connection, and by the tracking of the connections.
+	conn := &tlvConn{
+		WriteCloser: &trackedConn{
+			WriteCloser: tls.Server(pipeConn{Conn: serverConn}, &tls.Config{
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   []string{"h2"},
+			}),
+		},
+		tlvs: []ProxyProtocolTLV{{Type: 0xE0, Value: []byte("tenant-a")}},
+	}
+
+	proxyProtocol := &ProxyProtocol{Version: 2}
+	header, err := proxyProtocol.header(conn)
+	require.NoError(t, err)
+
+	tlvs := parseTLVs(t, header[len(pp2Signature)+4:])
+	assert.Equal(t, "h2", string(tlvs[pp2TypeALPN]))
+	assert.Equal(t, "foo.example.com", string(tlvs[pp2TypeAuthority]))
+	assert.Equal(t, "tenant-a", string(tlvs[0xE0]))
+
+	ssl := tlvs[pp2TypeSSL]
+	require.True(t, len(ssl) > 5)
+	assert.Equal(t, byte(pp2ClientSSL), ssl[0])
+	assert.Equal(t, uint32(1), binary.BigEndian.Uint32(ssl[1:5]))
+
+	subTLVs := parseTLVs(t, ssl[5:])
+	assert.Equal(t, "TLSv1.3", string(subTLVs[pp2SubtypeSSLVersion]))
+	assert.NotEmpty(t, subTLVs[pp2SubtypeSSLCipher])
+}
+
+func TestProxyProtocol_headerTLSHandshakeTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { tlsHandshakeTimeout = timeout }(tlsHandshakeTimeout)
+	tlsHandshakeTimeout = 50 * time.Millisecond
+
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	serverConn, clientConn := net.Pipe()
+	defer func() { _ = serverConn.Close() }()
+	defer func() { _ = clientConn.Close() }()
+
+	// The client never sends its ClientHello.
+	conn := tls.Server(pipeConn{Conn: serverConn}, &tls.Config{Certificates: []tls.Certificate{*cert}})
+
+	done := make(chan error, 1)
+	go func() {
+		proxyProtocol := &ProxyProtocol{Version: 2}
+		_, err := proxyProtocol.header(conn)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the TLS handshake did not time out")
+	}
+}
+
+func TestProxyProtocol_headerTLSPassthrough(t *testing.T) {
+	record := readClientHello(t, &tls.Config{ServerName: "foo.example.com"})
+
+	conn := &Conn{Peeked: record, WriteCloser: addrConn{}}
+
+	proxyProtocol := &ProxyProtocol{Version: 2}
+	header, err := proxyProtocol.header(conn)
+	require.NoError(t, err)
+
+	tlvs := parseTLVs(t, header[len(pp2Signature)+4:])
+	assert.Equal(t, "foo.example.com", string(tlvs[pp2TypeAuthority]))
+	assert.NotContains(t, tlvs, byte(pp2TypeSSL))
+}
+
+func parseTLVs(t *testing.T, b []byte) map[byte][]byte {
+	t.Helper()
+
+	tlvs := make(map[byte][]byte)
+	for len(b) > 0 {
+		require.True(t, len(b) >= 3)
+
+		length := int(binary.BigEndian.Uint16(b[1:3]))
+		require.True(t, len(b) >= 3+length)
+
+		tlvs[b[0]] = b[3 : 3+length]
+		b = b[3+length:]
+	}
+
+	return tlvs
+}
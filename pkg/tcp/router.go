@@ -16,6 +16,7 @@ import (
 // Router is a TCP router.
 type Router struct {
 	routingTable      map[string]Handler
+	sniRoutes         sniRoutes
 	httpForwarder     Handler
 	httpsForwarder    Handler
 	httpHandler       http.Handler
@@ -23,13 +24,17 @@ type Router struct {
 	httpsTLSConfig    *tls.Config // default TLS config
 	catchAllNoTLS     Handler
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+	// catchAllPriority is the priority of the * route against the SNI matchers, when set.
+	catchAllPriority *int
+	// tlsHandshakeMetrics, when set, records the TLS handshakes terminated by the router.
+	tlsHandshakeMetrics *TLSHandshakeMetrics
 }
 
 // ServeTCP forwards the connection to the right TCP/HTTP handler.
 func (r *Router) ServeTCP(conn WriteCloser) {
 	// FIXME -- Check if ProxyProtocol changes the first bytes of the request
 
-	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 {
+	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 && len(r.sniRoutes) == 0 {
 		r.catchAllNoTLS.ServeTCP(conn)
 		return
 	}
@@ -73,6 +78,18 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 		}
 	}
 
+	if serverName != "" {
+		routes := r.sniRoutes
+		if _, ok := r.routingTable["*"]; ok && r.catchAllPriority != nil {
+			routes = routes.withMinPriority(*r.catchAllPriority)
+		}
+
+		if target, ok := routes.match(serverName); ok {
+			target.ServeTCP(r.GetConn(conn, peeked))
+			return
+		}
+	}
+
 	// FIXME Needs tests
 	if target, ok := r.routingTable["*"]; ok {
 		target.ServeTCP(r.GetConn(conn, peeked))
@@ -97,11 +114,39 @@ func (r *Router) AddRoute(sniHost string, target Handler) {
 // AddRouteTLS defines a handler for a given sniHost and sets the matching tlsConfig.
 func (r *Router) AddRouteTLS(sniHost string, target Handler, config *tls.Config) {
 	r.AddRoute(sniHost, &TLSHandler{
-		Next:   target,
-		Config: config,
+		Next:    target,
+		Config:  config,
+		Metrics: r.tlsHandshakeMetrics,
+	})
+}
+
+// AddRouteMatcher defines a handler for the server names matching the given matcher.
+// Exact server names take precedence over matchers, and matchers are evaluated by decreasing priority.
+func (r *Router) AddRouteMatcher(matcher *SNIMatcher, target Handler) {
+	r.sniRoutes = append(r.sniRoutes, sniRoute{matcher: matcher, target: target})
+	r.sniRoutes.sort()
+}
+
+// AddRouteMatcherTLS defines a handler for the server names matching the given matcher and sets the matching tlsConfig.
+func (r *Router) AddRouteMatcherTLS(matcher *SNIMatcher, target Handler, config *tls.Config) {
+	r.AddRouteMatcher(matcher, &TLSHandler{
+		Next:    target,
+		Config:  config,
+		Metrics: r.tlsHandshakeMetrics,
 	})
 }
 
+// SetTLSHandshakeMetrics makes the router record the TLS handshakes of the TLS routes added afterwards.
+func (r *Router) SetTLSHandshakeMetrics(handshakeMetrics *TLSHandshakeMetrics) {
+	r.tlsHandshakeMetrics = handshakeMetrics
+}
+
+// SetCatchAllPriority makes the * route evaluated along with the SNI matchers, after the ones with a priority greater than or equal to the given priority.
+// By default, the * route is only used when no matcher matches.
+func (r *Router) SetCatchAllPriority(priority int) {
+	r.catchAllPriority = &priority
+}
+
 // AddRouteHTTPTLS defines a handler for a given sniHost and sets the matching tlsConfig.
 func (r *Router) AddRouteHTTPTLS(sniHost string, config *tls.Config) {
 	if r.hostHTTPTLSConfig == nil {
@@ -146,9 +191,15 @@ func (r *Router) HTTPSForwarder(handler Handler) {
 		r.AddRouteTLS(sniHost, handler, tlsConf)
 	}
 
+	// The HTTPS routers replacing the * route are only used when no matcher matches.
+	if _, ok := r.hostHTTPTLSConfig["*"]; ok {
+		r.catchAllPriority = nil
+	}
+
 	r.httpsForwarder = &TLSHandler{
-		Next:   handler,
-		Config: r.httpsTLSConfig,
+		Next:    handler,
+		Config:  r.httpsTLSConfig,
+		Metrics: r.tlsHandshakeMetrics,
 	}
 }
 
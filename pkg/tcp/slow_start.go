@@ -0,0 +1,62 @@
+package tcp
+
+import (
+	"fmt"
+	"time"
+)
+
+// slowStartScale is the factor applied to the weights of the servers during a slow start,
+// so that a ramping weight does not jump from 0 to the full weight of a light server.
+const slowStartScale = 100
+
+// slowStart ramps up linearly the weight of the servers coming back up,
+// from a percentage of their weight to their full weight.
+type slowStart struct {
+	duration time.Duration
+	initial  float64
+	now      func() time.Time
+}
+
+func newSlowStart(duration time.Duration, initialPercent int) (*slowStart, error) {
+	if duration <= 0 {
+		return nil, nil
+	}
+
+	if initialPercent < 0 || initialPercent > 100 {
+		return nil, fmt.Errorf("invalid initial percentage %d", initialPercent)
+	}
+
+	return &slowStart{
+		duration: duration,
+		initial:  float64(initialPercent) / 100,
+		now:      time.Now,
+	}, nil
+}
+
+// weight returns the effective weight of a server up since the given time, a zero time meaning it never went down.
+// With a slow start, all the weights are scaled, and the ramping weight of a server is at least 1.
+func (s *slowStart) weight(weight int, upSince time.Time) int {
+	if s == nil {
+		return weight
+	}
+
+	if weight <= 0 {
+		return 0
+	}
+
+	full := weight * slowStartScale
+	if upSince.IsZero() {
+		return full
+	}
+
+	elapsed := s.now().Sub(upSince)
+	if elapsed >= s.duration {
+		return full
+	}
+
+	factor := s.initial + (1-s.initial)*float64(elapsed)/float64(s.duration)
+	if w := int(float64(full) * factor); w > 0 {
+		return w
+	}
+	return 1
+}
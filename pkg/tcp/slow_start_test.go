@@ -0,0 +1,132 @@
+package tcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlowStart_weight(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		desc     string
+		weight   int
+		upSince  time.Time
+		expected int
+	}{
+		{
+			desc:     "never went down",
+			weight:   2,
+			expected: 200,
+		},
+		{
+			desc:     "just came back up",
+			weight:   2,
+			upSince:  now,
+			expected: 20,
+		},
+		{
+			desc:     "halfway through the ramp",
+			weight:   2,
+			upSince:  now.Add(-5 * time.Second),
+			expected: 110,
+		},
+		{
+			desc:     "ramp over",
+			weight:   2,
+			upSince:  now.Add(-time.Minute),
+			expected: 200,
+		},
+		{
+			desc:     "0 weight",
+			upSince:  now,
+			expected: 0,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ss, err := newSlowStart(10*time.Second, 10)
+			require.NoError(t, err)
+			ss.now = func() time.Time { return now }
+
+			assert.Equal(t, test.expected, ss.weight(test.weight, test.upSince))
+		})
+	}
+}
+
+func TestNewSlowStart(t *testing.T) {
+	ss, err := newSlowStart(0, 10)
+	require.NoError(t, err)
+	assert.Nil(t, ss)
+	assert.Equal(t, 3, ss.weight(3, time.Now()))
+
+	_, err = newSlowStart(time.Second, 101)
+	assert.Error(t, err)
+}
+
+func TestWRRLoadBalancer_slowStart(t *testing.T) {
+	balancer := NewWRRLoadBalancer()
+	for _, server := range []string{"h1", "h2"} {
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {}), nil)
+	}
+
+	require.NoError(t, balancer.SetSlowStart(10*time.Second, 10))
+
+	require.NoError(t, balancer.SetStatus("h1", false))
+	require.NoError(t, balancer.SetStatus("h1", true))
+
+	upSince := balancer.servers[0].upSince
+	balancer.slowStart.now = func() time.Time { return upSince }
+
+	selected := make(map[string]int)
+	for i := 0; i < 110; i++ {
+		srv, err := balancer.next()
+		require.NoError(t, err)
+		selected[srv.name]++
+	}
+
+	assert.Equal(t, map[string]int{"h1": 10, "h2": 100}, selected)
+
+	// Once the ramp is over, the servers are on par again.
+	balancer.slowStart.now = func() time.Time { return upSince.Add(10 * time.Second) }
+
+	selected = make(map[string]int)
+	for i := 0; i < 10; i++ {
+		srv, err := balancer.next()
+		require.NoError(t, err)
+		selected[srv.name]++
+	}
+
+	assert.Equal(t, map[string]int{"h1": 5, "h2": 5}, selected)
+}
+
+func TestLeastConnLoadBalancer_slowStart(t *testing.T) {
+	balancer := NewLeastConnLoadBalancer()
+	for _, server := range []string{"h1", "h2"} {
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {}), nil)
+	}
+
+	require.NoError(t, balancer.SetSlowStart(10*time.Second, 25))
+
+	require.NoError(t, balancer.SetStatus("h1", false))
+	require.NoError(t, balancer.SetStatus("h1", true))
+
+	upSince := balancer.servers[0].upSince
+	balancer.slowStart.now = func() time.Time { return upSince }
+
+	for i := 0; i < 5; i++ {
+		_, err := balancer.acquire()
+		require.NoError(t, err)
+	}
+
+	// h1 weighs a quarter of h2.
+	assert.Equal(t, 1, balancer.servers[0].active)
+	assert.Equal(t, 4, balancer.servers[1].active)
+}
@@ -0,0 +1,115 @@
+package tcp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SNIMatcher matches server names against a HostSNI pattern with wildcard labels, or against a HostSNIRegexp.
+type SNIMatcher struct {
+	// Pattern is the HostSNI or HostSNIRegexp value the matcher was built from.
+	Pattern string
+	// Priority orders the matchers which match the same server name, the highest first.
+	Priority int
+
+	exp *regexp.Regexp
+}
+
+// IsSNIPattern reports whether the HostSNI value contains wildcard labels.
+func IsSNIPattern(host string) bool {
+	return host != "*" && strings.Contains(host, "*")
+}
+
+// NewHostSNIMatcher creates a matcher for a HostSNI value with wildcard labels.
+// A "*" label matches exactly one label, and a leading "**" label matches one or more labels.
+// When priority is zero, the length of the pattern is used as priority.
+func NewHostSNIMatcher(host string, priority int) (*SNIMatcher, error) {
+	labels := strings.Split(strings.ToLower(host), ".")
+
+	parts := make([]string, len(labels))
+	for i, label := range labels {
+		switch {
+		case label == "**" && i == 0:
+			parts[i] = `.+`
+		case label == "*":
+			parts[i] = `[^.]+`
+		case strings.Contains(label, "*"):
+			return nil, fmt.Errorf("invalid HostSNI %q: a wildcard must be a whole label, and \"**\" must be the first label", host)
+		default:
+			parts[i] = regexp.QuoteMeta(label)
+		}
+	}
+
+	exp, err := regexp.Compile(`^` + strings.Join(parts, `\.`) + `$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HostSNI %q: %w", host, err)
+	}
+
+	return newSNIMatcher(host, priority, exp), nil
+}
+
+// NewHostSNIRegexpMatcher creates a matcher for a HostSNIRegexp value.
+// When priority is zero, the length of the expression is used as priority.
+func NewHostSNIRegexpMatcher(expr string, priority int) (*SNIMatcher, error) {
+	exp, err := regexp.Compile(`(?i)^(?:` + expr + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HostSNIRegexp %q: %w", expr, err)
+	}
+
+	return newSNIMatcher(expr, priority, exp), nil
+}
+
+func newSNIMatcher(pattern string, priority int, exp *regexp.Regexp) *SNIMatcher {
+	if priority == 0 {
+		priority = len(pattern)
+	}
+
+	return &SNIMatcher{
+		Pattern:  pattern,
+		Priority: priority,
+		exp:      exp,
+	}
+}
+
+// Match reports whether the server name matches.
+func (m *SNIMatcher) Match(serverName string) bool {
+	return m.exp.MatchString(serverName)
+}
+
+type sniRoute struct {
+	matcher *SNIMatcher
+	target  Handler
+}
+
+// sniRoutes is sorted by priority, then by pattern, so that overlapping patterns are always evaluated in the same order.
+type sniRoutes []sniRoute
+
+func (r sniRoutes) sort() {
+	sort.SliceStable(r, func(i, j int) bool {
+		if r[i].matcher.Priority == r[j].matcher.Priority {
+			return r[i].matcher.Pattern < r[j].matcher.Pattern
+		}
+		return r[i].matcher.Priority > r[j].matcher.Priority
+	})
+}
+
+// withMinPriority returns the routes whose priority is greater than or equal to the given priority.
+func (r sniRoutes) withMinPriority(priority int) sniRoutes {
+	for i, route := range r {
+		if route.matcher.Priority < priority {
+			return r[:i]
+		}
+	}
+	return r
+}
+
+func (r sniRoutes) match(serverName string) (Handler, bool) {
+	for _, route := range r {
+		if route.matcher.Match(serverName) {
+			return route.target, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,187 @@
+package tcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostSNIMatcher(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		host       string
+		serverName string
+		match      bool
+	}{
+		{
+			desc:       "single level wildcard",
+			host:       "*.example.com",
+			serverName: "foo.example.com",
+			match:      true,
+		},
+		{
+			desc:       "single level wildcard with several labels",
+			host:       "*.example.com",
+			serverName: "foo.bar.example.com",
+		},
+		{
+			desc:       "single level wildcard without label",
+			host:       "*.example.com",
+			serverName: "example.com",
+		},
+		{
+			desc:       "multi-level wildcard",
+			host:       "**.example.com",
+			serverName: "foo.bar.example.com",
+			match:      true,
+		},
+		{
+			desc:       "multi-level wildcard with one label",
+			host:       "**.example.com",
+			serverName: "foo.example.com",
+			match:      true,
+		},
+		{
+			desc:       "multi-level wildcard without label",
+			host:       "**.example.com",
+			serverName: "example.com",
+		},
+		{
+			desc:       "inner wildcard",
+			host:       "api.*.example.com",
+			serverName: "api.tenant.example.com",
+			match:      true,
+		},
+		{
+			desc:       "escaped dots",
+			host:       "*.example.com",
+			serverName: "foo.exampleXcom",
+		},
+		{
+			desc:       "case insensitive",
+			host:       "*.Example.com",
+			serverName: "foo.example.com",
+			match:      true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			matcher, err := NewHostSNIMatcher(test.host, 0)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.match, matcher.Match(test.serverName))
+		})
+	}
+}
+
+func TestHostSNIMatcherInvalid(t *testing.T) {
+	for _, host := range []string{"foo*.example.com", "foo.**.example.com"} {
+		_, err := NewHostSNIMatcher(host, 0)
+		assert.Error(t, err, host)
+	}
+}
+
+func TestHostSNIRegexpMatcher(t *testing.T) {
+	matcher, err := NewHostSNIRegexpMatcher(`[a-z]+-(eu|us)\.example\.com`, 0)
+	require.NoError(t, err)
+
+	assert.True(t, matcher.Match("tenant-eu.example.com"))
+	assert.True(t, matcher.Match("Tenant-US.example.com"))
+	assert.False(t, matcher.Match("tenant-eu.example.com.evil.com"))
+	assert.False(t, matcher.Match("tenant-asia.example.com"))
+
+	_, err = NewHostSNIRegexpMatcher(`(foo`, 0)
+	assert.Error(t, err)
+}
+
+func TestSNIRoutesPriority(t *testing.T) {
+	newMatcher := func(host string, priority int) *SNIMatcher {
+		matcher, err := NewHostSNIMatcher(host, priority)
+		require.NoError(t, err)
+		return matcher
+	}
+
+	var routes sniRoutes
+	for _, route := range []sniRoute{
+		{matcher: newMatcher("**.example.com", 0), target: HandlerFunc(func(WriteCloser) {})},
+		{matcher: newMatcher("*.tenant.example.com", 0), target: HandlerFunc(func(WriteCloser) {})},
+		{matcher: newMatcher("*.*.example.com", 0), target: HandlerFunc(func(WriteCloser) {})},
+	} {
+		routes = append(routes, route)
+		routes.sort()
+	}
+
+	var patterns []string
+	for _, route := range routes {
+		patterns = append(patterns, route.matcher.Pattern)
+	}
+	assert.Equal(t, []string{"*.tenant.example.com", "*.*.example.com", "**.example.com"}, patterns)
+
+	routes = append(routes, sniRoute{matcher: newMatcher("**.example.com", 100), target: HandlerFunc(func(WriteCloser) {})})
+	routes.sort()
+	assert.Equal(t, "**.example.com", routes[0].matcher.Pattern)
+	assert.Equal(t, 100, routes[0].matcher.Priority)
+
+	_, ok := routes.match("foo.tenant.example.com")
+	assert.True(t, ok)
+
+	_, ok = routes.match("example.org")
+	assert.False(t, ok)
+}
+
+func TestSNIRoutesWithMinPriority(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		priority         int
+		expectedPatterns []string
+	}{
+		{
+			desc:             "catch-all first",
+			priority:         100,
+			expectedPatterns: nil,
+		},
+		{
+			desc:             "catch-all between the matchers",
+			priority:         20,
+			expectedPatterns: []string{"*.tenant.example.com"},
+		},
+		{
+			desc:             "same priority as a matcher",
+			priority:         14,
+			expectedPatterns: []string{"*.tenant.example.com", "**.example.com"},
+		},
+		{
+			desc:             "catch-all last",
+			priority:         1,
+			expectedPatterns: []string{"*.tenant.example.com", "**.example.com"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var routes sniRoutes
+			for _, host := range []string{"**.example.com", "*.tenant.example.com"} {
+				matcher, err := NewHostSNIMatcher(host, 0)
+				require.NoError(t, err)
+
+				routes = append(routes, sniRoute{matcher: matcher, target: HandlerFunc(func(WriteCloser) {})})
+			}
+			routes.sort()
+
+			var patterns []string
+			for _, route := range routes.withMinPriority(test.priority) {
+				patterns = append(patterns, route.matcher.Pattern)
+			}
+
+			assert.Equal(t, test.expectedPatterns, patterns)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package tcp
+
+// statusUpdaters are the functions notified when the status of a handler changes,
+// a handler being up as long as it can forward connections to at least one server.
+type statusUpdaters []func(up bool)
+
+func (u statusUpdaters) notify(up bool) {
+	for _, fn := range u {
+		fn(up)
+	}
+}
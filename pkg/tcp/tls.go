@@ -1,16 +1,79 @@
 package tcp
 
 import (
+	"bytes"
 	"crypto/tls"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
 )
 
+const handshakeServerHello = 2
+
+// helloRetryRequestRandom is the random of the ServerHello messages which are HelloRetryRequests, cf RFC 8446 section 4.1.3.
+var helloRetryRequestRandom = []byte{
+	0xCF, 0x21, 0xAD, 0x74, 0xE5, 0x9A, 0x61, 0x11, 0xBE, 0x1D, 0x8C, 0x02, 0x1E, 0x65, 0xB8, 0x91,
+	0xC2, 0xA2, 0x11, 0x16, 0x7A, 0xBB, 0x8C, 0x5E, 0x07, 0x9E, 0x09, 0xE2, 0xC8, 0xA8, 0x33, 0x9C,
+}
+
 // TLSHandler handles TLS connections.
 type TLSHandler struct {
 	Next   Handler
 	Config *tls.Config
+	// Metrics, if not nil, records the TLS handshakes.
+	Metrics *TLSHandshakeMetrics
 }
 
 // ServeTCP terminates the TLS connection.
 func (t *TLSHandler) ServeTCP(conn WriteCloser) {
-	t.Next.ServeTCP(tls.Server(conn, t.Config))
+	if t.Metrics == nil {
+		t.Next.ServeTCP(tls.Server(conn, t.Config))
+		return
+	}
+
+	// The handshake is completed before handing over the connection, so that it can be measured.
+	observed := &handshakeConn{WriteCloser: conn}
+	tlsConn := tls.Server(observed, t.Config)
+
+	start := time.Now()
+	if err := tlsConn.Handshake(); err != nil {
+		log.WithoutContext().Debugf("Error during TLS handshake from %s: %v", conn.RemoteAddr(), err)
+		_ = conn.Close()
+		return
+	}
+
+	t.Metrics.record(tlsConn.ConnectionState(), observed.helloRetryRequest, start)
+
+	t.Next.ServeTCP(tlsConn)
+}
+
+// handshakeConn detects whether the server answered the ClientHello with a HelloRetryRequest,
+// from the first record it sends, which holds the ServerHello.
+type handshakeConn struct {
+	WriteCloser
+	inspected         bool
+	helloRetryRequest bool
+}
+
+func (c *handshakeConn) Write(p []byte) (int, error) {
+	if !c.inspected {
+		c.inspected = true
+		c.helloRetryRequest = isHelloRetryRequest(p)
+	}
+
+	return c.WriteCloser.Write(p)
+}
+
+// isHelloRetryRequest tells whether the given TLS record starts with a HelloRetryRequest.
+func isHelloRetryRequest(record []byte) bool {
+	// The random follows the record and handshake headers, and the legacy version.
+	const randomOffset = recordHeaderLength + handshakeHeaderLength + 2
+
+	if len(record) < randomOffset+len(helloRetryRequestRandom) {
+		return false
+	}
+
+	return record[0] == handshakeRecordType &&
+		record[recordHeaderLength] == handshakeServerHello &&
+		bytes.Equal(record[randomOffset:randomOffset+len(helloRetryRequestRandom)], helloRetryRequestRandom)
 }
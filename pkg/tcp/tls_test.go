@@ -0,0 +1,127 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/tls/generate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSHandler_metrics(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc                       string
+		serverConfig               *tls.Config
+		clientConfig               *tls.Config
+		connections                int
+		expectedLabels             []string
+		expectedHelloRetryRequests float64
+	}{
+		{
+			desc:           "full handshake",
+			serverConfig:   &tls.Config{},
+			clientConfig:   &tls.Config{},
+			connections:    1,
+			expectedLabels: []string{"tls_version", "1.3", "resumption", "none", "entrypoint", "websecure"},
+		},
+		{
+			desc:                       "HelloRetryRequest",
+			serverConfig:               &tls.Config{CurvePreferences: []tls.CurveID{tls.CurveP256}},
+			clientConfig:               &tls.Config{CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256}},
+			connections:                1,
+			expectedLabels:             []string{"tls_version", "1.3", "resumption", "none", "entrypoint", "websecure"},
+			expectedHelloRetryRequests: 1,
+		},
+		{
+			desc:           "session ticket resumption",
+			serverConfig:   &tls.Config{MaxVersion: tls.VersionTLS12},
+			clientConfig:   &tls.Config{ClientSessionCache: tls.NewLRUClientSessionCache(1)},
+			connections:    2,
+			expectedLabels: []string{"tls_version", "1.2", "resumption", "ticket", "entrypoint", "websecure"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			registry := newRegistryMock()
+
+			test.serverConfig.Certificates = []tls.Certificate{*cert}
+			test.clientConfig.InsecureSkipVerify = true // #nosec - self-signed test certificate.
+
+			handler := &TLSHandler{
+				Next: HandlerFunc(func(conn WriteCloser) {
+					_, err := conn.Write([]byte("ok"))
+					require.NoError(t, err)
+					require.NoError(t, conn.Close())
+				}),
+				Config:  test.serverConfig,
+				Metrics: NewTLSHandshakeMetrics(registry, "websecure"),
+			}
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer func() { _ = listener.Close() }()
+
+			served := make(chan struct{})
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					handler.ServeTCP(conn.(*net.TCPConn))
+					served <- struct{}{}
+				}
+			}()
+
+			for i := 0; i < test.connections; i++ {
+				conn, err := tls.Dial("tcp", listener.Addr().String(), test.clientConfig)
+				require.NoError(t, err)
+
+				buf := make([]byte, 2)
+				_, err = conn.Read(buf)
+				require.NoError(t, err)
+				assert.Equal(t, "ok", string(buf))
+
+				_ = conn.Close()
+				<-served
+			}
+
+			assert.Equal(t, test.connections, registry.tlsHandshakeDurations.count)
+			assert.Equal(t, []string{"tls_version", test.expectedLabels[1], "entrypoint", "websecure"}, registry.tlsHandshakeDurations.labelValues)
+			assert.Equal(t, float64(test.connections), registry.tlsHandshakes.value)
+			assert.Equal(t, test.expectedLabels, registry.tlsHandshakes.labelValues)
+			assert.Equal(t, test.expectedHelloRetryRequests, registry.tlsHelloRetryRequests.value)
+		})
+	}
+}
+
+func TestTLSHandler_handshakeError(t *testing.T) {
+	registry := newRegistryMock()
+
+	handler := &TLSHandler{
+		Next: HandlerFunc(func(conn WriteCloser) {
+			t.Error("the connection should not be forwarded")
+		}),
+		Config:  &tls.Config{},
+		Metrics: NewTLSHandshakeMetrics(registry, "websecure"),
+	}
+
+	client, server := net.Pipe()
+
+	go func() {
+		_, _ = client.Write([]byte("not a TLS handshake"))
+		_ = client.Close()
+	}()
+
+	handler.ServeTCP(pipeConn{Conn: server})
+
+	assert.Equal(t, 0, registry.tlsHandshakeDurations.count)
+	assert.Equal(t, float64(0), registry.tlsHandshakes.value)
+}
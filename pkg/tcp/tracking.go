@@ -0,0 +1,71 @@
+package tcp
+
+import (
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/connections"
+)
+
+// NewTrackingHandler returns a handler registering the connections of the given router in the connection table.
+func NewTrackingHandler(table *connections.Table, routerName string, next Handler) Handler {
+	return HandlerFunc(func(conn WriteCloser) {
+		tracked := &trackedConn{WriteCloser: conn}
+
+		id := table.Add("tcp", routerName, conn.RemoteAddr().String(), tracked)
+		defer table.Remove(id)
+
+		next.ServeTCP(tracked)
+	})
+}
+
+// trackedConn counts the bytes exchanged with the client.
+type trackedConn struct {
+	WriteCloser
+
+	mu       sync.RWMutex
+	bytesIn  int64
+	bytesOut int64
+	backend  string
+}
+
+func (c *trackedConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+
+	c.mu.Lock()
+	c.bytesIn += int64(n)
+	c.mu.Unlock()
+
+	return n, err
+}
+
+func (c *trackedConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+
+	c.mu.Lock()
+	c.bytesOut += int64(n)
+	c.mu.Unlock()
+
+	return n, err
+}
+
+func (c *trackedConn) setBackend(address string) {
+	c.mu.Lock()
+	c.backend = address
+	c.mu.Unlock()
+}
+
+func (c *trackedConn) unwrapConn() WriteCloser {
+	return c.WriteCloser
+}
+
+// Stats returns the statistics of the connection.
+func (c *trackedConn) Stats() connections.Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return connections.Stats{
+		Backend:  c.backend,
+		BytesIn:  c.bytesIn,
+		BytesOut: c.bytesOut,
+	}
+}
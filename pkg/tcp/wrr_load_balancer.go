@@ -3,43 +3,219 @@ package tcp
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/log"
 )
 
+// defaultDrainTimeout is how long the connections of a removed server are given to finish by default.
+const defaultDrainTimeout = 30 * time.Second
+
 type server struct {
 	Handler
+	name   string
 	weight int
+	// down is set when the server is reported unhealthy, which takes it out of the balancing.
+	down bool
+	// upSince is when the server came back up, which starts the ramp of its weight with a slow start.
+	upSince time.Time
+	// maxConns is the maximum number of concurrent connections forwarded to the server, 0 meaning no limit.
+	maxConns int
+
+	connsLock sync.Mutex
+	conns     map[WriteCloser]struct{}
+	// drained is closed once the last connection of the server ends, after the server is removed.
+	drained chan struct{}
+}
+
+func (s *server) track(conn WriteCloser) {
+	s.connsLock.Lock()
+	defer s.connsLock.Unlock()
+
+	s.conns[conn] = struct{}{}
+}
+
+// saturated reports whether the server reached its maximum number of concurrent connections.
+func (s *server) saturated() bool {
+	if s.maxConns <= 0 {
+		return false
+	}
+
+	s.connsLock.Lock()
+	defer s.connsLock.Unlock()
+
+	return len(s.conns) >= s.maxConns
+}
+
+func (s *server) untrack(conn WriteCloser) {
+	s.connsLock.Lock()
+	defer s.connsLock.Unlock()
+
+	delete(s.conns, conn)
+
+	if s.drained != nil && len(s.conns) == 0 {
+		close(s.drained)
+		s.drained = nil
+	}
+}
+
+// drain waits for the active connections of the server to end, and closes the ones still active after the timeout.
+// A negative timeout means the connections are never closed.
+func (s *server) drain(timeout time.Duration) <-chan struct{} {
+	drained := make(chan struct{})
+
+	s.connsLock.Lock()
+	if len(s.conns) == 0 {
+		s.connsLock.Unlock()
+		close(drained)
+		return drained
+	}
+	s.drained = drained
+	s.connsLock.Unlock()
+
+	if timeout >= 0 {
+		go func() {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+
+			select {
+			case <-drained:
+			case <-timer.C:
+				s.closeConns()
+			}
+		}()
+	}
+
+	return drained
+}
+
+func (s *server) closeConns() {
+	s.connsLock.Lock()
+	defer s.connsLock.Unlock()
+
+	for conn := range s.conns {
+		if err := conn.Close(); err != nil {
+			log.WithoutContext().Debugf("Error while closing the connection to the removed server %s: %v", s.name, err)
+		}
+	}
 }
 
 // WRRLoadBalancer is a naive RoundRobin load balancer for TCP services.
 type WRRLoadBalancer struct {
-	servers       []server
+	servers       []*server
 	lock          sync.RWMutex
 	currentWeight int
 	index         int
+	drainTimeout  time.Duration
+	slowStart     *slowStart
+	// maxConns is the maximum number of concurrent connections of the balancer, 0 meaning no limit.
+	maxConns int
+	active   int
+	updaters statusUpdaters
 }
 
 // NewWRRLoadBalancer creates a new WRRLoadBalancer.
 func NewWRRLoadBalancer() *WRRLoadBalancer {
 	return &WRRLoadBalancer{
-		index: -1,
+		index:        -1,
+		drainTimeout: defaultDrainTimeout,
+	}
+}
+
+// SetDrainTimeout sets how long the connections of a removed server are given to finish, before being closed.
+// A negative timeout means they are never closed.
+func (b *WRRLoadBalancer) SetDrainTimeout(timeout time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.drainTimeout = timeout
+}
+
+// SetSlowStart ramps up, over the duration, the weight of the servers coming back up,
+// from the initial percentage of their weight. A zero duration disables the slow start.
+func (b *WRRLoadBalancer) SetSlowStart(duration time.Duration, initialPercent int) error {
+	ss, err := newSlowStart(duration, initialPercent)
+	if err != nil {
+		return err
 	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.slowStart = ss
+
+	return nil
+}
+
+// SetMaxConnections sets the maximum number of concurrent connections of the balancer, 0 meaning no limit.
+// Once it is reached, the new connections are closed.
+func (b *WRRLoadBalancer) SetMaxConnections(max int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.maxConns = max
+}
+
+// SetServerMaxConnections sets the maximum number of concurrent connections of the named server, 0 meaning no limit.
+// Once it is reached, the server is skipped until one of its connections ends.
+func (b *WRRLoadBalancer) SetServerMaxConnections(name string, max int) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	found := false
+	for _, srv := range b.servers {
+		if srv.name == name {
+			srv.maxConns = max
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	return nil
 }
 
 // ServeTCP forwards the connection to the right service.
 func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
-	if len(b.servers) == 0 {
-		log.WithoutContext().Error("no available server")
+	next, err := b.acquire(conn)
+	if err != nil {
+		log.WithoutContext().Errorf("Error during load balancing: %v", err)
+		conn.Close()
 		return
 	}
+	defer b.release(next, conn)
+
+	next.ServeTCP(conn)
+}
+
+// acquire selects the next server, and counts the connection against the limits.
+func (b *WRRLoadBalancer) acquire(conn WriteCloser) (*server, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.maxConns > 0 && b.active >= b.maxConns {
+		return nil, fmt.Errorf("maximum number of connections reached (%d)", b.maxConns)
+	}
 
 	next, err := b.next()
 	if err != nil {
-		log.WithoutContext().Errorf("Error during load balancing: %v", err)
-		conn.Close()
+		return nil, err
 	}
-	next.ServeTCP(conn)
+
+	b.active++
+	next.track(conn)
+
+	return next, nil
+}
+
+func (b *WRRLoadBalancer) release(srv *server, conn WriteCloser) {
+	b.lock.Lock()
+	b.active--
+	b.lock.Unlock()
+
+	srv.untrack(conn)
 }
 
 // AddServer appends a server to the existing list.
@@ -50,18 +226,106 @@ func (b *WRRLoadBalancer) AddServer(serverHandler Handler) {
 
 // AddWeightServer appends a server to the existing list with a weight.
 func (b *WRRLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
+	b.AddNamedServer("", serverHandler, weight)
+}
+
+// AddNamedServer appends a server to the existing list with a weight,
+// under a name which allows to remove it later on.
+func (b *WRRLoadBalancer) AddNamedServer(name string, serverHandler Handler, weight *int) {
 	w := 1
 	if weight != nil {
 		w = *weight
 	}
-	b.servers = append(b.servers, server{Handler: serverHandler, weight: w})
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.servers = append(b.servers, &server{
+		Handler: serverHandler,
+		name:    name,
+		weight:  w,
+		conns:   make(map[WriteCloser]struct{}),
+	})
+}
+
+// RemoveServer removes the named server from the balancing:
+// the new connections are no longer forwarded to it,
+// while its active connections are given the drain timeout to finish, before being closed.
+// The returned channel is closed once all the connections to the server have ended.
+func (b *WRRLoadBalancer) RemoveServer(name string) (<-chan struct{}, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for i, srv := range b.servers {
+		if srv.name != name {
+			continue
+		}
+
+		b.servers = append(b.servers[:i], b.servers[i+1:]...)
+
+		// The round restarts, as the weights and positions of the servers changed.
+		b.index = -1
+		b.currentWeight = 0
+
+		return srv.drain(b.drainTimeout), nil
+	}
+
+	return nil, fmt.Errorf("server %q not found", name)
+}
+
+// RegisterStatusUpdater registers a function called when the balancer goes down, because all its servers are down,
+// or back up.
+func (b *WRRLoadBalancer) RegisterStatusUpdater(fn func(up bool)) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.updaters = append(b.updaters, fn)
+}
+
+// SetStatus marks the named server as up or down.
+// A server down no longer receives new connections, while its active connections are left untouched.
+// A server coming back up starts the ramp of its weight, with a slow start.
+func (b *WRRLoadBalancer) SetStatus(name string, up bool) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	wasUp := b.hasServerUp()
+
+	found := false
+	for _, srv := range b.servers {
+		if srv.name != name {
+			continue
+		}
+
+		found = true
+		if srv.down == up {
+			srv.down = !up
+			if up {
+				srv.upSince = time.Now()
+			}
+
+			// The round restarts, as the enabled servers changed.
+			b.index = -1
+			b.currentWeight = 0
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	if isUp := b.hasServerUp(); isUp != wasUp {
+		b.updaters.notify(isUp)
+	}
+
+	return nil
 }
 
 func (b *WRRLoadBalancer) maxWeight() int {
 	max := -1
 	for _, s := range b.servers {
-		if s.weight > max {
-			max = s.weight
+		if w := b.weightOf(s); !s.down && w > max {
+			max = w
 		}
 	}
 	return max
@@ -70,15 +334,42 @@ func (b *WRRLoadBalancer) maxWeight() int {
 func (b *WRRLoadBalancer) weightGcd() int {
 	divisor := -1
 	for _, s := range b.servers {
+		if s.down {
+			continue
+		}
+
 		if divisor == -1 {
-			divisor = s.weight
+			divisor = b.weightOf(s)
 		} else {
-			divisor = gcd(divisor, s.weight)
+			divisor = gcd(divisor, b.weightOf(s))
 		}
 	}
 	return divisor
 }
 
+func (b *WRRLoadBalancer) hasServerUp() bool {
+	for _, s := range b.servers {
+		if !s.down {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *WRRLoadBalancer) hasServerAvailable() bool {
+	for _, s := range b.servers {
+		if !s.down && b.weightOf(s) > 0 && !s.saturated() {
+			return true
+		}
+	}
+	return false
+}
+
+// weightOf returns the effective weight of the server, ramping with a slow start.
+func (b *WRRLoadBalancer) weightOf(s *server) int {
+	return b.slowStart.weight(s.weight, s.upSince)
+}
+
 func gcd(a, b int) int {
 	for b != 0 {
 		a, b = b, a%b
@@ -86,14 +377,21 @@ func gcd(a, b int) int {
 	return a
 }
 
-func (b *WRRLoadBalancer) next() (Handler, error) {
-	b.lock.Lock()
-	defer b.lock.Unlock()
-
+// next selects the next server, b.lock being held.
+func (b *WRRLoadBalancer) next() (*server, error) {
 	if len(b.servers) == 0 {
 		return nil, fmt.Errorf("no servers in the pool")
 	}
 
+	if !b.hasServerUp() {
+		return nil, fmt.Errorf("all servers are down")
+	}
+
+	// The servers with a weight of 0 are never selected, so they are not waited for.
+	if b.maxWeight() > 0 && !b.hasServerAvailable() {
+		return nil, fmt.Errorf("all servers reached their maximum number of connections")
+	}
+
 	// The algo below may look messy, but is actually very simple
 	// it calculates the GCD  and subtracts it on every iteration, what interleaves servers
 	// and allows us not to build an iterator every time we readjust weights
@@ -115,7 +413,7 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 			}
 		}
 		srv := b.servers[b.index]
-		if srv.weight >= b.currentWeight {
+		if !srv.down && b.weightOf(srv) >= b.currentWeight && !srv.saturated() {
 			return srv, nil
 		}
 	}
@@ -129,3 +129,215 @@ func TestLoadBalancing(t *testing.T) {
 		})
 	}
 }
+
+// closeNotifierConn is a connection notifying its closing.
+type closeNotifierConn struct {
+	*fakeConn
+	closed chan struct{}
+}
+
+func (c *closeNotifierConn) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func TestWRRLoadBalancer_RemoveServer(t *testing.T) {
+	balancer := NewWRRLoadBalancer()
+	for _, server := range []string{"h1", "h2"} {
+		server := server
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {
+			_, err := conn.Write([]byte(server))
+			require.NoError(t, err)
+		}), nil)
+	}
+
+	drained, err := balancer.RemoveServer("h1")
+	require.NoError(t, err)
+
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("a server without connections should be drained right away")
+	}
+
+	conn := &fakeConn{call: make(map[string]int)}
+	for i := 0; i < 4; i++ {
+		balancer.ServeTCP(conn)
+	}
+
+	assert.Equal(t, map[string]int{"h2": 4}, conn.call)
+
+	_, err = balancer.RemoveServer("h1")
+	assert.Error(t, err)
+}
+
+func TestWRRLoadBalancer_SetStatus(t *testing.T) {
+	balancer := NewWRRLoadBalancer()
+	for _, server := range []string{"h1", "h2"} {
+		server := server
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {
+			_, err := conn.Write([]byte(server))
+			require.NoError(t, err)
+		}), nil)
+	}
+
+	require.NoError(t, balancer.SetStatus("h1", false))
+
+	conn := &fakeConn{call: make(map[string]int)}
+	for i := 0; i < 4; i++ {
+		balancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h2": 4}, conn.call)
+
+	require.NoError(t, balancer.SetStatus("h2", false))
+
+	_, err := balancer.next()
+	assert.Error(t, err)
+
+	require.NoError(t, balancer.SetStatus("h1", true))
+	require.NoError(t, balancer.SetStatus("h2", true))
+
+	conn = &fakeConn{call: make(map[string]int)}
+	for i := 0; i < 4; i++ {
+		balancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h1": 2, "h2": 2}, conn.call)
+
+	assert.Error(t, balancer.SetStatus("h3", false))
+}
+
+func TestWRRLoadBalancer_RegisterStatusUpdater(t *testing.T) {
+	balancer := NewWRRLoadBalancer()
+	for _, server := range []string{"h1", "h2"} {
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {}), nil)
+	}
+
+	var statuses []bool
+	balancer.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	})
+
+	require.NoError(t, balancer.SetStatus("h1", false))
+	assert.Empty(t, statuses)
+
+	require.NoError(t, balancer.SetStatus("h2", false))
+	require.NoError(t, balancer.SetStatus("h2", false))
+	assert.Equal(t, []bool{false}, statuses)
+
+	require.NoError(t, balancer.SetStatus("h1", true))
+	require.NoError(t, balancer.SetStatus("h2", true))
+	assert.Equal(t, []bool{false, true}, statuses)
+}
+
+func TestWRRLoadBalancer_maxConnections(t *testing.T) {
+	balancer := NewWRRLoadBalancer()
+	for _, server := range []string{"h1", "h2"} {
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {}), nil)
+	}
+
+	require.NoError(t, balancer.SetServerMaxConnections("h1", 1))
+	require.NoError(t, balancer.SetServerMaxConnections("h2", 2))
+	assert.Error(t, balancer.SetServerMaxConnections("h3", 1))
+
+	conns := make([]WriteCloser, 4)
+	for i := range conns {
+		conns[i] = &fakeConn{call: make(map[string]int)}
+	}
+
+	var acquired []*server
+	for _, conn := range conns[:3] {
+		srv, err := balancer.acquire(conn)
+		require.NoError(t, err)
+		acquired = append(acquired, srv)
+	}
+	assert.Equal(t, "h1", acquired[0].name)
+	assert.Equal(t, "h2", acquired[1].name)
+	assert.Equal(t, "h2", acquired[2].name)
+
+	_, err := balancer.acquire(conns[3])
+	assert.Error(t, err)
+
+	balancer.release(acquired[0], conns[0])
+
+	srv, err := balancer.acquire(conns[3])
+	require.NoError(t, err)
+	assert.Equal(t, "h1", srv.name)
+
+	balancer.release(srv, conns[3])
+	balancer.SetMaxConnections(2)
+
+	_, err = balancer.acquire(conns[3])
+	assert.Error(t, err)
+}
+
+func TestWRRLoadBalancer_RemoveServer_draining(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		drainTimeout time.Duration
+		release      bool
+		expectClosed bool
+	}{
+		{
+			desc:         "connection ending before the drain timeout",
+			drainTimeout: time.Minute,
+			release:      true,
+		},
+		{
+			desc:         "connection closed after the drain timeout",
+			drainTimeout: 10 * time.Millisecond,
+			expectClosed: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+
+			balancer := NewWRRLoadBalancer()
+			balancer.SetDrainTimeout(test.drainTimeout)
+			balancer.AddNamedServer("h1", HandlerFunc(func(conn WriteCloser) {
+				close(started)
+				select {
+				case <-release:
+				case <-conn.(*closeNotifierConn).closed:
+				}
+			}), nil)
+
+			conn := &closeNotifierConn{fakeConn: &fakeConn{}, closed: make(chan struct{})}
+			go balancer.ServeTCP(conn)
+			<-started
+
+			drained, err := balancer.RemoveServer("h1")
+			require.NoError(t, err)
+
+			select {
+			case <-drained:
+				if !test.expectClosed {
+					t.Fatal("the server should not be drained while its connection is active")
+				}
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			if test.release {
+				close(release)
+			}
+
+			select {
+			case <-drained:
+			case <-time.After(time.Second):
+				t.Fatal("the server should be drained")
+			}
+
+			select {
+			case <-conn.closed:
+				assert.True(t, test.expectClosed, "the connection should not be closed")
+			default:
+				assert.False(t, test.expectClosed, "the connection should be closed")
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 // CertificateStore store for dynamic and static certificates.
 type CertificateStore struct {
 	DynamicCerts       *safe.Safe
+	NamedCerts         *safe.Safe
 	DefaultCertificate *tls.Certificate
 	CertCache          *cache.Cache
 }
@@ -24,6 +25,7 @@ type CertificateStore struct {
 func NewCertificateStore() *CertificateStore {
 	return &CertificateStore{
 		DynamicCerts: &safe.Safe{},
+		NamedCerts:   &safe.Safe{},
 		CertCache:    cache.New(1*time.Hour, 10*time.Minute),
 	}
 }
@@ -110,6 +112,15 @@ func (c CertificateStore) GetBestCertificate(clientHello *tls.ClientHelloInfo) *
 	return nil
 }
 
+// GetCertificateByName returns the certificate registered with the given name, if any.
+func (c CertificateStore) GetCertificateByName(name string) *tls.Certificate {
+	if c.NamedCerts == nil || c.NamedCerts.Get() == nil {
+		return nil
+	}
+
+	return c.NamedCerts.Get().(map[string]*tls.Certificate)[name]
+}
+
 // ResetCache clears the cache in the store.
 func (c CertificateStore) ResetCache() {
 	if c.CertCache != nil {
@@ -38,4 +38,6 @@ type Store struct {
 type CertAndStores struct {
 	Certificate `yaml:",inline"`
 	Stores      []string `json:"stores,omitempty" toml:"stores,omitempty" yaml:"stores,omitempty"`
+	// Name allows the routers to pin the certificate, whatever the server name of the connections.
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty"`
 }
@@ -59,6 +59,7 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 	}
 
 	storesCertificates := make(map[string]map[string]*tls.Certificate)
+	storesNamedCertificates := make(map[string]map[string]*tls.Certificate)
 	for _, conf := range certs {
 		if len(conf.Stores) == 0 {
 			if log.GetLevel() >= logrus.DebugLevel {
@@ -72,12 +73,41 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 			if err := conf.Certificate.AppendCertificate(storesCertificates, store); err != nil {
 				log.FromContext(ctxStore).Errorf("Unable to append certificate %s to store: %v", conf.Certificate.GetTruncatedCertificateName(), err)
 			}
+
+			if conf.Name != "" {
+				appendNamedCertificate(ctxStore, storesNamedCertificates, store, conf)
+			}
 		}
 	}
 
 	for storeName, certs := range storesCertificates {
 		m.getStore(storeName).DynamicCerts.Set(certs)
 	}
+
+	for storeName, certs := range storesNamedCertificates {
+		m.getStore(storeName).NamedCerts.Set(certs)
+	}
+}
+
+// appendNamedCertificate adds a named certificate to the named certificates of a store.
+// Unlike AppendCertificate, certificates are not deduplicated on their domains,
+// so that several certificates can be pinned for the same domains.
+func appendNamedCertificate(ctx context.Context, certs map[string]map[string]*tls.Certificate, store string, conf *CertAndStores) {
+	if _, exists := certs[store][conf.Name]; exists {
+		log.FromContext(ctx).Errorf("Skipping certificate %s: the name %q is already used in the store", conf.Certificate.GetTruncatedCertificateName(), conf.Name)
+		return
+	}
+
+	cert, err := buildDefaultCertificate(&conf.Certificate)
+	if err != nil {
+		log.FromContext(ctx).Errorf("Unable to load the certificate %q: %v", conf.Name, err)
+		return
+	}
+
+	if certs[store] == nil {
+		certs[store] = make(map[string]*tls.Certificate)
+	}
+	certs[store][conf.Name] = cert
 }
 
 // Get gets the TLS configuration to use for a given store / configuration.
@@ -133,6 +163,38 @@ func (m *Manager) Get(storeName string, configName string) (*tls.Config, error)
 	return tlsConfig, err
 }
 
+// GetWithCertificate gets the TLS configuration to use for a given store / configuration,
+// presenting the certificate of the given name instead of selecting one with the server name.
+func (m *Manager) GetWithCertificate(storeName, configName, certificateName string) (*tls.Config, error) {
+	tlsConfig, err := m.Get(storeName, configName)
+	if err != nil {
+		return tlsConfig, err
+	}
+
+	store := m.GetStore(storeName)
+	if store.GetCertificateByName(certificateName) == nil {
+		return tlsConfig, fmt.Errorf("unknown certificate %q in the TLS store %q", certificateName, storeName)
+	}
+
+	getCertificate := tlsConfig.GetCertificate
+	tlsConfig.GetCertificate = func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		// The TLS-ALPN-01 challenges need the challenge certificate.
+		for _, proto := range clientHello.SupportedProtos {
+			if proto == tlsalpn01.ACMETLS1Protocol {
+				return getCertificate(clientHello)
+			}
+		}
+
+		cert := store.GetCertificateByName(certificateName)
+		if cert == nil {
+			return nil, fmt.Errorf("certificate %q not found in the TLS store %q, closing connection", certificateName, storeName)
+		}
+		return cert, nil
+	}
+
+	return tlsConfig, nil
+}
+
 func (m *Manager) getStore(storeName string) *CertificateStore {
 	_, ok := m.stores[storeName]
 	if !ok {
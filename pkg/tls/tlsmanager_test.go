@@ -152,6 +152,42 @@ func TestManager_Get(t *testing.T) {
 	}
 }
 
+func TestManager_GetWithCertificate(t *testing.T) {
+	dynamicConfigs := []*CertAndStores{
+		{
+			Certificate: Certificate{
+				CertFile: localhostCert,
+				KeyFile:  localhostKey,
+			},
+			Name: "foo",
+		},
+		{
+			Certificate: Certificate{
+				CertFile: localhostCert,
+				KeyFile:  localhostKey,
+			},
+			Name: "bar",
+		},
+	}
+
+	tlsManager := NewManager()
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{"default": {}}, dynamicConfigs)
+
+	store := tlsManager.GetStore("default")
+	require.NotNil(t, store.GetCertificateByName("foo"))
+	require.NotNil(t, store.GetCertificateByName("bar"))
+
+	config, err := tlsManager.GetWithCertificate("default", "default", "bar")
+	require.NoError(t, err)
+
+	cert, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: "unknown.com"})
+	require.NoError(t, err)
+	assert.Same(t, store.GetCertificateByName("bar"), cert)
+
+	_, err = tlsManager.GetWithCertificate("default", "default", "unknown")
+	assert.Error(t, err)
+}
+
 func TestClientAuth(t *testing.T) {
 	tlsConfigs := map[string]Options{
 		"eca": {
@@ -7,6 +7,10 @@ const (
 	AccessLogDrop = "drop"
 	// AccessLogRedact is the redact string value.
 	AccessLogRedact = "redact"
+	// AccessLogHash is the hash string value.
+	AccessLogHash = "hash"
+	// AccessLogMask is the mask string value.
+	AccessLogMask = "mask"
 )
 
 const (
@@ -66,6 +70,14 @@ type AccessLogFields struct {
 	DefaultMode string            `description:"Default mode for fields: keep | drop" json:"defaultMode,omitempty" toml:"defaultMode,omitempty" yaml:"defaultMode,omitempty"  export:"true"`
 	Names       map[string]string `description:"Override mode for fields" json:"names,omitempty" toml:"names,omitempty" yaml:"names,omitempty" export:"true"`
 	Headers     *FieldHeaders     `description:"Headers to keep, drop or redact" json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	Redactions  []FieldRedaction  `description:"Rules redacting the fields and header values matching regular expressions." json:"redactions,omitempty" toml:"redactions,omitempty" yaml:"redactions,omitempty" export:"true"`
+}
+
+// FieldRedaction holds a rule redacting the access log fields, and the parts of their values, matching regular expressions.
+type FieldRedaction struct {
+	FieldName string `description:"Regular expression matching the whole name of the fields to redact (e.g. request_Authorization)." json:"fieldName,omitempty" toml:"fieldName,omitempty" yaml:"fieldName,omitempty" export:"true"`
+	Value     string `description:"Regular expression matching the parts of the values to redact, the whole value being redacted when omitted." json:"value,omitempty" toml:"value,omitempty" yaml:"value,omitempty"`
+	Mode      string `description:"Redaction mode: drop | hash | mask" json:"mode,omitempty" toml:"mode,omitempty" yaml:"mode,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -6,6 +6,8 @@ import (
 	"net"
 	"sync"
 	"time"
+
+	"github.com/containous/traefik/v2/pkg/connections"
 )
 
 const receiveMTU = 8192
@@ -193,6 +195,9 @@ type Conn struct {
 
 	muActivity   sync.RWMutex
 	lastActivity time.Time // the last time the session saw either read or write activity
+	bytesIn      int64     // the number of bytes read from the client
+	bytesOut     int64     // the number of bytes written to the client
+	backend      string    // the address of the backend the session is forwarded to, if any
 
 	ticker   *time.Ticker // for timeouts
 	doneOnce sync.Once
@@ -248,6 +253,7 @@ func (c *Conn) Read(p []byte) (int, error) {
 		n := <-c.sizeCh
 		c.muActivity.Lock()
 		c.lastActivity = time.Now()
+		c.bytesIn += int64(n)
 		c.muActivity.Unlock()
 		return n, nil
 	case <-c.doneCh:
@@ -265,7 +271,31 @@ func (c *Conn) Write(p []byte) (n int, err error) {
 	c.muActivity.Lock()
 	c.lastActivity = time.Now()
 	c.muActivity.Unlock()
-	return l.pConn.WriteTo(p, c.rAddr)
+
+	n, err = l.pConn.WriteTo(p, c.rAddr)
+
+	c.muActivity.Lock()
+	c.bytesOut += int64(n)
+	c.muActivity.Unlock()
+	return n, err
+}
+
+func (c *Conn) setBackend(address string) {
+	c.muActivity.Lock()
+	c.backend = address
+	c.muActivity.Unlock()
+}
+
+// Stats returns the statistics of the session.
+func (c *Conn) Stats() connections.Stats {
+	c.muActivity.RLock()
+	defer c.muActivity.RUnlock()
+
+	return connections.Stats{
+		Backend:  c.backend,
+		BytesIn:  c.bytesIn,
+		BytesOut: c.bytesOut,
+	}
 }
 
 func (c *Conn) close() {
@@ -0,0 +1,198 @@
+package udp
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+// hashReplicas is the number of points of each server on the ring,
+// which spreads the clients evenly between the servers.
+const hashReplicas = 160
+
+type hashServer struct {
+	Handler
+	name string
+	down bool
+}
+
+type hashPoint struct {
+	hash   uint64
+	server *hashServer
+}
+
+// HashLoadBalancer is a load balancer for UDP services forwarding the sessions
+// to the server owning the hash of the client address on a ring,
+// so that the datagrams of a client keep landing on the same server across sessions, even when the other servers change.
+type HashLoadBalancer struct {
+	// ipv4Mask and ipv6Mask are applied to the client IP before it is hashed,
+	// so that the clients of a subnet share their server.
+	ipv4Mask net.IPMask
+	ipv6Mask net.IPMask
+	// withPort makes the client port part of the hash, so that each client socket has its own server.
+	withPort bool
+
+	lock    sync.RWMutex
+	servers []*hashServer
+	ring    []hashPoint
+}
+
+// NewHashLoadBalancer creates a new HashLoadBalancer,
+// hashing the client IPs masked with the given prefix lengths (0 meaning the whole address),
+// along with the client ports if withPort is set.
+func NewHashLoadBalancer(ipv4Prefix, ipv6Prefix int, withPort bool) (*HashLoadBalancer, error) {
+	if ipv4Prefix < 0 || ipv4Prefix > 32 {
+		return nil, fmt.Errorf("invalid IPv4 prefix length %d", ipv4Prefix)
+	}
+	if ipv6Prefix < 0 || ipv6Prefix > 128 {
+		return nil, fmt.Errorf("invalid IPv6 prefix length %d", ipv6Prefix)
+	}
+
+	if ipv4Prefix == 0 {
+		ipv4Prefix = 32
+	}
+	if ipv6Prefix == 0 {
+		ipv6Prefix = 128
+	}
+
+	return &HashLoadBalancer{
+		ipv4Mask: net.CIDRMask(ipv4Prefix, 32),
+		ipv6Mask: net.CIDRMask(ipv6Prefix, 128),
+		withPort: withPort,
+	}, nil
+}
+
+// ServeUDP forwards the session to the server owning the hash of the client address.
+func (b *HashLoadBalancer) ServeUDP(conn *Conn) {
+	srv, err := b.next(b.key(conn.rAddr))
+	if err != nil {
+		log.WithoutContext().Errorf("Error during load balancing: %v", err)
+		conn.Close()
+		return
+	}
+
+	srv.ServeUDP(conn)
+}
+
+// AddServer appends a server to the existing list.
+func (b *HashLoadBalancer) AddServer(serverHandler Handler) {
+	b.AddNamedServer("", serverHandler, nil)
+}
+
+// AddNamedServer appends a server to the existing list,
+// under a name which allows to change its status later on.
+// The weight is ignored, as all the servers own the same share of the ring.
+func (b *HashLoadBalancer) AddNamedServer(name string, serverHandler Handler, _ *int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.servers = append(b.servers, &hashServer{Handler: serverHandler, name: name})
+	b.buildRing()
+}
+
+// SetStatus marks the named server as up or down.
+// A server down leaves the ring, so that only its clients move to the other servers,
+// while its ongoing sessions are left untouched.
+func (b *HashLoadBalancer) SetStatus(name string, up bool) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	found := false
+	for _, srv := range b.servers {
+		if srv.name == name {
+			srv.down = !up
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	b.buildRing()
+
+	return nil
+}
+
+// key returns the masked client IP of the address, followed by its port if withPort is set.
+func (b *HashLoadBalancer) key(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+
+	key := host
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			key = ip4.Mask(b.ipv4Mask).String()
+		} else {
+			key = ip.Mask(b.ipv6Mask).String()
+		}
+	}
+
+	if b.withPort {
+		return net.JoinHostPort(key, port)
+	}
+	return key
+}
+
+// next returns the server owning the first point of the ring following the hash of the key.
+func (b *HashLoadBalancer) next(key string) (*hashServer, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	if len(b.servers) == 0 {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	if len(b.ring) == 0 {
+		return nil, errors.New("all servers are down")
+	}
+
+	h := hashKey(key)
+	i := sort.Search(len(b.ring), func(i int) bool { return b.ring[i].hash >= h })
+
+	return b.ring[i%len(b.ring)].server, nil
+}
+
+// buildRing places the points of the servers up on the ring, b.lock being held.
+// The points of a server only depend on its name and rank, so the other servers keep theirs.
+func (b *HashLoadBalancer) buildRing() {
+	ring := make([]hashPoint, 0, len(b.servers)*hashReplicas)
+	for rank, srv := range b.servers {
+		if srv.down {
+			continue
+		}
+
+		id := srv.name
+		if id == "" {
+			id = "#" + strconv.Itoa(rank)
+		}
+
+		for i := 0; i < hashReplicas; i++ {
+			ring = append(ring, hashPoint{hash: hashKey(id + "#" + strconv.Itoa(i)), server: srv})
+		}
+	}
+
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+
+	b.ring = ring
+}
+
+// hashKey returns the position of the key on the ring.
+// A cryptographic hash is used, as close keys (e.g. consecutive IPs) must land on distant positions.
+func hashKey(key string) uint64 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
@@ -0,0 +1,140 @@
+package udp
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashLoadBalancer_key(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		ipv4Prefix int
+		ipv6Prefix int
+		withPort   bool
+		addr       net.Addr
+		expected   string
+	}{
+		{
+			desc:     "whole IPv4 address",
+			addr:     &net.UDPAddr{IP: net.ParseIP("10.0.1.42"), Port: 1234},
+			expected: "10.0.1.42",
+		},
+		{
+			desc:       "masked IPv4 address",
+			ipv4Prefix: 24,
+			addr:       &net.UDPAddr{IP: net.ParseIP("10.0.1.42"), Port: 1234},
+			expected:   "10.0.1.0",
+		},
+		{
+			desc:     "IPv4 address with port",
+			withPort: true,
+			addr:     &net.UDPAddr{IP: net.ParseIP("10.0.1.42"), Port: 1234},
+			expected: "10.0.1.42:1234",
+		},
+		{
+			desc:       "masked IPv6 address",
+			ipv6Prefix: 64,
+			addr:       &net.UDPAddr{IP: net.ParseIP("2001:db8:1:2:3:4:5:6"), Port: 1234},
+			expected:   "2001:db8:1:2::",
+		},
+		{
+			desc:     "IPv6 address with port",
+			withPort: true,
+			addr:     &net.UDPAddr{IP: net.ParseIP("2001:db8:1:2:3:4:5:6"), Port: 1234},
+			expected: "[2001:db8:1:2:3:4:5:6]:1234",
+		},
+		{
+			desc:     "no address",
+			expected: "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer, err := NewHashLoadBalancer(test.ipv4Prefix, test.ipv6Prefix, test.withPort)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, balancer.key(test.addr))
+		})
+	}
+}
+
+func TestNewHashLoadBalancer_invalidPrefix(t *testing.T) {
+	_, err := NewHashLoadBalancer(33, 0, false)
+	assert.Error(t, err)
+
+	_, err = NewHashLoadBalancer(0, -1, false)
+	assert.Error(t, err)
+}
+
+func TestHashLoadBalancer_next(t *testing.T) {
+	balancer, err := NewHashLoadBalancer(0, 0, false)
+	require.NoError(t, err)
+
+	for _, server := range []string{"h1", "h2", "h3"} {
+		balancer.AddNamedServer(server, HandlerFunc(func(*Conn) {}), nil)
+	}
+
+	owners := make(map[string]string)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("10.0.0.%d", i)
+
+		srv, err := balancer.next(key)
+		require.NoError(t, err)
+
+		owners[key] = srv.name
+	}
+
+	shares := make(map[string]int)
+	for _, owner := range owners {
+		shares[owner]++
+	}
+	assert.Len(t, shares, 3)
+
+	// The clients of the other servers keep their server, when one goes down.
+	require.NoError(t, balancer.SetStatus("h2", false))
+
+	for key, owner := range owners {
+		srv, err := balancer.next(key)
+		require.NoError(t, err)
+
+		if owner != "h2" {
+			assert.Equal(t, owner, srv.name)
+		} else {
+			assert.NotEqual(t, "h2", srv.name)
+		}
+	}
+
+	// And they all get their server back, when it comes back up.
+	require.NoError(t, balancer.SetStatus("h2", true))
+
+	for key, owner := range owners {
+		srv, err := balancer.next(key)
+		require.NoError(t, err)
+
+		assert.Equal(t, owner, srv.name)
+	}
+
+	assert.Error(t, balancer.SetStatus("h4", false))
+}
+
+func TestHashLoadBalancer_noServer(t *testing.T) {
+	balancer, err := NewHashLoadBalancer(0, 0, false)
+	require.NoError(t, err)
+
+	_, err = balancer.next("10.0.0.1")
+	assert.Error(t, err)
+
+	balancer.AddNamedServer("h1", HandlerFunc(func(*Conn) {}), nil)
+	require.NoError(t, balancer.SetStatus("h1", false))
+
+	_, err = balancer.next("10.0.0.1")
+	assert.Error(t, err)
+}
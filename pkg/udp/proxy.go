@@ -1,21 +1,66 @@
 package udp
 
 import (
+	"fmt"
 	"io"
 	"net"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/log"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
 )
 
+// DialOptions holds the options of the sockets opened to the server.
+type DialOptions struct {
+	// Timeout is the maximum duration to set up a socket, which is the resolution of the server address.
+	Timeout time.Duration
+	// SourceAddress is the local IP the datagrams are sent from.
+	SourceAddress net.IP
+	// ReadBufferSize and WriteBufferSize are the sizes of the socket buffers, 0 meaning the system default.
+	ReadBufferSize  int
+	WriteBufferSize int
+	// DSCP is the Differentiated Services Code Point the datagrams are marked with, 0 meaning no marking.
+	DSCP int
+}
+
+// Validate checks the dial options.
+func (o DialOptions) Validate() error {
+	if o.Timeout < 0 {
+		return fmt.Errorf("invalid dial timeout %s", o.Timeout)
+	}
+
+	if o.DSCP < 0 || o.DSCP > 63 {
+		return fmt.Errorf("invalid DSCP %d: it must be between 0 and 63", o.DSCP)
+	}
+
+	if o.ReadBufferSize < 0 || o.WriteBufferSize < 0 {
+		return fmt.Errorf("invalid buffer sizes (read: %d, write: %d)", o.ReadBufferSize, o.WriteBufferSize)
+	}
+
+	return nil
+}
+
 // Proxy is a reverse-proxy implementation of the Handler interface.
 type Proxy struct {
 	// TODO: maybe optimize by pre-resolving it at proxy creation time
 	target string
+	dialer *net.Dialer
+	opts   DialOptions
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string) (*Proxy, error) {
-	return &Proxy{target: address}, nil
+func NewProxy(address string, opts DialOptions) (*Proxy, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if opts.SourceAddress != nil {
+		dialer.LocalAddr = &net.UDPAddr{IP: opts.SourceAddress}
+	}
+
+	return &Proxy{target: address, dialer: dialer, opts: opts}, nil
 }
 
 // ServeUDP implements the Handler interface.
@@ -25,7 +70,7 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
-	connBackend, err := net.Dial("udp", p.target)
+	connBackend, err := p.dial()
 	if err != nil {
 		log.Errorf("Error while connecting to backend: %v", err)
 		return
@@ -34,6 +79,8 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 
+	conn.setBackend(p.target)
+
 	errChan := make(chan error)
 	go p.connCopy(conn, connBackend, errChan)
 	go p.connCopy(connBackend, conn, errChan)
@@ -46,6 +93,50 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	<-errChan
 }
 
+// dial opens a socket to the server, with the dial options.
+func (p *Proxy) dial() (net.Conn, error) {
+	conn, err := p.dialer.Dial("udp", p.target)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.setOptions(conn.(*net.UDPConn)); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (p *Proxy) setOptions(conn *net.UDPConn) error {
+	if p.opts.ReadBufferSize > 0 {
+		if err := conn.SetReadBuffer(p.opts.ReadBufferSize); err != nil {
+			return fmt.Errorf("error while setting the read buffer size: %w", err)
+		}
+	}
+
+	if p.opts.WriteBufferSize > 0 {
+		if err := conn.SetWriteBuffer(p.opts.WriteBufferSize); err != nil {
+			return fmt.Errorf("error while setting the write buffer size: %w", err)
+		}
+	}
+
+	if p.opts.DSCP > 0 {
+		// The DSCP is the 6 most significant bits of the IPv4 TOS and of the IPv6 traffic class.
+		var err error
+		if addr, ok := conn.RemoteAddr().(*net.UDPAddr); ok && addr.IP.To4() == nil {
+			err = ipv6.NewConn(conn).SetTrafficClass(p.opts.DSCP << 2)
+		} else {
+			err = ipv4.NewConn(conn).SetTOS(p.opts.DSCP << 2)
+		}
+		if err != nil {
+			return fmt.Errorf("error while setting the DSCP: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (p Proxy) connCopy(dst io.WriteCloser, src io.Reader, errCh chan error) {
 	_, err := io.Copy(dst, src)
 	errCh <- err
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv4"
 )
 
 func TestUDPProxy(t *testing.T) {
@@ -21,7 +22,7 @@ func TestUDPProxy(t *testing.T) {
 		}
 	}))
 
-	proxy, err := NewProxy(backendAddr)
+	proxy, err := NewProxy(backendAddr, DialOptions{})
 	require.NoError(t, err)
 
 	proxyAddr := ":8080"
@@ -40,6 +41,61 @@ func TestUDPProxy(t *testing.T) {
 	assert.Equal(t, "DATAWRITE", string(b[:n]))
 }
 
+func TestNewProxy_invalidOptions(t *testing.T) {
+	testCases := []struct {
+		desc string
+		opts DialOptions
+	}{
+		{
+			desc: "DSCP out of range",
+			opts: DialOptions{DSCP: 64},
+		},
+		{
+			desc: "negative DSCP",
+			opts: DialOptions{DSCP: -1},
+		},
+		{
+			desc: "negative buffer size",
+			opts: DialOptions{ReadBufferSize: -1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewProxy("127.0.0.1:8081", test.opts)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProxy_dial(t *testing.T) {
+	backend, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = backend.Close() }()
+
+	proxy, err := NewProxy(backend.LocalAddr().String(), DialOptions{
+		Timeout:         time.Second,
+		SourceAddress:   net.ParseIP("127.0.0.1"),
+		ReadBufferSize:  65536,
+		WriteBufferSize: 65536,
+		DSCP:            46,
+	})
+	require.NoError(t, err)
+
+	conn, err := proxy.dial()
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	assert.Equal(t, "127.0.0.1", conn.LocalAddr().(*net.UDPAddr).IP.String())
+
+	tos, err := ipv4.NewConn(conn).TOS()
+	require.NoError(t, err)
+	assert.Equal(t, 46<<2, tos)
+}
+
 func newServer(t *testing.T, addr string, handler Handler) {
 	addrL, err := net.ResolveUDPAddr("udp", addr)
 	require.NoError(t, err)
@@ -0,0 +1,15 @@
+package udp
+
+import (
+	"github.com/containous/traefik/v2/pkg/connections"
+)
+
+// NewTrackingHandler returns a handler registering the sessions of the given router in the connection table.
+func NewTrackingHandler(table *connections.Table, routerName string, next Handler) Handler {
+	return HandlerFunc(func(conn *Conn) {
+		id := table.Add("udp", routerName, conn.rAddr.String(), conn)
+		defer table.Remove(id)
+
+		next.ServeUDP(conn)
+	})
+}
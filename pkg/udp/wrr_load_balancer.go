@@ -9,7 +9,10 @@ import (
 
 type server struct {
 	Handler
+	name   string
 	weight int
+	// down is set when the server is reported unhealthy, which takes it out of the balancing.
+	down bool
 }
 
 // WRRLoadBalancer is a naive RoundRobin load balancer for UDP services.
@@ -38,6 +41,7 @@ func (b *WRRLoadBalancer) ServeUDP(conn *Conn) {
 	if err != nil {
 		log.WithoutContext().Errorf("Error during load balancing: %v", err)
 		conn.Close()
+		return
 	}
 	next.ServeUDP(conn)
 }
@@ -50,17 +54,57 @@ func (b *WRRLoadBalancer) AddServer(serverHandler Handler) {
 
 // AddWeightedServer appends a handler to the existing list with a weight.
 func (b *WRRLoadBalancer) AddWeightedServer(serverHandler Handler, weight *int) {
+	b.AddNamedServer("", serverHandler, weight)
+}
+
+// AddNamedServer appends a handler to the existing list with a weight,
+// under a name which allows to change its status later on.
+func (b *WRRLoadBalancer) AddNamedServer(name string, serverHandler Handler, weight *int) {
 	w := 1
 	if weight != nil {
 		w = *weight
 	}
-	b.servers = append(b.servers, server{Handler: serverHandler, weight: w})
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.servers = append(b.servers, server{Handler: serverHandler, name: name, weight: w})
+}
+
+// SetStatus marks the named server as up or down.
+// A server down no longer receives new sessions, while its ongoing ones are left untouched.
+func (b *WRRLoadBalancer) SetStatus(name string, up bool) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	found := false
+	for i := range b.servers {
+		srv := &b.servers[i]
+		if srv.name != name {
+			continue
+		}
+
+		found = true
+		if srv.down == up {
+			srv.down = !up
+
+			// The round restarts, as the enabled servers changed.
+			b.index = -1
+			b.currentWeight = 0
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	return nil
 }
 
 func (b *WRRLoadBalancer) maxWeight() int {
 	max := -1
 	for _, s := range b.servers {
-		if s.weight > max {
+		if !s.down && s.weight > max {
 			max = s.weight
 		}
 	}
@@ -70,6 +114,10 @@ func (b *WRRLoadBalancer) maxWeight() int {
 func (b *WRRLoadBalancer) weightGcd() int {
 	divisor := -1
 	for _, s := range b.servers {
+		if s.down {
+			continue
+		}
+
 		if divisor == -1 {
 			divisor = s.weight
 		} else {
@@ -79,6 +127,15 @@ func (b *WRRLoadBalancer) weightGcd() int {
 	return divisor
 }
 
+func (b *WRRLoadBalancer) hasServerUp() bool {
+	for _, s := range b.servers {
+		if !s.down {
+			return true
+		}
+	}
+	return false
+}
+
 func gcd(a, b int) int {
 	for b != 0 {
 		a, b = b, a%b
@@ -94,6 +151,10 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 		return nil, fmt.Errorf("no servers in the pool")
 	}
 
+	if !b.hasServerUp() {
+		return nil, fmt.Errorf("all servers are down")
+	}
+
 	// The algorithm below may look messy,
 	// but is actually very simple it calculates the GCD  and subtracts it on every iteration,
 	// what interleaves servers and allows us not to build an iterator every time we readjust weights.
@@ -115,7 +176,7 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 			}
 		}
 		srv := b.servers[b.index]
-		if srv.weight >= b.currentWeight {
+		if !srv.down && srv.weight >= b.currentWeight {
 			return srv, nil
 		}
 	}
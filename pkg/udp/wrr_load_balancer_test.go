@@ -0,0 +1,62 @@
+package udp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWRRLoadBalancer_weights(t *testing.T) {
+	balancer := NewWRRLoadBalancer()
+
+	calls := make(map[string]int)
+	for name, weight := range map[string]int{"h1": 3, "h2": 1} {
+		name := name
+		weight := weight
+		balancer.AddNamedServer(name, HandlerFunc(func(*Conn) {
+			calls[name]++
+		}), &weight)
+	}
+
+	for i := 0; i < 8; i++ {
+		balancer.ServeUDP(nil)
+	}
+
+	assert.Equal(t, map[string]int{"h1": 6, "h2": 2}, calls)
+}
+
+func TestWRRLoadBalancer_SetStatus(t *testing.T) {
+	balancer := NewWRRLoadBalancer()
+
+	calls := make(map[string]int)
+	for _, name := range []string{"h1", "h2"} {
+		name := name
+		balancer.AddNamedServer(name, HandlerFunc(func(*Conn) {
+			calls[name]++
+		}), nil)
+	}
+
+	require.NoError(t, balancer.SetStatus("h1", false))
+
+	for i := 0; i < 4; i++ {
+		balancer.ServeUDP(nil)
+	}
+	assert.Equal(t, map[string]int{"h2": 4}, calls)
+
+	require.NoError(t, balancer.SetStatus("h2", false))
+
+	_, err := balancer.next()
+	assert.Error(t, err)
+
+	require.NoError(t, balancer.SetStatus("h1", true))
+	require.NoError(t, balancer.SetStatus("h2", true))
+
+	calls = make(map[string]int)
+	for i := 0; i < 4; i++ {
+		balancer.ServeUDP(nil)
+	}
+	assert.Equal(t, map[string]int{"h1": 2, "h2": 2}, calls)
+
+	assert.Error(t, balancer.SetStatus("h3", false))
+}